@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE calculation_jobs (
+    job_id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    plan_id UUID NOT NULL REFERENCES load_plans(plan_id) ON DELETE CASCADE,
+
+    -- queued, running, completed, failed, cancelled
+    status VARCHAR(20) NOT NULL DEFAULT 'queued',
+    options JSONB NOT NULL DEFAULT '{}'::jsonb,
+
+    result_id UUID REFERENCES plan_results(result_id) ON DELETE SET NULL,
+    error_message TEXT,
+
+    -- Requester identity, used to rebuild the auth scope inside the worker
+    requested_by_id UUID NOT NULL,
+    requested_by_role VARCHAR(50) NOT NULL,
+    workspace_id UUID REFERENCES workspaces(workspace_id) ON DELETE CASCADE,
+
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    started_at TIMESTAMPTZ,
+    finished_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_calculation_jobs_plan ON calculation_jobs(plan_id);
+CREATE INDEX idx_calculation_jobs_status ON calculation_jobs(status);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS calculation_jobs;
+-- +goose StatementEnd
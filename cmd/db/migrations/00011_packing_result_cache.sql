@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Packing results keyed by a canonical hash of container, items, options and backend.
+CREATE TABLE packing_result_cache (
+    cache_key VARCHAR(64) PRIMARY KEY,
+    backend VARCHAR(50) NOT NULL,
+    result JSONB NOT NULL,
+    hit_count INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_hit_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_packing_result_cache_last_hit ON packing_result_cache(last_hit_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS packing_result_cache;
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Scenarios are what-if copies of a plan. They live in load_plans so items,
+-- results and calculation work unchanged, but are hidden from plan lists and
+-- dashboard counts.
+ALTER TABLE load_plans
+    ADD COLUMN parent_plan_id UUID REFERENCES load_plans(plan_id) ON DELETE CASCADE,
+    ADD COLUMN scenario_name VARCHAR(100);
+
+CREATE INDEX IF NOT EXISTS idx_load_plans_parent ON load_plans (parent_plan_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_load_plans_parent;
+
+DELETE FROM load_plans WHERE parent_plan_id IS NOT NULL;
+
+ALTER TABLE load_plans
+    DROP COLUMN IF EXISTS scenario_name,
+    DROP COLUMN IF EXISTS parent_plan_id;
+-- +goose StatementEnd
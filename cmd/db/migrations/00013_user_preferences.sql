@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Per-user display preferences. Units are a selection accepted by the API's
+-- ?units= parameter, e.g. "metric", "imperial" or "cm,kg".
+CREATE TABLE user_preferences (
+    user_id UUID PRIMARY KEY REFERENCES users(user_id) ON DELETE CASCADE,
+    units VARCHAR(16) NOT NULL DEFAULT 'metric',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS user_preferences;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Packing tolerances, all in millimetres. NULL means none.
+--   load_items.padding_mm        added to every side of each unit
+--   load_plans.wall_clearance_mm kept free along every container wall
+--   load_plans.item_gap_mm       minimum gap between neighbouring units
+ALTER TABLE load_items
+    ADD COLUMN padding_mm NUMERIC(10,2) CHECK (padding_mm >= 0);
+
+ALTER TABLE load_plans
+    ADD COLUMN wall_clearance_mm NUMERIC(10,2) CHECK (wall_clearance_mm >= 0),
+    ADD COLUMN item_gap_mm NUMERIC(10,2) CHECK (item_gap_mm >= 0);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE load_plans
+    DROP COLUMN IF EXISTS item_gap_mm,
+    DROP COLUMN IF EXISTS wall_clearance_mm;
+
+ALTER TABLE load_items
+    DROP COLUMN IF EXISTS padding_mm;
+-- +goose StatementEnd
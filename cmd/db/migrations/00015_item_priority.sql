@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Which units to keep when a load does not fit. Higher priority packs first;
+-- must-ship units pack before everything else.
+ALTER TABLE load_items
+    ADD COLUMN priority INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN must_ship BOOLEAN NOT NULL DEFAULT FALSE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE load_items
+    DROP COLUMN IF EXISTS must_ship,
+    DROP COLUMN IF EXISTS priority;
+-- +goose StatementEnd
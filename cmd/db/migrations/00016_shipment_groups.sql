@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Overflow plans carry the units a plan could not fit. Every plan of one
+-- shipment shares shipment_group_id, which is the plan_id of the first plan.
+ALTER TABLE load_plans
+    ADD COLUMN overflow_of_plan_id UUID REFERENCES load_plans(plan_id) ON DELETE SET NULL,
+    ADD COLUMN shipment_group_id UUID;
+
+CREATE INDEX IF NOT EXISTS idx_load_plans_shipment_group ON load_plans (shipment_group_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_load_plans_shipment_group;
+
+ALTER TABLE load_plans
+    DROP COLUMN IF EXISTS shipment_group_id,
+    DROP COLUMN IF EXISTS overflow_of_plan_id;
+-- +goose StatementEnd
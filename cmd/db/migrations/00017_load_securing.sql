@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Inputs of the EN 12195-1 securing estimate.
+--   friction_coefficient        against the container floor; NULL means unknown
+--   lashing_points_per_side     evenly spaced along each side wall; 0 means unknown
+--   lashing_point_capacity_dan  rated capacity of one point; NULL means not limiting
+ALTER TABLE products
+    ADD COLUMN friction_coefficient NUMERIC(4,2) CHECK (friction_coefficient >= 0);
+
+ALTER TABLE load_items
+    ADD COLUMN friction_coefficient NUMERIC(4,2) CHECK (friction_coefficient >= 0);
+
+ALTER TABLE containers
+    ADD COLUMN lashing_points_per_side INT NOT NULL DEFAULT 0 CHECK (lashing_points_per_side >= 0),
+    ADD COLUMN lashing_point_capacity_dan NUMERIC(8,2) CHECK (lashing_point_capacity_dan > 0);
+
+ALTER TABLE load_plans
+    ADD COLUMN lashing_points_per_side INT NOT NULL DEFAULT 0 CHECK (lashing_points_per_side >= 0),
+    ADD COLUMN lashing_point_capacity_dan NUMERIC(8,2) CHECK (lashing_point_capacity_dan > 0);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE load_plans
+    DROP COLUMN IF EXISTS lashing_point_capacity_dan,
+    DROP COLUMN IF EXISTS lashing_points_per_side;
+
+ALTER TABLE containers
+    DROP COLUMN IF EXISTS lashing_point_capacity_dan,
+    DROP COLUMN IF EXISTS lashing_points_per_side;
+
+ALTER TABLE load_items
+    DROP COLUMN IF EXISTS friction_coefficient;
+
+ALTER TABLE products
+    DROP COLUMN IF EXISTS friction_coefficient;
+-- +goose StatementEnd
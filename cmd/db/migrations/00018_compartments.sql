@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Compartments split a container along its length with bulkheads. Each is
+-- a JSON object {name, temperature_class, start_mm, length_mm, max_weight_kg};
+-- NULL means one undivided compartment. Plans keep their own copy.
+ALTER TABLE containers
+    ADD COLUMN compartments JSONB;
+
+ALTER TABLE load_plans
+    ADD COLUMN compartments JSONB;
+
+-- The compartment class an item must travel in. NULL means ambient.
+ALTER TABLE products
+    ADD COLUMN temperature_class TEXT CHECK (temperature_class IN ('ambient', 'chilled', 'frozen'));
+
+ALTER TABLE load_items
+    ADD COLUMN temperature_class TEXT CHECK (temperature_class IN ('ambient', 'chilled', 'frozen'));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE load_items
+    DROP COLUMN IF EXISTS temperature_class;
+
+ALTER TABLE products
+    DROP COLUMN IF EXISTS temperature_class;
+
+ALTER TABLE load_plans
+    DROP COLUMN IF EXISTS compartments;
+
+ALTER TABLE containers
+    DROP COLUMN IF EXISTS compartments;
+-- +goose StatementEnd
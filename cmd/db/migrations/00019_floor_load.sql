@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Floor load limits. NULL means not checked.
+--   floor_load_kg_m2  highest pressure the floor takes under a unit or stack
+--   line_load_kg_m    highest load per running metre of container length
+ALTER TABLE containers
+    ADD COLUMN floor_load_kg_m2 NUMERIC(10,2) CHECK (floor_load_kg_m2 > 0),
+    ADD COLUMN line_load_kg_m NUMERIC(10,2) CHECK (line_load_kg_m > 0);
+
+ALTER TABLE load_plans
+    ADD COLUMN floor_load_kg_m2 NUMERIC(10,2) CHECK (floor_load_kg_m2 > 0),
+    ADD COLUMN line_load_kg_m NUMERIC(10,2) CHECK (line_load_kg_m > 0);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE load_plans
+    DROP COLUMN IF EXISTS line_load_kg_m,
+    DROP COLUMN IF EXISTS floor_load_kg_m2;
+
+ALTER TABLE containers
+    DROP COLUMN IF EXISTS line_load_kg_m,
+    DROP COLUMN IF EXISTS floor_load_kg_m2;
+-- +goose StatementEnd
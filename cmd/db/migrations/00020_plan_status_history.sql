@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Every change of load_plans.status, manual or made by a calculation.
+CREATE TABLE plan_status_history (
+    history_id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    plan_id UUID NOT NULL REFERENCES load_plans(plan_id) ON DELETE CASCADE,
+
+    from_status VARCHAR(20),
+    to_status VARCHAR(20) NOT NULL,
+    reason TEXT,
+
+    -- Who moved the plan: user, guest or system
+    changed_by_type TEXT NOT NULL DEFAULT 'user',
+    changed_by_id UUID,
+
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_plan_status_history_plan ON plan_status_history(plan_id, changed_at);
+
+-- Calculations used to mark every feasible plan COMPLETED. COMPLETED now
+-- means loaded and is final, so those plans become PLANNED. Loading sessions
+-- come later, so no plan has finished loading yet.
+UPDATE load_plans SET status = 'PLANNED' WHERE status = 'COMPLETED';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+UPDATE load_plans SET status = 'COMPLETED' WHERE status = 'PLANNED';
+
+DROP TABLE IF EXISTS plan_status_history;
+-- +goose StatementEnd
@@ -0,0 +1,40 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Every calculation is kept as a numbered version of the plan's result; the
+-- active one is what the plan shows and loads.
+--   algorithm  packing run that produced the version
+--   inputs     snapshot of the container, items, options and backend used
+ALTER TABLE plan_results
+    ADD COLUMN version INT NOT NULL DEFAULT 1,
+    ADD COLUMN is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    ADD COLUMN algorithm TEXT,
+    ADD COLUMN inputs JSONB;
+
+CREATE UNIQUE INDEX idx_plan_results_version ON plan_results(plan_id, version);
+CREATE UNIQUE INDEX idx_plan_results_active ON plan_results(plan_id) WHERE is_active;
+
+-- Placements keep the ID of an item after it is deleted so older versions
+-- still say which item stood where.
+ALTER TABLE plan_placements
+    DROP CONSTRAINT IF EXISTS plan_placements_item_id_fkey;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM plan_results WHERE NOT is_active;
+DELETE FROM plan_placements pp
+WHERE pp.item_id IS NOT NULL
+  AND NOT EXISTS (SELECT 1 FROM load_items li WHERE li.item_id = pp.item_id);
+
+ALTER TABLE plan_placements
+    ADD CONSTRAINT plan_placements_item_id_fkey FOREIGN KEY (item_id) REFERENCES load_items(item_id);
+
+DROP INDEX IF EXISTS idx_plan_results_active;
+DROP INDEX IF EXISTS idx_plan_results_version;
+
+ALTER TABLE plan_results
+    DROP COLUMN IF EXISTS inputs,
+    DROP COLUMN IF EXISTS algorithm,
+    DROP COLUMN IF EXISTS is_active,
+    DROP COLUMN IF EXISTS version;
+-- +goose StatementEnd
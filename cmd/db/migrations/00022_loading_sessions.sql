@@ -0,0 +1,62 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A loading session follows the loading of a plan's active result, one
+-- scanned unit at a time. A plan has at most one open (active or paused)
+-- session.
+CREATE TABLE loading_sessions (
+    session_id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    plan_id UUID NOT NULL REFERENCES load_plans(plan_id) ON DELETE CASCADE,
+    result_id UUID REFERENCES plan_results(result_id) ON DELETE SET NULL,
+
+    -- active, paused, completed, abandoned
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    total_steps INT NOT NULL,
+
+    started_by_id UUID NOT NULL,
+    started_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    paused_at TIMESTAMPTZ,
+    completed_by_id UUID,
+    completed_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_loading_sessions_plan ON loading_sessions(plan_id);
+CREATE UNIQUE INDEX idx_loading_sessions_open ON loading_sessions(plan_id) WHERE status IN ('active', 'paused');
+
+-- Every scan made during a session, accepted or not.
+CREATE TABLE loading_scans (
+    scan_id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    session_id UUID NOT NULL REFERENCES loading_sessions(session_id) ON DELETE CASCADE,
+
+    barcode TEXT NOT NULL,
+    -- MATCHED, OUT_OF_SEQUENCE, DUPLICATE, UNKNOWN_STEP, WRONG_PLAN, INVALID_FORMAT
+    status VARCHAR(20) NOT NULL,
+    step_number INT,
+    expected_step INT,
+
+    operator_id UUID NOT NULL,
+    scanned_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_loading_scans_session ON loading_scans(session_id, scanned_at);
+-- A step is loaded once per session.
+CREATE UNIQUE INDEX idx_loading_scans_matched ON loading_scans(session_id, step_number) WHERE status = 'MATCHED';
+
+INSERT INTO permissions (name, description) VALUES
+('plan:load', 'Run loading sessions and scan units')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_id, permission_id)
+SELECT r.role_id, p.permission_id
+FROM roles r
+JOIN permissions p ON p.name = 'plan:load'
+WHERE r.name = 'operator'
+ON CONFLICT DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM permissions WHERE name = 'plan:load';
+
+DROP TABLE IF EXISTS loading_scans;
+DROP TABLE IF EXISTS loading_sessions;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Scans recorded offline carry the ID the device gave them, so uploading the
+-- same events again does not record them twice.
+ALTER TABLE loading_scans ADD COLUMN client_event_id UUID;
+
+CREATE UNIQUE INDEX idx_loading_scans_client_event ON loading_scans(session_id, client_event_id) WHERE client_event_id IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_loading_scans_client_event;
+
+ALTER TABLE loading_scans DROP COLUMN IF EXISTS client_event_id;
+-- +goose StatementEnd
@@ -0,0 +1,50 @@
+-- +goose Up
+-- +goose StatementBegin
+-- GTINs are stored in their 14-digit form; shorter EAN/UPC codes are
+-- padded with leading zeros.
+ALTER TABLE products
+    ADD COLUMN gtin VARCHAR(14) CHECK (gtin ~ '^[0-9]{14}$');
+
+CREATE INDEX idx_products_gtin ON products(gtin) WHERE gtin IS NOT NULL;
+
+ALTER TABLE load_items
+    ADD COLUMN gtin VARCHAR(14) CHECK (gtin ~ '^[0-9]{14}$');
+
+-- GS1 numbering of a workspace. last_serial is the last SSCC serial
+-- reference handed out; serials are never reused, even after the prefix
+-- changes.
+CREATE TABLE workspace_gs1 (
+    workspace_id UUID PRIMARY KEY REFERENCES workspaces(workspace_id) ON DELETE CASCADE,
+    company_prefix VARCHAR(12) NOT NULL CHECK (company_prefix ~ '^[0-9]{6,12}$'),
+    extension_digit SMALLINT NOT NULL DEFAULT 0 CHECK (extension_digit BETWEEN 0 AND 9),
+    last_serial BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- A handling unit is one placed unit of a result, identified by an SSCC.
+CREATE TABLE handling_units (
+    sscc CHAR(18) PRIMARY KEY,
+    workspace_id UUID NOT NULL REFERENCES workspaces(workspace_id) ON DELETE CASCADE,
+    plan_id UUID NOT NULL REFERENCES load_plans(plan_id) ON DELETE CASCADE,
+    result_id UUID NOT NULL REFERENCES plan_results(result_id) ON DELETE CASCADE,
+    placement_id UUID NOT NULL UNIQUE REFERENCES plan_placements(placement_id) ON DELETE CASCADE,
+    step_number INT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_handling_units_result ON handling_units(result_id, step_number);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS handling_units;
+DROP TABLE IF EXISTS workspace_gs1;
+
+ALTER TABLE load_items
+    DROP COLUMN IF EXISTS gtin;
+
+DROP INDEX IF EXISTS idx_products_gtin;
+
+ALTER TABLE products
+    DROP COLUMN IF EXISTS gtin;
+-- +goose StatementEnd
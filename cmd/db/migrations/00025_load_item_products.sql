@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A plan item may reference the catalog product it was filled from. The SKU
+-- is copied so reports keep it after the product is renamed or deleted.
+-- product_overridden records that the item's values no longer match the
+-- product's.
+ALTER TABLE load_items
+    ADD COLUMN product_id UUID REFERENCES products(product_id) ON DELETE SET NULL,
+    ADD COLUMN product_sku VARCHAR(50),
+    ADD COLUMN product_overridden BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX idx_load_items_product ON load_items(product_id) WHERE product_id IS NOT NULL;
+CREATE INDEX idx_load_items_product_sku ON load_items(product_sku) WHERE product_sku IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_load_items_product_sku;
+DROP INDEX IF EXISTS idx_load_items_product;
+
+ALTER TABLE load_items
+    DROP COLUMN IF EXISTS product_overridden,
+    DROP COLUMN IF EXISTS product_sku,
+    DROP COLUMN IF EXISTS product_id;
+-- +goose StatementEnd
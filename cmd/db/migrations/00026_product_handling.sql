@@ -0,0 +1,40 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Handling attributes of a product, copied onto the plan items filled from
+-- it. max_stack_load_kg is the weight a unit may carry on top; hazmat_class
+-- is the UN dangerous goods class or division.
+ALTER TABLE products
+    ADD COLUMN fragile BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN this_side_up BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN stackable BOOLEAN NOT NULL DEFAULT TRUE,
+    ADD COLUMN max_stack_load_kg NUMERIC(10,2) CHECK (max_stack_load_kg >= 0),
+    ADD COLUMN hazmat_class VARCHAR(3),
+    ADD COLUMN packaging_type VARCHAR(20);
+
+ALTER TABLE load_items
+    ADD COLUMN fragile BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN this_side_up BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN stackable BOOLEAN NOT NULL DEFAULT TRUE,
+    ADD COLUMN max_stack_load_kg NUMERIC(10,2) CHECK (max_stack_load_kg >= 0),
+    ADD COLUMN hazmat_class VARCHAR(3),
+    ADD COLUMN packaging_type VARCHAR(20);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE load_items
+    DROP COLUMN IF EXISTS packaging_type,
+    DROP COLUMN IF EXISTS hazmat_class,
+    DROP COLUMN IF EXISTS max_stack_load_kg,
+    DROP COLUMN IF EXISTS stackable,
+    DROP COLUMN IF EXISTS this_side_up,
+    DROP COLUMN IF EXISTS fragile;
+
+ALTER TABLE products
+    DROP COLUMN IF EXISTS packaging_type,
+    DROP COLUMN IF EXISTS hazmat_class,
+    DROP COLUMN IF EXISTS max_stack_load_kg,
+    DROP COLUMN IF EXISTS stackable,
+    DROP COLUMN IF EXISTS this_side_up,
+    DROP COLUMN IF EXISTS fragile;
+-- +goose StatementEnd
@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+-- packaging holds the levels above the each (inner, case, pallet) with their
+-- dimensions and how many units of the next lower level they contain. The
+-- product's own dimensions are those of the each. shipping_unit is the level
+-- plan items are converted to before packing.
+ALTER TABLE products
+    ADD COLUMN packaging JSONB,
+    ADD COLUMN shipping_unit VARCHAR(10) NOT NULL DEFAULT 'each'
+        CHECK (shipping_unit IN ('each', 'inner', 'case', 'pallet'));
+
+-- unit is the packaging level of one unit of the item, eaches_per_unit how
+-- many eaches it holds and ordered_eaches the quantity the line was entered
+-- with, before rounding up to whole units.
+ALTER TABLE load_items
+    ADD COLUMN unit VARCHAR(10) CHECK (unit IN ('each', 'inner', 'case', 'pallet')),
+    ADD COLUMN eaches_per_unit INT CHECK (eaches_per_unit > 0),
+    ADD COLUMN ordered_eaches INT CHECK (ordered_eaches > 0);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE load_items
+    DROP COLUMN IF EXISTS ordered_eaches,
+    DROP COLUMN IF EXISTS eaches_per_unit,
+    DROP COLUMN IF EXISTS unit;
+
+ALTER TABLE products
+    DROP COLUMN IF EXISTS shipping_unit,
+    DROP COLUMN IF EXISTS packaging;
+-- +goose StatementEnd
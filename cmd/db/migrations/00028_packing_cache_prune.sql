@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Cached results are pruned by when they were last used.
+DROP INDEX IF EXISTS idx_packing_result_cache_last_hit;
+
+CREATE INDEX idx_packing_result_cache_last_used ON packing_result_cache((COALESCE(last_hit_at, created_at)));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_packing_result_cache_last_used;
+
+CREATE INDEX idx_packing_result_cache_last_hit ON packing_result_cache(last_hit_at);
+-- +goose StatementEnd
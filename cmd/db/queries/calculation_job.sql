@@ -0,0 +1,65 @@
+-- name: CreateCalculationJob :one
+INSERT INTO calculation_jobs (
+    plan_id,
+    options,
+    requested_by_id,
+    requested_by_role,
+    workspace_id
+) VALUES (
+    $1, $2, $3, $4, $5
+)
+RETURNING *;
+
+-- name: GetCalculationJob :one
+SELECT *
+FROM calculation_jobs
+WHERE job_id = $1
+  AND plan_id = $2;
+
+-- name: GetCalculationJobAny :one
+SELECT *
+FROM calculation_jobs
+WHERE job_id = $1;
+
+-- name: MarkCalculationJobRunning :execrows
+UPDATE calculation_jobs
+SET status = 'running',
+    started_at = NOW()
+WHERE job_id = $1
+  AND status = 'queued';
+
+-- name: CompleteCalculationJob :exec
+UPDATE calculation_jobs
+SET status = 'completed',
+    result_id = $2,
+    finished_at = NOW()
+WHERE job_id = $1
+  AND status = 'running';
+
+-- name: FailCalculationJob :exec
+UPDATE calculation_jobs
+SET status = 'failed',
+    error_message = $2,
+    finished_at = NOW()
+WHERE job_id = $1
+  AND status = 'running';
+
+-- name: CancelCalculationJob :execrows
+UPDATE calculation_jobs
+SET status = 'cancelled',
+    finished_at = NOW()
+WHERE job_id = $1
+  AND status IN ('queued', 'running');
+
+-- name: ListPendingCalculationJobs :many
+SELECT *
+FROM calculation_jobs
+WHERE status = 'queued'
+ORDER BY created_at ASC
+LIMIT $1;
+
+-- name: RequeueRunningCalculationJobs :execrows
+UPDATE calculation_jobs
+SET status = 'queued',
+    started_at = NULL
+WHERE status = 'running';
@@ -6,9 +6,14 @@ INSERT INTO containers (
     inner_width_mm,
     inner_height_mm,
     max_weight_kg,
-    description
+    description,
+    lashing_points_per_side,
+    lashing_point_capacity_dan,
+    compartments,
+    floor_load_kg_m2,
+    line_load_kg_m
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
 )
 RETURNING *;
 
@@ -45,7 +50,12 @@ SET
     inner_height_mm = $6,
     max_weight_kg = $7,
     description = $8,
-    updated_at = NOW()
+    updated_at = NOW(),
+    lashing_points_per_side = $9,
+    lashing_point_capacity_dan = $10,
+    compartments = $11,
+    floor_load_kg_m2 = $12,
+    line_load_kg_m = $13
 WHERE container_id = $1
   AND workspace_id = $2;
 
@@ -58,7 +68,12 @@ SET
     inner_height_mm = $5,
     max_weight_kg = $6,
     description = $7,
-    updated_at = NOW()
+    updated_at = NOW(),
+    lashing_points_per_side = $8,
+    lashing_point_capacity_dan = $9,
+    compartments = $10,
+    floor_load_kg_m2 = $11,
+    line_load_kg_m = $12
 WHERE container_id = $1;
 
 -- name: DeleteContainer :exec
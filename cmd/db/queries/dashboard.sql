@@ -4,7 +4,7 @@
 SELECT COUNT(*) FROM users;
 
 -- name: CountGlobalActivePlans :one
-SELECT COUNT(*) FROM load_plans WHERE status NOT IN ('COMPLETED', 'FAILED');
+SELECT COUNT(*) FROM load_plans WHERE status NOT IN ('COMPLETED', 'FAILED') AND parent_plan_id IS NULL;
 
 -- name: CountGlobalContainers :one
 SELECT COUNT(*) FROM containers;
@@ -12,22 +12,30 @@ SELECT COUNT(*) FROM containers;
 -- name: GetGlobalPlanStatusDistribution :many
 SELECT status, COUNT(*) as count 
 FROM load_plans 
+WHERE parent_plan_id IS NULL
 GROUP BY status;
 
 -- name: CountGlobalCompletedPlansToday :one
 SELECT COUNT(*) FROM load_plans 
 WHERE status = 'COMPLETED' 
-AND created_at >= CURRENT_DATE;
+AND created_at >= CURRENT_DATE
+AND parent_plan_id IS NULL;
 
 -- name: CountGlobalTotalItems :one
-SELECT COALESCE(SUM(quantity), 0)::BIGINT FROM load_items;
+SELECT COALESCE(SUM(li.quantity), 0)::BIGINT
+FROM load_items li
+JOIN load_plans lp ON li.plan_id = lp.plan_id
+WHERE lp.parent_plan_id IS NULL;
 
 -- name: GetGlobalAvgVolumeUtilization :one
-SELECT COALESCE(AVG(volume_utilization_pct), 0)::FLOAT
-FROM plan_results;
+SELECT COALESCE(AVG(pr.volume_utilization_pct), 0)::FLOAT
+FROM plan_results pr
+JOIN load_plans lp ON pr.plan_id = lp.plan_id
+WHERE pr.is_active
+AND lp.parent_plan_id IS NULL;
 
 -- name: CountGlobalCompletedPlans :one
-SELECT COUNT(*) FROM load_plans WHERE status = 'COMPLETED';
+SELECT COUNT(*) FROM load_plans WHERE status = 'COMPLETED' AND parent_plan_id IS NULL;
 
 
 -- WORKSPACE SCOPED QUERIES
@@ -41,7 +49,8 @@ WHERE workspace_id = $1;
 SELECT COUNT(*) 
 FROM load_plans 
 WHERE workspace_id = $1 
-AND status NOT IN ('COMPLETED', 'FAILED');
+AND status NOT IN ('COMPLETED', 'FAILED')
+AND parent_plan_id IS NULL;
 
 -- name: CountWorkspaceContainers :one
 SELECT COUNT(*) 
@@ -52,6 +61,7 @@ WHERE workspace_id = $1 OR workspace_id IS NULL;
 SELECT status, COUNT(*) as count 
 FROM load_plans 
 WHERE workspace_id = $1
+AND parent_plan_id IS NULL
 GROUP BY status;
 
 -- name: CountWorkspaceCompletedPlansToday :one
@@ -59,22 +69,27 @@ SELECT COUNT(*)
 FROM load_plans 
 WHERE workspace_id = $1 
 AND status = 'COMPLETED' 
-AND created_at >= CURRENT_DATE;
+AND created_at >= CURRENT_DATE
+AND parent_plan_id IS NULL;
 
 -- name: CountWorkspaceItems :one
 SELECT COALESCE(SUM(li.quantity), 0)::BIGINT 
 FROM load_items li
 JOIN load_plans lp ON li.plan_id = lp.plan_id
-WHERE lp.workspace_id = $1;
+WHERE lp.workspace_id = $1
+AND lp.parent_plan_id IS NULL;
 
 -- name: GetWorkspaceAvgVolumeUtilization :one
 SELECT COALESCE(AVG(pr.volume_utilization_pct), 0)::FLOAT
 FROM plan_results pr
 JOIN load_plans lp ON pr.plan_id = lp.plan_id
-WHERE lp.workspace_id = $1;
+WHERE pr.is_active
+AND lp.workspace_id = $1
+AND lp.parent_plan_id IS NULL;
 
 -- name: CountWorkspaceCompletedPlans :one
 SELECT COUNT(*) 
 FROM load_plans 
 WHERE workspace_id = $1 
-AND status = 'COMPLETED';
+AND status = 'COMPLETED'
+AND parent_plan_id IS NULL;
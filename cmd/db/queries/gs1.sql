@@ -0,0 +1,46 @@
+-- name: GetWorkspaceGS1 :one
+SELECT *
+FROM workspace_gs1
+WHERE workspace_id = $1;
+
+-- name: UpsertWorkspaceGS1 :one
+INSERT INTO workspace_gs1 (
+    workspace_id,
+    company_prefix,
+    extension_digit
+) VALUES (
+    $1, $2, $3
+)
+ON CONFLICT (workspace_id) DO UPDATE
+SET
+    company_prefix = EXCLUDED.company_prefix,
+    extension_digit = EXCLUDED.extension_digit,
+    updated_at = NOW()
+RETURNING *;
+
+-- name: ReserveSSCCSerials :one
+UPDATE workspace_gs1
+SET
+    last_serial = last_serial + sqlc.arg(count)::bigint,
+    updated_at = NOW()
+WHERE workspace_id = sqlc.arg(workspace_id)
+RETURNING *;
+
+-- name: CreateHandlingUnit :one
+INSERT INTO handling_units (
+    sscc,
+    workspace_id,
+    plan_id,
+    result_id,
+    placement_id,
+    step_number
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+RETURNING *;
+
+-- name: ListHandlingUnits :many
+SELECT *
+FROM handling_units
+WHERE result_id = $1
+ORDER BY step_number;
@@ -0,0 +1,84 @@
+-- name: CreateLoadingSession :one
+INSERT INTO loading_sessions (
+    plan_id,
+    result_id,
+    total_steps,
+    started_by_id
+) VALUES (
+    $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: GetLoadingSession :one
+SELECT *
+FROM loading_sessions
+WHERE session_id = $1
+  AND plan_id = $2;
+
+-- name: GetOpenLoadingSession :one
+SELECT *
+FROM loading_sessions
+WHERE plan_id = $1
+  AND status IN ('active', 'paused');
+
+-- name: ListLoadingSessions :many
+SELECT *
+FROM loading_sessions
+WHERE plan_id = $1
+ORDER BY started_at DESC;
+
+-- name: PauseLoadingSession :execrows
+UPDATE loading_sessions
+SET status = 'paused',
+    paused_at = NOW()
+WHERE session_id = $1
+  AND status = 'active';
+
+-- name: ResumeLoadingSession :execrows
+UPDATE loading_sessions
+SET status = 'active',
+    paused_at = NULL
+WHERE session_id = $1
+  AND status = 'paused';
+
+-- name: CompleteLoadingSession :execrows
+UPDATE loading_sessions
+SET status = 'completed',
+    completed_by_id = $2,
+    completed_at = NOW()
+WHERE session_id = $1
+  AND status IN ('active', 'paused');
+
+-- name: AbandonLoadingSessions :exec
+UPDATE loading_sessions
+SET status = 'abandoned'
+WHERE plan_id = $1
+  AND status IN ('active', 'paused');
+
+-- name: CreateLoadingScan :one
+INSERT INTO loading_scans (
+    session_id,
+    barcode,
+    status,
+    step_number,
+    expected_step,
+    operator_id,
+    client_event_id,
+    scanned_at
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, COALESCE(sqlc.narg('scanned_at')::timestamptz, NOW())
+)
+RETURNING *;
+
+-- name: ListLoadingScans :many
+SELECT *
+FROM loading_scans
+WHERE session_id = $1
+ORDER BY scanned_at, scan_id;
+
+-- name: ListMatchedLoadingSteps :many
+SELECT step_number
+FROM loading_scans
+WHERE session_id = $1
+  AND status = 'MATCHED'
+ORDER BY step_number;
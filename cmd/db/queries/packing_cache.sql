@@ -0,0 +1,31 @@
+-- name: HitPackingResultCache :one
+UPDATE packing_result_cache
+SET hit_count = hit_count + 1,
+    last_hit_at = NOW()
+WHERE cache_key = $1
+RETURNING result;
+
+-- name: UpsertPackingResultCache :exec
+INSERT INTO packing_result_cache (
+    cache_key,
+    backend,
+    result
+) VALUES (
+    $1, $2, $3
+)
+ON CONFLICT (cache_key) DO UPDATE
+SET backend = EXCLUDED.backend,
+    result = EXCLUDED.result,
+    created_at = NOW();
+
+-- name: PrunePackingResultCache :execrows
+-- Drops results not used since before and all but the keep most recently
+-- used ones.
+DELETE FROM packing_result_cache
+WHERE COALESCE(last_hit_at, created_at) < sqlc.arg(before)::timestamptz
+   OR cache_key IN (
+       SELECT c.cache_key
+       FROM packing_result_cache c
+       ORDER BY COALESCE(c.last_hit_at, c.created_at) DESC
+       OFFSET sqlc.arg(keep)::int
+   );
@@ -9,9 +9,16 @@ INSERT INTO load_plans (
     height_mm,
     max_weight_kg,
     created_by_type,
-    created_by_id
+    created_by_id,
+    wall_clearance_mm,
+    item_gap_mm,
+    lashing_points_per_side,
+    lashing_point_capacity_dan,
+    compartments,
+    floor_load_kg_m2,
+    line_load_kg_m
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17
 )
 RETURNING *;
 
@@ -23,9 +30,27 @@ INSERT INTO load_items (
     weight_kg,
     quantity,
     allow_rotation,
-    color_hex
+    color_hex,
+    padding_mm,
+    priority,
+    must_ship,
+    friction_coefficient,
+    temperature_class,
+    gtin,
+    product_id,
+    product_sku,
+    product_overridden,
+    fragile,
+    this_side_up,
+    stackable,
+    max_stack_load_kg,
+    hazmat_class,
+    packaging_type,
+    unit,
+    eaches_per_unit,
+    ordered_eaches
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27
 )
 RETURNING *;
 
@@ -51,12 +76,14 @@ WHERE plan_id = $1
 SELECT *
 FROM load_plans
 WHERE workspace_id IS NOT DISTINCT FROM $1
+  AND parent_plan_id IS NULL
 ORDER BY created_at DESC
 LIMIT $2 OFFSET $3;
 
 -- name: ListLoadPlansAll :many
 SELECT *
 FROM load_plans
+WHERE parent_plan_id IS NULL
 ORDER BY created_at DESC
 LIMIT $1 OFFSET $2;
 
@@ -65,6 +92,7 @@ SELECT *
 FROM load_plans
 WHERE created_by_type = 'guest'
   AND created_by_id = $1
+  AND parent_plan_id IS NULL
 ORDER BY created_at DESC
 LIMIT $2 OFFSET $3;
 
@@ -97,7 +125,21 @@ SET
     weight_kg = $7,
     quantity = $8,
     allow_rotation = $9,
-    color_hex = $10
+    color_hex = $10,
+    padding_mm = $11,
+    priority = $12,
+    must_ship = $13,
+    friction_coefficient = $14,
+    temperature_class = $15,
+    gtin = $16,
+    product_overridden = $17,
+    fragile = $18,
+    this_side_up = $19,
+    stackable = $20,
+    max_stack_load_kg = $21,
+    hazmat_class = $22,
+    packaging_type = $23,
+    ordered_eaches = $24
 WHERE plan_id = $1 AND item_id = $2;
 
 -- name: DeleteLoadItem :exec
@@ -113,7 +155,14 @@ SET
     width_mm = $6,
     height_mm = $7,
     max_weight_kg = $8,
-    status = $9
+    status = $9,
+    wall_clearance_mm = $10,
+    item_gap_mm = $11,
+    lashing_points_per_side = $12,
+    lashing_point_capacity_dan = $13,
+    compartments = $14,
+    floor_load_kg_m2 = $15,
+    line_load_kg_m = $16
 WHERE plan_id = $1
   AND workspace_id IS NOT DISTINCT FROM $2;
 
@@ -127,14 +176,22 @@ INSERT INTO plan_results (
     plan_id,
     total_loaded_weight_kg,
     volume_utilization_pct,
-    is_feasible
+    is_feasible,
+    version,
+    algorithm,
+    inputs
 ) VALUES (
-    $1, $2, $3, $4
+    $1, $2, $3, $4,
+    (SELECT COALESCE(MAX(version), 0) + 1 FROM plan_results WHERE plan_id = $1),
+    $5, $6
 )
 RETURNING *;
 
--- name: DeletePlanResults :exec
-DELETE FROM plan_results WHERE plan_id = $1;
+-- name: DeactivatePlanResults :exec
+UPDATE plan_results SET is_active = FALSE WHERE plan_id = $1 AND is_active;
+
+-- name: ActivatePlanResult :exec
+UPDATE plan_results SET is_active = TRUE WHERE result_id = $1;
 
 -- name: CreatePlanPlacement :copyfrom
 INSERT INTO plan_placements (
@@ -150,7 +207,87 @@ INSERT INTO plan_placements (
 );
 
 -- name: GetPlanResult :one
-SELECT * FROM plan_results WHERE plan_id = $1;
+SELECT * FROM plan_results WHERE plan_id = $1 AND is_active;
+
+-- name: GetPlanResultVersion :one
+SELECT * FROM plan_results WHERE plan_id = $1 AND version = $2;
+
+-- name: ListPlanResults :many
+SELECT * FROM plan_results WHERE plan_id = $1 ORDER BY version DESC;
 
 -- name: ListPlanPlacements :many
-SELECT * FROM plan_placements WHERE result_id = $1 ORDER BY step_number ASC;
\ No newline at end of file
+SELECT * FROM plan_placements WHERE result_id = $1 ORDER BY step_number ASC;
+-- name: CreateScenarioPlan :one
+INSERT INTO load_plans (
+    parent_plan_id,
+    scenario_name,
+    workspace_id,
+    plan_code,
+    status,
+    cont_label,
+    length_mm,
+    width_mm,
+    height_mm,
+    max_weight_kg,
+    created_by_type,
+    created_by_id,
+    wall_clearance_mm,
+    item_gap_mm,
+    lashing_points_per_side,
+    lashing_point_capacity_dan,
+    compartments,
+    floor_load_kg_m2,
+    line_load_kg_m
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19
+)
+RETURNING *;
+
+-- name: CreateOverflowPlan :one
+INSERT INTO load_plans (
+    overflow_of_plan_id,
+    shipment_group_id,
+    workspace_id,
+    plan_code,
+    status,
+    cont_label,
+    length_mm,
+    width_mm,
+    height_mm,
+    max_weight_kg,
+    created_by_type,
+    created_by_id,
+    wall_clearance_mm,
+    item_gap_mm,
+    lashing_points_per_side,
+    lashing_point_capacity_dan,
+    compartments,
+    floor_load_kg_m2,
+    line_load_kg_m
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19
+)
+RETURNING *;
+
+-- name: LockLoadPlan :one
+SELECT status FROM load_plans
+WHERE plan_id = $1
+FOR UPDATE;
+
+-- name: SetPlanShipmentGroup :exec
+UPDATE load_plans
+SET shipment_group_id = $2
+WHERE plan_id = $1
+  AND shipment_group_id IS NULL;
+
+-- name: ListShipmentGroupPlans :many
+SELECT *
+FROM load_plans
+WHERE shipment_group_id = $1
+ORDER BY created_at ASC;
+
+-- name: ListPlanScenarios :many
+SELECT *
+FROM load_plans
+WHERE parent_plan_id = $1
+ORDER BY created_at ASC;
@@ -2,7 +2,8 @@
 SELECT COUNT(*)
 FROM load_plans
 WHERE created_by_type = sqlc.arg(created_by_type)
-  AND created_by_id = sqlc.arg(created_by_id);
+  AND created_by_id = sqlc.arg(created_by_id)
+  AND parent_plan_id IS NULL;
 
 -- name: ClaimPlansFromGuest :exec
 UPDATE load_plans
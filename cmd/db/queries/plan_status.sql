@@ -0,0 +1,18 @@
+-- name: CreatePlanStatusHistory :one
+INSERT INTO plan_status_history (
+    plan_id,
+    from_status,
+    to_status,
+    reason,
+    changed_by_type,
+    changed_by_id
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+)
+RETURNING *;
+
+-- name: ListPlanStatusHistory :many
+SELECT *
+FROM plan_status_history
+WHERE plan_id = $1
+ORDER BY changed_at, history_id;
@@ -7,9 +7,20 @@ INSERT INTO products (
     width_mm,
     height_mm,
     weight_kg,
-    color_hex
+    color_hex,
+    friction_coefficient,
+    temperature_class,
+    gtin,
+    fragile,
+    this_side_up,
+    stackable,
+    max_stack_load_kg,
+    hazmat_class,
+    packaging_type,
+    packaging,
+    shipping_unit
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19
 )
 RETURNING *;
 
@@ -30,6 +41,21 @@ FROM products
 WHERE product_id = $1
   AND (workspace_id = $2 OR workspace_id IS NULL);
 
+-- name: GetProductBySku :one
+SELECT *
+FROM products
+WHERE sku = $1
+  AND (workspace_id = $2 OR workspace_id IS NULL)
+ORDER BY (workspace_id IS NULL) ASC
+LIMIT 1;
+
+-- name: GetProductBySkuAny :one
+SELECT *
+FROM products
+WHERE sku = $1
+ORDER BY (workspace_id IS NULL) DESC, created_at
+LIMIT 1;
+
 -- name: ListProducts :many
 SELECT *
 FROM products
@@ -47,7 +73,18 @@ SET
     height_mm = $7,
     weight_kg = $8,
     color_hex = $9,
-    updated_at = NOW()
+    updated_at = NOW(),
+    friction_coefficient = $10,
+    temperature_class = $11,
+    gtin = $12,
+    fragile = $13,
+    this_side_up = $14,
+    stackable = $15,
+    max_stack_load_kg = $16,
+    hazmat_class = $17,
+    packaging_type = $18,
+    packaging = $19,
+    shipping_unit = $20
 WHERE product_id = $1
   AND workspace_id = $2;
 
@@ -61,7 +98,18 @@ SET
     height_mm = $6,
     weight_kg = $7,
     color_hex = $8,
-    updated_at = NOW()
+    updated_at = NOW(),
+    friction_coefficient = $9,
+    temperature_class = $10,
+    gtin = $11,
+    fragile = $12,
+    this_side_up = $13,
+    stackable = $14,
+    max_stack_load_kg = $15,
+    hazmat_class = $16,
+    packaging_type = $17,
+    packaging = $18,
+    shipping_unit = $19
 WHERE product_id = $1;
 
 -- name: DeleteProduct :exec
@@ -0,0 +1,16 @@
+-- name: GetUserPreference :one
+SELECT *
+FROM user_preferences
+WHERE user_id = $1;
+
+-- name: UpsertUserPreference :one
+INSERT INTO user_preferences (
+    user_id,
+    units
+) VALUES (
+    $1, $2
+)
+ON CONFLICT (user_id) DO UPDATE
+SET units = EXCLUDED.units,
+    updated_at = NOW()
+RETURNING *;
@@ -67,6 +67,7 @@ INSERT INTO permissions (name, description) VALUES
 ('plan:update', 'Update plans'),
 ('plan:delete', 'Delete plans'),
 ('plan:calculate', 'Calculate plan placements'),
+('plan:load', 'Run loading sessions and scan units'),
 
 ('plan_item:*', 'Full access to plan items'),
 ('plan_item:read', 'Read plan items'),
@@ -146,7 +147,7 @@ ON CONFLICT DO NOTHING;
 INSERT INTO role_permissions (role_id, permission_id)
 SELECT r.role_id, p.permission_id
 FROM roles r
-JOIN permissions p ON p.name IN ('workspace:read', 'plan:read', 'plan_item:*', 'product:read', 'container:read', 'dashboard:read')
+JOIN permissions p ON p.name IN ('workspace:read', 'plan:read', 'plan:load', 'plan_item:*', 'product:read', 'container:read', 'dashboard:read')
 WHERE r.name = 'operator'
 ON CONFLICT DO NOTHING;
 
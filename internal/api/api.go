@@ -9,10 +9,13 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/ekastn/load-stuffing-calculator/internal/barcode"
 	"github.com/ekastn/load-stuffing-calculator/internal/cache"
 	"github.com/ekastn/load-stuffing-calculator/internal/config"
 	"github.com/ekastn/load-stuffing-calculator/internal/gateway"
 	"github.com/ekastn/load-stuffing-calculator/internal/handler"
+	"github.com/ekastn/load-stuffing-calculator/internal/middleware"
+	"github.com/ekastn/load-stuffing-calculator/internal/packer"
 	"github.com/ekastn/load-stuffing-calculator/internal/service"
 	"github.com/ekastn/load-stuffing-calculator/internal/store"
 	"github.com/gin-contrib/cors"
@@ -33,10 +36,16 @@ type App struct {
 	containerHandler *handler.ContainerHandler
 	productHandler   *handler.ProductHandler
 	planHandler      *handler.PlanHandler
+	capacityHandler  *handler.CapacityHandler
+	jobHandler       *handler.CalculationJobHandler
+	jobSvc           service.CalculationJobService
+	loadingHandler   *handler.LoadingHandler
 	dashboardHandler *handler.DashboardHandler
 	workspaceHandler *handler.WorkspaceHandler
 	memberHandler    *handler.MemberHandler
 	inviteHandler    *handler.InviteHandler
+	prefHandler      *handler.PreferenceHandler
+	prefSvc          service.PreferenceService
 	jwtSecret        string
 }
 
@@ -45,7 +54,11 @@ func NewApp(cfg config.Config, db *pgxpool.Pool) *App {
 	permCache := cache.NewPermissionCache()
 
 	packingGW := gateway.NewHTTPPackingGateway(cfg.PackingServiceURL, 60*time.Second)
-	pack := service.NewPackingService(packingGW)
+	var resultStore cache.ResultStore
+	if cfg.PackCachePersist {
+		resultStore = cache.NewPostgresResultStore(querier, time.Duration(cfg.PackCacheTTLHours)*time.Hour, cfg.PackCacheMaxRows)
+	}
+	pack := cache.NewCachingPacker(service.NewPackingService(packingGW), service.PackingServiceBackend, cfg.PackCacheSize, resultStore)
 
 	authSvc := service.NewAuthService(querier, cfg.JWTSecret)
 	userSvc := service.NewUserService(querier)
@@ -53,11 +66,20 @@ func NewApp(cfg config.Config, db *pgxpool.Pool) *App {
 	permSvc := service.NewPermissionService(querier)
 	containerSvc := service.NewContainerService(querier)
 	productSvc := service.NewProductService(querier)
-	planSvc := service.NewPlanService(querier, pack)
+	native := cache.NewCachingPacker(packer.NewPacker(), packer.Backend, cfg.PackCacheSize, resultStore)
+	planSvc := service.NewPlanServiceWithBackends(querier, pack, service.PackerBackends{
+		"native": native,
+		"py3dbp": pack,
+	})
+	capacitySvc := service.NewCapacityService(querier, productSvc, native)
+	jobSvc := service.NewCalculationJobService(querier, planSvc, cfg.CalcWorkers)
+	codec := barcode.NewCodec(cfg.BarcodeSecret, cfg.BarcodeAcceptLegacy)
+	loadingSvc := service.NewLoadingService(querier, codec)
 	dashboardSvc := service.NewDashboardService(querier)
 	workspaceSvc := service.NewWorkspaceService(querier)
 	memberSvc := service.NewMemberService(querier)
 	inviteSvc := service.NewInviteService(querier, cfg.JWTSecret)
+	prefSvc := service.NewPreferenceService(querier)
 
 	authHandler := handler.NewAuthHandler(authSvc)
 	userHandler := handler.NewUserHandler(userSvc)
@@ -65,11 +87,15 @@ func NewApp(cfg config.Config, db *pgxpool.Pool) *App {
 	permHandler := handler.NewPermissionHandler(permSvc, permCache)
 	containerHandler := handler.NewContainerHandler(containerSvc)
 	productHandler := handler.NewProductHandler(productSvc)
-	planHandler := handler.NewPlanHandler(planSvc)
+	planHandler := handler.NewPlanHandler(planSvc, codec)
+	capacityHandler := handler.NewCapacityHandler(capacitySvc)
+	jobHandler := handler.NewCalculationJobHandler(jobSvc)
+	loadingHandler := handler.NewLoadingHandler(loadingSvc)
 	dashboardHandler := handler.NewDashboardHandler(dashboardSvc)
 	workspaceHandler := handler.NewWorkspaceHandler(workspaceSvc)
 	memberHandler := handler.NewMemberHandler(memberSvc)
 	inviteHandler := handler.NewInviteHandler(inviteSvc)
+	prefHandler := handler.NewPreferenceHandler(prefSvc)
 
 	app := &App{
 		config:           cfg,
@@ -83,10 +109,16 @@ func NewApp(cfg config.Config, db *pgxpool.Pool) *App {
 		containerHandler: containerHandler,
 		productHandler:   productHandler,
 		planHandler:      planHandler,
+		capacityHandler:  capacityHandler,
+		jobHandler:       jobHandler,
+		jobSvc:           jobSvc,
+		loadingHandler:   loadingHandler,
 		dashboardHandler: dashboardHandler,
 		workspaceHandler: workspaceHandler,
 		memberHandler:    memberHandler,
 		inviteHandler:    inviteHandler,
+		prefHandler:      prefHandler,
+		prefSvc:          prefSvc,
 		jwtSecret:        cfg.JWTSecret,
 	}
 
@@ -101,7 +133,8 @@ func (a *App) setupRouter() {
 	config := cors.DefaultConfig()
 	config.AllowAllOrigins = true
 	config.AllowMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"}
-	config.AllowHeaders = []string{"Origin", "Content-Length", "Content-Type", "Authorization"}
+	config.AllowHeaders = []string{"Origin", "Content-Length", "Content-Type", "Authorization", middleware.UnitsHeader}
+	config.ExposeHeaders = []string{middleware.UnitsHeader}
 
 	router.Use(cors.New(config))
 	a.setupRoutes(router)
@@ -114,6 +147,13 @@ func (a *App) Run() error {
 		Handler: a.router,
 	}
 
+	jobCtx, stopJobs := context.WithCancel(context.Background())
+	jobsDone := make(chan struct{})
+	go func() {
+		defer close(jobsDone)
+		a.jobSvc.Start(jobCtx)
+	}()
+
 	go func() {
 		log.Printf("Server starting on %s", a.config.Addr)
 		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
@@ -132,6 +172,10 @@ func (a *App) Run() error {
 		log.Fatal("Server forced to shutdown:", err)
 	}
 
+	// Interrupted jobs stay "running" and are requeued on the next start.
+	stopJobs()
+	<-jobsDone
+
 	log.Println("Server exiting")
 	return nil
 }
@@ -29,12 +29,15 @@ func (a *App) setupRoutes(r *gin.Engine) {
 		}
 
 		v1.Use(middleware.JWT(a.jwtSecret))
+		v1.Use(middleware.Units(a.prefSvc.UnitsForUser))
 
 		// Auth endpoints that require a valid access token.
 		authAuthed := v1.Group("/auth")
 		{
 			authAuthed.POST("/switch-workspace", a.authHandler.SwitchWorkspace)
 			authAuthed.GET("/me", a.authHandler.Me)
+			authAuthed.GET("/preferences", a.prefHandler.GetPreferences)
+			authAuthed.PUT("/preferences", a.prefHandler.UpdatePreferences)
 		}
 
 		perm := middleware.NewPermissionMiddleware(a.querier, a.permCache)
@@ -97,6 +100,8 @@ func (a *App) setupRoutes(r *gin.Engine) {
 			workspaces.POST("", perm.Require("workspace:create"), a.workspaceHandler.CreateWorkspace)
 			workspaces.PATCH("/:id", perm.Require("workspace:update"), a.workspaceHandler.UpdateWorkspace)
 			workspaces.DELETE("/:id", perm.Require("workspace:delete"), a.workspaceHandler.DeleteWorkspace)
+			workspaces.GET("/:id/gs1", perm.Require("workspace:read"), a.workspaceHandler.GetGS1Settings)
+			workspaces.PUT("/:id/gs1", perm.Require("workspace:update"), a.workspaceHandler.UpdateGS1Settings)
 		}
 
 		members := v1.Group("/members")
@@ -131,9 +136,49 @@ func (a *App) setupRoutes(r *gin.Engine) {
 			plans.DELETE("/:id/items/:itemId", perm.Require("plan_item:*"), a.planHandler.DeletePlanItem)
 
 			plans.POST("/:id/calculate", perm.Require("plan:calculate"), a.planHandler.CalculatePlan)
+			plans.GET("/:id/calculate/stream", perm.Require("plan:calculate"), a.planHandler.StreamCalculation)
+			plans.POST("/:id/compare", perm.Require("plan:calculate"), a.planHandler.ComparePlan)
+			plans.POST("/:id/alternatives/:alternativeId/adopt", perm.Require("plan:calculate"), a.planHandler.AdoptAlternative)
+			plans.GET("/:id/preflight", perm.Require("plan:read"), a.planHandler.PreflightPlan)
+			plans.POST("/:id/overflow", perm.Require("plan:create"), a.planHandler.CreateOverflowPlan)
+			plans.GET("/:id/shipment", perm.Require("plan:read"), a.planHandler.GetShipmentGroup)
+			plans.GET("/:id/status-history", perm.Require("plan:read"), a.planHandler.GetPlanStatusHistory)
+
+			plans.GET("/:id/results", perm.Require("plan:read"), a.planHandler.ListResultVersions)
+			plans.GET("/:id/results/diff", perm.Require("plan:read"), a.planHandler.DiffResultVersions)
+			plans.GET("/:id/results/:version", perm.Require("plan:read"), a.planHandler.GetResultVersion)
+			plans.POST("/:id/results/:version/restore", perm.Require("plan:calculate"), a.planHandler.RestoreResultVersion)
+
+			plans.POST("/:id/scenarios", perm.Require("plan:create"), a.planHandler.CreateScenario)
+			plans.GET("/:id/scenarios", perm.Require("plan:read"), a.planHandler.ListScenarios)
+			plans.GET("/:id/scenarios/compare", perm.Require("plan:read"), a.planHandler.CompareScenarios)
+
+			plans.POST("/:id/jobs", perm.Require("plan:calculate"), a.jobHandler.EnqueueCalculation)
+			plans.GET("/:id/jobs/:jobId", perm.Require("plan:read"), a.jobHandler.GetCalculationJob)
+			plans.POST("/:id/jobs/:jobId/cancel", perm.Require("plan:calculate"), a.jobHandler.CancelCalculationJob)
 
 			plans.GET("/:id/barcodes", perm.Require("plan:read"), a.planHandler.GetPlanBarcodes)
+			plans.GET("/:id/barcodes/:step/image", perm.Require("plan:read"), a.planHandler.GetPlanBarcodeImage)
+			plans.GET("/:id/labels", perm.Require("plan:read"), a.planHandler.GetPlanLabels)
 			plans.POST("/:id/validations", perm.Require("plan:read"), a.planHandler.ValidatePlanBarcode)
+			plans.POST("/:id/handling-units", perm.Require("plan:load"), a.planHandler.AssignHandlingUnits)
+			plans.GET("/:id/handling-units", perm.Require("plan:read"), a.planHandler.ListHandlingUnits)
+			plans.GET("/:id/handling-units/labels", perm.Require("plan:read"), a.planHandler.GetHandlingUnitLabels)
+
+			plans.POST("/:id/loading-sessions", perm.Require("plan:load"), a.loadingHandler.StartLoadingSession)
+			plans.GET("/:id/loading-sessions", perm.Require("plan:read"), a.loadingHandler.ListLoadingSessions)
+			plans.GET("/:id/loading-sessions/:sessionId", perm.Require("plan:read"), a.loadingHandler.GetLoadingSession)
+			plans.POST("/:id/loading-sessions/:sessionId/pause", perm.Require("plan:load"), a.loadingHandler.PauseLoadingSession)
+			plans.POST("/:id/loading-sessions/:sessionId/complete", perm.Require("plan:load"), a.loadingHandler.CompleteLoadingSession)
+			plans.POST("/:id/loading-sessions/:sessionId/scans", perm.Require("plan:load"), a.loadingHandler.RecordLoadingScan)
+			plans.GET("/:id/loading-sessions/:sessionId/scans", perm.Require("plan:read"), a.loadingHandler.ListLoadingScans)
+			plans.GET("/:id/offline-bundle", perm.Require("plan:load"), a.loadingHandler.GetOfflineBundle)
+			plans.POST("/:id/offline-sync", perm.Require("plan:load"), a.loadingHandler.SyncOfflineScans)
+		}
+
+		capacity := v1.Group("/capacity")
+		{
+			capacity.POST("/max-quantity", perm.Require("plan:calculate"), a.capacityHandler.MaxQuantity)
 		}
 	}
 }
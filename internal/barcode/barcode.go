@@ -0,0 +1,69 @@
+// Package barcode builds and reads the labels put on loading units. Labels
+// are signed (see Codec); the legacy format named the plan, the loading step
+// and the item by the first eight characters of their IDs:
+// PLAN-<plan>-STEP-<step>-<item>.
+package barcode
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Parsed is a label read back into its parts. Signed labels carry full IDs,
+// legacy ones short IDs.
+type Parsed struct {
+	Version    int
+	PlanID     string
+	StepNumber int
+	ItemID     string
+}
+
+// MatchesPlan reports whether the label names planID.
+func (p *Parsed) MatchesPlan(planID uuid.UUID) bool {
+	return p.PlanID == p.idOf(planID)
+}
+
+// MatchesItem reports whether the label names itemID.
+func (p *Parsed) MatchesItem(itemID uuid.UUID) bool {
+	return p.ItemID == p.idOf(itemID)
+}
+
+func (p *Parsed) idOf(id uuid.UUID) string {
+	if p.Version == VersionLegacy {
+		return ShortID(id)
+	}
+	return id.String()
+}
+
+// Generate returns the legacy label of a loading step.
+func Generate(planID uuid.UUID, stepNumber int, itemID uuid.UUID) string {
+	return fmt.Sprintf("PLAN-%s-STEP-%03d-%s", ShortID(planID), stepNumber, ShortID(itemID))
+}
+
+// Parse reads a legacy label; it returns nil when s is not one.
+func Parse(s string) *Parsed {
+	parts := strings.Split(s, "-")
+	if len(parts) != 5 || parts[0] != "PLAN" || parts[2] != "STEP" {
+		return nil
+	}
+
+	stepNum, err := strconv.Atoi(parts[3])
+	if err != nil {
+		return nil
+	}
+
+	return &Parsed{
+		Version:    VersionLegacy,
+		PlanID:     parts[1],
+		StepNumber: stepNum,
+		ItemID:     parts[4],
+	}
+}
+
+// ShortID is the form of id used in labels.
+func ShortID(id uuid.UUID) string {
+	return id.String()[:8]
+}
@@ -1,4 +1,4 @@
-package handler
+package barcode
 
 import (
 	"testing"
@@ -13,7 +13,7 @@ func TestGenerateBarcode(t *testing.T) {
 	step := 1
 
 	expected := "PLAN-a3f2e8b1-STEP-001-c4d9f2a3"
-	actual := generateBarcode(planID, step, itemID)
+	actual := Generate(planID, step, itemID)
 
 	assert.Equal(t, expected, actual)
 }
@@ -22,12 +22,13 @@ func TestParseBarcode(t *testing.T) {
 	tests := []struct {
 		name    string
 		barcode string
-		want    *ParsedBarcode
+		want    *Parsed
 	}{
 		{
 			name:    "valid barcode",
 			barcode: "PLAN-a3f2e8b1-STEP-001-c4d9f2a3",
-			want: &ParsedBarcode{
+			want: &Parsed{
+				Version:    VersionLegacy,
 				PlanID:     "a3f2e8b1",
 				StepNumber: 1,
 				ItemID:     "c4d9f2a3",
@@ -57,7 +58,7 @@ func TestParseBarcode(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := parseBarcode(tt.barcode)
+			got := Parse(tt.barcode)
 			assert.Equal(t, tt.want, got)
 		})
 	}
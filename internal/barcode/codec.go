@@ -0,0 +1,125 @@
+package barcode
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Label format versions.
+const (
+	VersionLegacy = 1
+	VersionSigned = 2
+)
+
+// signedPrefix starts a signed label:
+// LS2-<plan>-<step>-<item>-<signature>. IDs are the full UUIDs in base32 and
+// the signature is a truncated HMAC-SHA256, so labels only use upper-case
+// letters, digits and '-' and fit QR alphanumeric mode and Code128.
+const signedPrefix = "LS2"
+
+// signatureBytes is how much of the HMAC a label carries.
+const signatureBytes = 10
+
+var (
+	ErrInvalidFormat    = errors.New("invalid barcode format")
+	ErrInvalidSignature = errors.New("barcode signature does not match")
+	ErrLegacyRejected   = errors.New("legacy barcodes are not accepted")
+)
+
+var idEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// Codec generates signed labels and reads signed labels and, when allowed,
+// legacy ones.
+type Codec struct {
+	secret       []byte
+	acceptLegacy bool
+}
+
+// NewCodec returns a Codec signing with secret. acceptLegacy keeps unsigned
+// PLAN-xxxx-STEP-nnn-yyyy labels printed before signing was introduced
+// scannable.
+func NewCodec(secret string, acceptLegacy bool) *Codec {
+	return &Codec{secret: []byte(secret), acceptLegacy: acceptLegacy}
+}
+
+// Generate returns the signed label of a loading step.
+func (c *Codec) Generate(planID uuid.UUID, stepNumber int, itemID uuid.UUID) string {
+	plan := idEncoding.EncodeToString(planID[:])
+	item := idEncoding.EncodeToString(itemID[:])
+	step := fmt.Sprintf("%03d", stepNumber)
+	return strings.Join([]string{signedPrefix, plan, step, item, c.labelSignature(plan, step, item)}, "-")
+}
+
+// Parse reads a scanned label. Signed labels must carry a valid signature;
+// legacy labels are read only when the codec accepts them.
+func (c *Codec) Parse(s string) (*Parsed, error) {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(strings.ToUpper(s), signedPrefix+"-") {
+		p := Parse(s)
+		if p == nil {
+			return nil, ErrInvalidFormat
+		}
+		if !c.acceptLegacy {
+			return nil, ErrLegacyRejected
+		}
+		return p, nil
+	}
+
+	parts := strings.Split(strings.ToUpper(s), "-")
+	if len(parts) != 5 {
+		return nil, ErrInvalidFormat
+	}
+	plan, step, item, sig := parts[1], parts[2], parts[3], parts[4]
+	planID, err := decodeID(plan)
+	if err != nil {
+		return nil, ErrInvalidFormat
+	}
+	itemID, err := decodeID(item)
+	if err != nil {
+		return nil, ErrInvalidFormat
+	}
+	stepNum, err := strconv.Atoi(step)
+	if err != nil {
+		return nil, ErrInvalidFormat
+	}
+	if !hmac.Equal([]byte(sig), []byte(c.labelSignature(plan, step, item))) {
+		return nil, ErrInvalidSignature
+	}
+
+	return &Parsed{
+		Version:    VersionSigned,
+		PlanID:     planID.String(),
+		StepNumber: stepNum,
+		ItemID:     itemID.String(),
+	}, nil
+}
+
+// Signature returns the hex HMAC-SHA256 of parts joined by '|'. Besides
+// labels it signs the offline bundles devices validate labels with.
+func (c *Codec) Signature(parts ...string) string {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write([]byte(strings.Join(parts, "|")))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (c *Codec) labelSignature(plan, step, item string) string {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write([]byte(strings.Join([]string{signedPrefix, plan, step, item}, "|")))
+	return idEncoding.EncodeToString(mac.Sum(nil)[:signatureBytes])
+}
+
+func decodeID(s string) (uuid.UUID, error) {
+	raw, err := idEncoding.DecodeString(s)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return uuid.FromBytes(raw)
+}
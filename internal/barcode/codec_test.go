@@ -0,0 +1,70 @@
+package barcode
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodecRoundTrip(t *testing.T) {
+	c := NewCodec("secret", true)
+	planID := uuid.MustParse("a3f2e8b1-c4d9-4f2a-b1c8-3d9e8f7a6b5c")
+	itemID := uuid.MustParse("c4d9f2a3-8b1c-4e5f-9a2b-6d7e8f9a0b1c")
+
+	code := c.Generate(planID, 12, itemID)
+	assert.True(t, strings.HasPrefix(code, "LS2-"))
+	// QR alphanumeric mode and Code128 both cover this alphabet.
+	assert.Regexp(t, regexp.MustCompile(`^[A-Z0-9-]+$`), code)
+
+	got, err := c.Parse(code)
+	require.NoError(t, err)
+	assert.Equal(t, VersionSigned, got.Version)
+	assert.Equal(t, 12, got.StepNumber)
+	assert.True(t, got.MatchesPlan(planID))
+	assert.True(t, got.MatchesItem(itemID))
+
+	// Another plan sharing the first eight characters is told apart.
+	twin := uuid.MustParse("a3f2e8b1-0000-4000-8000-000000000000")
+	assert.False(t, got.MatchesPlan(twin))
+}
+
+func TestCodecParse(t *testing.T) {
+	c := NewCodec("secret", true)
+	planID, itemID := uuid.New(), uuid.New()
+	code := c.Generate(planID, 1, itemID)
+	parts := strings.Split(code, "-")
+
+	tests := []struct {
+		name    string
+		codec   *Codec
+		barcode string
+		wantErr error
+	}{
+		{"lower_case_scan", c, strings.ToLower(code), nil},
+		{"other_secret", NewCodec("other", true), code, ErrInvalidSignature},
+		{"forged_step", c, strings.Join([]string{parts[0], parts[1], "002", parts[3], parts[4]}, "-"), ErrInvalidSignature},
+		{"truncated", c, strings.Join(parts[:4], "-"), ErrInvalidFormat},
+		{"bad_id", c, strings.Join([]string{parts[0], "XYZ", parts[2], parts[3], parts[4]}, "-"), ErrInvalidFormat},
+		{"legacy_accepted", c, Generate(planID, 1, itemID), nil},
+		{"legacy_rejected", NewCodec("secret", false), Generate(planID, 1, itemID), ErrLegacyRejected},
+		{"garbage", c, "hello", ErrInvalidFormat},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.codec.Parse(tt.barcode)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, got)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, got.MatchesPlan(planID))
+			assert.True(t, got.MatchesItem(itemID))
+			assert.Equal(t, 1, got.StepNumber)
+		})
+	}
+}
@@ -0,0 +1,357 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ekastn/load-stuffing-calculator/internal/packer"
+	"github.com/ekastn/load-stuffing-calculator/internal/store"
+)
+
+// ResultStore is a second-level store for packing results shared between
+// processes (e.g. Postgres).
+type ResultStore interface {
+	Get(ctx context.Context, key string) (packer.PackingResult, bool, error)
+	Put(ctx context.Context, key, backend string, result packer.PackingResult) error
+}
+
+// CachingPacker serves repeated packing requests from a cache keyed by the
+// content of the request. Item IDs, labels and colours are not part of the key,
+// so plans built from the same template share results.
+type CachingPacker struct {
+	next       packer.Packer
+	backend    string
+	mem        *lruCache
+	persistent ResultStore
+}
+
+// NewCachingPacker wraps next. backend must change whenever next's behaviour
+// changes. size is the in-memory LRU capacity; persistent may be nil.
+func NewCachingPacker(next packer.Packer, backend string, size int, persistent ResultStore) *CachingPacker {
+	return &CachingPacker{
+		next:       next,
+		backend:    backend,
+		mem:        newLRUCache(size),
+		persistent: persistent,
+	}
+}
+
+func (c *CachingPacker) Pack(ctx context.Context, container packer.ContainerInput, items []packer.ItemInput) (packer.PackingResult, error) {
+	start := time.Now()
+	key := CacheKey(c.backend, container, items)
+
+	if cached, ok := c.mem.get(key); ok {
+		return restoreResult(cached, container, items, time.Since(start)), nil
+	}
+
+	if c.persistent != nil {
+		cached, ok, err := c.persistent.Get(ctx, key)
+		if err != nil {
+			log.Printf("packing cache: lookup failed: %v", err)
+		} else if ok {
+			c.mem.put(key, cached)
+			return restoreResult(cached, container, items, time.Since(start)), nil
+		}
+	}
+
+	res, err := c.next.Pack(ctx, container, items)
+	if err != nil {
+		return res, err
+	}
+
+	anon := anonymiseResult(res, items)
+	c.mem.put(key, anon)
+	if c.persistent != nil {
+		if err := c.persistent.Put(ctx, key, c.backend, anon); err != nil {
+			log.Printf("packing cache: store failed: %v", err)
+		}
+	}
+	return res, nil
+}
+
+type keyContainer struct {
+	Length    float64 `json:"l"`
+	Width     float64 `json:"w"`
+	Height    float64 `json:"h"`
+	MaxWeight float64 `json:"max_wt"`
+	Strategy  string  `json:"strategy"`
+	Goal      string  `json:"goal"`
+	Gravity   bool    `json:"gravity"`
+
+	// omitempty keeps keys of requests without clearances unchanged.
+	WallClearance float64 `json:"wall,omitempty"`
+	ItemGap       float64 `json:"gap,omitempty"`
+}
+
+type keyItem struct {
+	Length        float64 `json:"l"`
+	Width         float64 `json:"w"`
+	Height        float64 `json:"h"`
+	Weight        float64 `json:"wt"`
+	Quantity      int     `json:"qty"`
+	AllowRotation bool    `json:"rot"`
+	Padding       float64 `json:"pad,omitempty"`
+
+	// Handling flags change how the native packer places units.
+	Fragile      bool    `json:"frag,omitempty"`
+	ThisSideUp   bool    `json:"up,omitempty"`
+	NoStack      bool    `json:"nostack,omitempty"`
+	MaxStackLoad float64 `json:"maxload,omitempty"`
+}
+
+type keyPayload struct {
+	Backend   string       `json:"backend"`
+	Container keyContainer `json:"container"`
+	Items     []keyItem    `json:"items"`
+}
+
+// CacheKey returns the canonical hash of a packing request. Items keep their
+// order because some strategies are order-sensitive.
+func CacheKey(backend string, container packer.ContainerInput, items []packer.ItemInput) string {
+	payload := keyPayload{
+		Backend: backend,
+		Container: keyContainer{
+			Length:    container.Length,
+			Width:     container.Width,
+			Height:    container.Height,
+			MaxWeight: container.MaxWeight,
+			Strategy:  strings.ToLower(strings.TrimSpace(container.Options.Strategy)),
+			Goal:      strings.ToLower(strings.TrimSpace(container.Options.Goal)),
+			Gravity:   container.Options.Gravity,
+
+			WallClearance: container.WallClearance,
+			ItemGap:       container.ItemGap,
+		},
+		Items: make([]keyItem, 0, len(items)),
+	}
+	for _, it := range items {
+		payload.Items = append(payload.Items, keyItem{
+			Length:        it.Length,
+			Width:         it.Width,
+			Height:        it.Height,
+			Weight:        it.Weight,
+			Quantity:      it.Quantity,
+			AllowRotation: it.AllowRotation,
+			Padding:       it.Padding,
+
+			Fragile:      it.Fragile,
+			ThisSideUp:   it.ThisSideUp,
+			NoStack:      it.NoStack,
+			MaxStackLoad: it.MaxStackLoad,
+		})
+	}
+
+	// Marshalling structs (not maps) keeps field order, and therefore the hash, stable.
+	raw, _ := json.Marshal(payload)
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// anonymiseResult replaces item IDs with their position in items so the
+// result can be reused for requests with different IDs.
+func anonymiseResult(res packer.PackingResult, items []packer.ItemInput) packer.PackingResult {
+	ordinal := make(map[string]string, len(items))
+	for i, it := range items {
+		ordinal[it.ID] = strconv.Itoa(i)
+	}
+
+	out := res
+	out.ContainerID = ""
+	out.PackedItems = make([]packer.PackedItem, len(res.PackedItems))
+	for i, pi := range res.PackedItems {
+		pi.ItemID = ordinal[pi.ItemID]
+		pi.InstanceID = pi.ItemID + instanceSuffix(pi.InstanceID)
+		pi.Label = ""
+		pi.ProductSKU = ""
+		out.PackedItems[i] = pi
+	}
+	out.UnfitItems = make([]packer.ItemInput, len(res.UnfitItems))
+	for i, u := range res.UnfitItems {
+		out.UnfitItems[i] = packer.ItemInput{ID: ordinal[u.ID], Quantity: u.Quantity}
+	}
+	return out
+}
+
+// restoreResult maps an anonymised result back onto the current request.
+func restoreResult(cached packer.PackingResult, container packer.ContainerInput, items []packer.ItemInput, lookup time.Duration) packer.PackingResult {
+	byOrdinal := func(ord string) (packer.ItemInput, bool) {
+		i, err := strconv.Atoi(ord)
+		if err != nil || i < 0 || i >= len(items) {
+			return packer.ItemInput{}, false
+		}
+		return items[i], true
+	}
+
+	out := cached
+	out.ContainerID = container.ID
+	out.PackedItems = make([]packer.PackedItem, 0, len(cached.PackedItems))
+	for _, pi := range cached.PackedItems {
+		in, ok := byOrdinal(pi.ItemID)
+		if !ok {
+			continue
+		}
+		pi.InstanceID = in.ID + instanceSuffix(pi.InstanceID)
+		pi.ItemID = in.ID
+		pi.Label = in.Label
+		pi.ProductSKU = in.ProductSKU
+		out.PackedItems = append(out.PackedItems, pi)
+	}
+	out.UnfitItems = make([]packer.ItemInput, 0, len(cached.UnfitItems))
+	for _, u := range cached.UnfitItems {
+		in, ok := byOrdinal(u.ID)
+		if !ok {
+			continue
+		}
+		in.Quantity = u.Quantity
+		out.UnfitItems = append(out.UnfitItems, in)
+	}
+	out.DurationMs = lookup.Milliseconds()
+	out.CacheHit = true
+	return out
+}
+
+// instanceSuffix returns the ":<n>" part of an instance ID.
+func instanceSuffix(instanceID string) string {
+	if i := strings.LastIndex(instanceID, ":"); i >= 0 {
+		return instanceID[i:]
+	}
+	return ""
+}
+
+// lruCache is a fixed-size, concurrency-safe LRU. A size below 1 disables it.
+type lruCache struct {
+	mu    sync.Mutex
+	size  int
+	order *list.List
+	items map[string]*list.Element
+}
+
+type lruEntry struct {
+	key    string
+	result packer.PackingResult
+}
+
+func newLRUCache(size int) *lruCache {
+	return &lruCache{
+		size:  size,
+		order: list.New(),
+		items: make(map[string]*list.Element),
+	}
+}
+
+func (c *lruCache) get(key string) (packer.PackingResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.items[key]
+	if !ok {
+		return packer.PackingResult{}, false
+	}
+	c.order.MoveToFront(el)
+	return el.Value.(*lruEntry).result, true
+}
+
+func (c *lruCache) put(key string, result packer.PackingResult) {
+	if c.size < 1 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.items[key]; ok {
+		el.Value.(*lruEntry).result = result
+		c.order.MoveToFront(el)
+		return
+	}
+	c.items[key] = c.order.PushFront(&lruEntry{key: key, result: result})
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*lruEntry).key)
+	}
+}
+
+// pruneInterval is how often a PostgresResultStore prunes the table, at most.
+const pruneInterval = time.Hour
+
+// PostgresResultStore persists packing results in the packing_result_cache
+// table. Results not used for ttl are dropped, and at most maxRows are kept,
+// the most recently used first.
+type PostgresResultStore struct {
+	q       store.Querier
+	ttl     time.Duration
+	maxRows int
+	now     func() time.Time
+
+	mu        sync.Mutex
+	lastPrune time.Time
+}
+
+func NewPostgresResultStore(q store.Querier, ttl time.Duration, maxRows int) *PostgresResultStore {
+	return &PostgresResultStore{q: q, ttl: ttl, maxRows: maxRows, now: time.Now}
+}
+
+func (s *PostgresResultStore) Get(ctx context.Context, key string) (packer.PackingResult, bool, error) {
+	raw, err := s.q.HitPackingResultCache(ctx, key)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return packer.PackingResult{}, false, nil
+		}
+		return packer.PackingResult{}, false, err
+	}
+	var res packer.PackingResult
+	if err := json.Unmarshal(raw, &res); err != nil {
+		return packer.PackingResult{}, false, fmt.Errorf("decode cached result: %w", err)
+	}
+	return res, true, nil
+}
+
+func (s *PostgresResultStore) Put(ctx context.Context, key, backend string, result packer.PackingResult) error {
+	raw, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	if err := s.q.UpsertPackingResultCache(ctx, store.UpsertPackingResultCacheParams{
+		CacheKey: key,
+		Backend:  backend,
+		Result:   raw,
+	}); err != nil {
+		return err
+	}
+	s.prune(ctx)
+	return nil
+}
+
+// prune drops expired and surplus results once per pruneInterval. Failures
+// are logged; the next interval tries again.
+func (s *PostgresResultStore) prune(ctx context.Context) {
+	now := s.now()
+	s.mu.Lock()
+	if now.Sub(s.lastPrune) < pruneInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.lastPrune = now
+	s.mu.Unlock()
+
+	n, err := s.q.PrunePackingResultCache(ctx, store.PrunePackingResultCacheParams{
+		Before: now.Add(-s.ttl),
+		Keep:   int32(s.maxRows),
+	})
+	if err != nil {
+		log.Printf("packing cache: prune failed: %v", err)
+		return
+	}
+	if n > 0 {
+		log.Printf("packing cache: pruned %d results", n)
+	}
+}
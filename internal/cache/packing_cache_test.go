@@ -0,0 +1,226 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ekastn/load-stuffing-calculator/internal/mocks"
+	"github.com/ekastn/load-stuffing-calculator/internal/packer"
+	"github.com/ekastn/load-stuffing-calculator/internal/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type countingPacker struct {
+	calls int
+	err   error
+}
+
+func (p *countingPacker) Pack(ctx context.Context, container packer.ContainerInput, items []packer.ItemInput) (packer.PackingResult, error) {
+	p.calls++
+	if p.err != nil {
+		return packer.PackingResult{}, p.err
+	}
+	res := packer.PackingResult{ContainerID: container.ID, Algorithm: "fake", DurationMs: 500}
+	for _, it := range items {
+		res.PackedItems = append(res.PackedItems, packer.PackedItem{
+			ItemID:     it.ID,
+			InstanceID: it.ID + ":0",
+			Label:      it.Label,
+			Position:   packer.Position{X: float64(len(res.PackedItems)) * 10},
+		})
+	}
+	res.UnfitItems = []packer.ItemInput{{ID: items[len(items)-1].ID, Label: items[len(items)-1].Label, Quantity: 1}}
+	res.TotalPackedItems = len(res.PackedItems)
+	return res, nil
+}
+
+type memStore struct {
+	data map[string]packer.PackingResult
+}
+
+func (s *memStore) Get(ctx context.Context, key string) (packer.PackingResult, bool, error) {
+	res, ok := s.data[key]
+	return res, ok, nil
+}
+
+func (s *memStore) Put(ctx context.Context, key, backend string, result packer.PackingResult) error {
+	s.data[key] = result
+	return nil
+}
+
+func testContainer(id string) packer.ContainerInput {
+	return packer.ContainerInput{ID: id, Length: 1000, Width: 1000, Height: 1000, MaxWeight: 100}
+}
+
+func testItems(prefix string) []packer.ItemInput {
+	return []packer.ItemInput{
+		{ID: prefix + "-a", Label: prefix + " A", Length: 100, Width: 100, Height: 100, Weight: 1, Quantity: 1, Color: "#ff0000"},
+		{ID: prefix + "-b", Label: prefix + " B", Length: 200, Width: 100, Height: 100, Weight: 2, Quantity: 2},
+	}
+}
+
+func TestCacheKey(t *testing.T) {
+	base := CacheKey("b1", testContainer("c1"), testItems("x"))
+
+	t.Run("ignores_ids_labels_and_colours", func(t *testing.T) {
+		assert.Equal(t, base, CacheKey("b1", testContainer("c2"), testItems("y")))
+	})
+
+	t.Run("normalises_option_case", func(t *testing.T) {
+		c1 := testContainer("c1")
+		c1.Options.Strategy = "BFD"
+		c2 := testContainer("c1")
+		c2.Options.Strategy = " bfd "
+		assert.Equal(t, CacheKey("b1", c1, testItems("x")), CacheKey("b1", c2, testItems("x")))
+	})
+
+	t.Run("changes_with_backend_geometry_and_options", func(t *testing.T) {
+		assert.NotEqual(t, base, CacheKey("b2", testContainer("c1"), testItems("x")))
+
+		items := testItems("x")
+		items[0].Quantity = 3
+		assert.NotEqual(t, base, CacheKey("b1", testContainer("c1"), items))
+
+		c := testContainer("c1")
+		c.Options.Gravity = true
+		assert.NotEqual(t, base, CacheKey("b1", c, testItems("x")))
+	})
+
+	t.Run("changes_with_clearances", func(t *testing.T) {
+		c := testContainer("c1")
+		c.WallClearance = 20
+		assert.NotEqual(t, base, CacheKey("b1", c, testItems("x")))
+
+		c = testContainer("c1")
+		c.ItemGap = 10
+		assert.NotEqual(t, base, CacheKey("b1", c, testItems("x")))
+
+		items := testItems("x")
+		items[0].Padding = 5
+		assert.NotEqual(t, base, CacheKey("b1", testContainer("c1"), items))
+	})
+
+	t.Run("changes_with_handling", func(t *testing.T) {
+		for _, set := range []func(*packer.ItemInput){
+			func(it *packer.ItemInput) { it.Fragile = true },
+			func(it *packer.ItemInput) { it.ThisSideUp = true },
+			func(it *packer.ItemInput) { it.NoStack = true },
+			func(it *packer.ItemInput) { it.MaxStackLoad = 50 },
+		} {
+			items := testItems("x")
+			set(&items[0])
+			assert.NotEqual(t, base, CacheKey("b1", testContainer("c1"), items))
+		}
+	})
+}
+
+func TestCachingPacker_Pack(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("hit_remaps_to_current_items", func(t *testing.T) {
+		next := &countingPacker{}
+		cp := NewCachingPacker(next, "b1", 8, nil)
+
+		first, err := cp.Pack(ctx, testContainer("c1"), testItems("x"))
+		require.NoError(t, err)
+		assert.False(t, first.CacheHit)
+
+		second, err := cp.Pack(ctx, testContainer("c2"), testItems("y"))
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, next.calls)
+		assert.True(t, second.CacheHit)
+		assert.Equal(t, "c2", second.ContainerID)
+		assert.Equal(t, "fake", second.Algorithm)
+		require.Len(t, second.PackedItems, 2)
+		assert.Equal(t, "y-a", second.PackedItems[0].ItemID)
+		assert.Equal(t, "y-a:0", second.PackedItems[0].InstanceID)
+		assert.Equal(t, "y A", second.PackedItems[0].Label)
+		assert.Equal(t, first.PackedItems[1].Position, second.PackedItems[1].Position)
+		require.Len(t, second.UnfitItems, 1)
+		assert.Equal(t, "y-b", second.UnfitItems[0].ID)
+		assert.Equal(t, 1, second.UnfitItems[0].Quantity)
+		assert.Equal(t, 100.0, second.UnfitItems[0].Width)
+	})
+
+	t.Run("errors_are_not_cached", func(t *testing.T) {
+		next := &countingPacker{err: errors.New("boom")}
+		cp := NewCachingPacker(next, "b1", 8, nil)
+
+		_, err := cp.Pack(ctx, testContainer("c1"), testItems("x"))
+		assert.Error(t, err)
+		_, err = cp.Pack(ctx, testContainer("c1"), testItems("x"))
+		assert.Error(t, err)
+		assert.Equal(t, 2, next.calls)
+	})
+
+	t.Run("persistent_store_serves_other_instances", func(t *testing.T) {
+		shared := &memStore{data: map[string]packer.PackingResult{}}
+
+		_, err := NewCachingPacker(&countingPacker{}, "b1", 8, shared).Pack(ctx, testContainer("c1"), testItems("x"))
+		require.NoError(t, err)
+
+		next := &countingPacker{}
+		res, err := NewCachingPacker(next, "b1", 8, shared).Pack(ctx, testContainer("c1"), testItems("z"))
+		require.NoError(t, err)
+		assert.Equal(t, 0, next.calls)
+		assert.True(t, res.CacheHit)
+		assert.Equal(t, "z-a", res.PackedItems[0].ItemID)
+	})
+
+	t.Run("zero_size_disables_memory_cache", func(t *testing.T) {
+		next := &countingPacker{}
+		cp := NewCachingPacker(next, "b1", 0, nil)
+
+		_, _ = cp.Pack(ctx, testContainer("c1"), testItems("x"))
+		_, _ = cp.Pack(ctx, testContainer("c1"), testItems("x"))
+		assert.Equal(t, 2, next.calls)
+	})
+}
+
+func TestLRUCache_Evicts(t *testing.T) {
+	c := newLRUCache(2)
+	c.put("a", packer.PackingResult{Algorithm: "a"})
+	c.put("b", packer.PackingResult{Algorithm: "b"})
+
+	_, ok := c.get("a") // a becomes most recent
+	require.True(t, ok)
+	c.put("c", packer.PackingResult{Algorithm: "c"})
+
+	_, ok = c.get("b")
+	assert.False(t, ok)
+	_, ok = c.get("a")
+	assert.True(t, ok)
+	_, ok = c.get("c")
+	assert.True(t, ok)
+}
+
+func TestPostgresResultStore_Prunes(t *testing.T) {
+	var prunes []store.PrunePackingResultCacheParams
+	q := &mocks.MockQuerier{
+		UpsertPackingResultCacheFunc: func(ctx context.Context, arg store.UpsertPackingResultCacheParams) error {
+			return nil
+		},
+		PrunePackingResultCacheFunc: func(ctx context.Context, arg store.PrunePackingResultCacheParams) (int64, error) {
+			prunes = append(prunes, arg)
+			return 0, nil
+		},
+	}
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	s := NewPostgresResultStore(q, 24*time.Hour, 100)
+	s.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	require.NoError(t, s.Put(ctx, "k1", "b1", packer.PackingResult{}))
+	require.NoError(t, s.Put(ctx, "k2", "b1", packer.PackingResult{}))
+	require.Len(t, prunes, 1) // once per interval
+	assert.Equal(t, now.Add(-24*time.Hour), prunes[0].Before)
+	assert.Equal(t, int32(100), prunes[0].Keep)
+
+	now = now.Add(pruneInterval)
+	require.NoError(t, s.Put(ctx, "k3", "b1", packer.PackingResult{}))
+	assert.Len(t, prunes, 2)
+}
@@ -1,18 +1,32 @@
 package config
 
 import (
+	"errors"
 	"log"
 
 	"github.com/ekastn/load-stuffing-calculator/internal/env"
 	"github.com/joho/godotenv"
 )
 
+// defaultSecret is the signing secret used when none is configured. It is
+// only acceptable in development.
+const defaultSecret = "secret"
+
 type Config struct {
+	Env         string
 	Addr        string
 	DatabaseURL string
 	JWTSecret   string
 
+	BarcodeSecret       string
+	BarcodeAcceptLegacy bool
+
 	PackingServiceURL string
+	CalcWorkers       int
+	PackCacheSize     int
+	PackCachePersist  bool
+	PackCacheTTLHours int
+	PackCacheMaxRows  int
 
 	FounderUsername string
 	FounderEmail    string
@@ -20,18 +34,39 @@ type Config struct {
 }
 
 func Load() Config {
-	if env.GetString("SRV_ENV", "dev") == "dev" {
+	srvEnv := env.GetString("SRV_ENV", "dev")
+	if srvEnv == "dev" {
 		err := godotenv.Load()
 		if err != nil {
 			log.Println("Error loading .env file in development mode, continuing without it.")
 		}
 	}
+	jwtSecret := env.GetString("JWT_SECRET", defaultSecret)
+
+	// In development labels are signed with the JWT secret unless one is set
+	// for them; elsewhere BARCODE_SECRET must be set on its own. Unsigned
+	// legacy labels are only read when explicitly enabled while old labels
+	// are being replaced.
+	barcodeFallback := ""
+	if srvEnv == "dev" {
+		barcodeFallback = jwtSecret
+	}
+
 	return Config{
+		Env:         srvEnv,
 		Addr:        env.GetString("SRV_ADDR", ":8080"),
 		DatabaseURL: env.GetString("DATABASE_URL", ""),
-		JWTSecret:   env.GetString("JWT_SECRET", "secret"),
+		JWTSecret:   jwtSecret,
+
+		BarcodeSecret:       env.GetString("BARCODE_SECRET", barcodeFallback),
+		BarcodeAcceptLegacy: env.GetBool("BARCODE_ACCEPT_LEGACY", false),
 
 		PackingServiceURL: env.GetString("PACKING_SERVICE_URL", "http://localhost:5051"),
+		CalcWorkers:       env.GetInt("CALC_WORKERS", 2),
+		PackCacheSize:     env.GetInt("PACK_CACHE_SIZE", 256),
+		PackCachePersist:  env.GetBool("PACK_CACHE_PERSIST", false),
+		PackCacheTTLHours: env.GetInt("PACK_CACHE_TTL_HOURS", 24*7),
+		PackCacheMaxRows:  env.GetInt("PACK_CACHE_MAX_ROWS", 10000),
 
 		// Founder bootstrap (backwards compatible with ADMIN_*).
 		FounderUsername: env.GetString("FOUNDER_USERNAME", env.GetString("ADMIN_USERNAME", "admin")),
@@ -39,3 +74,22 @@ func Load() Config {
 		FounderPassword: env.GetString("FOUNDER_PASSWORD", env.GetString("ADMIN_PASSWORD", "admin123")),
 	}
 }
+
+// Validate reports settings the server must not start with. Outside
+// development the JWT and label secrets must be set to something other than
+// the built-in default, and to different values.
+func (c Config) Validate() error {
+	if c.Env == "dev" {
+		return nil
+	}
+	if c.JWTSecret == "" || c.JWTSecret == defaultSecret {
+		return errors.New("JWT_SECRET must be set outside development")
+	}
+	if c.BarcodeSecret == "" || c.BarcodeSecret == defaultSecret {
+		return errors.New("BARCODE_SECRET must be set outside development")
+	}
+	if c.BarcodeSecret == c.JWTSecret {
+		return errors.New("BARCODE_SECRET must differ from JWT_SECRET")
+	}
+	return nil
+}
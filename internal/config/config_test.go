@@ -349,6 +349,107 @@ func TestLoad_SpecialCharactersInValues(t *testing.T) {
 	}
 }
 
+func TestLoad_BarcodeSettings(t *testing.T) {
+	t.Run("legacy_labels_are_opt_in", func(t *testing.T) {
+		clearEnvVars(t)
+		t.Setenv("SRV_ENV", "prod")
+
+		cfg := config.Load()
+		assert.False(t, cfg.BarcodeAcceptLegacy)
+
+		t.Setenv("BARCODE_ACCEPT_LEGACY", "true")
+		cfg = config.Load()
+		assert.True(t, cfg.BarcodeAcceptLegacy)
+	})
+
+	t.Run("secret_falls_back_to_jwt_secret_in_dev", func(t *testing.T) {
+		clearEnvVars(t)
+		t.Setenv("SRV_ENV", "dev")
+		t.Setenv("JWT_SECRET", "jwt-secret")
+
+		cfg := config.Load()
+		assert.Equal(t, "jwt-secret", cfg.BarcodeSecret)
+
+		t.Setenv("BARCODE_SECRET", "label-secret")
+		cfg = config.Load()
+		assert.Equal(t, "label-secret", cfg.BarcodeSecret)
+	})
+
+	t.Run("no_fallback_outside_dev", func(t *testing.T) {
+		clearEnvVars(t)
+		t.Setenv("SRV_ENV", "prod")
+		t.Setenv("JWT_SECRET", "jwt-secret")
+
+		cfg := config.Load()
+		assert.Empty(t, cfg.BarcodeSecret)
+	})
+}
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		envVars map[string]string
+		wantErr string
+	}{
+		{
+			name:    "dev_allows_default_secret",
+			envVars: map[string]string{"SRV_ENV": "dev"},
+		},
+		{
+			name:    "prod_rejects_default_secret",
+			envVars: map[string]string{"SRV_ENV": "prod"},
+			wantErr: "JWT_SECRET",
+		},
+		{
+			name:    "prod_rejects_explicit_default_secret",
+			envVars: map[string]string{"SRV_ENV": "prod", "JWT_SECRET": "secret"},
+			wantErr: "JWT_SECRET",
+		},
+		{
+			name:    "prod_rejects_empty_secret",
+			envVars: map[string]string{"SRV_ENV": "prod", "JWT_SECRET": ""},
+			wantErr: "JWT_SECRET",
+		},
+		{
+			name:    "prod_rejects_default_barcode_secret",
+			envVars: map[string]string{"SRV_ENV": "prod", "JWT_SECRET": "jwt-secret", "BARCODE_SECRET": "secret"},
+			wantErr: "BARCODE_SECRET",
+		},
+		{
+			name:    "prod_rejects_missing_barcode_secret",
+			envVars: map[string]string{"SRV_ENV": "prod", "JWT_SECRET": "jwt-secret"},
+			wantErr: "BARCODE_SECRET",
+		},
+		{
+			name:    "prod_rejects_shared_secret",
+			envVars: map[string]string{"SRV_ENV": "prod", "JWT_SECRET": "jwt-secret", "BARCODE_SECRET": "jwt-secret"},
+			wantErr: "BARCODE_SECRET must differ",
+		},
+		{
+			name:    "prod_accepts_custom_secrets",
+			envVars: map[string]string{"SRV_ENV": "prod", "JWT_SECRET": "jwt-secret", "BARCODE_SECRET": "label-secret"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnvVars(t)
+			for key, value := range tt.envVars {
+				t.Setenv(key, value)
+			}
+
+			err := config.Load().Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), tt.wantErr)
+			}
+		})
+	}
+}
+
 // clearEnvVars clears relevant environment variables to ensure test isolation.
 func clearEnvVars(t *testing.T) {
 	t.Helper()
@@ -358,6 +459,8 @@ func clearEnvVars(t *testing.T) {
 	os.Unsetenv("SRV_ADDR")
 	os.Unsetenv("DATABASE_URL")
 	os.Unsetenv("JWT_SECRET")
+	os.Unsetenv("BARCODE_SECRET")
+	os.Unsetenv("BARCODE_ACCEPT_LEGACY")
 	os.Unsetenv("PACKING_SERVICE_URL")
 	os.Unsetenv("FOUNDER_USERNAME")
 	os.Unsetenv("FOUNDER_EMAIL")
@@ -0,0 +1,14 @@
+package dto
+
+import "time"
+
+type CalculationJobResponse struct {
+	JobID        string     `json:"job_id"`
+	PlanID       string     `json:"plan_id"`
+	Status       string     `json:"status" example:"queued"` // queued | running | completed | failed | cancelled
+	ResultID     *string    `json:"result_id,omitempty"`
+	ErrorMessage *string    `json:"error_message,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	StartedAt    *time.Time `json:"started_at,omitempty"`
+	FinishedAt   *time.Time `json:"finished_at,omitempty"`
+}
@@ -0,0 +1,59 @@
+package dto
+
+// MaxQuantityRequest asks how many units of one product, or of a product mix,
+// fit in a container. Give either product_id / product_sku or mix.
+type MaxQuantityRequest struct {
+	ProductID  *string `json:"product_id,omitempty" binding:"omitempty,uuid" example:"a1b2c3d4-..."`
+	ProductSKU *string `json:"product_sku,omitempty" binding:"omitempty,max=50" example:"TV55-001"`
+
+	Mix []MaxQuantityMixEntry `json:"mix,omitempty" binding:"omitempty,max=20,dive"`
+
+	Container     CreatePlanContainer `json:"container" binding:"required"`
+	AllowRotation *bool               `json:"allow_rotation,omitempty" example:"true"`
+
+	Strategy string `json:"strategy,omitempty" example:"bestfitdecreasing"`
+	Goal     string `json:"goal,omitempty" example:"tightest"`
+	Gravity  *bool  `json:"gravity,omitempty" example:"true"`
+}
+
+// MaxQuantityMixEntry is one product of a mix. Ratio is the number of units of
+// this product per set, e.g. 2:1 for two TVs per sound bar.
+type MaxQuantityMixEntry struct {
+	ProductID  *string `json:"product_id,omitempty" binding:"omitempty,uuid"`
+	ProductSKU *string `json:"product_sku,omitempty" binding:"omitempty,max=50"`
+	Ratio      int     `json:"ratio" binding:"required,gt=0" example:"2"`
+}
+
+type MaxQuantityResponse struct {
+	MaxQuantity    int                  `json:"max_quantity" example:"1320"` // total units
+	Sets           int                  `json:"sets" example:"660"`          // complete mix sets; equals max_quantity for one product
+	LimitingFactor string               `json:"limiting_factor" example:"space"`
+	SearchComplete bool                 `json:"search_complete"` // false when the search stopped early on its time budget
+	Iterations     int                  `json:"iterations"`
+	Algorithm      string               `json:"algorithm"`
+	Products       []MaxQuantityProduct `json:"products"`
+	Container      PlanContainerInfo    `json:"container"`
+	Stats          PlanStats            `json:"stats"`
+	Placements     []CapacityPlacement  `json:"placements"`
+}
+
+type MaxQuantityProduct struct {
+	ProductID string  `json:"product_id"`
+	SKU       *string `json:"sku,omitempty"`
+	Name      string  `json:"name"`
+	Ratio     int     `json:"ratio"`
+	Quantity  int     `json:"quantity"`
+}
+
+// CapacityPlacement is one placed unit. Dimensions are after rotation.
+type CapacityPlacement struct {
+	ProductID  string  `json:"product_id"`
+	PositionX  float64 `json:"pos_x"`
+	PositionY  float64 `json:"pos_y"`
+	PositionZ  float64 `json:"pos_z"`
+	LengthMM   float64 `json:"length_mm"`
+	WidthMM    float64 `json:"width_mm"`
+	HeightMM   float64 `json:"height_mm"`
+	Rotation   int     `json:"rotation"`
+	StepNumber int     `json:"step_number"`
+}
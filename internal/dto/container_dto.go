@@ -7,6 +7,19 @@ type CreateContainerRequest struct {
 	InnerHeightMM float64 `json:"inner_height_mm" binding:"required,gt=0"`
 	MaxWeightKG   float64 `json:"max_weight_kg" binding:"required,gt=0"`
 	Description   *string `json:"description" binding:"omitempty,max=500"`
+
+	// Lashing points spread evenly along each side wall, and the rated
+	// load of one point in daN (omit if not limiting).
+	LashingPointsPerSide    int      `json:"lashing_points_per_side" binding:"gte=0" example:"10"`
+	LashingPointCapacityDaN *float64 `json:"lashing_point_capacity_dan" binding:"omitempty,gt=0" example:"1000"`
+
+	// Highest floor pressure under a unit or stack, and highest load per
+	// running metre of length (omit if not known).
+	FloorLoadKgM2 *float64 `json:"floor_load_kg_m2" binding:"omitempty,gt=0" example:"2500"`
+	LineLoadKgM   *float64 `json:"line_load_kg_m" binding:"omitempty,gt=0" example:"4500"`
+
+	// Compartments divide the container with bulkheads; omit for one space.
+	Compartments []Compartment `json:"compartments" binding:"omitempty,dive"`
 }
 
 type UpdateContainerRequest struct {
@@ -16,6 +29,19 @@ type UpdateContainerRequest struct {
 	InnerHeightMM float64 `json:"inner_height_mm" binding:"required,gt=0"`
 	MaxWeightKG   float64 `json:"max_weight_kg" binding:"required,gt=0"`
 	Description   *string `json:"description" binding:"omitempty,max=500"`
+
+	// Lashing points spread evenly along each side wall, and the rated
+	// load of one point in daN (omit if not limiting).
+	LashingPointsPerSide    int      `json:"lashing_points_per_side" binding:"gte=0" example:"10"`
+	LashingPointCapacityDaN *float64 `json:"lashing_point_capacity_dan" binding:"omitempty,gt=0" example:"1000"`
+
+	// Highest floor pressure under a unit or stack, and highest load per
+	// running metre of length (omit if not known).
+	FloorLoadKgM2 *float64 `json:"floor_load_kg_m2" binding:"omitempty,gt=0" example:"2500"`
+	LineLoadKgM   *float64 `json:"line_load_kg_m" binding:"omitempty,gt=0" example:"4500"`
+
+	// Compartments divide the container with bulkheads; omit for one space.
+	Compartments []Compartment `json:"compartments" binding:"omitempty,dive"`
 }
 
 type ContainerResponse struct {
@@ -26,4 +52,21 @@ type ContainerResponse struct {
 	InnerHeightMM float64 `json:"inner_height_mm"`
 	MaxWeightKG   float64 `json:"max_weight_kg"`
 	Description   *string `json:"description,omitempty"`
+
+	LashingPointsPerSide    int      `json:"lashing_points_per_side"`
+	LashingPointCapacityDaN *float64 `json:"lashing_point_capacity_dan,omitempty"`
+	FloorLoadKgM2           *float64 `json:"floor_load_kg_m2,omitempty"`
+	LineLoadKgM             *float64 `json:"line_load_kg_m,omitempty"`
+
+	Compartments []Compartment `json:"compartments,omitempty"`
+}
+
+// Compartment is a section of a container between two bulkheads. It spans
+// the full width and height and runs from StartMM to StartMM+LengthMM.
+type Compartment struct {
+	Name             string   `json:"name" binding:"required,max=50" example:"Chilled"`
+	TemperatureClass string   `json:"temperature_class" binding:"required,oneof=ambient chilled frozen" example:"chilled"`
+	StartMM          float64  `json:"start_mm" binding:"gte=0" example:"0"`
+	LengthMM         float64  `json:"length_mm" binding:"required,gt=0" example:"6000"`
+	MaxWeightKG      *float64 `json:"max_weight_kg,omitempty" binding:"omitempty,gt=0" example:"10000"`
 }
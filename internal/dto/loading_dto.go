@@ -0,0 +1,99 @@
+package dto
+
+import "time"
+
+type LoadingSessionResponse struct {
+	SessionID        string     `json:"session_id"`
+	PlanID           string     `json:"plan_id"`
+	ResultID         *string    `json:"result_id,omitempty"`
+	Status           string     `json:"status" example:"active"` // active | paused | completed | abandoned
+	PlanStatus       string     `json:"plan_status,omitempty"`
+	TotalSteps       int        `json:"total_steps"`
+	LoadedSteps      int        `json:"loaded_steps"`
+	ProgressPct      float64    `json:"progress_pct"`
+	NextExpectedStep *int       `json:"next_expected_step,omitempty"`
+	StartedByID      string     `json:"started_by_id"`
+	StartedAt        time.Time  `json:"started_at"`
+	PausedAt         *time.Time `json:"paused_at,omitempty"`
+	CompletedByID    *string    `json:"completed_by_id,omitempty"`
+	CompletedAt      *time.Time `json:"completed_at,omitempty"`
+}
+
+type LoadingScanRequest struct {
+	Barcode string `json:"barcode" binding:"required"`
+}
+
+type LoadingScanDetail struct {
+	ScanID       string    `json:"scan_id"`
+	Barcode      string    `json:"barcode"`
+	Status       string    `json:"status" example:"MATCHED"` // MATCHED | OUT_OF_SEQUENCE | DUPLICATE | UNKNOWN_STEP | UNKNOWN_PRODUCT | WRONG_PLAN | INVALID_FORMAT | INVALID_SIGNATURE
+	StepNumber   *int      `json:"step_number,omitempty"`
+	ExpectedStep *int      `json:"expected_step,omitempty"`
+	OperatorID   string    `json:"operator_id"`
+	ScannedAt    time.Time `json:"scanned_at"`
+	EventID      *string   `json:"event_id,omitempty"` // set on scans synced from offline
+}
+
+// LoadingScanResponse is a recorded scan and the session progress after it.
+type LoadingScanResponse struct {
+	Scan    LoadingScanDetail      `json:"scan"`
+	Session LoadingSessionResponse `json:"session"`
+}
+
+// OfflineBundleHeader identifies what an offline bundle was built from. It is
+// signed by the server and sent back with the scans recorded offline.
+type OfflineBundleHeader struct {
+	FormatVersion int       `json:"format_version" binding:"required"`
+	PlanID        string    `json:"plan_id" binding:"required"`
+	ResultID      string    `json:"result_id" binding:"required"`
+	ResultVersion int       `json:"result_version"`
+	SessionID     string    `json:"session_id,omitempty"`
+	IssuedAt      time.Time `json:"issued_at" binding:"required"`
+	ContentDigest string    `json:"content_digest" binding:"required"` // sha256 of the JSON content
+	Signature     string    `json:"signature" binding:"required"`
+}
+
+// OfflineBundleContent is what a device needs to validate scans offline.
+type OfflineBundleContent struct {
+	PlanCode    string            `json:"plan_code"`
+	PlanStatus  string            `json:"plan_status"`
+	Container   PlanContainerInfo `json:"container"`
+	TotalSteps  int               `json:"total_steps"`
+	LoadedSteps []int             `json:"loaded_steps"`
+	Steps       []BarcodeInfo     `json:"steps"`
+}
+
+type OfflineBundle struct {
+	Header  OfflineBundleHeader  `json:"header"`
+	Content OfflineBundleContent `json:"content"`
+}
+
+type OfflineScanEvent struct {
+	EventID   string    `json:"event_id" binding:"required,uuid"`
+	Barcode   string    `json:"barcode" binding:"required"`
+	ScannedAt time.Time `json:"scanned_at" binding:"required"`
+}
+
+type OfflineSyncRequest struct {
+	Bundle OfflineBundleHeader `json:"bundle" binding:"required"`
+	Events []OfflineScanEvent  `json:"events" binding:"required,dive"`
+}
+
+type OfflineEventResult struct {
+	EventID      string `json:"event_id"`
+	Outcome      string `json:"outcome" example:"applied"` // applied | already_synced | conflict
+	ScanStatus   string `json:"scan_status,omitempty"`
+	StepNumber   *int   `json:"step_number,omitempty"`
+	ExpectedStep *int   `json:"expected_step,omitempty"`
+	Reason       string `json:"reason,omitempty"`
+}
+
+// OfflineSyncReport reconciles an upload of offline scans with the session.
+type OfflineSyncReport struct {
+	Applied       int                    `json:"applied"`
+	Matched       int                    `json:"matched"`
+	AlreadySynced int                    `json:"already_synced"`
+	Conflicts     int                    `json:"conflicts"`
+	Events        []OfflineEventResult   `json:"events"`
+	Session       LoadingSessionResponse `json:"session"`
+}
@@ -21,18 +21,55 @@ type CreatePlanContainer struct {
 	WidthMM     *float64 `json:"width_mm,omitempty" binding:"omitempty,gt=0" example:"2350"`
 	HeightMM    *float64 `json:"height_mm,omitempty" binding:"omitempty,gt=0" example:"2390"`
 	MaxWeightKG *float64 `json:"max_weight_kg,omitempty" binding:"omitempty,gt=0" example:"28200"`
+
+	// Space kept free along the walls and ceiling, and between items.
+	WallClearanceMM *float64 `json:"wall_clearance_mm,omitempty" binding:"omitempty,gte=0" example:"20"`
+	ItemGapMM       *float64 `json:"item_gap_mm,omitempty" binding:"omitempty,gte=0" example:"10"`
+
+	// Lashing points of a custom container; a preset container brings its own.
+	LashingPointsPerSide    *int     `json:"lashing_points_per_side,omitempty" binding:"omitempty,gte=0" example:"10"`
+	LashingPointCapacityDaN *float64 `json:"lashing_point_capacity_dan,omitempty" binding:"omitempty,gt=0" example:"1000"`
+
+	// Floor load limits of a custom container; a preset container brings its own.
+	FloorLoadKgM2 *float64 `json:"floor_load_kg_m2,omitempty" binding:"omitempty,gt=0" example:"2500"`
+	LineLoadKgM   *float64 `json:"line_load_kg_m,omitempty" binding:"omitempty,gt=0" example:"4500"`
+
+	// Compartments of a custom container; a preset container brings its own.
+	// Omit to keep the current ones, send an empty list to remove them.
+	Compartments []Compartment `json:"compartments,omitempty" binding:"omitempty,dive"`
 }
 
+// CreatePlanItem is one line of a plan. An item referencing a catalog product
+// by product_id or product_sku takes the dimensions and weight of the
+// product's shipping unit and the product's color and handling attributes
+// for every field it leaves out; without a product the dimensions and weight
+// are required.
 type CreatePlanItem struct {
-	ProductSKU    *string `json:"product_sku,omitempty" binding:"omitempty,max=50" example:"TV55-001"`
-	Label         *string `json:"label,omitempty" binding:"omitempty,max=100" example:"TV LED 55 inch"`
-	LengthMM      float64 `json:"length_mm" binding:"required,gt=0" example:"1300"`
-	WidthMM       float64 `json:"width_mm" binding:"required,gt=0" example:"800"`
-	HeightMM      float64 `json:"height_mm" binding:"required,gt=0" example:"200"`
-	WeightKG      float64 `json:"weight_kg" binding:"required,gt=0" example:"25.5"`
-	Quantity      int     `json:"quantity" binding:"required,gt=0" example:"120"`
-	AllowRotation *bool   `json:"allow_rotation,omitempty" binding:"-" example:"true"`
-	ColorHex      *string `json:"color_hex,omitempty" binding:"omitempty,len=7,startswith=#" example:"#ff5733"`
+	ProductID     *string  `json:"product_id,omitempty" binding:"omitempty,uuid"`
+	ProductSKU    *string  `json:"product_sku,omitempty" binding:"omitempty,max=50" example:"TV55-001"`
+	Label         *string  `json:"label,omitempty" binding:"omitempty,max=100" example:"TV LED 55 inch"`
+	LengthMM      float64  `json:"length_mm,omitempty" binding:"omitempty,gt=0" example:"1300"`
+	WidthMM       float64  `json:"width_mm,omitempty" binding:"omitempty,gt=0" example:"800"`
+	HeightMM      float64  `json:"height_mm,omitempty" binding:"omitempty,gt=0" example:"200"`
+	WeightKG      float64  `json:"weight_kg,omitempty" binding:"omitempty,gt=0" example:"25.5"`
+	Quantity      int      `json:"quantity" binding:"required,gt=0" example:"120"`
+	AllowRotation *bool    `json:"allow_rotation,omitempty" binding:"-" example:"true"`
+	ColorHex      *string  `json:"color_hex,omitempty" binding:"omitempty,len=7,startswith=#" example:"#ff5733"`
+	PaddingMM     *float64 `json:"padding_mm,omitempty" binding:"omitempty,gte=0" example:"5"`
+	Priority      int      `json:"priority,omitempty" binding:"omitempty,min=0,max=100" example:"10"` // higher packs first
+	MustShip      bool     `json:"must_ship,omitempty" example:"false"`
+	// FrictionCoefficient is µ against the container floor, used for load securing.
+	FrictionCoefficient *float64 `json:"friction_coefficient,omitempty" binding:"omitempty,gte=0,lte=2" example:"0.3"`
+	// TemperatureClass limits the item to compartments of that class.
+	TemperatureClass *string `json:"temperature_class,omitempty" binding:"omitempty,oneof=ambient chilled frozen" example:"chilled"`
+	// GTIN lets the item be loaded by scanning its product barcode.
+	GTIN *string `json:"gtin,omitempty" binding:"omitempty,numeric,min=8,max=14" example:"4006381333931"`
+	// Unit is the packaging level Quantity counts, the product's shipping
+	// unit when left out. Items of a product are converted to whole shipping
+	// units, rounding up.
+	Unit *string `json:"unit,omitempty" binding:"omitempty,oneof=each inner case pallet" example:"each"`
+
+	ProductHandling
 }
 
 type CreatePlanResponse struct {
@@ -49,19 +86,25 @@ type CreatePlanResponse struct {
 }
 
 type PlanDetailResponse struct {
-	PlanID      string             `json:"plan_id"`
-	PlanCode    string             `json:"plan_code"`
-	Title       string             `json:"title"`
-	Notes       *string            `json:"notes,omitempty"`
-	Status      string             `json:"status" example:"COMPLETED"` // DRAFT, IN_PROGRESS, COMPLETED, PARTIAL, FAILED, CANCELLED
-	Container   PlanContainerInfo  `json:"container"`
-	Stats       PlanStats          `json:"stats"`
-	Items       []PlanItemDetail   `json:"items"`
-	Calculation *CalculationResult `json:"calculation,omitempty"`
-	CreatedBy   UserSummary        `json:"created_by"`
-	CreatedAt   time.Time          `json:"created_at"`
-	UpdatedAt   time.Time          `json:"updated_at"`
-	CompletedAt *time.Time         `json:"completed_at,omitempty"`
+	PlanID       string  `json:"plan_id"`
+	ParentPlanID *string `json:"parent_plan_id,omitempty"`
+	ScenarioName *string `json:"scenario_name,omitempty"`
+	// OverflowOfPlanID is the plan whose unfit units this plan carries;
+	// ShipmentGroupID links all plans of one shipment.
+	OverflowOfPlanID *string            `json:"overflow_of_plan_id,omitempty"`
+	ShipmentGroupID  *string            `json:"shipment_group_id,omitempty"`
+	PlanCode         string             `json:"plan_code"`
+	Title            string             `json:"title"`
+	Notes            *string            `json:"notes,omitempty"`
+	Status           string             `json:"status" example:"COMPLETED"` // DRAFT, IN_PROGRESS, COMPLETED, PARTIAL, FAILED, CANCELLED
+	Container        PlanContainerInfo  `json:"container"`
+	Stats            PlanStats          `json:"stats"`
+	Items            []PlanItemDetail   `json:"items"`
+	Calculation      *CalculationResult `json:"calculation,omitempty"`
+	CreatedBy        UserSummary        `json:"created_by"`
+	CreatedAt        time.Time          `json:"created_at"`
+	UpdatedAt        time.Time          `json:"updated_at"`
+	CompletedAt      *time.Time         `json:"completed_at,omitempty"`
 }
 
 type PlanContainerInfo struct {
@@ -72,6 +115,17 @@ type PlanContainerInfo struct {
 	HeightMM    float64 `json:"height_mm"`
 	MaxWeightKG float64 `json:"max_weight_kg"`
 	VolumeM3    float64 `json:"volume_m3"`
+
+	WallClearanceMM float64 `json:"wall_clearance_mm"`
+	ItemGapMM       float64 `json:"item_gap_mm"`
+
+	LashingPointsPerSide    int      `json:"lashing_points_per_side"`
+	LashingPointCapacityDaN *float64 `json:"lashing_point_capacity_dan,omitempty"`
+
+	FloorLoadKgM2 *float64 `json:"floor_load_kg_m2,omitempty"`
+	LineLoadKgM   *float64 `json:"line_load_kg_m,omitempty"`
+
+	Compartments []Compartment `json:"compartments,omitempty"`
 }
 
 type PlanStats struct {
@@ -80,6 +134,32 @@ type PlanStats struct {
 	TotalVolumeM3        float64 `json:"total_volume_m3"`
 	VolumeUtilizationPct float64 `json:"volume_utilization_pct"`
 	WeightUtilizationPct float64 `json:"weight_utilization_pct"`
+
+	// Compartments is the load of each compartment once the plan is calculated.
+	Compartments []CompartmentStats `json:"compartments,omitempty"`
+	// SKUs totals the items of each product SKU, with the units placed once
+	// the plan is calculated.
+	SKUs []SKUStats `json:"skus,omitempty"`
+}
+
+// SKUStats is the share of one product SKU in a plan.
+type SKUStats struct {
+	SKU           string  `json:"sku"`
+	Quantity      int     `json:"quantity"`
+	PlacedUnits   int     `json:"placed_units"`
+	TotalWeightKG float64 `json:"total_weight_kg"`
+	TotalVolumeM3 float64 `json:"total_volume_m3"`
+}
+
+// CompartmentStats is the load of one compartment.
+type CompartmentStats struct {
+	Name                 string  `json:"name"`
+	TemperatureClass     string  `json:"temperature_class"`
+	TotalItems           int     `json:"total_items"`
+	TotalWeightKG        float64 `json:"total_weight_kg"`
+	TotalVolumeM3        float64 `json:"total_volume_m3"`
+	VolumeUtilizationPct float64 `json:"volume_utilization_pct"`
+	WeightUtilizationPct float64 `json:"weight_utilization_pct"` // 0 without a compartment limit
 }
 
 type PlanItemDetail struct {
@@ -96,19 +176,185 @@ type PlanItemDetail struct {
 	AllowRotation bool    `json:"allow_rotation"`
 	StackingLimit int     `json:"stacking_limit"`
 	ColorHex      *string `json:"color_hex,omitempty"`
+	PaddingMM     float64 `json:"padding_mm"`
+	Priority      int     `json:"priority"`
+	MustShip      bool    `json:"must_ship"`
 	CreatedAt     string  `json:"created_at"`
+
+	FrictionCoefficient *float64 `json:"friction_coefficient,omitempty"`
+	TemperatureClass    *string  `json:"temperature_class,omitempty"`
+	GTIN                *string  `json:"gtin,omitempty"`
+
+	// ProductID is the catalog product the item was filled from.
+	// ProductOverridden is set when the item's values differ from it.
+	ProductID         *string `json:"product_id,omitempty"`
+	ProductOverridden bool    `json:"product_overridden"`
+
+	HandlingAttributes
+
+	// Unit is the packaging level of one unit of the item and EachesPerUnit
+	// the eaches it holds. OrderedEaches is the quantity the line was
+	// entered with, before rounding up to whole units.
+	Unit          *string `json:"unit,omitempty"`
+	EachesPerUnit *int    `json:"eaches_per_unit,omitempty"`
+	OrderedEaches *int    `json:"ordered_eaches,omitempty"`
 }
 
 type CalculationResult struct {
 	JobID             string            `json:"job_id"`
-	Status            string            `json:"status"` // queued | running | completed | failed
+	Version           int               `json:"version,omitempty"` // result version the calculation was saved as
+	Status            string            `json:"status"`            // queued | running | completed | failed
 	Algorithm         string            `json:"algorithm" example:"maxrects-bssf"`
 	CalculatedAt      *string           `json:"calculated_at,omitempty"`
 	DurationMs        int64             `json:"duration_ms,omitempty"`
 	EfficiencyScore   float64           `json:"efficiency_score,omitempty"`
 	VolumeUtilization float64           `json:"volume_utilization_pct,omitempty"`
+	WeightUtilization float64           `json:"weight_utilization_pct,omitempty"`
 	VisualizationURL  string            `json:"visualization_url" example:"/visualizer?plan=f47ac10b-..."`
+	CacheHit          bool              `json:"cache_hit"` // result was served from the packing cache
 	Placements        []PlacementDetail `json:"placements,omitempty"`
+	VoidAnalysis      *VoidAnalysis     `json:"void_analysis,omitempty"` // set on fresh calculations
+	UnfitItems        []UnfitItemInfo   `json:"unfit_items,omitempty"`   // set on fresh calculations
+	Securing          *SecuringPlan     `json:"securing,omitempty"`
+	FloorLoad         *FloorLoadReport  `json:"floor_load,omitempty"`
+	Handling          *HandlingReport   `json:"handling,omitempty"`
+}
+
+// HandlingReport checks the placed units against their handling attributes.
+type HandlingReport struct {
+	Violations    []HandlingViolation `json:"violations"`
+	HazmatClasses []string            `json:"hazmat_classes,omitempty"`
+	OK            bool                `json:"ok"`
+}
+
+// HandlingViolation is a placed unit loaded against its handling attributes.
+type HandlingViolation struct {
+	ItemID    string   `json:"item_id"`
+	Label     string   `json:"label,omitempty"`
+	PositionX float64  `json:"pos_x"`
+	PositionY float64  `json:"pos_y"`
+	PositionZ float64  `json:"pos_z"`
+	Rule      string   `json:"rule"` // this_side_up | no_stack | fragile | max_stack_load
+	LoadKG    *float64 `json:"load_kg,omitempty"`
+	LimitKG   *float64 `json:"limit_kg,omitempty"`
+}
+
+// FloorLoadReport checks the load the cargo puts on the container floor.
+type FloorLoadReport struct {
+	MaxPressureKgM2 float64              `json:"max_pressure_kg_m2"`
+	MaxLineLoadKgM  float64              `json:"max_line_load_kg_m"`
+	LimitKgM2       *float64             `json:"floor_load_limit_kg_m2,omitempty"`
+	LimitKgM        *float64             `json:"line_load_limit_kg_m,omitempty"`
+	Violations      []FloorLoadViolation `json:"violations"`
+	LineViolations  []LineLoadViolation  `json:"line_violations"`
+	OK              bool                 `json:"ok"`
+}
+
+// FloorLoadViolation is a floor-standing unit or stack pressing on the
+// floor harder than the container allows.
+type FloorLoadViolation struct {
+	ItemID       string  `json:"item_id"`
+	Label        string  `json:"label,omitempty"`
+	PositionX    float64 `json:"pos_x"`
+	PositionY    float64 `json:"pos_y"`
+	LengthMM     float64 `json:"length_mm"`
+	WidthMM      float64 `json:"width_mm"`
+	LoadKG       float64 `json:"load_kg"` // the unit plus everything stacked on it
+	PressureKgM2 float64 `json:"pressure_kg_m2"`
+
+	// Bearing area (e.g. a steel plate or timber bed) that spreads the load
+	// within the limit.
+	RequiredAreaM2      float64 `json:"required_bearing_area_m2"`
+	RecommendedLengthMM float64 `json:"recommended_length_mm"`
+	RecommendedWidthMM  float64 `json:"recommended_width_mm"`
+}
+
+// LineLoadViolation is a metre of container length loaded beyond the limit.
+type LineLoadViolation struct {
+	StartMM         float64 `json:"start_mm"`
+	EndMM           float64 `json:"end_mm"`
+	LoadKG          float64 `json:"load_kg"`
+	RequiredLengthM float64 `json:"required_length_m"` // length to spread the load over
+}
+
+// SecuringPlan estimates the lashing a calculated load needs under
+// EN 12195-1:2010 for road transport. Blocking by walls and neighbouring
+// cargo is not credited.
+type SecuringPlan struct {
+	Standard      string  `json:"standard" example:"EN 12195-1:2010"`
+	Forward       float64 `json:"forward_coefficient" example:"0.8"`
+	Backward      float64 `json:"backward_coefficient" example:"0.5"`
+	Sideways      float64 `json:"sideways_coefficient" example:"0.5"`
+	StrapLCDaN    float64 `json:"strap_lc_dan" example:"2500"` // lashing capacity of one strap
+	StrapSTFDaN   float64 `json:"strap_stf_dan" example:"400"` // standard tension force of one strap
+	LashingPoints int     `json:"lashing_points_per_side"`
+
+	Blocks         []SecuringBlock `json:"blocks"`
+	TotalStraps    int             `json:"total_straps"`
+	TopOverStraps  int             `json:"top_over_straps"`
+	DirectLashings int             `json:"direct_lashings"`
+	Sufficient     bool            `json:"sufficient"` // every block can be lashed with the available points
+	Warnings       []string        `json:"warnings,omitempty"`
+}
+
+// SecuringBlock is a group of touching units of one item secured together.
+type SecuringBlock struct {
+	ItemID       string  `json:"item_id"`
+	Label        string  `json:"label,omitempty"`
+	Units        int     `json:"units"`
+	WeightKG     float64 `json:"weight_kg"`
+	PositionX    float64 `json:"pos_x"`
+	PositionY    float64 `json:"pos_y"`
+	PositionZ    float64 `json:"pos_z"`
+	LengthMM     float64 `json:"length_mm"`
+	WidthMM      float64 `json:"width_mm"`
+	HeightMM     float64 `json:"height_mm"`
+	Friction     float64 `json:"friction_coefficient"`
+	AngleDeg     float64 `json:"angle_deg"`     // vertical angle of the straps
+	RestraintDaN float64 `json:"restraint_dan"` // force friction does not take up in the worst direction
+	TippingRisk  bool    `json:"tipping_risk"`
+
+	TopOverStraps  int `json:"top_over_straps"`
+	DirectLashings int `json:"direct_lashings"`
+
+	Method        string `json:"method" example:"top_over"` // none | top_over | direct
+	Straps        int    `json:"straps"`
+	LashingPoints int    `json:"lashing_points"` // per side within reach of the block
+	Sufficient    bool   `json:"sufficient"`
+	Note          string `json:"note,omitempty"`
+}
+
+// VoidAnalysis describes the empty space left by a calculation and the
+// dunnage needed to stop the load shifting into it.
+type VoidAnalysis struct {
+	ThresholdMM      float64                 `json:"threshold_mm" example:"50"`
+	EmptyVolumeM3    float64                 `json:"empty_volume_m3"`
+	Voids            []VoidDetail            `json:"voids"`
+	Dunnage          []DunnageRecommendation `json:"dunnage"` // totals per dunnage type
+	TotalDunnageCost float64                 `json:"total_dunnage_cost"`
+	Currency         string                  `json:"currency" example:"USD"`
+}
+
+// VoidDetail is one maximal empty cuboid. Flagged voids are gaps at least
+// ThresholdMM wide that the load could shift into.
+type VoidDetail struct {
+	PositionX float64                `json:"pos_x"`
+	PositionY float64                `json:"pos_y"`
+	PositionZ float64                `json:"pos_z"`
+	LengthMM  float64                `json:"length_mm"`
+	WidthMM   float64                `json:"width_mm"`
+	HeightMM  float64                `json:"height_mm"`
+	VolumeM3  float64                `json:"volume_m3"`
+	Flagged   bool                   `json:"flagged"`
+	ShiftAxis string                 `json:"shift_axis,omitempty" example:"length"`
+	GapMM     float64                `json:"gap_mm,omitempty"`
+	Dunnage   *DunnageRecommendation `json:"dunnage,omitempty"`
+}
+
+type DunnageRecommendation struct {
+	Type          string  `json:"type" example:"airbag"` // airbag | filler
+	Quantity      int     `json:"quantity"`
+	EstimatedCost float64 `json:"estimated_cost"`
 }
 
 type PlacementDetail struct {
@@ -134,8 +380,21 @@ type PlanListItem struct {
 }
 
 type UpdatePlanRequest struct {
-	Status    *string              `json:"status,omitempty" binding:"omitempty,oneof=DRAFT IN_PROGRESS COMPLETED PARTIAL FAILED CANCELLED"`
+	Status    *string              `json:"status,omitempty" binding:"omitempty,oneof=DRAFT PLANNED IN_PROGRESS COMPLETED PARTIAL FAILED CANCELLED"`
 	Container *CreatePlanContainer `json:"container,omitempty"`
+
+	// StatusReason is kept in the status history with the change.
+	StatusReason *string `json:"status_reason,omitempty" binding:"omitempty,max=500"`
+}
+
+// PlanStatusChange is one entry of a plan's status history.
+type PlanStatusChange struct {
+	FromStatus    *string `json:"from_status,omitempty"`
+	ToStatus      string  `json:"to_status"`
+	Reason        *string `json:"reason,omitempty"`
+	ChangedByType string  `json:"changed_by_type"` // user, guest or system
+	ChangedByID   *string `json:"changed_by_id,omitempty"`
+	ChangedAt     string  `json:"changed_at"`
 }
 
 type AddPlanItemRequest struct {
@@ -151,18 +410,101 @@ type UpdatePlanItemRequest struct {
 	Quantity      *int     `json:"quantity,omitempty" binding:"omitempty,gt=0"`
 	AllowRotation *bool    `json:"allow_rotation,omitempty"`
 	ColorHex      *string  `json:"color_hex,omitempty" binding:"omitempty,len=7,startswith=#"`
+	PaddingMM     *float64 `json:"padding_mm,omitempty" binding:"omitempty,gte=0"`
+	Priority      *int     `json:"priority,omitempty" binding:"omitempty,min=0,max=100"`
+	MustShip      *bool    `json:"must_ship,omitempty"`
+
+	FrictionCoefficient *float64 `json:"friction_coefficient,omitempty" binding:"omitempty,gte=0,lte=2"`
+	TemperatureClass    *string  `json:"temperature_class,omitempty" binding:"omitempty,oneof=ambient chilled frozen"`
+	GTIN                *string  `json:"gtin,omitempty" binding:"omitempty,numeric,min=8,max=14"` // empty clears
+
+	ProductHandling
 }
 
 type CalculatePlanRequest struct {
-	Strategy string `json:"strategy" binding:"omitempty" example:"bestfitdecreasing"`
-	Goal     string `json:"goal" binding:"omitempty" example:"tightest"`
-	Gravity  *bool  `json:"gravity" binding:"omitempty" example:"true"`
+	Backend  string `json:"backend,omitempty" form:"backend" binding:"omitempty" example:"native"`
+	Strategy string `json:"strategy" form:"strategy" binding:"omitempty" example:"bestfitdecreasing"`
+	Goal     string `json:"goal" form:"goal" binding:"omitempty" example:"tightest"`
+	Gravity  *bool  `json:"gravity" form:"gravity" binding:"omitempty" example:"true"`
+
+	// Narrowest gap flagged for dunnage; defaults to 50 mm.
+	VoidThresholdMM *float64 `json:"void_threshold_mm,omitempty" form:"void_threshold_mm" binding:"omitempty,gt=0" example:"50"`
+
+	// Pack must-ship items first and drop low-priority units first. Defaults
+	// to on when any item has a priority or is must-ship.
+	Prioritize *bool `json:"prioritize,omitempty" form:"prioritize" binding:"omitempty" example:"true"`
+}
+
+// PreflightResponse lists problems found in a plan without packing it.
+type PreflightResponse struct {
+	PlanID            string           `json:"plan_id"`
+	OK                bool             `json:"ok"` // no issues and within weight and volume
+	TotalWeightKG     float64          `json:"total_weight_kg"`
+	MaxWeightKG       float64          `json:"max_weight_kg"`
+	TotalVolumeM3     float64          `json:"total_volume_m3"`
+	ContainerVolumeM3 float64          `json:"container_volume_m3"`
+	Overweight        bool             `json:"overweight"`
+	OverVolume        bool             `json:"over_volume"`
+	Issues            []PreflightIssue `json:"issues"`
+}
+
+// PreflightIssue is an item no packing run can place.
+type PreflightIssue struct {
+	ItemID   string  `json:"item_id"`
+	Label    *string `json:"label,omitempty"`
+	Quantity int     `json:"quantity"`
+	Code     string  `json:"code" example:"too_large"` // too_large | orientation | overweight
+	Message  string  `json:"message"`
+}
+
+type ComparePlanRequest struct {
+	Candidates []CalculatePlanRequest `json:"candidates" binding:"required,min=1,max=12,dive"`
+}
+
+type ComparePlanResponse struct {
+	PlanID       string            `json:"plan_id"`
+	Alternatives []PlanAlternative `json:"alternatives"` // best first
+}
+
+// PlanAlternative is one candidate of a comparison. Posting to
+// /plans/{id}/alternatives/{alternative_id}/adopt saves it as the plan's
+// result exactly as compared, for 30 minutes after the comparison.
+type PlanAlternative struct {
+	Rank              int                  `json:"rank"`
+	AlternativeID     string               `json:"alternative_id,omitempty"` // set when the candidate packed
+	Options           CalculatePlanRequest `json:"options"`
+	Algorithm         string               `json:"algorithm,omitempty"`
+	Error             string               `json:"error,omitempty"`
+	IsFeasible        bool                 `json:"is_feasible"`
+	PackedCount       int                  `json:"packed_count"`
+	UnfitCount        int                  `json:"unfit_count"`
+	VolumeUtilization float64              `json:"volume_utilization_pct"`
+	WeightUtilization float64              `json:"weight_utilization_pct"`
+	CogOffsetPct      float64              `json:"cog_offset_pct"` // horizontal offset from the floor centre
+	StabilityPct      float64              `json:"stability_pct"`  // average supported base area
+	DurationMs        int64                `json:"duration_ms"`
+	CacheHit          bool                 `json:"cache_hit"`
+	Placements        []PlacementDetail    `json:"placements,omitempty"`
+}
+
+// CalculationProgress is streamed as an SSE "progress" event while a plan is
+// calculated. "placing" events replay the final layout once packing is done.
+type CalculationProgress struct {
+	Stage                 string            `json:"stage" example:"placing"` // started | candidate | placing
+	ItemsTotal            int               `json:"items_total"`
+	ItemsPlaced           int               `json:"items_placed"`
+	VolumeUtilization     float64           `json:"volume_utilization_pct"`
+	BestAlgorithm         string            `json:"best_algorithm,omitempty"`
+	BestVolumeUtilization float64           `json:"best_volume_utilization_pct,omitempty"`
+	Placements            []PlacementDetail `json:"placements,omitempty"` // added since the previous event
 }
 
 type BarcodeInfo struct {
 	StepNumber int        `json:"step_number"`
 	ItemID     string     `json:"item_id"`
 	ItemLabel  string     `json:"item_label"`
+	SKU        string     `json:"sku,omitempty"`
+	GTIN       string     `json:"gtin,omitempty"`
 	Barcode    string     `json:"barcode"`
 	Position   Position   `json:"position"`
 	Dimensions Dimensions `json:"dimensions"`
@@ -187,10 +529,83 @@ type ValidateBarcodeRequest struct {
 
 type ValidationResult struct {
 	Valid      bool   `json:"valid"`
-	Status     string `json:"status"` // MATCHED, OUT_OF_SEQUENCE, WRONG_PLAN, INVALID_FORMAT
+	Status     string `json:"status"` // MATCHED, OUT_OF_SEQUENCE, WRONG_PLAN, INVALID_FORMAT, INVALID_SIGNATURE
 	PlanID     string `json:"plan_id,omitempty"`
 	StepNumber int    `json:"step_number,omitempty"`
 	ItemID     string `json:"item_id,omitempty"`
 	Barcode    string `json:"barcode,omitempty"`
 	Error      string `json:"error,omitempty"`
 }
+
+// HandlingUnit is a placed unit of the plan's active result identified by an
+// SSCC for GS1-128 labels and the customer's WMS.
+type HandlingUnit struct {
+	SSCC          string    `json:"sscc" example:"006141410000000018"`
+	ElementString string    `json:"element_string" example:"(00)006141410000000018"`
+	StepNumber    int       `json:"step_number"`
+	PlacementID   string    `json:"placement_id"`
+	ItemID        string    `json:"item_id"`
+	ItemLabel     string    `json:"item_label"`
+	SKU           string    `json:"sku,omitempty"`
+	GTIN          string    `json:"gtin,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// ResultInputs is what a result version was calculated from.
+type ResultInputs struct {
+	Container PlanContainerInfo    `json:"container"`
+	Items     []PlanItemDetail     `json:"items"`
+	Options   CalculatePlanRequest `json:"options"`
+	Backend   string               `json:"backend"`
+}
+
+// ResultVersion is one saved calculation of a plan. Inputs and Placements are
+// only set when a single version is fetched.
+type ResultVersion struct {
+	ResultID          string            `json:"result_id"`
+	Version           int               `json:"version"`
+	IsActive          bool              `json:"is_active"`
+	Algorithm         string            `json:"algorithm"`
+	IsFeasible        bool              `json:"is_feasible"`
+	VolumeUtilization float64           `json:"volume_utilization_pct"`
+	LoadedWeightKG    float64           `json:"loaded_weight_kg"`
+	CreatedAt         string            `json:"created_at"`
+	Inputs            *ResultInputs     `json:"inputs,omitempty"`
+	Placements        []PlacementDetail `json:"placements,omitempty"`
+}
+
+// ResultItemDiff is an item whose definition or packed count differs between
+// two result versions.
+type ResultItemDiff struct {
+	ItemID       string `json:"item_id"`
+	Label        string `json:"label,omitempty"`
+	Change       string `json:"change"` // added | removed | changed
+	FromQuantity int    `json:"from_quantity"`
+	ToQuantity   int    `json:"to_quantity"`
+	FromPacked   int    `json:"from_packed"`
+	ToPacked     int    `json:"to_packed"`
+}
+
+// ResultVersionDiff compares result version To against version From.
+type ResultVersionDiff struct {
+	FromVersion int `json:"from_version"`
+	ToVersion   int `json:"to_version"`
+
+	FromAlgorithm string `json:"from_algorithm"`
+	ToAlgorithm   string `json:"to_algorithm"`
+	FromFeasible  bool   `json:"from_feasible"`
+	ToFeasible    bool   `json:"to_feasible"`
+
+	VolumeUtilizationDelta float64 `json:"volume_utilization_delta_pct"`
+	LoadedWeightDeltaKG    float64 `json:"loaded_weight_delta_kg"`
+
+	ContainerChanged bool `json:"container_changed"`
+	OptionsChanged   bool `json:"options_changed"` // options or backend
+
+	Items []ResultItemDiff `json:"items"`
+
+	// UnitsUnchanged counts units of To placed exactly as in From;
+	// UnitsMoved counts the rest.
+	UnitsUnchanged int `json:"units_unchanged"`
+	UnitsMoved     int `json:"units_moved"`
+}
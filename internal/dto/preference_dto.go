@@ -0,0 +1,12 @@
+package dto
+
+type UpdatePreferencesRequest struct {
+	Units string `json:"units" binding:"required,max=16" example:"imperial"` // metric | imperial | "<length>,<weight>"
+}
+
+type PreferencesResponse struct {
+	Units      string `json:"units" example:"in,lb"`
+	LengthUnit string `json:"length_unit" example:"in"`
+	WeightUnit string `json:"weight_unit" example:"lb"`
+	VolumeUnit string `json:"volume_unit" example:"ft3"`
+}
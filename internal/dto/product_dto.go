@@ -1,5 +1,45 @@
 package dto
 
+// ProductHandling are the handling attributes of a product, copied onto the
+// plan items filled from it. Left out, a unit is neither fragile nor
+// this-side-up and is stackable without a load limit.
+type ProductHandling struct {
+	Fragile    *bool `json:"fragile,omitempty" example:"false"`
+	ThisSideUp *bool `json:"this_side_up,omitempty" example:"true"`
+	Stackable  *bool `json:"stackable,omitempty" example:"true"`
+	// MaxStackLoadKG is the weight a unit may carry on top.
+	MaxStackLoadKG *float64 `json:"max_stack_load_kg,omitempty" binding:"omitempty,gte=0" example:"200"`
+	// HazmatClass is the UN dangerous goods class or division.
+	HazmatClass   *string `json:"hazmat_class,omitempty" binding:"omitempty,oneof=1 1.1 1.2 1.3 1.4 1.5 1.6 2.1 2.2 2.3 3 4.1 4.2 4.3 5.1 5.2 6.1 6.2 7 8 9" example:"3"`
+	PackagingType *string `json:"packaging_type,omitempty" binding:"omitempty,oneof=box carton case crate drum bag sack pallet roll bundle other" example:"carton"`
+}
+
+// HandlingAttributes are the handling attributes of a product or plan item
+// as returned by the API.
+type HandlingAttributes struct {
+	Fragile        bool     `json:"fragile"`
+	ThisSideUp     bool     `json:"this_side_up"`
+	Stackable      bool     `json:"stackable"`
+	MaxStackLoadKG *float64 `json:"max_stack_load_kg,omitempty"`
+	HazmatClass    *string  `json:"hazmat_class,omitempty"`
+	PackagingType  *string  `json:"packaging_type,omitempty"`
+}
+
+// PackagingLevel is a level of a product's packaging hierarchy above the
+// each, whose dimensions are the product's own. Contains counts the units of
+// the next lower level in the hierarchy, e.g. inners in a case.
+type PackagingLevel struct {
+	Level    string  `json:"level" binding:"required,oneof=inner case pallet" example:"case"`
+	Contains int     `json:"contains" binding:"required,gt=0" example:"4"`
+	LengthMM float64 `json:"length_mm" binding:"required,gt=0" example:"600"`
+	WidthMM  float64 `json:"width_mm" binding:"required,gt=0" example:"400"`
+	HeightMM float64 `json:"height_mm" binding:"required,gt=0" example:"300"`
+	WeightKG float64 `json:"weight_kg" binding:"required,gt=0" example:"12.5"`
+	// EachQuantity is the number of eaches in one unit of the level; it is
+	// worked out from the hierarchy.
+	EachQuantity int `json:"each_quantity" binding:"-"`
+}
+
 type CreateProductRequest struct {
 	Name     string  `json:"name" binding:"required,min=2,max=150"`
 	SKU      *string `json:"sku"`
@@ -8,8 +48,23 @@ type CreateProductRequest struct {
 	HeightMM float64 `json:"height_mm" binding:"required,gt=0"`
 	WeightKG float64 `json:"weight_kg" binding:"required,gt=0"`
 	ColorHex *string `json:"color_hex" binding:"omitempty,hexcolor"`
+	// FrictionCoefficient is µ against the container floor, used for load securing.
+	FrictionCoefficient *float64 `json:"friction_coefficient" binding:"omitempty,gte=0,lte=2" example:"0.3"`
+	// TemperatureClass is the compartment class the product travels in.
+	TemperatureClass *string `json:"temperature_class" binding:"omitempty,oneof=ambient chilled frozen" example:"chilled"`
+	// GTIN is the product's EAN/UPC/GTIN-14; its check digit is verified.
+	GTIN *string `json:"gtin" binding:"omitempty,numeric,min=8,max=14" example:"4006381333931"`
+
+	ProductHandling
+
+	// Packaging is the hierarchy above the each; ShippingUnit is the level
+	// plan items are converted to before packing, each when left out.
+	Packaging    []PackagingLevel `json:"packaging,omitempty" binding:"omitempty,dive"`
+	ShippingUnit *string          `json:"shipping_unit,omitempty" binding:"omitempty,oneof=each inner case pallet" example:"case"`
 }
 
+// UpdateProductRequest replaces a product's core fields. Catalog attributes
+// left out keep their stored values.
 type UpdateProductRequest struct {
 	Name     string  `json:"name" binding:"required,min=2,max=150"`
 	SKU      *string `json:"sku"`
@@ -18,6 +73,20 @@ type UpdateProductRequest struct {
 	HeightMM float64 `json:"height_mm" binding:"required,gt=0"`
 	WeightKG float64 `json:"weight_kg" binding:"required,gt=0"`
 	ColorHex *string `json:"color_hex" binding:"omitempty,hexcolor"`
+	// FrictionCoefficient is µ against the container floor, used for load securing.
+	FrictionCoefficient *float64 `json:"friction_coefficient" binding:"omitempty,gte=0,lte=2" example:"0.3"`
+	// TemperatureClass is the compartment class the product travels in.
+	TemperatureClass *string `json:"temperature_class" binding:"omitempty,oneof=ambient chilled frozen" example:"chilled"`
+	// GTIN is the product's EAN/UPC/GTIN-14; its check digit is verified.
+	GTIN *string `json:"gtin" binding:"omitempty,numeric,min=8,max=14" example:"4006381333931"`
+
+	ProductHandling
+
+	// Packaging is the hierarchy above the each; ShippingUnit is the level
+	// plan items are converted to before packing. Both keep their stored
+	// values when left out; an empty list removes the hierarchy.
+	Packaging    []PackagingLevel `json:"packaging,omitempty" binding:"omitempty,dive"`
+	ShippingUnit *string          `json:"shipping_unit,omitempty" binding:"omitempty,oneof=each inner case pallet" example:"case"`
 }
 
 type ProductResponse struct {
@@ -29,4 +98,13 @@ type ProductResponse struct {
 	HeightMM float64 `json:"height_mm"`
 	WeightKG float64 `json:"weight_kg"`
 	ColorHex *string `json:"color_hex,omitempty"`
+
+	FrictionCoefficient *float64 `json:"friction_coefficient,omitempty"`
+	TemperatureClass    *string  `json:"temperature_class,omitempty"`
+	GTIN                *string  `json:"gtin,omitempty"` // 14 digits
+
+	HandlingAttributes
+
+	Packaging    []PackagingLevel `json:"packaging,omitempty"`
+	ShippingUnit string           `json:"shipping_unit"`
 }
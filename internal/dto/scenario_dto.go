@@ -0,0 +1,53 @@
+package dto
+
+import "time"
+
+type CreateScenarioRequest struct {
+	Name string `json:"name" binding:"required,max=100" example:"20GP without optional SKUs"`
+
+	// Container replaces the parent's container; omit to keep it.
+	Container *CreatePlanContainer `json:"container,omitempty"`
+
+	// Item overrides applied while copying the parent's items.
+	ExcludeItemIDs    []string       `json:"exclude_item_ids,omitempty" binding:"omitempty,dive,uuid"`
+	QuantityOverrides map[string]int `json:"quantity_overrides,omitempty" binding:"omitempty,dive,keys,uuid,endkeys,gt=0"`
+}
+
+type ScenarioResponse struct {
+	PlanID       string    `json:"plan_id"`
+	ParentPlanID string    `json:"parent_plan_id"`
+	Name         string    `json:"name"`
+	PlanCode     string    `json:"plan_code"`
+	Status       string    `json:"status" example:"DRAFT"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type ScenarioComparisonResponse struct {
+	BasePlanID string             `json:"base_plan_id"`
+	Scenarios  []ScenarioSnapshot `json:"scenarios"` // the base plan first, then scenarios oldest first
+}
+
+type ScenarioSnapshot struct {
+	PlanID      string            `json:"plan_id"`
+	Name        string            `json:"name" example:"40HC with all items"`
+	IsBase      bool              `json:"is_base"`
+	Status      string            `json:"status" example:"COMPLETED"`
+	Calculated  bool              `json:"calculated"`
+	Container   PlanContainerInfo `json:"container"`
+	Stats       PlanStats         `json:"stats"`
+	PackedItems int               `json:"packed_items"`
+	UnfitItems  []UnfitItemInfo   `json:"unfit_items"`
+}
+
+type UnfitItemInfo struct {
+	ItemID   string  `json:"item_id"`
+	Label    *string `json:"label,omitempty"`
+	Quantity int     `json:"quantity"`
+	MustShip bool    `json:"must_ship,omitempty"`
+	Reason   string  `json:"reason,omitempty" example:"weight"` // volume | weight | geometry
+
+	// Diagnosis is one of too_large, overweight, no_space, orientation,
+	// stacking or unplaced; Message explains it.
+	Diagnosis string `json:"diagnosis,omitempty" example:"no_space"`
+	Message   string `json:"message,omitempty"`
+}
@@ -0,0 +1,48 @@
+package dto
+
+// CreateOverflowPlanRequest moves the unfit units of a calculated plan into
+// a new plan of the same shipment.
+type CreateOverflowPlanRequest struct {
+	// Container for the overflow plan. Defaults to the source plan's container.
+	Container *CreatePlanContainer `json:"container,omitempty"`
+	// SuggestContainer picks the smallest saved container that holds every
+	// leftover unit by size, weight and volume. Ignored when Container is set.
+	SuggestContainer bool `json:"suggest_container,omitempty" example:"true"`
+	// AutoCalculate calculates the new plan right away. Defaults to true.
+	AutoCalculate *bool                 `json:"auto_calculate,omitempty" example:"true"`
+	Options       *CalculatePlanRequest `json:"options,omitempty"`
+}
+
+type OverflowPlanResponse struct {
+	PlanID             string             `json:"plan_id"`
+	PlanCode           string             `json:"plan_code" example:"PLD-20251209-104500-2"`
+	Status             string             `json:"status" example:"COMPLETED"`
+	OverflowOfPlanID   string             `json:"overflow_of_plan_id"`
+	ShipmentGroupID    string             `json:"shipment_group_id"`
+	Container          PlanContainerInfo  `json:"container"`
+	ContainerSuggested bool               `json:"container_suggested"`
+	Items              []UnfitItemInfo    `json:"items"` // units moved from the source plan
+	TotalItems         int                `json:"total_items"`
+	Calculation        *CalculationResult `json:"calculation,omitempty"`
+}
+
+// ShipmentGroupResponse lists every plan of a shipment, the original plan first.
+type ShipmentGroupResponse struct {
+	ShipmentGroupID  string         `json:"shipment_group_id"`
+	Plans            []ShipmentPlan `json:"plans"`
+	TotalItems       int            `json:"total_items"`       // units ordered in the original plan
+	PackedItems      int            `json:"packed_items"`      // units placed across all plans
+	OutstandingUnits int            `json:"outstanding_units"` // units not placed in any plan and not moved on to an overflow plan
+}
+
+type ShipmentPlan struct {
+	PlanID           string            `json:"plan_id"`
+	PlanCode         string            `json:"plan_code"`
+	OverflowOfPlanID *string           `json:"overflow_of_plan_id,omitempty"`
+	Status           string            `json:"status" example:"PARTIAL"`
+	Calculated       bool              `json:"calculated"`
+	Container        PlanContainerInfo `json:"container"`
+	Stats            PlanStats         `json:"stats"`
+	PackedItems      int               `json:"packed_items"`
+	UnfitItems       []UnfitItemInfo   `json:"unfit_items"`
+}
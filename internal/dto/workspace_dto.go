@@ -26,3 +26,18 @@ type UpdateWorkspaceRequest struct {
 	Name        *string `json:"name,omitempty" binding:"omitempty,max=150"`
 	OwnerUserID *string `json:"owner_user_id,omitempty" binding:"omitempty,uuid"`
 }
+
+// GS1SettingsResponse is the GS1 numbering a workspace issues SSCCs with.
+type GS1SettingsResponse struct {
+	WorkspaceID    string    `json:"workspace_id"`
+	CompanyPrefix  string    `json:"company_prefix" example:"0614141"`
+	ExtensionDigit int       `json:"extension_digit"`
+	LastSerial     int64     `json:"last_serial"`
+	SerialsLeft    int64     `json:"serials_left"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type UpdateGS1SettingsRequest struct {
+	CompanyPrefix  string `json:"company_prefix" binding:"required,numeric,min=6,max=12" example:"0614141"`
+	ExtensionDigit *int   `json:"extension_digit,omitempty" binding:"omitempty,min=0,max=9"`
+}
@@ -0,0 +1,135 @@
+// Package gs1 validates and builds GS1 identifiers: GTINs of trade items and
+// SSCCs of handling units, and reads them from scanned element strings.
+package gs1
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Application identifiers used by the planner.
+const (
+	AISSCC = "00"
+	AIGTIN = "01"
+)
+
+// fnc1 is how scanners transmit the FNC1 separator of GS1-128 and GS1
+// DataMatrix codes.
+const fnc1 = "\x1d"
+
+var (
+	ErrInvalidGTIN          = errors.New("invalid GTIN")
+	ErrInvalidCompanyPrefix = errors.New("GS1 company prefix must be 6 to 12 digits")
+	ErrSerialExhausted      = errors.New("SSCC serial references exhausted for the company prefix")
+)
+
+// CheckDigit returns the GS1 mod-10 check digit of digits, which must not
+// include the check digit itself.
+func CheckDigit(digits string) int {
+	sum := 0
+	for i := 0; i < len(digits); i++ {
+		d := int(digits[len(digits)-1-i] - '0')
+		if i%2 == 0 {
+			d *= 3
+		}
+		sum += d
+	}
+	return (10 - sum%10) % 10
+}
+
+// NormalizeGTIN validates a GTIN-8, -12, -13 or -14 and returns it as the
+// 14-digit form stored and compared everywhere.
+func NormalizeGTIN(s string) (string, error) {
+	s = strings.ReplaceAll(strings.TrimSpace(s), " ", "")
+	switch len(s) {
+	case 8, 12, 13, 14:
+	default:
+		return "", fmt.Errorf("%w: %q must have 8, 12, 13 or 14 digits", ErrInvalidGTIN, s)
+	}
+	if !allDigits(s) {
+		return "", fmt.Errorf("%w: %q must only contain digits", ErrInvalidGTIN, s)
+	}
+	if CheckDigit(s[:len(s)-1]) != int(s[len(s)-1]-'0') {
+		return "", fmt.Errorf("%w: %q has a wrong check digit", ErrInvalidGTIN, s)
+	}
+	return strings.Repeat("0", 14-len(s)) + s, nil
+}
+
+// ValidCompanyPrefix reports whether p can be a GS1 company prefix.
+func ValidCompanyPrefix(p string) bool {
+	return len(p) >= 6 && len(p) <= 12 && allDigits(p)
+}
+
+// MaxSerial is the largest serial reference an SSCC can carry with
+// companyPrefix.
+func MaxSerial(companyPrefix string) int64 {
+	max := int64(1)
+	for i := len(companyPrefix); i < 16; i++ {
+		max *= 10
+	}
+	return max - 1
+}
+
+// SSCC builds an SSCC-18 from an extension digit, a company prefix and a
+// serial reference.
+func SSCC(extension int, companyPrefix string, serial int64) (string, error) {
+	if !ValidCompanyPrefix(companyPrefix) {
+		return "", ErrInvalidCompanyPrefix
+	}
+	if extension < 0 || extension > 9 {
+		return "", fmt.Errorf("SSCC extension digit must be 0 to 9")
+	}
+	if serial < 0 || serial > MaxSerial(companyPrefix) {
+		return "", ErrSerialExhausted
+	}
+	body := fmt.Sprintf("%d%s%0*d", extension, companyPrefix, 16-len(companyPrefix), serial)
+	return fmt.Sprintf("%s%d", body, CheckDigit(body)), nil
+}
+
+// ElementString returns the human-readable element string of one
+// application identifier, e.g. "(00)006141411234567890".
+func ElementString(ai, data string) string {
+	return "(" + ai + ")" + data
+}
+
+// ScannedGTIN reads a GTIN from a scan: a bare EAN/UPC/GTIN, or a GS1
+// element string starting with AI (01), with or without parentheses,
+// symbology identifier or FNC1 separators. It returns the 14-digit GTIN.
+func ScannedGTIN(scan string) (string, bool) {
+	s := strings.TrimSpace(scan)
+	// Symbology identifiers such as ]C1 (GS1-128), ]d2 (DataMatrix), ]Q3 (QR).
+	if len(s) > 3 && s[0] == ']' {
+		s = s[3:]
+	}
+	s = strings.TrimPrefix(s, fnc1)
+
+	switch {
+	case strings.HasPrefix(s, "("+AIGTIN+")"):
+		s = s[4:]
+		if len(s) < 14 {
+			return "", false
+		}
+		s = s[:14]
+	case len(s) >= 16 && strings.HasPrefix(s, AIGTIN) && allDigits(s[2:16]):
+		s = s[2:16]
+	}
+
+	gtin, err := NormalizeGTIN(s)
+	if err != nil {
+		return "", false
+	}
+	return gtin, true
+}
+
+func allDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,68 @@
+package gs1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeGTIN(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+		ok   bool
+	}{
+		{"4006381333931", "04006381333931", true}, // GTIN-13
+		{"036000291452", "00036000291452", true},  // UPC-A
+		{"96385074", "00000096385074", true},      // EAN-8
+		{"10614141000415", "10614141000415", true},
+		{" 4006381333931 ", "04006381333931", true},
+		{"4006381333932", "", false}, // wrong check digit
+		{"40063813339", "", false},   // 11 digits
+		{"40063813339A1", "", false},
+	}
+	for _, tt := range tests {
+		got, err := NormalizeGTIN(tt.in)
+		if !tt.ok {
+			assert.ErrorIs(t, err, ErrInvalidGTIN, tt.in)
+			continue
+		}
+		require.NoError(t, err, tt.in)
+		assert.Equal(t, tt.want, got)
+	}
+}
+
+func TestSSCC(t *testing.T) {
+	sscc, err := SSCC(1, "0614141", 123456789)
+	require.NoError(t, err)
+	assert.Equal(t, "106141411234567897", sscc)
+	assert.Len(t, sscc, 18)
+
+	_, err = SSCC(0, "12345", 1)
+	assert.ErrorIs(t, err, ErrInvalidCompanyPrefix)
+
+	_, err = SSCC(0, "061414112345", MaxSerial("061414112345")+1)
+	assert.ErrorIs(t, err, ErrSerialExhausted)
+	assert.Equal(t, int64(9999), MaxSerial("061414112345"))
+}
+
+func TestScannedGTIN(t *testing.T) {
+	for _, scan := range []string{
+		"4006381333931",
+		"(01)04006381333931",
+		"0104006381333931",
+		"]C10104006381333931",
+		"0104006381333931" + fnc1 + "10LOT42",
+		"(01)04006381333931(10)LOT42",
+	} {
+		got, ok := ScannedGTIN(scan)
+		assert.True(t, ok, scan)
+		assert.Equal(t, "04006381333931", got, scan)
+	}
+
+	for _, scan := range []string{"", "LS2-ABC", "(00)106141411234567897", "4006381333932"} {
+		_, ok := ScannedGTIN(scan)
+		assert.False(t, ok, scan)
+	}
+}
@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/ekastn/load-stuffing-calculator/internal/dto"
+	"github.com/ekastn/load-stuffing-calculator/internal/response"
+	"github.com/ekastn/load-stuffing-calculator/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type CalculationJobHandler struct {
+	jobSvc service.CalculationJobService
+}
+
+func NewCalculationJobHandler(jobSvc service.CalculationJobService) *CalculationJobHandler {
+	return &CalculationJobHandler{jobSvc: jobSvc}
+}
+
+// EnqueueCalculation godoc
+//
+//	@Summary		Queue plan calculation
+//	@Description	Queues the packing calculation for a plan and returns immediately. Poll the job for its status.
+//	@Tags			plans
+//	@Accept			json
+//	@Produce		json
+//	@Param			workspace_id	query		string						false	"Workspace override (founder only)"
+//	@Param			id				path		string						true	"Plan ID"
+//	@Param			request			body		dto.CalculatePlanRequest	false	"Calculation Options"
+//	@Success		202				{object}	response.APIResponse{data=dto.CalculationJobResponse}
+//	@Failure		400				{object}	response.APIResponse
+//	@Failure		500				{object}	response.APIResponse
+//	@Security		BearerAuth
+//	@Router			/plans/{id}/jobs [post]
+func (h *CalculationJobHandler) EnqueueCalculation(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		response.Error(c, http.StatusBadRequest, "Plan ID is required")
+		return
+	}
+
+	var req dto.CalculatePlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		if !errors.Is(err, io.EOF) {
+			response.Error(c, http.StatusBadRequest, "Invalid request format: "+err.Error())
+			return
+		}
+		req = dto.CalculatePlanRequest{}
+	}
+
+	withFounderWorkspaceOverride(c)
+
+	resp, err := h.jobSvc.EnqueueCalculation(c.Request.Context(), id, req)
+	if err != nil {
+		respondPlanServiceError(c, err, http.StatusInternalServerError, "Failed to queue calculation: ")
+		return
+	}
+
+	response.Success(c, http.StatusAccepted, resp)
+}
+
+// GetCalculationJob godoc
+//
+//	@Summary		Get calculation job
+//	@Description	Returns the status of a queued plan calculation.
+//	@Tags			plans
+//	@Accept			json
+//	@Produce		json
+//	@Param			workspace_id	query		string	false	"Workspace override (founder only)"
+//	@Param			id				path		string	true	"Plan ID"
+//	@Param			jobId			path		string	true	"Job ID"
+//	@Success		200				{object}	response.APIResponse{data=dto.CalculationJobResponse}
+//	@Failure		404				{object}	response.APIResponse
+//	@Security		BearerAuth
+//	@Router			/plans/{id}/jobs/{jobId} [get]
+func (h *CalculationJobHandler) GetCalculationJob(c *gin.Context) {
+	id := c.Param("id")
+	jobID := c.Param("jobId")
+	if id == "" || jobID == "" {
+		response.Error(c, http.StatusBadRequest, "Plan ID and Job ID are required")
+		return
+	}
+
+	withFounderWorkspaceOverride(c)
+
+	resp, err := h.jobSvc.GetCalculationJob(c.Request.Context(), id, jobID)
+	if err != nil {
+		respondPlanServiceError(c, err, http.StatusNotFound, "Job not found: ")
+		return
+	}
+
+	response.Success(c, http.StatusOK, resp)
+}
+
+// CancelCalculationJob godoc
+//
+//	@Summary		Cancel calculation job
+//	@Description	Cancels a queued or running plan calculation.
+//	@Tags			plans
+//	@Accept			json
+//	@Produce		json
+//	@Param			workspace_id	query		string	false	"Workspace override (founder only)"
+//	@Param			id				path		string	true	"Plan ID"
+//	@Param			jobId			path		string	true	"Job ID"
+//	@Success		200				{object}	response.APIResponse{data=dto.CalculationJobResponse}
+//	@Failure		404				{object}	response.APIResponse
+//	@Failure		409				{object}	response.APIResponse
+//	@Security		BearerAuth
+//	@Router			/plans/{id}/jobs/{jobId}/cancel [post]
+func (h *CalculationJobHandler) CancelCalculationJob(c *gin.Context) {
+	id := c.Param("id")
+	jobID := c.Param("jobId")
+	if id == "" || jobID == "" {
+		response.Error(c, http.StatusBadRequest, "Plan ID and Job ID are required")
+		return
+	}
+
+	withFounderWorkspaceOverride(c)
+
+	resp, err := h.jobSvc.CancelCalculationJob(c.Request.Context(), id, jobID)
+	if err != nil {
+		if errors.Is(err, service.ErrJobFinished) {
+			response.Error(c, http.StatusConflict, "Job already finished")
+			return
+		}
+		respondPlanServiceError(c, err, http.StatusNotFound, "Job not found: ")
+		return
+	}
+
+	response.Success(c, http.StatusOK, resp)
+}
@@ -0,0 +1,162 @@
+package handler_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ekastn/load-stuffing-calculator/internal/dto"
+	"github.com/ekastn/load-stuffing-calculator/internal/handler"
+	"github.com/ekastn/load-stuffing-calculator/internal/mocks"
+	"github.com/ekastn/load-stuffing-calculator/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCalculationJobHandler_EnqueueCalculation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	planID := uuid.New().String()
+
+	t.Run("accepted", func(t *testing.T) {
+		mockSvc := new(mocks.MockCalculationJobService)
+		h := handler.NewCalculationJobHandler(mockSvc)
+
+		expectedResp := &dto.CalculationJobResponse{
+			JobID:     uuid.New().String(),
+			PlanID:    planID,
+			Status:    "queued",
+			CreatedAt: time.Now(),
+		}
+		mockSvc.On("EnqueueCalculation", mock.Anything, planID, dto.CalculatePlanRequest{}).Return(expectedResp, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/plans/"+planID+"/jobs", nil)
+		c.Params = gin.Params{{Key: "id", Value: planID}}
+
+		h.EnqueueCalculation(c)
+
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		mockSvc.AssertExpectations(t)
+	})
+
+	t.Run("missing_id", func(t *testing.T) {
+		mockSvc := new(mocks.MockCalculationJobService)
+		h := handler.NewCalculationJobHandler(mockSvc)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/plans//jobs", nil)
+		c.Params = gin.Params{{Key: "id", Value: ""}}
+
+		h.EnqueueCalculation(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("forbidden", func(t *testing.T) {
+		mockSvc := new(mocks.MockCalculationJobService)
+		h := handler.NewCalculationJobHandler(mockSvc)
+
+		mockSvc.On("EnqueueCalculation", mock.Anything, planID, mock.Anything).Return(nil, service.ErrForbidden)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/plans/"+planID+"/jobs", nil)
+		c.Params = gin.Params{{Key: "id", Value: planID}}
+
+		h.EnqueueCalculation(c)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		mockSvc.AssertExpectations(t)
+	})
+}
+
+func TestCalculationJobHandler_GetCalculationJob(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	planID := uuid.New().String()
+	jobID := uuid.New().String()
+
+	t.Run("success", func(t *testing.T) {
+		mockSvc := new(mocks.MockCalculationJobService)
+		h := handler.NewCalculationJobHandler(mockSvc)
+
+		expectedResp := &dto.CalculationJobResponse{JobID: jobID, PlanID: planID, Status: "running"}
+		mockSvc.On("GetCalculationJob", mock.Anything, planID, jobID).Return(expectedResp, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/plans/"+planID+"/jobs/"+jobID, nil)
+		c.Params = gin.Params{{Key: "id", Value: planID}, {Key: "jobId", Value: jobID}}
+
+		h.GetCalculationJob(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockSvc.AssertExpectations(t)
+	})
+
+	t.Run("not_found", func(t *testing.T) {
+		mockSvc := new(mocks.MockCalculationJobService)
+		h := handler.NewCalculationJobHandler(mockSvc)
+
+		mockSvc.On("GetCalculationJob", mock.Anything, planID, jobID).Return(nil, errors.New("not found"))
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/plans/"+planID+"/jobs/"+jobID, nil)
+		c.Params = gin.Params{{Key: "id", Value: planID}, {Key: "jobId", Value: jobID}}
+
+		h.GetCalculationJob(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockSvc.AssertExpectations(t)
+	})
+}
+
+func TestCalculationJobHandler_CancelCalculationJob(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	planID := uuid.New().String()
+	jobID := uuid.New().String()
+
+	t.Run("success", func(t *testing.T) {
+		mockSvc := new(mocks.MockCalculationJobService)
+		h := handler.NewCalculationJobHandler(mockSvc)
+
+		expectedResp := &dto.CalculationJobResponse{JobID: jobID, PlanID: planID, Status: "cancelled"}
+		mockSvc.On("CancelCalculationJob", mock.Anything, planID, jobID).Return(expectedResp, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/plans/"+planID+"/jobs/"+jobID+"/cancel", nil)
+		c.Params = gin.Params{{Key: "id", Value: planID}, {Key: "jobId", Value: jobID}}
+
+		h.CancelCalculationJob(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockSvc.AssertExpectations(t)
+	})
+
+	t.Run("already_finished", func(t *testing.T) {
+		mockSvc := new(mocks.MockCalculationJobService)
+		h := handler.NewCalculationJobHandler(mockSvc)
+
+		mockSvc.On("CancelCalculationJob", mock.Anything, planID, jobID).Return(nil, service.ErrJobFinished)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/plans/"+planID+"/jobs/"+jobID+"/cancel", nil)
+		c.Params = gin.Params{{Key: "id", Value: planID}, {Key: "jobId", Value: jobID}}
+
+		h.CancelCalculationJob(c)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		mockSvc.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ekastn/load-stuffing-calculator/internal/dto"
+	"github.com/ekastn/load-stuffing-calculator/internal/response"
+	"github.com/ekastn/load-stuffing-calculator/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type CapacityHandler struct {
+	capacitySvc service.CapacityService
+}
+
+func NewCapacityHandler(capacitySvc service.CapacityService) *CapacityHandler {
+	return &CapacityHandler{capacitySvc: capacitySvc}
+}
+
+// MaxQuantity godoc
+//
+//	@Summary		Find how many units fit in a container
+//	@Description	Searches for the largest quantity of one product, or of complete sets of a product mix, that fits in the container by volume, weight and actual placement. Returns the quantity, the limiting factor and the layout.
+//	@Tags			capacity
+//	@Accept			json
+//	@Produce		json
+//	@Param			workspace_id	query		string					false	"Workspace override (founder only)"
+//	@Param			request			body		dto.MaxQuantityRequest	true	"Product or mix and container"
+//	@Success		200				{object}	response.APIResponse{data=dto.MaxQuantityResponse}
+//	@Failure		400				{object}	response.APIResponse
+//	@Failure		404				{object}	response.APIResponse
+//	@Failure		500				{object}	response.APIResponse
+//	@Security		BearerAuth
+//	@Router			/capacity/max-quantity [post]
+func (h *CapacityHandler) MaxQuantity(c *gin.Context) {
+	withFounderWorkspaceOverride(c)
+
+	var req dto.MaxQuantityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request format: "+err.Error())
+		return
+	}
+
+	resp, err := h.capacitySvc.MaxQuantity(c.Request.Context(), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidCapacityRequest):
+			response.Error(c, http.StatusBadRequest, err.Error())
+		case errors.Is(err, service.ErrCapacityInputNotFound):
+			response.Error(c, http.StatusNotFound, err.Error())
+		default:
+			response.Error(c, http.StatusInternalServerError, "Failed to calculate capacity: "+err.Error())
+		}
+		return
+	}
+
+	response.Success(c, http.StatusOK, resp)
+}
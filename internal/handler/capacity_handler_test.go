@@ -0,0 +1,95 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ekastn/load-stuffing-calculator/internal/dto"
+	"github.com/ekastn/load-stuffing-calculator/internal/handler"
+	"github.com/ekastn/load-stuffing-calculator/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCapacityHandler_MaxQuantity(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	sku := "TV55-001"
+	l, w, h, wt := 6058.0, 2438.0, 2591.0, 28000.0
+	req := dto.MaxQuantityRequest{
+		ProductSKU: &sku,
+		Container:  dto.CreatePlanContainer{LengthMM: &l, WidthMM: &w, HeightMM: &h, MaxWeightKG: &wt},
+	}
+
+	tests := []struct {
+		name       string
+		body       any
+		svcResp    *dto.MaxQuantityResponse
+		svcErr     error
+		wantStatus int
+	}{
+		{
+			name:       "success",
+			body:       req,
+			svcResp:    &dto.MaxQuantityResponse{MaxQuantity: 120, Sets: 120, LimitingFactor: service.LimitSpace},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "bad_json",
+			body:       "invalid",
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid_request",
+			body:       req,
+			svcErr:     fmt.Errorf("%w: give either a product or a mix", service.ErrInvalidCapacityRequest),
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "product_not_found",
+			body:       req,
+			svcErr:     fmt.Errorf("%w: product: no rows", service.ErrCapacityInputNotFound),
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "packing_error",
+			body:       req,
+			svcErr:     errors.New("packing failed"),
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := new(MockCapacityService)
+			hd := handler.NewCapacityHandler(mockSvc)
+
+			if tt.svcResp != nil || tt.svcErr != nil {
+				mockSvc.On("MaxQuantity", mock.Anything, req).Return(tt.svcResp, tt.svcErr)
+			}
+
+			var payload []byte
+			if s, ok := tt.body.(string); ok {
+				payload = []byte(s)
+			} else {
+				payload, _ = json.Marshal(tt.body)
+			}
+
+			rec := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(rec)
+			c.Request = httptest.NewRequest(http.MethodPost, "/capacity/max-quantity", bytes.NewBuffer(payload))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			hd.MaxQuantity(c)
+
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			mockSvc.AssertExpectations(t)
+		})
+	}
+}
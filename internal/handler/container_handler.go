@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -42,6 +43,10 @@ func (h *ContainerHandler) CreateContainer(c *gin.Context) {
 	}
 
 	resp, err := h.containerSvc.CreateContainer(c.Request.Context(), req)
+	if errors.Is(err, service.ErrInvalidCompartments) {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
 	if err != nil {
 		response.Error(c, http.StatusInternalServerError, "Failed to create container: "+err.Error())
 		return
@@ -145,6 +150,10 @@ func (h *ContainerHandler) UpdateContainer(c *gin.Context) {
 	}
 
 	err := h.containerSvc.UpdateContainer(c.Request.Context(), id, req)
+	if errors.Is(err, service.ErrInvalidCompartments) {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
 	if err != nil {
 		response.Error(c, http.StatusInternalServerError, "Failed to update container: "+err.Error())
 		return
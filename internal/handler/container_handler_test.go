@@ -4,12 +4,14 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/ekastn/load-stuffing-calculator/internal/dto"
 	"github.com/ekastn/load-stuffing-calculator/internal/handler"
+	"github.com/ekastn/load-stuffing-calculator/internal/service"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -88,6 +90,35 @@ func TestContainerHandler_CreateContainer(t *testing.T) {
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
 		mockSvc.AssertExpectations(t)
 	})
+
+	t.Run("invalid_compartments", func(t *testing.T) {
+		mockSvc := new(MockContainerService)
+		h := handler.NewContainerHandler(mockSvc)
+
+		req := dto.CreateContainerRequest{
+			Name:          "Reefer",
+			InnerLengthMM: 6000,
+			InnerWidthMM:  2300,
+			InnerHeightMM: 2500,
+			MaxWeightKG:   20000,
+			Compartments: []dto.Compartment{
+				{Name: "Chilled", TemperatureClass: "chilled", StartMM: 4000, LengthMM: 4000},
+			},
+		}
+
+		mockSvc.On("CreateContainer", mock.Anything, req).Return((*dto.ContainerResponse)(nil), fmt.Errorf("%w: out of bounds", service.ErrInvalidCompartments))
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		jsonBytes, _ := json.Marshal(req)
+		c.Request = httptest.NewRequest(http.MethodPost, "/containers", bytes.NewBuffer(jsonBytes))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		h.CreateContainer(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockSvc.AssertExpectations(t)
+	})
 }
 
 func TestContainerHandler_GetContainer(t *testing.T) {
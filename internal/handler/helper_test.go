@@ -1,6 +1,12 @@
 package handler_test
 
-import "time"
+import (
+	"time"
+
+	"github.com/ekastn/load-stuffing-calculator/internal/barcode"
+)
+
+var testCodec = barcode.NewCodec("test-secret", true)
 
 // Helper functions for pointers
 func stringPtr(s string) *string     { return &s }
@@ -0,0 +1,329 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/ekastn/load-stuffing-calculator/internal/dto"
+	"github.com/ekastn/load-stuffing-calculator/internal/response"
+	"github.com/ekastn/load-stuffing-calculator/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type LoadingHandler struct {
+	loadingSvc service.LoadingService
+}
+
+func NewLoadingHandler(loadingSvc service.LoadingService) *LoadingHandler {
+	return &LoadingHandler{loadingSvc: loadingSvc}
+}
+
+// StartLoadingSession godoc
+//
+//	@Summary		Start loading session
+//	@Description	Opens a loading session for a calculated plan and moves the plan to IN_PROGRESS. A paused session is resumed.
+//	@Tags			loading
+//	@Accept			json
+//	@Produce		json
+//	@Param			workspace_id	query		string	false	"Workspace override (founder only)"
+//	@Param			id				path		string	true	"Plan ID"
+//	@Success		201				{object}	response.APIResponse{data=dto.LoadingSessionResponse}
+//	@Failure		400				{object}	response.APIResponse
+//	@Failure		409				{object}	response.APIResponse
+//	@Security		BearerAuth
+//	@Router			/plans/{id}/loading-sessions [post]
+func (h *LoadingHandler) StartLoadingSession(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		response.Error(c, http.StatusBadRequest, "Plan ID is required")
+		return
+	}
+
+	withFounderWorkspaceOverride(c)
+
+	resp, err := h.loadingSvc.StartSession(c.Request.Context(), id)
+	if err != nil {
+		respondPlanServiceError(c, err, http.StatusBadRequest, "Failed to start loading session: ")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, resp)
+}
+
+// ListLoadingSessions godoc
+//
+//	@Summary		List loading sessions
+//	@Description	Lists the loading sessions of a plan, newest first.
+//	@Tags			loading
+//	@Accept			json
+//	@Produce		json
+//	@Param			workspace_id	query		string	false	"Workspace override (founder only)"
+//	@Param			id				path		string	true	"Plan ID"
+//	@Success		200				{object}	response.APIResponse{data=[]dto.LoadingSessionResponse}
+//	@Failure		404				{object}	response.APIResponse
+//	@Security		BearerAuth
+//	@Router			/plans/{id}/loading-sessions [get]
+func (h *LoadingHandler) ListLoadingSessions(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		response.Error(c, http.StatusBadRequest, "Plan ID is required")
+		return
+	}
+
+	withFounderWorkspaceOverride(c)
+
+	resp, err := h.loadingSvc.ListSessions(c.Request.Context(), id)
+	if err != nil {
+		respondPlanServiceError(c, err, http.StatusNotFound, "Failed to list loading sessions: ")
+		return
+	}
+
+	response.Success(c, http.StatusOK, resp)
+}
+
+// GetLoadingSession godoc
+//
+//	@Summary		Get loading session
+//	@Description	Returns a loading session with its progress and next expected step.
+//	@Tags			loading
+//	@Accept			json
+//	@Produce		json
+//	@Param			workspace_id	query		string	false	"Workspace override (founder only)"
+//	@Param			id				path		string	true	"Plan ID"
+//	@Param			sessionId		path		string	true	"Session ID"
+//	@Success		200				{object}	response.APIResponse{data=dto.LoadingSessionResponse}
+//	@Failure		404				{object}	response.APIResponse
+//	@Security		BearerAuth
+//	@Router			/plans/{id}/loading-sessions/{sessionId} [get]
+func (h *LoadingHandler) GetLoadingSession(c *gin.Context) {
+	id := c.Param("id")
+	sessionID := c.Param("sessionId")
+	if id == "" || sessionID == "" {
+		response.Error(c, http.StatusBadRequest, "Plan ID and Session ID are required")
+		return
+	}
+
+	withFounderWorkspaceOverride(c)
+
+	resp, err := h.loadingSvc.GetSession(c.Request.Context(), id, sessionID)
+	if err != nil {
+		respondPlanServiceError(c, err, http.StatusNotFound, "Loading session not found: ")
+		return
+	}
+
+	response.Success(c, http.StatusOK, resp)
+}
+
+// PauseLoadingSession godoc
+//
+//	@Summary		Pause loading session
+//	@Description	Pauses an active loading session. Starting a session again resumes it.
+//	@Tags			loading
+//	@Accept			json
+//	@Produce		json
+//	@Param			workspace_id	query		string	false	"Workspace override (founder only)"
+//	@Param			id				path		string	true	"Plan ID"
+//	@Param			sessionId		path		string	true	"Session ID"
+//	@Success		200				{object}	response.APIResponse{data=dto.LoadingSessionResponse}
+//	@Failure		404				{object}	response.APIResponse
+//	@Failure		409				{object}	response.APIResponse
+//	@Security		BearerAuth
+//	@Router			/plans/{id}/loading-sessions/{sessionId}/pause [post]
+func (h *LoadingHandler) PauseLoadingSession(c *gin.Context) {
+	id := c.Param("id")
+	sessionID := c.Param("sessionId")
+	if id == "" || sessionID == "" {
+		response.Error(c, http.StatusBadRequest, "Plan ID and Session ID are required")
+		return
+	}
+
+	withFounderWorkspaceOverride(c)
+
+	resp, err := h.loadingSvc.PauseSession(c.Request.Context(), id, sessionID)
+	if err != nil {
+		respondPlanServiceError(c, err, http.StatusBadRequest, "Failed to pause loading session: ")
+		return
+	}
+
+	response.Success(c, http.StatusOK, resp)
+}
+
+// CompleteLoadingSession godoc
+//
+//	@Summary		Complete loading session
+//	@Description	Closes a loading session once every step is loaded and completes the plan.
+//	@Tags			loading
+//	@Accept			json
+//	@Produce		json
+//	@Param			workspace_id	query		string	false	"Workspace override (founder only)"
+//	@Param			id				path		string	true	"Plan ID"
+//	@Param			sessionId		path		string	true	"Session ID"
+//	@Success		200				{object}	response.APIResponse{data=dto.LoadingSessionResponse}
+//	@Failure		404				{object}	response.APIResponse
+//	@Failure		409				{object}	response.APIResponse
+//	@Security		BearerAuth
+//	@Router			/plans/{id}/loading-sessions/{sessionId}/complete [post]
+func (h *LoadingHandler) CompleteLoadingSession(c *gin.Context) {
+	id := c.Param("id")
+	sessionID := c.Param("sessionId")
+	if id == "" || sessionID == "" {
+		response.Error(c, http.StatusBadRequest, "Plan ID and Session ID are required")
+		return
+	}
+
+	withFounderWorkspaceOverride(c)
+
+	resp, err := h.loadingSvc.CompleteSession(c.Request.Context(), id, sessionID)
+	if err != nil {
+		respondPlanServiceError(c, err, http.StatusBadRequest, "Failed to complete loading session: ")
+		return
+	}
+
+	response.Success(c, http.StatusOK, resp)
+}
+
+// RecordLoadingScan godoc
+//
+//	@Summary		Scan a unit
+//	@Description	Checks a scanned label against the session's next expected step and records the scan.
+//	@Tags			loading
+//	@Accept			json
+//	@Produce		json
+//	@Param			workspace_id	query		string					false	"Workspace override (founder only)"
+//	@Param			id				path		string					true	"Plan ID"
+//	@Param			sessionId		path		string					true	"Session ID"
+//	@Param			request			body		dto.LoadingScanRequest	true	"Scan Data"
+//	@Success		201				{object}	response.APIResponse{data=dto.LoadingScanResponse}
+//	@Failure		400				{object}	response.APIResponse
+//	@Failure		404				{object}	response.APIResponse
+//	@Failure		409				{object}	response.APIResponse
+//	@Security		BearerAuth
+//	@Router			/plans/{id}/loading-sessions/{sessionId}/scans [post]
+func (h *LoadingHandler) RecordLoadingScan(c *gin.Context) {
+	id := c.Param("id")
+	sessionID := c.Param("sessionId")
+	if id == "" || sessionID == "" {
+		response.Error(c, http.StatusBadRequest, "Plan ID and Session ID are required")
+		return
+	}
+
+	var req dto.LoadingScanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request format: "+err.Error())
+		return
+	}
+
+	withFounderWorkspaceOverride(c)
+
+	resp, err := h.loadingSvc.RecordScan(c.Request.Context(), id, sessionID, req)
+	if err != nil {
+		respondPlanServiceError(c, err, http.StatusBadRequest, "Failed to record scan: ")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, resp)
+}
+
+// ListLoadingScans godoc
+//
+//	@Summary		List loading scans
+//	@Description	Lists every scan of a loading session in the order they were made.
+//	@Tags			loading
+//	@Accept			json
+//	@Produce		json
+//	@Param			workspace_id	query		string	false	"Workspace override (founder only)"
+//	@Param			id				path		string	true	"Plan ID"
+//	@Param			sessionId		path		string	true	"Session ID"
+//	@Success		200				{object}	response.APIResponse{data=[]dto.LoadingScanDetail}
+//	@Failure		404				{object}	response.APIResponse
+//	@Security		BearerAuth
+//	@Router			/plans/{id}/loading-sessions/{sessionId}/scans [get]
+func (h *LoadingHandler) ListLoadingScans(c *gin.Context) {
+	id := c.Param("id")
+	sessionID := c.Param("sessionId")
+	if id == "" || sessionID == "" {
+		response.Error(c, http.StatusBadRequest, "Plan ID and Session ID are required")
+		return
+	}
+
+	withFounderWorkspaceOverride(c)
+
+	resp, err := h.loadingSvc.ListScans(c.Request.Context(), id, sessionID)
+	if err != nil {
+		respondPlanServiceError(c, err, http.StatusNotFound, "Failed to list scans: ")
+		return
+	}
+
+	response.Success(c, http.StatusOK, resp)
+}
+
+// GetOfflineBundle godoc
+//
+//	@Summary		Download offline bundle
+//	@Description	Packages the plan, its placements and barcodes into a signed bundle for validating scans offline.
+//	@Tags			loading
+//	@Accept			json
+//	@Produce		json
+//	@Param			workspace_id	query		string	false	"Workspace override (founder only)"
+//	@Param			id				path		string	true	"Plan ID"
+//	@Success		200				{object}	response.APIResponse{data=dto.OfflineBundle}
+//	@Failure		404				{object}	response.APIResponse
+//	@Failure		409				{object}	response.APIResponse
+//	@Security		BearerAuth
+//	@Router			/plans/{id}/offline-bundle [get]
+func (h *LoadingHandler) GetOfflineBundle(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		response.Error(c, http.StatusBadRequest, "Plan ID is required")
+		return
+	}
+
+	withFounderWorkspaceOverride(c)
+
+	resp, err := h.loadingSvc.OfflineBundle(c.Request.Context(), id)
+	if err != nil {
+		respondPlanServiceError(c, err, http.StatusNotFound, "Failed to build offline bundle: ")
+		return
+	}
+
+	response.Success(c, http.StatusOK, resp)
+}
+
+// SyncOfflineScans godoc
+//
+//	@Summary		Upload offline scans
+//	@Description	Records scans made offline against a downloaded bundle. Events are idempotent by ID; scans made against an outdated plan are recorded and reported as conflicts. A bundle downloaded before loading started is synced into the session opened since.
+//	@Tags			loading
+//	@Accept			json
+//	@Produce		json
+//	@Param			workspace_id	query		string					false	"Workspace override (founder only)"
+//	@Param			id				path		string					true	"Plan ID"
+//	@Param			request			body		dto.OfflineSyncRequest	true	"Offline Scans"
+//	@Success		200				{object}	response.APIResponse{data=dto.OfflineSyncReport}
+//	@Failure		400				{object}	response.APIResponse
+//	@Failure		404				{object}	response.APIResponse
+//	@Failure		409				{object}	response.APIResponse
+//	@Security		BearerAuth
+//	@Router			/plans/{id}/offline-sync [post]
+func (h *LoadingHandler) SyncOfflineScans(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		response.Error(c, http.StatusBadRequest, "Plan ID is required")
+		return
+	}
+
+	var req dto.OfflineSyncRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request format: "+err.Error())
+		return
+	}
+
+	withFounderWorkspaceOverride(c)
+
+	resp, err := h.loadingSvc.SyncOfflineScans(c.Request.Context(), id, req)
+	if err != nil {
+		respondPlanServiceError(c, err, http.StatusBadRequest, "Failed to sync offline scans: ")
+		return
+	}
+
+	response.Success(c, http.StatusOK, resp)
+}
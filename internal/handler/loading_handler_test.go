@@ -0,0 +1,253 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ekastn/load-stuffing-calculator/internal/dto"
+	"github.com/ekastn/load-stuffing-calculator/internal/handler"
+	"github.com/ekastn/load-stuffing-calculator/internal/mocks"
+	"github.com/ekastn/load-stuffing-calculator/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestLoadingHandler_StartLoadingSession(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	planID := uuid.New().String()
+
+	t.Run("created", func(t *testing.T) {
+		mockSvc := new(mocks.MockLoadingService)
+		h := handler.NewLoadingHandler(mockSvc)
+
+		mockSvc.On("StartSession", mock.Anything, planID).Return(&dto.LoadingSessionResponse{PlanID: planID, Status: "active"}, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/plans/"+planID+"/loading-sessions", nil)
+		c.Params = gin.Params{{Key: "id", Value: planID}}
+
+		h.StartLoadingSession(c)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		mockSvc.AssertExpectations(t)
+	})
+
+	t.Run("plan_not_loadable", func(t *testing.T) {
+		mockSvc := new(mocks.MockLoadingService)
+		h := handler.NewLoadingHandler(mockSvc)
+
+		mockSvc.On("StartSession", mock.Anything, planID).Return(nil, fmt.Errorf("%w: DRAFT to IN_PROGRESS", service.ErrInvalidStatusTransition))
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/plans/"+planID+"/loading-sessions", nil)
+		c.Params = gin.Params{{Key: "id", Value: planID}}
+
+		h.StartLoadingSession(c)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
+
+func TestLoadingHandler_RecordLoadingScan(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	planID := uuid.New().String()
+	sessionID := uuid.New().String()
+	params := gin.Params{{Key: "id", Value: planID}, {Key: "sessionId", Value: sessionID}}
+
+	t.Run("recorded", func(t *testing.T) {
+		mockSvc := new(mocks.MockLoadingService)
+		h := handler.NewLoadingHandler(mockSvc)
+
+		req := dto.LoadingScanRequest{Barcode: "PLAN-12345678-STEP-001-87654321"}
+		mockSvc.On("RecordScan", mock.Anything, planID, sessionID, req).Return(&dto.LoadingScanResponse{
+			Scan: dto.LoadingScanDetail{Barcode: req.Barcode, Status: "MATCHED"},
+		}, nil)
+
+		body, _ := json.Marshal(req)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/plans/"+planID+"/loading-sessions/"+sessionID+"/scans", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Params = params
+
+		h.RecordLoadingScan(c)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		mockSvc.AssertExpectations(t)
+	})
+
+	t.Run("missing_barcode", func(t *testing.T) {
+		mockSvc := new(mocks.MockLoadingService)
+		h := handler.NewLoadingHandler(mockSvc)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/plans/"+planID+"/loading-sessions/"+sessionID+"/scans", bytes.NewBufferString(`{}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Params = params
+
+		h.RecordLoadingScan(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("paused_session", func(t *testing.T) {
+		mockSvc := new(mocks.MockLoadingService)
+		h := handler.NewLoadingHandler(mockSvc)
+
+		mockSvc.On("RecordScan", mock.Anything, planID, sessionID, mock.Anything).Return(nil, service.ErrLoadingSessionNotActive)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/plans/"+planID+"/loading-sessions/"+sessionID+"/scans", bytes.NewBufferString(`{"barcode":"x"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Params = params
+
+		h.RecordLoadingScan(c)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
+
+func TestLoadingHandler_CompleteLoadingSession(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	planID := uuid.New().String()
+	sessionID := uuid.New().String()
+	params := gin.Params{{Key: "id", Value: planID}, {Key: "sessionId", Value: sessionID}}
+
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"completed", nil, http.StatusOK},
+		{"steps_left", fmt.Errorf("%w: 1 of 2 steps loaded", service.ErrLoadingIncomplete), http.StatusConflict},
+		{"unknown_session", service.ErrLoadingSessionNotFound, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := new(mocks.MockLoadingService)
+			h := handler.NewLoadingHandler(mockSvc)
+
+			if tt.err != nil {
+				mockSvc.On("CompleteSession", mock.Anything, planID, sessionID).Return(nil, tt.err)
+			} else {
+				mockSvc.On("CompleteSession", mock.Anything, planID, sessionID).Return(&dto.LoadingSessionResponse{Status: "completed"}, nil)
+			}
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/plans/"+planID+"/loading-sessions/"+sessionID+"/complete", nil)
+			c.Params = params
+
+			h.CompleteLoadingSession(c)
+
+			assert.Equal(t, tt.want, w.Code)
+			mockSvc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestLoadingHandler_ListLoadingScans(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	planID := uuid.New().String()
+	sessionID := uuid.New().String()
+
+	mockSvc := new(mocks.MockLoadingService)
+	h := handler.NewLoadingHandler(mockSvc)
+
+	mockSvc.On("ListScans", mock.Anything, planID, sessionID).Return([]dto.LoadingScanDetail{{Status: "MATCHED"}, {Status: "DUPLICATE"}}, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/plans/"+planID+"/loading-sessions/"+sessionID+"/scans", nil)
+	c.Params = gin.Params{{Key: "id", Value: planID}, {Key: "sessionId", Value: sessionID}}
+
+	h.ListLoadingScans(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "DUPLICATE")
+	mockSvc.AssertExpectations(t)
+}
+
+func TestLoadingHandler_SyncOfflineScans(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	planID := uuid.New().String()
+	req := dto.OfflineSyncRequest{
+		Bundle: dto.OfflineBundleHeader{
+			FormatVersion: 1,
+			PlanID:        planID,
+			ResultID:      uuid.New().String(),
+			SessionID:     uuid.New().String(),
+			IssuedAt:      time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC),
+			ContentDigest: "digest",
+			Signature:     "signature",
+		},
+		Events: []dto.OfflineScanEvent{{EventID: uuid.New().String(), Barcode: "PLAN-12345678-STEP-001-87654321", ScannedAt: time.Date(2026, 3, 1, 8, 5, 0, 0, time.UTC)}},
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"synced", nil, http.StatusOK},
+		{"invalid_bundle", fmt.Errorf("%w: signature does not match", service.ErrInvalidBundle), http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := new(mocks.MockLoadingService)
+			h := handler.NewLoadingHandler(mockSvc)
+
+			if tt.err != nil {
+				mockSvc.On("SyncOfflineScans", mock.Anything, planID, req).Return(nil, tt.err)
+			} else {
+				mockSvc.On("SyncOfflineScans", mock.Anything, planID, req).Return(&dto.OfflineSyncReport{Applied: 1}, nil)
+			}
+
+			body, _ := json.Marshal(req)
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/plans/"+planID+"/offline-sync", bytes.NewBuffer(body))
+			c.Request.Header.Set("Content-Type", "application/json")
+			c.Params = gin.Params{{Key: "id", Value: planID}}
+
+			h.SyncOfflineScans(c)
+
+			assert.Equal(t, tt.want, w.Code)
+			mockSvc.AssertExpectations(t)
+		})
+	}
+
+	t.Run("event_without_id", func(t *testing.T) {
+		mockSvc := new(mocks.MockLoadingService)
+		h := handler.NewLoadingHandler(mockSvc)
+
+		bad := req
+		bad.Events = []dto.OfflineScanEvent{{Barcode: "x", ScannedAt: time.Now()}}
+		body, _ := json.Marshal(bad)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/plans/"+planID+"/offline-sync", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Params = gin.Params{{Key: "id", Value: planID}}
+
+		h.SyncOfflineScans(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
@@ -17,6 +17,7 @@ type MockInviteService = mocks.MockInviteService
 type MockMemberService = mocks.MockMemberService
 type MockDashboardService = mocks.MockDashboardService
 type MockWorkspaceService = mocks.MockWorkspaceService
+type MockCapacityService = mocks.MockCapacityService
 
 // MockPermCache is a mock for PermissionCache
 type MockPermCache struct {
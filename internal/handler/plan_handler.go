@@ -5,12 +5,12 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"sort"
 	"strconv"
-	"strings"
 
 	"github.com/ekastn/load-stuffing-calculator/internal/auth"
+	"github.com/ekastn/load-stuffing-calculator/internal/barcode"
 	"github.com/ekastn/load-stuffing-calculator/internal/dto"
+	"github.com/ekastn/load-stuffing-calculator/internal/label"
 	"github.com/ekastn/load-stuffing-calculator/internal/response"
 	"github.com/ekastn/load-stuffing-calculator/internal/service"
 	"github.com/ekastn/load-stuffing-calculator/internal/types"
@@ -20,6 +20,7 @@ import (
 
 type PlanHandler struct {
 	planSvc service.PlanService
+	codec   *barcode.Codec
 }
 
 func withFounderWorkspaceOverride(c *gin.Context) {
@@ -35,8 +36,8 @@ func withFounderWorkspaceOverride(c *gin.Context) {
 	c.Request = c.Request.WithContext(ctx)
 }
 
-func NewPlanHandler(planSvc service.PlanService) *PlanHandler {
-	return &PlanHandler{planSvc: planSvc}
+func NewPlanHandler(planSvc service.PlanService, codec *barcode.Codec) *PlanHandler {
+	return &PlanHandler{planSvc: planSvc, codec: codec}
 }
 
 func respondPlanServiceError(c *gin.Context, err error, defaultStatus int, defaultMessage string) {
@@ -45,6 +46,41 @@ func respondPlanServiceError(c *gin.Context, err error, defaultStatus int, defau
 		response.Error(c, http.StatusTooManyRequests, "Trial limit reached")
 	case errors.Is(err, service.ErrForbidden):
 		response.Error(c, http.StatusForbidden, "Forbidden")
+	case errors.Is(err, service.ErrUnknownBackend):
+		response.Error(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, service.ErrScenarioLimitReached):
+		response.Error(c, http.StatusConflict, "Scenario limit reached")
+	case errors.Is(err, service.ErrInvalidScenarioItems):
+		response.Error(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, service.ErrNothingToOverflow):
+		response.Error(c, http.StatusConflict, "Plan has no unfit items")
+	case errors.Is(err, service.ErrOverflowExists):
+		response.Error(c, http.StatusConflict, err.Error())
+	case errors.Is(err, service.ErrNoSuitableContainer):
+		response.Error(c, http.StatusUnprocessableEntity, "No saved container holds the unfit items")
+	case errors.Is(err, service.ErrInvalidCompartments):
+		response.Error(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, service.ErrInvalidStatusTransition):
+		response.Error(c, http.StatusConflict, err.Error())
+	case errors.Is(err, service.ErrResultVersionNotFound):
+		response.Error(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrStaleResultVersion), errors.Is(err, service.ErrStaleAlternative):
+		response.Error(c, http.StatusConflict, err.Error())
+	case errors.Is(err, service.ErrAlternativeNotFound):
+		response.Error(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrLoadingSessionNotFound):
+		response.Error(c, http.StatusNotFound, err.Error())
+	case errors.Is(err, service.ErrLoadingSessionNotActive), errors.Is(err, service.ErrLoadingIncomplete):
+		response.Error(c, http.StatusConflict, err.Error())
+	case errors.Is(err, service.ErrPlanNotCalculated):
+		response.Error(c, http.StatusConflict, err.Error())
+	case errors.Is(err, service.ErrInvalidBundle), errors.Is(err, service.ErrInvalidGTIN):
+		response.Error(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, service.ErrGS1NotConfigured), errors.Is(err, service.ErrSSCCExhausted):
+		response.Error(c, http.StatusConflict, err.Error())
+	case errors.Is(err, service.ErrItemProductNotFound), errors.Is(err, service.ErrItemDimensionsRequired),
+		errors.Is(err, service.ErrInvalidPackaging):
+		response.Error(c, http.StatusBadRequest, err.Error())
 	default:
 		response.Error(c, defaultStatus, defaultMessage+err.Error())
 	}
@@ -149,7 +185,7 @@ func (h *PlanHandler) ListPlans(c *gin.Context) {
 // UpdatePlan godoc
 //
 //	@Summary		Update a plan
-//	@Description	Updates an existing plan (status, container).
+//	@Description	Updates an existing plan (status, container). Status changes must follow the plan state machine; a change it does not allow returns 409.
 //	@Tags			plans
 //	@Accept			json
 //	@Produce		json
@@ -158,6 +194,7 @@ func (h *PlanHandler) ListPlans(c *gin.Context) {
 //	@Param			request			body		dto.UpdatePlanRequest	true	"Plan Update Data"
 //	@Success		200				{object}	response.APIResponse
 //	@Failure		400				{object}	response.APIResponse
+//	@Failure		409				{object}	response.APIResponse
 //	@Failure		500				{object}	response.APIResponse
 //	@Security		BearerAuth
 //	@Router			/plans/{id} [put]
@@ -228,6 +265,7 @@ func (h *PlanHandler) DeletePlan(c *gin.Context) {
 //	@Param			request			body		dto.AddPlanItemRequest	true	"Item Data"
 //	@Success		201				{object}	response.APIResponse{data=dto.PlanItemDetail}
 //	@Failure		400				{object}	response.APIResponse
+//	@Failure		409				{object}	response.APIResponse
 //	@Failure		500				{object}	response.APIResponse
 //	@Security		BearerAuth
 //	@Router			/plans/{id}/items [post]
@@ -301,6 +339,7 @@ func (h *PlanHandler) GetPlanItem(c *gin.Context) {
 //	@Param			request			body		dto.UpdatePlanItemRequest	true	"Update Data"
 //	@Success		200				{object}	response.APIResponse
 //	@Failure		400				{object}	response.APIResponse
+//	@Failure		409				{object}	response.APIResponse
 //	@Failure		500				{object}	response.APIResponse
 //	@Security		BearerAuth
 //	@Router			/plans/{id}/items/{itemId} [put]
@@ -341,6 +380,7 @@ func (h *PlanHandler) UpdatePlanItem(c *gin.Context) {
 //	@Param			itemId			path		string	true	"Item ID"
 //	@Success		200				{object}	response.APIResponse
 //	@Failure		400				{object}	response.APIResponse
+//	@Failure		409				{object}	response.APIResponse
 //	@Failure		500				{object}	response.APIResponse
 //	@Security		BearerAuth
 //	@Router			/plans/{id}/items/{itemId} [delete]
@@ -408,6 +448,496 @@ func (h *PlanHandler) CalculatePlan(c *gin.Context) {
 	response.Success(c, http.StatusOK, resp)
 }
 
+// StreamCalculation godoc
+//
+//	@Summary		Calculate plan with live progress
+//	@Description	Runs the packing calculation and streams Server-Sent Events: "progress" events while packing, a replay of the final placements as "placing" progress events, then a single "result" or "error" event.
+//	@Tags			plans
+//	@Produce		text/event-stream
+//	@Param			workspace_id	query		string	false	"Workspace override (founder only)"
+//	@Param			id				path		string	true	"Plan ID"
+//	@Param			strategy		query		string	false	"Packing strategy"
+//	@Param			goal			query		string	false	"Goal for parallel strategy"
+//	@Param			gravity			query		bool	false	"Apply gravity"
+//	@Success		200				{object}	dto.CalculationProgress
+//	@Failure		400				{object}	response.APIResponse
+//	@Security		BearerAuth
+//	@Router			/plans/{id}/calculate/stream [get]
+func (h *PlanHandler) StreamCalculation(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		response.Error(c, http.StatusBadRequest, "Plan ID is required")
+		return
+	}
+
+	var req dto.CalculatePlanRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid query parameters: "+err.Error())
+		return
+	}
+
+	withFounderWorkspaceOverride(c)
+
+	type outcome struct {
+		res *dto.CalculationResult
+		err error
+	}
+
+	// Progress is best-effort: events are dropped rather than stalling the packer
+	// when the client reads slowly.
+	events := make(chan dto.CalculationProgress, 64)
+	done := make(chan outcome, 1)
+	go func() {
+		res, err := h.planSvc.CalculatePlanWithProgress(c.Request.Context(), id, req, func(p dto.CalculationProgress) {
+			select {
+			case events <- p:
+			default:
+			}
+		})
+		done <- outcome{res: res, err: err}
+	}()
+
+	c.Header("Content-Type", "text/event-stream; charset=utf-8")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	ctx := c.Request.Context()
+	for {
+		select {
+		case p := <-events:
+			c.SSEvent("progress", p)
+			c.Writer.Flush()
+		case out := <-done:
+			// Flush progress that arrived just before the result.
+			for len(events) > 0 {
+				c.SSEvent("progress", <-events)
+			}
+			if out.err != nil {
+				c.SSEvent("error", gin.H{"message": "Failed to calculate plan: " + out.err.Error()})
+			} else {
+				c.SSEvent("result", out.res)
+			}
+			c.Writer.Flush()
+			return
+		case <-ctx.Done():
+			// Client went away; the calculation is cancelled through the same context.
+			return
+		}
+	}
+}
+
+// ComparePlan godoc
+//
+//	@Summary		Compare packing strategies
+//	@Description	Packs the plan with each candidate strategy/backend concurrently and returns the alternatives ranked by unfit count, utilisation, stability, centre-of-gravity offset and runtime. Nothing is saved; adopt an alternative within 30 minutes to make it the active result.
+//	@Tags			plans
+//	@Accept			json
+//	@Produce		json
+//	@Param			workspace_id	query		string					false	"Workspace override (founder only)"
+//	@Param			id				path		string					true	"Plan ID"
+//	@Param			request			body		dto.ComparePlanRequest	true	"Candidates"
+//	@Success		200				{object}	response.APIResponse{data=dto.ComparePlanResponse}
+//	@Failure		400				{object}	response.APIResponse
+//	@Failure		500				{object}	response.APIResponse
+//	@Security		BearerAuth
+//	@Router			/plans/{id}/compare [post]
+func (h *PlanHandler) ComparePlan(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		response.Error(c, http.StatusBadRequest, "Plan ID is required")
+		return
+	}
+
+	var req dto.ComparePlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request format: "+err.Error())
+		return
+	}
+
+	withFounderWorkspaceOverride(c)
+
+	resp, err := h.planSvc.ComparePlan(c.Request.Context(), id, req)
+	if err != nil {
+		respondPlanServiceError(c, err, http.StatusInternalServerError, "Failed to compare strategies: ")
+		return
+	}
+
+	response.Success(c, http.StatusOK, resp)
+}
+
+// AdoptAlternative godoc
+//
+//	@Summary		Adopt a compared alternative
+//	@Description	Saves an alternative returned by the compare endpoint as the plan's new active result, with exactly the compared placements. Alternatives are kept for 30 minutes; the plan's container and items must not have changed since the comparison.
+//	@Tags			plans
+//	@Produce		json
+//	@Param			workspace_id	query		string	false	"Workspace override (founder only)"
+//	@Param			id				path		string	true	"Plan ID"
+//	@Param			alternativeId	path		string	true	"Alternative ID"
+//	@Success		200				{object}	response.APIResponse{data=dto.CalculationResult}
+//	@Failure		400				{object}	response.APIResponse
+//	@Failure		404				{object}	response.APIResponse
+//	@Failure		409				{object}	response.APIResponse
+//	@Security		BearerAuth
+//	@Router			/plans/{id}/alternatives/{alternativeId}/adopt [post]
+func (h *PlanHandler) AdoptAlternative(c *gin.Context) {
+	id := c.Param("id")
+	altID := c.Param("alternativeId")
+
+	withFounderWorkspaceOverride(c)
+
+	resp, err := h.planSvc.AdoptAlternative(c.Request.Context(), id, altID)
+	if err != nil {
+		respondPlanServiceError(c, err, http.StatusBadRequest, "Failed to adopt alternative: ")
+		return
+	}
+
+	response.Success(c, http.StatusOK, resp)
+}
+
+// CreateScenario godoc
+//
+//	@Summary		Create plan scenario
+//	@Description	Forks a plan into a named what-if scenario with its own container, items and calculation. Scenarios are hidden from the plan list; manage their items and calculate them through the regular plan endpoints using the scenario's plan_id.
+//	@Tags			plans
+//	@Accept			json
+//	@Produce		json
+//	@Param			workspace_id	query		string						false	"Workspace override (founder only)"
+//	@Param			id				path		string						true	"Plan ID"
+//	@Param			request			body		dto.CreateScenarioRequest	true	"Scenario"
+//	@Success		201				{object}	response.APIResponse{data=dto.ScenarioResponse}
+//	@Failure		400				{object}	response.APIResponse
+//	@Failure		409				{object}	response.APIResponse
+//	@Security		BearerAuth
+//	@Router			/plans/{id}/scenarios [post]
+func (h *PlanHandler) CreateScenario(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		response.Error(c, http.StatusBadRequest, "Plan ID is required")
+		return
+	}
+
+	var req dto.CreateScenarioRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request format: "+err.Error())
+		return
+	}
+
+	withFounderWorkspaceOverride(c)
+
+	resp, err := h.planSvc.CreateScenario(c.Request.Context(), id, req)
+	if err != nil {
+		respondPlanServiceError(c, err, http.StatusBadRequest, "Failed to create scenario: ")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, resp)
+}
+
+// ListScenarios godoc
+//
+//	@Summary		List plan scenarios
+//	@Description	Lists the scenarios of a plan (or of its base plan when called with a scenario ID).
+//	@Tags			plans
+//	@Produce		json
+//	@Param			workspace_id	query		string	false	"Workspace override (founder only)"
+//	@Param			id				path		string	true	"Plan ID"
+//	@Success		200				{object}	response.APIResponse{data=[]dto.ScenarioResponse}
+//	@Failure		404				{object}	response.APIResponse
+//	@Security		BearerAuth
+//	@Router			/plans/{id}/scenarios [get]
+func (h *PlanHandler) ListScenarios(c *gin.Context) {
+	id := c.Param("id")
+
+	withFounderWorkspaceOverride(c)
+
+	resp, err := h.planSvc.ListScenarios(c.Request.Context(), id)
+	if err != nil {
+		respondPlanServiceError(c, err, http.StatusNotFound, "Failed to list scenarios: ")
+		return
+	}
+
+	response.Success(c, http.StatusOK, resp)
+}
+
+// CompareScenarios godoc
+//
+//	@Summary		Compare plan scenarios
+//	@Description	Shows the base plan and its scenarios side by side: container, stats and unfit items of each latest calculation.
+//	@Tags			plans
+//	@Produce		json
+//	@Param			workspace_id	query		string	false	"Workspace override (founder only)"
+//	@Param			id				path		string	true	"Plan ID"
+//	@Success		200				{object}	response.APIResponse{data=dto.ScenarioComparisonResponse}
+//	@Failure		404				{object}	response.APIResponse
+//	@Security		BearerAuth
+//	@Router			/plans/{id}/scenarios/compare [get]
+func (h *PlanHandler) CompareScenarios(c *gin.Context) {
+	id := c.Param("id")
+
+	withFounderWorkspaceOverride(c)
+
+	resp, err := h.planSvc.CompareScenarios(c.Request.Context(), id)
+	if err != nil {
+		respondPlanServiceError(c, err, http.StatusNotFound, "Failed to compare scenarios: ")
+		return
+	}
+
+	response.Success(c, http.StatusOK, resp)
+}
+
+// PreflightPlan godoc
+//
+//	@Summary		Check a plan before packing
+//	@Description	Finds items that cannot be placed in any allowed orientation or weigh more than the container allows, and totals over the container's weight or volume. Nothing is packed.
+//	@Tags			plans
+//	@Produce		json
+//	@Param			workspace_id	query		string	false	"Workspace override (founder only)"
+//	@Param			id				path		string	true	"Plan ID"
+//	@Success		200				{object}	response.APIResponse{data=dto.PreflightResponse}
+//	@Failure		404				{object}	response.APIResponse
+//	@Security		BearerAuth
+//	@Router			/plans/{id}/preflight [get]
+func (h *PlanHandler) PreflightPlan(c *gin.Context) {
+	id := c.Param("id")
+
+	withFounderWorkspaceOverride(c)
+
+	resp, err := h.planSvc.PreflightPlan(c.Request.Context(), id)
+	if err != nil {
+		respondPlanServiceError(c, err, http.StatusNotFound, "Failed to check plan: ")
+		return
+	}
+
+	response.Success(c, http.StatusOK, resp)
+}
+
+// CreateOverflowPlan godoc
+//
+//	@Summary		Create overflow plan
+//	@Description	Moves the units a calculated plan could not place into a new plan, in the same container, a given one or the smallest saved container that holds them. Both plans join one shipment group. A plan can be overflowed once; the new plan is calculated unless auto_calculate is false.
+//	@Tags			plans
+//	@Accept			json
+//	@Produce		json
+//	@Param			workspace_id	query		string							false	"Workspace override (founder only)"
+//	@Param			id				path		string							true	"Plan ID"
+//	@Param			request			body		dto.CreateOverflowPlanRequest	false	"Overflow options"
+//	@Success		201				{object}	response.APIResponse{data=dto.OverflowPlanResponse}
+//	@Failure		400				{object}	response.APIResponse
+//	@Failure		409				{object}	response.APIResponse
+//	@Failure		422				{object}	response.APIResponse
+//	@Security		BearerAuth
+//	@Router			/plans/{id}/overflow [post]
+func (h *PlanHandler) CreateOverflowPlan(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		response.Error(c, http.StatusBadRequest, "Plan ID is required")
+		return
+	}
+
+	var req dto.CreateOverflowPlanRequest
+	// Empty body is allowed: same container, calculated right away.
+	if err := c.ShouldBindJSON(&req); err != nil {
+		if !errors.Is(err, io.EOF) {
+			response.Error(c, http.StatusBadRequest, "Invalid request format: "+err.Error())
+			return
+		}
+		req = dto.CreateOverflowPlanRequest{}
+	}
+
+	withFounderWorkspaceOverride(c)
+
+	resp, err := h.planSvc.CreateOverflowPlan(c.Request.Context(), id, req)
+	if err != nil {
+		respondPlanServiceError(c, err, http.StatusBadRequest, "Failed to create overflow plan: ")
+		return
+	}
+
+	response.Success(c, http.StatusCreated, resp)
+}
+
+// GetShipmentGroup godoc
+//
+//	@Summary		Get plan shipment
+//	@Description	Lists every plan of the shipment the plan belongs to (the original plan and its overflow plans) with their stats, and how many units are still not placed anywhere.
+//	@Tags			plans
+//	@Produce		json
+//	@Param			workspace_id	query		string	false	"Workspace override (founder only)"
+//	@Param			id				path		string	true	"Plan ID"
+//	@Success		200				{object}	response.APIResponse{data=dto.ShipmentGroupResponse}
+//	@Failure		404				{object}	response.APIResponse
+//	@Security		BearerAuth
+//	@Router			/plans/{id}/shipment [get]
+func (h *PlanHandler) GetShipmentGroup(c *gin.Context) {
+	id := c.Param("id")
+
+	withFounderWorkspaceOverride(c)
+
+	resp, err := h.planSvc.GetShipmentGroup(c.Request.Context(), id)
+	if err != nil {
+		respondPlanServiceError(c, err, http.StatusNotFound, "Failed to get shipment: ")
+		return
+	}
+
+	response.Success(c, http.StatusOK, resp)
+}
+
+// GetPlanStatusHistory godoc
+//
+//	@Summary		Get plan status history
+//	@Description	Lists every status change of the plan, oldest first, with who made it and when.
+//	@Tags			plans
+//	@Produce		json
+//	@Param			workspace_id	query		string	false	"Workspace override (founder only)"
+//	@Param			id				path		string	true	"Plan ID"
+//	@Success		200				{object}	response.APIResponse{data=[]dto.PlanStatusChange}
+//	@Failure		404				{object}	response.APIResponse
+//	@Security		BearerAuth
+//	@Router			/plans/{id}/status-history [get]
+func (h *PlanHandler) GetPlanStatusHistory(c *gin.Context) {
+	id := c.Param("id")
+
+	withFounderWorkspaceOverride(c)
+
+	resp, err := h.planSvc.GetPlanStatusHistory(c.Request.Context(), id)
+	if err != nil {
+		respondPlanServiceError(c, err, http.StatusNotFound, "Failed to get status history: ")
+		return
+	}
+
+	response.Success(c, http.StatusOK, resp)
+}
+
+// ListResultVersions godoc
+//
+//	@Summary		List plan result versions
+//	@Description	Lists every saved calculation of the plan, newest first. The active version is the one the plan shows.
+//	@Tags			plans
+//	@Produce		json
+//	@Param			workspace_id	query		string	false	"Workspace override (founder only)"
+//	@Param			id				path		string	true	"Plan ID"
+//	@Success		200				{object}	response.APIResponse{data=[]dto.ResultVersion}
+//	@Failure		404				{object}	response.APIResponse
+//	@Security		BearerAuth
+//	@Router			/plans/{id}/results [get]
+func (h *PlanHandler) ListResultVersions(c *gin.Context) {
+	id := c.Param("id")
+
+	withFounderWorkspaceOverride(c)
+
+	resp, err := h.planSvc.ListResultVersions(c.Request.Context(), id)
+	if err != nil {
+		respondPlanServiceError(c, err, http.StatusNotFound, "Failed to list result versions: ")
+		return
+	}
+
+	response.Success(c, http.StatusOK, resp)
+}
+
+// GetResultVersion godoc
+//
+//	@Summary		Get a plan result version
+//	@Description	Returns one saved calculation of the plan with the inputs it was calculated from and its placements.
+//	@Tags			plans
+//	@Produce		json
+//	@Param			workspace_id	query		string	false	"Workspace override (founder only)"
+//	@Param			id				path		string	true	"Plan ID"
+//	@Param			version			path		int		true	"Result version"
+//	@Success		200				{object}	response.APIResponse{data=dto.ResultVersion}
+//	@Failure		400				{object}	response.APIResponse
+//	@Failure		404				{object}	response.APIResponse
+//	@Security		BearerAuth
+//	@Router			/plans/{id}/results/{version} [get]
+func (h *PlanHandler) GetResultVersion(c *gin.Context) {
+	id := c.Param("id")
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version < 1 {
+		response.Error(c, http.StatusBadRequest, "Invalid result version")
+		return
+	}
+
+	withFounderWorkspaceOverride(c)
+
+	resp, err := h.planSvc.GetResultVersion(c.Request.Context(), id, version)
+	if err != nil {
+		respondPlanServiceError(c, err, http.StatusNotFound, "Failed to get result version: ")
+		return
+	}
+
+	response.Success(c, http.StatusOK, resp)
+}
+
+// RestoreResultVersion godoc
+//
+//	@Summary		Restore a plan result version
+//	@Description	Makes a saved calculation the plan's active result. The plan's container and items must still match the version's inputs.
+//	@Tags			plans
+//	@Produce		json
+//	@Param			workspace_id	query		string	false	"Workspace override (founder only)"
+//	@Param			id				path		string	true	"Plan ID"
+//	@Param			version			path		int		true	"Result version"
+//	@Success		200				{object}	response.APIResponse{data=dto.ResultVersion}
+//	@Failure		400				{object}	response.APIResponse
+//	@Failure		404				{object}	response.APIResponse
+//	@Failure		409				{object}	response.APIResponse
+//	@Security		BearerAuth
+//	@Router			/plans/{id}/results/{version}/restore [post]
+func (h *PlanHandler) RestoreResultVersion(c *gin.Context) {
+	id := c.Param("id")
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version < 1 {
+		response.Error(c, http.StatusBadRequest, "Invalid result version")
+		return
+	}
+
+	withFounderWorkspaceOverride(c)
+
+	resp, err := h.planSvc.RestoreResultVersion(c.Request.Context(), id, version)
+	if err != nil {
+		respondPlanServiceError(c, err, http.StatusBadRequest, "Failed to restore result version: ")
+		return
+	}
+
+	response.Success(c, http.StatusOK, resp)
+}
+
+// DiffResultVersions godoc
+//
+//	@Summary		Diff two plan result versions
+//	@Description	Compares result version "to" against version "from": metrics, changed inputs, items whose definition or packed count differ, and how many units moved.
+//	@Tags			plans
+//	@Produce		json
+//	@Param			workspace_id	query		string	false	"Workspace override (founder only)"
+//	@Param			id				path		string	true	"Plan ID"
+//	@Param			from			query		int		true	"Base version"
+//	@Param			to				query		int		true	"Compared version"
+//	@Success		200				{object}	response.APIResponse{data=dto.ResultVersionDiff}
+//	@Failure		400				{object}	response.APIResponse
+//	@Failure		404				{object}	response.APIResponse
+//	@Security		BearerAuth
+//	@Router			/plans/{id}/results/diff [get]
+func (h *PlanHandler) DiffResultVersions(c *gin.Context) {
+	id := c.Param("id")
+	from, errFrom := strconv.Atoi(c.Query("from"))
+	to, errTo := strconv.Atoi(c.Query("to"))
+	if errFrom != nil || errTo != nil || from < 1 || to < 1 {
+		response.Error(c, http.StatusBadRequest, "from and to must be result versions")
+		return
+	}
+
+	withFounderWorkspaceOverride(c)
+
+	resp, err := h.planSvc.DiffResultVersions(c.Request.Context(), id, from, to)
+	if err != nil {
+		respondPlanServiceError(c, err, http.StatusNotFound, "Failed to diff result versions: ")
+		return
+	}
+
+	response.Success(c, http.StatusOK, resp)
+}
+
 // GetPlanBarcodes returns generated barcodes for all placements in a plan
 //
 //	@Summary		Get plan barcodes
@@ -425,64 +955,259 @@ func (h *PlanHandler) CalculatePlan(c *gin.Context) {
 func (h *PlanHandler) GetPlanBarcodes(c *gin.Context) {
 	planID := c.Param("id")
 
-	// Get plan with placements (reuse existing method)
-	plan, err := h.planSvc.GetPlan(c.Request.Context(), planID)
-	if err != nil {
-		response.Error(c, http.StatusNotFound, "Plan not found")
+	_, barcodes, ok := h.loadPlanBarcodes(c, planID)
+	if !ok {
 		return
 	}
 
-	planUUID, err := uuid.Parse(plan.PlanID)
+	response.Success(c, http.StatusOK, barcodes)
+}
+
+// GetPlanBarcodeImage renders the barcode of one loading step as an image
+//
+//	@Summary		Get barcode image
+//	@Description	Renders the barcode of a loading step as a QR code or Code128 image
+//	@Tags			plans
+//	@Produce		png
+//	@Produce		image/svg+xml
+//	@Param			workspace_id	query		string	false	"Workspace override (founder only)"
+//	@Param			id				path		string	true	"Plan ID"
+//	@Param			step			path		int		true	"Step number"
+//	@Param			symbology		query		string	false	"qr (default) or code128"
+//	@Param			format			query		string	false	"png (default) or svg"
+//	@Param			size			query		int		false	"Width in pixels (64-2048, default 256)"
+//	@Success		200				{file}		binary
+//	@Failure		400				{object}	response.APIResponse
+//	@Failure		404				{object}	response.APIResponse
+//	@Security		BearerAuth
+//	@Router			/plans/{id}/barcodes/{step}/image [get]
+func (h *PlanHandler) GetPlanBarcodeImage(c *gin.Context) {
+	planID := c.Param("id")
+
+	step, err := strconv.Atoi(c.Param("step"))
 	if err != nil {
-		response.Error(c, http.StatusInternalServerError, "Invalid plan ID in response")
+		response.Error(c, http.StatusBadRequest, "Invalid step number")
+		return
+	}
+	sym, err := label.ParseSymbology(c.Query("symbology"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	format, err := label.ParseFormat(c.Query("format"))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	size, err := strconv.Atoi(c.DefaultQuery("size", strconv.Itoa(label.DefaultSize)))
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid size")
 		return
 	}
 
-	// Generate barcodes
-	barcodes := make([]dto.BarcodeInfo, 0, len(plan.Calculation.Placements))
+	withFounderWorkspaceOverride(c)
 
-	for _, placement := range plan.Calculation.Placements {
-		// Skip placements without step numbers (shouldn't happen for valid placements)
-		if placement.StepNumber == 0 {
-			continue
-		}
+	_, barcodes, ok := h.loadPlanBarcodes(c, planID)
+	if !ok {
+		return
+	}
 
-		item := findItemByID(plan.Items, placement.ItemID)
-		if item == nil {
+	for _, b := range barcodes {
+		if b.StepNumber != step {
 			continue
 		}
-
-		itemUUID, err := uuid.Parse(placement.ItemID)
+		img, err := label.Render(b.Barcode, sym, format, size)
 		if err != nil {
-			continue
+			response.Error(c, http.StatusInternalServerError, "Failed to render barcode: "+err.Error())
+			return
 		}
+		c.Data(http.StatusOK, format.ContentType(), img)
+		return
+	}
 
-		barcode := generateBarcode(planUUID, placement.StepNumber, itemUUID)
-
-		barcodes = append(barcodes, dto.BarcodeInfo{
-			StepNumber: placement.StepNumber,
-			ItemID:     placement.ItemID,
-			ItemLabel:  *item.Label,
-			Barcode:    barcode,
-			Position: dto.Position{
-				X: placement.PositionX,
-				Y: placement.PositionY,
-				Z: placement.PositionZ,
-			},
-			Dimensions: dto.Dimensions{
-				Length: item.LengthMM,
-				Width:  item.WidthMM,
-				Height: item.HeightMM,
-			},
-		})
+	response.Error(c, http.StatusNotFound, "Step not found")
+}
+
+// GetPlanLabels renders printable labels for every loading step of a plan
+//
+//	@Summary		Get plan labels
+//	@Description	Renders the labels of all loading steps as an A4 PDF label sheet or as ZPL for Zebra printers
+//	@Tags			plans
+//	@Produce		application/pdf
+//	@Produce		application/zpl
+//	@Param			workspace_id	query		string	false	"Workspace override (founder only)"
+//	@Param			id				path		string	true	"Plan ID"
+//	@Param			format			query		string	false	"pdf (default) or zpl"
+//	@Success		200				{file}		binary
+//	@Failure		400				{object}	response.APIResponse
+//	@Failure		404				{object}	response.APIResponse
+//	@Security		BearerAuth
+//	@Router			/plans/{id}/labels [get]
+func (h *PlanHandler) GetPlanLabels(c *gin.Context) {
+	planID := c.Param("id")
+
+	format := c.DefaultQuery("format", "pdf")
+	if format != "pdf" && format != "zpl" {
+		response.Error(c, http.StatusBadRequest, "Format must be pdf or zpl")
+		return
 	}
 
-	// Sort by step number
-	sort.Slice(barcodes, func(i, j int) bool {
-		return barcodes[i].StepNumber < barcodes[j].StepNumber
-	})
+	withFounderWorkspaceOverride(c)
 
-	response.Success(c, http.StatusOK, barcodes)
+	plan, barcodes, ok := h.loadPlanBarcodes(c, planID)
+	if !ok {
+		return
+	}
+
+	filename := plan.PlanCode + "-labels." + format
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	if format == "zpl" {
+		c.Data(http.StatusOK, "application/zpl", label.ZPL(barcodes))
+		return
+	}
+
+	sheet, err := label.Sheet(plan.PlanCode+" labels", barcodes)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to render labels: "+err.Error())
+		return
+	}
+	c.Data(http.StatusOK, "application/pdf", sheet)
+}
+
+// AssignHandlingUnits godoc
+//
+//	@Summary		Assign SSCCs to handling units
+//	@Description	Gives every placed unit of the plan's active result that has none an SSCC-18 from the workspace's GS1 company prefix and returns all handling units of the result
+//	@Tags			plans
+//	@Produce		json
+//	@Param			workspace_id	query		string	false	"Workspace override (founder only)"
+//	@Param			id				path		string	true	"Plan ID"
+//	@Success		200				{object}	response.APIResponse{data=[]dto.HandlingUnit}
+//	@Failure		404				{object}	response.APIResponse
+//	@Failure		409				{object}	response.APIResponse
+//	@Security		BearerAuth
+//	@Router			/plans/{id}/handling-units [post]
+func (h *PlanHandler) AssignHandlingUnits(c *gin.Context) {
+	id := c.Param("id")
+
+	withFounderWorkspaceOverride(c)
+
+	resp, err := h.planSvc.AssignHandlingUnits(c.Request.Context(), id)
+	if err != nil {
+		respondPlanServiceError(c, err, http.StatusNotFound, "Failed to assign handling units: ")
+		return
+	}
+
+	response.Success(c, http.StatusOK, resp)
+}
+
+// ListHandlingUnits godoc
+//
+//	@Summary		List handling units
+//	@Description	Lists the SSCCs issued for the units of the plan's active result, in step order
+//	@Tags			plans
+//	@Produce		json
+//	@Param			workspace_id	query		string	false	"Workspace override (founder only)"
+//	@Param			id				path		string	true	"Plan ID"
+//	@Success		200				{object}	response.APIResponse{data=[]dto.HandlingUnit}
+//	@Failure		404				{object}	response.APIResponse
+//	@Failure		409				{object}	response.APIResponse
+//	@Security		BearerAuth
+//	@Router			/plans/{id}/handling-units [get]
+func (h *PlanHandler) ListHandlingUnits(c *gin.Context) {
+	id := c.Param("id")
+
+	withFounderWorkspaceOverride(c)
+
+	resp, err := h.planSvc.ListHandlingUnits(c.Request.Context(), id)
+	if err != nil {
+		respondPlanServiceError(c, err, http.StatusNotFound, "Failed to list handling units: ")
+		return
+	}
+
+	response.Success(c, http.StatusOK, resp)
+}
+
+// GetHandlingUnitLabels renders GS1 logistic labels for the handling units of a plan
+//
+//	@Summary		Get GS1-128 handling unit labels
+//	@Description	Renders a GS1 logistic label with the GS1-128 SSCC of each handling unit, as an A6 PDF or as ZPL for 4x6 inch Zebra labels
+//	@Tags			plans
+//	@Produce		application/pdf
+//	@Produce		application/zpl
+//	@Param			workspace_id	query		string	false	"Workspace override (founder only)"
+//	@Param			id				path		string	true	"Plan ID"
+//	@Param			format			query		string	false	"pdf (default) or zpl"
+//	@Success		200				{file}		binary
+//	@Failure		400				{object}	response.APIResponse
+//	@Failure		404				{object}	response.APIResponse
+//	@Failure		409				{object}	response.APIResponse
+//	@Security		BearerAuth
+//	@Router			/plans/{id}/handling-units/labels [get]
+func (h *PlanHandler) GetHandlingUnitLabels(c *gin.Context) {
+	planID := c.Param("id")
+
+	format := c.DefaultQuery("format", "pdf")
+	if format != "pdf" && format != "zpl" {
+		response.Error(c, http.StatusBadRequest, "Format must be pdf or zpl")
+		return
+	}
+
+	withFounderWorkspaceOverride(c)
+
+	plan, err := h.planSvc.GetPlan(c.Request.Context(), planID)
+	if err != nil {
+		response.Error(c, http.StatusNotFound, "Plan not found")
+		return
+	}
+	units, err := h.planSvc.ListHandlingUnits(c.Request.Context(), planID)
+	if err != nil {
+		respondPlanServiceError(c, err, http.StatusNotFound, "Failed to list handling units: ")
+		return
+	}
+	if len(units) == 0 {
+		response.Error(c, http.StatusConflict, "Plan has no handling units; assign SSCCs first")
+		return
+	}
+
+	filename := plan.PlanCode + "-sscc." + format
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	if format == "zpl" {
+		c.Data(http.StatusOK, "application/zpl", label.SSCCZPL(units))
+		return
+	}
+
+	sheet, err := label.SSCCSheet(plan.PlanCode, units)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Failed to render labels: "+err.Error())
+		return
+	}
+	c.Data(http.StatusOK, "application/pdf", sheet)
+}
+
+// loadPlanBarcodes fetches a plan and builds the label of each loading step
+// in step order. On failure it writes the error response and returns false.
+func (h *PlanHandler) loadPlanBarcodes(c *gin.Context, planID string) (*dto.PlanDetailResponse, []dto.BarcodeInfo, bool) {
+	plan, err := h.planSvc.GetPlan(c.Request.Context(), planID)
+	if err != nil {
+		response.Error(c, http.StatusNotFound, "Plan not found")
+		return nil, nil, false
+	}
+
+	planUUID, err := uuid.Parse(plan.PlanID)
+	if err != nil {
+		response.Error(c, http.StatusInternalServerError, "Invalid plan ID in response")
+		return nil, nil, false
+	}
+
+	barcodes := []dto.BarcodeInfo{}
+	if plan.Calculation != nil {
+		barcodes = service.PlanBarcodes(h.codec, planUUID, plan.Calculation.Placements, plan.Items)
+	}
+
+	return plan, barcodes, true
 }
 
 // ValidatePlanBarcode validates a scanned barcode against a plan
@@ -509,8 +1234,17 @@ func (h *PlanHandler) ValidatePlanBarcode(c *gin.Context) {
 	}
 
 	// Parse scanned barcode
-	parsed := parseBarcode(req.Barcode)
-	if parsed == nil {
+	parsed, err := h.codec.Parse(req.Barcode)
+	if errors.Is(err, barcode.ErrInvalidSignature) {
+		response.Success(c, http.StatusOK, dto.ValidationResult{
+			Valid:   false,
+			Status:  "INVALID_SIGNATURE",
+			Error:   "Barcode signature does not match",
+			Barcode: req.Barcode,
+		})
+		return
+	}
+	if err != nil {
 		response.Success(c, http.StatusOK, dto.ValidationResult{
 			Valid:  false,
 			Status: "INVALID_FORMAT",
@@ -526,8 +1260,7 @@ func (h *PlanHandler) ValidatePlanBarcode(c *gin.Context) {
 		return
 	}
 
-	planShort := planUUID.String()[:8]
-	if parsed.PlanID != planShort {
+	if !parsed.MatchesPlan(planUUID) {
 		response.Success(c, http.StatusOK, dto.ValidationResult{
 			Valid:      false,
 			Status:     "WRONG_PLAN",
@@ -567,46 +1300,3 @@ func (h *PlanHandler) ValidatePlanBarcode(c *gin.Context) {
 		Barcode:    req.Barcode,
 	})
 }
-
-// Helper: Generate deterministic barcode
-func generateBarcode(planID uuid.UUID, stepNumber int, itemID uuid.UUID) string {
-	planShort := planID.String()[:8]
-	itemShort := itemID.String()[:8]
-	return fmt.Sprintf("PLAN-%s-STEP-%03d-%s", planShort, stepNumber, itemShort)
-}
-
-// Helper: Parse scanned barcode
-func parseBarcode(barcode string) *ParsedBarcode {
-	parts := strings.Split(barcode, "-")
-	if len(parts) != 5 || parts[0] != "PLAN" || parts[2] != "STEP" {
-		return nil
-	}
-
-	stepNum, err := strconv.Atoi(parts[3])
-	if err != nil {
-		return nil
-	}
-
-	return &ParsedBarcode{
-		PlanID:     parts[1],
-		StepNumber: stepNum,
-		ItemID:     parts[4],
-	}
-}
-
-// Helper: Find item by ID
-func findItemByID(items []dto.PlanItemDetail, itemID string) *dto.PlanItemDetail {
-	for i := range items {
-		if items[i].ItemID == itemID {
-			return &items[i]
-		}
-	}
-	return nil
-}
-
-// Helper struct
-type ParsedBarcode struct {
-	PlanID     string
-	StepNumber int
-	ItemID     string
-}
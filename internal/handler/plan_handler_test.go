@@ -7,8 +7,10 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
+	"github.com/ekastn/load-stuffing-calculator/internal/barcode"
 	"github.com/ekastn/load-stuffing-calculator/internal/dto"
 	"github.com/ekastn/load-stuffing-calculator/internal/handler"
 	"github.com/ekastn/load-stuffing-calculator/internal/mocks"
@@ -17,6 +19,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestPlanHandler_CreatePlan(t *testing.T) {
@@ -34,7 +37,7 @@ func TestPlanHandler_CreatePlan(t *testing.T) {
 
 	t.Run("success", func(t *testing.T) {
 		mockSvc := new(mocks.MockPlanService)
-		h := handler.NewPlanHandler(mockSvc)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
 
 		req := dto.CreatePlanRequest{
 			Title: "Test Plan",
@@ -64,7 +67,7 @@ func TestPlanHandler_CreatePlan(t *testing.T) {
 
 	t.Run("bad_request", func(t *testing.T) {
 		mockSvc := new(mocks.MockPlanService)
-		h := handler.NewPlanHandler(mockSvc)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -78,7 +81,7 @@ func TestPlanHandler_CreatePlan(t *testing.T) {
 
 	t.Run("service_error", func(t *testing.T) {
 		mockSvc := new(mocks.MockPlanService)
-		h := handler.NewPlanHandler(mockSvc)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
 
 		req := dto.CreatePlanRequest{
 			Title: "Test Plan",
@@ -106,7 +109,7 @@ func TestPlanHandler_CreatePlan(t *testing.T) {
 
 	t.Run("trial_limit_reached", func(t *testing.T) {
 		mockSvc := new(mocks.MockPlanService)
-		h := handler.NewPlanHandler(mockSvc)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
 
 		req := dto.CreatePlanRequest{
 			Title: "Test Plan",
@@ -140,7 +143,7 @@ func TestPlanHandler_GetPlan(t *testing.T) {
 
 	t.Run("success", func(t *testing.T) {
 		mockSvc := new(mocks.MockPlanService)
-		h := handler.NewPlanHandler(mockSvc)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
 
 		expectedResp := &dto.PlanDetailResponse{
 			PlanID:   planID,
@@ -168,7 +171,7 @@ func TestPlanHandler_GetPlan(t *testing.T) {
 
 	t.Run("missing_id", func(t *testing.T) {
 		mockSvc := new(mocks.MockPlanService)
-		h := handler.NewPlanHandler(mockSvc)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -182,7 +185,7 @@ func TestPlanHandler_GetPlan(t *testing.T) {
 
 	t.Run("not_found", func(t *testing.T) {
 		mockSvc := new(mocks.MockPlanService)
-		h := handler.NewPlanHandler(mockSvc)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
 
 		mockSvc.On("GetPlan", mock.Anything, planID).Return(nil, errors.New("not found"))
 
@@ -203,7 +206,7 @@ func TestPlanHandler_ListPlans(t *testing.T) {
 
 	t.Run("success", func(t *testing.T) {
 		mockSvc := new(mocks.MockPlanService)
-		h := handler.NewPlanHandler(mockSvc)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
 
 		expectedResp := []dto.PlanListItem{{
 			PlanID:   uuid.New().String(),
@@ -224,7 +227,7 @@ func TestPlanHandler_ListPlans(t *testing.T) {
 
 	t.Run("service_error", func(t *testing.T) {
 		mockSvc := new(mocks.MockPlanService)
-		h := handler.NewPlanHandler(mockSvc)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
 
 		mockSvc.On("ListPlans", mock.Anything, int32(1), int32(10)).Return(nil, errors.New("db error"))
 
@@ -246,7 +249,7 @@ func TestPlanHandler_UpdatePlan(t *testing.T) {
 
 	t.Run("success", func(t *testing.T) {
 		mockSvc := new(mocks.MockPlanService)
-		h := handler.NewPlanHandler(mockSvc)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
 
 		req := dto.UpdatePlanRequest{
 			Status: stringPtr("COMPLETED"),
@@ -268,7 +271,7 @@ func TestPlanHandler_UpdatePlan(t *testing.T) {
 
 	t.Run("missing_id", func(t *testing.T) {
 		mockSvc := new(mocks.MockPlanService)
-		h := handler.NewPlanHandler(mockSvc)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
 
 		req := dto.UpdatePlanRequest{Status: stringPtr("COMPLETED")}
 
@@ -286,7 +289,7 @@ func TestPlanHandler_UpdatePlan(t *testing.T) {
 
 	t.Run("invalid_json", func(t *testing.T) {
 		mockSvc := new(mocks.MockPlanService)
-		h := handler.NewPlanHandler(mockSvc)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -301,7 +304,7 @@ func TestPlanHandler_UpdatePlan(t *testing.T) {
 
 	t.Run("service_error", func(t *testing.T) {
 		mockSvc := new(mocks.MockPlanService)
-		h := handler.NewPlanHandler(mockSvc)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
 
 		req := dto.UpdatePlanRequest{Status: stringPtr("COMPLETED")}
 		mockSvc.On("UpdatePlan", mock.Anything, planID, req).Return(errors.New("db error"))
@@ -321,7 +324,7 @@ func TestPlanHandler_UpdatePlan(t *testing.T) {
 
 	t.Run("forbidden", func(t *testing.T) {
 		mockSvc := new(mocks.MockPlanService)
-		h := handler.NewPlanHandler(mockSvc)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
 
 		req := dto.UpdatePlanRequest{Status: stringPtr("COMPLETED")}
 		mockSvc.On("UpdatePlan", mock.Anything, planID, req).Return(service.ErrForbidden)
@@ -338,6 +341,27 @@ func TestPlanHandler_UpdatePlan(t *testing.T) {
 		assert.Equal(t, http.StatusForbidden, w.Code)
 		mockSvc.AssertExpectations(t)
 	})
+
+	t.Run("invalid_status_transition", func(t *testing.T) {
+		mockSvc := new(mocks.MockPlanService)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
+
+		req := dto.UpdatePlanRequest{Status: stringPtr("COMPLETED")}
+		mockSvc.On("UpdatePlan", mock.Anything, planID, req).Return(fmt.Errorf("%w: DRAFT to COMPLETED", service.ErrInvalidStatusTransition))
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		jsonBytes, _ := json.Marshal(req)
+		c.Request = httptest.NewRequest(http.MethodPut, "/plans/"+planID, bytes.NewBuffer(jsonBytes))
+		c.Params = gin.Params{{Key: "id", Value: planID}}
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		h.UpdatePlan(c)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), "DRAFT to COMPLETED")
+		mockSvc.AssertExpectations(t)
+	})
 }
 
 func TestPlanHandler_DeletePlan(t *testing.T) {
@@ -347,7 +371,7 @@ func TestPlanHandler_DeletePlan(t *testing.T) {
 
 	t.Run("success", func(t *testing.T) {
 		mockSvc := new(mocks.MockPlanService)
-		h := handler.NewPlanHandler(mockSvc)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
 
 		mockSvc.On("DeletePlan", mock.Anything, planID).Return(nil)
 
@@ -364,7 +388,7 @@ func TestPlanHandler_DeletePlan(t *testing.T) {
 
 	t.Run("missing_id", func(t *testing.T) {
 		mockSvc := new(mocks.MockPlanService)
-		h := handler.NewPlanHandler(mockSvc)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -378,7 +402,7 @@ func TestPlanHandler_DeletePlan(t *testing.T) {
 
 	t.Run("service_error", func(t *testing.T) {
 		mockSvc := new(mocks.MockPlanService)
-		h := handler.NewPlanHandler(mockSvc)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
 
 		mockSvc.On("DeletePlan", mock.Anything, planID).Return(errors.New("db error"))
 
@@ -395,7 +419,7 @@ func TestPlanHandler_DeletePlan(t *testing.T) {
 
 	t.Run("forbidden", func(t *testing.T) {
 		mockSvc := new(mocks.MockPlanService)
-		h := handler.NewPlanHandler(mockSvc)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
 
 		mockSvc.On("DeletePlan", mock.Anything, planID).Return(service.ErrForbidden)
 
@@ -429,7 +453,7 @@ func TestPlanHandler_AddPlanItem(t *testing.T) {
 
 	t.Run("success", func(t *testing.T) {
 		mockSvc := new(mocks.MockPlanService)
-		h := handler.NewPlanHandler(mockSvc)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
 
 		expectedResp := &dto.PlanItemDetail{ItemID: uuid.New().String(), Label: &itemLabel}
 		mockSvc.On("AddPlanItem", mock.Anything, planID, itemReq).Return(expectedResp, nil)
@@ -449,7 +473,7 @@ func TestPlanHandler_AddPlanItem(t *testing.T) {
 
 	t.Run("missing_id", func(t *testing.T) {
 		mockSvc := new(mocks.MockPlanService)
-		h := handler.NewPlanHandler(mockSvc)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -465,7 +489,7 @@ func TestPlanHandler_AddPlanItem(t *testing.T) {
 
 	t.Run("invalid_json", func(t *testing.T) {
 		mockSvc := new(mocks.MockPlanService)
-		h := handler.NewPlanHandler(mockSvc)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -480,7 +504,7 @@ func TestPlanHandler_AddPlanItem(t *testing.T) {
 
 	t.Run("service_error", func(t *testing.T) {
 		mockSvc := new(mocks.MockPlanService)
-		h := handler.NewPlanHandler(mockSvc)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
 
 		mockSvc.On("AddPlanItem", mock.Anything, planID, itemReq).Return(nil, errors.New("db error"))
 
@@ -499,7 +523,7 @@ func TestPlanHandler_AddPlanItem(t *testing.T) {
 
 	t.Run("forbidden", func(t *testing.T) {
 		mockSvc := new(mocks.MockPlanService)
-		h := handler.NewPlanHandler(mockSvc)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
 
 		mockSvc.On("AddPlanItem", mock.Anything, planID, itemReq).Return(nil, service.ErrForbidden)
 
@@ -526,7 +550,7 @@ func TestPlanHandler_GetPlanItem(t *testing.T) {
 
 	t.Run("success", func(t *testing.T) {
 		mockSvc := new(mocks.MockPlanService)
-		h := handler.NewPlanHandler(mockSvc)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
 
 		expectedResp := &dto.PlanItemDetail{ItemID: itemID, Label: &itemLabel}
 		mockSvc.On("GetPlanItem", mock.Anything, planID, itemID).Return(expectedResp, nil)
@@ -544,7 +568,7 @@ func TestPlanHandler_GetPlanItem(t *testing.T) {
 
 	t.Run("missing_ids", func(t *testing.T) {
 		mockSvc := new(mocks.MockPlanService)
-		h := handler.NewPlanHandler(mockSvc)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -558,7 +582,7 @@ func TestPlanHandler_GetPlanItem(t *testing.T) {
 
 	t.Run("not_found", func(t *testing.T) {
 		mockSvc := new(mocks.MockPlanService)
-		h := handler.NewPlanHandler(mockSvc)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
 
 		mockSvc.On("GetPlanItem", mock.Anything, planID, itemID).Return(nil, errors.New("not found"))
 
@@ -584,7 +608,7 @@ func TestPlanHandler_UpdatePlanItem(t *testing.T) {
 
 	t.Run("missing_ids", func(t *testing.T) {
 		mockSvc := new(mocks.MockPlanService)
-		h := handler.NewPlanHandler(mockSvc)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -600,7 +624,7 @@ func TestPlanHandler_UpdatePlanItem(t *testing.T) {
 
 	t.Run("invalid_json", func(t *testing.T) {
 		mockSvc := new(mocks.MockPlanService)
-		h := handler.NewPlanHandler(mockSvc)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -615,7 +639,7 @@ func TestPlanHandler_UpdatePlanItem(t *testing.T) {
 
 	t.Run("service_error", func(t *testing.T) {
 		mockSvc := new(mocks.MockPlanService)
-		h := handler.NewPlanHandler(mockSvc)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
 
 		mockSvc.On("UpdatePlanItem", mock.Anything, planID, itemID, updateReq).Return(errors.New("db error"))
 
@@ -641,7 +665,7 @@ func TestPlanHandler_DeletePlanItem(t *testing.T) {
 
 	t.Run("missing_ids", func(t *testing.T) {
 		mockSvc := new(mocks.MockPlanService)
-		h := handler.NewPlanHandler(mockSvc)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -655,7 +679,7 @@ func TestPlanHandler_DeletePlanItem(t *testing.T) {
 
 	t.Run("service_error", func(t *testing.T) {
 		mockSvc := new(mocks.MockPlanService)
-		h := handler.NewPlanHandler(mockSvc)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
 
 		mockSvc.On("DeletePlanItem", mock.Anything, planID, itemID).Return(errors.New("db error"))
 
@@ -678,7 +702,7 @@ func TestPlanHandler_CalculatePlan(t *testing.T) {
 
 	t.Run("success", func(t *testing.T) {
 		mockSvc := new(mocks.MockPlanService)
-		h := handler.NewPlanHandler(mockSvc)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
 
 		expectedResp := &dto.CalculationResult{
 			JobID:           uuid.New().String(),
@@ -701,7 +725,7 @@ func TestPlanHandler_CalculatePlan(t *testing.T) {
 
 	t.Run("missing_id", func(t *testing.T) {
 		mockSvc := new(mocks.MockPlanService)
-		h := handler.NewPlanHandler(mockSvc)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
 
 		w := httptest.NewRecorder()
 		c, _ := gin.CreateTestContext(w)
@@ -715,7 +739,7 @@ func TestPlanHandler_CalculatePlan(t *testing.T) {
 
 	t.Run("error_calc_failed", func(t *testing.T) {
 		mockSvc := new(mocks.MockPlanService)
-		h := handler.NewPlanHandler(mockSvc)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
 
 		mockSvc.On("CalculatePlan", mock.Anything, planID, mock.Anything).Return(nil, errors.New("packing failed"))
 
@@ -732,7 +756,7 @@ func TestPlanHandler_CalculatePlan(t *testing.T) {
 
 	t.Run("forbidden", func(t *testing.T) {
 		mockSvc := new(mocks.MockPlanService)
-		h := handler.NewPlanHandler(mockSvc)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
 
 		mockSvc.On("CalculatePlan", mock.Anything, planID, mock.Anything).Return(nil, service.ErrForbidden)
 
@@ -748,6 +772,74 @@ func TestPlanHandler_CalculatePlan(t *testing.T) {
 	})
 }
 
+func TestPlanHandler_StreamCalculation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	planID := uuid.New().String()
+
+	t.Run("streams_progress_then_result", func(t *testing.T) {
+		mockSvc := new(mocks.MockPlanService)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
+
+		expectedResp := &dto.CalculationResult{JobID: uuid.New().String(), Status: "PLANNED"}
+		mockSvc.On("CalculatePlanWithProgress", mock.Anything, planID, dto.CalculatePlanRequest{Strategy: "parallel", Goal: "tightest"}, mock.Anything).
+			Run(func(args mock.Arguments) {
+				onProgress := args.Get(3).(func(dto.CalculationProgress))
+				onProgress(dto.CalculationProgress{Stage: "started", ItemsTotal: 2})
+				onProgress(dto.CalculationProgress{Stage: "placing", ItemsTotal: 2, ItemsPlaced: 2})
+			}).
+			Return(expectedResp, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/plans/"+planID+"/calculate/stream?strategy=parallel&goal=tightest", nil)
+		c.Params = gin.Params{{Key: "id", Value: planID}}
+
+		h.StreamCalculation(c)
+
+		body := w.Body.String()
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Type"), "text/event-stream")
+		assert.Equal(t, 2, strings.Count(body, "event:progress"))
+		assert.Contains(t, body, "event:result")
+		assert.Contains(t, body, expectedResp.JobID)
+		assert.Less(t, strings.LastIndex(body, "event:progress"), strings.Index(body, "event:result"))
+		mockSvc.AssertExpectations(t)
+	})
+
+	t.Run("streams_error_event", func(t *testing.T) {
+		mockSvc := new(mocks.MockPlanService)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
+
+		mockSvc.On("CalculatePlanWithProgress", mock.Anything, planID, mock.Anything, mock.Anything).Return(nil, errors.New("packing failed"))
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/plans/"+planID+"/calculate/stream", nil)
+		c.Params = gin.Params{{Key: "id", Value: planID}}
+
+		h.StreamCalculation(c)
+
+		assert.Contains(t, w.Body.String(), "event:error")
+		assert.Contains(t, w.Body.String(), "packing failed")
+		mockSvc.AssertExpectations(t)
+	})
+
+	t.Run("missing_id", func(t *testing.T) {
+		mockSvc := new(mocks.MockPlanService)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/plans//calculate/stream", nil)
+		c.Params = gin.Params{{Key: "id", Value: ""}}
+
+		h.StreamCalculation(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
 func TestPlanHandler_GetPlanBarcodes(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -757,7 +849,7 @@ func TestPlanHandler_GetPlanBarcodes(t *testing.T) {
 
 	t.Run("success", func(t *testing.T) {
 		mockSvc := new(mocks.MockPlanService)
-		h := handler.NewPlanHandler(mockSvc)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
 
 		plan := &dto.PlanDetailResponse{
 			PlanID: planID,
@@ -790,16 +882,19 @@ func TestPlanHandler_GetPlanBarcodes(t *testing.T) {
 		assert.Equal(t, 1, resp.Data[0].StepNumber)
 		assert.Equal(t, itemLabel, resp.Data[0].ItemLabel)
 
-		// Verify deterministic barcode format: PLAN-{8}-STEP-{3}-{8}
-		expectedBarcode := fmt.Sprintf("PLAN-%s-STEP-001-%s", planID[:8], itemID[:8])
-		assert.Equal(t, expectedBarcode, resp.Data[0].Barcode)
+		// Labels are signed and carry the full plan and item IDs
+		parsed, err := testCodec.Parse(resp.Data[0].Barcode)
+		require.NoError(t, err)
+		assert.Equal(t, planID, parsed.PlanID)
+		assert.Equal(t, 1, parsed.StepNumber)
+		assert.Equal(t, itemID, parsed.ItemID)
 
 		mockSvc.AssertExpectations(t)
 	})
 
 	t.Run("plan_not_found", func(t *testing.T) {
 		mockSvc := new(mocks.MockPlanService)
-		h := handler.NewPlanHandler(mockSvc)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
 
 		mockSvc.On("GetPlan", mock.Anything, planID).Return(nil, errors.New("not found"))
 
@@ -815,6 +910,119 @@ func TestPlanHandler_GetPlanBarcodes(t *testing.T) {
 	})
 }
 
+func TestPlanHandler_GetPlanBarcodeImage(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	planID := uuid.New().String()
+	itemID := uuid.New().String()
+	itemLabel := "Test Item"
+	plan := &dto.PlanDetailResponse{
+		PlanID:   planID,
+		PlanCode: "PLN-1",
+		Items:    []dto.PlanItemDetail{{ItemID: itemID, Label: &itemLabel}},
+		Calculation: &dto.CalculationResult{
+			Placements: []dto.PlacementDetail{{ItemID: itemID, StepNumber: 1}},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		step        string
+		query       string
+		want        int
+		contentType string
+	}{
+		{"qr_png", "1", "", http.StatusOK, "image/png"},
+		{"code128_svg", "1", "?symbology=code128&format=svg", http.StatusOK, "image/svg+xml"},
+		{"unknown_step", "2", "", http.StatusNotFound, ""},
+		{"unknown_symbology", "1", "?symbology=ean13", http.StatusBadRequest, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := new(mocks.MockPlanService)
+			h := handler.NewPlanHandler(mockSvc, testCodec)
+
+			mockSvc.On("GetPlan", mock.Anything, planID).Return(plan, nil).Maybe()
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/plans/"+planID+"/barcodes/"+tt.step+"/image"+tt.query, nil)
+			c.Params = gin.Params{{Key: "id", Value: planID}, {Key: "step", Value: tt.step}}
+
+			h.GetPlanBarcodeImage(c)
+
+			assert.Equal(t, tt.want, w.Code)
+			if tt.contentType != "" {
+				assert.Equal(t, tt.contentType, w.Header().Get("Content-Type"))
+				assert.NotEmpty(t, w.Body.Bytes())
+			}
+		})
+	}
+}
+
+func TestPlanHandler_GetPlanLabels(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	planID := uuid.New().String()
+	itemID := uuid.New().String()
+	itemLabel := "Test Item"
+	sku := "SKU-1"
+	plan := &dto.PlanDetailResponse{
+		PlanID:   planID,
+		PlanCode: "PLN-1",
+		Items:    []dto.PlanItemDetail{{ItemID: itemID, Label: &itemLabel, ProductSKU: &sku}},
+		Calculation: &dto.CalculationResult{
+			Placements: []dto.PlacementDetail{{ItemID: itemID, StepNumber: 1}},
+		},
+	}
+
+	newCtx := func(query string) (*httptest.ResponseRecorder, *gin.Context) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/plans/"+planID+"/labels"+query, nil)
+		c.Params = gin.Params{{Key: "id", Value: planID}}
+		return w, c
+	}
+
+	t.Run("pdf", func(t *testing.T) {
+		mockSvc := new(mocks.MockPlanService)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
+		mockSvc.On("GetPlan", mock.Anything, planID).Return(plan, nil)
+
+		w, c := newCtx("")
+		h.GetPlanLabels(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/pdf", w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Header().Get("Content-Disposition"), "PLN-1-labels.pdf")
+		assert.True(t, strings.HasPrefix(w.Body.String(), "%PDF"))
+	})
+
+	t.Run("zpl", func(t *testing.T) {
+		mockSvc := new(mocks.MockPlanService)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
+		mockSvc.On("GetPlan", mock.Anything, planID).Return(plan, nil)
+
+		w, c := newCtx("?format=zpl")
+		h.GetPlanLabels(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "^FDSTEP 001^FS")
+		assert.Contains(t, w.Body.String(), "^FDSKU SKU-1^FS")
+	})
+
+	t.Run("unknown_format", func(t *testing.T) {
+		mockSvc := new(mocks.MockPlanService)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
+
+		w, c := newCtx("?format=docx")
+		h.GetPlanLabels(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockSvc.AssertNotCalled(t, "GetPlan", mock.Anything, mock.Anything)
+	})
+}
+
 func TestPlanHandler_ValidatePlanBarcode(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -825,7 +1033,7 @@ func TestPlanHandler_ValidatePlanBarcode(t *testing.T) {
 
 	t.Run("success_matched", func(t *testing.T) {
 		mockSvc := new(mocks.MockPlanService)
-		h := handler.NewPlanHandler(mockSvc)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
 
 		req := dto.ValidateBarcodeRequest{
 			Barcode:      validBarcode,
@@ -852,7 +1060,7 @@ func TestPlanHandler_ValidatePlanBarcode(t *testing.T) {
 
 	t.Run("out_of_sequence", func(t *testing.T) {
 		mockSvc := new(mocks.MockPlanService)
-		h := handler.NewPlanHandler(mockSvc)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
 
 		// Barcode is for step 1, but we expect step 2
 		req := dto.ValidateBarcodeRequest{
@@ -880,7 +1088,7 @@ func TestPlanHandler_ValidatePlanBarcode(t *testing.T) {
 
 	t.Run("wrong_plan", func(t *testing.T) {
 		mockSvc := new(mocks.MockPlanService)
-		h := handler.NewPlanHandler(mockSvc)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
 
 		otherPlanID := uuid.New().String()
 		wrongBarcode := fmt.Sprintf("PLAN-%s-STEP-%03d-%s", otherPlanID[:8], step, itemID[:8])
@@ -910,7 +1118,7 @@ func TestPlanHandler_ValidatePlanBarcode(t *testing.T) {
 
 	t.Run("invalid_format", func(t *testing.T) {
 		mockSvc := new(mocks.MockPlanService)
-		h := handler.NewPlanHandler(mockSvc)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
 
 		req := dto.ValidateBarcodeRequest{
 			Barcode: "INVALID-BARCODE",
@@ -933,8 +1141,603 @@ func TestPlanHandler_ValidatePlanBarcode(t *testing.T) {
 		assert.False(t, resp.Data.Valid)
 		assert.Equal(t, "INVALID_FORMAT", resp.Data.Status)
 	})
+
+	signedCases := []struct {
+		name    string
+		barcode string
+		valid   bool
+		status  string
+	}{
+		{"signed_matched", testCodec.Generate(uuid.MustParse(planID), step, uuid.MustParse(itemID)), true, "MATCHED"},
+		{"signed_wrong_plan", testCodec.Generate(uuid.New(), step, uuid.MustParse(itemID)), false, "WRONG_PLAN"},
+		{"invalid_signature", barcode.NewCodec("forged", true).Generate(uuid.MustParse(planID), step, uuid.MustParse(itemID)), false, "INVALID_SIGNATURE"},
+	}
+	for _, tt := range signedCases {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := new(mocks.MockPlanService)
+			h := handler.NewPlanHandler(mockSvc, testCodec)
+
+			jsonBytes, _ := json.Marshal(dto.ValidateBarcodeRequest{Barcode: tt.barcode, ExpectedStep: intPtr(1)})
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPost, "/plans/"+planID+"/validations", bytes.NewBuffer(jsonBytes))
+			c.Params = gin.Params{{Key: "id", Value: planID}}
+
+			h.ValidatePlanBarcode(c)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+
+			var resp struct {
+				Data dto.ValidationResult `json:"data"`
+			}
+			json.Unmarshal(w.Body.Bytes(), &resp)
+			assert.Equal(t, tt.valid, resp.Data.Valid)
+			assert.Equal(t, tt.status, resp.Data.Status)
+		})
+	}
+
+	t.Run("legacy_rejected", func(t *testing.T) {
+		mockSvc := new(mocks.MockPlanService)
+		h := handler.NewPlanHandler(mockSvc, barcode.NewCodec("test-secret", false))
+
+		jsonBytes, _ := json.Marshal(dto.ValidateBarcodeRequest{Barcode: validBarcode})
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/plans/"+planID+"/validations", bytes.NewBuffer(jsonBytes))
+		c.Params = gin.Params{{Key: "id", Value: planID}}
+
+		h.ValidatePlanBarcode(c)
+
+		var resp struct {
+			Data dto.ValidationResult `json:"data"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		assert.False(t, resp.Data.Valid)
+		assert.Equal(t, "INVALID_FORMAT", resp.Data.Status)
+	})
 }
 
 func intPtr(i int) *int {
 	return &i
 }
+
+func TestPlanHandler_AdoptAlternative(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	planID := uuid.New().String()
+	altID := uuid.New().String()
+
+	newCtx := func() (*httptest.ResponseRecorder, *gin.Context) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/plans/"+planID+"/alternatives/"+altID+"/adopt", nil)
+		c.Params = gin.Params{{Key: "id", Value: planID}, {Key: "alternativeId", Value: altID}}
+		return w, c
+	}
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{name: "success", wantStatus: http.StatusOK},
+		{name: "expired", err: service.ErrAlternativeNotFound, wantStatus: http.StatusNotFound},
+		{name: "stale", err: fmt.Errorf("%w: items changed", service.ErrStaleAlternative), wantStatus: http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := new(mocks.MockPlanService)
+			h := handler.NewPlanHandler(mockSvc, testCodec)
+
+			if tt.err != nil {
+				mockSvc.On("AdoptAlternative", mock.Anything, planID, altID).Return(nil, tt.err)
+			} else {
+				mockSvc.On("AdoptAlternative", mock.Anything, planID, altID).Return(&dto.CalculationResult{Status: "PLANNED"}, nil)
+			}
+
+			w, c := newCtx()
+			h.AdoptAlternative(c)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			mockSvc.AssertExpectations(t)
+		})
+	}
+}
+
+func TestPlanHandler_ComparePlan(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	planID := uuid.New().String()
+	req := dto.ComparePlanRequest{
+		Candidates: []dto.CalculatePlanRequest{{Strategy: "bestfitdecreasing"}, {Backend: "native"}},
+	}
+
+	newCtx := func(body []byte) (*httptest.ResponseRecorder, *gin.Context) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/plans/"+planID+"/compare", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Params = gin.Params{{Key: "id", Value: planID}}
+		return w, c
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockSvc := new(mocks.MockPlanService)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
+
+		expected := &dto.ComparePlanResponse{
+			PlanID:       planID,
+			Alternatives: []dto.PlanAlternative{{Rank: 1}, {Rank: 2}},
+		}
+		mockSvc.On("ComparePlan", mock.Anything, planID, req).Return(expected, nil)
+
+		body, _ := json.Marshal(req)
+		w, c := newCtx(body)
+		h.ComparePlan(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockSvc.AssertExpectations(t)
+	})
+
+	t.Run("no_candidates", func(t *testing.T) {
+		mockSvc := new(mocks.MockPlanService)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
+
+		w, c := newCtx([]byte(`{"candidates":[]}`))
+		h.ComparePlan(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockSvc.AssertNotCalled(t, "ComparePlan", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("unknown_backend", func(t *testing.T) {
+		mockSvc := new(mocks.MockPlanService)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
+
+		mockSvc.On("ComparePlan", mock.Anything, planID, req).
+			Return(nil, fmt.Errorf("%w: %q", service.ErrUnknownBackend, "nope"))
+
+		body, _ := json.Marshal(req)
+		w, c := newCtx(body)
+		h.ComparePlan(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("forbidden", func(t *testing.T) {
+		mockSvc := new(mocks.MockPlanService)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
+
+		mockSvc.On("ComparePlan", mock.Anything, planID, req).Return(nil, service.ErrForbidden)
+
+		body, _ := json.Marshal(req)
+		w, c := newCtx(body)
+		h.ComparePlan(c)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+}
+
+func TestPlanHandler_CreateScenario(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	planID := uuid.New().String()
+	req := dto.CreateScenarioRequest{Name: "20GP without optional SKUs"}
+
+	newCtx := func(body []byte) (*httptest.ResponseRecorder, *gin.Context) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/plans/"+planID+"/scenarios", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Params = gin.Params{{Key: "id", Value: planID}}
+		return w, c
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockSvc := new(mocks.MockPlanService)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
+
+		mockSvc.On("CreateScenario", mock.Anything, planID, req).
+			Return(&dto.ScenarioResponse{PlanID: uuid.New().String(), ParentPlanID: planID, Name: req.Name}, nil)
+
+		body, _ := json.Marshal(req)
+		w, c := newCtx(body)
+		h.CreateScenario(c)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		mockSvc.AssertExpectations(t)
+	})
+
+	t.Run("missing_name", func(t *testing.T) {
+		mockSvc := new(mocks.MockPlanService)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
+
+		w, c := newCtx([]byte(`{}`))
+		h.CreateScenario(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("limit_reached", func(t *testing.T) {
+		mockSvc := new(mocks.MockPlanService)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
+
+		mockSvc.On("CreateScenario", mock.Anything, planID, req).Return(nil, service.ErrScenarioLimitReached)
+
+		body, _ := json.Marshal(req)
+		w, c := newCtx(body)
+		h.CreateScenario(c)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("unknown_item", func(t *testing.T) {
+		mockSvc := new(mocks.MockPlanService)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
+
+		mockSvc.On("CreateScenario", mock.Anything, planID, req).Return(nil, service.ErrInvalidScenarioItems)
+
+		body, _ := json.Marshal(req)
+		w, c := newCtx(body)
+		h.CreateScenario(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestPlanHandler_CompareScenarios(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	planID := uuid.New().String()
+
+	t.Run("success", func(t *testing.T) {
+		mockSvc := new(mocks.MockPlanService)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
+
+		mockSvc.On("CompareScenarios", mock.Anything, planID).
+			Return(&dto.ScenarioComparisonResponse{BasePlanID: planID}, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/plans/"+planID+"/scenarios/compare", nil)
+		c.Params = gin.Params{{Key: "id", Value: planID}}
+
+		h.CompareScenarios(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockSvc.AssertExpectations(t)
+	})
+
+	t.Run("not_found", func(t *testing.T) {
+		mockSvc := new(mocks.MockPlanService)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
+
+		mockSvc.On("CompareScenarios", mock.Anything, planID).Return(nil, errors.New("no rows"))
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/plans/"+planID+"/scenarios/compare", nil)
+		c.Params = gin.Params{{Key: "id", Value: planID}}
+
+		h.CompareScenarios(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestPlanHandler_PreflightPlan(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	planID := uuid.New().String()
+
+	t.Run("success", func(t *testing.T) {
+		mockSvc := new(mocks.MockPlanService)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
+
+		mockSvc.On("PreflightPlan", mock.Anything, planID).
+			Return(&dto.PreflightResponse{PlanID: planID, OK: true}, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/plans/"+planID+"/preflight", nil)
+		c.Params = gin.Params{{Key: "id", Value: planID}}
+
+		h.PreflightPlan(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockSvc.AssertExpectations(t)
+	})
+
+	t.Run("not_found", func(t *testing.T) {
+		mockSvc := new(mocks.MockPlanService)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
+
+		mockSvc.On("PreflightPlan", mock.Anything, planID).Return(nil, errors.New("no rows"))
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/plans/"+planID+"/preflight", nil)
+		c.Params = gin.Params{{Key: "id", Value: planID}}
+
+		h.PreflightPlan(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestPlanHandler_CreateOverflowPlan(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	planID := uuid.New().String()
+
+	t.Run("empty_body", func(t *testing.T) {
+		mockSvc := new(mocks.MockPlanService)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
+
+		mockSvc.On("CreateOverflowPlan", mock.Anything, planID, dto.CreateOverflowPlanRequest{}).
+			Return(&dto.OverflowPlanResponse{PlanID: uuid.New().String(), OverflowOfPlanID: planID}, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/plans/"+planID+"/overflow", nil)
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Params = gin.Params{{Key: "id", Value: planID}}
+
+		h.CreateOverflowPlan(c)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		mockSvc.AssertExpectations(t)
+	})
+
+	t.Run("suggest_container", func(t *testing.T) {
+		mockSvc := new(mocks.MockPlanService)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
+
+		mockSvc.On("CreateOverflowPlan", mock.Anything, planID, mock.MatchedBy(func(req dto.CreateOverflowPlanRequest) bool {
+			return req.SuggestContainer && req.AutoCalculate != nil && !*req.AutoCalculate
+		})).Return(&dto.OverflowPlanResponse{ContainerSuggested: true}, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		body := strings.NewReader(`{"suggest_container":true,"auto_calculate":false}`)
+		c.Request = httptest.NewRequest(http.MethodPost, "/plans/"+planID+"/overflow", body)
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Params = gin.Params{{Key: "id", Value: planID}}
+
+		h.CreateOverflowPlan(c)
+
+		assert.Equal(t, http.StatusCreated, w.Code)
+		mockSvc.AssertExpectations(t)
+	})
+
+	t.Run("nothing_to_overflow", func(t *testing.T) {
+		mockSvc := new(mocks.MockPlanService)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
+
+		mockSvc.On("CreateOverflowPlan", mock.Anything, planID, mock.Anything).Return(nil, service.ErrNothingToOverflow)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/plans/"+planID+"/overflow", nil)
+		c.Params = gin.Params{{Key: "id", Value: planID}}
+
+		h.CreateOverflowPlan(c)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("overflow_exists", func(t *testing.T) {
+		mockSvc := new(mocks.MockPlanService)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
+
+		mockSvc.On("CreateOverflowPlan", mock.Anything, planID, mock.Anything).Return(nil, service.ErrOverflowExists)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/plans/"+planID+"/overflow", nil)
+		c.Params = gin.Params{{Key: "id", Value: planID}}
+
+		h.CreateOverflowPlan(c)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("no_suitable_container", func(t *testing.T) {
+		mockSvc := new(mocks.MockPlanService)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
+
+		mockSvc.On("CreateOverflowPlan", mock.Anything, planID, mock.Anything).Return(nil, service.ErrNoSuitableContainer)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/plans/"+planID+"/overflow", strings.NewReader(`{"suggest_container":true}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Params = gin.Params{{Key: "id", Value: planID}}
+
+		h.CreateOverflowPlan(c)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	})
+}
+
+func TestPlanHandler_GetShipmentGroup(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	planID := uuid.New().String()
+
+	t.Run("success", func(t *testing.T) {
+		mockSvc := new(mocks.MockPlanService)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
+
+		mockSvc.On("GetShipmentGroup", mock.Anything, planID).
+			Return(&dto.ShipmentGroupResponse{ShipmentGroupID: planID}, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/plans/"+planID+"/shipment", nil)
+		c.Params = gin.Params{{Key: "id", Value: planID}}
+
+		h.GetShipmentGroup(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockSvc.AssertExpectations(t)
+	})
+
+	t.Run("not_found", func(t *testing.T) {
+		mockSvc := new(mocks.MockPlanService)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
+
+		mockSvc.On("GetShipmentGroup", mock.Anything, planID).Return(nil, errors.New("no rows"))
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/plans/"+planID+"/shipment", nil)
+		c.Params = gin.Params{{Key: "id", Value: planID}}
+
+		h.GetShipmentGroup(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestPlanHandler_ResultVersions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	planID := uuid.New().String()
+
+	t.Run("get_invalid_version", func(t *testing.T) {
+		mockSvc := new(mocks.MockPlanService)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/plans/"+planID+"/results/latest", nil)
+		c.Params = gin.Params{{Key: "id", Value: planID}, {Key: "version", Value: "latest"}}
+
+		h.GetResultVersion(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockSvc.AssertNotCalled(t, "GetResultVersion")
+	})
+
+	t.Run("restore_stale", func(t *testing.T) {
+		mockSvc := new(mocks.MockPlanService)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
+
+		mockSvc.On("RestoreResultVersion", mock.Anything, planID, 2).Return(nil, fmt.Errorf("%w: items changed", service.ErrStaleResultVersion))
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/plans/"+planID+"/results/2/restore", nil)
+		c.Params = gin.Params{{Key: "id", Value: planID}, {Key: "version", Value: "2"}}
+
+		h.RestoreResultVersion(c)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		mockSvc.AssertExpectations(t)
+	})
+
+	t.Run("diff", func(t *testing.T) {
+		mockSvc := new(mocks.MockPlanService)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
+
+		mockSvc.On("DiffResultVersions", mock.Anything, planID, 1, 3).Return(&dto.ResultVersionDiff{FromVersion: 1, ToVersion: 3}, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/plans/"+planID+"/results/diff?from=1&to=3", nil)
+		c.Params = gin.Params{{Key: "id", Value: planID}}
+
+		h.DiffResultVersions(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockSvc.AssertExpectations(t)
+	})
+
+	t.Run("diff_missing_version", func(t *testing.T) {
+		mockSvc := new(mocks.MockPlanService)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
+
+		mockSvc.On("DiffResultVersions", mock.Anything, planID, 1, 9).Return(nil, fmt.Errorf("%w: 9", service.ErrResultVersionNotFound))
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/plans/"+planID+"/results/diff?from=1&to=9", nil)
+		c.Params = gin.Params{{Key: "id", Value: planID}}
+
+		h.DiffResultVersions(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestPlanHandler_HandlingUnits(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	planID := uuid.New().String()
+	plan := &dto.PlanDetailResponse{PlanID: planID, PlanCode: "PLN-1"}
+	units := []dto.HandlingUnit{{
+		SSCC:          "006141410000000012",
+		ElementString: "(00)006141410000000012",
+		StepNumber:    1,
+		ItemLabel:     "Carton",
+		GTIN:          "04006381333931",
+	}}
+
+	newCtx := func(method, path string) (*httptest.ResponseRecorder, *gin.Context) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(method, "/plans/"+planID+path, nil)
+		c.Params = gin.Params{{Key: "id", Value: planID}}
+		return w, c
+	}
+
+	t.Run("assign", func(t *testing.T) {
+		mockSvc := new(mocks.MockPlanService)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
+		mockSvc.On("AssignHandlingUnits", mock.Anything, planID).Return(units, nil)
+
+		w, c := newCtx(http.MethodPost, "/handling-units")
+		h.AssignHandlingUnits(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"sscc":"006141410000000012"`)
+	})
+
+	t.Run("assign_without_gs1_prefix", func(t *testing.T) {
+		mockSvc := new(mocks.MockPlanService)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
+		mockSvc.On("AssignHandlingUnits", mock.Anything, planID).Return(nil, service.ErrGS1NotConfigured)
+
+		w, c := newCtx(http.MethodPost, "/handling-units")
+		h.AssignHandlingUnits(c)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("labels_zpl", func(t *testing.T) {
+		mockSvc := new(mocks.MockPlanService)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
+		mockSvc.On("GetPlan", mock.Anything, planID).Return(plan, nil)
+		mockSvc.On("ListHandlingUnits", mock.Anything, planID).Return(units, nil)
+
+		w, c := newCtx(http.MethodGet, "/handling-units/labels?format=zpl")
+		h.GetHandlingUnitLabels(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Disposition"), "PLN-1-sscc.zpl")
+		assert.Contains(t, w.Body.String(), "^FD(00)006141410000000012^FS")
+	})
+
+	t.Run("labels_without_units", func(t *testing.T) {
+		mockSvc := new(mocks.MockPlanService)
+		h := handler.NewPlanHandler(mockSvc, testCodec)
+		mockSvc.On("GetPlan", mock.Anything, planID).Return(plan, nil)
+		mockSvc.On("ListHandlingUnits", mock.Anything, planID).Return([]dto.HandlingUnit{}, nil)
+
+		w, c := newCtx(http.MethodGet, "/handling-units/labels")
+		h.GetHandlingUnitLabels(c)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+}
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ekastn/load-stuffing-calculator/internal/dto"
+	"github.com/ekastn/load-stuffing-calculator/internal/response"
+	"github.com/ekastn/load-stuffing-calculator/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type PreferenceHandler struct {
+	prefSvc service.PreferenceService
+}
+
+func NewPreferenceHandler(prefSvc service.PreferenceService) *PreferenceHandler {
+	return &PreferenceHandler{prefSvc: prefSvc}
+}
+
+// GetPreferences godoc
+//
+//	@Summary		Get preferences
+//	@Description	Returns the current user's preferences, including the units used when a request sets neither ?units= nor X-Units.
+//	@Tags			auth
+//	@Produce		json
+//	@Success		200	{object}	response.APIResponse{data=dto.PreferencesResponse}
+//	@Failure		401	{object}	response.APIResponse
+//	@Security		BearerAuth
+//	@Router			/auth/preferences [get]
+func (h *PreferenceHandler) GetPreferences(c *gin.Context) {
+	resp, err := h.prefSvc.GetPreferences(c.Request.Context())
+	if err != nil {
+		response.Error(c, http.StatusUnauthorized, "Failed to resolve session")
+		return
+	}
+
+	response.Success(c, http.StatusOK, resp)
+}
+
+// UpdatePreferences godoc
+//
+//	@Summary		Update preferences
+//	@Description	Sets the current user's default units: "metric" (mm, kg), "imperial" (in, lb) or a "<length>,<weight>" pair such as "cm,kg".
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		dto.UpdatePreferencesRequest	true	"Preferences"
+//	@Success		200		{object}	response.APIResponse{data=dto.PreferencesResponse}
+//	@Failure		400		{object}	response.APIResponse
+//	@Failure		403		{object}	response.APIResponse
+//	@Security		BearerAuth
+//	@Router			/auth/preferences [put]
+func (h *PreferenceHandler) UpdatePreferences(c *gin.Context) {
+	var req dto.UpdatePreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request format: "+err.Error())
+		return
+	}
+
+	resp, err := h.prefSvc.UpdatePreferences(c.Request.Context(), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrForbidden):
+			response.Error(c, http.StatusForbidden, "Forbidden")
+		case errors.Is(err, service.ErrInvalidUnits):
+			response.Error(c, http.StatusBadRequest, err.Error())
+		default:
+			response.Error(c, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	response.Success(c, http.StatusOK, resp)
+}
@@ -0,0 +1,77 @@
+package handler_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ekastn/load-stuffing-calculator/internal/dto"
+	"github.com/ekastn/load-stuffing-calculator/internal/handler"
+	"github.com/ekastn/load-stuffing-calculator/internal/mocks"
+	"github.com/ekastn/load-stuffing-calculator/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestPreferenceHandler_UpdatePreferences(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name           string
+		body           string
+		setupMock      func(*mocks.MockPreferenceService)
+		expectedStatus int
+	}{
+		{
+			name: "success",
+			body: `{"units":"imperial"}`,
+			setupMock: func(m *mocks.MockPreferenceService) {
+				m.On("UpdatePreferences", mock.Anything, dto.UpdatePreferencesRequest{Units: "imperial"}).
+					Return(&dto.PreferencesResponse{Units: "in,lb"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "missing_units",
+			body:           `{}`,
+			setupMock:      func(m *mocks.MockPreferenceService) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "invalid_units",
+			body: `{"units":"stone"}`,
+			setupMock: func(m *mocks.MockPreferenceService) {
+				m.On("UpdatePreferences", mock.Anything, mock.Anything).Return(nil, service.ErrInvalidUnits)
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "trial_forbidden",
+			body: `{"units":"metric"}`,
+			setupMock: func(m *mocks.MockPreferenceService) {
+				m.On("UpdatePreferences", mock.Anything, mock.Anything).Return(nil, service.ErrForbidden)
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockSvc := new(mocks.MockPreferenceService)
+			tt.setupMock(mockSvc)
+			h := handler.NewPreferenceHandler(mockSvc)
+
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPut, "/auth/preferences", bytes.NewBufferString(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			h.UpdatePreferences(c)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockSvc.AssertExpectations(t)
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -42,6 +43,10 @@ func (h *ProductHandler) CreateProduct(c *gin.Context) {
 	}
 
 	resp, err := h.productSvc.CreateProduct(c.Request.Context(), req)
+	if errors.Is(err, service.ErrInvalidGTIN) || errors.Is(err, service.ErrInvalidPackaging) {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
 	if err != nil {
 		response.Error(c, http.StatusInternalServerError, "Failed to create product: "+err.Error())
 		return
@@ -117,7 +122,7 @@ func (h *ProductHandler) ListProducts(c *gin.Context) {
 // UpdateProduct godoc
 //
 //	@Summary		Update a product
-//	@Description	Updates an existing product. Requires admin privileges.
+//	@Description	Updates an existing product. Friction, temperature class, GTIN, handling and packaging fields left out keep their stored values; an empty string clears a text field and an empty packaging list removes the hierarchy. Requires admin privileges.
 //	@Tags			products
 //	@Accept			json
 //	@Produce		json
@@ -145,6 +150,10 @@ func (h *ProductHandler) UpdateProduct(c *gin.Context) {
 	}
 
 	err := h.productSvc.UpdateProduct(c.Request.Context(), id, req)
+	if errors.Is(err, service.ErrInvalidGTIN) || errors.Is(err, service.ErrInvalidPackaging) {
+		response.Error(c, http.StatusBadRequest, err.Error())
+		return
+	}
 	if err != nil {
 		response.Error(c, http.StatusInternalServerError, "Failed to update product: "+err.Error())
 		return
@@ -4,12 +4,14 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/ekastn/load-stuffing-calculator/internal/dto"
 	"github.com/ekastn/load-stuffing-calculator/internal/handler"
+	"github.com/ekastn/load-stuffing-calculator/internal/service"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -88,6 +90,62 @@ func TestProductHandler_CreateProduct(t *testing.T) {
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
 		mockSvc.AssertExpectations(t)
 	})
+
+	t.Run("invalid_gtin", func(t *testing.T) {
+		mockSvc := new(MockProductService)
+		h := handler.NewProductHandler(mockSvc)
+
+		gtin := "4006381333932"
+		req := dto.CreateProductRequest{
+			Name:     "Item 1",
+			GTIN:     &gtin,
+			LengthMM: 100,
+			WidthMM:  50,
+			HeightMM: 20,
+			WeightKG: 1.5,
+		}
+
+		mockSvc.On("CreateProduct", mock.Anything, req).Return((*dto.ProductResponse)(nil), fmt.Errorf("%w: wrong check digit", service.ErrInvalidGTIN))
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		jsonBytes, _ := json.Marshal(req)
+		c.Request = httptest.NewRequest(http.MethodPost, "/products", bytes.NewBuffer(jsonBytes))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		h.CreateProduct(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockSvc.AssertExpectations(t)
+	})
+
+	t.Run("invalid_packaging", func(t *testing.T) {
+		mockSvc := new(MockProductService)
+		h := handler.NewProductHandler(mockSvc)
+
+		pallet := "pallet"
+		req := dto.CreateProductRequest{
+			Name:         "Item 1",
+			LengthMM:     100,
+			WidthMM:      50,
+			HeightMM:     20,
+			WeightKG:     1.5,
+			ShippingUnit: &pallet,
+		}
+
+		mockSvc.On("CreateProduct", mock.Anything, req).Return((*dto.ProductResponse)(nil), fmt.Errorf("%w: shipping unit %q is not a packaging level", service.ErrInvalidPackaging, pallet))
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		jsonBytes, _ := json.Marshal(req)
+		c.Request = httptest.NewRequest(http.MethodPost, "/products", bytes.NewBuffer(jsonBytes))
+		c.Request.Header.Set("Content-Type", "application/json")
+
+		h.CreateProduct(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockSvc.AssertExpectations(t)
+	})
 }
 
 func TestProductHandler_GetProduct(t *testing.T) {
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -135,3 +136,68 @@ func (h *WorkspaceHandler) DeleteWorkspace(c *gin.Context) {
 	}
 	response.Success(c, http.StatusOK, nil)
 }
+
+// GetGS1Settings godoc
+//
+//	@Summary		Get workspace GS1 settings
+//	@Description	Returns the GS1 company prefix and extension digit SSCCs are issued with, and how many serials are left.
+//	@Tags			workspaces
+//	@Produce		json
+//	@Param			id	path		string	true	"Workspace ID"
+//	@Success		200	{object}	response.APIResponse{data=dto.GS1SettingsResponse}
+//	@Failure		400	{object}	response.APIResponse
+//	@Failure		404	{object}	response.APIResponse
+//	@Security		BearerAuth
+//	@Router			/workspaces/{id}/gs1 [get]
+func (h *WorkspaceHandler) GetGS1Settings(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		response.Error(c, http.StatusBadRequest, "Workspace ID is required")
+		return
+	}
+
+	resp, err := h.workspaceSvc.GetGS1Settings(c.Request.Context(), id)
+	if err != nil {
+		if errors.Is(err, service.ErrGS1NotConfigured) {
+			response.Error(c, http.StatusNotFound, err.Error())
+			return
+		}
+		response.Error(c, http.StatusBadRequest, "Failed to get GS1 settings: "+err.Error())
+		return
+	}
+	response.Success(c, http.StatusOK, resp)
+}
+
+// UpdateGS1Settings godoc
+//
+//	@Summary		Update workspace GS1 settings
+//	@Description	Sets the GS1 company prefix and extension digit SSCCs are issued with. Serials already issued are never reused.
+//	@Tags			workspaces
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string							true	"Workspace ID"
+//	@Param			request	body		dto.UpdateGS1SettingsRequest	true	"GS1 settings"
+//	@Success		200		{object}	response.APIResponse{data=dto.GS1SettingsResponse}
+//	@Failure		400		{object}	response.APIResponse
+//	@Security		BearerAuth
+//	@Router			/workspaces/{id}/gs1 [put]
+func (h *WorkspaceHandler) UpdateGS1Settings(c *gin.Context) {
+	id := c.Param("id")
+	if id == "" {
+		response.Error(c, http.StatusBadRequest, "Workspace ID is required")
+		return
+	}
+
+	var req dto.UpdateGS1SettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.Error(c, http.StatusBadRequest, "Invalid request format: "+err.Error())
+		return
+	}
+
+	resp, err := h.workspaceSvc.UpdateGS1Settings(c.Request.Context(), id, req)
+	if err != nil {
+		response.Error(c, http.StatusBadRequest, "Failed to update GS1 settings: "+err.Error())
+		return
+	}
+	response.Success(c, http.StatusOK, resp)
+}
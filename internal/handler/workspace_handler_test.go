@@ -11,6 +11,7 @@ import (
 
 	"github.com/ekastn/load-stuffing-calculator/internal/dto"
 	"github.com/ekastn/load-stuffing-calculator/internal/handler"
+	"github.com/ekastn/load-stuffing-calculator/internal/service"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -418,3 +419,63 @@ func TestWorkspaceHandler_DeleteWorkspace(t *testing.T) {
 		mockSvc.AssertExpectations(t)
 	})
 }
+
+func TestWorkspaceHandler_GS1Settings(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	t.Run("get_not_configured", func(t *testing.T) {
+		mockSvc := new(MockWorkspaceService)
+		h := handler.NewWorkspaceHandler(mockSvc)
+
+		workspaceID := "ws-123"
+		mockSvc.On("GetGS1Settings", mock.Anything, workspaceID).Return(nil, service.ErrGS1NotConfigured)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/workspaces/"+workspaceID+"/gs1", nil)
+		c.Params = gin.Params{{Key: "id", Value: workspaceID}}
+
+		h.GetGS1Settings(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockSvc.AssertExpectations(t)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		mockSvc := new(MockWorkspaceService)
+		h := handler.NewWorkspaceHandler(mockSvc)
+
+		workspaceID := "ws-123"
+		req := dto.UpdateGS1SettingsRequest{CompanyPrefix: "0614141"}
+		mockSvc.On("UpdateGS1Settings", mock.Anything, workspaceID, req).Return(&dto.GS1SettingsResponse{CompanyPrefix: "0614141"}, nil)
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		jsonBytes, _ := json.Marshal(req)
+		c.Request = httptest.NewRequest(http.MethodPut, "/api/v1/workspaces/"+workspaceID+"/gs1", bytes.NewBuffer(jsonBytes))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Params = gin.Params{{Key: "id", Value: workspaceID}}
+
+		h.UpdateGS1Settings(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockSvc.AssertExpectations(t)
+	})
+
+	t.Run("update_rejects_letters_in_prefix", func(t *testing.T) {
+		mockSvc := new(MockWorkspaceService)
+		h := handler.NewWorkspaceHandler(mockSvc)
+
+		workspaceID := "ws-123"
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPut, "/api/v1/workspaces/"+workspaceID+"/gs1", bytes.NewBufferString(`{"company_prefix":"06141A1"}`))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Params = gin.Params{{Key: "id", Value: workspaceID}}
+
+		h.UpdateGS1Settings(c)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockSvc.AssertNotCalled(t, "UpdateGS1Settings")
+	})
+}
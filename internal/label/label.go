@@ -0,0 +1,201 @@
+// Package label renders loading labels: QR and Code128 images of a step's
+// barcode, A4 label sheets and ZPL for Zebra thermal printers. Everything
+// is drawn from dto.BarcodeInfo so every client prints the same label.
+// Handling units get GS1-128 logistic labels carrying their SSCC.
+package label
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+
+	bc "github.com/boombuler/barcode"
+	"github.com/boombuler/barcode/code128"
+	"github.com/boombuler/barcode/qr"
+)
+
+// Symbology is the kind of barcode drawn.
+type Symbology string
+
+const (
+	QR      Symbology = "qr"
+	Code128 Symbology = "code128"
+	// GS1128 encodes a GS1 element string such as "(00)006141410000000018"
+	// as Code128 led by FNC1. Only fixed-length application identifiers
+	// are supported, as no separators are inserted.
+	GS1128 Symbology = "gs1-128"
+)
+
+// Format is the image format of a rendered barcode.
+type Format string
+
+const (
+	PNG Format = "png"
+	SVG Format = "svg"
+)
+
+// Image size limits in pixels.
+const (
+	DefaultSize = 256
+	MinSize     = 64
+	MaxSize     = 2048
+)
+
+var (
+	ErrUnknownSymbology = errors.New("unknown symbology")
+	ErrUnknownFormat    = errors.New("unknown image format")
+)
+
+// ParseSymbology reads a symbology name; empty means QR.
+func ParseSymbology(s string) (Symbology, error) {
+	switch Symbology(s) {
+	case "", QR:
+		return QR, nil
+	case Code128:
+		return Code128, nil
+	case GS1128:
+		return GS1128, nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrUnknownSymbology, s)
+}
+
+// ParseFormat reads an image format name; empty means PNG.
+func ParseFormat(s string) (Format, error) {
+	switch Format(s) {
+	case "", PNG:
+		return PNG, nil
+	case SVG:
+		return SVG, nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrUnknownFormat, s)
+}
+
+// ContentType is the MIME type of an image format.
+func (f Format) ContentType() string {
+	if f == SVG {
+		return "image/svg+xml"
+	}
+	return "image/png"
+}
+
+// Render draws code as an image. size is the width in pixels; QR codes are
+// square and Code128 and GS1-128 bars are a third as tall as they are wide.
+func Render(code string, sym Symbology, format Format, size int) ([]byte, error) {
+	size = clampSize(size)
+	symbol, err := encode(code, sym)
+	if err != nil {
+		return nil, err
+	}
+	w, h := size, size
+	if sym.linear() {
+		h = size / 3
+	}
+
+	switch format {
+	case PNG:
+		// Scaling needs at least one pixel per module.
+		b := symbol.Bounds()
+		if w < b.Dx() {
+			w = b.Dx()
+		}
+		if sym == QR {
+			h = w
+		}
+		scaled, err := bc.Scale(symbol, w, h)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scale barcode: %w", err)
+		}
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, scaled); err != nil {
+			return nil, fmt.Errorf("failed to encode png: %w", err)
+		}
+		return buf.Bytes(), nil
+	case SVG:
+		return renderSVG(symbol, sym, w, h), nil
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+}
+
+func encode(code string, sym Symbology) (bc.Barcode, error) {
+	switch sym {
+	case QR:
+		symbol, err := qr.Encode(code, qr.M, qr.Auto)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode qr code: %w", err)
+		}
+		return symbol, nil
+	case Code128:
+		symbol, err := code128.Encode(code)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode code128: %w", err)
+		}
+		return symbol, nil
+	case GS1128:
+		data := strings.NewReplacer("(", "", ")", "").Replace(code)
+		symbol, err := code128.Encode(string(code128.FNC1) + data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode gs1-128: %w", err)
+		}
+		return symbol, nil
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnknownSymbology, sym)
+}
+
+// linear reports whether sym is a one-dimensional barcode.
+func (sym Symbology) linear() bool {
+	return sym == Code128 || sym == GS1128
+}
+
+// renderSVG draws the modules of an unscaled symbol as rectangles, merging
+// runs of dark modules in a row. 1D symbols are one module tall and are
+// stretched to the full height.
+func renderSVG(symbol image.Image, sym Symbology, w, h int) []byte {
+	b := symbol.Bounds()
+	cols, rows := b.Dx(), b.Dy()
+
+	aspect := ""
+	if sym.linear() {
+		aspect = ` preserveAspectRatio="none"`
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d"%s shape-rendering="crispEdges">`, w, h, cols, rows, aspect)
+	fmt.Fprintf(&buf, `<rect width="%d" height="%d" fill="#fff"/>`, cols, rows)
+	buf.WriteString(`<path fill="#000" d="`)
+	for y := 0; y < rows; y++ {
+		for x := 0; x < cols; {
+			if !dark(symbol.At(b.Min.X+x, b.Min.Y+y)) {
+				x++
+				continue
+			}
+			start := x
+			for x < cols && dark(symbol.At(b.Min.X+x, b.Min.Y+y)) {
+				x++
+			}
+			fmt.Fprintf(&buf, "M%d %dh%dv1h-%dz", start, y, x-start, x-start)
+		}
+	}
+	buf.WriteString(`"/></svg>`)
+	return buf.Bytes()
+}
+
+func dark(c color.Color) bool {
+	g := color.GrayModel.Convert(c).(color.Gray)
+	return g.Y < 128
+}
+
+func clampSize(size int) int {
+	switch {
+	case size <= 0:
+		return DefaultSize
+	case size < MinSize:
+		return MinSize
+	case size > MaxSize:
+		return MaxSize
+	}
+	return size
+}
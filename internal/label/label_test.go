@@ -0,0 +1,125 @@
+package label
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/ekastn/load-stuffing-calculator/internal/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testCode = "LS2-YAYC5DF7HBCXBFLEVX5JZ2DKFE-001-WU5IRBKD4VATPIAUYZGEG5LY6M-NQVLIWKSFYIXAQUC"
+
+func TestRender(t *testing.T) {
+	t.Run("qr_png", func(t *testing.T) {
+		out, err := Render(testCode, QR, PNG, 300)
+		require.NoError(t, err)
+		img, err := png.Decode(bytes.NewReader(out))
+		require.NoError(t, err)
+		assert.Equal(t, img.Bounds().Dx(), img.Bounds().Dy())
+		assert.Equal(t, 300, img.Bounds().Dx())
+	})
+
+	t.Run("code128_png_widens_to_fit_modules", func(t *testing.T) {
+		out, err := Render(testCode, Code128, PNG, MinSize)
+		require.NoError(t, err)
+		img, err := png.Decode(bytes.NewReader(out))
+		require.NoError(t, err)
+		assert.Greater(t, img.Bounds().Dx(), MinSize)
+	})
+
+	t.Run("svg", func(t *testing.T) {
+		out, err := Render(testCode, QR, SVG, 0)
+		require.NoError(t, err)
+		svg := string(out)
+		assert.True(t, strings.HasPrefix(svg, "<svg"))
+		assert.Contains(t, svg, `width="256"`)
+		assert.Contains(t, svg, `<path fill="#000" d="M`)
+	})
+
+	t.Run("unknown_names", func(t *testing.T) {
+		_, err := ParseSymbology("ean13")
+		assert.ErrorIs(t, err, ErrUnknownSymbology)
+		_, err = ParseFormat("gif")
+		assert.ErrorIs(t, err, ErrUnknownFormat)
+	})
+}
+
+func testLabels(n int) []dto.BarcodeInfo {
+	out := make([]dto.BarcodeInfo, n)
+	for i := range out {
+		out[i] = dto.BarcodeInfo{
+			StepNumber: i + 1,
+			ItemLabel:  "Carton ^A_1~",
+			SKU:        "SKU-1",
+			Barcode:    testCode,
+			Position:   dto.Position{X: 100, Y: 0, Z: 250},
+			Dimensions: dto.Dimensions{Length: 600, Width: 400, Height: 300},
+		}
+	}
+	return out
+}
+
+func TestSheet(t *testing.T) {
+	out, err := Sheet("PLN-1 labels", testLabels(15))
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(out, []byte("%PDF")))
+	// 15 labels need a second sheet.
+	assert.Equal(t, 2, bytes.Count(out, []byte("/Type /Page\n")))
+}
+
+func TestZPL(t *testing.T) {
+	out := string(ZPL(testLabels(2)))
+	assert.Equal(t, 2, strings.Count(out, "^XA"))
+	assert.Equal(t, 2, strings.Count(out, "^XZ"))
+	assert.Contains(t, out, "^FDSTEP 002^FS")
+	assert.Contains(t, out, "^FDQA,"+testCode+"^FS")
+	assert.Contains(t, out, "^FDCarton _5EA_5F1_7E^FS")
+	assert.Contains(t, out, "^FDSKU SKU-1^FS")
+}
+
+func testUnits(n int) []dto.HandlingUnit {
+	out := make([]dto.HandlingUnit, n)
+	for i := range out {
+		out[i] = dto.HandlingUnit{
+			SSCC:          "006141410000000018",
+			ElementString: "(00)006141410000000018",
+			StepNumber:    i + 1,
+			ItemLabel:     "Carton",
+			SKU:           "SKU-1",
+			GTIN:          "04006381333931",
+		}
+	}
+	return out
+}
+
+func TestGS1128(t *testing.T) {
+	gs1, err := Render("(00)006141410000000018", GS1128, PNG, 512)
+	require.NoError(t, err)
+	plain, err := Render("00006141410000000018", Code128, PNG, 512)
+	require.NoError(t, err)
+	// The leading FNC1 makes the symbol differ from plain Code128.
+	assert.NotEqual(t, plain, gs1)
+
+	sym, err := ParseSymbology("gs1-128")
+	require.NoError(t, err)
+	assert.Equal(t, GS1128, sym)
+}
+
+func TestSSCCSheet(t *testing.T) {
+	out, err := SSCCSheet("PLN-1 handling units", testUnits(3))
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(out, []byte("%PDF")))
+	assert.Equal(t, 3, bytes.Count(out, []byte("/Type /Page\n")))
+}
+
+func TestSSCCZPL(t *testing.T) {
+	out := string(SSCCZPL(testUnits(2)))
+	assert.Equal(t, 2, strings.Count(out, "^XA"))
+	assert.Contains(t, out, "^BCN,260,Y,N,N,D^FD(00)006141410000000018^FS")
+	assert.Contains(t, out, "^FDGTIN 04006381333931^FS")
+	assert.Contains(t, out, "^FDLOADING STEP 002^FS")
+}
@@ -0,0 +1,96 @@
+package label
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/ekastn/load-stuffing-calculator/internal/dto"
+	"github.com/go-pdf/fpdf"
+)
+
+// Sheet layout in mm: 14 labels of 99.1 x 38.1 on A4, the common 2 x 7
+// label stock.
+const (
+	sheetCols    = 2
+	sheetRows    = 7
+	labelWidth   = 99.1
+	labelHeight  = 38.1
+	sheetLeft    = 4.65
+	sheetTop     = 15.15
+	sheetPitchX  = 101.6
+	labelPadding = 3.0
+	qrSize       = labelHeight - 2*labelPadding
+)
+
+// Sheet renders labels on A4 label sheets, one label per loading step, in
+// the order given. Each label carries the step's QR code, step number, item
+// label, SKU, position and the barcode text for manual entry.
+func Sheet(title string, labels []dto.BarcodeInfo) ([]byte, error) {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetTitle(title, true)
+	pdf.SetAutoPageBreak(false, 0)
+	pdf.SetDrawColor(200, 200, 200)
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	if len(labels) == 0 {
+		pdf.AddPage()
+	}
+	for i, l := range labels {
+		slot := i % (sheetCols * sheetRows)
+		if slot == 0 {
+			pdf.AddPage()
+		}
+		x := sheetLeft + float64(slot%sheetCols)*sheetPitchX
+		y := sheetTop + float64(slot/sheetCols)*labelHeight
+
+		img, err := Render(l.Barcode, QR, PNG, 512)
+		if err != nil {
+			return nil, fmt.Errorf("step %d: %w", l.StepNumber, err)
+		}
+		name := fmt.Sprintf("step-%d", i)
+		opts := fpdf.ImageOptions{ImageType: "PNG"}
+		pdf.RegisterImageOptionsReader(name, opts, bytes.NewReader(img))
+
+		pdf.Rect(x, y, labelWidth, labelHeight, "D")
+		pdf.ImageOptions(name, x+labelPadding, y+labelPadding, qrSize, qrSize, false, opts, 0, "")
+
+		textX := x + labelPadding + qrSize + labelPadding
+		textW := labelWidth - (textX - x) - labelPadding
+
+		pdf.SetXY(textX, y+labelPadding)
+		pdf.SetFont("Helvetica", "B", 16)
+		pdf.CellFormat(textW, 7, fmt.Sprintf("STEP %03d", l.StepNumber), "", 2, "L", false, 0, "")
+
+		pdf.SetFont("Helvetica", "B", 9)
+		pdf.CellFormat(textW, 4.5, fit(pdf, tr(l.ItemLabel), textW), "", 2, "L", false, 0, "")
+
+		pdf.SetFont("Helvetica", "", 8)
+		if l.SKU != "" {
+			pdf.CellFormat(textW, 4, fit(pdf, tr("SKU "+l.SKU), textW), "", 2, "L", false, 0, "")
+		}
+		pdf.CellFormat(textW, 4, fmt.Sprintf("Pos X %.0f  Y %.0f  Z %.0f mm", l.Position.X, l.Position.Y, l.Position.Z), "", 2, "L", false, 0, "")
+		pdf.CellFormat(textW, 4, fmt.Sprintf("%.0f x %.0f x %.0f mm", l.Dimensions.Length, l.Dimensions.Width, l.Dimensions.Height), "", 2, "L", false, 0, "")
+
+		pdf.SetFont("Courier", "", 5)
+		pdf.SetXY(textX, y+labelHeight-labelPadding-4)
+		pdf.MultiCell(textW, 2, l.Barcode, "", "L", false)
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("failed to render label sheet: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// fit shortens s with an ellipsis until it fits in w.
+func fit(pdf *fpdf.Fpdf, s string, w float64) string {
+	if pdf.GetStringWidth(s) <= w {
+		return s
+	}
+	r := []rune(s)
+	for len(r) > 0 && pdf.GetStringWidth(string(r)+"...") > w {
+		r = r[:len(r)-1]
+	}
+	return string(r) + "..."
+}
@@ -0,0 +1,110 @@
+package label
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/ekastn/load-stuffing-calculator/internal/dto"
+	"github.com/go-pdf/fpdf"
+)
+
+// Logistic label layout in mm: one A6 label per page, the usual size of GS1
+// logistic labels.
+const (
+	ssccWidth   = 105.0
+	ssccHeight  = 148.0
+	ssccMargin  = 6.0
+	ssccBarsTop = 92.0
+	ssccBarsH   = 32.0
+)
+
+// SSCCSheet renders a GS1 logistic label per handling unit: the unit's
+// contents in the upper half and its SSCC as GS1-128 with the element string
+// printed beneath.
+func SSCCSheet(title string, units []dto.HandlingUnit) ([]byte, error) {
+	pdf := fpdf.NewCustom(&fpdf.InitType{
+		OrientationStr: "P",
+		UnitStr:        "mm",
+		Size:           fpdf.SizeType{Wd: ssccWidth, Ht: ssccHeight},
+	})
+	pdf.SetTitle(title, true)
+	pdf.SetAutoPageBreak(false, 0)
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	textW := ssccWidth - 2*ssccMargin
+
+	if len(units) == 0 {
+		pdf.AddPage()
+	}
+	for i, u := range units {
+		pdf.AddPage()
+
+		pdf.SetXY(ssccMargin, ssccMargin)
+		pdf.SetFont("Helvetica", "B", 9)
+		pdf.CellFormat(textW, 5, tr(title), "B", 2, "L", false, 0, "")
+		pdf.Ln(3)
+
+		pdf.SetFont("Helvetica", "", 8)
+		pdf.CellFormat(textW, 4, "SSCC", "", 2, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "B", 16)
+		pdf.CellFormat(textW, 8, u.SSCC, "", 2, "L", false, 0, "")
+		pdf.Ln(2)
+
+		pdf.SetFont("Helvetica", "", 8)
+		pdf.CellFormat(textW, 4, "CONTENT", "", 2, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "B", 11)
+		pdf.CellFormat(textW, 6, fit(pdf, tr(u.ItemLabel), textW), "", 2, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 9)
+		if u.GTIN != "" {
+			pdf.CellFormat(textW, 5, "GTIN "+u.GTIN, "", 2, "L", false, 0, "")
+		}
+		if u.SKU != "" {
+			pdf.CellFormat(textW, 5, fit(pdf, tr("SKU "+u.SKU), textW), "", 2, "L", false, 0, "")
+		}
+		pdf.CellFormat(textW, 5, fmt.Sprintf("Loading step %03d", u.StepNumber), "", 2, "L", false, 0, "")
+
+		img, err := Render(u.ElementString, GS1128, PNG, 1024)
+		if err != nil {
+			return nil, fmt.Errorf("sscc %s: %w", u.SSCC, err)
+		}
+		name := fmt.Sprintf("sscc-%d", i)
+		opts := fpdf.ImageOptions{ImageType: "PNG"}
+		pdf.RegisterImageOptionsReader(name, opts, bytes.NewReader(img))
+		pdf.Line(ssccMargin, ssccBarsTop-4, ssccWidth-ssccMargin, ssccBarsTop-4)
+		pdf.ImageOptions(name, ssccMargin, ssccBarsTop, textW, ssccBarsH, false, opts, 0, "")
+
+		pdf.SetXY(ssccMargin, ssccBarsTop+ssccBarsH+1)
+		pdf.SetFont("Helvetica", "B", 11)
+		pdf.CellFormat(textW, 6, u.ElementString, "", 2, "C", false, 0, "")
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("failed to render logistic labels: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// SSCCZPL renders a GS1 logistic label per handling unit as ZPL II for 4 x 6
+// inch labels on 203 dpi printers. The SSCC is printed with ^BC in UCC/EAN
+// mode, which adds the FNC1 and the human-readable element string.
+func SSCCZPL(units []dto.HandlingUnit) []byte {
+	var buf bytes.Buffer
+	for _, u := range units {
+		buf.WriteString("^XA^CI28^PW812^LL1218\n")
+		fmt.Fprintf(&buf, "^FO40,40^A0N,28,28^FDSSCC^FS\n")
+		fmt.Fprintf(&buf, "^FO40,75^A0N,56,56^FD%s^FS\n", u.SSCC)
+		fmt.Fprintf(&buf, "^FO40,170^A0N,28,28^FDCONTENT^FS\n")
+		fmt.Fprintf(&buf, "^FO40,205^A0N,40,40^FB730,2,0,L^FH^FD%s^FS\n", zplField(u.ItemLabel))
+		if u.GTIN != "" {
+			fmt.Fprintf(&buf, "^FO40,300^A0N,32,32^FDGTIN %s^FS\n", u.GTIN)
+		}
+		if u.SKU != "" {
+			fmt.Fprintf(&buf, "^FO40,345^A0N,32,32^FH^FDSKU %s^FS\n", zplField(u.SKU))
+		}
+		fmt.Fprintf(&buf, "^FO40,390^A0N,32,32^FDLOADING STEP %03d^FS\n", u.StepNumber)
+		buf.WriteString("^FO40,700^GB730,3,3^FS\n")
+		fmt.Fprintf(&buf, "^FO60,760^BY3^BCN,260,Y,N,N,D^FD(00)%s^FS\n", u.SSCC)
+		buf.WriteString("^XZ\n")
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,38 @@
+package label
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/ekastn/load-stuffing-calculator/internal/dto"
+)
+
+// ZPL renders labels as ZPL II for 4 x 2 inch labels on 203 dpi Zebra
+// printers, one label per loading step. Signed barcodes are too long for
+// Code128 at that width, so the code is printed as QR.
+func ZPL(labels []dto.BarcodeInfo) []byte {
+	var buf bytes.Buffer
+	for _, l := range labels {
+		buf.WriteString("^XA^CI28^PW812^LL406\n")
+		fmt.Fprintf(&buf, "^FO20,20^BQN,2,5^FH^FDQA,%s^FS\n", zplField(l.Barcode))
+		fmt.Fprintf(&buf, "^FO250,30^A0N,64,64^FDSTEP %03d^FS\n", l.StepNumber)
+		fmt.Fprintf(&buf, "^FO250,110^A0N,32,32^FB540,2,0,L^FH^FD%s^FS\n", zplField(l.ItemLabel))
+		if l.SKU != "" {
+			fmt.Fprintf(&buf, "^FO250,190^A0N,28,28^FH^FDSKU %s^FS\n", zplField(l.SKU))
+		}
+		fmt.Fprintf(&buf, "^FO250,230^A0N,24,24^FDPOS X %.0f Y %.0f Z %.0f mm^FS\n", l.Position.X, l.Position.Y, l.Position.Z)
+		fmt.Fprintf(&buf, "^FO250,265^A0N,24,24^FD%.0f x %.0f x %.0f mm^FS\n", l.Dimensions.Length, l.Dimensions.Width, l.Dimensions.Height)
+		fmt.Fprintf(&buf, "^FO20,370^A0N,18,18^FH^FD%s^FS\n", zplField(l.Barcode))
+		buf.WriteString("^XZ\n")
+	}
+	return buf.Bytes()
+}
+
+// zplFieldEscaper hex-escapes the characters ZPL reads as commands inside a
+// ^FH field.
+var zplFieldEscaper = strings.NewReplacer("_", "_5F", "^", "_5E", "~", "_7E")
+
+func zplField(s string) string {
+	return zplFieldEscaper.Replace(s)
+}
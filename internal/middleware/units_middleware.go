@@ -0,0 +1,126 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ekastn/load-stuffing-calculator/internal/response"
+	"github.com/ekastn/load-stuffing-calculator/internal/units"
+	"github.com/gin-gonic/gin"
+)
+
+// UnitsHeader selects request/response units and echoes the units used.
+const UnitsHeader = "X-Units"
+
+// UnitPreferenceFunc looks up a user's stored unit preference.
+type UnitPreferenceFunc func(ctx context.Context, userID string) (units.System, bool)
+
+// Units makes JSON requests and responses unit-aware. The selection comes from
+// the "units" query parameter, then the X-Units header, then the user's stored
+// preference, and defaults to mm/kg. Handlers and services only ever see
+// canonical units.
+func Units(preferred UnitPreferenceFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		sys, err := resolveUnits(c, preferred)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "Invalid units: "+err.Error())
+			c.Abort()
+			return
+		}
+		w := &unitsWriter{ResponseWriter: c.Writer, sys: sys}
+		c.Writer = w
+
+		if sys.IsCanonical() {
+			c.Next()
+			return
+		}
+
+		if c.Request.Body != nil && c.ContentType() == gin.MIMEJSON {
+			body, err := io.ReadAll(c.Request.Body)
+			if err != nil {
+				response.Error(c, http.StatusBadRequest, "Failed to read request body")
+				c.Abort()
+				return
+			}
+			if len(bytes.TrimSpace(body)) > 0 {
+				if body, err = units.RewriteRequest(body, sys); err != nil {
+					response.Error(c, http.StatusBadRequest, "Invalid request format: "+err.Error())
+					c.Abort()
+					return
+				}
+			}
+			c.Request.Body = io.NopCloser(bytes.NewReader(body))
+			c.Request.ContentLength = int64(len(body))
+		}
+
+		c.Next()
+		w.finish()
+	}
+}
+
+func resolveUnits(c *gin.Context, preferred UnitPreferenceFunc) (units.System, error) {
+	if v := c.Query("units"); v != "" {
+		return units.Parse(v)
+	}
+	if v := c.GetHeader(UnitsHeader); v != "" {
+		return units.Parse(v)
+	}
+	if preferred != nil {
+		if userID := c.GetString("user_id"); userID != "" {
+			if sys, ok := preferred(c.Request.Context(), userID); ok {
+				return sys, nil
+			}
+		}
+	}
+	return units.Canonical, nil
+}
+
+// unitsWriter buffers JSON responses so they can be converted once the handler
+// is done. Anything else (e.g. Server-Sent Events) passes straight through.
+// Only JSON responses carry the units header, with the units they were
+// written in.
+type unitsWriter struct {
+	gin.ResponseWriter
+	sys units.System
+
+	decided   bool
+	buffering bool
+	buf       bytes.Buffer
+}
+
+func (w *unitsWriter) Write(data []byte) (int, error) {
+	if !w.decided {
+		w.decided = true
+		isJSON := strings.HasPrefix(w.Header().Get("Content-Type"), gin.MIMEJSON)
+		w.buffering = isJSON && !w.sys.IsCanonical()
+		if isJSON && !w.buffering {
+			w.Header().Set(UnitsHeader, w.sys.String())
+		}
+	}
+	if w.buffering {
+		return w.buf.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *unitsWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *unitsWriter) finish() {
+	if !w.buffering {
+		return
+	}
+	body := w.buf.Bytes()
+	sys := units.Canonical
+	if converted, err := units.RewriteResponse(body, w.sys); err == nil {
+		body, sys = converted, w.sys
+	}
+	w.Header().Set(UnitsHeader, sys.String())
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	_, _ = w.ResponseWriter.Write(body)
+}
@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ekastn/load-stuffing-calculator/internal/units"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnits(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	imperial := func(ctx context.Context, userID string) (units.System, bool) {
+		if userID == "us-user" {
+			return units.System{Length: units.Inch, Weight: units.Pound}, true
+		}
+		return units.Canonical, false
+	}
+
+	newRouter := func(userID string) (*gin.Engine, *string) {
+		var gotBody string
+		r := gin.New()
+		r.Use(func(c *gin.Context) {
+			if userID != "" {
+				c.Set("user_id", userID)
+			}
+		})
+		r.Use(Units(imperial))
+		r.POST("/echo", func(c *gin.Context) {
+			raw, _ := io.ReadAll(c.Request.Body)
+			gotBody = string(raw)
+			c.JSON(http.StatusOK, gin.H{"length_mm": 254.0})
+		})
+		r.GET("/stream", func(c *gin.Context) {
+			c.Header("Content-Type", "text/event-stream")
+			c.Status(http.StatusOK)
+			_, _ = c.Writer.WriteString("data: {\"length_mm\":254}\n\n")
+		})
+		return r, &gotBody
+	}
+
+	t.Run("default_is_canonical", func(t *testing.T) {
+		r, body := newRouter("")
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader(`{"length_mm":10}`))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, "mm,kg", w.Header().Get(UnitsHeader))
+		assert.JSONEq(t, `{"length_mm":10}`, *body)
+		assert.JSONEq(t, `{"length_mm":254}`, w.Body.String())
+	})
+
+	t.Run("query_parameter", func(t *testing.T) {
+		r, body := newRouter("")
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/echo?units=imperial", strings.NewReader(`{"length_in":10}`))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "in,lb", w.Header().Get(UnitsHeader))
+		assert.JSONEq(t, `{"length_mm":254}`, *body)
+		assert.JSONEq(t, `{"length_in":10}`, w.Body.String())
+	})
+
+	t.Run("header_beats_preference", func(t *testing.T) {
+		r, _ := newRouter("us-user")
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/echo", nil)
+		req.Header.Set(UnitsHeader, "cm")
+		r.ServeHTTP(w, req)
+
+		assert.JSONEq(t, `{"length_cm":25.4}`, w.Body.String())
+	})
+
+	t.Run("user_preference", func(t *testing.T) {
+		r, _ := newRouter("us-user")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/echo", nil))
+
+		assert.JSONEq(t, `{"length_in":10}`, w.Body.String())
+	})
+
+	t.Run("invalid_units", func(t *testing.T) {
+		r, _ := newRouter("")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/echo?units=furlong", nil))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("non_json_passes_through", func(t *testing.T) {
+		r, _ := newRouter("")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/stream?units=imperial", nil))
+
+		assert.Equal(t, "data: {\"length_mm\":254}\n\n", w.Body.String())
+		assert.Empty(t, w.Header().Get(UnitsHeader))
+	})
+}
@@ -70,7 +70,6 @@ type MockQuerier struct {
 	UpdateLoadPlanFunc              func(ctx context.Context, arg store.UpdateLoadPlanParams) error
 	DeleteLoadPlanFunc              func(ctx context.Context, arg store.DeleteLoadPlanParams) error
 	CreatePlanResultFunc            func(ctx context.Context, arg store.CreatePlanResultParams) (store.PlanResult, error)
-	DeletePlanResultsFunc           func(ctx context.Context, planID *uuid.UUID) error
 	CreatePlanPlacementFunc         func(ctx context.Context, arg []store.CreatePlanPlacementParams) (int64, error)
 	GetPlanResultFunc               func(ctx context.Context, planID *uuid.UUID) (store.PlanResult, error)
 	ListPlanPlacementsFunc          func(ctx context.Context, resultID *uuid.UUID) ([]store.PlanPlacement, error)
@@ -124,6 +123,65 @@ type MockQuerier struct {
 	GetPersonalWorkspaceByOwnerFunc         func(ctx context.Context, ownerUserID uuid.UUID) (store.Workspace, error)
 	ListWorkspacesForUserFunc               func(ctx context.Context, arg store.ListWorkspacesForUserParams) ([]store.Workspace, error)
 	GetMemberRoleNameByWorkspaceAndUserFunc func(ctx context.Context, arg store.GetMemberRoleNameByWorkspaceAndUserParams) (string, error)
+
+	CreateCalculationJobFunc          func(ctx context.Context, arg store.CreateCalculationJobParams) (store.CalculationJob, error)
+	GetCalculationJobFunc             func(ctx context.Context, arg store.GetCalculationJobParams) (store.CalculationJob, error)
+	GetCalculationJobAnyFunc          func(ctx context.Context, jobID uuid.UUID) (store.CalculationJob, error)
+	MarkCalculationJobRunningFunc     func(ctx context.Context, jobID uuid.UUID) (int64, error)
+	CompleteCalculationJobFunc        func(ctx context.Context, arg store.CompleteCalculationJobParams) error
+	FailCalculationJobFunc            func(ctx context.Context, arg store.FailCalculationJobParams) error
+	CancelCalculationJobFunc          func(ctx context.Context, jobID uuid.UUID) (int64, error)
+	ListPendingCalculationJobsFunc    func(ctx context.Context, limit int32) ([]store.CalculationJob, error)
+	RequeueRunningCalculationJobsFunc func(ctx context.Context) (int64, error)
+
+	HitPackingResultCacheFunc    func(ctx context.Context, cacheKey string) ([]byte, error)
+	UpsertPackingResultCacheFunc func(ctx context.Context, arg store.UpsertPackingResultCacheParams) error
+	PrunePackingResultCacheFunc  func(ctx context.Context, arg store.PrunePackingResultCacheParams) (int64, error)
+
+	CreateScenarioPlanFunc func(ctx context.Context, arg store.CreateScenarioPlanParams) (store.LoadPlan, error)
+	ListPlanScenariosFunc  func(ctx context.Context, parentPlanID *uuid.UUID) ([]store.LoadPlan, error)
+
+	GetUserPreferenceFunc    func(ctx context.Context, userID uuid.UUID) (store.UserPreference, error)
+	UpsertUserPreferenceFunc func(ctx context.Context, arg store.UpsertUserPreferenceParams) (store.UserPreference, error)
+
+	GetProductBySkuFunc    func(ctx context.Context, arg store.GetProductBySkuParams) (store.Product, error)
+	GetProductBySkuAnyFunc func(ctx context.Context, sku *string) (store.Product, error)
+
+	CreateOverflowPlanFunc     func(ctx context.Context, arg store.CreateOverflowPlanParams) (store.LoadPlan, error)
+	ListShipmentGroupPlansFunc func(ctx context.Context, shipmentGroupID *uuid.UUID) ([]store.LoadPlan, error)
+	SetPlanShipmentGroupFunc   func(ctx context.Context, arg store.SetPlanShipmentGroupParams) error
+
+	CreatePlanStatusHistoryFunc func(ctx context.Context, arg store.CreatePlanStatusHistoryParams) (store.PlanStatusHistory, error)
+	ListPlanStatusHistoryFunc   func(ctx context.Context, planID uuid.UUID) ([]store.PlanStatusHistory, error)
+
+	ActivatePlanResultFunc    func(ctx context.Context, resultID uuid.UUID) error
+	DeactivatePlanResultsFunc func(ctx context.Context, planID *uuid.UUID) error
+	GetPlanResultVersionFunc  func(ctx context.Context, arg store.GetPlanResultVersionParams) (store.PlanResult, error)
+	ListPlanResultsFunc       func(ctx context.Context, planID *uuid.UUID) ([]store.PlanResult, error)
+
+	AbandonLoadingSessionsFunc  func(ctx context.Context, planID uuid.UUID) error
+	CompleteLoadingSessionFunc  func(ctx context.Context, arg store.CompleteLoadingSessionParams) (int64, error)
+	CreateLoadingScanFunc       func(ctx context.Context, arg store.CreateLoadingScanParams) (store.LoadingScan, error)
+	CreateLoadingSessionFunc    func(ctx context.Context, arg store.CreateLoadingSessionParams) (store.LoadingSession, error)
+	GetLoadingSessionFunc       func(ctx context.Context, arg store.GetLoadingSessionParams) (store.LoadingSession, error)
+	GetOpenLoadingSessionFunc   func(ctx context.Context, planID uuid.UUID) (store.LoadingSession, error)
+	ListLoadingScansFunc        func(ctx context.Context, sessionID uuid.UUID) ([]store.LoadingScan, error)
+	ListLoadingSessionsFunc     func(ctx context.Context, planID uuid.UUID) ([]store.LoadingSession, error)
+	ListMatchedLoadingStepsFunc func(ctx context.Context, sessionID uuid.UUID) ([]*int32, error)
+	PauseLoadingSessionFunc     func(ctx context.Context, sessionID uuid.UUID) (int64, error)
+	ResumeLoadingSessionFunc    func(ctx context.Context, sessionID uuid.UUID) (int64, error)
+
+	CreateHandlingUnitFunc func(ctx context.Context, arg store.CreateHandlingUnitParams) (store.HandlingUnit, error)
+	GetWorkspaceGS1Func    func(ctx context.Context, workspaceID uuid.UUID) (store.WorkspaceGs1, error)
+	ListHandlingUnitsFunc  func(ctx context.Context, resultID uuid.UUID) ([]store.HandlingUnit, error)
+	ReserveSSCCSerialsFunc func(ctx context.Context, arg store.ReserveSSCCSerialsParams) (store.WorkspaceGs1, error)
+	UpsertWorkspaceGS1Func func(ctx context.Context, arg store.UpsertWorkspaceGS1Params) (store.WorkspaceGs1, error)
+
+	LockLoadPlanFunc func(ctx context.Context, planID uuid.UUID) (*string, error)
+
+	// ExecTxFunc stands in for running a transaction; when nil, fn runs on
+	// the mock itself.
+	ExecTxFunc func(ctx context.Context, fn func(store.Querier) error) error
 }
 
 func (m *MockQuerier) UpdateUserPassword(ctx context.Context, arg store.UpdateUserPasswordParams) error {
@@ -161,13 +219,6 @@ func (m *MockQuerier) CreatePlanResult(ctx context.Context, arg store.CreatePlan
 	return store.PlanResult{}, fmt.Errorf("CreatePlanResult not implemented")
 }
 
-func (m *MockQuerier) DeletePlanResults(ctx context.Context, planID *uuid.UUID) error {
-	if m.DeletePlanResultsFunc != nil {
-		return m.DeletePlanResultsFunc(ctx, planID)
-	}
-	return fmt.Errorf("DeletePlanResults not implemented")
-}
-
 func (m *MockQuerier) CreatePlanPlacement(ctx context.Context, arg []store.CreatePlanPlacementParams) (int64, error) {
 	if m.CreatePlanPlacementFunc != nil {
 		return m.CreatePlanPlacementFunc(ctx, arg)
@@ -883,4 +934,319 @@ func (m *MockQuerier) UpdateRefreshTokenWorkspace(ctx context.Context, arg store
 	return fmt.Errorf("UpdateRefreshTokenWorkspace not implemented")
 }
 
-var _ store.Querier = (*MockQuerier)(nil)
+func (m *MockQuerier) CreateCalculationJob(ctx context.Context, arg store.CreateCalculationJobParams) (store.CalculationJob, error) {
+	if m.CreateCalculationJobFunc != nil {
+		return m.CreateCalculationJobFunc(ctx, arg)
+	}
+	return store.CalculationJob{}, fmt.Errorf("CreateCalculationJob not implemented")
+}
+
+func (m *MockQuerier) GetCalculationJob(ctx context.Context, arg store.GetCalculationJobParams) (store.CalculationJob, error) {
+	if m.GetCalculationJobFunc != nil {
+		return m.GetCalculationJobFunc(ctx, arg)
+	}
+	return store.CalculationJob{}, fmt.Errorf("GetCalculationJob not implemented")
+}
+
+func (m *MockQuerier) GetCalculationJobAny(ctx context.Context, jobID uuid.UUID) (store.CalculationJob, error) {
+	if m.GetCalculationJobAnyFunc != nil {
+		return m.GetCalculationJobAnyFunc(ctx, jobID)
+	}
+	return store.CalculationJob{}, fmt.Errorf("GetCalculationJobAny not implemented")
+}
+
+func (m *MockQuerier) MarkCalculationJobRunning(ctx context.Context, jobID uuid.UUID) (int64, error) {
+	if m.MarkCalculationJobRunningFunc != nil {
+		return m.MarkCalculationJobRunningFunc(ctx, jobID)
+	}
+	return 0, fmt.Errorf("MarkCalculationJobRunning not implemented")
+}
+
+func (m *MockQuerier) CompleteCalculationJob(ctx context.Context, arg store.CompleteCalculationJobParams) error {
+	if m.CompleteCalculationJobFunc != nil {
+		return m.CompleteCalculationJobFunc(ctx, arg)
+	}
+	return fmt.Errorf("CompleteCalculationJob not implemented")
+}
+
+func (m *MockQuerier) FailCalculationJob(ctx context.Context, arg store.FailCalculationJobParams) error {
+	if m.FailCalculationJobFunc != nil {
+		return m.FailCalculationJobFunc(ctx, arg)
+	}
+	return fmt.Errorf("FailCalculationJob not implemented")
+}
+
+func (m *MockQuerier) CancelCalculationJob(ctx context.Context, jobID uuid.UUID) (int64, error) {
+	if m.CancelCalculationJobFunc != nil {
+		return m.CancelCalculationJobFunc(ctx, jobID)
+	}
+	return 0, fmt.Errorf("CancelCalculationJob not implemented")
+}
+
+func (m *MockQuerier) ListPendingCalculationJobs(ctx context.Context, limit int32) ([]store.CalculationJob, error) {
+	if m.ListPendingCalculationJobsFunc != nil {
+		return m.ListPendingCalculationJobsFunc(ctx, limit)
+	}
+	return nil, fmt.Errorf("ListPendingCalculationJobs not implemented")
+}
+
+func (m *MockQuerier) RequeueRunningCalculationJobs(ctx context.Context) (int64, error) {
+	if m.RequeueRunningCalculationJobsFunc != nil {
+		return m.RequeueRunningCalculationJobsFunc(ctx)
+	}
+	return 0, fmt.Errorf("RequeueRunningCalculationJobs not implemented")
+}
+
+func (m *MockQuerier) HitPackingResultCache(ctx context.Context, cacheKey string) ([]byte, error) {
+	if m.HitPackingResultCacheFunc != nil {
+		return m.HitPackingResultCacheFunc(ctx, cacheKey)
+	}
+	return nil, fmt.Errorf("HitPackingResultCache not implemented")
+}
+
+func (m *MockQuerier) PrunePackingResultCache(ctx context.Context, arg store.PrunePackingResultCacheParams) (int64, error) {
+	if m.PrunePackingResultCacheFunc != nil {
+		return m.PrunePackingResultCacheFunc(ctx, arg)
+	}
+	return 0, fmt.Errorf("PrunePackingResultCache not implemented")
+}
+
+func (m *MockQuerier) UpsertPackingResultCache(ctx context.Context, arg store.UpsertPackingResultCacheParams) error {
+	if m.UpsertPackingResultCacheFunc != nil {
+		return m.UpsertPackingResultCacheFunc(ctx, arg)
+	}
+	return fmt.Errorf("UpsertPackingResultCache not implemented")
+}
+
+func (m *MockQuerier) CreateScenarioPlan(ctx context.Context, arg store.CreateScenarioPlanParams) (store.LoadPlan, error) {
+	if m.CreateScenarioPlanFunc != nil {
+		return m.CreateScenarioPlanFunc(ctx, arg)
+	}
+	return store.LoadPlan{}, fmt.Errorf("CreateScenarioPlan not implemented")
+}
+
+func (m *MockQuerier) ListPlanScenarios(ctx context.Context, parentPlanID *uuid.UUID) ([]store.LoadPlan, error) {
+	if m.ListPlanScenariosFunc != nil {
+		return m.ListPlanScenariosFunc(ctx, parentPlanID)
+	}
+	return nil, fmt.Errorf("ListPlanScenarios not implemented")
+}
+
+func (m *MockQuerier) GetUserPreference(ctx context.Context, userID uuid.UUID) (store.UserPreference, error) {
+	if m.GetUserPreferenceFunc != nil {
+		return m.GetUserPreferenceFunc(ctx, userID)
+	}
+	return store.UserPreference{}, fmt.Errorf("GetUserPreference not implemented")
+}
+
+func (m *MockQuerier) UpsertUserPreference(ctx context.Context, arg store.UpsertUserPreferenceParams) (store.UserPreference, error) {
+	if m.UpsertUserPreferenceFunc != nil {
+		return m.UpsertUserPreferenceFunc(ctx, arg)
+	}
+	return store.UserPreference{}, fmt.Errorf("UpsertUserPreference not implemented")
+}
+
+func (m *MockQuerier) GetProductBySku(ctx context.Context, arg store.GetProductBySkuParams) (store.Product, error) {
+	if m.GetProductBySkuFunc != nil {
+		return m.GetProductBySkuFunc(ctx, arg)
+	}
+	return store.Product{}, fmt.Errorf("GetProductBySku not implemented")
+}
+
+func (m *MockQuerier) GetProductBySkuAny(ctx context.Context, sku *string) (store.Product, error) {
+	if m.GetProductBySkuAnyFunc != nil {
+		return m.GetProductBySkuAnyFunc(ctx, sku)
+	}
+	return store.Product{}, fmt.Errorf("GetProductBySkuAny not implemented")
+}
+
+func (m *MockQuerier) CreateOverflowPlan(ctx context.Context, arg store.CreateOverflowPlanParams) (store.LoadPlan, error) {
+	if m.CreateOverflowPlanFunc != nil {
+		return m.CreateOverflowPlanFunc(ctx, arg)
+	}
+	return store.LoadPlan{}, fmt.Errorf("CreateOverflowPlan not implemented")
+}
+
+func (m *MockQuerier) ListShipmentGroupPlans(ctx context.Context, shipmentGroupID *uuid.UUID) ([]store.LoadPlan, error) {
+	if m.ListShipmentGroupPlansFunc != nil {
+		return m.ListShipmentGroupPlansFunc(ctx, shipmentGroupID)
+	}
+	return nil, fmt.Errorf("ListShipmentGroupPlans not implemented")
+}
+
+func (m *MockQuerier) SetPlanShipmentGroup(ctx context.Context, arg store.SetPlanShipmentGroupParams) error {
+	if m.SetPlanShipmentGroupFunc != nil {
+		return m.SetPlanShipmentGroupFunc(ctx, arg)
+	}
+	return fmt.Errorf("SetPlanShipmentGroup not implemented")
+}
+
+func (m *MockQuerier) CreatePlanStatusHistory(ctx context.Context, arg store.CreatePlanStatusHistoryParams) (store.PlanStatusHistory, error) {
+	if m.CreatePlanStatusHistoryFunc != nil {
+		return m.CreatePlanStatusHistoryFunc(ctx, arg)
+	}
+	return store.PlanStatusHistory{}, fmt.Errorf("CreatePlanStatusHistory not implemented")
+}
+
+func (m *MockQuerier) ListPlanStatusHistory(ctx context.Context, planID uuid.UUID) ([]store.PlanStatusHistory, error) {
+	if m.ListPlanStatusHistoryFunc != nil {
+		return m.ListPlanStatusHistoryFunc(ctx, planID)
+	}
+	return nil, fmt.Errorf("ListPlanStatusHistory not implemented")
+}
+
+func (m *MockQuerier) ActivatePlanResult(ctx context.Context, resultID uuid.UUID) error {
+	if m.ActivatePlanResultFunc != nil {
+		return m.ActivatePlanResultFunc(ctx, resultID)
+	}
+	return fmt.Errorf("ActivatePlanResult not implemented")
+}
+
+func (m *MockQuerier) DeactivatePlanResults(ctx context.Context, planID *uuid.UUID) error {
+	if m.DeactivatePlanResultsFunc != nil {
+		return m.DeactivatePlanResultsFunc(ctx, planID)
+	}
+	return fmt.Errorf("DeactivatePlanResults not implemented")
+}
+
+func (m *MockQuerier) GetPlanResultVersion(ctx context.Context, arg store.GetPlanResultVersionParams) (store.PlanResult, error) {
+	if m.GetPlanResultVersionFunc != nil {
+		return m.GetPlanResultVersionFunc(ctx, arg)
+	}
+	return store.PlanResult{}, fmt.Errorf("GetPlanResultVersion not implemented")
+}
+
+func (m *MockQuerier) ListPlanResults(ctx context.Context, planID *uuid.UUID) ([]store.PlanResult, error) {
+	if m.ListPlanResultsFunc != nil {
+		return m.ListPlanResultsFunc(ctx, planID)
+	}
+	return nil, fmt.Errorf("ListPlanResults not implemented")
+}
+
+func (m *MockQuerier) AbandonLoadingSessions(ctx context.Context, planID uuid.UUID) error {
+	if m.AbandonLoadingSessionsFunc != nil {
+		return m.AbandonLoadingSessionsFunc(ctx, planID)
+	}
+	return fmt.Errorf("AbandonLoadingSessions not implemented")
+}
+
+func (m *MockQuerier) CompleteLoadingSession(ctx context.Context, arg store.CompleteLoadingSessionParams) (int64, error) {
+	if m.CompleteLoadingSessionFunc != nil {
+		return m.CompleteLoadingSessionFunc(ctx, arg)
+	}
+	return 0, fmt.Errorf("CompleteLoadingSession not implemented")
+}
+
+func (m *MockQuerier) CreateLoadingScan(ctx context.Context, arg store.CreateLoadingScanParams) (store.LoadingScan, error) {
+	if m.CreateLoadingScanFunc != nil {
+		return m.CreateLoadingScanFunc(ctx, arg)
+	}
+	return store.LoadingScan{}, fmt.Errorf("CreateLoadingScan not implemented")
+}
+
+func (m *MockQuerier) CreateLoadingSession(ctx context.Context, arg store.CreateLoadingSessionParams) (store.LoadingSession, error) {
+	if m.CreateLoadingSessionFunc != nil {
+		return m.CreateLoadingSessionFunc(ctx, arg)
+	}
+	return store.LoadingSession{}, fmt.Errorf("CreateLoadingSession not implemented")
+}
+
+func (m *MockQuerier) GetLoadingSession(ctx context.Context, arg store.GetLoadingSessionParams) (store.LoadingSession, error) {
+	if m.GetLoadingSessionFunc != nil {
+		return m.GetLoadingSessionFunc(ctx, arg)
+	}
+	return store.LoadingSession{}, fmt.Errorf("GetLoadingSession not implemented")
+}
+
+func (m *MockQuerier) GetOpenLoadingSession(ctx context.Context, planID uuid.UUID) (store.LoadingSession, error) {
+	if m.GetOpenLoadingSessionFunc != nil {
+		return m.GetOpenLoadingSessionFunc(ctx, planID)
+	}
+	return store.LoadingSession{}, fmt.Errorf("GetOpenLoadingSession not implemented")
+}
+
+func (m *MockQuerier) ListLoadingScans(ctx context.Context, sessionID uuid.UUID) ([]store.LoadingScan, error) {
+	if m.ListLoadingScansFunc != nil {
+		return m.ListLoadingScansFunc(ctx, sessionID)
+	}
+	return nil, fmt.Errorf("ListLoadingScans not implemented")
+}
+
+func (m *MockQuerier) ListLoadingSessions(ctx context.Context, planID uuid.UUID) ([]store.LoadingSession, error) {
+	if m.ListLoadingSessionsFunc != nil {
+		return m.ListLoadingSessionsFunc(ctx, planID)
+	}
+	return nil, fmt.Errorf("ListLoadingSessions not implemented")
+}
+
+func (m *MockQuerier) ListMatchedLoadingSteps(ctx context.Context, sessionID uuid.UUID) ([]*int32, error) {
+	if m.ListMatchedLoadingStepsFunc != nil {
+		return m.ListMatchedLoadingStepsFunc(ctx, sessionID)
+	}
+	return nil, fmt.Errorf("ListMatchedLoadingSteps not implemented")
+}
+
+func (m *MockQuerier) PauseLoadingSession(ctx context.Context, sessionID uuid.UUID) (int64, error) {
+	if m.PauseLoadingSessionFunc != nil {
+		return m.PauseLoadingSessionFunc(ctx, sessionID)
+	}
+	return 0, fmt.Errorf("PauseLoadingSession not implemented")
+}
+
+func (m *MockQuerier) ResumeLoadingSession(ctx context.Context, sessionID uuid.UUID) (int64, error) {
+	if m.ResumeLoadingSessionFunc != nil {
+		return m.ResumeLoadingSessionFunc(ctx, sessionID)
+	}
+	return 0, fmt.Errorf("ResumeLoadingSession not implemented")
+}
+
+func (m *MockQuerier) CreateHandlingUnit(ctx context.Context, arg store.CreateHandlingUnitParams) (store.HandlingUnit, error) {
+	if m.CreateHandlingUnitFunc != nil {
+		return m.CreateHandlingUnitFunc(ctx, arg)
+	}
+	return store.HandlingUnit{}, fmt.Errorf("CreateHandlingUnit not implemented")
+}
+
+func (m *MockQuerier) GetWorkspaceGS1(ctx context.Context, workspaceID uuid.UUID) (store.WorkspaceGs1, error) {
+	if m.GetWorkspaceGS1Func != nil {
+		return m.GetWorkspaceGS1Func(ctx, workspaceID)
+	}
+	return store.WorkspaceGs1{}, fmt.Errorf("GetWorkspaceGS1 not implemented")
+}
+
+func (m *MockQuerier) ListHandlingUnits(ctx context.Context, resultID uuid.UUID) ([]store.HandlingUnit, error) {
+	if m.ListHandlingUnitsFunc != nil {
+		return m.ListHandlingUnitsFunc(ctx, resultID)
+	}
+	return nil, fmt.Errorf("ListHandlingUnits not implemented")
+}
+
+func (m *MockQuerier) ReserveSSCCSerials(ctx context.Context, arg store.ReserveSSCCSerialsParams) (store.WorkspaceGs1, error) {
+	if m.ReserveSSCCSerialsFunc != nil {
+		return m.ReserveSSCCSerialsFunc(ctx, arg)
+	}
+	return store.WorkspaceGs1{}, fmt.Errorf("ReserveSSCCSerials not implemented")
+}
+
+func (m *MockQuerier) UpsertWorkspaceGS1(ctx context.Context, arg store.UpsertWorkspaceGS1Params) (store.WorkspaceGs1, error) {
+	if m.UpsertWorkspaceGS1Func != nil {
+		return m.UpsertWorkspaceGS1Func(ctx, arg)
+	}
+	return store.WorkspaceGs1{}, fmt.Errorf("UpsertWorkspaceGS1 not implemented")
+}
+
+func (m *MockQuerier) LockLoadPlan(ctx context.Context, planID uuid.UUID) (*string, error) {
+	if m.LockLoadPlanFunc != nil {
+		return m.LockLoadPlanFunc(ctx, planID)
+	}
+	return nil, fmt.Errorf("LockLoadPlan not implemented")
+}
+
+func (m *MockQuerier) ExecTx(ctx context.Context, fn func(store.Querier) error) error {
+	if m.ExecTxFunc != nil {
+		return m.ExecTxFunc(ctx, fn)
+	}
+	return fn(m)
+}
+
+var _ store.TxQuerier = (*MockQuerier)(nil)
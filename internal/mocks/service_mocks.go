@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/ekastn/load-stuffing-calculator/internal/dto"
+	"github.com/ekastn/load-stuffing-calculator/internal/units"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/mock"
 )
@@ -256,6 +257,14 @@ func (m *MockProductService) GetProduct(ctx context.Context, id string) (*dto.Pr
 	return args.Get(0).(*dto.ProductResponse), args.Error(1)
 }
 
+func (m *MockProductService) GetProductBySKU(ctx context.Context, sku string) (*dto.ProductResponse, error) {
+	args := m.Called(ctx, sku)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.ProductResponse), args.Error(1)
+}
+
 func (m *MockProductService) ListProducts(ctx context.Context, page, limit int32) ([]dto.ProductResponse, error) {
 	args := m.Called(ctx, page, limit)
 	if args.Get(0) == nil {
@@ -347,6 +356,134 @@ func (m *MockPlanService) CalculatePlan(ctx context.Context, planID string, opts
 	return args.Get(0).(*dto.CalculationResult), args.Error(1)
 }
 
+func (m *MockPlanService) CalculatePlanWithProgress(ctx context.Context, planID string, opts dto.CalculatePlanRequest, onProgress func(dto.CalculationProgress)) (*dto.CalculationResult, error) {
+	args := m.Called(ctx, planID, opts, onProgress)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.CalculationResult), args.Error(1)
+}
+
+func (m *MockPlanService) ComparePlan(ctx context.Context, planID string, req dto.ComparePlanRequest) (*dto.ComparePlanResponse, error) {
+	args := m.Called(ctx, planID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.ComparePlanResponse), args.Error(1)
+}
+
+func (m *MockPlanService) AdoptAlternative(ctx context.Context, planID, alternativeID string) (*dto.CalculationResult, error) {
+	args := m.Called(ctx, planID, alternativeID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.CalculationResult), args.Error(1)
+}
+
+func (m *MockPlanService) CreateScenario(ctx context.Context, planID string, req dto.CreateScenarioRequest) (*dto.ScenarioResponse, error) {
+	args := m.Called(ctx, planID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.ScenarioResponse), args.Error(1)
+}
+
+func (m *MockPlanService) ListScenarios(ctx context.Context, planID string) ([]dto.ScenarioResponse, error) {
+	args := m.Called(ctx, planID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dto.ScenarioResponse), args.Error(1)
+}
+
+func (m *MockPlanService) CompareScenarios(ctx context.Context, planID string) (*dto.ScenarioComparisonResponse, error) {
+	args := m.Called(ctx, planID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.ScenarioComparisonResponse), args.Error(1)
+}
+
+func (m *MockPlanService) PreflightPlan(ctx context.Context, planID string) (*dto.PreflightResponse, error) {
+	args := m.Called(ctx, planID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.PreflightResponse), args.Error(1)
+}
+
+func (m *MockPlanService) CreateOverflowPlan(ctx context.Context, planID string, req dto.CreateOverflowPlanRequest) (*dto.OverflowPlanResponse, error) {
+	args := m.Called(ctx, planID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.OverflowPlanResponse), args.Error(1)
+}
+
+func (m *MockPlanService) GetShipmentGroup(ctx context.Context, planID string) (*dto.ShipmentGroupResponse, error) {
+	args := m.Called(ctx, planID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.ShipmentGroupResponse), args.Error(1)
+}
+
+func (m *MockPlanService) GetPlanStatusHistory(ctx context.Context, planID string) ([]dto.PlanStatusChange, error) {
+	args := m.Called(ctx, planID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dto.PlanStatusChange), args.Error(1)
+}
+
+func (m *MockPlanService) ListResultVersions(ctx context.Context, planID string) ([]dto.ResultVersion, error) {
+	args := m.Called(ctx, planID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dto.ResultVersion), args.Error(1)
+}
+
+func (m *MockPlanService) GetResultVersion(ctx context.Context, planID string, version int) (*dto.ResultVersion, error) {
+	args := m.Called(ctx, planID, version)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.ResultVersion), args.Error(1)
+}
+
+func (m *MockPlanService) RestoreResultVersion(ctx context.Context, planID string, version int) (*dto.ResultVersion, error) {
+	args := m.Called(ctx, planID, version)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.ResultVersion), args.Error(1)
+}
+
+func (m *MockPlanService) DiffResultVersions(ctx context.Context, planID string, from, to int) (*dto.ResultVersionDiff, error) {
+	args := m.Called(ctx, planID, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.ResultVersionDiff), args.Error(1)
+}
+
+func (m *MockPlanService) AssignHandlingUnits(ctx context.Context, planID string) ([]dto.HandlingUnit, error) {
+	args := m.Called(ctx, planID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dto.HandlingUnit), args.Error(1)
+}
+
+func (m *MockPlanService) ListHandlingUnits(ctx context.Context, planID string) ([]dto.HandlingUnit, error) {
+	args := m.Called(ctx, planID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dto.HandlingUnit), args.Error(1)
+}
+
 // MockInviteService is a mock implementation of service.InviteService
 type MockInviteService struct {
 	mock.Mock
@@ -461,3 +598,168 @@ func (m *MockWorkspaceService) DeleteWorkspace(ctx context.Context, id string) e
 	args := m.Called(ctx, id)
 	return args.Error(0)
 }
+
+func (m *MockWorkspaceService) GetGS1Settings(ctx context.Context, id string) (*dto.GS1SettingsResponse, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.GS1SettingsResponse), args.Error(1)
+}
+
+func (m *MockWorkspaceService) UpdateGS1Settings(ctx context.Context, id string, req dto.UpdateGS1SettingsRequest) (*dto.GS1SettingsResponse, error) {
+	args := m.Called(ctx, id, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.GS1SettingsResponse), args.Error(1)
+}
+
+// MockCalculationJobService is a mock implementation of service.CalculationJobService
+type MockCalculationJobService struct {
+	mock.Mock
+}
+
+func (m *MockCalculationJobService) EnqueueCalculation(ctx context.Context, planID string, opts dto.CalculatePlanRequest) (*dto.CalculationJobResponse, error) {
+	args := m.Called(ctx, planID, opts)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.CalculationJobResponse), args.Error(1)
+}
+
+func (m *MockCalculationJobService) GetCalculationJob(ctx context.Context, planID, jobID string) (*dto.CalculationJobResponse, error) {
+	args := m.Called(ctx, planID, jobID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.CalculationJobResponse), args.Error(1)
+}
+
+func (m *MockCalculationJobService) CancelCalculationJob(ctx context.Context, planID, jobID string) (*dto.CalculationJobResponse, error) {
+	args := m.Called(ctx, planID, jobID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.CalculationJobResponse), args.Error(1)
+}
+
+func (m *MockCalculationJobService) Start(ctx context.Context) {
+	m.Called(ctx)
+}
+
+// MockLoadingService is a mock implementation of service.LoadingService
+type MockLoadingService struct {
+	mock.Mock
+}
+
+func (m *MockLoadingService) StartSession(ctx context.Context, planID string) (*dto.LoadingSessionResponse, error) {
+	args := m.Called(ctx, planID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.LoadingSessionResponse), args.Error(1)
+}
+
+func (m *MockLoadingService) GetSession(ctx context.Context, planID, sessionID string) (*dto.LoadingSessionResponse, error) {
+	args := m.Called(ctx, planID, sessionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.LoadingSessionResponse), args.Error(1)
+}
+
+func (m *MockLoadingService) ListSessions(ctx context.Context, planID string) ([]dto.LoadingSessionResponse, error) {
+	args := m.Called(ctx, planID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dto.LoadingSessionResponse), args.Error(1)
+}
+
+func (m *MockLoadingService) PauseSession(ctx context.Context, planID, sessionID string) (*dto.LoadingSessionResponse, error) {
+	args := m.Called(ctx, planID, sessionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.LoadingSessionResponse), args.Error(1)
+}
+
+func (m *MockLoadingService) CompleteSession(ctx context.Context, planID, sessionID string) (*dto.LoadingSessionResponse, error) {
+	args := m.Called(ctx, planID, sessionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.LoadingSessionResponse), args.Error(1)
+}
+
+func (m *MockLoadingService) RecordScan(ctx context.Context, planID, sessionID string, req dto.LoadingScanRequest) (*dto.LoadingScanResponse, error) {
+	args := m.Called(ctx, planID, sessionID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.LoadingScanResponse), args.Error(1)
+}
+
+func (m *MockLoadingService) ListScans(ctx context.Context, planID, sessionID string) ([]dto.LoadingScanDetail, error) {
+	args := m.Called(ctx, planID, sessionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]dto.LoadingScanDetail), args.Error(1)
+}
+
+func (m *MockLoadingService) OfflineBundle(ctx context.Context, planID string) (*dto.OfflineBundle, error) {
+	args := m.Called(ctx, planID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.OfflineBundle), args.Error(1)
+}
+
+func (m *MockLoadingService) SyncOfflineScans(ctx context.Context, planID string, req dto.OfflineSyncRequest) (*dto.OfflineSyncReport, error) {
+	args := m.Called(ctx, planID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.OfflineSyncReport), args.Error(1)
+}
+
+// MockPreferenceService is a mock implementation of service.PreferenceService
+type MockPreferenceService struct {
+	mock.Mock
+}
+
+func (m *MockPreferenceService) GetPreferences(ctx context.Context) (*dto.PreferencesResponse, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.PreferencesResponse), args.Error(1)
+}
+
+func (m *MockPreferenceService) UpdatePreferences(ctx context.Context, req dto.UpdatePreferencesRequest) (*dto.PreferencesResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.PreferencesResponse), args.Error(1)
+}
+
+func (m *MockPreferenceService) UnitsForUser(ctx context.Context, userID string) (units.System, bool) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(units.System), args.Bool(1)
+}
+
+// MockCapacityService is a mock implementation of service.CapacityService
+type MockCapacityService struct {
+	mock.Mock
+}
+
+func (m *MockCapacityService) MaxQuantity(ctx context.Context, req dto.MaxQuantityRequest) (*dto.MaxQuantityResponse, error) {
+	args := m.Called(ctx, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*dto.MaxQuantityResponse), args.Error(1)
+}
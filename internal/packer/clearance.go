@@ -0,0 +1,110 @@
+package packer
+
+// Clearances reserve space the packing algorithms must leave empty:
+//
+//   - ItemInput.Padding is added to every side of each unit (bulging cartons).
+//   - ContainerInput.WallClearance is kept free along the side walls, both
+//     ends and the ceiling. Units still stand on the floor.
+//   - ContainerInput.ItemGap is the minimum distance between two units.
+//
+// Backends pack "envelopes": every unit grows by 2*Padding+ItemGap per axis
+// and the container shrinks by the wall clearance but gains one ItemGap, so
+// the last unit on an axis does not need a gap behind it. Within its envelope
+// a unit is inset by its padding horizontally and rests on the envelope floor.
+
+// HasClearances reports whether any padding, clearance or gap is requested.
+func HasClearances(container ContainerInput, items []ItemInput) bool {
+	if container.WallClearance > 0 || container.ItemGap > 0 {
+		return true
+	}
+	for _, it := range items {
+		if it.Padding > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// ApplyClearances returns the container and items a backend should pack.
+// Inputs without clearances are returned unchanged.
+func ApplyClearances(container ContainerInput, items []ItemInput) (ContainerInput, []ItemInput) {
+	if !HasClearances(container, items) {
+		return container, items
+	}
+
+	wall := nonNegative(container.WallClearance)
+	gap := nonNegative(container.ItemGap)
+
+	packBox := container
+	packBox.Length = container.Length - 2*wall + gap
+	packBox.Width = container.Width - 2*wall + gap
+	packBox.Height = container.Height - wall + gap
+
+	packItems := make([]ItemInput, len(items))
+	for i, it := range items {
+		grow := envelopeGrowth(it, gap)
+		packItems[i] = it
+		packItems[i].Length += grow
+		packItems[i].Width += grow
+		packItems[i].Height += grow
+	}
+	return packBox, packItems
+}
+
+// RemoveClearances converts a result packed from ApplyClearances' output back
+// to the real container: placements get the true item dimensions and
+// positions, unfit items get their original inputs back and utilisation is
+// measured against the real container. items and container are the inputs
+// originally passed to ApplyClearances.
+func RemoveClearances(container ContainerInput, items []ItemInput, result *PackingResult) {
+	if !HasClearances(container, items) {
+		return
+	}
+
+	wall := nonNegative(container.WallClearance)
+	gap := nonNegative(container.ItemGap)
+
+	byID := make(map[string]ItemInput, len(items))
+	for _, it := range items {
+		byID[it.ID] = it
+	}
+
+	result.TotalVolumePackedM3 = 0
+	for i := range result.PackedItems {
+		pi := &result.PackedItems[i]
+		pad := nonNegative(byID[pi.ItemID].Padding)
+		grow := 2*pad + gap
+
+		pi.RotatedLength -= grow
+		pi.RotatedWidth -= grow
+		pi.RotatedHeight -= grow
+		pi.Position.X += wall + pad
+		pi.Position.Y += wall + pad
+
+		result.TotalVolumePackedM3 += pi.RotatedLength * pi.RotatedWidth * pi.RotatedHeight
+	}
+	result.TotalVolumePackedM3 /= 1_000_000_000.0 // mm3 to m3
+
+	for i, unfit := range result.UnfitItems {
+		if orig, ok := byID[unfit.ID]; ok {
+			orig.Quantity = unfit.Quantity
+			result.UnfitItems[i] = orig
+		}
+	}
+
+	result.VolumeUtilisationPct = 0
+	if contVol := container.Length * container.Width * container.Height; contVol > 0 {
+		result.VolumeUtilisationPct = result.TotalVolumePackedM3 * 1_000_000_000.0 / contVol * 100
+	}
+}
+
+func envelopeGrowth(it ItemInput, gap float64) float64 {
+	return 2*nonNegative(it.Padding) + gap
+}
+
+func nonNegative(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	return v
+}
@@ -0,0 +1,99 @@
+package packer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ekastn/load-stuffing-calculator/internal/packer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyClearances(t *testing.T) {
+	container := packer.ContainerInput{ID: "c", Length: 1000, Width: 500, Height: 400, WallClearance: 20, ItemGap: 10}
+	items := []packer.ItemInput{{ID: "a", Length: 100, Width: 50, Height: 40, Quantity: 1, Padding: 5}}
+
+	box, packItems := packer.ApplyClearances(container, items)
+
+	assert.Equal(t, 970.0, box.Length)
+	assert.Equal(t, 470.0, box.Width)
+	assert.Equal(t, 390.0, box.Height) // ceiling only, units stand on the floor
+	assert.Equal(t, 120.0, packItems[0].Length)
+	assert.Equal(t, 70.0, packItems[0].Width)
+	assert.Equal(t, 60.0, packItems[0].Height)
+
+	// The caller's slice is not modified.
+	assert.Equal(t, 100.0, items[0].Length)
+
+	t.Run("no_clearances_is_a_no_op", func(t *testing.T) {
+		plain := packer.ContainerInput{ID: "c", Length: 1000, Width: 500, Height: 400}
+		box, packItems := packer.ApplyClearances(plain, []packer.ItemInput{{ID: "a", Length: 100}})
+		assert.Equal(t, plain, box)
+		assert.Equal(t, 100.0, packItems[0].Length)
+		assert.False(t, packer.HasClearances(plain, packItems))
+	})
+}
+
+func TestPacker_Pack_Clearances(t *testing.T) {
+	const (
+		wall    = 20.0
+		gap     = 10.0
+		padding = 5.0
+	)
+	container := packer.ContainerInput{
+		ID:            "CONT-CLR",
+		Length:        1000,
+		Width:         500,
+		Height:        400,
+		MaxWeight:     1000,
+		WallClearance: wall,
+		ItemGap:       gap,
+		Options:       packer.PackOptions{Gravity: true},
+	}
+	items := []packer.ItemInput{
+		{ID: "BOX", Label: "Carton", Length: 100, Width: 100, Height: 100, Weight: 1, Quantity: 200, AllowRotation: true, Padding: padding},
+	}
+
+	res, err := packer.NewPacker().Pack(context.Background(), container, items)
+	require.NoError(t, err)
+	require.NotEmpty(t, res.PackedItems)
+	require.NotEmpty(t, res.UnfitItems, "200 cartons fill the container only without clearances")
+
+	const eps = 1e-6
+	for _, pi := range res.PackedItems {
+		// True dimensions are reported.
+		assert.Equal(t, 100.0, pi.RotatedLength)
+		assert.Equal(t, 100.0, pi.RotatedWidth)
+		assert.Equal(t, 100.0, pi.RotatedHeight)
+
+		assert.GreaterOrEqual(t, pi.Position.X, wall+padding-eps)
+		assert.GreaterOrEqual(t, pi.Position.Y, wall+padding-eps)
+		assert.GreaterOrEqual(t, pi.Position.Z, 0.0)
+		assert.LessOrEqual(t, pi.Position.X+pi.RotatedLength, container.Length-wall-padding+eps)
+		assert.LessOrEqual(t, pi.Position.Y+pi.RotatedWidth, container.Width-wall-padding+eps)
+		assert.LessOrEqual(t, pi.Position.Z+pi.RotatedHeight, container.Height-wall+eps)
+	}
+
+	// Neighbouring cartons are apart by both paddings plus the gap on at
+	// least one axis.
+	minDist := 2*padding + gap
+	apart := func(a1, a2, b1, b2 float64) bool {
+		return b1-a2 >= minDist-eps || a1-b2 >= minDist-eps
+	}
+	for i := range res.PackedItems {
+		for j := i + 1; j < len(res.PackedItems); j++ {
+			a, b := res.PackedItems[i], res.PackedItems[j]
+			ok := apart(a.Position.X, a.Position.X+a.RotatedLength, b.Position.X, b.Position.X+b.RotatedLength) ||
+				apart(a.Position.Y, a.Position.Y+a.RotatedWidth, b.Position.Y, b.Position.Y+b.RotatedWidth) ||
+				apart(a.Position.Z, a.Position.Z+a.RotatedHeight, b.Position.Z, b.Position.Z+b.RotatedHeight)
+			assert.True(t, ok, "%s and %s are too close", a.InstanceID, b.InstanceID)
+		}
+	}
+
+	// Unfit items keep the caller's dimensions and utilisation uses the real container.
+	assert.Equal(t, 100.0, res.UnfitItems[0].Length)
+	assert.Equal(t, padding, res.UnfitItems[0].Padding)
+	wantVol := float64(len(res.PackedItems)) * 0.001
+	assert.InDelta(t, wantVol, res.TotalVolumePackedM3, 1e-9)
+	assert.InDelta(t, wantVol/0.2*100, res.VolumeUtilisationPct, 1e-6)
+}
@@ -0,0 +1,245 @@
+package packer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Temperature classes of compartments and items.
+const (
+	TemperatureAmbient = "ambient"
+	TemperatureChilled = "chilled"
+	TemperatureFrozen  = "frozen"
+)
+
+// ConstraintTemperature is reported for units no compartment can take.
+const ConstraintTemperature = "temperature"
+
+// UnfitTemperature diagnoses units whose temperature class matches no
+// compartment of the container.
+const UnfitTemperature = "temperature"
+
+// Compartment is a section of the container between two bulkheads. It spans
+// the full width and height and runs from Start to Start+Length along the
+// container.
+type Compartment struct {
+	Name             string
+	TemperatureClass string  // ambient when empty
+	Start            float64 // mm from the front of the container
+	Length           float64 // mm
+	MaxWeight        float64 // kg; 0 leaves only the container limit
+}
+
+// CompartmentUsage is the load of one compartment.
+type CompartmentUsage struct {
+	Compartment
+	Units                int
+	VolumeM3             float64
+	WeightKG             float64
+	VolumeUtilisationPct float64 // 0-100
+	WeightUtilisationPct float64 // 0-100; 0 without a compartment limit
+}
+
+// TemperatureCompatible reports whether an item of class item may ride in a
+// compartment of class compartment. Classes must match; empty means ambient.
+func TemperatureCompatible(item, compartment string) bool {
+	return temperatureClass(item) == temperatureClass(compartment)
+}
+
+func temperatureClass(c string) string {
+	if c == "" {
+		return TemperatureAmbient
+	}
+	return c
+}
+
+// ValidateCompartments checks that the compartments lie inside the container,
+// do not overlap and use a known temperature class.
+func ValidateCompartments(container ContainerInput) error {
+	comps := append([]Compartment(nil), container.Compartments...)
+	sort.Slice(comps, func(i, j int) bool { return comps[i].Start < comps[j].Start })
+
+	end := 0.0
+	for _, c := range comps {
+		switch temperatureClass(c.TemperatureClass) {
+		case TemperatureAmbient, TemperatureChilled, TemperatureFrozen:
+		default:
+			return fmt.Errorf("compartment %q: unknown temperature class %q", c.Name, c.TemperatureClass)
+		}
+		if c.Start < 0 || c.Length <= 0 || c.Start+c.Length > container.Length+1e-6 {
+			return fmt.Errorf("compartment %q does not lie inside the %.0f mm container", c.Name, container.Length)
+		}
+		if c.Start < end-1e-6 {
+			return fmt.Errorf("compartment %q overlaps the compartment before it", c.Name)
+		}
+		end = c.Start + c.Length
+	}
+	return nil
+}
+
+// PackCompartments packs items into the container's compartments in order,
+// each unit only into compartments of its temperature class. Every
+// compartment is packed with PackPrioritized as a container of its own;
+// units left over move on to the next compatible compartment. The
+// container's MaxWeight still caps the whole load.
+//
+// Without compartments it is a plain PackPrioritized call.
+func PackCompartments(ctx context.Context, p Packer, container ContainerInput, items []ItemInput) (PackingResult, error) {
+	if len(container.Compartments) == 0 {
+		return PackPrioritized(ctx, p, container, items)
+	}
+
+	start := time.Now()
+	remaining := cloneItems(items)
+	out := PackingResult{ContainerID: container.ID, PackedItems: []PackedItem{}}
+
+	for _, c := range container.Compartments {
+		sub := compartmentContainer(container, c)
+		if container.MaxWeight > 0 {
+			left := container.MaxWeight - out.TotalWeightPackedKG
+			if left <= 0 {
+				break
+			}
+			if sub.MaxWeight == 0 || left < sub.MaxWeight {
+				sub.MaxWeight = left
+			}
+		}
+
+		var fit []ItemInput
+		for _, it := range remaining {
+			if it.Quantity > 0 && TemperatureCompatible(it.TemperatureClass, c.TemperatureClass) {
+				fit = append(fit, it)
+			}
+		}
+		if len(fit) == 0 {
+			continue
+		}
+
+		res, err := PackPrioritized(ctx, p, sub, fit)
+		if err != nil {
+			return PackingResult{}, err
+		}
+
+		placed := make(map[string]int)
+		for _, pi := range res.PackedItems {
+			pi.Position.X += c.Start
+			out.PackedItems = append(out.PackedItems, pi)
+			placed[pi.ItemID]++
+		}
+		for i := range remaining {
+			remaining[i].Quantity -= placed[remaining[i].ID]
+		}
+		out.TotalWeightPackedKG += res.TotalWeightPackedKG
+		out.TotalVolumePackedM3 += res.TotalVolumePackedM3
+		if out.Algorithm == "" {
+			out.Algorithm = res.Algorithm
+		}
+	}
+
+	for _, it := range remaining {
+		if it.Quantity > 0 {
+			out.UnfitItems = append(out.UnfitItems, it)
+		}
+	}
+	out.TotalPackedItems = len(out.PackedItems)
+	out.IsFeasible = len(out.UnfitItems) == 0
+	if contVolM3 := container.Length * container.Width * container.Height / 1_000_000_000.0; contVolM3 > 0 {
+		out.VolumeUtilisationPct = out.TotalVolumePackedM3 / contVolM3 * 100
+	}
+	if container.MaxWeight > 0 {
+		out.WeightUtilisationPct = out.TotalWeightPackedKG / container.MaxWeight * 100
+	}
+	if out.Algorithm != "" {
+		out.Algorithm += "+compartments"
+	}
+	out.DurationMs = time.Since(start).Milliseconds()
+	return out, nil
+}
+
+// CompartmentUsages reports the load of every compartment. A unit belongs to
+// the compartment holding its centre.
+func CompartmentUsages(container ContainerInput, packed []PackedItem, items []ItemInput) []CompartmentUsage {
+	if len(container.Compartments) == 0 {
+		return nil
+	}
+	weights := make(map[string]float64, len(items))
+	for _, it := range items {
+		weights[it.ID] = it.Weight
+	}
+
+	out := make([]CompartmentUsage, len(container.Compartments))
+	for i, c := range container.Compartments {
+		out[i].Compartment = c
+	}
+	for _, pi := range packed {
+		centre := pi.Position.X + pi.RotatedLength/2
+		for i, c := range container.Compartments {
+			if centre >= c.Start && centre < c.Start+c.Length {
+				out[i].Units++
+				out[i].VolumeM3 += pi.RotatedLength * pi.RotatedWidth * pi.RotatedHeight / 1_000_000_000.0
+				out[i].WeightKG += weights[pi.ItemID]
+				break
+			}
+		}
+	}
+	for i, c := range container.Compartments {
+		if volM3 := c.Length * container.Width * container.Height / 1_000_000_000.0; volM3 > 0 {
+			out[i].VolumeUtilisationPct = out[i].VolumeM3 / volM3 * 100
+		}
+		if c.MaxWeight > 0 {
+			out[i].WeightUtilisationPct = out[i].WeightKG / c.MaxWeight * 100
+		}
+	}
+	return out
+}
+
+// compartmentContainer is the container a compartment is packed as.
+func compartmentContainer(container ContainerInput, c Compartment) ContainerInput {
+	sub := container
+	sub.ID = container.ID + "/" + c.Name
+	sub.Length = c.Length
+	sub.MaxWeight = c.MaxWeight
+	sub.Compartments = nil
+	return sub
+}
+
+// compatibleCompartments lists the compartments that can take it.
+func compatibleCompartments(container ContainerInput, it ItemInput) []Compartment {
+	var out []Compartment
+	for _, c := range container.Compartments {
+		if TemperatureCompatible(it.TemperatureClass, c.TemperatureClass) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// checkCompartments is checkUnit for a container with compartments: the unit
+// must fit at least one compartment of its temperature class.
+func checkCompartments(container ContainerInput, it ItemInput) (UnfitDiagnosis, bool) {
+	comps := compatibleCompartments(container, it)
+	if len(comps) == 0 {
+		return UnfitDiagnosis{
+			Code:    UnfitTemperature,
+			Message: fmt.Sprintf("no compartment takes %s cargo", temperatureClass(it.TemperatureClass)),
+		}, false
+	}
+
+	var first UnfitDiagnosis
+	for i, c := range comps {
+		sub := compartmentContainer(container, c)
+		if sub.MaxWeight == 0 || (container.MaxWeight > 0 && container.MaxWeight < sub.MaxWeight) {
+			sub.MaxWeight = container.MaxWeight
+		}
+		d, ok := checkUnit(sub, it)
+		if ok {
+			return d, true
+		}
+		if i == 0 {
+			first = d
+		}
+	}
+	return first, false
+}
@@ -0,0 +1,119 @@
+package packer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ekastn/load-stuffing-calculator/internal/packer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPackCompartments(t *testing.T) {
+	ctx := context.Background()
+	container := packer.ContainerInput{
+		ID: "C", Length: 2000, Width: 1000, Height: 1000, MaxWeight: 100,
+		Compartments: []packer.Compartment{
+			{Name: "dry", TemperatureClass: packer.TemperatureAmbient, Start: 0, Length: 1000},
+			{Name: "cold", TemperatureClass: packer.TemperatureChilled, Start: 1000, Length: 1000, MaxWeight: 5},
+		},
+	}
+
+	t.Run("keeps_classes_apart", func(t *testing.T) {
+		dry := cube("DRY", 4)
+		cold := cube("COLD", 4)
+		cold.TemperatureClass = packer.TemperatureChilled
+
+		res, err := packer.PackCompartments(ctx, packer.NewPacker(), container, []packer.ItemInput{dry, cold})
+		require.NoError(t, err)
+
+		assert.True(t, res.IsFeasible)
+		assert.Equal(t, map[string]int{"DRY": 4, "COLD": 4}, placedByID(res))
+		for _, pi := range res.PackedItems {
+			if pi.ItemID == "COLD" {
+				assert.GreaterOrEqual(t, pi.Position.X, 1000.0)
+			} else {
+				assert.Less(t, pi.Position.X+pi.RotatedLength, 1000.0+1e-6)
+			}
+		}
+		assert.Contains(t, res.Algorithm, "+compartments")
+		assert.InDelta(t, 8.0, res.TotalWeightPackedKG, 1e-9)
+
+		usage := packer.CompartmentUsages(container, res.PackedItems, []packer.ItemInput{dry, cold})
+		require.Len(t, usage, 2)
+		assert.Equal(t, 4, usage[0].Units)
+		assert.InDelta(t, 50.0, usage[0].VolumeUtilisationPct, 1e-6)
+		assert.Zero(t, usage[0].WeightUtilisationPct)
+		assert.Equal(t, 4, usage[1].Units)
+		assert.InDelta(t, 80.0, usage[1].WeightUtilisationPct, 1e-6)
+	})
+
+	t.Run("compartment_weight_limit", func(t *testing.T) {
+		cold := cube("COLD", 8)
+		cold.TemperatureClass = packer.TemperatureChilled
+
+		res, err := packer.PackCompartments(ctx, packer.NewPacker(), container, []packer.ItemInput{cold})
+		require.NoError(t, err)
+
+		assert.False(t, res.IsFeasible)
+		assert.Equal(t, map[string]int{"COLD": 5}, placedByID(res))
+		require.Len(t, res.UnfitItems, 1)
+		assert.Equal(t, 3, res.UnfitItems[0].Quantity)
+	})
+
+	t.Run("no_compatible_compartment", func(t *testing.T) {
+		frozen := cube("FROZEN", 2)
+		frozen.TemperatureClass = packer.TemperatureFrozen
+
+		res, err := packer.PackCompartments(ctx, packer.NewPacker(), container, []packer.ItemInput{frozen})
+		require.NoError(t, err)
+
+		assert.Empty(t, res.PackedItems)
+		require.Len(t, res.UnfitItems, 1)
+		assert.Equal(t, packer.ConstraintTemperature, packer.UnfitConstraint(container, res, res.UnfitItems[0]))
+		assert.Equal(t, packer.UnfitTemperature, packer.DiagnoseUnfit(container, res)[0].Code)
+
+		report := packer.Preflight(container, []packer.ItemInput{frozen})
+		require.Len(t, report.Issues, 1)
+		assert.Equal(t, packer.UnfitTemperature, report.Issues[0].Code)
+	})
+
+	t.Run("without_compartments_ignores_classes", func(t *testing.T) {
+		c := container
+		c.Compartments = nil
+		frozen := cube("FROZEN", 2)
+		frozen.TemperatureClass = packer.TemperatureFrozen
+
+		res, err := packer.PackCompartments(ctx, packer.NewPacker(), c, []packer.ItemInput{frozen})
+		require.NoError(t, err)
+		assert.True(t, res.IsFeasible)
+	})
+}
+
+func TestValidateCompartments(t *testing.T) {
+	container := packer.ContainerInput{Length: 2000, Width: 1000, Height: 1000}
+
+	tests := []struct {
+		name  string
+		comps []packer.Compartment
+		ok    bool
+	}{
+		{"none", nil, true},
+		{"adjacent", []packer.Compartment{{Name: "a", Length: 1000}, {Name: "b", Start: 1000, Length: 1000, TemperatureClass: "frozen"}}, true},
+		{"overlap", []packer.Compartment{{Name: "a", Length: 1200}, {Name: "b", Start: 1000, Length: 1000}}, false},
+		{"outside", []packer.Compartment{{Name: "a", Start: 1500, Length: 1000}}, false},
+		{"unknown_class", []packer.Compartment{{Name: "a", Length: 1000, TemperatureClass: "hot"}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := container
+			c.Compartments = tt.comps
+			err := packer.ValidateCompartments(c)
+			if tt.ok {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
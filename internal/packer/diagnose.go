@@ -0,0 +1,182 @@
+package packer
+
+import (
+	"fmt"
+	"math"
+)
+
+// Diagnosis codes for units that could not be placed.
+const (
+	UnfitTooLarge    = "too_large"   // larger than the empty container in every allowed orientation
+	UnfitOverweight  = "overweight"  // would push the load over MaxWeight
+	UnfitNoSpace     = "no_space"    // no remaining space is large enough
+	UnfitOrientation = "orientation" // only a rotation the item forbids would fit
+	UnfitStacking    = "stacking"    // space is left only on top of other units
+	UnfitUnplaced    = "unplaced"    // a large enough gap remains; the strategy did not use it
+)
+
+// UnfitDiagnosis explains why the units of one unfit item were not placed.
+type UnfitDiagnosis struct {
+	Code    string
+	Message string
+}
+
+// PreflightIssue is a problem found before packing.
+type PreflightIssue struct {
+	ItemID   string
+	Label    string
+	Quantity int
+	Code     string
+	Message  string
+}
+
+// PreflightReport summarises the checks Preflight runs.
+type PreflightReport struct {
+	Issues        []PreflightIssue
+	TotalWeightKG float64
+	TotalVolumeM3 float64
+	Overweight    bool // all units together exceed MaxWeight
+	OverVolume    bool // all units together exceed the container volume
+}
+
+// DiagnoseUnfit explains every entry of res.UnfitItems, in the same order.
+func DiagnoseUnfit(container ContainerInput, res PackingResult) []UnfitDiagnosis {
+	if len(res.UnfitItems) == 0 {
+		return nil
+	}
+
+	minSide := math.Inf(1)
+	for _, it := range res.UnfitItems {
+		d := paddedDims(it)
+		minSide = math.Min(minSide, math.Min(d[0], math.Min(d[1], d[2])))
+	}
+	voids := FindVoids(container, res.PackedItems, minSide)
+
+	out := make([]UnfitDiagnosis, 0, len(res.UnfitItems))
+	for _, it := range res.UnfitItems {
+		out = append(out, diagnoseItem(container, res, voids, it))
+	}
+	return out
+}
+
+// Preflight finds items that can never be placed and loads that are over
+// the container's weight or volume, without packing anything.
+func Preflight(container ContainerInput, items []ItemInput) PreflightReport {
+	var r PreflightReport
+	for _, it := range items {
+		r.TotalWeightKG += it.Weight * float64(it.Quantity)
+		r.TotalVolumeM3 += it.Length * it.Width * it.Height / 1_000_000_000.0 * float64(it.Quantity)
+
+		if d, ok := checkUnit(container, it); !ok {
+			r.Issues = append(r.Issues, PreflightIssue{
+				ItemID:   it.ID,
+				Label:    it.Label,
+				Quantity: it.Quantity,
+				Code:     d.Code,
+				Message:  d.Message,
+			})
+		}
+	}
+
+	r.Overweight = container.MaxWeight > 0 && r.TotalWeightKG > container.MaxWeight
+	contVolM3 := container.Length * container.Width * container.Height / 1_000_000_000.0
+	r.OverVolume = r.TotalVolumeM3 > contVolM3
+	return r
+}
+
+func diagnoseItem(container ContainerInput, res PackingResult, voids []Void, it ItemInput) UnfitDiagnosis {
+	if d, ok := checkUnit(container, it); !ok {
+		return d
+	}
+
+	if container.MaxWeight > 0 && res.TotalWeightPackedKG+it.Weight > container.MaxWeight {
+		return UnfitDiagnosis{
+			Code: UnfitOverweight,
+			Message: fmt.Sprintf("adding a unit of %.2f kg to the %.2f kg loaded exceeds the %.2f kg limit",
+				it.Weight, res.TotalWeightPackedKG, container.MaxWeight),
+		}
+	}
+
+	dims := paddedDims(it)
+	rotatedOnly, aboveFloorOnly := false, true
+	found := false
+	for _, v := range voids {
+		space := [3]float64{v.Length, v.Width, v.Height}
+		if fitsAny(dims, space, it.AllowRotation) {
+			found = true
+			if v.Position.Z == 0 {
+				aboveFloorOnly = false
+			}
+		} else if fitsAny(dims, space, true) {
+			rotatedOnly = true
+		}
+	}
+
+	switch {
+	case found && aboveFloorOnly:
+		return UnfitDiagnosis{Code: UnfitStacking, Message: "space is left only on top of other units"}
+	case found:
+		return UnfitDiagnosis{Code: UnfitUnplaced, Message: "a large enough gap remains; another strategy may place the unit"}
+	case rotatedOnly:
+		return UnfitDiagnosis{Code: UnfitOrientation, Message: "a remaining gap fits the unit only in an orientation it does not allow"}
+	default:
+		return UnfitDiagnosis{Code: UnfitNoSpace, Message: "no remaining space is large enough for the unit"}
+	}
+}
+
+// checkUnit reports whether a single unit could ever be placed in the empty
+// container: it must fit in some allowed orientation and weigh no more than
+// MaxWeight. With compartments, one of its temperature class must take it.
+func checkUnit(container ContainerInput, it ItemInput) (UnfitDiagnosis, bool) {
+	if len(container.Compartments) > 0 {
+		return checkCompartments(container, it)
+	}
+	box, env := ApplyClearances(container, []ItemInput{it})
+	dims := [3]float64{env[0].Length, env[0].Width, env[0].Height}
+	space := [3]float64{box.Length, box.Width, box.Height}
+
+	if !fitsAny(dims, space, it.AllowRotation) {
+		if !it.AllowRotation && fitsAny(dims, space, true) {
+			return UnfitDiagnosis{
+				Code:    UnfitOrientation,
+				Message: "the unit fits the container only when rotated, and rotation is not allowed",
+			}, false
+		}
+		return UnfitDiagnosis{
+			Code: UnfitTooLarge,
+			Message: fmt.Sprintf("%.0f×%.0f×%.0f mm does not fit the %.0f×%.0f×%.0f mm space in any allowed orientation",
+				it.Length, it.Width, it.Height, space[0], space[1], space[2]),
+		}, false
+	}
+
+	if container.MaxWeight > 0 && it.Weight > container.MaxWeight {
+		return UnfitDiagnosis{
+			Code:    UnfitOverweight,
+			Message: fmt.Sprintf("one unit weighs %.2f kg, more than the %.2f kg limit", it.Weight, container.MaxWeight),
+		}, false
+	}
+	return UnfitDiagnosis{}, true
+}
+
+// paddedDims is the space a unit takes up including its padding.
+func paddedDims(it ItemInput) [3]float64 {
+	pad := 2 * nonNegative(it.Padding)
+	return [3]float64{it.Length + pad, it.Width + pad, it.Height + pad}
+}
+
+// fitsAny reports whether dims fit in space as given or, with rotation, in
+// any of the six orientations.
+func fitsAny(dims, space [3]float64, rotate bool) bool {
+	const eps = 1e-6
+	perms := [6][3]int{{0, 1, 2}, {1, 0, 2}, {1, 2, 0}, {2, 1, 0}, {2, 0, 1}, {0, 2, 1}}
+	n := 1
+	if rotate {
+		n = len(perms)
+	}
+	for _, p := range perms[:n] {
+		if dims[p[0]] <= space[0]+eps && dims[p[1]] <= space[1]+eps && dims[p[2]] <= space[2]+eps {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package packer_test
+
+import (
+	"testing"
+
+	"github.com/ekastn/load-stuffing-calculator/internal/packer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiagnoseUnfit(t *testing.T) {
+	container := packer.ContainerInput{Length: 2000, Width: 1000, Height: 1000, MaxWeight: 100}
+
+	unit := func(l, w, h, kg float64, rotate bool) packer.ItemInput {
+		return packer.ItemInput{ID: "U", Length: l, Width: w, Height: h, Weight: kg, Quantity: 1, AllowRotation: rotate}
+	}
+
+	tests := []struct {
+		name   string
+		packed []packer.PackedItem
+		weight float64
+		item   packer.ItemInput
+		want   string
+	}{
+		{"too_large", nil, 0, unit(2500, 500, 500, 1, true), packer.UnfitTooLarge},
+		{"orientation_in_empty_container", nil, 0, unit(500, 1500, 500, 1, false), packer.UnfitOrientation},
+		{"heavier_than_container", nil, 0, unit(100, 100, 100, 150, true), packer.UnfitOverweight},
+		{"over_remaining_weight", []packer.PackedItem{block(0, 0, 0, 100, 100, 100)}, 95, unit(100, 100, 100, 10, true), packer.UnfitOverweight},
+		{"no_space", []packer.PackedItem{block(0, 0, 0, 1900, 1000, 1000)}, 10, unit(500, 500, 500, 1, true), packer.UnfitNoSpace},
+		{"orientation_in_gap", []packer.PackedItem{block(0, 0, 0, 1400, 1000, 1000)}, 10, unit(800, 500, 500, 1, false), packer.UnfitOrientation},
+		{"stacking", []packer.PackedItem{block(0, 0, 0, 2000, 1000, 400)}, 10, unit(500, 500, 500, 1, true), packer.UnfitStacking},
+		{"unplaced", []packer.PackedItem{block(0, 0, 0, 1000, 1000, 1000)}, 10, unit(500, 500, 500, 1, true), packer.UnfitUnplaced},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := packer.PackingResult{
+				PackedItems:         tt.packed,
+				TotalWeightPackedKG: tt.weight,
+				UnfitItems:          []packer.ItemInput{tt.item},
+			}
+			diags := packer.DiagnoseUnfit(container, res)
+			require.Len(t, diags, 1)
+			assert.Equal(t, tt.want, diags[0].Code)
+			assert.NotEmpty(t, diags[0].Message)
+		})
+	}
+}
+
+func TestPreflight(t *testing.T) {
+	container := packer.ContainerInput{Length: 1000, Width: 1000, Height: 1000, MaxWeight: 100, WallClearance: 50}
+
+	t.Run("clean_plan", func(t *testing.T) {
+		r := packer.Preflight(container, []packer.ItemInput{cube("A", 2)})
+		assert.Empty(t, r.Issues)
+		assert.False(t, r.Overweight)
+		assert.False(t, r.OverVolume)
+		assert.Equal(t, 2.0, r.TotalWeightKG)
+	})
+
+	t.Run("finds_impossible_items_and_totals", func(t *testing.T) {
+		wide := packer.ItemInput{ID: "WIDE", Length: 960, Width: 100, Height: 100, Weight: 1, Quantity: 1, AllowRotation: true}
+		heavy := cube("HEAVY", 9)
+		heavy.Weight = 20
+
+		r := packer.Preflight(container, []packer.ItemInput{wide, heavy})
+
+		require.Len(t, r.Issues, 1)
+		assert.Equal(t, "WIDE", r.Issues[0].ItemID) // too long once the wall clearance is taken off
+		assert.Equal(t, packer.UnfitTooLarge, r.Issues[0].Code)
+		assert.True(t, r.Overweight)
+		assert.True(t, r.OverVolume)
+	})
+}
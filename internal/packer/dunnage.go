@@ -0,0 +1,182 @@
+package packer
+
+import (
+	"math"
+	"sort"
+)
+
+// Dunnage types recommended for gaps in the load.
+const (
+	DunnageAirbag = "airbag"
+	DunnageFiller = "filler"
+)
+
+// DunnageConfig controls which voids are flagged and how they are filled.
+// Lengths are in mm, costs per unit in Currency.
+type DunnageConfig struct {
+	// MinGap is the narrowest gap worth filling. Narrower gaps are left as is.
+	MinGap float64
+	// Gaps narrower than AirbagMinGap get filler boards, wider ones airbags.
+	AirbagMinGap float64
+	// AirbagMaxGap is the widest gap one inflated airbag fills. Wider gaps
+	// take several airbags side by side.
+	AirbagMaxGap float64
+
+	AirbagWidth  float64
+	AirbagHeight float64
+	AirbagCost   float64
+
+	FillerWidth  float64
+	FillerHeight float64
+	FillerCost   float64
+
+	Currency string
+}
+
+// DefaultDunnageConfig uses 1000x1200 mm airbags and 1200x1000 mm honeycomb
+// filler boards at typical list prices.
+var DefaultDunnageConfig = DunnageConfig{
+	MinGap:       50,
+	AirbagMinGap: 100,
+	AirbagMaxGap: 300,
+	AirbagWidth:  1000,
+	AirbagHeight: 1200,
+	AirbagCost:   4.50,
+	FillerWidth:  1200,
+	FillerHeight: 1000,
+	FillerCost:   2.00,
+	Currency:     "USD",
+}
+
+// DunnageGap is a void the load can shift into, with what to fill it with.
+type DunnageGap struct {
+	Void
+	Axis       string  // "length" or "width": the direction the load can shift
+	Gap        float64 // mm along Axis
+	FaceWidth  float64 // mm of cargo face to cover
+	FaceHeight float64 // mm of cargo face to cover
+	Type       string
+	Quantity   int
+	Cost       float64
+}
+
+// VoidReport is the outcome of AnalyzeVoids.
+type VoidReport struct {
+	Voids     []Void       // maximal empty cuboids, largest first
+	Gaps      []DunnageGap // non-overlapping voids that need dunnage
+	TotalCost float64
+	Currency  string
+}
+
+// AnalyzeVoids finds the empty space around a packed load and recommends
+// dunnage for the gaps the load could shift into. A void is a gap when its
+// narrower horizontal side is at least cfg.MinGap wide and borders cargo;
+// open space running wall to wall (e.g. headroom) is not. Overlapping voids
+// are only counted once, largest first.
+func AnalyzeVoids(container ContainerInput, items []PackedItem, cfg DunnageConfig) VoidReport {
+	report := VoidReport{
+		Voids:    FindVoids(container, items, cfg.MinGap),
+		Currency: cfg.Currency,
+	}
+
+	var taken []cuboid
+	for _, v := range report.Voids {
+		vc := cuboid{
+			x1: v.Position.X, y1: v.Position.Y, z1: v.Position.Z,
+			x2: v.Position.X + v.Length, y2: v.Position.Y + v.Width, z2: v.Position.Z + v.Height,
+		}
+
+		gap, ok := shiftGap(container, items, v, vc)
+		if !ok || gap.Gap < cfg.MinGap {
+			continue
+		}
+
+		overlaps := false
+		for _, t := range taken {
+			if t.intersects(vc) {
+				overlaps = true
+				break
+			}
+		}
+		if overlaps {
+			continue
+		}
+		taken = append(taken, vc)
+
+		recommendDunnage(&gap, cfg)
+		report.Gaps = append(report.Gaps, gap)
+		report.TotalCost += gap.Cost
+	}
+
+	sort.SliceStable(report.Gaps, func(i, j int) bool {
+		return report.Gaps[i].VolumeM3() > report.Gaps[j].VolumeM3()
+	})
+	return report
+}
+
+// shiftGap describes v as a gap along its narrower horizontal side. ok is
+// false when that side runs wall to wall or no cargo borders it.
+func shiftGap(container ContainerInput, items []PackedItem, v Void, vc cuboid) (DunnageGap, bool) {
+	const eps = 1e-6
+
+	g := DunnageGap{Void: v}
+	alongLength := v.Length <= v.Width
+	if alongLength {
+		if vc.x1 <= eps && vc.x2 >= container.Length-eps {
+			return g, false
+		}
+		g.Axis, g.Gap, g.FaceWidth = "length", v.Length, v.Width
+	} else {
+		if vc.y1 <= eps && vc.y2 >= container.Width-eps {
+			return g, false
+		}
+		g.Axis, g.Gap, g.FaceWidth = "width", v.Width, v.Length
+	}
+
+	// The face to cover is as high as the tallest bordering item.
+	top := vc.z1
+	for _, it := range items {
+		c := packedCuboid(it)
+		if c.z2 <= vc.z1+eps || c.z1 >= vc.z2-eps {
+			continue
+		}
+		var borders bool
+		if alongLength {
+			borders = (math.Abs(c.x2-vc.x1) <= eps || math.Abs(c.x1-vc.x2) <= eps) &&
+				c.y1 < vc.y2-eps && vc.y1 < c.y2-eps
+		} else {
+			borders = (math.Abs(c.y2-vc.y1) <= eps || math.Abs(c.y1-vc.y2) <= eps) &&
+				c.x1 < vc.x2-eps && vc.x1 < c.x2-eps
+		}
+		if borders && c.z2 > top {
+			top = c.z2
+		}
+	}
+	g.FaceHeight = math.Min(top, vc.z2) - vc.z1
+	return g, g.FaceHeight > eps
+}
+
+func recommendDunnage(g *DunnageGap, cfg DunnageConfig) {
+	if g.Gap < cfg.AirbagMinGap {
+		g.Type = DunnageFiller
+		g.Quantity = coverCount(g.FaceWidth, g.FaceHeight, cfg.FillerWidth, cfg.FillerHeight)
+		g.Cost = float64(g.Quantity) * cfg.FillerCost
+		return
+	}
+
+	rows := 1
+	if cfg.AirbagMaxGap > 0 {
+		rows = int(math.Ceil(g.Gap / cfg.AirbagMaxGap))
+	}
+	g.Type = DunnageAirbag
+	g.Quantity = rows * coverCount(g.FaceWidth, g.FaceHeight, cfg.AirbagWidth, cfg.AirbagHeight)
+	g.Cost = float64(g.Quantity) * cfg.AirbagCost
+}
+
+// coverCount is the number of w x h units needed to cover a faceW x faceH face.
+func coverCount(faceW, faceH, w, h float64) int {
+	if w <= 0 || h <= 0 {
+		return 1
+	}
+	return int(math.Ceil(faceW/w)) * int(math.Ceil(faceH/h))
+}
@@ -0,0 +1,177 @@
+package packer
+
+import (
+	"math"
+	"sort"
+)
+
+// FloorLoadViolation is a floor-standing unit whose stack presses on the
+// floor harder than ContainerInput.FloorLoadLimit allows.
+type FloorLoadViolation struct {
+	ItemID   string
+	Label    string
+	Position Position
+	Length   float64 // footprint, mm
+	Width    float64 // footprint, mm
+
+	LoadKG       float64 // the unit plus everything it carries
+	PressureKgM2 float64
+	LimitKgM2    float64
+
+	// RequiredAreaM2 is the bearing area that brings the pressure down to
+	// the limit; RecommendedLength and RecommendedWidth are a base of that
+	// area with the unit's proportions.
+	RequiredAreaM2    float64
+	RecommendedLength float64 // mm
+	RecommendedWidth  float64 // mm
+}
+
+// LineLoadViolation is a metre of container length loaded beyond
+// ContainerInput.LineLoadLimit.
+type LineLoadViolation struct {
+	Start  float64 // mm from the front of the container
+	End    float64 // mm
+	LoadKG float64 // load on this metre
+	Limit  float64 // kg per metre
+
+	// RequiredLengthM is the length the load must be spread over.
+	RequiredLengthM float64
+}
+
+// FloorLoadReport is the outcome of CheckFloorLoad.
+type FloorLoadReport struct {
+	MaxPressureKgM2 float64
+	MaxLineLoadKgM  float64
+	Violations      []FloorLoadViolation
+	LineViolations  []LineLoadViolation
+	OK              bool
+}
+
+// lineBin is the running length line loads are measured over (mm).
+const lineBin = 1000.0
+
+// CheckFloorLoad works out the load each floor-standing unit passes to the
+// floor and checks it against the container's floor and line load limits.
+// Stacked units hand their weight down to the units beneath them in
+// proportion to the footprint they share; a unit with nothing beneath it
+// stands on the floor. Limits of 0 are not checked, but the maxima are
+// still reported.
+func CheckFloorLoad(container ContainerInput, packed []PackedItem, items []ItemInput) FloorLoadReport {
+	r := FloorLoadReport{OK: true}
+	if len(packed) == 0 {
+		return r
+	}
+
+	byID := make(map[string]ItemInput, len(items))
+	for _, it := range items {
+		byID[it.ID] = it
+	}
+
+	load, below := stackLoads(packed, byID)
+
+	bins := int(math.Ceil(container.Length / lineBin))
+	lineLoad := make([]float64, max(bins, 1))
+
+	for i, pi := range packed {
+		if len(below[i]) > 0 {
+			continue
+		}
+		areaM2 := pi.RotatedLength * pi.RotatedWidth / 1_000_000.0
+		if areaM2 <= 0 {
+			continue
+		}
+		pressure := load[i] / areaM2
+		r.MaxPressureKgM2 = math.Max(r.MaxPressureKgM2, pressure)
+
+		if limit := container.FloorLoadLimit; limit > 0 && pressure > limit {
+			required := load[i] / limit
+			scale := math.Sqrt(required / areaM2)
+			label := pi.Label
+			if label == "" {
+				label = byID[pi.ItemID].Label
+			}
+			r.Violations = append(r.Violations, FloorLoadViolation{
+				ItemID:            pi.ItemID,
+				Label:             label,
+				Position:          pi.Position,
+				Length:            pi.RotatedLength,
+				Width:             pi.RotatedWidth,
+				LoadKG:            load[i],
+				PressureKgM2:      pressure,
+				LimitKgM2:         limit,
+				RequiredAreaM2:    required,
+				RecommendedLength: math.Ceil(pi.RotatedLength * scale),
+				RecommendedWidth:  math.Ceil(pi.RotatedWidth * scale),
+			})
+		}
+
+		// Spread the unit's load evenly over its length.
+		x0, x1 := pi.Position.X, pi.Position.X+pi.RotatedLength
+		perMM := load[i] / (x1 - x0)
+		for b := range lineLoad {
+			lo, hi := math.Max(x0, float64(b)*lineBin), math.Min(x1, float64(b+1)*lineBin)
+			if hi > lo {
+				lineLoad[b] += (hi - lo) * perMM
+			}
+		}
+	}
+
+	for b, kg := range lineLoad {
+		r.MaxLineLoadKgM = math.Max(r.MaxLineLoadKgM, kg)
+		if limit := container.LineLoadLimit; limit > 0 && kg > limit+1e-9 {
+			r.LineViolations = append(r.LineViolations, LineLoadViolation{
+				Start:           float64(b) * lineBin,
+				End:             math.Min(float64(b+1)*lineBin, container.Length),
+				LoadKG:          kg,
+				Limit:           limit,
+				RequiredLengthM: kg / limit,
+			})
+		}
+	}
+
+	r.OK = len(r.Violations) == 0 && len(r.LineViolations) == 0
+	return r
+}
+
+// stackLoads works out the load each unit bears: its own weight plus
+// everything stacked on it. Stacked units hand their weight down to the
+// units beneath them in proportion to the footprint they share. below[i]
+// lists the units packed[i] rests on; it is empty for a unit on the floor.
+func stackLoads(packed []PackedItem, byID map[string]ItemInput) (load []float64, below [][]int) {
+	// Walk from the top down so each unit's load is complete before it is
+	// passed on.
+	order := make([]int, len(packed))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return packed[order[a]].Position.Z > packed[order[b]].Position.Z
+	})
+
+	const eps = 1.0 // mm
+	load = make([]float64, len(packed))
+	for i, pi := range packed {
+		load[i] = byID[pi.ItemID].Weight
+	}
+	below = make([][]int, len(packed))
+	for _, i := range order {
+		top := packed[i]
+		if top.Position.Z <= eps {
+			continue
+		}
+		var total float64
+		for j, low := range packed {
+			if j == i || math.Abs(low.Position.Z+low.RotatedHeight-top.Position.Z) > eps {
+				continue
+			}
+			if a := overlapArea(top, low); a > 0 {
+				below[i] = append(below[i], j)
+				total += a
+			}
+		}
+		for _, j := range below[i] {
+			load[j] += load[i] * overlapArea(top, packed[j]) / total
+		}
+	}
+	return load, below
+}
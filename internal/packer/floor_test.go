@@ -0,0 +1,59 @@
+package packer_test
+
+import (
+	"testing"
+
+	"github.com/ekastn/load-stuffing-calculator/internal/packer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckFloorLoad(t *testing.T) {
+	unit := func(id string, x, z, l float64) packer.PackedItem {
+		return packer.PackedItem{
+			ItemID: id, Label: id,
+			Position:      packer.Position{X: x, Z: z},
+			RotatedLength: l, RotatedWidth: 1000, RotatedHeight: 500,
+		}
+	}
+
+	t.Run("stack_over_limits", func(t *testing.T) {
+		container := packer.ContainerInput{Length: 3000, Width: 2000, Height: 2000, FloorLoadLimit: 1000, LineLoadLimit: 1200}
+		items := []packer.ItemInput{{ID: "A", Weight: 1000}, {ID: "B", Weight: 500}}
+		packed := []packer.PackedItem{unit("A", 0, 0, 1000), unit("B", 0, 500, 1000)}
+
+		r := packer.CheckFloorLoad(container, packed, items)
+
+		assert.False(t, r.OK)
+		assert.InDelta(t, 1500, r.MaxPressureKgM2, 1e-6)
+		assert.InDelta(t, 1500, r.MaxLineLoadKgM, 1e-6)
+
+		require.Len(t, r.Violations, 1)
+		v := r.Violations[0]
+		assert.Equal(t, "A", v.ItemID)
+		assert.InDelta(t, 1500, v.LoadKG, 1e-6)
+		assert.InDelta(t, 1.5, v.RequiredAreaM2, 1e-9)
+		assert.Equal(t, 1225.0, v.RecommendedLength)
+		assert.Equal(t, 1225.0, v.RecommendedWidth)
+
+		require.Len(t, r.LineViolations, 1)
+		lv := r.LineViolations[0]
+		assert.Equal(t, 0.0, lv.Start)
+		assert.Equal(t, 1000.0, lv.End)
+		assert.InDelta(t, 1.25, lv.RequiredLengthM, 1e-9)
+	})
+
+	t.Run("bridging_unit_splits_its_weight", func(t *testing.T) {
+		container := packer.ContainerInput{Length: 3000, Width: 2000, Height: 2000}
+		items := []packer.ItemInput{{ID: "D", Weight: 100}, {ID: "E", Weight: 100}, {ID: "C", Weight: 400}}
+		packed := []packer.PackedItem{unit("D", 0, 0, 1000), unit("E", 1000, 0, 1000), unit("C", 0, 500, 2000)}
+
+		r := packer.CheckFloorLoad(container, packed, items)
+
+		assert.True(t, r.OK)
+		assert.InDelta(t, 300, r.MaxPressureKgM2, 1e-6)
+		assert.InDelta(t, 300, r.MaxLineLoadKgM, 1e-6)
+		assert.Empty(t, r.Violations)
+		assert.Empty(t, r.LineViolations)
+	})
+}
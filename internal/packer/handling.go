@@ -0,0 +1,191 @@
+package packer
+
+import "sort"
+
+// Handling rules a packed unit can break.
+const (
+	HandlingThisSideUp   = "this_side_up"
+	HandlingNoStack      = "no_stack"
+	HandlingFragile      = "fragile"
+	HandlingMaxStackLoad = "max_stack_load"
+)
+
+// HandlingViolation is a packed unit loaded against its handling attributes.
+type HandlingViolation struct {
+	ItemID   string
+	Label    string
+	Position Position
+	Rule     string
+
+	// LoadKG is the weight the unit carries; LimitKG is its MaxStackLoad.
+	// Both are set for HandlingMaxStackLoad only.
+	LoadKG  float64
+	LimitKG float64
+}
+
+// HandlingReport is the outcome of CheckHandling.
+type HandlingReport struct {
+	Violations []HandlingViolation
+	// HazmatClasses lists the dangerous goods classes in the load, sorted.
+	HazmatClasses []string
+	OK            bool
+}
+
+// CheckHandling checks the packed units against the handling attributes of
+// their items: this-side-up units must stand upright, non-stackable units
+// must carry nothing, fragile units may carry only fragile units, and no
+// unit may carry more than its MaxStackLoad. The native packer keeps the
+// first two rules itself (see enforceHandling); other backends and the
+// stacking limits are only checked, so a violation means the load must be
+// rearranged by hand or the plan recalculated with other settings.
+func CheckHandling(packed []PackedItem, items []ItemInput) HandlingReport {
+	r := HandlingReport{OK: true}
+
+	byID := make(map[string]ItemInput, len(items))
+	hazmat := make(map[string]bool)
+	for _, it := range items {
+		byID[it.ID] = it
+	}
+	for _, pi := range packed {
+		if c := byID[pi.ItemID].HazmatClass; c != "" {
+			hazmat[c] = true
+		}
+	}
+	for c := range hazmat {
+		r.HazmatClasses = append(r.HazmatClasses, c)
+	}
+	sort.Strings(r.HazmatClasses)
+
+	load, below := stackLoads(packed, byID)
+	// carries marks units with anything on them, carriesOther those with a
+	// unit that is not fragile on them.
+	carries := make([]bool, len(packed))
+	carriesOther := make([]bool, len(packed))
+	for i, lows := range below {
+		fragileTop := byID[packed[i].ItemID].Fragile
+		for _, j := range lows {
+			carries[j] = true
+			if !fragileTop {
+				carriesOther[j] = true
+			}
+		}
+	}
+
+	for i, pi := range packed {
+		it := byID[pi.ItemID]
+		label := pi.Label
+		if label == "" {
+			label = it.Label
+		}
+		add := func(rule string) {
+			r.Violations = append(r.Violations, HandlingViolation{
+				ItemID:   pi.ItemID,
+				Label:    label,
+				Position: pi.Position,
+				Rule:     rule,
+			})
+		}
+
+		// Rotations 0 and 1 keep the unit's height vertical.
+		if it.ThisSideUp && pi.RotationType > 1 {
+			add(HandlingThisSideUp)
+		}
+		if it.NoStack && carries[i] {
+			add(HandlingNoStack)
+		}
+		if it.Fragile && !it.NoStack && carriesOther[i] {
+			add(HandlingFragile)
+		}
+		if carried := load[i] - it.Weight; it.MaxStackLoad > 0 && carried > it.MaxStackLoad+1e-9 {
+			add(HandlingMaxStackLoad)
+			v := &r.Violations[len(r.Violations)-1]
+			v.LoadKG = carried
+			v.LimitKG = it.MaxStackLoad
+		}
+	}
+
+	r.OK = len(r.Violations) == 0
+	return r
+}
+
+// enforceHandling takes out of result the units packed against a placement
+// rule: this-side-up units laid on their side and units stacked on a
+// non-stackable unit. boxpacker3 tries every rotation of every unit, so the
+// rules cannot be passed to it. Units resting on a removed unit go with it,
+// leaving nothing afloat, and all removed units are reported unfit.
+func enforceHandling(container ContainerInput, items []ItemInput, result *PackingResult) {
+	byID := make(map[string]ItemInput, len(items))
+	for _, it := range items {
+		byID[it.ID] = it
+	}
+	_, below := stackLoads(result.PackedItems, byID)
+
+	removed := make([]bool, len(result.PackedItems))
+	found := false
+	for i, pi := range result.PackedItems {
+		// Rotations 0 and 1 keep the unit's height vertical.
+		if byID[pi.ItemID].ThisSideUp && pi.RotationType > 1 {
+			removed[i], found = true, true
+		}
+		for _, j := range below[i] {
+			if byID[result.PackedItems[j].ItemID].NoStack {
+				removed[i], found = true, true
+			}
+		}
+	}
+	if !found {
+		return
+	}
+	for changed := true; changed; {
+		changed = false
+		for i, lows := range below {
+			if removed[i] {
+				continue
+			}
+			for _, j := range lows {
+				if removed[j] {
+					removed[i], changed = true, true
+					break
+				}
+			}
+		}
+	}
+
+	unfit := make(map[string]int)
+	kept := result.PackedItems[:0]
+	result.TotalVolumePackedM3, result.TotalWeightPackedKG = 0, 0
+	for i, pi := range result.PackedItems {
+		if removed[i] {
+			unfit[pi.ItemID]++
+			continue
+		}
+		kept = append(kept, pi)
+		result.TotalVolumePackedM3 += pi.RotatedLength * pi.RotatedWidth * pi.RotatedHeight / 1_000_000_000.0
+		result.TotalWeightPackedKG += byID[pi.ItemID].Weight
+	}
+	result.PackedItems = kept
+	result.TotalPackedItems = len(kept)
+
+	for i, u := range result.UnfitItems {
+		if n, ok := unfit[u.ID]; ok {
+			result.UnfitItems[i].Quantity += n
+			delete(unfit, u.ID)
+		}
+	}
+	for _, it := range items {
+		if n, ok := unfit[it.ID]; ok {
+			u := it
+			u.Quantity = n
+			result.UnfitItems = append(result.UnfitItems, u)
+		}
+	}
+
+	result.VolumeUtilisationPct, result.WeightUtilisationPct = 0, 0
+	if contVol := container.Length * container.Width * container.Height; contVol > 0 {
+		result.VolumeUtilisationPct = result.TotalVolumePackedM3 * 1_000_000_000.0 / contVol * 100
+	}
+	if container.MaxWeight > 0 {
+		result.WeightUtilisationPct = result.TotalWeightPackedKG / container.MaxWeight * 100
+	}
+	result.IsFeasible = len(result.UnfitItems) == 0
+}
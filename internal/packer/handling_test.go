@@ -0,0 +1,120 @@
+package packer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ekastn/load-stuffing-calculator/internal/packer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckHandling(t *testing.T) {
+	unit := func(id string, z float64, rot int) packer.PackedItem {
+		return packer.PackedItem{
+			ItemID: id, Label: id,
+			Position:      packer.Position{Z: z},
+			RotatedLength: 1000, RotatedWidth: 1000, RotatedHeight: 500,
+			RotationType: rot,
+		}
+	}
+	rules := func(r packer.HandlingReport) map[string]string {
+		out := make(map[string]string)
+		for _, v := range r.Violations {
+			out[v.ItemID] = v.Rule
+		}
+		return out
+	}
+
+	t.Run("stacking_rules", func(t *testing.T) {
+		items := []packer.ItemInput{
+			{ID: "base", Weight: 100, MaxStackLoad: 150},
+			{ID: "glass", Weight: 50, Fragile: true},
+			{ID: "box", Weight: 100},
+		}
+		// box on glass on base: glass carries a non-fragile unit and base
+		// carries 150 kg, exactly its limit.
+		packed := []packer.PackedItem{unit("base", 0, 0), unit("glass", 500, 0), unit("box", 1000, 0)}
+
+		r := packer.CheckHandling(packed, items)
+		assert.False(t, r.OK)
+		assert.Equal(t, map[string]string{"glass": packer.HandlingFragile}, rules(r))
+
+		items[0].MaxStackLoad = 120
+		items[2].Fragile = true
+		r = packer.CheckHandling(packed, items)
+		require.Len(t, r.Violations, 1)
+		v := r.Violations[0]
+		assert.Equal(t, packer.HandlingMaxStackLoad, v.Rule)
+		assert.Equal(t, "base", v.ItemID)
+		assert.InDelta(t, 150, v.LoadKG, 1e-9)
+		assert.Equal(t, 120.0, v.LimitKG)
+	})
+
+	t.Run("no_stack_and_orientation", func(t *testing.T) {
+		items := []packer.ItemInput{
+			{ID: "drum", Weight: 200, NoStack: true},
+			{ID: "lid", Weight: 10, ThisSideUp: true, HazmatClass: "3"},
+			{ID: "acid", Weight: 10, HazmatClass: "8"},
+		}
+		packed := []packer.PackedItem{unit("drum", 0, 0), unit("lid", 500, 2), {ItemID: "acid", Position: packer.Position{X: 2000}, RotatedLength: 500, RotatedWidth: 500, RotatedHeight: 500}}
+
+		r := packer.CheckHandling(packed, items)
+		assert.False(t, r.OK)
+		assert.Equal(t, map[string]string{"drum": packer.HandlingNoStack, "lid": packer.HandlingThisSideUp}, rules(r))
+		assert.Equal(t, []string{"3", "8"}, r.HazmatClasses)
+	})
+
+	t.Run("clean_load", func(t *testing.T) {
+		items := []packer.ItemInput{{ID: "a", Weight: 10, ThisSideUp: true}, {ID: "b", Weight: 10, Fragile: true}}
+		packed := []packer.PackedItem{unit("a", 0, 1), unit("b", 500, 0)}
+
+		r := packer.CheckHandling(packed, items)
+		assert.True(t, r.OK)
+		assert.Empty(t, r.Violations)
+		assert.Empty(t, r.HazmatClasses)
+	})
+}
+
+func TestPack_KeepsPlacementRules(t *testing.T) {
+	container := packer.ContainerInput{ID: "c", Length: 1000, Width: 1000, Height: 1000, MaxWeight: 1000}
+	placed := func(res packer.PackingResult) int {
+		n := len(res.PackedItems)
+		for _, u := range res.UnfitItems {
+			n += u.Quantity
+		}
+		return n
+	}
+
+	t.Run("this_side_up_stays_upright", func(t *testing.T) {
+		low := container
+		low.Height = 500
+		// Standing, the unit is too tall; it fits only laid down.
+		items := []packer.ItemInput{{ID: "tall", Length: 400, Width: 400, Height: 800, Weight: 10, Quantity: 1, AllowRotation: true, ThisSideUp: true}}
+
+		res, err := packer.NewPacker().Pack(context.Background(), low, items)
+		require.NoError(t, err)
+		assert.Empty(t, res.PackedItems)
+		require.Len(t, res.UnfitItems, 1)
+		assert.Equal(t, "tall", res.UnfitItems[0].ID)
+		assert.False(t, res.IsFeasible)
+
+		items[0].ThisSideUp = false
+		res, err = packer.NewPacker().Pack(context.Background(), low, items)
+		require.NoError(t, err)
+		assert.Len(t, res.PackedItems, 1)
+	})
+
+	t.Run("nothing_on_non_stackable_units", func(t *testing.T) {
+		items := []packer.ItemInput{
+			{ID: "drum", Length: 1000, Width: 1000, Height: 600, Weight: 200, Quantity: 1, AllowRotation: true, NoStack: true},
+			{ID: "box", Length: 1000, Width: 1000, Height: 400, Weight: 20, Quantity: 1, AllowRotation: true},
+		}
+
+		res, err := packer.NewPacker().Pack(context.Background(), container, items)
+		require.NoError(t, err)
+		assert.Equal(t, 2, placed(res))
+		assert.True(t, packer.CheckHandling(res.PackedItems, items).OK)
+		assert.Equal(t, len(res.PackedItems), res.TotalPackedItems)
+	})
+}
@@ -0,0 +1,92 @@
+package packer
+
+import "math"
+
+// LoadMetrics describes how well a packed load will behave in transit.
+type LoadMetrics struct {
+	// Centre of gravity of the packed load, in container coordinates (mm).
+	CogX, CogY, CogZ float64
+	// CogOffsetPct is the larger of the X and Y offsets of the centre of gravity
+	// from the floor centre, relative to half the container length/width (0-100).
+	CogOffsetPct float64
+	// SupportRatio is the average share of each item's base resting on the
+	// floor or on items directly below it (0-1).
+	SupportRatio float64
+}
+
+// ComputeMetrics derives centre of gravity and support metrics for result.
+// Item weights are looked up from items by ID.
+func ComputeMetrics(container ContainerInput, items []ItemInput, result PackingResult) LoadMetrics {
+	var m LoadMetrics
+	if len(result.PackedItems) == 0 {
+		return m
+	}
+
+	weightByID := make(map[string]float64, len(items))
+	for _, it := range items {
+		weightByID[it.ID] = it.Weight
+	}
+
+	var total float64
+	for _, pi := range result.PackedItems {
+		w := weightByID[pi.ItemID]
+		total += w
+		m.CogX += w * (pi.Position.X + pi.RotatedLength/2)
+		m.CogY += w * (pi.Position.Y + pi.RotatedWidth/2)
+		m.CogZ += w * (pi.Position.Z + pi.RotatedHeight/2)
+	}
+	if total > 0 {
+		m.CogX /= total
+		m.CogY /= total
+		m.CogZ /= total
+
+		var offX, offY float64
+		if container.Length > 0 {
+			offX = math.Abs(m.CogX-container.Length/2) / (container.Length / 2)
+		}
+		if container.Width > 0 {
+			offY = math.Abs(m.CogY-container.Width/2) / (container.Width / 2)
+		}
+		m.CogOffsetPct = math.Max(offX, offY) * 100
+	}
+
+	m.SupportRatio = supportRatio(result.PackedItems)
+	return m
+}
+
+func supportRatio(packed []PackedItem) float64 {
+	const eps = 1e-6
+
+	var sum float64
+	for i, cur := range packed {
+		if cur.Position.Z <= eps {
+			sum++
+			continue
+		}
+
+		base := cur.RotatedLength * cur.RotatedWidth
+		if base <= 0 {
+			continue
+		}
+
+		var supported float64
+		for j, below := range packed {
+			if i == j || math.Abs(below.Position.Z+below.RotatedHeight-cur.Position.Z) > eps {
+				continue
+			}
+			supported += overlapArea(cur, below)
+		}
+		sum += math.Min(supported/base, 1)
+	}
+	return sum / float64(len(packed))
+}
+
+// overlapArea returns the XY footprint overlap of two packed items in mm².
+func overlapArea(a, b PackedItem) float64 {
+	dx := math.Min(a.Position.X+a.RotatedLength, b.Position.X+b.RotatedLength) - math.Max(a.Position.X, b.Position.X)
+	dy := math.Min(a.Position.Y+a.RotatedWidth, b.Position.Y+b.RotatedWidth) - math.Max(a.Position.Y, b.Position.Y)
+	if dx <= 0 || dy <= 0 {
+		return 0
+	}
+	return dx * dy
+}
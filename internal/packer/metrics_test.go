@@ -0,0 +1,35 @@
+package packer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeMetrics(t *testing.T) {
+	container := ContainerInput{Length: 1000, Width: 1000, Height: 1000}
+	items := []ItemInput{
+		{ID: "heavy", Weight: 30},
+		{ID: "light", Weight: 10},
+	}
+
+	t.Run("empty_result", func(t *testing.T) {
+		assert.Equal(t, LoadMetrics{}, ComputeMetrics(container, items, PackingResult{}))
+	})
+
+	t.Run("cog_and_support", func(t *testing.T) {
+		res := PackingResult{PackedItems: []PackedItem{
+			{ItemID: "heavy", RotatedLength: 500, RotatedWidth: 1000, RotatedHeight: 500},
+			// Half of its base overhangs the heavy item below.
+			{ItemID: "light", Position: Position{X: 250, Z: 500}, RotatedLength: 500, RotatedWidth: 1000, RotatedHeight: 500},
+		}}
+
+		m := ComputeMetrics(container, items, res)
+
+		// (30*250 + 10*500) / 40
+		assert.InDelta(t, 312.5, m.CogX, 1e-9)
+		assert.InDelta(t, 500, m.CogY, 1e-9)
+		assert.InDelta(t, 37.5, m.CogOffsetPct, 1e-9)
+		assert.InDelta(t, 0.75, m.SupportRatio, 1e-9)
+	})
+}
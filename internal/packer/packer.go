@@ -11,6 +11,10 @@ import (
 	"github.com/bavix/boxpacker3"
 )
 
+// Backend identifies this packer implementation in cached results. Bump it
+// whenever packing behaviour changes so stale results are not served.
+const Backend = "boxpacker3/v1.3.2"
+
 // Packer defines the interface for 3D bin packing algorithms.
 type Packer interface {
 	Pack(ctx context.Context, container ContainerInput, items []ItemInput) (PackingResult, error)
@@ -28,10 +32,18 @@ func NewPacker() Packer {
 func (p *packer) Pack(ctx context.Context, container ContainerInput, items []ItemInput) (PackingResult, error) {
 	start := time.Now()
 
-	boxes := []*boxpacker3.Box{p.toBox(container)}
-	libItems, itemMap := p.toItems(items)
+	packBox, packItems := ApplyClearances(container, items)
+	boxes := []*boxpacker3.Box{p.toBox(packBox)}
+	libItems, itemMap := p.toItems(packItems)
+
+	reportProgress(ctx, Progress{Stage: ProgressStageStarted, ItemsTotal: len(libItems)})
+
+	var tracker *progressTracker
+	if _, ok := ctx.Value(progressKey{}).(ProgressFunc); ok {
+		tracker = &progressTracker{ctx: ctx, container: container, itemsTotal: len(libItems)}
+	}
 
-	bp, algoName, err := p.newLibPacker(container.Options)
+	bp, algoName, err := p.newLibPacker(container.Options, tracker)
 	if err != nil {
 		return PackingResult{}, err
 	}
@@ -42,12 +54,14 @@ func (p *packer) Pack(ctx context.Context, container ContainerInput, items []Ite
 		return PackingResult{}, fmt.Errorf("packing calculation failed: %w", err)
 	}
 
-	result := p.buildResult(container, packResult, itemMap, time.Since(start))
+	result := p.buildResult(packBox, packResult, itemMap, time.Since(start))
 	result.Algorithm = algoName
 
 	if container.Options.Gravity {
-		p.applyGravity(container, &result)
+		p.applyGravity(packBox, &result)
 	}
+	enforceHandling(packBox, packItems, &result)
+	RemoveClearances(container, items, &result)
 
 	return result, nil
 }
@@ -274,7 +288,9 @@ func strategyName(strategy boxpacker3.PackingStrategy) string {
 	}
 }
 
-func (p *packer) newLibPacker(opts PackOptions) (*boxpacker3.Packer, string, error) {
+// newLibPacker builds the boxpacker3 packer for opts. A non-nil tracker is
+// notified as each strategy of a parallel run finishes.
+func (p *packer) newLibPacker(opts PackOptions, tracker *progressTracker) (*boxpacker3.Packer, string, error) {
 	strategy := strings.ToLower(strings.TrimSpace(opts.Strategy))
 	goal := strings.ToLower(strings.TrimSpace(opts.Goal))
 
@@ -317,13 +333,19 @@ func (p *packer) newLibPacker(opts PackOptions) (*boxpacker3.Packer, string, err
 			return nil, "", fmt.Errorf("invalid pack goal: %q", opts.Goal)
 		}
 
+		algos := []boxpacker3.PackingAlgorithm{
+			boxpacker3.NewMinimizeBoxesStrategy(),
+			boxpacker3.NewBestFitDecreasingStrategy(),
+			boxpacker3.NewBestFitStrategy(),
+			boxpacker3.NewGreedyStrategy(),
+		}
+		if tracker != nil {
+			tracker.goal = comparator
+			algos = tracker.wrap(algos...)
+		}
+
 		ps := boxpacker3.NewParallelStrategy(
-			boxpacker3.WithAlgorithms(
-				boxpacker3.NewMinimizeBoxesStrategy(),
-				boxpacker3.NewBestFitDecreasingStrategy(),
-				boxpacker3.NewBestFitStrategy(),
-				boxpacker3.NewGreedyStrategy(),
-			),
+			boxpacker3.WithAlgorithms(algos...),
 			boxpacker3.WithGoal(comparator),
 		)
 
@@ -0,0 +1,156 @@
+package packer
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// Constraints reported for units that were left out of a load.
+const (
+	ConstraintVolume   = "volume"   // the unit's volume exceeds the space left
+	ConstraintWeight   = "weight"   // the unit would push the load over MaxWeight
+	ConstraintGeometry = "geometry" // volume and weight allow it, but no gap fits its shape
+)
+
+// PackPrioritized packs items with p, filling the container tier by tier:
+// must-ship items first, then the remaining items from the highest priority
+// down. A tier that does not fit completely keeps as many of its leading
+// units as fit alongside the tiers before it; the rest are dropped, so lower
+// priorities always give way first. Must-ship units are only dropped when
+// they cannot all fit on their own.
+//
+// Without Options.Prioritize, or when every item shares one tier, it is a
+// plain p.Pack call.
+func PackPrioritized(ctx context.Context, p Packer, container ContainerInput, items []ItemInput) (PackingResult, error) {
+	tiers := priorityTiers(items)
+	if !container.Options.Prioritize || len(tiers) < 2 {
+		return p.Pack(ctx, container, items)
+	}
+
+	start := time.Now()
+	inner := container
+	inner.Options.Prioritize = false
+
+	var accepted []ItemInput
+	best := PackingResult{ContainerID: container.ID}
+	for _, tier := range tiers {
+		res, err := p.Pack(ctx, inner, append(cloneItems(accepted), tier...))
+		if err != nil {
+			return PackingResult{}, err
+		}
+		if res.IsFeasible {
+			accepted = append(accepted, tier...)
+			best = res
+			continue
+		}
+
+		// Keep the longest prefix of the tier that still fits.
+		lo, hi := 0, unitCount(tier)-1
+		for lo < hi {
+			mid := lo + (hi-lo+1)/2
+			res, err := p.Pack(ctx, inner, append(cloneItems(accepted), takeUnits(tier, mid)...))
+			if err != nil {
+				return PackingResult{}, err
+			}
+			if res.IsFeasible {
+				lo, best = mid, res
+			} else {
+				hi = mid - 1
+			}
+		}
+		accepted = append(accepted, takeUnits(tier, lo)...)
+	}
+
+	best.UnfitItems = droppedUnits(items, accepted)
+	best.IsFeasible = len(best.UnfitItems) == 0
+	if best.Algorithm != "" {
+		best.Algorithm += "+priority"
+	}
+	best.DurationMs = time.Since(start).Milliseconds()
+	return best, nil
+}
+
+// UnfitConstraint names the constraint that kept one unit of item out of res:
+// temperature if no compartment takes its class, weight if adding it would
+// exceed MaxWeight, volume if it is larger than the space left, otherwise
+// geometry.
+func UnfitConstraint(container ContainerInput, res PackingResult, item ItemInput) string {
+	if len(container.Compartments) > 0 && len(compatibleCompartments(container, item)) == 0 {
+		return ConstraintTemperature
+	}
+	if container.MaxWeight > 0 && res.TotalWeightPackedKG+item.Weight > container.MaxWeight {
+		return ConstraintWeight
+	}
+	contVolM3 := container.Length * container.Width * container.Height / 1_000_000_000.0
+	unitVolM3 := item.Length * item.Width * item.Height / 1_000_000_000.0
+	if res.TotalVolumePackedM3+unitVolM3 > contVolM3 {
+		return ConstraintVolume
+	}
+	return ConstraintGeometry
+}
+
+// priorityTiers groups items into packing tiers: all must-ship items, then
+// one tier per priority, highest first. Input order is kept within a tier.
+func priorityTiers(items []ItemInput) [][]ItemInput {
+	sorted := cloneItems(items)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].MustShip != sorted[j].MustShip {
+			return sorted[i].MustShip
+		}
+		return sorted[i].Priority > sorted[j].Priority
+	})
+
+	var tiers [][]ItemInput
+	for i, it := range sorted {
+		if i == 0 || it.MustShip != sorted[i-1].MustShip || (!it.MustShip && it.Priority != sorted[i-1].Priority) {
+			tiers = append(tiers, nil)
+		}
+		tiers[len(tiers)-1] = append(tiers[len(tiers)-1], it)
+	}
+	return tiers
+}
+
+// takeUnits returns the first n units of items.
+func takeUnits(items []ItemInput, n int) []ItemInput {
+	var out []ItemInput
+	for _, it := range items {
+		if n <= 0 {
+			break
+		}
+		take := min(it.Quantity, n)
+		it.Quantity = take
+		out = append(out, it)
+		n -= take
+	}
+	return out
+}
+
+// droppedUnits lists the units of items missing from accepted, in input order.
+func droppedUnits(items, accepted []ItemInput) []ItemInput {
+	kept := make(map[string]int, len(accepted))
+	for _, it := range accepted {
+		kept[it.ID] += it.Quantity
+	}
+
+	var out []ItemInput
+	for _, it := range items {
+		if missing := it.Quantity - kept[it.ID]; missing > 0 {
+			it.Quantity = missing
+			out = append(out, it)
+		}
+	}
+	return out
+}
+
+func unitCount(items []ItemInput) int {
+	n := 0
+	for _, it := range items {
+		n += it.Quantity
+	}
+	return n
+}
+
+func cloneItems(items []ItemInput) []ItemInput {
+	return append([]ItemInput(nil), items...)
+}
@@ -0,0 +1,116 @@
+package packer_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ekastn/load-stuffing-calculator/internal/packer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type countingPacker struct {
+	packer.Packer
+	calls int
+}
+
+func (c *countingPacker) Pack(ctx context.Context, container packer.ContainerInput, items []packer.ItemInput) (packer.PackingResult, error) {
+	c.calls++
+	return c.Packer.Pack(ctx, container, items)
+}
+
+func cube(id string, qty int) packer.ItemInput {
+	return packer.ItemInput{ID: id, Label: id, Length: 500, Width: 500, Height: 500, Weight: 1, Quantity: qty, AllowRotation: true}
+}
+
+func placedByID(res packer.PackingResult) map[string]int {
+	out := map[string]int{}
+	for _, it := range res.PackedItems {
+		out[it.ItemID]++
+	}
+	return out
+}
+
+func TestPackPrioritized(t *testing.T) {
+	ctx := context.Background()
+	container := packer.ContainerInput{
+		ID: "C", Length: 1000, Width: 1000, Height: 1000, MaxWeight: 100,
+		Options: packer.PackOptions{Prioritize: true},
+	}
+
+	t.Run("drops_lowest_priority_first", func(t *testing.T) {
+		low := cube("LOW", 6)
+		must := cube("MUST", 4)
+		must.MustShip = true
+		high := cube("HIGH", 2)
+		high.Priority = 5
+
+		res, err := packer.PackPrioritized(ctx, packer.NewPacker(), container, []packer.ItemInput{low, must, high})
+		require.NoError(t, err)
+
+		assert.False(t, res.IsFeasible)
+		assert.Equal(t, map[string]int{"MUST": 4, "HIGH": 2, "LOW": 2}, placedByID(res))
+		require.Len(t, res.UnfitItems, 1)
+		assert.Equal(t, "LOW", res.UnfitItems[0].ID)
+		assert.Equal(t, 4, res.UnfitItems[0].Quantity)
+		assert.Contains(t, res.Algorithm, "+priority")
+	})
+
+	t.Run("weight_limit_drops_low_priority", func(t *testing.T) {
+		c := container
+		c.MaxWeight = 30
+		heavy := cube("HEAVY", 2)
+		heavy.Weight = 20
+		must := cube("MUST", 1)
+		must.Weight = 20
+		must.MustShip = true
+
+		res, err := packer.PackPrioritized(ctx, packer.NewPacker(), c, []packer.ItemInput{heavy, must})
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]int{"MUST": 1}, placedByID(res))
+		require.Len(t, res.UnfitItems, 1)
+		assert.Equal(t, packer.ConstraintWeight, packer.UnfitConstraint(c, res, res.UnfitItems[0]))
+	})
+
+	t.Run("single_tier_is_a_plain_pack", func(t *testing.T) {
+		p := &countingPacker{Packer: packer.NewPacker()}
+		res, err := packer.PackPrioritized(ctx, p, container, []packer.ItemInput{cube("A", 10)})
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, p.calls)
+		assert.Equal(t, 8, res.TotalPackedItems)
+	})
+
+	t.Run("off_without_option", func(t *testing.T) {
+		c := container
+		c.Options.Prioritize = false
+		must := cube("MUST", 1)
+		must.MustShip = true
+
+		p := &countingPacker{Packer: packer.NewPacker()}
+		_, err := packer.PackPrioritized(ctx, p, c, []packer.ItemInput{cube("A", 10), must})
+		require.NoError(t, err)
+		assert.Equal(t, 1, p.calls)
+	})
+}
+
+func TestUnfitConstraint(t *testing.T) {
+	container := packer.ContainerInput{Length: 1000, Width: 1000, Height: 1000, MaxWeight: 100}
+	unit := cube("A", 1)
+
+	tests := []struct {
+		name string
+		res  packer.PackingResult
+		want string
+	}{
+		{"weight", packer.PackingResult{TotalWeightPackedKG: 99.5, TotalVolumePackedM3: 0.1}, packer.ConstraintWeight},
+		{"volume", packer.PackingResult{TotalWeightPackedKG: 10, TotalVolumePackedM3: 0.9}, packer.ConstraintVolume},
+		{"geometry", packer.PackingResult{TotalWeightPackedKG: 10, TotalVolumePackedM3: 0.5}, packer.ConstraintGeometry},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, packer.UnfitConstraint(container, tt.res, unit))
+		})
+	}
+}
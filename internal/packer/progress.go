@@ -0,0 +1,175 @@
+package packer
+
+import (
+	"context"
+	"sync"
+
+	"github.com/bavix/boxpacker3"
+)
+
+// Progress stages reported while a plan is being packed.
+const (
+	ProgressStageStarted   = "started"
+	ProgressStageCandidate = "candidate" // a strategy finished; Best* holds the best result so far
+	ProgressStagePlacing   = "placing"   // replay of the final result's placements after packing, in loading order
+)
+
+// Progress is a snapshot of a running packing calculation.
+type Progress struct {
+	Stage                string
+	ItemsTotal           int
+	ItemsPlaced          int
+	VolumeUtilisationPct float64
+
+	// Best-so-far for multi-strategy runs.
+	BestAlgorithm            string
+	BestVolumeUtilisationPct float64
+
+	// Placements added since the previous event, for partial previews.
+	NewItems []PackedItem
+}
+
+// ProgressFunc receives progress events. It must not block.
+type ProgressFunc func(Progress)
+
+type progressKey struct{}
+
+// WithProgress attaches a progress listener to ctx. Packers report to it when present.
+func WithProgress(ctx context.Context, fn ProgressFunc) context.Context {
+	return context.WithValue(ctx, progressKey{}, fn)
+}
+
+func reportProgress(ctx context.Context, p Progress) {
+	if fn, ok := ctx.Value(progressKey{}).(ProgressFunc); ok && fn != nil {
+		fn(p)
+	}
+}
+
+// progressBatches caps the number of "placing" events sent for one result.
+const progressBatches = 20
+
+// ReplayPlacements replays the placements of a finished result as "placing"
+// events, so clients can build the load up step by step. The packers do not
+// report placements while they run; these events are sent once Pack has
+// returned and only animate the final layout. It is a no-op when ctx has no
+// progress listener.
+func ReplayPlacements(ctx context.Context, container ContainerInput, result PackingResult) {
+	if _, ok := ctx.Value(progressKey{}).(ProgressFunc); !ok {
+		return
+	}
+
+	total := result.TotalPackedItems + countUnits(result.UnfitItems)
+	contVol := container.Length * container.Width * container.Height
+
+	batch := (len(result.PackedItems) + progressBatches - 1) / progressBatches
+	if batch < 1 {
+		batch = 1
+	}
+
+	var packedVol float64
+	for start := 0; start < len(result.PackedItems); start += batch {
+		end := min(start+batch, len(result.PackedItems))
+		for _, it := range result.PackedItems[start:end] {
+			packedVol += it.RotatedLength * it.RotatedWidth * it.RotatedHeight
+		}
+
+		util := 0.0
+		if contVol > 0 {
+			util = packedVol / contVol * 100
+		}
+
+		reportProgress(ctx, Progress{
+			Stage:                ProgressStagePlacing,
+			ItemsTotal:           total,
+			ItemsPlaced:          end,
+			VolumeUtilisationPct: util,
+			NewItems:             result.PackedItems[start:end],
+		})
+	}
+}
+
+func countUnits(items []ItemInput) int {
+	n := 0
+	for _, it := range items {
+		n += it.Quantity
+	}
+	return n
+}
+
+// progressTracker follows the candidates of a parallel run and reports the
+// best one seen so far using the same goal the strategy selects with.
+type progressTracker struct {
+	ctx        context.Context
+	container  ContainerInput
+	itemsTotal int
+	goal       boxpacker3.ComparatorFunc
+
+	mu       sync.Mutex
+	best     *boxpacker3.Result
+	bestName string
+	bestUtil float64
+}
+
+func (t *progressTracker) wrap(algos ...boxpacker3.PackingAlgorithm) []boxpacker3.PackingAlgorithm {
+	wrapped := make([]boxpacker3.PackingAlgorithm, 0, len(algos))
+	for _, a := range algos {
+		wrapped = append(wrapped, &trackedAlgorithm{inner: a, tracker: t})
+	}
+	return wrapped
+}
+
+func (t *progressTracker) observe(name string, res *boxpacker3.Result) {
+	placed, util := resultFill(t.container, res)
+
+	t.mu.Lock()
+	if t.goal(res, t.best) {
+		t.best = res
+		t.bestName = name
+		t.bestUtil = util
+	}
+	p := Progress{
+		Stage:                    ProgressStageCandidate,
+		ItemsTotal:               t.itemsTotal,
+		ItemsPlaced:              placed,
+		VolumeUtilisationPct:     util,
+		BestAlgorithm:            t.bestName,
+		BestVolumeUtilisationPct: t.bestUtil,
+	}
+	t.mu.Unlock()
+
+	reportProgress(t.ctx, p)
+}
+
+func resultFill(container ContainerInput, res *boxpacker3.Result) (int, float64) {
+	if len(res.Boxes) == 0 {
+		return 0, 0
+	}
+	var placed int
+	var vol float64
+	for _, it := range res.Boxes[0].GetItems() {
+		placed++
+		vol += it.GetVolume()
+	}
+	contVol := container.Length * container.Width * container.Height
+	if contVol <= 0 {
+		return placed, 0
+	}
+	return placed, vol / contVol * 100
+}
+
+type trackedAlgorithm struct {
+	inner   boxpacker3.PackingAlgorithm
+	tracker *progressTracker
+}
+
+func (a *trackedAlgorithm) Name() string {
+	return a.inner.Name()
+}
+
+func (a *trackedAlgorithm) Pack(ctx context.Context, boxes []*boxpacker3.Box, items []*boxpacker3.Item) (*boxpacker3.Result, error) {
+	res, err := a.inner.Pack(ctx, boxes, items)
+	if err == nil && res != nil {
+		a.tracker.observe(a.inner.Name(), res)
+	}
+	return res, err
+}
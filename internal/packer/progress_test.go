@@ -0,0 +1,87 @@
+package packer_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/ekastn/load-stuffing-calculator/internal/packer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPacker_Progress(t *testing.T) {
+	container := packer.ContainerInput{
+		ID:        "CONT-001",
+		Length:    1000,
+		Width:     1000,
+		Height:    1000,
+		MaxWeight: 100,
+		Options:   packer.PackOptions{Strategy: "parallel", Goal: "tightest"},
+	}
+	items := []packer.ItemInput{
+		{ID: "ITEM-1", Label: "Box", Length: 500, Width: 500, Height: 500, Weight: 10, Quantity: 5},
+	}
+
+	var mu sync.Mutex
+	var events []packer.Progress
+	ctx := packer.WithProgress(context.Background(), func(p packer.Progress) {
+		mu.Lock()
+		events = append(events, p)
+		mu.Unlock()
+	})
+
+	res, err := packer.NewPacker().Pack(ctx, container, items)
+	require.NoError(t, err)
+	packer.ReplayPlacements(ctx, container, res)
+
+	require.NotEmpty(t, events)
+	assert.Equal(t, packer.ProgressStageStarted, events[0].Stage)
+	assert.Equal(t, 5, events[0].ItemsTotal)
+
+	var candidates, placed int
+	for _, e := range events {
+		switch e.Stage {
+		case packer.ProgressStageCandidate:
+			candidates++
+			assert.NotEmpty(t, e.BestAlgorithm)
+			assert.LessOrEqual(t, e.BestVolumeUtilisationPct, 100.0)
+		case packer.ProgressStagePlacing:
+			placed += len(e.NewItems)
+			assert.Equal(t, placed, e.ItemsPlaced)
+		}
+	}
+	assert.Equal(t, 4, candidates, "one candidate event per parallel strategy")
+	assert.Equal(t, res.TotalPackedItems, placed)
+
+	last := events[len(events)-1]
+	assert.Equal(t, packer.ProgressStagePlacing, last.Stage)
+	assert.InDelta(t, res.VolumeUtilisationPct, last.VolumeUtilisationPct, 1e-6)
+}
+
+func TestReplayPlacements_WithoutListener(t *testing.T) {
+	// Must not panic or allocate events when nobody is listening.
+	packer.ReplayPlacements(context.Background(), packer.ContainerInput{Length: 1, Width: 1, Height: 1}, packer.PackingResult{
+		PackedItems: []packer.PackedItem{{ItemID: "A"}},
+	})
+}
+
+func TestReplayPlacements_Batches(t *testing.T) {
+	container := packer.ContainerInput{Length: 1000, Width: 1000, Height: 1000}
+
+	var packed []packer.PackedItem
+	for i := 0; i < 100; i++ {
+		packed = append(packed, packer.PackedItem{ItemID: "A", RotatedLength: 100, RotatedWidth: 100, RotatedHeight: 10})
+	}
+	res := packer.PackingResult{PackedItems: packed, TotalPackedItems: len(packed)}
+
+	var events []packer.Progress
+	ctx := packer.WithProgress(context.Background(), func(p packer.Progress) {
+		events = append(events, p)
+	})
+	packer.ReplayPlacements(ctx, container, res)
+
+	assert.Len(t, events, 20)
+	assert.Equal(t, 100, events[len(events)-1].ItemsPlaced)
+	assert.InDelta(t, 1.0, events[len(events)-1].VolumeUtilisationPct, 1e-9)
+}
@@ -0,0 +1,340 @@
+package packer
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Securing methods for a block of cargo.
+const (
+	SecuringNone    = "none"     // friction alone holds the block
+	SecuringTopOver = "top_over" // frictional lashing over the top of the block
+	SecuringDirect  = "direct"   // diagonal lashing from the block to the lashing points
+)
+
+// SecuringConfig holds the EN 12195-1:2010 parameters of a securing
+// calculation. Coefficients are shares of g, forces are in daN.
+type SecuringConfig struct {
+	// Acceleration coefficients the load must withstand (EN 12195-1 table 2).
+	Forward  float64
+	Backward float64
+	Sideways float64
+	Vertical float64
+
+	// Safety factors for frictional lashing, lengthwise and sideways.
+	SafetyLengthwise float64
+	SafetySideways   float64
+	// FrictionFactor reduces the friction credited to direct lashing.
+	FrictionFactor float64
+
+	StrapLC  float64 // lashing capacity of one strap
+	StrapSTF float64 // standard tension force of one strap
+
+	// DefaultFriction is used for items without a friction coefficient.
+	DefaultFriction float64
+	// DirectAngle is the horizontal angle (degrees) of direct lashings to
+	// the container's long axis.
+	DirectAngle float64
+	// StrapSpacing is the closest two top-over straps can run (mm).
+	StrapSpacing float64
+	// PointReach is how far (mm) past the ends of a block a lashing point
+	// still counts as usable for it.
+	PointReach float64
+}
+
+// DefaultSecuringConfig is road transport with 50 mm ratchet straps
+// (LC 2500 daN, STF 400 daN) and a conservative friction of 0.2.
+var DefaultSecuringConfig = SecuringConfig{
+	Forward:          0.8,
+	Backward:         0.5,
+	Sideways:         0.5,
+	Vertical:         1.0,
+	SafetyLengthwise: 1.1,
+	SafetySideways:   1.0,
+	FrictionFactor:   0.75,
+	StrapLC:          2500,
+	StrapSTF:         400,
+	DefaultFriction:  0.2,
+	DirectAngle:      30,
+	StrapSpacing:     250,
+	PointReach:       500,
+}
+
+// SecuringBlock is the securing estimate for a group of touching units of
+// one item.
+type SecuringBlock struct {
+	ItemID   string
+	Label    string
+	Units    int
+	WeightKG float64
+
+	// Bounding box of the block.
+	Position Position
+	Length   float64
+	Width    float64
+	Height   float64
+
+	Friction float64
+	AngleDeg float64 // vertical angle of the straps
+	// RestraintDaN is the force friction does not take up in the worst direction.
+	RestraintDaN float64
+	TippingRisk  bool
+
+	TopOverStraps  int // straps needed for frictional lashing
+	DirectLashings int // lashings needed for direct lashing
+
+	Method        string
+	Straps        int // straps for Method
+	LashingPoints int // lashing points per side within reach of the block
+	Sufficient    bool
+	Note          string
+}
+
+// SecuringPlan is the outcome of PlanSecuring.
+type SecuringPlan struct {
+	Blocks         []SecuringBlock
+	TotalStraps    int
+	TopOverStraps  int // straps of blocks secured top-over
+	DirectLashings int // lashings of blocks secured directly
+	Sufficient     bool
+	Warnings       []string
+}
+
+const gravity = 9.81 // m/s²
+
+// PlanSecuring estimates the lashing a packed load needs under
+// EN 12195-1:2010. Touching units of the same item form a block; each block
+// gets the number of top-over straps and of direct lashings it needs, and
+// the method needing fewer straps that the container's lashing points
+// allow. Blocking by walls or neighbouring cargo is not credited, so the
+// estimate errs on the safe side.
+func PlanSecuring(container ContainerInput, packed []PackedItem, items []ItemInput, cfg SecuringConfig) SecuringPlan {
+	plan := SecuringPlan{Blocks: []SecuringBlock{}, Sufficient: true}
+	if len(packed) == 0 {
+		return plan
+	}
+
+	byID := make(map[string]ItemInput, len(items))
+	for _, it := range items {
+		byID[it.ID] = it
+	}
+
+	for _, units := range findBlocks(container, packed, byID) {
+		b := secureBlock(container, units, byID[units[0].ItemID], cfg)
+		plan.Blocks = append(plan.Blocks, b)
+		plan.TotalStraps += b.Straps
+		switch b.Method {
+		case SecuringTopOver:
+			plan.TopOverStraps += b.Straps
+		case SecuringDirect:
+			plan.DirectLashings += b.Straps
+		}
+		if !b.Sufficient {
+			plan.Sufficient = false
+		}
+	}
+
+	if container.LashingPointsPerSide == 0 {
+		plan.Warnings = append(plan.Warnings, "the container has no lashing points configured; anchor positions were not checked")
+	}
+	if !plan.Sufficient {
+		plan.Warnings = append(plan.Warnings, "some blocks cannot be secured by lashing alone; add blocking or dunnage")
+	}
+	return plan
+}
+
+func secureBlock(container ContainerInput, units []PackedItem, it ItemInput, cfg SecuringConfig) SecuringBlock {
+	b := SecuringBlock{
+		ItemID:   it.ID,
+		Label:    it.Label,
+		Units:    len(units),
+		WeightKG: it.Weight * float64(len(units)),
+		Friction: it.Friction,
+	}
+	if b.Label == "" {
+		b.Label = units[0].Label
+	}
+	if b.Friction <= 0 {
+		b.Friction = cfg.DefaultFriction
+	}
+
+	minP := Position{X: math.Inf(1), Y: math.Inf(1), Z: math.Inf(1)}
+	maxP := Position{X: math.Inf(-1), Y: math.Inf(-1), Z: math.Inf(-1)}
+	for _, u := range units {
+		minP.X = math.Min(minP.X, u.Position.X)
+		minP.Y = math.Min(minP.Y, u.Position.Y)
+		minP.Z = math.Min(minP.Z, u.Position.Z)
+		maxP.X = math.Max(maxP.X, u.Position.X+u.RotatedLength)
+		maxP.Y = math.Max(maxP.Y, u.Position.Y+u.RotatedWidth)
+		maxP.Z = math.Max(maxP.Z, u.Position.Z+u.RotatedHeight)
+	}
+	b.Position = minP
+	b.Length = maxP.X - minP.X
+	b.Width = maxP.Y - minP.Y
+	b.Height = maxP.Z - minP.Z
+
+	// Straps run from the top of the block down to the floor at the side
+	// walls; the side closer to its wall gives the steeper angle, the other
+	// side limits the strap.
+	run := math.Max(math.Max(minP.Y, container.Width-maxP.Y), 1)
+	alpha := math.Atan2(maxP.Z, run)
+	b.AngleDeg = alpha * 180 / math.Pi
+
+	mg := b.WeightKG * gravity / 10 // daN
+	mu := b.Friction
+
+	b.TippingRisk = cfg.Sideways*b.Height > cfg.Vertical*b.Width ||
+		cfg.Forward*b.Height > cfg.Vertical*b.Length
+
+	type direction struct {
+		c, fs, share float64
+	}
+	beta := cfg.DirectAngle * math.Pi / 180
+	dirs := []direction{
+		{cfg.Forward, cfg.SafetyLengthwise, math.Cos(beta)},
+		{cfg.Backward, cfg.SafetyLengthwise, math.Cos(beta)},
+		{cfg.Sideways, cfg.SafetySideways, math.Sin(beta)},
+	}
+
+	lc := cfg.StrapLC
+	if container.LashingPointCapacity > 0 {
+		lc = math.Min(lc, container.LashingPointCapacity)
+	}
+
+	needed := b.TippingRisk
+	direct := [3]int{}
+	for i, d := range dirs {
+		excess := d.c - mu*cfg.Vertical
+		if excess <= 0 {
+			continue
+		}
+		needed = true
+		b.RestraintDaN = math.Max(b.RestraintDaN, excess*mg)
+
+		// EN 12195-1 (10): n >= (c - µ·cz)·m·g / (2·µ·sinα·FT) · fs
+		if mu > 0 && cfg.StrapSTF > 0 {
+			n := excess * mg / (2 * mu * math.Sin(alpha) * cfg.StrapSTF) * d.fs
+			b.TopOverStraps = max(b.TopOverStraps, int(math.Ceil(n-1e-9)))
+		} else {
+			b.TopOverStraps = math.MaxInt32
+		}
+
+		// Direct lashing: each lashing takes up LC·(cosα·cosβ + fµ·µ·sinα).
+		fmu := cfg.FrictionFactor * mu
+		per := lc * (math.Cos(alpha)*d.share + fmu*math.Sin(alpha))
+		if rest := d.c - fmu*cfg.Vertical; rest > 0 && per > 0 {
+			direct[i] = int(math.Ceil(rest*mg/per - 1e-9))
+		}
+	}
+
+	if !needed {
+		b.Method = SecuringNone
+		b.Sufficient = true
+		b.Note = "friction holds the block"
+		return b
+	}
+
+	b.TopOverStraps = max(b.TopOverStraps, 2)
+	// Lashings pull either forwards or backwards and to one side each, so
+	// the corners share the sideways load.
+	b.DirectLashings = max(direct[0]+direct[1], 2*direct[2], 4)
+	b.DirectLashings += b.DirectLashings % 2
+
+	maxTopOver := int(b.Length/cfg.StrapSpacing) + 1
+	topOverOK := !b.TippingRisk && b.TopOverStraps <= maxTopOver
+	directOK := true
+	if n := container.LashingPointsPerSide; n > 0 {
+		b.LashingPoints = lashingPointsInReach(container, minP.X-cfg.PointReach, maxP.X+cfg.PointReach)
+		topOverOK = topOverOK && b.TopOverStraps <= b.LashingPoints
+		directOK = b.DirectLashings <= 2*b.LashingPoints
+	}
+
+	switch {
+	case topOverOK && (!directOK || b.TopOverStraps <= b.DirectLashings):
+		b.Method, b.Straps, b.Sufficient = SecuringTopOver, b.TopOverStraps, true
+	case directOK:
+		b.Method, b.Straps, b.Sufficient = SecuringDirect, b.DirectLashings, true
+		if b.TippingRisk {
+			b.Note = "the block can tip; lash it directly"
+		}
+	default:
+		b.Method, b.Straps = SecuringDirect, b.DirectLashings
+		if !b.TippingRisk && b.TopOverStraps < b.DirectLashings {
+			b.Method, b.Straps = SecuringTopOver, b.TopOverStraps
+		}
+		b.Note = fmt.Sprintf("%d straps needed but only %d lashing points per side are within reach", b.Straps, b.LashingPoints)
+	}
+	return b
+}
+
+// lashingPointsInReach counts the lashing points on one side wall between
+// x0 and x1. Points are spread evenly along the container.
+func lashingPointsInReach(container ContainerInput, x0, x1 float64) int {
+	n := container.LashingPointsPerSide
+	step := container.Length / float64(n)
+	count := 0
+	for i := 0; i < n; i++ {
+		x := (float64(i) + 0.5) * step
+		if x >= x0 && x <= x1 {
+			count++
+		}
+	}
+	return count
+}
+
+// findBlocks groups the units of each item into sets of touching units,
+// ordered by position from the front of the container.
+func findBlocks(container ContainerInput, packed []PackedItem, items map[string]ItemInput) [][]PackedItem {
+	byItem := make(map[string][]PackedItem)
+	var order []string
+	for _, p := range packed {
+		if _, ok := byItem[p.ItemID]; !ok {
+			order = append(order, p.ItemID)
+		}
+		byItem[p.ItemID] = append(byItem[p.ItemID], p)
+	}
+
+	var blocks [][]PackedItem
+	for _, id := range order {
+		units := byItem[id]
+		tol := 10 + container.ItemGap + 2*nonNegative(items[id].Padding)
+
+		seen := make([]bool, len(units))
+		for i := range units {
+			if seen[i] {
+				continue
+			}
+			seen[i] = true
+			block := []PackedItem{units[i]}
+			for q := 0; q < len(block); q++ {
+				for j := range units {
+					if !seen[j] && touching(block[q], units[j], tol) {
+						seen[j] = true
+						block = append(block, units[j])
+					}
+				}
+			}
+			blocks = append(blocks, block)
+		}
+	}
+
+	sort.SliceStable(blocks, func(i, j int) bool {
+		return minX(blocks[i]) < minX(blocks[j])
+	})
+	return blocks
+}
+
+func touching(a, b PackedItem, tol float64) bool {
+	return a.Position.X <= b.Position.X+b.RotatedLength+tol && b.Position.X <= a.Position.X+a.RotatedLength+tol &&
+		a.Position.Y <= b.Position.Y+b.RotatedWidth+tol && b.Position.Y <= a.Position.Y+a.RotatedWidth+tol &&
+		a.Position.Z <= b.Position.Z+b.RotatedHeight+tol && b.Position.Z <= a.Position.Z+a.RotatedHeight+tol
+}
+
+func minX(units []PackedItem) float64 {
+	x := math.Inf(1)
+	for _, u := range units {
+		x = math.Min(x, u.Position.X)
+	}
+	return x
+}
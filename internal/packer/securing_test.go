@@ -0,0 +1,94 @@
+package packer_test
+
+import (
+	"testing"
+
+	"github.com/ekastn/load-stuffing-calculator/internal/packer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanSecuring(t *testing.T) {
+	container := packer.ContainerInput{Length: 12000, Width: 2350, Height: 2390}
+
+	unit := func(id string, x, y, z, l, w, h float64) packer.PackedItem {
+		p := block(x, y, z, l, w, h)
+		p.ItemID = id
+		return p
+	}
+	items := []packer.ItemInput{
+		{ID: "crate", Label: "Crate", Length: 1200, Width: 1000, Height: 1000, Weight: 500, Friction: 0.3},
+		{ID: "rubber", Label: "Rubber mat", Length: 500, Width: 500, Height: 400, Weight: 100, Friction: 0.9},
+		{ID: "column", Label: "Column", Length: 400, Width: 400, Height: 1600, Weight: 200, Friction: 0.3},
+	}
+	packed := []packer.PackedItem{
+		unit("crate", 0, 0, 0, 1200, 1000, 1000),
+		unit("crate", 1200, 0, 0, 1200, 1000, 1000),
+		unit("rubber", 5000, 0, 0, 500, 500, 400),
+		unit("column", 8000, 0, 0, 400, 400, 1600),
+		unit("crate", 10000, 0, 0, 1200, 1000, 1000),
+	}
+
+	t.Run("per_block_method", func(t *testing.T) {
+		plan := packer.PlanSecuring(container, packed, items, packer.DefaultSecuringConfig)
+
+		require.Len(t, plan.Blocks, 4)
+
+		crates := plan.Blocks[0]
+		assert.Equal(t, "crate", crates.ItemID)
+		assert.Equal(t, 2, crates.Units)
+		assert.Equal(t, 1000.0, crates.WeightKG)
+		assert.Equal(t, 2400.0, crates.Length)
+		assert.False(t, crates.TippingRisk)
+		assert.Equal(t, 4, crates.TopOverStraps)
+		assert.Equal(t, 4, crates.DirectLashings)
+		assert.Equal(t, packer.SecuringTopOver, crates.Method)
+		assert.Equal(t, 4, crates.Straps)
+		assert.InDelta(t, 490.5, crates.RestraintDaN, 1e-6)
+
+		rubber := plan.Blocks[1]
+		assert.Equal(t, packer.SecuringNone, rubber.Method)
+		assert.Zero(t, rubber.Straps)
+
+		column := plan.Blocks[2]
+		assert.True(t, column.TippingRisk)
+		assert.Equal(t, packer.SecuringDirect, column.Method)
+		assert.Equal(t, 4, column.Straps)
+
+		single := plan.Blocks[3]
+		assert.Equal(t, 1, single.Units)
+
+		assert.True(t, plan.Sufficient)
+		assert.Equal(t, 4+2, plan.TopOverStraps)
+		assert.Equal(t, 4, plan.DirectLashings)
+		assert.Equal(t, plan.TopOverStraps+plan.DirectLashings, plan.TotalStraps)
+		assert.NotEmpty(t, plan.Warnings) // no lashing points configured
+	})
+
+	t.Run("lashing_points_out_of_reach", func(t *testing.T) {
+		withPoints := container
+		withPoints.LashingPointsPerSide = 2 // at 3000 and 9000 mm
+
+		plan := packer.PlanSecuring(withPoints, packed[:2], items, packer.DefaultSecuringConfig)
+
+		require.Len(t, plan.Blocks, 1)
+		assert.Zero(t, plan.Blocks[0].LashingPoints)
+		assert.False(t, plan.Blocks[0].Sufficient)
+		assert.NotEmpty(t, plan.Blocks[0].Note)
+		assert.False(t, plan.Sufficient)
+	})
+
+	t.Run("default_friction", func(t *testing.T) {
+		noFriction := []packer.ItemInput{{ID: "crate", Weight: 500}}
+		plan := packer.PlanSecuring(container, packed[:1], noFriction, packer.DefaultSecuringConfig)
+
+		require.Len(t, plan.Blocks, 1)
+		assert.Equal(t, packer.DefaultSecuringConfig.DefaultFriction, plan.Blocks[0].Friction)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		plan := packer.PlanSecuring(container, nil, items, packer.DefaultSecuringConfig)
+		assert.Empty(t, plan.Blocks)
+		assert.True(t, plan.Sufficient)
+	})
+}
@@ -8,6 +8,19 @@ type ContainerInput struct {
 	Height    float64 // mm
 	MaxWeight float64 // kg
 
+	WallClearance float64 // mm kept free along walls and ceiling
+	ItemGap       float64 // mm minimum between units
+
+	LashingPointsPerSide int     // evenly spaced along each side wall; 0 if unknown
+	LashingPointCapacity float64 // daN per point; 0 if not limiting
+
+	FloorLoadLimit float64 // kg/m² under any unit or stack; 0 if not checked
+	LineLoadLimit  float64 // kg per running metre of length; 0 if not checked
+
+	// Compartments split the container along its length; units are packed
+	// only into compartments of their temperature class. See PackCompartments.
+	Compartments []Compartment
+
 	Options PackOptions
 }
 
@@ -15,6 +28,10 @@ type PackOptions struct {
 	Strategy string
 	Goal     string
 	Gravity  bool
+
+	// Prioritize packs must-ship items first and drops the lowest-priority
+	// units first when the load does not fit. See PackPrioritized.
+	Prioritize bool
 }
 
 // ItemInput represents an item to be packed.
@@ -30,6 +47,20 @@ type ItemInput struct {
 	AllowRotation bool
 	Color         string // Hex color code
 	ProductSKU    string
+	Padding       float64 // mm added per side
+	Priority      int     // higher packs first when Options.Prioritize is set
+	MustShip      bool    // packs before every other item when Options.Prioritize is set
+	Friction      float64 // coefficient against the container floor; 0 if unknown
+
+	TemperatureClass string // required compartment class; ambient when empty
+
+	// Handling attributes, checked after packing by CheckHandling. The
+	// native packer also keeps ThisSideUp and NoStack.
+	Fragile      bool    // may carry only other fragile units
+	ThisSideUp   bool    // may only turn about the vertical axis
+	NoStack      bool    // may not carry any unit
+	MaxStackLoad float64 // kg the unit may carry; 0 if not limited
+	HazmatClass  string  // UN dangerous goods class or division; empty if none
 }
 
 // PackedItem represents a single instance of an item successfully placed in the container.
@@ -63,4 +94,5 @@ type PackingResult struct {
 	IsFeasible           bool    // True if all requested items fit
 	Algorithm            string
 	DurationMs           int64
+	CacheHit             bool // True if the result was served from a result cache
 }
@@ -0,0 +1,135 @@
+package packer
+
+import "sort"
+
+// Void is an empty axis-aligned cuboid inside the container, in mm.
+type Void struct {
+	Position Position
+	Length   float64
+	Width    float64
+	Height   float64
+}
+
+// VolumeM3 returns the void's volume in cubic metres.
+func (v Void) VolumeM3() float64 {
+	return v.Length * v.Width * v.Height / 1_000_000_000.0
+}
+
+// maxVoidSpaces bounds the working set of FindVoids on pathological loads.
+const maxVoidSpaces = 4000
+
+type cuboid struct {
+	x1, y1, z1 float64
+	x2, y2, z2 float64
+}
+
+func (c cuboid) intersects(o cuboid) bool {
+	const eps = 1e-6
+	return c.x1+eps < o.x2 && o.x1+eps < c.x2 &&
+		c.y1+eps < o.y2 && o.y1+eps < c.y2 &&
+		c.z1+eps < o.z2 && o.z1+eps < c.z2
+}
+
+func (c cuboid) contains(o cuboid) bool {
+	const eps = 1e-6
+	return c.x1 <= o.x1+eps && c.y1 <= o.y1+eps && c.z1 <= o.z1+eps &&
+		o.x2 <= c.x2+eps && o.y2 <= c.y2+eps && o.z2 <= c.z2+eps
+}
+
+func (c cuboid) fits(minSize float64) bool {
+	return c.x2-c.x1 >= minSize && c.y2-c.y1 >= minSize && c.z2-c.z1 >= minSize
+}
+
+func (c cuboid) volume() float64 {
+	return (c.x2 - c.x1) * (c.y2 - c.y1) * (c.z2 - c.z1)
+}
+
+func (c cuboid) void() Void {
+	return Void{
+		Position: Position{X: c.x1, Y: c.y1, Z: c.z1},
+		Length:   c.x2 - c.x1,
+		Width:    c.y2 - c.y1,
+		Height:   c.z2 - c.z1,
+	}
+}
+
+func packedCuboid(it PackedItem) cuboid {
+	return cuboid{
+		x1: it.Position.X, y1: it.Position.Y, z1: it.Position.Z,
+		x2: it.Position.X + it.RotatedLength,
+		y2: it.Position.Y + it.RotatedWidth,
+		z2: it.Position.Z + it.RotatedHeight,
+	}
+}
+
+// FindVoids returns the maximal empty cuboids left in container by items,
+// largest first. Maximal cuboids may overlap each other. Cuboids with any side
+// shorter than minSize mm are dropped; they can never contain a larger one.
+func FindVoids(container ContainerInput, items []PackedItem, minSize float64) []Void {
+	spaces := []cuboid{{x2: container.Length, y2: container.Width, z2: container.Height}}
+	if !spaces[0].fits(minSize) {
+		return nil
+	}
+
+	for _, it := range items {
+		box := packedCuboid(it)
+
+		next := make([]cuboid, 0, len(spaces))
+		for _, s := range spaces {
+			if !s.intersects(box) {
+				next = append(next, s)
+				continue
+			}
+			// Split s into the (up to six) parts that lie beside box.
+			parts := [6]cuboid{s, s, s, s, s, s}
+			parts[0].x2 = box.x1
+			parts[1].x1 = box.x2
+			parts[2].y2 = box.y1
+			parts[3].y1 = box.y2
+			parts[4].z2 = box.z1
+			parts[5].z1 = box.z2
+			for _, p := range parts {
+				if p.fits(minSize) {
+					next = append(next, p)
+				}
+			}
+		}
+		spaces = pruneContained(next)
+	}
+
+	sort.SliceStable(spaces, func(i, j int) bool {
+		return spaces[i].volume() > spaces[j].volume()
+	})
+
+	voids := make([]Void, len(spaces))
+	for i, s := range spaces {
+		voids[i] = s.void()
+	}
+	return voids
+}
+
+// pruneContained drops cuboids that lie inside another one. Larger cuboids
+// are checked first so each survivor only has to be compared with the kept ones.
+func pruneContained(spaces []cuboid) []cuboid {
+	sort.SliceStable(spaces, func(i, j int) bool {
+		return spaces[i].volume() > spaces[j].volume()
+	})
+
+	kept := make([]cuboid, 0, len(spaces))
+	for _, s := range spaces {
+		inside := false
+		for _, k := range kept {
+			if k.contains(s) {
+				inside = true
+				break
+			}
+		}
+		if !inside {
+			kept = append(kept, s)
+		}
+		if len(kept) == maxVoidSpaces {
+			break
+		}
+	}
+	return kept
+}
@@ -0,0 +1,100 @@
+package packer_test
+
+import (
+	"testing"
+
+	"github.com/ekastn/load-stuffing-calculator/internal/packer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func block(x, y, z, l, w, h float64) packer.PackedItem {
+	return packer.PackedItem{
+		ItemID:        "B",
+		Position:      packer.Position{X: x, Y: y, Z: z},
+		RotatedLength: l,
+		RotatedWidth:  w,
+		RotatedHeight: h,
+	}
+}
+
+func TestFindVoids(t *testing.T) {
+	container := packer.ContainerInput{Length: 1000, Width: 1000, Height: 1000}
+
+	t.Run("empty_container_is_one_void", func(t *testing.T) {
+		voids := packer.FindVoids(container, nil, 1)
+		require.Len(t, voids, 1)
+		assert.Equal(t, packer.Void{Length: 1000, Width: 1000, Height: 1000}, voids[0])
+	})
+
+	t.Run("corner_box_leaves_three_maximal_voids", func(t *testing.T) {
+		voids := packer.FindVoids(container, []packer.PackedItem{block(0, 0, 0, 400, 400, 400)}, 1)
+		require.Len(t, voids, 3)
+		for _, v := range voids {
+			assert.InDelta(t, 0.6, v.VolumeM3(), 1e-9)
+		}
+		assert.Contains(t, voids, packer.Void{Position: packer.Position{X: 400}, Length: 600, Width: 1000, Height: 1000})
+		assert.Contains(t, voids, packer.Void{Position: packer.Position{Y: 400}, Length: 1000, Width: 600, Height: 1000})
+		assert.Contains(t, voids, packer.Void{Position: packer.Position{Z: 400}, Length: 1000, Width: 1000, Height: 600})
+	})
+
+	t.Run("drops_voids_below_min_size", func(t *testing.T) {
+		voids := packer.FindVoids(container, []packer.PackedItem{block(0, 0, 0, 980, 1000, 1000)}, 50)
+		assert.Empty(t, voids)
+	})
+
+	t.Run("full_container_has_no_voids", func(t *testing.T) {
+		voids := packer.FindVoids(container, []packer.PackedItem{block(0, 0, 0, 1000, 1000, 1000)}, 1)
+		assert.Empty(t, voids)
+	})
+}
+
+func TestAnalyzeVoids(t *testing.T) {
+	container := packer.ContainerInput{Length: 2000, Width: 1000, Height: 1000}
+	cfg := packer.DefaultDunnageConfig
+
+	t.Run("door_gap_gets_airbags", func(t *testing.T) {
+		report := packer.AnalyzeVoids(container, []packer.PackedItem{block(0, 0, 0, 1800, 1000, 600)}, cfg)
+
+		require.Len(t, report.Gaps, 1)
+		g := report.Gaps[0]
+		assert.Equal(t, "length", g.Axis)
+		assert.Equal(t, 200.0, g.Gap)
+		assert.Equal(t, 1000.0, g.FaceWidth)
+		assert.Equal(t, 600.0, g.FaceHeight) // only as high as the cargo
+		assert.Equal(t, packer.DunnageAirbag, g.Type)
+		assert.Equal(t, 1, g.Quantity)
+		assert.Equal(t, cfg.AirbagCost, report.TotalCost)
+		assert.Equal(t, "USD", report.Currency)
+
+		// Headroom above the load runs wall to wall and is not a gap.
+		assert.Len(t, report.Voids, 2)
+	})
+
+	t.Run("narrow_gap_gets_fillers", func(t *testing.T) {
+		report := packer.AnalyzeVoids(container, []packer.PackedItem{block(0, 0, 0, 1920, 1000, 1000)}, cfg)
+
+		require.Len(t, report.Gaps, 1)
+		assert.Equal(t, packer.DunnageFiller, report.Gaps[0].Type)
+		assert.Equal(t, 1, report.Gaps[0].Quantity)
+	})
+
+	t.Run("wide_gap_needs_several_airbags", func(t *testing.T) {
+		report := packer.AnalyzeVoids(container, []packer.PackedItem{block(0, 0, 0, 1300, 1000, 1000)}, cfg)
+
+		require.Len(t, report.Gaps, 1)
+		assert.Equal(t, 700.0, report.Gaps[0].Gap)
+		assert.Equal(t, 3, report.Gaps[0].Quantity) // three airbags deep, one high
+	})
+
+	t.Run("gap_below_threshold_is_ignored", func(t *testing.T) {
+		report := packer.AnalyzeVoids(container, []packer.PackedItem{block(0, 0, 0, 1970, 1000, 1000)}, cfg)
+		assert.Empty(t, report.Gaps)
+		assert.Zero(t, report.TotalCost)
+	})
+
+	t.Run("empty_container_needs_nothing", func(t *testing.T) {
+		report := packer.AnalyzeVoids(container, nil, cfg)
+		assert.Empty(t, report.Gaps)
+	})
+}
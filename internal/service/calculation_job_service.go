@@ -0,0 +1,314 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/ekastn/load-stuffing-calculator/internal/auth"
+	"github.com/ekastn/load-stuffing-calculator/internal/dto"
+	"github.com/ekastn/load-stuffing-calculator/internal/store"
+	"github.com/ekastn/load-stuffing-calculator/internal/types"
+	"github.com/google/uuid"
+)
+
+type CalculationJobService interface {
+	EnqueueCalculation(ctx context.Context, planID string, opts dto.CalculatePlanRequest) (*dto.CalculationJobResponse, error)
+	GetCalculationJob(ctx context.Context, planID, jobID string) (*dto.CalculationJobResponse, error)
+	CancelCalculationJob(ctx context.Context, planID, jobID string) (*dto.CalculationJobResponse, error)
+	// Start recovers interrupted jobs and runs the worker pool until ctx is done.
+	Start(ctx context.Context)
+}
+
+var ErrJobFinished = fmt.Errorf("job already finished")
+
+const (
+	defaultCalcWorkers = 2
+	jobSweepInterval   = 5 * time.Second
+)
+
+type calculationJobService struct {
+	q       store.Querier
+	plans   PlanService
+	workers int
+	queue   chan uuid.UUID
+
+	mu      sync.Mutex
+	running map[uuid.UUID]context.CancelFunc
+}
+
+func NewCalculationJobService(q store.Querier, plans PlanService, workers int) CalculationJobService {
+	if workers < 1 {
+		workers = defaultCalcWorkers
+	}
+	return &calculationJobService{
+		q:       q,
+		plans:   plans,
+		workers: workers,
+		queue:   make(chan uuid.UUID, workers*16),
+		running: make(map[uuid.UUID]context.CancelFunc),
+	}
+}
+
+func (s *calculationJobService) EnqueueCalculation(ctx context.Context, planID string, opts dto.CalculatePlanRequest) (*dto.CalculationJobResponse, error) {
+	pID, err := uuid.Parse(planID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid plan id")
+	}
+
+	if _, err := resolvePlanScope(ctx, s.q, pID); err != nil {
+		return nil, fmt.Errorf("plan not found: %w", err)
+	}
+
+	actor, err := actorFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Remember the workspace the request was made in so the worker can act
+	// with the same scope later on.
+	workspaceID, err := func() (*uuid.UUID, error) {
+		if actor.role == types.RoleTrial.String() {
+			return nil, nil
+		}
+		if isFounder(ctx) {
+			return workspaceOverrideIDFromContext(ctx)
+		}
+		return workspaceIDFromContext(ctx)
+	}()
+	if err != nil {
+		return nil, err
+	}
+
+	options, err := json.Marshal(opts)
+	if err != nil {
+		return nil, fmt.Errorf("invalid options: %w", err)
+	}
+
+	job, err := s.q.CreateCalculationJob(ctx, store.CreateCalculationJobParams{
+		PlanID:          pID,
+		Options:         options,
+		RequestedByID:   actor.id,
+		RequestedByRole: actor.role,
+		WorkspaceID:     workspaceID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create job: %w", err)
+	}
+
+	// A full queue is not an error: the sweeper picks the job up from the table.
+	select {
+	case s.queue <- job.JobID:
+	default:
+	}
+
+	return mapCalculationJob(job), nil
+}
+
+func (s *calculationJobService) GetCalculationJob(ctx context.Context, planID, jobID string) (*dto.CalculationJobResponse, error) {
+	job, err := s.getScopedJob(ctx, planID, jobID)
+	if err != nil {
+		return nil, err
+	}
+	return mapCalculationJob(*job), nil
+}
+
+func (s *calculationJobService) CancelCalculationJob(ctx context.Context, planID, jobID string) (*dto.CalculationJobResponse, error) {
+	job, err := s.getScopedJob(ctx, planID, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	n, err := s.q.CancelCalculationJob(ctx, job.JobID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel job: %w", err)
+	}
+	if n == 0 {
+		return nil, ErrJobFinished
+	}
+
+	s.mu.Lock()
+	if cancel, ok := s.running[job.JobID]; ok {
+		cancel()
+	}
+	s.mu.Unlock()
+
+	updated, err := s.q.GetCalculationJobAny(ctx, job.JobID)
+	if err != nil {
+		return nil, err
+	}
+	return mapCalculationJob(updated), nil
+}
+
+func (s *calculationJobService) getScopedJob(ctx context.Context, planID, jobID string) (*store.CalculationJob, error) {
+	pID, err := uuid.Parse(planID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid plan id")
+	}
+	jID, err := uuid.Parse(jobID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid job id")
+	}
+
+	if _, err := resolvePlanScope(ctx, s.q, pID); err != nil {
+		return nil, fmt.Errorf("plan not found: %w", err)
+	}
+
+	job, err := s.q.GetCalculationJob(ctx, store.GetCalculationJobParams{JobID: jID, PlanID: pID})
+	if err != nil {
+		return nil, fmt.Errorf("job not found: %w", err)
+	}
+	return &job, nil
+}
+
+func (s *calculationJobService) Start(ctx context.Context) {
+	// Jobs left running by a previous process were interrupted; queue them again.
+	if n, err := s.q.RequeueRunningCalculationJobs(ctx); err != nil {
+		log.Printf("calculation jobs: failed to requeue interrupted jobs: %v", err)
+	} else if n > 0 {
+		log.Printf("calculation jobs: requeued %d interrupted job(s)", n)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < s.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case jobID := <-s.queue:
+					s.runJob(ctx, jobID)
+				}
+			}
+		}()
+	}
+
+	s.sweep(ctx)
+	ticker := time.NewTicker(jobSweepInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		case <-ticker.C:
+			s.sweep(ctx)
+		}
+	}
+}
+
+// sweep feeds queued jobs from the table into the worker queue. Jobs may be
+// offered more than once; MarkCalculationJobRunning lets only one worker win.
+func (s *calculationJobService) sweep(ctx context.Context) {
+	jobs, err := s.q.ListPendingCalculationJobs(ctx, int32(cap(s.queue)))
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("calculation jobs: failed to list pending jobs: %v", err)
+		}
+		return
+	}
+	for _, job := range jobs {
+		select {
+		case s.queue <- job.JobID:
+		default:
+			return
+		}
+	}
+}
+
+func (s *calculationJobService) runJob(ctx context.Context, jobID uuid.UUID) {
+	claimed, err := s.q.MarkCalculationJobRunning(ctx, jobID)
+	if err != nil || claimed == 0 {
+		return
+	}
+
+	jobCtx, cancel := context.WithCancel(ctx)
+	s.mu.Lock()
+	s.running[jobID] = cancel
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.running, jobID)
+		s.mu.Unlock()
+		cancel()
+	}()
+
+	// Re-read after registering so a cancel issued in between is not missed.
+	job, err := s.q.GetCalculationJobAny(ctx, jobID)
+	if err != nil || job.Status != types.JobStatusRunning.String() {
+		return
+	}
+
+	var opts dto.CalculatePlanRequest
+	if err := json.Unmarshal(job.Options, &opts); err != nil {
+		s.failJob(ctx, jobID, fmt.Errorf("invalid options: %w", err))
+		return
+	}
+
+	res, err := s.plans.CalculatePlan(jobContext(jobCtx, job), job.PlanID.String(), opts)
+	if err != nil {
+		if ctx.Err() != nil {
+			// Shutting down: leave the job running so Start requeues it on the next boot.
+			return
+		}
+		if errors.Is(jobCtx.Err(), context.Canceled) {
+			// Cancelled through the API; the row is already marked.
+			return
+		}
+		s.failJob(ctx, jobID, err)
+		return
+	}
+
+	var resultID *uuid.UUID
+	if id, err := uuid.Parse(res.JobID); err == nil {
+		resultID = &id
+	}
+	if err := s.q.CompleteCalculationJob(ctx, store.CompleteCalculationJobParams{JobID: jobID, ResultID: resultID}); err != nil {
+		log.Printf("calculation jobs: failed to complete job %s: %v", jobID, err)
+	}
+}
+
+func (s *calculationJobService) failJob(ctx context.Context, jobID uuid.UUID, cause error) {
+	msg := cause.Error()
+	if err := s.q.FailCalculationJob(ctx, store.FailCalculationJobParams{JobID: jobID, ErrorMessage: &msg}); err != nil {
+		log.Printf("calculation jobs: failed to mark job %s as failed: %v", jobID, err)
+	}
+}
+
+// jobContext rebuilds the requester's auth scope so the plan service applies
+// the same access rules as it would have for the original HTTP request.
+func jobContext(ctx context.Context, job store.CalculationJob) context.Context {
+	ctx = auth.WithRole(ctx, job.RequestedByRole)
+	ctx = auth.WithUserID(ctx, job.RequestedByID.String())
+	if job.WorkspaceID != nil {
+		ctx = auth.WithWorkspaceID(ctx, job.WorkspaceID.String())
+		if job.RequestedByRole == types.RoleFounder.String() {
+			ctx = auth.WithWorkspaceOverrideID(ctx, job.WorkspaceID.String())
+		}
+	}
+	return ctx
+}
+
+func mapCalculationJob(job store.CalculationJob) *dto.CalculationJobResponse {
+	var resultID *string
+	if job.ResultID != nil {
+		id := job.ResultID.String()
+		resultID = &id
+	}
+	return &dto.CalculationJobResponse{
+		JobID:        job.JobID.String(),
+		PlanID:       job.PlanID.String(),
+		Status:       job.Status,
+		ResultID:     resultID,
+		ErrorMessage: job.ErrorMessage,
+		CreatedAt:    job.CreatedAt,
+		StartedAt:    job.StartedAt,
+		FinishedAt:   job.FinishedAt,
+	}
+}
@@ -0,0 +1,238 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ekastn/load-stuffing-calculator/internal/auth"
+	"github.com/ekastn/load-stuffing-calculator/internal/dto"
+	"github.com/ekastn/load-stuffing-calculator/internal/mocks"
+	"github.com/ekastn/load-stuffing-calculator/internal/service"
+	"github.com/ekastn/load-stuffing-calculator/internal/store"
+	"github.com/ekastn/load-stuffing-calculator/internal/types"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalculationJobService_EnqueueCalculation(t *testing.T) {
+	planID := uuid.New()
+
+	t.Run("creates_queued_job_with_requester_scope", func(t *testing.T) {
+		ctx := authedPlannerCtx()
+		wsStr, _ := auth.WorkspaceIDFromContext(ctx)
+		userStr, _ := auth.UserIDFromContext(ctx)
+
+		mockQ := &MockQuerier{
+			GetLoadPlanFunc: func(ctx context.Context, arg store.GetLoadPlanParams) (store.LoadPlan, error) {
+				return store.LoadPlan{PlanID: planID}, nil
+			},
+			CreateCalculationJobFunc: func(ctx context.Context, arg store.CreateCalculationJobParams) (store.CalculationJob, error) {
+				assert.Equal(t, planID, arg.PlanID)
+				assert.Equal(t, userStr, arg.RequestedByID.String())
+				assert.Equal(t, types.RolePlanner.String(), arg.RequestedByRole)
+				require.NotNil(t, arg.WorkspaceID)
+				assert.Equal(t, wsStr, arg.WorkspaceID.String())
+
+				var opts dto.CalculatePlanRequest
+				require.NoError(t, json.Unmarshal(arg.Options, &opts))
+				assert.Equal(t, "ffd", opts.Strategy)
+
+				return store.CalculationJob{
+					JobID:  uuid.New(),
+					PlanID: arg.PlanID,
+					Status: types.JobStatusQueued.String(),
+				}, nil
+			},
+		}
+
+		s := service.NewCalculationJobService(mockQ, new(mocks.MockPlanService), 1)
+		resp, err := s.EnqueueCalculation(ctx, planID.String(), dto.CalculatePlanRequest{Strategy: "ffd"})
+
+		require.NoError(t, err)
+		assert.Equal(t, types.JobStatusQueued.String(), resp.Status)
+		assert.Equal(t, planID.String(), resp.PlanID)
+	})
+
+	t.Run("invalid_plan_id", func(t *testing.T) {
+		s := service.NewCalculationJobService(&MockQuerier{}, new(mocks.MockPlanService), 1)
+		_, err := s.EnqueueCalculation(authedPlannerCtx(), "bad", dto.CalculatePlanRequest{})
+		assert.Error(t, err)
+	})
+
+	t.Run("trial_cannot_queue_foreign_plan", func(t *testing.T) {
+		mockQ := &MockQuerier{
+			GetLoadPlanForGuestFunc: func(ctx context.Context, arg store.GetLoadPlanForGuestParams) (store.LoadPlan, error) {
+				return store.LoadPlan{}, assert.AnError
+			},
+		}
+
+		s := service.NewCalculationJobService(mockQ, new(mocks.MockPlanService), 1)
+		_, err := s.EnqueueCalculation(authedTrialCtxWithID(uuid.New()), planID.String(), dto.CalculatePlanRequest{})
+		assert.ErrorIs(t, err, service.ErrForbidden)
+	})
+}
+
+func TestCalculationJobService_CancelCalculationJob(t *testing.T) {
+	planID := uuid.New()
+	jobID := uuid.New()
+
+	t.Run("already_finished", func(t *testing.T) {
+		mockQ := &MockQuerier{
+			GetLoadPlanFunc: func(ctx context.Context, arg store.GetLoadPlanParams) (store.LoadPlan, error) {
+				return store.LoadPlan{PlanID: planID}, nil
+			},
+			GetCalculationJobFunc: func(ctx context.Context, arg store.GetCalculationJobParams) (store.CalculationJob, error) {
+				return store.CalculationJob{JobID: jobID, PlanID: planID, Status: types.JobStatusCompleted.String()}, nil
+			},
+			CancelCalculationJobFunc: func(ctx context.Context, id uuid.UUID) (int64, error) {
+				return 0, nil
+			},
+		}
+
+		s := service.NewCalculationJobService(mockQ, new(mocks.MockPlanService), 1)
+		_, err := s.CancelCalculationJob(authedPlannerCtx(), planID.String(), jobID.String())
+		assert.ErrorIs(t, err, service.ErrJobFinished)
+	})
+}
+
+func TestCalculationJobService_Worker(t *testing.T) {
+	planID := uuid.New()
+	jobID := uuid.New()
+	userID := uuid.New()
+	workspaceID := uuid.New()
+	resultID := uuid.New()
+
+	newJobQuerier := func(status *string, mu *sync.Mutex) *MockQuerier {
+		return &MockQuerier{
+			RequeueRunningCalculationJobsFunc: func(ctx context.Context) (int64, error) {
+				return 0, nil
+			},
+			ListPendingCalculationJobsFunc: func(ctx context.Context, limit int32) ([]store.CalculationJob, error) {
+				mu.Lock()
+				defer mu.Unlock()
+				if *status != types.JobStatusQueued.String() {
+					return nil, nil
+				}
+				return []store.CalculationJob{{JobID: jobID, PlanID: planID, Status: *status}}, nil
+			},
+			MarkCalculationJobRunningFunc: func(ctx context.Context, id uuid.UUID) (int64, error) {
+				mu.Lock()
+				defer mu.Unlock()
+				if *status != types.JobStatusQueued.String() {
+					return 0, nil
+				}
+				*status = types.JobStatusRunning.String()
+				return 1, nil
+			},
+			GetCalculationJobAnyFunc: func(ctx context.Context, id uuid.UUID) (store.CalculationJob, error) {
+				mu.Lock()
+				defer mu.Unlock()
+				return store.CalculationJob{
+					JobID:           jobID,
+					PlanID:          planID,
+					Status:          *status,
+					Options:         []byte(`{"strategy":"bfd"}`),
+					RequestedByID:   userID,
+					RequestedByRole: types.RolePlanner.String(),
+					WorkspaceID:     &workspaceID,
+				}, nil
+			},
+			GetLoadPlanFunc: func(ctx context.Context, arg store.GetLoadPlanParams) (store.LoadPlan, error) {
+				return store.LoadPlan{PlanID: planID}, nil
+			},
+			GetCalculationJobFunc: func(ctx context.Context, arg store.GetCalculationJobParams) (store.CalculationJob, error) {
+				return store.CalculationJob{JobID: jobID, PlanID: planID}, nil
+			},
+			CancelCalculationJobFunc: func(ctx context.Context, id uuid.UUID) (int64, error) {
+				mu.Lock()
+				defer mu.Unlock()
+				*status = types.JobStatusCancelled.String()
+				return 1, nil
+			},
+		}
+	}
+
+	t.Run("runs_job_as_requester_and_completes", func(t *testing.T) {
+		var mu sync.Mutex
+		status := types.JobStatusQueued.String()
+		completed := make(chan store.CompleteCalculationJobParams, 1)
+
+		mockQ := newJobQuerier(&status, &mu)
+		mockQ.CompleteCalculationJobFunc = func(ctx context.Context, arg store.CompleteCalculationJobParams) error {
+			completed <- arg
+			return nil
+		}
+
+		plans := new(mocks.MockPlanService)
+		plans.On("CalculatePlan", mock.MatchedBy(func(ctx context.Context) bool {
+			uid, _ := auth.UserIDFromContext(ctx)
+			wid, _ := auth.WorkspaceIDFromContext(ctx)
+			return uid == userID.String() && wid == workspaceID.String()
+		}), planID.String(), dto.CalculatePlanRequest{Strategy: "bfd"}).
+			Return(&dto.CalculationResult{JobID: resultID.String()}, nil)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		s := service.NewCalculationJobService(mockQ, plans, 1)
+		go s.Start(ctx)
+
+		select {
+		case arg := <-completed:
+			assert.Equal(t, jobID, arg.JobID)
+			require.NotNil(t, arg.ResultID)
+			assert.Equal(t, resultID, *arg.ResultID)
+		case <-time.After(2 * time.Second):
+			t.Fatal("job was not completed")
+		}
+		plans.AssertExpectations(t)
+	})
+
+	t.Run("cancel_propagates_to_running_calculation", func(t *testing.T) {
+		var mu sync.Mutex
+		status := types.JobStatusQueued.String()
+		started := make(chan struct{})
+		stopped := make(chan struct{})
+
+		mockQ := newJobQuerier(&status, &mu)
+		mockQ.FailCalculationJobFunc = func(ctx context.Context, arg store.FailCalculationJobParams) error {
+			t.Error("cancelled job must not be marked as failed")
+			return nil
+		}
+
+		plans := new(mocks.MockPlanService)
+		plans.On("CalculatePlan", mock.Anything, planID.String(), mock.Anything).
+			Run(func(args mock.Arguments) {
+				ctx := args.Get(0).(context.Context)
+				close(started)
+				<-ctx.Done()
+				close(stopped)
+			}).
+			Return(nil, context.Canceled)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		s := service.NewCalculationJobService(mockQ, plans, 1)
+		go s.Start(ctx)
+
+		select {
+		case <-started:
+		case <-time.After(2 * time.Second):
+			t.Fatal("job was not started")
+		}
+
+		resp, err := s.CancelCalculationJob(authedPlannerCtx(), planID.String(), jobID.String())
+		require.NoError(t, err)
+		assert.Equal(t, types.JobStatusCancelled.String(), resp.Status)
+
+		select {
+		case <-stopped:
+		case <-time.After(2 * time.Second):
+			t.Fatal("calculation context was not cancelled")
+		}
+	})
+}
@@ -0,0 +1,395 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/ekastn/load-stuffing-calculator/internal/dto"
+	"github.com/ekastn/load-stuffing-calculator/internal/packer"
+	"github.com/ekastn/load-stuffing-calculator/internal/store"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidCapacityRequest = fmt.Errorf("invalid capacity request")
+	ErrCapacityInputNotFound  = fmt.Errorf("product or container not found")
+)
+
+const (
+	// maxCapacityUnits caps the units packed in one probe. Packing time grows
+	// quickly with the item count.
+	maxCapacityUnits = 3000
+	// maxCapacityProbes bounds the search after the initial fill.
+	maxCapacityProbes = 8
+	// capacitySearchBudget is the time the search may take before it settles
+	// for the best quantity found so far.
+	capacitySearchBudget = 30 * time.Second
+)
+
+// Limiting factors reported by MaxQuantity.
+const (
+	LimitWeight = "weight"
+	LimitVolume = "volume"
+	LimitSpace  = "space" // volume and weight allow more, but the shapes do not fit
+	LimitCap    = "search_cap"
+)
+
+type CapacityService interface {
+	MaxQuantity(ctx context.Context, req dto.MaxQuantityRequest) (*dto.MaxQuantityResponse, error)
+}
+
+type capacityService struct {
+	q        store.Querier
+	products ProductService
+	p        packer.Packer
+}
+
+func NewCapacityService(q store.Querier, products ProductService, p packer.Packer) CapacityService {
+	return &capacityService{q: q, products: products, p: p}
+}
+
+type capacityProduct struct {
+	product *dto.ProductResponse
+	ratio   int
+}
+
+// MaxQuantity finds the largest number of units (or complete mix sets) that
+// fit in the container. It fills the container with as many sets as volume
+// and weight allow, takes what the packer managed to place as a lower bound
+// and then binary-searches the quantities in between.
+func (s *capacityService) MaxQuantity(ctx context.Context, req dto.MaxQuantityRequest) (*dto.MaxQuantityResponse, error) {
+	mix, err := s.resolveProducts(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	container, info, err := s.resolveContainer(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	allowRot := true
+	if req.AllowRotation != nil {
+		allowRot = *req.AllowRotation
+	}
+
+	var setVolume, setWeight float64
+	var setUnits int
+	for _, m := range mix {
+		setVolume += m.product.LengthMM * m.product.WidthMM * m.product.HeightMM * float64(m.ratio)
+		setWeight += m.product.WeightKG * float64(m.ratio)
+		setUnits += m.ratio
+	}
+	if setVolume <= 0 {
+		return nil, fmt.Errorf("%w: products must have positive dimensions", ErrInvalidCapacityRequest)
+	}
+
+	upper, limit := capacityUpperBound(container, setVolume, setWeight, setUnits)
+
+	buildItems := func(sets int) []packer.ItemInput {
+		items := make([]packer.ItemInput, 0, len(mix))
+		for _, m := range mix {
+			items = append(items, packer.ItemInput{
+				ID:            m.product.ID,
+				Label:         m.product.Name,
+				Length:        m.product.LengthMM,
+				Width:         m.product.WidthMM,
+				Height:        m.product.HeightMM,
+				Weight:        m.product.WeightKG,
+				Quantity:      sets * m.ratio,
+				AllowRotation: allowRot,
+				ProductSKU:    getString(m.product.SKU),
+				Friction:      getFloat(m.product.FrictionCoefficient),
+
+				TemperatureClass: getString(m.product.TemperatureClass),
+
+				Fragile:      m.product.Fragile,
+				ThisSideUp:   m.product.ThisSideUp,
+				NoStack:      !m.product.Stackable,
+				MaxStackLoad: getFloat(m.product.MaxStackLoadKG),
+				HazmatClass:  getString(m.product.HazmatClass),
+			})
+		}
+		return items
+	}
+
+	searchCtx, cancel := context.WithTimeout(ctx, capacitySearchBudget)
+	defer cancel()
+
+	resp := &dto.MaxQuantityResponse{SearchComplete: true}
+	var best packer.PackingResult
+	bestSets := 0
+
+	if upper > 0 {
+		// Initial fill: whatever the packer places out of the upper bound is a
+		// valid layout for that many complete sets.
+		res, err := s.p.Pack(searchCtx, container, buildItems(upper))
+		resp.Iterations++
+		if err != nil {
+			return nil, fmt.Errorf("packing failed: %w", err)
+		}
+		resp.Algorithm = res.Algorithm
+
+		if res.IsFeasible {
+			best, bestSets = res, upper
+		} else {
+			bestSets = completeSets(res, mix)
+			best = trimToSets(res, container, mix, bestSets)
+			limit = LimitSpace
+
+			lo, hi := bestSets, upper-1
+			for lo < hi && resp.Iterations <= maxCapacityProbes {
+				mid := lo + (hi-lo+1)/2
+				probe, err := s.p.Pack(searchCtx, container, buildItems(mid))
+				resp.Iterations++
+				if err != nil {
+					if searchCtx.Err() != nil {
+						resp.SearchComplete = false
+						break
+					}
+					return nil, fmt.Errorf("packing failed: %w", err)
+				}
+				if probe.IsFeasible {
+					lo, best, bestSets = mid, probe, mid
+				} else {
+					hi = mid - 1
+				}
+			}
+			if lo < hi {
+				resp.SearchComplete = false
+			}
+		}
+	}
+
+	resp.Sets = bestSets
+	resp.MaxQuantity = bestSets * setUnits
+	resp.LimitingFactor = limit
+	resp.Container = info
+	resp.Stats = dto.PlanStats{
+		TotalItems:           resp.MaxQuantity,
+		TotalWeightKG:        best.TotalWeightPackedKG,
+		TotalVolumeM3:        best.TotalVolumePackedM3,
+		VolumeUtilizationPct: best.VolumeUtilisationPct,
+		WeightUtilizationPct: best.WeightUtilisationPct,
+	}
+	for _, m := range mix {
+		resp.Products = append(resp.Products, dto.MaxQuantityProduct{
+			ProductID: m.product.ID,
+			SKU:       m.product.SKU,
+			Name:      m.product.Name,
+			Ratio:     m.ratio,
+			Quantity:  bestSets * m.ratio,
+		})
+	}
+	resp.Placements = make([]dto.CapacityPlacement, 0, len(best.PackedItems))
+	for i, it := range best.PackedItems {
+		resp.Placements = append(resp.Placements, dto.CapacityPlacement{
+			ProductID:  it.ItemID,
+			PositionX:  it.Position.X,
+			PositionY:  it.Position.Y,
+			PositionZ:  it.Position.Z,
+			LengthMM:   it.RotatedLength,
+			WidthMM:    it.RotatedWidth,
+			HeightMM:   it.RotatedHeight,
+			Rotation:   it.RotationType,
+			StepNumber: i + 1,
+		})
+	}
+	return resp, nil
+}
+
+// capacityUpperBound is the number of sets volume and weight allow, capped at
+// maxCapacityUnits, and the factor that sets it.
+func capacityUpperBound(container packer.ContainerInput, setVolume, setWeight float64, setUnits int) (int, string) {
+	contVolume := container.Length * container.Width * container.Height
+
+	sets := int(math.Floor(contVolume / setVolume))
+	limit := LimitVolume
+	if container.MaxWeight > 0 && setWeight > 0 {
+		if byWeight := int(math.Floor(container.MaxWeight / setWeight)); byWeight < sets {
+			sets, limit = byWeight, LimitWeight
+		}
+	}
+	if capped := maxCapacityUnits / setUnits; capped < sets {
+		sets, limit = capped, LimitCap
+	}
+	return max(sets, 0), limit
+}
+
+// completeSets counts the complete sets among the placed units.
+func completeSets(res packer.PackingResult, mix []capacityProduct) int {
+	placed := make(map[string]int, len(mix))
+	for _, it := range res.PackedItems {
+		placed[it.ItemID]++
+	}
+	sets := math.MaxInt
+	for _, m := range mix {
+		sets = min(sets, placed[m.product.ID]/m.ratio)
+	}
+	return sets
+}
+
+// trimToSets drops placed units beyond the given number of complete sets.
+// Removing units from a layout never makes the remaining units overlap.
+func trimToSets(res packer.PackingResult, container packer.ContainerInput, mix []capacityProduct, sets int) packer.PackingResult {
+	keep := make(map[string]int, len(mix))
+	weight := make(map[string]float64, len(mix))
+	for _, m := range mix {
+		keep[m.product.ID] = sets * m.ratio
+		weight[m.product.ID] = m.product.WeightKG
+	}
+
+	contVolM3 := container.Length * container.Width * container.Height / 1_000_000_000.0
+	contWeight := container.MaxWeight
+
+	out := res
+	out.PackedItems = make([]packer.PackedItem, 0, len(res.PackedItems))
+	out.TotalVolumePackedM3, out.TotalWeightPackedKG = 0, 0
+	for _, it := range res.PackedItems {
+		if keep[it.ItemID] == 0 {
+			continue
+		}
+		keep[it.ItemID]--
+		out.PackedItems = append(out.PackedItems, it)
+		out.TotalVolumePackedM3 += it.RotatedLength * it.RotatedWidth * it.RotatedHeight / 1_000_000_000.0
+		out.TotalWeightPackedKG += weight[it.ItemID]
+	}
+	out.TotalPackedItems = len(out.PackedItems)
+	out.VolumeUtilisationPct, out.WeightUtilisationPct = 0, 0
+	if contVolM3 > 0 {
+		out.VolumeUtilisationPct = out.TotalVolumePackedM3 / contVolM3 * 100
+	}
+	if contWeight > 0 {
+		out.WeightUtilisationPct = out.TotalWeightPackedKG / contWeight * 100
+	}
+	return out
+}
+
+func (s *capacityService) resolveProducts(ctx context.Context, req dto.MaxQuantityRequest) ([]capacityProduct, error) {
+	single := req.ProductID != nil || req.ProductSKU != nil
+	if single == (len(req.Mix) > 0) {
+		return nil, fmt.Errorf("%w: give either a product or a mix", ErrInvalidCapacityRequest)
+	}
+
+	entries := req.Mix
+	if single {
+		entries = []dto.MaxQuantityMixEntry{{ProductID: req.ProductID, ProductSKU: req.ProductSKU, Ratio: 1}}
+	}
+
+	mix := make([]capacityProduct, 0, len(entries))
+	seen := make(map[string]bool, len(entries))
+	for _, e := range entries {
+		if e.Ratio < 1 {
+			return nil, fmt.Errorf("%w: ratio must be positive", ErrInvalidCapacityRequest)
+		}
+
+		var product *dto.ProductResponse
+		var err error
+		switch {
+		case e.ProductID != nil:
+			product, err = s.products.GetProduct(ctx, *e.ProductID)
+		case e.ProductSKU != nil:
+			product, err = s.products.GetProductBySKU(ctx, *e.ProductSKU)
+		default:
+			return nil, fmt.Errorf("%w: each mix entry needs a product_id or product_sku", ErrInvalidCapacityRequest)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: product: %v", ErrCapacityInputNotFound, err)
+		}
+
+		if seen[product.ID] {
+			return nil, fmt.Errorf("%w: product %s is listed twice", ErrInvalidCapacityRequest, product.ID)
+		}
+		seen[product.ID] = true
+		mix = append(mix, capacityProduct{product: product, ratio: e.Ratio})
+	}
+	return mix, nil
+}
+
+func (s *capacityService) resolveContainer(ctx context.Context, req dto.MaxQuantityRequest) (packer.ContainerInput, dto.PlanContainerInfo, error) {
+	c := req.Container
+	in := packer.ContainerInput{
+		ID: "capacity",
+		Options: packer.PackOptions{
+			Strategy: req.Strategy,
+			Goal:     req.Goal,
+			Gravity:  req.Gravity != nil && *req.Gravity,
+		},
+	}
+	if c.WallClearanceMM != nil {
+		in.WallClearance = *c.WallClearanceMM
+	}
+	if c.ItemGapMM != nil {
+		in.ItemGap = *c.ItemGapMM
+	}
+	info := dto.PlanContainerInfo{ContainerID: c.ContainerID}
+
+	if c.ContainerID != nil {
+		contUUID, err := uuid.Parse(*c.ContainerID)
+		if err != nil {
+			return in, info, fmt.Errorf("%w: invalid container_id format", ErrInvalidCapacityRequest)
+		}
+
+		workspaceID, err := workspaceIDFromContext(ctx)
+		if err != nil {
+			return in, info, err
+		}
+		overrideWorkspaceID, err := workspaceOverrideIDFromContext(ctx)
+		if err != nil {
+			return in, info, err
+		}
+		if isFounder(ctx) && overrideWorkspaceID != nil {
+			workspaceID = overrideWorkspaceID
+		}
+
+		cont, err := s.q.GetContainer(ctx, store.GetContainerParams{ContainerID: contUUID, WorkspaceID: workspaceID})
+		if err != nil {
+			return in, info, fmt.Errorf("%w: container: %v", ErrCapacityInputNotFound, err)
+		}
+		in.Length = toFloat(cont.InnerLengthMm)
+		in.Width = toFloat(cont.InnerWidthMm)
+		in.Height = toFloat(cont.InnerHeightMm)
+		in.MaxWeight = toFloat(cont.MaxWeightKg)
+		info.Name = &cont.Name
+
+		in.LashingPointsPerSide = int(cont.LashingPointsPerSide)
+		info.LashingPointCapacityDaN = toOptionalFloat(cont.LashingPointCapacityDan)
+		info.FloorLoadKgM2 = toOptionalFloat(cont.FloorLoadKgM2)
+		info.LineLoadKgM = toOptionalFloat(cont.LineLoadKgM)
+		info.Compartments = decodeCompartments(cont.Compartments)
+	} else {
+		if c.LengthMM == nil || c.WidthMM == nil || c.HeightMM == nil || c.MaxWeightKG == nil {
+			return in, info, fmt.Errorf("%w: custom container dimensions are required", ErrInvalidCapacityRequest)
+		}
+		in.Length = *c.LengthMM
+		in.Width = *c.WidthMM
+		in.Height = *c.HeightMM
+		in.MaxWeight = *c.MaxWeightKG
+
+		if c.LashingPointsPerSide != nil {
+			in.LashingPointsPerSide = *c.LashingPointsPerSide
+		}
+		info.LashingPointCapacityDaN = c.LashingPointCapacityDaN
+		info.FloorLoadKgM2 = c.FloorLoadKgM2
+		info.LineLoadKgM = c.LineLoadKgM
+		if _, err := encodeCompartments(in.Length, c.Compartments); err != nil {
+			return in, info, fmt.Errorf("%w: %v", ErrInvalidCapacityRequest, err)
+		}
+		info.Compartments = c.Compartments
+	}
+	in.LashingPointCapacity = getFloat(info.LashingPointCapacityDaN)
+	in.FloorLoadLimit = getFloat(info.FloorLoadKgM2)
+	in.LineLoadLimit = getFloat(info.LineLoadKgM)
+	in.Compartments = packerCompartments(info.Compartments)
+
+	info.LengthMM = in.Length
+	info.WidthMM = in.Width
+	info.HeightMM = in.Height
+	info.MaxWeightKG = in.MaxWeight
+	info.VolumeM3 = in.Length * in.Width * in.Height / 1_000_000_000.0
+	info.WallClearanceMM = in.WallClearance
+	info.ItemGapMM = in.ItemGap
+	info.LashingPointsPerSide = in.LashingPointsPerSide
+	return in, info, nil
+}
@@ -0,0 +1,245 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ekastn/load-stuffing-calculator/internal/dto"
+	"github.com/ekastn/load-stuffing-calculator/internal/mocks"
+	"github.com/ekastn/load-stuffing-calculator/internal/packer"
+	"github.com/ekastn/load-stuffing-calculator/internal/service"
+	"github.com/ekastn/load-stuffing-calculator/internal/store"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// slotPacker places units in request order until it runs out of slots.
+func slotPacker(slots int, calls *[]int) *MockPacker {
+	return &MockPacker{
+		PackFunc: func(ctx context.Context, container packer.ContainerInput, items []packer.ItemInput) (packer.PackingResult, error) {
+			res := packer.PackingResult{Algorithm: "slots"}
+			total := 0
+			for _, it := range items {
+				total += it.Quantity
+				for i := 0; i < it.Quantity && len(res.PackedItems) < slots; i++ {
+					res.PackedItems = append(res.PackedItems, packer.PackedItem{
+						ItemID:        it.ID,
+						RotatedLength: it.Length,
+						RotatedWidth:  it.Width,
+						RotatedHeight: it.Height,
+					})
+				}
+			}
+			res.TotalPackedItems = len(res.PackedItems)
+			res.IsFeasible = total <= slots
+			*calls = append(*calls, total)
+			return res, nil
+		},
+	}
+}
+
+func cubeContainer(side, maxWeight float64) dto.CreatePlanContainer {
+	return dto.CreatePlanContainer{LengthMM: &side, WidthMM: &side, HeightMM: &side, MaxWeightKG: &maxWeight}
+}
+
+func TestCapacityService_MaxQuantity(t *testing.T) {
+	sku := "CUBE-100"
+	cube := &dto.ProductResponse{ID: "p1", Name: "Cube", SKU: &sku, LengthMM: 100, WidthMM: 100, HeightMM: 100, WeightKG: 1}
+
+	t.Run("search_finds_space_limit", func(t *testing.T) {
+		products := new(mocks.MockProductService)
+		products.On("GetProductBySKU", mock.Anything, sku).Return(cube, nil)
+		var calls []int
+
+		s := service.NewCapacityService(&MockQuerier{}, products, slotPacker(700, &calls))
+		resp, err := s.MaxQuantity(authedPlannerCtx(), dto.MaxQuantityRequest{
+			ProductSKU: &sku,
+			Container:  cubeContainer(1000, 10000),
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, 700, resp.MaxQuantity)
+		assert.Equal(t, 700, resp.Sets)
+		assert.Equal(t, service.LimitSpace, resp.LimitingFactor)
+		assert.True(t, resp.SearchComplete)
+		assert.Equal(t, 1000, calls[0]) // first probe fills to the volume bound
+		assert.Len(t, resp.Placements, 700)
+		assert.Equal(t, len(calls), resp.Iterations)
+		assert.InDelta(t, 70.0, resp.Stats.VolumeUtilizationPct, 1e-9)
+	})
+
+	t.Run("weight_bound_fits_first_time", func(t *testing.T) {
+		products := new(mocks.MockProductService)
+		products.On("GetProduct", mock.Anything, "p1").Return(cube, nil)
+		var calls []int
+		id := "p1"
+
+		s := service.NewCapacityService(&MockQuerier{}, products, slotPacker(1000, &calls))
+		resp, err := s.MaxQuantity(authedPlannerCtx(), dto.MaxQuantityRequest{
+			ProductID: &id,
+			Container: cubeContainer(1000, 50),
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, 50, resp.MaxQuantity)
+		assert.Equal(t, service.LimitWeight, resp.LimitingFactor)
+		assert.Equal(t, 1, resp.Iterations)
+		assert.True(t, resp.SearchComplete)
+	})
+
+	t.Run("mix_counts_complete_sets", func(t *testing.T) {
+		skuB := "CUBE-B"
+		other := &dto.ProductResponse{ID: "p2", Name: "Other", SKU: &skuB, LengthMM: 100, WidthMM: 100, HeightMM: 100, WeightKG: 1}
+		products := new(mocks.MockProductService)
+		products.On("GetProductBySKU", mock.Anything, sku).Return(cube, nil)
+		products.On("GetProductBySKU", mock.Anything, skuB).Return(other, nil)
+		var calls []int
+
+		s := service.NewCapacityService(&MockQuerier{}, products, slotPacker(100, &calls))
+		resp, err := s.MaxQuantity(authedPlannerCtx(), dto.MaxQuantityRequest{
+			Mix: []dto.MaxQuantityMixEntry{
+				{ProductSKU: &sku, Ratio: 2},
+				{ProductSKU: &skuB, Ratio: 1},
+			},
+			Container: cubeContainer(1000, 10000),
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, 33, resp.Sets)
+		assert.Equal(t, 99, resp.MaxQuantity)
+		require.Len(t, resp.Products, 2)
+		assert.Equal(t, 66, resp.Products[0].Quantity)
+		assert.Equal(t, 33, resp.Products[1].Quantity)
+		assert.Len(t, resp.Placements, 99)
+	})
+
+	t.Run("handling_attributes_reach_packer", func(t *testing.T) {
+		maxLoad, friction, temp, hazmat := 20.0, 0.4, "chilled", "3"
+		crate := *cube
+		crate.ThisSideUp = true
+		crate.Fragile = true
+		crate.MaxStackLoadKG = &maxLoad
+		crate.FrictionCoefficient = &friction
+		crate.TemperatureClass = &temp
+		crate.HazmatClass = &hazmat
+		products := new(mocks.MockProductService)
+		products.On("GetProductBySKU", mock.Anything, sku).Return(&crate, nil)
+		var seen []packer.ItemInput
+		p := &MockPacker{
+			PackFunc: func(ctx context.Context, container packer.ContainerInput, items []packer.ItemInput) (packer.PackingResult, error) {
+				seen = items
+				return packer.PackingResult{IsFeasible: true}, nil
+			},
+		}
+
+		s := service.NewCapacityService(&MockQuerier{}, products, p)
+		_, err := s.MaxQuantity(authedPlannerCtx(), dto.MaxQuantityRequest{
+			ProductSKU: &sku,
+			Container:  cubeContainer(1000, 10000),
+		})
+		require.NoError(t, err)
+
+		require.Len(t, seen, 1)
+		assert.True(t, seen[0].ThisSideUp)
+		assert.True(t, seen[0].Fragile)
+		assert.True(t, seen[0].NoStack) // Stackable is false on the product
+		assert.Equal(t, 20.0, seen[0].MaxStackLoad)
+		assert.Equal(t, 0.4, seen[0].Friction)
+		assert.Equal(t, "chilled", seen[0].TemperatureClass)
+		assert.Equal(t, "3", seen[0].HazmatClass)
+	})
+
+	t.Run("catalog_container_limits_reach_packer", func(t *testing.T) {
+		products := new(mocks.MockProductService)
+		products.On("GetProductBySKU", mock.Anything, sku).Return(cube, nil)
+		contID := uuid.New()
+		comps, err := json.Marshal([]dto.Compartment{
+			{Name: "Chilled", TemperatureClass: "chilled", StartMM: 0, LengthMM: 400},
+			{Name: "Dry", TemperatureClass: "ambient", StartMM: 400, LengthMM: 600},
+		})
+		require.NoError(t, err)
+		q := &MockQuerier{
+			GetContainerFunc: func(ctx context.Context, arg store.GetContainerParams) (store.Container, error) {
+				return store.Container{
+					ContainerID:             contID,
+					Name:                    "Reefer",
+					InnerLengthMm:           toNumeric(1000),
+					InnerWidthMm:            toNumeric(1000),
+					InnerHeightMm:           toNumeric(1000),
+					MaxWeightKg:             toNumeric(10000),
+					LashingPointsPerSide:    6,
+					LashingPointCapacityDan: toNumeric(800),
+					FloorLoadKgM2:           toNumeric(2500),
+					LineLoadKgM:             toNumeric(4000),
+					Compartments:            comps,
+				}, nil
+			},
+		}
+		var seen packer.ContainerInput
+		p := &MockPacker{
+			PackFunc: func(ctx context.Context, container packer.ContainerInput, items []packer.ItemInput) (packer.PackingResult, error) {
+				seen = container
+				return packer.PackingResult{IsFeasible: true}, nil
+			},
+		}
+
+		id := contID.String()
+		s := service.NewCapacityService(q, products, p)
+		resp, err := s.MaxQuantity(authedPlannerCtx(), dto.MaxQuantityRequest{
+			ProductSKU: &sku,
+			Container:  dto.CreatePlanContainer{ContainerID: &id},
+		})
+		require.NoError(t, err)
+
+		require.Len(t, seen.Compartments, 2)
+		assert.Equal(t, "chilled", seen.Compartments[0].TemperatureClass)
+		assert.Equal(t, 6, seen.LashingPointsPerSide)
+		assert.Equal(t, 800.0, seen.LashingPointCapacity)
+		assert.Equal(t, 2500.0, seen.FloorLoadLimit)
+		assert.Equal(t, 4000.0, seen.LineLoadLimit)
+		assert.Len(t, resp.Container.Compartments, 2)
+		assert.Equal(t, 6, resp.Container.LashingPointsPerSide)
+	})
+
+	t.Run("product_and_mix_rejected", func(t *testing.T) {
+		s := service.NewCapacityService(&MockQuerier{}, new(mocks.MockProductService), &MockPacker{})
+		_, err := s.MaxQuantity(authedPlannerCtx(), dto.MaxQuantityRequest{
+			ProductSKU: &sku,
+			Mix:        []dto.MaxQuantityMixEntry{{ProductSKU: &sku, Ratio: 1}},
+			Container:  cubeContainer(1000, 10000),
+		})
+		assert.ErrorIs(t, err, service.ErrInvalidCapacityRequest)
+	})
+
+	t.Run("unknown_product", func(t *testing.T) {
+		products := new(mocks.MockProductService)
+		products.On("GetProductBySKU", mock.Anything, sku).Return(nil, fmt.Errorf("no rows"))
+
+		s := service.NewCapacityService(&MockQuerier{}, products, &MockPacker{})
+		_, err := s.MaxQuantity(authedPlannerCtx(), dto.MaxQuantityRequest{
+			ProductSKU: &sku,
+			Container:  cubeContainer(1000, 10000),
+		})
+		assert.ErrorIs(t, err, service.ErrCapacityInputNotFound)
+	})
+
+	t.Run("packer_error", func(t *testing.T) {
+		products := new(mocks.MockProductService)
+		products.On("GetProductBySKU", mock.Anything, sku).Return(cube, nil)
+		p := &MockPacker{PackFunc: func(ctx context.Context, c packer.ContainerInput, items []packer.ItemInput) (packer.PackingResult, error) {
+			return packer.PackingResult{}, errors.New("boom")
+		}}
+
+		s := service.NewCapacityService(&MockQuerier{}, products, p)
+		_, err := s.MaxQuantity(authedPlannerCtx(), dto.MaxQuantityRequest{
+			ProductSKU: &sku,
+			Container:  cubeContainer(1000, 10000),
+		})
+		assert.Error(t, err)
+	})
+}
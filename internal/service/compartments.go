@@ -0,0 +1,87 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ekastn/load-stuffing-calculator/internal/dto"
+	"github.com/ekastn/load-stuffing-calculator/internal/packer"
+)
+
+// ErrInvalidCompartments is returned when compartments overlap or do not
+// fit the container.
+var ErrInvalidCompartments = fmt.Errorf("invalid compartments")
+
+// encodeCompartments checks comps against a container of lengthMM and
+// encodes them for storage. No compartments is stored as NULL.
+func encodeCompartments(lengthMM float64, comps []dto.Compartment) ([]byte, error) {
+	if len(comps) == 0 {
+		return nil, nil
+	}
+	cont := packer.ContainerInput{Length: lengthMM, Compartments: packerCompartments(comps)}
+	if err := packer.ValidateCompartments(cont); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCompartments, err)
+	}
+	raw, err := json.Marshal(comps)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode compartments: %w", err)
+	}
+	return raw, nil
+}
+
+// decodeCompartments reads stored compartments; NULL or bad JSON is none.
+func decodeCompartments(raw []byte) []dto.Compartment {
+	if len(raw) == 0 {
+		return nil
+	}
+	var comps []dto.Compartment
+	if err := json.Unmarshal(raw, &comps); err != nil {
+		return nil
+	}
+	return comps
+}
+
+func packerCompartments(comps []dto.Compartment) []packer.Compartment {
+	if len(comps) == 0 {
+		return nil
+	}
+	out := make([]packer.Compartment, 0, len(comps))
+	for _, c := range comps {
+		pc := packer.Compartment{
+			Name:             c.Name,
+			TemperatureClass: c.TemperatureClass,
+			Start:            c.StartMM,
+			Length:           c.LengthMM,
+		}
+		if c.MaxWeightKG != nil {
+			pc.MaxWeight = *c.MaxWeightKG
+		}
+		out = append(out, pc)
+	}
+	return out
+}
+
+// compartmentStats reports the load of each compartment of container.
+func compartmentStats(container packer.ContainerInput, packed []packer.PackedItem, items []packer.ItemInput) []dto.CompartmentStats {
+	usages := packer.CompartmentUsages(container, packed, items)
+	if len(usages) == 0 {
+		return nil
+	}
+	out := make([]dto.CompartmentStats, 0, len(usages))
+	for _, u := range usages {
+		class := u.TemperatureClass
+		if class == "" {
+			class = packer.TemperatureAmbient
+		}
+		out = append(out, dto.CompartmentStats{
+			Name:                 u.Name,
+			TemperatureClass:     class,
+			TotalItems:           u.Units,
+			TotalWeightKG:        u.WeightKG,
+			TotalVolumeM3:        u.VolumeM3,
+			VolumeUtilizationPct: u.VolumeUtilisationPct,
+			WeightUtilizationPct: u.WeightUtilisationPct,
+		})
+	}
+	return out
+}
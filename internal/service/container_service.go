@@ -47,6 +47,11 @@ func (s *containerService) CreateContainer(ctx context.Context, req dto.CreateCo
 		return nil, fmt.Errorf("workspace id is required")
 	}
 
+	compartments, err := encodeCompartments(req.InnerLengthMM, req.Compartments)
+	if err != nil {
+		return nil, err
+	}
+
 	container, err := s.q.CreateContainer(ctx, store.CreateContainerParams{
 		WorkspaceID:   workspaceID,
 		Name:          req.Name,
@@ -55,6 +60,12 @@ func (s *containerService) CreateContainer(ctx context.Context, req dto.CreateCo
 		InnerHeightMm: toNumeric(req.InnerHeightMM),
 		MaxWeightKg:   toNumeric(req.MaxWeightKG),
 		Description:   req.Description,
+
+		LashingPointsPerSide:    int32(req.LashingPointsPerSide),
+		LashingPointCapacityDan: toOptionalNumeric(req.LashingPointCapacityDaN),
+		Compartments:            compartments,
+		FloorLoadKgM2:           toOptionalNumeric(req.FloorLoadKgM2),
+		LineLoadKgM:             toOptionalNumeric(req.LineLoadKgM),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create container: %w", err)
@@ -151,6 +162,11 @@ func (s *containerService) UpdateContainer(ctx context.Context, id string, req d
 		return fmt.Errorf("invalid container id: %w", err)
 	}
 
+	compartments, err := encodeCompartments(req.InnerLengthMM, req.Compartments)
+	if err != nil {
+		return err
+	}
+
 	overrideWorkspaceID, err := workspaceOverrideIDFromContext(ctx)
 	if err != nil {
 		return err
@@ -165,6 +181,9 @@ func (s *containerService) UpdateContainer(ctx context.Context, id string, req d
 			InnerHeightMm: toNumeric(req.InnerHeightMM),
 			MaxWeightKg:   toNumeric(req.MaxWeightKG),
 			Description:   req.Description,
+
+			LashingPointsPerSide:    int32(req.LashingPointsPerSide),
+			LashingPointCapacityDan: toOptionalNumeric(req.LashingPointCapacityDaN),
 		})
 		if err != nil {
 			return fmt.Errorf("failed to update container: %w", err)
@@ -192,6 +211,12 @@ func (s *containerService) UpdateContainer(ctx context.Context, id string, req d
 		InnerHeightMm: toNumeric(req.InnerHeightMM),
 		MaxWeightKg:   toNumeric(req.MaxWeightKG),
 		Description:   req.Description,
+
+		LashingPointsPerSide:    int32(req.LashingPointsPerSide),
+		LashingPointCapacityDan: toOptionalNumeric(req.LashingPointCapacityDaN),
+		Compartments:            compartments,
+		FloorLoadKgM2:           toOptionalNumeric(req.FloorLoadKgM2),
+		LineLoadKgM:             toOptionalNumeric(req.LineLoadKgM),
 	})
 	if err != nil {
 		return fmt.Errorf("failed to update container: %w", err)
@@ -245,5 +270,11 @@ func mapContainerToResponse(c store.Container) *dto.ContainerResponse {
 		InnerHeightMM: toFloat(c.InnerHeightMm),
 		MaxWeightKG:   toFloat(c.MaxWeightKg),
 		Description:   c.Description,
+
+		LashingPointsPerSide:    int(c.LashingPointsPerSide),
+		LashingPointCapacityDaN: toOptionalFloat(c.LashingPointCapacityDan),
+		FloorLoadKgM2:           toOptionalFloat(c.FloorLoadKgM2),
+		LineLoadKgM:             toOptionalFloat(c.LineLoadKgM),
+		Compartments:            decodeCompartments(c.Compartments),
 	}
 }
@@ -2,6 +2,7 @@ package service_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"testing"
 
@@ -766,3 +767,55 @@ func TestContainerService_DeleteContainer(t *testing.T) {
 		}
 	})
 }
+
+func TestContainerService_Compartments(t *testing.T) {
+	chilledMax := 8000.0
+	req := dto.CreateContainerRequest{
+		Name:          "Reefer",
+		InnerLengthMM: 12000,
+		InnerWidthMM:  2300,
+		InnerHeightMM: 2500,
+		MaxWeightKG:   26000,
+		Compartments: []dto.Compartment{
+			{Name: "Dry", TemperatureClass: "ambient", StartMM: 0, LengthMM: 6000},
+			{Name: "Chilled", TemperatureClass: "chilled", StartMM: 6000, LengthMM: 6000, MaxWeightKG: &chilledMax},
+		},
+	}
+
+	t.Run("stored_and_returned", func(t *testing.T) {
+		mockQ := &MockQuerier{
+			CreateContainerFunc: func(ctx context.Context, arg store.CreateContainerParams) (store.Container, error) {
+				return store.Container{ContainerID: uuid.New(), Name: arg.Name, Compartments: arg.Compartments}, nil
+			},
+		}
+		s := service.NewContainerService(mockQ)
+
+		resp, err := s.CreateContainer(ctxWithWorkspaceID(uuid.New()), req)
+		if err != nil {
+			t.Fatalf("CreateContainer() error = %v", err)
+		}
+		if len(resp.Compartments) != 2 || resp.Compartments[1].Name != "Chilled" || *resp.Compartments[1].MaxWeightKG != chilledMax {
+			t.Errorf("Compartments = %+v", resp.Compartments)
+		}
+	})
+
+	t.Run("overlap_rejected", func(t *testing.T) {
+		bad := req
+		bad.Compartments = []dto.Compartment{
+			{Name: "Dry", TemperatureClass: "ambient", StartMM: 0, LengthMM: 7000},
+			{Name: "Chilled", TemperatureClass: "chilled", StartMM: 6000, LengthMM: 6000},
+		}
+		mockQ := &MockQuerier{
+			CreateContainerFunc: func(ctx context.Context, arg store.CreateContainerParams) (store.Container, error) {
+				t.Fatalf("unexpected db call")
+				return store.Container{}, nil
+			},
+		}
+		s := service.NewContainerService(mockQ)
+
+		_, err := s.CreateContainer(ctxWithWorkspaceID(uuid.New()), bad)
+		if !errors.Is(err, service.ErrInvalidCompartments) {
+			t.Errorf("CreateContainer() error = %v, want ErrInvalidCompartments", err)
+		}
+	})
+}
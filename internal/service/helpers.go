@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/ekastn/load-stuffing-calculator/internal/auth"
+	"github.com/ekastn/load-stuffing-calculator/internal/store"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 )
@@ -16,6 +17,23 @@ func toNumeric(f float64) pgtype.Numeric {
 	return n
 }
 
+// toOptionalNumeric maps nil to SQL NULL.
+func toOptionalNumeric(f *float64) pgtype.Numeric {
+	if f == nil {
+		return pgtype.Numeric{}
+	}
+	return toNumeric(*f)
+}
+
+// toOptionalFloat maps SQL NULL to nil.
+func toOptionalFloat(n pgtype.Numeric) *float64 {
+	if !n.Valid {
+		return nil
+	}
+	f := toFloat(n)
+	return &f
+}
+
 func workspaceIDFromContext(ctx context.Context) (*uuid.UUID, error) {
 	workspaceID, ok := auth.WorkspaceIDFromContext(ctx)
 	if !ok || workspaceID == "" {
@@ -40,9 +58,48 @@ func getString(s *string) string {
 	return *s
 }
 
+func getFloat(f *float64) float64 {
+	if f == nil {
+		return 0
+	}
+	return *f
+}
+
+func toOptionalInt(n *int32) *int {
+	if n == nil {
+		return nil
+	}
+	v := int(*n)
+	return &v
+}
+
+func boolOr(b *bool, def bool) bool {
+	if b == nil {
+		return def
+	}
+	return *b
+}
+
 func formatStoreTime(t *time.Time) string {
 	if t == nil {
 		return ""
 	}
 	return t.Format(time.RFC3339)
 }
+
+func uuidString(id *uuid.UUID) *string {
+	if id == nil {
+		return nil
+	}
+	s := id.String()
+	return &s
+}
+
+// inTx runs fn in one transaction of q when q supports them, and on q
+// otherwise.
+func inTx(ctx context.Context, q store.Querier, fn func(store.Querier) error) error {
+	if tx, ok := q.(store.TxQuerier); ok {
+		return tx.ExecTx(ctx, fn)
+	}
+	return fn(q)
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/ekastn/load-stuffing-calculator/internal/auth"
+	"github.com/ekastn/load-stuffing-calculator/internal/store"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 )
@@ -51,7 +52,24 @@ func floatPtr(f float64) *float64 {
 	return &f
 }
 
+// int32Ptr is a test helper to return a pointer to an int32.
+func int32Ptr(i int32) *int32 {
+	return &i
+}
+
 // timePtr is a test helper to return a pointer to a time.Time.
 func timePtr(t time.Time) *time.Time {
 	return &t
 }
+
+// lockPlanAs is a test helper stubbing LockLoadPlan with a plan status.
+func lockPlanAs(status *string) func(context.Context, uuid.UUID) (*string, error) {
+	return func(context.Context, uuid.UUID) (*string, error) {
+		return status, nil
+	}
+}
+
+// acceptStatusHistory is a test helper stubbing CreatePlanStatusHistory.
+func acceptStatusHistory(context.Context, store.CreatePlanStatusHistoryParams) (store.PlanStatusHistory, error) {
+	return store.PlanStatusHistory{}, nil
+}
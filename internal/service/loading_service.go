@@ -0,0 +1,535 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/ekastn/load-stuffing-calculator/internal/barcode"
+	"github.com/ekastn/load-stuffing-calculator/internal/dto"
+	"github.com/ekastn/load-stuffing-calculator/internal/gs1"
+	"github.com/ekastn/load-stuffing-calculator/internal/store"
+	"github.com/ekastn/load-stuffing-calculator/internal/types"
+	"github.com/google/uuid"
+)
+
+// LoadingService runs the loading of calculated plans: a session follows the
+// units of the plan's active result being scanned into the container in step
+// order and keeps every scan for audit.
+type LoadingService interface {
+	// StartSession opens a loading session and moves the plan to
+	// IN_PROGRESS. An open session of the plan is resumed instead.
+	StartSession(ctx context.Context, planID string) (*dto.LoadingSessionResponse, error)
+	GetSession(ctx context.Context, planID, sessionID string) (*dto.LoadingSessionResponse, error)
+	ListSessions(ctx context.Context, planID string) ([]dto.LoadingSessionResponse, error)
+	PauseSession(ctx context.Context, planID, sessionID string) (*dto.LoadingSessionResponse, error)
+	// CompleteSession closes a session whose steps are all loaded and
+	// completes the plan when its result allows it.
+	CompleteSession(ctx context.Context, planID, sessionID string) (*dto.LoadingSessionResponse, error)
+	RecordScan(ctx context.Context, planID, sessionID string, req dto.LoadingScanRequest) (*dto.LoadingScanResponse, error)
+	ListScans(ctx context.Context, planID, sessionID string) ([]dto.LoadingScanDetail, error)
+	// OfflineBundle packages what a device needs to validate scans without a
+	// connection, signed so the scans can be uploaded against it later.
+	OfflineBundle(ctx context.Context, planID string) (*dto.OfflineBundle, error)
+	// SyncOfflineScans records scans made offline against the bundle they
+	// were validated with and reports how each was reconciled.
+	SyncOfflineScans(ctx context.Context, planID string, req dto.OfflineSyncRequest) (*dto.OfflineSyncReport, error)
+}
+
+var (
+	ErrLoadingSessionNotFound = fmt.Errorf("loading session not found")
+
+	// ErrLoadingSessionNotActive is returned for scans and changes to a
+	// session that is paused, completed or abandoned.
+	ErrLoadingSessionNotActive = fmt.Errorf("loading session is not active")
+
+	// ErrLoadingIncomplete is returned when completing a session with steps
+	// left to load.
+	ErrLoadingIncomplete = fmt.Errorf("loading is not complete")
+)
+
+type loadingService struct {
+	q     store.Querier
+	codec *barcode.Codec
+}
+
+// NewLoadingService returns a LoadingService reading labels and signing
+// offline bundles with codec.
+func NewLoadingService(q store.Querier, codec *barcode.Codec) LoadingService {
+	return &loadingService{q: q, codec: codec}
+}
+
+func (s *loadingService) StartSession(ctx context.Context, planID string) (*dto.LoadingSessionResponse, error) {
+	pID, err := uuid.Parse(planID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid plan id")
+	}
+	scope, err := resolvePlanScope(ctx, s.q, pID)
+	if err != nil {
+		return nil, err
+	}
+	actor, err := actorFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	plan := scope.plan
+
+	// The plan stays locked while the session is opened, so it is pinned to
+	// the result that is active when the plan moves to IN_PROGRESS.
+	var session store.LoadingSession
+	var status types.PlanStatus
+	err = inTx(ctx, s.q, func(q store.Querier) error {
+		from, err := lockPlanStatus(ctx, q, pID)
+		if err != nil {
+			return err
+		}
+		status = from
+
+		if open, err := q.GetOpenLoadingSession(ctx, pID); err == nil {
+			if open.Status == types.LoadingSessionPaused.String() {
+				if _, err := q.ResumeLoadingSession(ctx, open.SessionID); err != nil {
+					return fmt.Errorf("failed to resume loading session: %w", err)
+				}
+				open.Status = types.LoadingSessionActive.String()
+				open.PausedAt = nil
+			}
+			session = open
+			return nil
+		}
+
+		// A plan set to IN_PROGRESS by hand may start loading without moving.
+		if from != types.PlanStatusInProgress {
+			if err := checkStatusTransition(ctx, q, plan, from, types.PlanStatusInProgress); err != nil {
+				return err
+			}
+		}
+		res, err := q.GetPlanResult(ctx, &pID)
+		if err != nil {
+			return fmt.Errorf("%w: loading requires a calculated plan", ErrInvalidStatusTransition)
+		}
+		placements, err := q.ListPlanPlacements(ctx, &res.ResultID)
+		if err != nil {
+			return fmt.Errorf("failed to list placements: %w", err)
+		}
+		if len(placements) == 0 {
+			return fmt.Errorf("%w: the plan has nothing to load", ErrInvalidStatusTransition)
+		}
+
+		session, err = q.CreateLoadingSession(ctx, store.CreateLoadingSessionParams{
+			PlanID:      pID,
+			ResultID:    &res.ResultID,
+			TotalSteps:  int32(len(placements)),
+			StartedByID: actor.id,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to start loading session: %w", err)
+		}
+
+		if from != types.PlanStatusInProgress {
+			reason := "loading session started"
+			if err := setPlanStatus(ctx, q, pID, scope.workspaceID, from, types.PlanStatusInProgress, &reason); err != nil {
+				return fmt.Errorf("failed to update plan status: %w", err)
+			}
+			status = types.PlanStatusInProgress
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.sessionResponse(ctx, session, status)
+}
+
+func (s *loadingService) GetSession(ctx context.Context, planID, sessionID string) (*dto.LoadingSessionResponse, error) {
+	scope, session, err := s.resolveSession(ctx, planID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	return s.sessionResponse(ctx, session, planStatusOf(scope.plan))
+}
+
+func (s *loadingService) ListSessions(ctx context.Context, planID string) ([]dto.LoadingSessionResponse, error) {
+	pID, err := uuid.Parse(planID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid plan id")
+	}
+	scope, err := resolvePlanScope(ctx, s.q, pID)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := s.q.ListLoadingSessions(ctx, pID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list loading sessions: %w", err)
+	}
+	status := planStatusOf(scope.plan)
+	out := make([]dto.LoadingSessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		resp, err := s.sessionResponse(ctx, session, status)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *resp)
+	}
+	return out, nil
+}
+
+func (s *loadingService) PauseSession(ctx context.Context, planID, sessionID string) (*dto.LoadingSessionResponse, error) {
+	scope, session, err := s.resolveSession(ctx, planID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	n, err := s.q.PauseLoadingSession(ctx, session.SessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pause loading session: %w", err)
+	}
+	if n == 0 {
+		return nil, fmt.Errorf("%w: session is %s", ErrLoadingSessionNotActive, session.Status)
+	}
+	session, err = s.q.GetLoadingSession(ctx, store.GetLoadingSessionParams{SessionID: session.SessionID, PlanID: session.PlanID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load loading session: %w", err)
+	}
+	return s.sessionResponse(ctx, session, planStatusOf(scope.plan))
+}
+
+func (s *loadingService) CompleteSession(ctx context.Context, planID, sessionID string) (*dto.LoadingSessionResponse, error) {
+	scope, session, err := s.resolveSession(ctx, planID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	actor, err := actorFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// The plan is locked so the session and the plan's status change
+	// together, against the result that is still active.
+	plan := scope.plan
+	var loaded map[int]bool
+	var status types.PlanStatus
+	err = inTx(ctx, s.q, func(q store.Querier) error {
+		from, err := lockPlanStatus(ctx, q, plan.PlanID)
+		if err != nil {
+			return err
+		}
+		status = from
+
+		session, err = q.GetLoadingSession(ctx, store.GetLoadingSessionParams{SessionID: session.SessionID, PlanID: session.PlanID})
+		if err != nil {
+			return fmt.Errorf("failed to load loading session: %w", err)
+		}
+		if !loadingSessionOpen(session) {
+			return fmt.Errorf("%w: session is %s", ErrLoadingSessionNotActive, session.Status)
+		}
+		loaded, err = loadedSteps(ctx, q, session.SessionID)
+		if err != nil {
+			return err
+		}
+		if len(loaded) < int(session.TotalSteps) {
+			return fmt.Errorf("%w: %d of %d steps loaded", ErrLoadingIncomplete, len(loaded), session.TotalSteps)
+		}
+
+		n, err := q.CompleteLoadingSession(ctx, store.CompleteLoadingSessionParams{SessionID: session.SessionID, CompletedByID: &actor.id})
+		if err != nil {
+			return fmt.Errorf("failed to complete loading session: %w", err)
+		}
+		if n == 0 {
+			return fmt.Errorf("%w: session was closed", ErrLoadingSessionNotActive)
+		}
+		session, err = q.GetLoadingSession(ctx, store.GetLoadingSessionParams{SessionID: session.SessionID, PlanID: session.PlanID})
+		if err != nil {
+			return fmt.Errorf("failed to load loading session: %w", err)
+		}
+
+		// A plan whose result left units out stays IN_PROGRESS: loading the
+		// placed units does not complete it.
+		if from == types.PlanStatusInProgress && checkStatusTransition(ctx, q, plan, from, types.PlanStatusCompleted) == nil {
+			reason := "loading session completed"
+			if err := setPlanStatus(ctx, q, plan.PlanID, scope.workspaceID, from, types.PlanStatusCompleted, &reason); err != nil {
+				return fmt.Errorf("failed to update plan status: %w", err)
+			}
+			status = types.PlanStatusCompleted
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return mapLoadingSession(session, loaded, status), nil
+}
+
+// RecordScan checks a scanned label against the session's next expected step
+// and records the scan whatever its outcome.
+func (s *loadingService) RecordScan(ctx context.Context, planID, sessionID string, req dto.LoadingScanRequest) (*dto.LoadingScanResponse, error) {
+	scope, session, err := s.resolveSession(ctx, planID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	actor, err := actorFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status != types.LoadingSessionActive.String() {
+		return nil, fmt.Errorf("%w: session is %s", ErrLoadingSessionNotActive, session.Status)
+	}
+
+	loaded, err := loadedSteps(ctx, s.q, session.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	var placements []store.PlanPlacement
+	if session.ResultID != nil {
+		placements, err = s.q.ListPlanPlacements(ctx, session.ResultID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list placements: %w", err)
+		}
+	}
+	gtins, err := s.itemGTINs(ctx, session.PlanID)
+	if err != nil {
+		return nil, err
+	}
+
+	expected := nextLoadingStep(int(session.TotalSteps), loaded)
+	status, step := classifyScan(s.codec, req.Barcode, session.PlanID, placements, gtins, loaded, expected)
+
+	scan, err := s.insertScan(ctx, store.CreateLoadingScanParams{
+		SessionID:    session.SessionID,
+		Barcode:      req.Barcode,
+		Status:       status.String(),
+		StepNumber:   int32FromInt(step),
+		ExpectedStep: int32FromInt(expected),
+		OperatorID:   actor.id,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to record scan: %w", err)
+	}
+	if scan.Status == types.ScanMatched.String() {
+		loaded[*step] = true
+	}
+
+	return &dto.LoadingScanResponse{
+		Scan:    mapLoadingScan(scan),
+		Session: *mapLoadingSession(session, loaded, planStatusOf(scope.plan)),
+	}, nil
+}
+
+func (s *loadingService) ListScans(ctx context.Context, planID, sessionID string) ([]dto.LoadingScanDetail, error) {
+	_, session, err := s.resolveSession(ctx, planID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	scans, err := s.q.ListLoadingScans(ctx, session.SessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scans: %w", err)
+	}
+	out := make([]dto.LoadingScanDetail, 0, len(scans))
+	for _, scan := range scans {
+		out = append(out, mapLoadingScan(scan))
+	}
+	return out, nil
+}
+
+// insertScan records a scan. A match that loses the race for its step to
+// another scan is recorded as a duplicate.
+func (s *loadingService) insertScan(ctx context.Context, params store.CreateLoadingScanParams) (store.LoadingScan, error) {
+	scan, err := s.q.CreateLoadingScan(ctx, params)
+	if err != nil && params.Status == types.ScanMatched.String() && isUniqueViolation(err) {
+		params.Status = types.ScanDuplicate.String()
+		scan, err = s.q.CreateLoadingScan(ctx, params)
+	}
+	return scan, err
+}
+
+// resolveSession loads a session of a plan the caller in ctx may see.
+func (s *loadingService) resolveSession(ctx context.Context, planID, sessionID string) (*planScope, store.LoadingSession, error) {
+	pID, err := uuid.Parse(planID)
+	if err != nil {
+		return nil, store.LoadingSession{}, fmt.Errorf("invalid plan id")
+	}
+	sID, err := uuid.Parse(sessionID)
+	if err != nil {
+		return nil, store.LoadingSession{}, fmt.Errorf("invalid session id")
+	}
+	scope, err := resolvePlanScope(ctx, s.q, pID)
+	if err != nil {
+		return nil, store.LoadingSession{}, err
+	}
+
+	session, err := s.q.GetLoadingSession(ctx, store.GetLoadingSessionParams{SessionID: sID, PlanID: pID})
+	if err != nil {
+		return nil, store.LoadingSession{}, ErrLoadingSessionNotFound
+	}
+	return scope, session, nil
+}
+
+// loadedSteps returns the steps of a session matched so far.
+func loadedSteps(ctx context.Context, q store.Querier, sessionID uuid.UUID) (map[int]bool, error) {
+	steps, err := q.ListMatchedLoadingSteps(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list loaded steps: %w", err)
+	}
+	loaded := make(map[int]bool, len(steps))
+	for _, step := range steps {
+		if step != nil {
+			loaded[int(*step)] = true
+		}
+	}
+	return loaded, nil
+}
+
+func (s *loadingService) sessionResponse(ctx context.Context, session store.LoadingSession, planStatus types.PlanStatus) (*dto.LoadingSessionResponse, error) {
+	loaded, err := loadedSteps(ctx, s.q, session.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	return mapLoadingSession(session, loaded, planStatus), nil
+}
+
+func loadingSessionOpen(session store.LoadingSession) bool {
+	return session.Status == types.LoadingSessionActive.String() || session.Status == types.LoadingSessionPaused.String()
+}
+
+// nextLoadingStep is the lowest step not loaded yet, or nil when all are.
+func nextLoadingStep(total int, loaded map[int]bool) *int {
+	for step := 1; step <= total; step++ {
+		if !loaded[step] {
+			return &step
+		}
+	}
+	return nil
+}
+
+// classifyScan decides the outcome of scanning code and the step it names.
+// The label must name the plan, a step of its result and the item placed at
+// that step. A scanned GTIN stands for the steps of the items carrying it,
+// see classifyGTINScan.
+func classifyScan(codec *barcode.Codec, code string, planID uuid.UUID, placements []store.PlanPlacement, gtins map[uuid.UUID]string, loaded map[int]bool, expected *int) (types.ScanStatus, *int) {
+	parsed, err := codec.Parse(code)
+	switch {
+	case errors.Is(err, barcode.ErrInvalidSignature):
+		return types.ScanInvalidSignature, nil
+	case errors.Is(err, barcode.ErrInvalidFormat):
+		if gtin, ok := gs1.ScannedGTIN(code); ok {
+			return classifyGTINScan(gtin, placements, gtins, loaded, expected)
+		}
+		return types.ScanInvalidFormat, nil
+	case err != nil:
+		return types.ScanInvalidFormat, nil
+	}
+	if !parsed.MatchesPlan(planID) {
+		return types.ScanWrongPlan, nil
+	}
+	step := parsed.StepNumber
+
+	known := false
+	for _, pl := range placements {
+		if int(pl.StepNumber) == step && pl.ItemID != nil && parsed.MatchesItem(*pl.ItemID) {
+			known = true
+			break
+		}
+	}
+	switch {
+	case !known:
+		return types.ScanUnknownStep, &step
+	case loaded[step]:
+		return types.ScanDuplicate, &step
+	case expected == nil || *expected != step:
+		return types.ScanOutOfSequence, &step
+	}
+	return types.ScanMatched, &step
+}
+
+// classifyGTINScan resolves a scanned GTIN to a step. Any unit of the product
+// will do, so the expected step matches when it holds the product; otherwise
+// the scan names the first unloaded step holding it.
+func classifyGTINScan(gtin string, placements []store.PlanPlacement, gtins map[uuid.UUID]string, loaded map[int]bool, expected *int) (types.ScanStatus, *int) {
+	var steps []int
+	for _, pl := range placements {
+		if pl.StepNumber == 0 || pl.ItemID == nil || gtins[*pl.ItemID] != gtin {
+			continue
+		}
+		steps = append(steps, int(pl.StepNumber))
+	}
+	if len(steps) == 0 {
+		return types.ScanUnknownProduct, nil
+	}
+	sort.Ints(steps)
+
+	for _, step := range steps {
+		if expected != nil && *expected == step {
+			return types.ScanMatched, &step
+		}
+	}
+	for _, step := range steps {
+		if !loaded[step] {
+			return types.ScanOutOfSequence, &step
+		}
+	}
+	return types.ScanDuplicate, &steps[len(steps)-1]
+}
+
+// itemGTINs maps the items of a plan that carry a GTIN to it.
+func (s *loadingService) itemGTINs(ctx context.Context, planID uuid.UUID) (map[uuid.UUID]string, error) {
+	items, err := s.q.ListLoadItems(ctx, &planID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list items: %w", err)
+	}
+	gtins := make(map[uuid.UUID]string)
+	for _, it := range items {
+		if it.Gtin != nil {
+			gtins[it.ItemID] = *it.Gtin
+		}
+	}
+	return gtins, nil
+}
+
+func mapLoadingSession(session store.LoadingSession, loaded map[int]bool, planStatus types.PlanStatus) *dto.LoadingSessionResponse {
+	total := int(session.TotalSteps)
+	resp := &dto.LoadingSessionResponse{
+		SessionID:   session.SessionID.String(),
+		PlanID:      session.PlanID.String(),
+		Status:      session.Status,
+		PlanStatus:  planStatus.String(),
+		TotalSteps:  total,
+		LoadedSteps: len(loaded),
+		StartedByID: session.StartedByID.String(),
+		StartedAt:   session.StartedAt,
+		PausedAt:    session.PausedAt,
+		CompletedAt: session.CompletedAt,
+	}
+	if total > 0 {
+		resp.ProgressPct = float64(len(loaded)) / float64(total) * 100
+	}
+	if loadingSessionOpen(session) {
+		resp.NextExpectedStep = nextLoadingStep(total, loaded)
+	}
+	if session.ResultID != nil {
+		id := session.ResultID.String()
+		resp.ResultID = &id
+	}
+	if session.CompletedByID != nil {
+		id := session.CompletedByID.String()
+		resp.CompletedByID = &id
+	}
+	return resp
+}
+
+func mapLoadingScan(scan store.LoadingScan) dto.LoadingScanDetail {
+	d := dto.LoadingScanDetail{
+		ScanID:       scan.ScanID.String(),
+		Barcode:      scan.Barcode,
+		Status:       scan.Status,
+		StepNumber:   intFromInt32(scan.StepNumber),
+		ExpectedStep: intFromInt32(scan.ExpectedStep),
+		OperatorID:   scan.OperatorID.String(),
+		ScannedAt:    scan.ScannedAt,
+	}
+	if scan.ClientEventID != nil {
+		id := scan.ClientEventID.String()
+		d.EventID = &id
+	}
+	return d
+}
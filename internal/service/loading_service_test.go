@@ -0,0 +1,529 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ekastn/load-stuffing-calculator/internal/barcode"
+	"github.com/ekastn/load-stuffing-calculator/internal/dto"
+	"github.com/ekastn/load-stuffing-calculator/internal/service"
+	"github.com/ekastn/load-stuffing-calculator/internal/store"
+	"github.com/ekastn/load-stuffing-calculator/internal/types"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testLoadingCodec = barcode.NewCodec("loading-secret", true)
+
+// loadingFixture keeps a plan, its result and its loading sessions in memory.
+type loadingFixture struct {
+	planID   uuid.UUID
+	resultID uuid.UUID
+	items    []uuid.UUID
+	gtins    map[int]string // step -> GTIN of the item placed there
+	status   types.PlanStatus
+	feasible bool
+	sessions map[uuid.UUID]*store.LoadingSession
+	scans    []store.LoadingScan
+	history  []store.CreatePlanStatusHistoryParams
+	q        *MockQuerier
+}
+
+func newLoadingFixture(status types.PlanStatus, feasible bool, steps int) *loadingFixture {
+	f := &loadingFixture{
+		planID:   uuid.New(),
+		resultID: uuid.New(),
+		status:   status,
+		feasible: feasible,
+		sessions: make(map[uuid.UUID]*store.LoadingSession),
+	}
+	for i := 0; i < steps; i++ {
+		f.items = append(f.items, uuid.New())
+	}
+
+	f.q = &MockQuerier{
+		GetLoadPlanFunc: func(ctx context.Context, arg store.GetLoadPlanParams) (store.LoadPlan, error) {
+			return store.LoadPlan{PlanID: f.planID, Status: stringPtr(f.status.String())}, nil
+		},
+		GetPlanResultFunc: func(ctx context.Context, id *uuid.UUID) (store.PlanResult, error) {
+			return store.PlanResult{ResultID: f.resultID, PlanID: id, IsFeasible: &f.feasible}, nil
+		},
+		ListPlanPlacementsFunc: func(ctx context.Context, id *uuid.UUID) ([]store.PlanPlacement, error) {
+			out := make([]store.PlanPlacement, 0, len(f.items))
+			for i := range f.items {
+				out = append(out, store.PlanPlacement{ResultID: id, ItemID: &f.items[i], StepNumber: int32(i + 1)})
+			}
+			return out, nil
+		},
+		ListLoadItemsFunc: func(ctx context.Context, id *uuid.UUID) ([]store.LoadItem, error) {
+			out := make([]store.LoadItem, 0, len(f.items))
+			for i, itemID := range f.items {
+				it := store.LoadItem{ItemID: itemID, ItemLabel: stringPtr(fmt.Sprintf("Box %d", i+1)), Quantity: 1}
+				if gtin, ok := f.gtins[i+1]; ok {
+					it.Gtin = &gtin
+				}
+				out = append(out, it)
+			}
+			return out, nil
+		},
+		LockLoadPlanFunc: func(ctx context.Context, id uuid.UUID) (*string, error) {
+			return stringPtr(f.status.String()), nil
+		},
+		UpdatePlanStatusFunc: func(ctx context.Context, arg store.UpdatePlanStatusParams) error {
+			f.status = types.PlanStatus(*arg.Status)
+			return nil
+		},
+		CreatePlanStatusHistoryFunc: func(ctx context.Context, arg store.CreatePlanStatusHistoryParams) (store.PlanStatusHistory, error) {
+			f.history = append(f.history, arg)
+			return store.PlanStatusHistory{}, nil
+		},
+		GetOpenLoadingSessionFunc: func(ctx context.Context, planID uuid.UUID) (store.LoadingSession, error) {
+			for _, s := range f.sessions {
+				if s.Status == "active" || s.Status == "paused" {
+					return *s, nil
+				}
+			}
+			return store.LoadingSession{}, fmt.Errorf("no rows in result set")
+		},
+		CreateLoadingSessionFunc: func(ctx context.Context, arg store.CreateLoadingSessionParams) (store.LoadingSession, error) {
+			s := &store.LoadingSession{
+				SessionID:   uuid.New(),
+				PlanID:      arg.PlanID,
+				ResultID:    arg.ResultID,
+				Status:      "active",
+				TotalSteps:  arg.TotalSteps,
+				StartedByID: arg.StartedByID,
+				StartedAt:   time.Now(),
+			}
+			f.sessions[s.SessionID] = s
+			return *s, nil
+		},
+		GetLoadingSessionFunc: func(ctx context.Context, arg store.GetLoadingSessionParams) (store.LoadingSession, error) {
+			s, ok := f.sessions[arg.SessionID]
+			if !ok || s.PlanID != arg.PlanID {
+				return store.LoadingSession{}, fmt.Errorf("no rows in result set")
+			}
+			return *s, nil
+		},
+		PauseLoadingSessionFunc: func(ctx context.Context, id uuid.UUID) (int64, error) {
+			return f.setSessionStatus(id, "active", "paused"), nil
+		},
+		ResumeLoadingSessionFunc: func(ctx context.Context, id uuid.UUID) (int64, error) {
+			return f.setSessionStatus(id, "paused", "active"), nil
+		},
+		CompleteLoadingSessionFunc: func(ctx context.Context, arg store.CompleteLoadingSessionParams) (int64, error) {
+			n := f.setSessionStatus(arg.SessionID, "active", "completed") + f.setSessionStatus(arg.SessionID, "paused", "completed")
+			if n > 0 {
+				f.sessions[arg.SessionID].CompletedByID = arg.CompletedByID
+			}
+			return n, nil
+		},
+		ListMatchedLoadingStepsFunc: func(ctx context.Context, id uuid.UUID) ([]*int32, error) {
+			var out []*int32
+			for _, sc := range f.scans {
+				if sc.SessionID == id && sc.Status == "MATCHED" {
+					out = append(out, sc.StepNumber)
+				}
+			}
+			return out, nil
+		},
+		CreateLoadingScanFunc: func(ctx context.Context, arg store.CreateLoadingScanParams) (store.LoadingScan, error) {
+			sc := store.LoadingScan{
+				ScanID:        uuid.New(),
+				SessionID:     arg.SessionID,
+				Barcode:       arg.Barcode,
+				Status:        arg.Status,
+				StepNumber:    arg.StepNumber,
+				ExpectedStep:  arg.ExpectedStep,
+				OperatorID:    arg.OperatorID,
+				ScannedAt:     time.Now(),
+				ClientEventID: arg.ClientEventID,
+			}
+			if arg.ScannedAt != nil {
+				sc.ScannedAt = *arg.ScannedAt
+			}
+			f.scans = append(f.scans, sc)
+			return sc, nil
+		},
+		ListLoadingSessionsFunc: func(ctx context.Context, planID uuid.UUID) ([]store.LoadingSession, error) {
+			out := make([]store.LoadingSession, 0, len(f.sessions))
+			for _, s := range f.sessions {
+				out = append(out, *s)
+			}
+			return out, nil
+		},
+		ListLoadingScansFunc: func(ctx context.Context, id uuid.UUID) ([]store.LoadingScan, error) {
+			var out []store.LoadingScan
+			for _, sc := range f.scans {
+				if sc.SessionID == id {
+					out = append(out, sc)
+				}
+			}
+			return out, nil
+		},
+	}
+	return f
+}
+
+func (f *loadingFixture) setSessionStatus(id uuid.UUID, from, to string) int64 {
+	s, ok := f.sessions[id]
+	if !ok || s.Status != from {
+		return 0
+	}
+	s.Status = to
+	return 1
+}
+
+func (f *loadingFixture) label(step int) string {
+	return testLoadingCodec.Generate(f.planID, step, f.items[step-1])
+}
+
+func TestLoadingService_Session(t *testing.T) {
+	ctx := authedPlannerCtx()
+
+	t.Run("full_loading_completes_plan", func(t *testing.T) {
+		f := newLoadingFixture(types.PlanStatusPlanned, true, 3)
+		s := service.NewLoadingService(f.q, testLoadingCodec)
+
+		session, err := s.StartSession(ctx, f.planID.String())
+		require.NoError(t, err)
+		assert.Equal(t, "active", session.Status)
+		assert.Equal(t, types.PlanStatusInProgress, f.status)
+		assert.Equal(t, 3, session.TotalSteps)
+		require.NotNil(t, session.NextExpectedStep)
+		assert.Equal(t, 1, *session.NextExpectedStep)
+
+		for step := 1; step <= 3; step++ {
+			resp, err := s.RecordScan(ctx, f.planID.String(), session.SessionID, dto.LoadingScanRequest{Barcode: f.label(step)})
+			require.NoError(t, err)
+			assert.Equal(t, "MATCHED", resp.Scan.Status)
+			assert.Equal(t, step, resp.Session.LoadedSteps)
+		}
+
+		done, err := s.CompleteSession(ctx, f.planID.String(), session.SessionID)
+		require.NoError(t, err)
+		assert.Equal(t, "completed", done.Status)
+		assert.Equal(t, types.PlanStatusCompleted.String(), done.PlanStatus)
+		assert.InDelta(t, 100, done.ProgressPct, 1e-9)
+		assert.Nil(t, done.NextExpectedStep)
+		assert.Equal(t, types.PlanStatusCompleted, f.status)
+
+		require.Len(t, f.history, 2)
+		assert.Equal(t, "IN_PROGRESS", f.history[0].ToStatus)
+		assert.Equal(t, "COMPLETED", f.history[1].ToStatus)
+	})
+
+	t.Run("scan_outcomes", func(t *testing.T) {
+		f := newLoadingFixture(types.PlanStatusPlanned, true, 3)
+		s := service.NewLoadingService(f.q, testLoadingCodec)
+		session, err := s.StartSession(ctx, f.planID.String())
+		require.NoError(t, err)
+
+		scan := func(code string) *dto.LoadingScanResponse {
+			resp, err := s.RecordScan(ctx, f.planID.String(), session.SessionID, dto.LoadingScanRequest{Barcode: code})
+			require.NoError(t, err)
+			return resp
+		}
+
+		assert.Equal(t, "INVALID_FORMAT", scan("not-a-label").Scan.Status)
+		assert.Equal(t, "INVALID_SIGNATURE", scan(barcode.NewCodec("forged", true).Generate(f.planID, 1, f.items[0])).Scan.Status)
+		assert.Equal(t, "WRONG_PLAN", scan(testLoadingCodec.Generate(uuid.New(), 1, f.items[0])).Scan.Status)
+		assert.Equal(t, "UNKNOWN_STEP", scan(testLoadingCodec.Generate(f.planID, 9, f.items[0])).Scan.Status)
+		assert.Equal(t, "UNKNOWN_STEP", scan(testLoadingCodec.Generate(f.planID, 1, f.items[1])).Scan.Status)
+
+		out := scan(f.label(2))
+		assert.Equal(t, "OUT_OF_SEQUENCE", out.Scan.Status)
+		require.NotNil(t, out.Scan.ExpectedStep)
+		assert.Equal(t, 1, *out.Scan.ExpectedStep)
+		assert.Equal(t, 0, out.Session.LoadedSteps)
+
+		first := scan(f.label(1))
+		assert.Equal(t, "MATCHED", first.Scan.Status)
+		assert.Equal(t, 2, *first.Session.NextExpectedStep)
+		assert.InDelta(t, 100.0/3, first.Session.ProgressPct, 1e-9)
+
+		dup := scan(f.label(1))
+		assert.Equal(t, "DUPLICATE", dup.Scan.Status)
+		assert.Equal(t, 1, dup.Session.LoadedSteps)
+
+		trail, err := s.ListScans(ctx, f.planID.String(), session.SessionID)
+		require.NoError(t, err)
+		require.Len(t, trail, 8)
+		assert.Equal(t, "not-a-label", trail[0].Barcode)
+		assert.Nil(t, trail[0].StepNumber)
+		assert.NotEmpty(t, trail[0].OperatorID)
+	})
+
+	t.Run("legacy_labels", func(t *testing.T) {
+		f := newLoadingFixture(types.PlanStatusPlanned, true, 2)
+		legacy := barcode.Generate(f.planID, 1, f.items[0])
+
+		strict := service.NewLoadingService(f.q, barcode.NewCodec("loading-secret", false))
+		session, err := strict.StartSession(ctx, f.planID.String())
+		require.NoError(t, err)
+		resp, err := strict.RecordScan(ctx, f.planID.String(), session.SessionID, dto.LoadingScanRequest{Barcode: legacy})
+		require.NoError(t, err)
+		assert.Equal(t, "INVALID_FORMAT", resp.Scan.Status)
+
+		s := service.NewLoadingService(f.q, testLoadingCodec)
+		resp, err = s.RecordScan(ctx, f.planID.String(), session.SessionID, dto.LoadingScanRequest{Barcode: legacy})
+		require.NoError(t, err)
+		assert.Equal(t, "MATCHED", resp.Scan.Status)
+	})
+
+	t.Run("gtin_scans", func(t *testing.T) {
+		f := newLoadingFixture(types.PlanStatusPlanned, true, 4)
+		f.gtins = map[int]string{1: "05901234123457", 2: "04006381333931", 4: "04006381333931"}
+		s := service.NewLoadingService(f.q, testLoadingCodec)
+		session, err := s.StartSession(ctx, f.planID.String())
+		require.NoError(t, err)
+
+		scan := func(code string) dto.LoadingScanDetail {
+			resp, err := s.RecordScan(ctx, f.planID.String(), session.SessionID, dto.LoadingScanRequest{Barcode: code})
+			require.NoError(t, err)
+			return resp.Scan
+		}
+		assertScan := func(d dto.LoadingScanDetail, status string, step int) {
+			t.Helper()
+			assert.Equal(t, status, d.Status)
+			require.NotNil(t, d.StepNumber)
+			assert.Equal(t, step, *d.StepNumber)
+		}
+
+		// Step 1 is expected; the product of steps 2 and 4 names step 2.
+		assertScan(scan("(01)04006381333931"), "OUT_OF_SEQUENCE", 2)
+		assertScan(scan("5901234123457"), "MATCHED", 1)
+		assertScan(scan("]C10104006381333931"), "MATCHED", 2)
+		// Step 3 has no GTIN, so the product's next unit is step 4.
+		assertScan(scan("4006381333931"), "OUT_OF_SEQUENCE", 4)
+		assertScan(scan("05901234123457"), "DUPLICATE", 1)
+
+		unknown := scan("73513537")
+		assert.Equal(t, "UNKNOWN_PRODUCT", unknown.Status)
+		assert.Nil(t, unknown.StepNumber)
+		assert.Equal(t, "INVALID_FORMAT", scan("4006381333932").Status)
+	})
+
+	t.Run("incomplete_session_is_not_completed", func(t *testing.T) {
+		f := newLoadingFixture(types.PlanStatusPlanned, true, 2)
+		s := service.NewLoadingService(f.q, testLoadingCodec)
+		session, err := s.StartSession(ctx, f.planID.String())
+		require.NoError(t, err)
+
+		_, err = s.CompleteSession(ctx, f.planID.String(), session.SessionID)
+		assert.ErrorIs(t, err, service.ErrLoadingIncomplete)
+		assert.Contains(t, err.Error(), "0 of 2")
+		assert.Equal(t, types.PlanStatusInProgress, f.status)
+	})
+
+	t.Run("pause_and_resume", func(t *testing.T) {
+		f := newLoadingFixture(types.PlanStatusPlanned, true, 2)
+		s := service.NewLoadingService(f.q, testLoadingCodec)
+		session, err := s.StartSession(ctx, f.planID.String())
+		require.NoError(t, err)
+
+		paused, err := s.PauseSession(ctx, f.planID.String(), session.SessionID)
+		require.NoError(t, err)
+		assert.Equal(t, "paused", paused.Status)
+
+		_, err = s.RecordScan(ctx, f.planID.String(), session.SessionID, dto.LoadingScanRequest{Barcode: f.label(1)})
+		assert.ErrorIs(t, err, service.ErrLoadingSessionNotActive)
+
+		_, err = s.PauseSession(ctx, f.planID.String(), session.SessionID)
+		assert.ErrorIs(t, err, service.ErrLoadingSessionNotActive)
+
+		resumed, err := s.StartSession(ctx, f.planID.String())
+		require.NoError(t, err)
+		assert.Equal(t, session.SessionID, resumed.SessionID)
+		assert.Equal(t, "active", resumed.Status)
+		assert.Len(t, f.sessions, 1)
+	})
+
+	t.Run("partial_plan_stays_in_progress", func(t *testing.T) {
+		f := newLoadingFixture(types.PlanStatusPartial, false, 1)
+		s := service.NewLoadingService(f.q, testLoadingCodec)
+		session, err := s.StartSession(ctx, f.planID.String())
+		require.NoError(t, err)
+		_, err = s.RecordScan(ctx, f.planID.String(), session.SessionID, dto.LoadingScanRequest{Barcode: f.label(1)})
+		require.NoError(t, err)
+
+		done, err := s.CompleteSession(ctx, f.planID.String(), session.SessionID)
+		require.NoError(t, err)
+		assert.Equal(t, "completed", done.Status)
+		assert.Equal(t, types.PlanStatusInProgress.String(), done.PlanStatus)
+	})
+
+	t.Run("draft_plan_cannot_load", func(t *testing.T) {
+		f := newLoadingFixture(types.PlanStatusDraft, true, 1)
+		s := service.NewLoadingService(f.q, testLoadingCodec)
+		_, err := s.StartSession(ctx, f.planID.String())
+		assert.ErrorIs(t, err, service.ErrInvalidStatusTransition)
+		assert.Empty(t, f.sessions)
+	})
+
+	t.Run("start_runs_in_one_transaction", func(t *testing.T) {
+		f := newLoadingFixture(types.PlanStatusPlanned, true, 2)
+		var calls []string
+		f.q.ExecTxFunc = func(ctx context.Context, fn func(store.Querier) error) error {
+			calls = append(calls, "begin")
+			err := fn(f.q)
+			calls = append(calls, "end")
+			return err
+		}
+		create := f.q.CreateLoadingSessionFunc
+		f.q.CreateLoadingSessionFunc = func(ctx context.Context, arg store.CreateLoadingSessionParams) (store.LoadingSession, error) {
+			calls = append(calls, "session")
+			return create(ctx, arg)
+		}
+		f.q.UpdatePlanStatusFunc = func(ctx context.Context, arg store.UpdatePlanStatusParams) error {
+			calls = append(calls, "status")
+			return fmt.Errorf("status write failed")
+		}
+		s := service.NewLoadingService(f.q, testLoadingCodec)
+
+		_, err := s.StartSession(ctx, f.planID.String())
+		assert.ErrorContains(t, err, "status write failed")
+		assert.Equal(t, []string{"begin", "session", "status", "end"}, calls)
+	})
+
+	t.Run("locked_status_is_checked", func(t *testing.T) {
+		// The plan was read as PLANNED but was cancelled before the lock.
+		f := newLoadingFixture(types.PlanStatusPlanned, true, 1)
+		f.q.LockLoadPlanFunc = lockPlanAs(stringPtr(types.PlanStatusCancelled.String()))
+		s := service.NewLoadingService(f.q, testLoadingCodec)
+
+		_, err := s.StartSession(ctx, f.planID.String())
+		assert.ErrorIs(t, err, service.ErrInvalidStatusTransition)
+		assert.Empty(t, f.sessions)
+	})
+
+	t.Run("unknown_session", func(t *testing.T) {
+		f := newLoadingFixture(types.PlanStatusPlanned, true, 1)
+		s := service.NewLoadingService(f.q, testLoadingCodec)
+		_, err := s.GetSession(ctx, f.planID.String(), uuid.New().String())
+		assert.ErrorIs(t, err, service.ErrLoadingSessionNotFound)
+	})
+}
+
+func TestLoadingService_OfflineSync(t *testing.T) {
+	ctx := authedPlannerCtx()
+
+	setup := func(t *testing.T) (*loadingFixture, service.LoadingService, *dto.OfflineBundle) {
+		f := newLoadingFixture(types.PlanStatusPlanned, true, 3)
+		s := service.NewLoadingService(f.q, testLoadingCodec)
+		_, err := s.StartSession(ctx, f.planID.String())
+		require.NoError(t, err)
+		bundle, err := s.OfflineBundle(ctx, f.planID.String())
+		require.NoError(t, err)
+		return f, s, bundle
+	}
+	event := func(barcode string, at int) dto.OfflineScanEvent {
+		return dto.OfflineScanEvent{
+			EventID:   uuid.New().String(),
+			Barcode:   barcode,
+			ScannedAt: time.Date(2026, 3, 1, 8, 0, at, 0, time.UTC),
+		}
+	}
+
+	t.Run("bundle", func(t *testing.T) {
+		f, _, bundle := setup(t)
+		assert.Equal(t, f.resultID.String(), bundle.Header.ResultID)
+		assert.NotEmpty(t, bundle.Header.SessionID)
+		assert.NotEmpty(t, bundle.Header.Signature)
+		assert.Len(t, bundle.Header.ContentDigest, 64)
+		require.Len(t, bundle.Content.Steps, 3)
+		assert.Equal(t, f.label(2), bundle.Content.Steps[1].Barcode)
+		assert.Equal(t, "Box 2", bundle.Content.Steps[1].ItemLabel)
+		assert.Empty(t, bundle.Content.LoadedSteps)
+	})
+
+	t.Run("events_replay_in_scan_order_and_are_idempotent", func(t *testing.T) {
+		f, s, bundle := setup(t)
+		// Uploaded out of order; step 2 was scanned after step 1.
+		req := dto.OfflineSyncRequest{
+			Bundle: bundle.Header,
+			Events: []dto.OfflineScanEvent{event(f.label(2), 2), event(f.label(1), 1), event(f.label(1), 3)},
+		}
+
+		report, err := s.SyncOfflineScans(ctx, f.planID.String(), req)
+		require.NoError(t, err)
+		assert.Equal(t, 3, report.Applied)
+		assert.Equal(t, 2, report.Matched)
+		assert.Equal(t, "DUPLICATE", report.Events[2].ScanStatus)
+		assert.Equal(t, 2, report.Session.LoadedSteps)
+		assert.Equal(t, 3, *report.Session.NextExpectedStep)
+		assert.Equal(t, time.Date(2026, 3, 1, 8, 0, 1, 0, time.UTC), f.scans[0].ScannedAt)
+
+		again, err := s.SyncOfflineScans(ctx, f.planID.String(), req)
+		require.NoError(t, err)
+		assert.Equal(t, 0, again.Applied)
+		assert.Equal(t, 3, again.AlreadySynced)
+		assert.Equal(t, "MATCHED", again.Events[0].ScanStatus)
+		assert.Len(t, f.scans, 3)
+	})
+
+	t.Run("recalculated_plan_conflicts", func(t *testing.T) {
+		f, s, bundle := setup(t)
+		f.resultID = uuid.New()
+
+		report, err := s.SyncOfflineScans(ctx, f.planID.String(), dto.OfflineSyncRequest{
+			Bundle: bundle.Header,
+			Events: []dto.OfflineScanEvent{event(f.label(1), 1)},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 1, report.Conflicts)
+		assert.Equal(t, "conflict", report.Events[0].Outcome)
+		assert.Contains(t, report.Events[0].Reason, "recalculated")
+		require.Len(t, f.scans, 1)
+		assert.Equal(t, "CONFLICT", f.scans[0].Status)
+		assert.Equal(t, bundle.Header.SessionID, f.scans[0].SessionID.String())
+		assert.Equal(t, report.Events[0].EventID, f.scans[0].ClientEventID.String())
+		assert.Empty(t, report.Session.LoadedSteps)
+
+		again, err := s.SyncOfflineScans(ctx, f.planID.String(), dto.OfflineSyncRequest{
+			Bundle: bundle.Header,
+			Events: []dto.OfflineScanEvent{{EventID: report.Events[0].EventID, Barcode: f.label(1)}},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 1, again.AlreadySynced)
+		assert.Equal(t, "CONFLICT", again.Events[0].ScanStatus)
+		assert.Len(t, f.scans, 1)
+	})
+
+	t.Run("bundle_before_loading_syncs_into_later_session", func(t *testing.T) {
+		f := newLoadingFixture(types.PlanStatusPlanned, true, 2)
+		s := service.NewLoadingService(f.q, testLoadingCodec)
+		bundle, err := s.OfflineBundle(ctx, f.planID.String())
+		require.NoError(t, err)
+		assert.Empty(t, bundle.Header.SessionID)
+		req := dto.OfflineSyncRequest{Bundle: bundle.Header, Events: []dto.OfflineScanEvent{event(f.label(1), 1)}}
+
+		_, err = s.SyncOfflineScans(ctx, f.planID.String(), req)
+		assert.ErrorIs(t, err, service.ErrLoadingSessionNotActive)
+		assert.Empty(t, f.scans)
+
+		session, err := s.StartSession(ctx, f.planID.String())
+		require.NoError(t, err)
+		report, err := s.SyncOfflineScans(ctx, f.planID.String(), req)
+		require.NoError(t, err)
+		assert.Equal(t, 1, report.Matched)
+		require.Len(t, f.scans, 1)
+		assert.Equal(t, session.SessionID, f.scans[0].SessionID.String())
+	})
+
+	t.Run("tampered_bundle", func(t *testing.T) {
+		f, s, bundle := setup(t)
+		header := bundle.Header
+		header.ResultID = uuid.New().String()
+
+		_, err := s.SyncOfflineScans(ctx, f.planID.String(), dto.OfflineSyncRequest{Bundle: header})
+		assert.ErrorIs(t, err, service.ErrInvalidBundle)
+
+		other := service.NewLoadingService(f.q, barcode.NewCodec("other-secret", true))
+		_, err = other.SyncOfflineScans(ctx, f.planID.String(), dto.OfflineSyncRequest{Bundle: bundle.Header})
+		assert.ErrorIs(t, err, service.ErrInvalidBundle)
+	})
+}
@@ -0,0 +1,342 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/ekastn/load-stuffing-calculator/internal/barcode"
+	"github.com/ekastn/load-stuffing-calculator/internal/dto"
+	"github.com/ekastn/load-stuffing-calculator/internal/store"
+	"github.com/ekastn/load-stuffing-calculator/internal/types"
+	"github.com/google/uuid"
+)
+
+// offlineBundleFormat is the version of the offline bundle layout.
+const offlineBundleFormat = 1
+
+const (
+	offlineApplied       = "applied"
+	offlineAlreadySynced = "already_synced"
+	offlineConflict      = "conflict"
+)
+
+var (
+	// ErrPlanNotCalculated is returned when a plan has no result to load.
+	ErrPlanNotCalculated = fmt.Errorf("plan has no calculation result")
+
+	// ErrInvalidBundle is returned for an offline upload whose bundle header
+	// was not issued by this server for the plan.
+	ErrInvalidBundle = fmt.Errorf("invalid offline bundle")
+)
+
+func (s *loadingService) OfflineBundle(ctx context.Context, planID string) (*dto.OfflineBundle, error) {
+	pID, err := uuid.Parse(planID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid plan id")
+	}
+	scope, err := resolvePlanScope(ctx, s.q, pID)
+	if err != nil {
+		return nil, err
+	}
+	plan := scope.plan
+
+	res, err := s.q.GetPlanResult(ctx, &pID)
+	if err != nil {
+		return nil, ErrPlanNotCalculated
+	}
+	placements, err := s.q.ListPlanPlacements(ctx, &res.ResultID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list placements: %w", err)
+	}
+	items, err := s.q.ListLoadItems(ctx, &pID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list items: %w", err)
+	}
+
+	details := make([]dto.PlanItemDetail, 0, len(items))
+	for _, it := range items {
+		details = append(details, *mapLoadItemToDetail(it))
+	}
+
+	content := dto.OfflineBundleContent{
+		PlanCode:    plan.PlanCode,
+		PlanStatus:  planStatusOf(plan).String(),
+		Container:   mapPlanContainerInfo(plan),
+		TotalSteps:  len(placements),
+		LoadedSteps: []int{},
+		Steps:       PlanBarcodes(s.codec, pID, mapPlacementDetails(placements), details),
+	}
+	header := dto.OfflineBundleHeader{
+		FormatVersion: offlineBundleFormat,
+		PlanID:        pID.String(),
+		ResultID:      res.ResultID.String(),
+		ResultVersion: int(res.Version),
+		IssuedAt:      time.Now().UTC().Truncate(time.Second),
+	}
+
+	if session, err := s.q.GetOpenLoadingSession(ctx, pID); err == nil {
+		header.SessionID = session.SessionID.String()
+		loaded, err := loadedSteps(ctx, s.q, session.SessionID)
+		if err != nil {
+			return nil, err
+		}
+		for step := range loaded {
+			content.LoadedSteps = append(content.LoadedSteps, step)
+		}
+		sort.Ints(content.LoadedSteps)
+	}
+
+	raw, err := json.Marshal(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode offline bundle: %w", err)
+	}
+	digest := sha256.Sum256(raw)
+	header.ContentDigest = hex.EncodeToString(digest[:])
+	header.Signature = s.signBundle(header)
+
+	return &dto.OfflineBundle{Header: header, Content: content}, nil
+}
+
+// SyncOfflineScans replays offline scans in the order they were made. Events
+// already uploaded are reported, not recorded again. When the plan was
+// recalculated or the session closed after the bundle was downloaded, the
+// scans no longer describe the load; they are recorded as conflicts and
+// reported as such. A bundle downloaded before loading started is synced
+// into the session opened since.
+func (s *loadingService) SyncOfflineScans(ctx context.Context, planID string, req dto.OfflineSyncRequest) (*dto.OfflineSyncReport, error) {
+	if req.Bundle.PlanID != planID || !hmac.Equal([]byte(req.Bundle.Signature), []byte(s.signBundle(req.Bundle))) {
+		return nil, fmt.Errorf("%w: signature does not match", ErrInvalidBundle)
+	}
+	scope, session, err := s.resolveBundleSession(ctx, planID, req.Bundle.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	actor, err := actorFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	scans, err := s.q.ListLoadingScans(ctx, session.SessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scans: %w", err)
+	}
+	synced := make(map[uuid.UUID]store.LoadingScan)
+	for _, scan := range scans {
+		if scan.ClientEventID != nil {
+			synced[*scan.ClientEventID] = scan
+		}
+	}
+
+	var conflict string
+	res, err := s.q.GetPlanResult(ctx, &session.PlanID)
+	switch {
+	case err != nil || res.ResultID.String() != req.Bundle.ResultID:
+		conflict = "the plan was recalculated after the bundle was downloaded"
+	case session.Status != types.LoadingSessionActive.String():
+		conflict = "the loading session is " + session.Status
+	}
+
+	loaded, err := loadedSteps(ctx, s.q, session.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	var placements []store.PlanPlacement
+	var gtins map[uuid.UUID]string
+	if conflict == "" && session.ResultID != nil {
+		placements, err = s.q.ListPlanPlacements(ctx, session.ResultID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list placements: %w", err)
+		}
+		gtins, err = s.itemGTINs(ctx, session.PlanID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	events := append([]dto.OfflineScanEvent(nil), req.Events...)
+	sort.SliceStable(events, func(a, b int) bool { return events[a].ScannedAt.Before(events[b].ScannedAt) })
+
+	report := &dto.OfflineSyncReport{Events: make([]dto.OfflineEventResult, 0, len(events))}
+	for _, ev := range events {
+		result := dto.OfflineEventResult{EventID: ev.EventID}
+		eventID, err := uuid.Parse(ev.EventID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid event id %q", ev.EventID)
+		}
+
+		if prior, ok := synced[eventID]; ok {
+			result.Outcome = offlineAlreadySynced
+			result.ScanStatus = prior.Status
+			result.StepNumber = intFromInt32(prior.StepNumber)
+			result.ExpectedStep = intFromInt32(prior.ExpectedStep)
+			report.AlreadySynced++
+			report.Events = append(report.Events, result)
+			continue
+		}
+		if conflict != "" {
+			scannedAt := ev.ScannedAt
+			scan, err := s.q.CreateLoadingScan(ctx, store.CreateLoadingScanParams{
+				SessionID:     session.SessionID,
+				Barcode:       ev.Barcode,
+				Status:        types.ScanConflict.String(),
+				OperatorID:    actor.id,
+				ClientEventID: &eventID,
+				ScannedAt:     &scannedAt,
+			})
+			if err != nil && !isUniqueViolation(err) {
+				return nil, fmt.Errorf("failed to record scan: %w", err)
+			}
+			if err == nil {
+				synced[eventID] = scan
+			}
+			result.Outcome = offlineConflict
+			result.ScanStatus = types.ScanConflict.String()
+			result.Reason = conflict
+			report.Conflicts++
+			report.Events = append(report.Events, result)
+			continue
+		}
+
+		expected := nextLoadingStep(int(session.TotalSteps), loaded)
+		status, step := classifyScan(s.codec, ev.Barcode, session.PlanID, placements, gtins, loaded, expected)
+		scannedAt := ev.ScannedAt
+		scan, err := s.insertScan(ctx, store.CreateLoadingScanParams{
+			SessionID:     session.SessionID,
+			Barcode:       ev.Barcode,
+			Status:        status.String(),
+			StepNumber:    int32FromInt(step),
+			ExpectedStep:  int32FromInt(expected),
+			OperatorID:    actor.id,
+			ClientEventID: &eventID,
+			ScannedAt:     &scannedAt,
+		})
+		if err != nil {
+			if !isUniqueViolation(err) {
+				return nil, fmt.Errorf("failed to record scan: %w", err)
+			}
+			// The same event was uploaded concurrently.
+			result.Outcome = offlineAlreadySynced
+			report.AlreadySynced++
+			report.Events = append(report.Events, result)
+			continue
+		}
+		synced[eventID] = scan
+
+		result.Outcome = offlineApplied
+		result.ScanStatus = scan.Status
+		result.StepNumber = step
+		result.ExpectedStep = expected
+		report.Applied++
+		if scan.Status == types.ScanMatched.String() {
+			loaded[*step] = true
+			report.Matched++
+		}
+		report.Events = append(report.Events, result)
+	}
+
+	report.Session = *mapLoadingSession(session, loaded, planStatusOf(scope.plan))
+	return report, nil
+}
+
+// resolveBundleSession loads the session an offline upload belongs to: the
+// one named in the bundle or, for a bundle downloaded before loading started,
+// the session of the plan open now.
+func (s *loadingService) resolveBundleSession(ctx context.Context, planID, sessionID string) (*planScope, store.LoadingSession, error) {
+	if sessionID != "" {
+		return s.resolveSession(ctx, planID, sessionID)
+	}
+	pID, err := uuid.Parse(planID)
+	if err != nil {
+		return nil, store.LoadingSession{}, fmt.Errorf("invalid plan id")
+	}
+	scope, err := resolvePlanScope(ctx, s.q, pID)
+	if err != nil {
+		return nil, store.LoadingSession{}, err
+	}
+	session, err := s.q.GetOpenLoadingSession(ctx, pID)
+	if err != nil {
+		return nil, store.LoadingSession{}, fmt.Errorf("%w: start loading before syncing offline scans", ErrLoadingSessionNotActive)
+	}
+	return scope, session, nil
+}
+
+// signBundle returns the signature of an offline bundle header. It covers
+// the content through its digest, so the header alone can be checked.
+func (s *loadingService) signBundle(h dto.OfflineBundleHeader) string {
+	return s.codec.Signature(
+		strconv.Itoa(h.FormatVersion),
+		h.PlanID,
+		h.ResultID,
+		strconv.Itoa(h.ResultVersion),
+		h.SessionID,
+		h.IssuedAt.UTC().Format(time.RFC3339),
+		h.ContentDigest,
+	)
+}
+
+// PlanBarcodes lists the loading steps of a calculated plan with their
+// labels, in step order. Placements without a step or an item are skipped.
+func PlanBarcodes(codec *barcode.Codec, planID uuid.UUID, placements []dto.PlacementDetail, items []dto.PlanItemDetail) []dto.BarcodeInfo {
+	byID := make(map[string]dto.PlanItemDetail, len(items))
+	for _, it := range items {
+		byID[it.ItemID] = it
+	}
+
+	out := make([]dto.BarcodeInfo, 0, len(placements))
+	for _, pl := range placements {
+		if pl.StepNumber == 0 {
+			continue
+		}
+		item, ok := byID[pl.ItemID]
+		if !ok {
+			continue
+		}
+		itemID, err := uuid.Parse(pl.ItemID)
+		if err != nil {
+			continue
+		}
+		out = append(out, dto.BarcodeInfo{
+			StepNumber: pl.StepNumber,
+			ItemID:     pl.ItemID,
+			ItemLabel:  getString(item.Label),
+			SKU:        getString(item.ProductSKU),
+			GTIN:       getString(item.GTIN),
+			Barcode:    codec.Generate(planID, pl.StepNumber, itemID),
+			Position: dto.Position{
+				X: pl.PositionX,
+				Y: pl.PositionY,
+				Z: pl.PositionZ,
+			},
+			Dimensions: dto.Dimensions{
+				Length: item.LengthMM,
+				Width:  item.WidthMM,
+				Height: item.HeightMM,
+			},
+		})
+	}
+	sort.Slice(out, func(a, b int) bool { return out[a].StepNumber < out[b].StepNumber })
+	return out
+}
+
+func intFromInt32(v *int32) *int {
+	if v == nil {
+		return nil
+	}
+	n := int(*v)
+	return &n
+}
+
+func int32FromInt(v *int) *int32 {
+	if v == nil {
+		return nil
+	}
+	n := int32(*v)
+	return &n
+}
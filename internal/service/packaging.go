@@ -0,0 +1,121 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/ekastn/load-stuffing-calculator/internal/dto"
+	"github.com/ekastn/load-stuffing-calculator/internal/store"
+)
+
+// ErrInvalidPackaging is returned for a packaging hierarchy with repeated
+// levels or a shipping unit it does not define, and for plan items counted
+// in a level their product does not have.
+var ErrInvalidPackaging = fmt.Errorf("invalid packaging")
+
+// Packaging levels from the smallest up.
+const (
+	unitEach   = "each"
+	unitInner  = "inner"
+	unitCase   = "case"
+	unitPallet = "pallet"
+)
+
+var packagingRank = map[string]int{unitEach: 0, unitInner: 1, unitCase: 2, unitPallet: 3}
+
+// encodePackaging orders levels from the smallest up, works out their each
+// quantities and encodes them for storage with the shipping unit, each when
+// nil. No levels is stored as NULL.
+func encodePackaging(levels []dto.PackagingLevel, shippingUnit *string) ([]byte, string, error) {
+	unit := unitEach
+	if shippingUnit != nil {
+		unit = *shippingUnit
+	}
+
+	sorted := append([]dto.PackagingLevel(nil), levels...)
+	sort.SliceStable(sorted, func(a, b int) bool {
+		return packagingRank[sorted[a].Level] < packagingRank[sorted[b].Level]
+	})
+	eaches := 1
+	defined := map[string]bool{unitEach: true}
+	for i := range sorted {
+		l := &sorted[i]
+		if defined[l.Level] {
+			return nil, "", fmt.Errorf("%w: level %q given twice", ErrInvalidPackaging, l.Level)
+		}
+		defined[l.Level] = true
+		eaches *= l.Contains
+		l.EachQuantity = eaches
+	}
+	if !defined[unit] {
+		return nil, "", fmt.Errorf("%w: shipping unit %q is not a packaging level", ErrInvalidPackaging, unit)
+	}
+
+	if len(sorted) == 0 {
+		return nil, unit, nil
+	}
+	raw, err := json.Marshal(sorted)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to encode packaging: %w", err)
+	}
+	return raw, unit, nil
+}
+
+// decodePackaging reads stored packaging levels; NULL or bad JSON is none.
+func decodePackaging(raw []byte) []dto.PackagingLevel {
+	if len(raw) == 0 {
+		return nil
+	}
+	var levels []dto.PackagingLevel
+	if err := json.Unmarshal(raw, &levels); err != nil {
+		return nil
+	}
+	return levels
+}
+
+// packagingUnit returns a level of the product's hierarchy, the each being
+// the product itself.
+func packagingUnit(p store.Product, level string) (dto.PackagingLevel, bool) {
+	if level == unitEach {
+		return dto.PackagingLevel{
+			Level:        unitEach,
+			Contains:     1,
+			LengthMM:     toFloat(p.LengthMm),
+			WidthMM:      toFloat(p.WidthMm),
+			HeightMM:     toFloat(p.HeightMm),
+			WeightKG:     toFloat(p.WeightKg),
+			EachQuantity: 1,
+		}, true
+	}
+	for _, l := range decodePackaging(p.Packaging) {
+		if l.Level == level {
+			return l, true
+		}
+	}
+	return dto.PackagingLevel{}, false
+}
+
+// shippingQuantity converts quantity units of the given level (the
+// shipping unit when nil) to whole shipping units of the product, rounding
+// up. It returns the shipping unit and the number of eaches ordered.
+func shippingQuantity(p store.Product, unit *string, quantity int) (dto.PackagingLevel, int, int, error) {
+	shipping := p.ShippingUnit
+	if shipping == "" {
+		shipping = unitEach
+	}
+	ship, ok := packagingUnit(p, shipping)
+	if !ok {
+		return dto.PackagingLevel{}, 0, 0, fmt.Errorf("%w: product has no %s level", ErrInvalidPackaging, shipping)
+	}
+	entered := ship
+	if unit != nil && *unit != shipping {
+		if entered, ok = packagingUnit(p, *unit); !ok {
+			return dto.PackagingLevel{}, 0, 0, fmt.Errorf("%w: product has no %s level", ErrInvalidPackaging, *unit)
+		}
+	}
+
+	eaches := quantity * entered.EachQuantity
+	units := (eaches + ship.EachQuantity - 1) / ship.EachQuantity
+	return ship, units, eaches, nil
+}
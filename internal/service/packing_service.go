@@ -18,6 +18,12 @@ import (
 // - We always send units="mm".
 // - dto.CalculatePlanRequest options (strategy/goal/gravity) are ignored.
 // - AllowRotation is ignored for now.
+// - Padding, wall clearance and item gap are applied here, so the service only
+//   ever sees the enlarged items and reduced container.
+
+// PackingServiceBackend identifies the packing microservice in cached results.
+// Bump it whenever the service's packing behaviour changes.
+const PackingServiceBackend = "py3dbp/1"
 
 type packingService struct {
 	gw gateway.PackingGateway
@@ -32,6 +38,9 @@ func (s *packingService) Pack(ctx context.Context, container packer.ContainerInp
 		return packer.PackingResult{}, fmt.Errorf("packing gateway is nil")
 	}
 
+	realContainer, realItems := container, items
+	container, items = packer.ApplyClearances(container, items)
+
 	// Server-internal defaults for py3dbp.
 	// Keep these stable unless we explicitly decide to expose them.
 	req := gateway.PackRequest{
@@ -147,6 +156,7 @@ func (s *packingService) Pack(ctx context.Context, container packer.ContainerInp
 		result.WeightUtilisationPct = (result.TotalWeightPackedKG / container.MaxWeight) * 100
 	}
 
+	packer.RemoveClearances(realContainer, realItems, &result)
 	return result, nil
 }
 
@@ -56,6 +56,49 @@ func TestPackingService_Pack_SortsByStepAndComputesRotationDims(t *testing.T) {
 	require.Equal(t, float64(33), res.PackedItems[1].RotatedHeight)
 }
 
+func TestPackingService_Pack_AppliesClearances(t *testing.T) {
+	var got gateway.PackRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(gateway.PackResponse{
+			Success: true,
+			Data: &gateway.PackDataOut{
+				Units: "mm",
+				Placements: []gateway.PackPlacementOut{
+					{ItemID: "A", PosX: 0, PosY: 0, PosZ: 0, Rotation: 0, StepNumber: 1},
+				},
+			},
+		})
+	}))
+	defer srv.Close()
+
+	p := NewPackingService(gateway.NewHTTPPackingGateway(srv.URL, 0))
+
+	container := packer.ContainerInput{ID: "c", Length: 1000, Width: 500, Height: 400, MaxWeight: 100, WallClearance: 20, ItemGap: 10}
+	res, err := p.Pack(context.Background(), container, []packer.ItemInput{
+		{ID: "A", Length: 100, Width: 50, Height: 40, Weight: 1, Quantity: 1, Padding: 5},
+	})
+	require.NoError(t, err)
+
+	// The service packs envelopes inside the reduced container.
+	require.Equal(t, float64(970), got.Container.Length)
+	require.Equal(t, float64(470), got.Container.Width)
+	require.Equal(t, float64(390), got.Container.Height)
+	require.Equal(t, float64(120), got.Items[0].Length)
+	require.Equal(t, float64(70), got.Items[0].Width)
+	require.Equal(t, float64(60), got.Items[0].Height)
+
+	// Placements report the true item and its real position.
+	require.Len(t, res.PackedItems, 1)
+	pi := res.PackedItems[0]
+	require.Equal(t, float64(100), pi.RotatedLength)
+	require.Equal(t, float64(50), pi.RotatedWidth)
+	require.Equal(t, float64(40), pi.RotatedHeight)
+	require.Equal(t, packer.Position{X: 25, Y: 25, Z: 0}, pi.Position)
+	require.InDelta(t, 0.0002, res.TotalVolumePackedM3, 1e-12)
+}
+
 func TestPackingService_Pack_NilGateway(t *testing.T) {
 	p := NewPackingService(nil)
 	_, err := p.Pack(context.Background(), packer.ContainerInput{}, []packer.ItemInput{})
@@ -0,0 +1,284 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ekastn/load-stuffing-calculator/internal/dto"
+	"github.com/ekastn/load-stuffing-calculator/internal/packer"
+	"github.com/ekastn/load-stuffing-calculator/internal/store"
+	"github.com/google/uuid"
+)
+
+// maxParallelCandidates bounds how many candidates of one comparison pack at once.
+const maxParallelCandidates = 4
+
+// Compared results are kept in memory so one can be adopted exactly as it
+// was shown. They expire after alternativeTTL; at most maxAlternatives are
+// kept.
+const (
+	alternativeTTL  = 30 * time.Minute
+	maxAlternatives = 512
+)
+
+var (
+	// ErrAlternativeNotFound is returned for an alternative that was never
+	// compared for the plan, has expired or was already adopted.
+	ErrAlternativeNotFound = fmt.Errorf("compared alternative not found")
+
+	// ErrStaleAlternative is returned when the plan changed after the
+	// comparison, so the compared layout no longer fits its items.
+	ErrStaleAlternative = fmt.Errorf("compared alternative is out of date")
+)
+
+// comparedAlternative is a packed candidate kept for adoption.
+type comparedAlternative struct {
+	planID  uuid.UUID
+	opts    dto.CalculatePlanRequest
+	inputs  []byte
+	res     packer.PackingResult
+	expires time.Time
+}
+
+type alternativeCache struct {
+	mu      sync.Mutex
+	entries map[uuid.UUID]comparedAlternative
+}
+
+// put keeps alt under id. When the cache is full, expired entries are dropped
+// first and then arbitrary ones.
+func (c *alternativeCache) put(id uuid.UUID, alt comparedAlternative) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = make(map[uuid.UUID]comparedAlternative)
+	}
+	if len(c.entries) >= maxAlternatives {
+		now := time.Now()
+		for k, e := range c.entries {
+			if !now.Before(e.expires) {
+				delete(c.entries, k)
+			}
+		}
+		for k := range c.entries {
+			if len(c.entries) < maxAlternatives {
+				break
+			}
+			delete(c.entries, k)
+		}
+	}
+	c.entries[id] = alt
+}
+
+func (c *alternativeCache) get(id uuid.UUID) (comparedAlternative, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	alt, ok := c.entries[id]
+	if ok && !time.Now().Before(alt.expires) {
+		delete(c.entries, id)
+		return comparedAlternative{}, false
+	}
+	return alt, ok
+}
+
+func (c *alternativeCache) remove(id uuid.UUID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, id)
+}
+
+// ComparePlan packs the plan with every candidate and returns them ranked.
+// Nothing is persisted; each packed candidate is kept for a while under its
+// alternative ID so AdoptAlternative can save it.
+func (s *planService) ComparePlan(ctx context.Context, planID string, req dto.ComparePlanRequest) (*dto.ComparePlanResponse, error) {
+	pID, err := uuid.Parse(planID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid plan id")
+	}
+
+	scope, err := s.resolvePlanScope(ctx, pID)
+	if err != nil {
+		return nil, fmt.Errorf("plan not found: %w", err)
+	}
+
+	items, err := s.q.ListLoadItems(ctx, &scope.plan.PlanID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list items: %w", err)
+	}
+
+	// Reject unknown backends up front rather than reporting them per candidate.
+	for _, c := range req.Candidates {
+		if _, err := s.packerFor(c.Backend); err != nil {
+			return nil, err
+		}
+	}
+
+	alts := make([]dto.PlanAlternative, len(req.Candidates))
+	results := make([]packer.PackingResult, len(req.Candidates))
+	sem := make(chan struct{}, maxParallelCandidates)
+	var wg sync.WaitGroup
+	for i, opts := range req.Candidates {
+		wg.Add(1)
+		go func(i int, opts dto.CalculatePlanRequest) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			alts[i], results[i] = s.runAlternative(ctx, scope, items, opts)
+		}(i, opts)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	expires := time.Now().Add(alternativeTTL)
+	for i := range alts {
+		if alts[i].Error != "" {
+			continue
+		}
+		inputs, err := encodeResultInputs(scope.plan, items, alts[i].Options)
+		if err != nil {
+			return nil, err
+		}
+		id := uuid.New()
+		s.alternatives.put(id, comparedAlternative{
+			planID:  pID,
+			opts:    alts[i].Options,
+			inputs:  inputs,
+			res:     results[i],
+			expires: expires,
+		})
+		alts[i].AlternativeID = id.String()
+	}
+
+	rankAlternatives(alts)
+	return &dto.ComparePlanResponse{PlanID: pID.String(), Alternatives: alts}, nil
+}
+
+// AdoptAlternative saves a compared alternative as the plan's new active
+// result, with exactly the placements the comparison returned.
+func (s *planService) AdoptAlternative(ctx context.Context, planID, alternativeID string) (*dto.CalculationResult, error) {
+	pID, err := uuid.Parse(planID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid plan id")
+	}
+	altID, err := uuid.Parse(alternativeID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid alternative id")
+	}
+
+	scope, err := s.resolvePlanScope(ctx, pID)
+	if err != nil {
+		return nil, fmt.Errorf("plan not found: %w", err)
+	}
+	if from := planStatusOf(scope.plan); !from.Calculable() {
+		return nil, fmt.Errorf("%w: a %s plan cannot be recalculated", ErrInvalidStatusTransition, from)
+	}
+
+	alt, ok := s.alternatives.get(altID)
+	if !ok || alt.planID != pID {
+		return nil, ErrAlternativeNotFound
+	}
+
+	items, err := s.q.ListLoadItems(ctx, &pID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list items: %w", err)
+	}
+	inputs, err := encodeResultInputs(scope.plan, items, alt.opts)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(inputs, alt.inputs) {
+		return nil, fmt.Errorf("%w: the container or items changed after the comparison", ErrStaleAlternative)
+	}
+
+	var saved store.PlanResult
+	err = inTx(ctx, s.q, func(q store.Querier) error {
+		var err error
+		saved, err = saveResultVersion(ctx, q, scope, alt.res, alt.inputs)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.alternatives.remove(altID)
+
+	contInput, itemInputs := buildPackInputs(scope.plan, items, alt.opts)
+	return calculationResult(planID, saved, contInput, itemInputs, alt.res, alt.opts), nil
+}
+
+func (s *planService) runAlternative(ctx context.Context, scope *planScope, items []store.LoadItem, opts dto.CalculatePlanRequest) (dto.PlanAlternative, packer.PackingResult) {
+	alt := dto.PlanAlternative{Options: opts}
+
+	p, _ := s.packerFor(opts.Backend)
+	contInput, itemInputs := buildPackInputs(scope.plan, items, opts)
+
+	res, err := packer.PackCompartments(ctx, p, contInput, itemInputs)
+	if err != nil {
+		alt.Error = err.Error()
+		return alt, res
+	}
+
+	metrics := packer.ComputeMetrics(contInput, itemInputs, res)
+
+	unfit := 0
+	for _, u := range res.UnfitItems {
+		unfit += u.Quantity
+	}
+
+	alt.Algorithm = res.Algorithm
+	alt.IsFeasible = res.IsFeasible
+	alt.PackedCount = res.TotalPackedItems
+	alt.UnfitCount = unfit
+	alt.VolumeUtilization = res.VolumeUtilisationPct
+	alt.WeightUtilization = res.WeightUtilisationPct
+	alt.CogOffsetPct = metrics.CogOffsetPct
+	alt.StabilityPct = metrics.SupportRatio * 100
+	alt.DurationMs = res.DurationMs
+	alt.CacheHit = res.CacheHit
+
+	for i, pi := range res.PackedItems {
+		alt.Placements = append(alt.Placements, dto.PlacementDetail{
+			ItemID:     pi.ItemID,
+			PositionX:  pi.Position.X,
+			PositionY:  pi.Position.Y,
+			PositionZ:  pi.Position.Z,
+			Rotation:   pi.RotationType,
+			StepNumber: i + 1,
+		})
+	}
+	return alt, res
+}
+
+// rankAlternatives orders candidates: failed runs last, then fewest unfit
+// items, highest utilisation, best stability, smallest CoG offset, fastest.
+func rankAlternatives(alts []dto.PlanAlternative) {
+	sort.SliceStable(alts, func(i, j int) bool {
+		a, b := alts[i], alts[j]
+		if (a.Error == "") != (b.Error == "") {
+			return a.Error == ""
+		}
+		if a.UnfitCount != b.UnfitCount {
+			return a.UnfitCount < b.UnfitCount
+		}
+		if a.VolumeUtilization != b.VolumeUtilization {
+			return a.VolumeUtilization > b.VolumeUtilization
+		}
+		if a.StabilityPct != b.StabilityPct {
+			return a.StabilityPct > b.StabilityPct
+		}
+		if a.CogOffsetPct != b.CogOffsetPct {
+			return a.CogOffsetPct < b.CogOffsetPct
+		}
+		return a.DurationMs < b.DurationMs
+	})
+	for i := range alts {
+		alts[i].Rank = i + 1
+	}
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"github.com/ekastn/load-stuffing-calculator/internal/dto"
+	"github.com/ekastn/load-stuffing-calculator/internal/packer"
+)
+
+// planFloorLoad checks the packed load against the container's floor and
+// line load limits.
+func planFloorLoad(container packer.ContainerInput, packed []packer.PackedItem, items []packer.ItemInput) *dto.FloorLoadReport {
+	r := packer.CheckFloorLoad(container, packed, items)
+
+	out := &dto.FloorLoadReport{
+		MaxPressureKgM2: r.MaxPressureKgM2,
+		MaxLineLoadKgM:  r.MaxLineLoadKgM,
+		Violations:      make([]dto.FloorLoadViolation, 0, len(r.Violations)),
+		LineViolations:  make([]dto.LineLoadViolation, 0, len(r.LineViolations)),
+		OK:              r.OK,
+	}
+	if container.FloorLoadLimit > 0 {
+		limit := container.FloorLoadLimit
+		out.LimitKgM2 = &limit
+	}
+	if container.LineLoadLimit > 0 {
+		limit := container.LineLoadLimit
+		out.LimitKgM = &limit
+	}
+	for _, v := range r.Violations {
+		out.Violations = append(out.Violations, dto.FloorLoadViolation{
+			ItemID:              v.ItemID,
+			Label:               v.Label,
+			PositionX:           v.Position.X,
+			PositionY:           v.Position.Y,
+			LengthMM:            v.Length,
+			WidthMM:             v.Width,
+			LoadKG:              v.LoadKG,
+			PressureKgM2:        v.PressureKgM2,
+			RequiredAreaM2:      v.RequiredAreaM2,
+			RecommendedLengthMM: v.RecommendedLength,
+			RecommendedWidthMM:  v.RecommendedWidth,
+		})
+	}
+	for _, v := range r.LineViolations {
+		out.LineViolations = append(out.LineViolations, dto.LineLoadViolation{
+			StartMM:         v.Start,
+			EndMM:           v.End,
+			LoadKG:          v.LoadKG,
+			RequiredLengthM: v.RequiredLengthM,
+		})
+	}
+	return out
+}
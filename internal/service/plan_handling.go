@@ -0,0 +1,35 @@
+package service
+
+import (
+	"github.com/ekastn/load-stuffing-calculator/internal/dto"
+	"github.com/ekastn/load-stuffing-calculator/internal/packer"
+)
+
+// planHandling checks the packed load against the items' handling
+// attributes.
+func planHandling(packed []packer.PackedItem, items []packer.ItemInput) *dto.HandlingReport {
+	r := packer.CheckHandling(packed, items)
+
+	out := &dto.HandlingReport{
+		Violations:    make([]dto.HandlingViolation, 0, len(r.Violations)),
+		HazmatClasses: r.HazmatClasses,
+		OK:            r.OK,
+	}
+	for _, v := range r.Violations {
+		hv := dto.HandlingViolation{
+			ItemID:    v.ItemID,
+			Label:     v.Label,
+			PositionX: v.Position.X,
+			PositionY: v.Position.Y,
+			PositionZ: v.Position.Z,
+			Rule:      v.Rule,
+		}
+		if v.Rule == packer.HandlingMaxStackLoad {
+			load, limit := v.LoadKG, v.LimitKG
+			hv.LoadKG = &load
+			hv.LimitKG = &limit
+		}
+		out.Violations = append(out.Violations, hv)
+	}
+	return out
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ekastn/load-stuffing-calculator/internal/dto"
+	"github.com/ekastn/load-stuffing-calculator/internal/gs1"
+	"github.com/ekastn/load-stuffing-calculator/internal/store"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrGS1NotConfigured is returned when issuing SSCCs for a plan whose
+	// workspace has no GS1 company prefix.
+	ErrGS1NotConfigured = fmt.Errorf("workspace has no GS1 company prefix")
+
+	// ErrSSCCExhausted is returned when the company prefix has no serial
+	// references left for the units to label.
+	ErrSSCCExhausted = gs1.ErrSerialExhausted
+)
+
+// AssignHandlingUnits gives every placed unit of the plan's active result
+// that has none an SSCC from the workspace's GS1 numbering and returns all
+// units of the result. Serials are reserved up front, so a unit that loses a
+// race with a concurrent call skips its serial rather than reusing it.
+func (s *planService) AssignHandlingUnits(ctx context.Context, planID string) ([]dto.HandlingUnit, error) {
+	pID, err := uuid.Parse(planID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid plan id")
+	}
+	scope, err := resolvePlanScope(ctx, s.q, pID)
+	if err != nil {
+		return nil, err
+	}
+	if scope.plan.WorkspaceID == nil {
+		return nil, ErrGS1NotConfigured
+	}
+	workspaceID := *scope.plan.WorkspaceID
+	settings, err := s.q.GetWorkspaceGS1(ctx, workspaceID)
+	if err != nil {
+		return nil, ErrGS1NotConfigured
+	}
+
+	res, err := s.q.GetPlanResult(ctx, &pID)
+	if err != nil {
+		return nil, ErrPlanNotCalculated
+	}
+	placements, err := s.q.ListPlanPlacements(ctx, &res.ResultID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list placements: %w", err)
+	}
+	units, err := s.q.ListHandlingUnits(ctx, res.ResultID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list handling units: %w", err)
+	}
+
+	assigned := make(map[uuid.UUID]bool, len(units))
+	for _, u := range units {
+		assigned[u.PlacementID] = true
+	}
+	var missing []store.PlanPlacement
+	for _, pl := range placements {
+		if pl.StepNumber == 0 || pl.ItemID == nil || assigned[pl.PlacementID] {
+			continue
+		}
+		missing = append(missing, pl)
+	}
+
+	if len(missing) > 0 {
+		count := int64(len(missing))
+		if gs1.MaxSerial(settings.CompanyPrefix)-settings.LastSerial < count {
+			return nil, fmt.Errorf("%w: %d units to label", ErrSSCCExhausted, count)
+		}
+		reserved, err := s.q.ReserveSSCCSerials(ctx, store.ReserveSSCCSerialsParams{Count: count, WorkspaceID: workspaceID})
+		if err != nil {
+			return nil, fmt.Errorf("failed to reserve SSCC serials: %w", err)
+		}
+		first := reserved.LastSerial - count + 1
+		for i, pl := range missing {
+			sscc, err := gs1.SSCC(int(reserved.ExtensionDigit), reserved.CompanyPrefix, first+int64(i))
+			if err != nil {
+				return nil, fmt.Errorf("failed to build SSCC: %w", err)
+			}
+			_, err = s.q.CreateHandlingUnit(ctx, store.CreateHandlingUnitParams{
+				Sscc:        sscc,
+				WorkspaceID: workspaceID,
+				PlanID:      pID,
+				ResultID:    res.ResultID,
+				PlacementID: pl.PlacementID,
+				StepNumber:  pl.StepNumber,
+			})
+			if err != nil && !isUniqueViolation(err) {
+				return nil, fmt.Errorf("failed to create handling unit: %w", err)
+			}
+		}
+		units, err = s.q.ListHandlingUnits(ctx, res.ResultID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list handling units: %w", err)
+		}
+	}
+
+	return s.handlingUnitDetails(ctx, pID, units, placements)
+}
+
+// ListHandlingUnits returns the SSCCs issued for the plan's active result.
+func (s *planService) ListHandlingUnits(ctx context.Context, planID string) ([]dto.HandlingUnit, error) {
+	pID, err := uuid.Parse(planID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid plan id")
+	}
+	if _, err := resolvePlanScope(ctx, s.q, pID); err != nil {
+		return nil, err
+	}
+
+	res, err := s.q.GetPlanResult(ctx, &pID)
+	if err != nil {
+		return nil, ErrPlanNotCalculated
+	}
+	units, err := s.q.ListHandlingUnits(ctx, res.ResultID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list handling units: %w", err)
+	}
+	placements, err := s.q.ListPlanPlacements(ctx, &res.ResultID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list placements: %w", err)
+	}
+	return s.handlingUnitDetails(ctx, pID, units, placements)
+}
+
+// handlingUnitDetails describes units with the items placed in them, in step
+// order.
+func (s *planService) handlingUnitDetails(ctx context.Context, planID uuid.UUID, units []store.HandlingUnit, placements []store.PlanPlacement) ([]dto.HandlingUnit, error) {
+	items, err := s.q.ListLoadItems(ctx, &planID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list items: %w", err)
+	}
+	byID := make(map[uuid.UUID]dto.PlanItemDetail, len(items))
+	for _, it := range items {
+		byID[it.ItemID] = *mapLoadItemToDetail(it)
+	}
+	itemOf := make(map[uuid.UUID]uuid.UUID, len(placements))
+	for _, pl := range placements {
+		if pl.ItemID != nil {
+			itemOf[pl.PlacementID] = *pl.ItemID
+		}
+	}
+
+	out := make([]dto.HandlingUnit, 0, len(units))
+	for _, u := range units {
+		itemID := itemOf[u.PlacementID]
+		item := byID[itemID]
+		out = append(out, dto.HandlingUnit{
+			SSCC:          u.Sscc,
+			ElementString: gs1.ElementString(gs1.AISSCC, u.Sscc),
+			StepNumber:    int(u.StepNumber),
+			PlacementID:   u.PlacementID.String(),
+			ItemID:        itemID.String(),
+			ItemLabel:     getString(item.Label),
+			SKU:           getString(item.ProductSKU),
+			GTIN:          getString(item.GTIN),
+			CreatedAt:     u.CreatedAt,
+		})
+	}
+	return out, nil
+}
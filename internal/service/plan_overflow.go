@@ -0,0 +1,378 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ekastn/load-stuffing-calculator/internal/dto"
+	"github.com/ekastn/load-stuffing-calculator/internal/packer"
+	"github.com/ekastn/load-stuffing-calculator/internal/store"
+	"github.com/ekastn/load-stuffing-calculator/internal/types"
+	"github.com/google/uuid"
+)
+
+var ErrNothingToOverflow = fmt.Errorf("plan has no unfit items")
+var ErrNoSuitableContainer = fmt.Errorf("no saved container holds the unfit items")
+var ErrOverflowExists = fmt.Errorf("plan already has an overflow plan")
+
+// CreateOverflowPlan moves the units a calculated plan could not place into
+// a new plan. Both plans join the source's shipment group, which is keyed by
+// the ID of the first plan of the shipment.
+func (s *planService) CreateOverflowPlan(ctx context.Context, planID string, req dto.CreateOverflowPlanRequest) (*dto.OverflowPlanResponse, error) {
+	pID, err := uuid.Parse(planID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid plan id")
+	}
+
+	scope, err := s.resolvePlanScope(ctx, pID)
+	if err != nil {
+		return nil, err
+	}
+	source := scope.plan
+
+	snap, err := s.scenarioSnapshot(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+	if !snap.Calculated || len(snap.UnfitItems) == 0 {
+		return nil, ErrNothingToOverflow
+	}
+
+	items, err := s.q.ListLoadItems(ctx, &source.PlanID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list items: %w", err)
+	}
+	missing := make(map[string]int, len(snap.UnfitItems))
+	for _, u := range snap.UnfitItems {
+		missing[u.ItemID] = u.Quantity
+	}
+	var leftovers []store.LoadItem
+	for _, it := range items {
+		if q := missing[it.ItemID.String()]; q > 0 {
+			it.Quantity = int32(q)
+			leftovers = append(leftovers, it)
+		}
+	}
+
+	cont := containerOf(source)
+	suggested := false
+	switch {
+	case req.Container != nil:
+		cont, err = s.overrideContainer(ctx, source.WorkspaceID, cont, req.Container)
+		if err != nil {
+			return nil, err
+		}
+	case req.SuggestContainer:
+		cont, err = s.suggestContainer(ctx, source, leftovers)
+		if err != nil {
+			return nil, err
+		}
+		suggested = true
+	}
+
+	actor, err := actorFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkTrialLimit(ctx, actor); err != nil {
+		return nil, err
+	}
+	createdByType := "user"
+	if actor.role == types.RoleTrial.String() {
+		createdByType = "guest"
+	}
+
+	groupID := source.PlanID
+	if source.ShipmentGroupID != nil {
+		groupID = *source.ShipmentGroupID
+	}
+
+	status := types.PlanStatusDraft.String()
+	var plan store.LoadPlan
+	err = inTx(ctx, s.q, func(q store.Querier) error {
+		// The group's first plan is locked so concurrent overflows of the
+		// same shipment are numbered one after the other.
+		if _, err := q.LockLoadPlan(ctx, groupID); err != nil {
+			return fmt.Errorf("failed to lock shipment: %w", err)
+		}
+		if source.ShipmentGroupID == nil {
+			err := q.SetPlanShipmentGroup(ctx, store.SetPlanShipmentGroupParams{PlanID: source.PlanID, ShipmentGroupID: &groupID})
+			if err != nil {
+				return fmt.Errorf("failed to group plan: %w", err)
+			}
+		}
+
+		group, err := q.ListShipmentGroupPlans(ctx, &groupID)
+		if err != nil {
+			return fmt.Errorf("failed to list shipment plans: %w", err)
+		}
+		for _, p := range group {
+			if p.OverflowOfPlanID != nil && *p.OverflowOfPlanID == source.PlanID {
+				return fmt.Errorf("%w: %s", ErrOverflowExists, p.PlanCode)
+			}
+		}
+		rootCode := source.PlanCode
+		if len(group) > 0 {
+			rootCode = group[0].PlanCode
+		}
+		planCode := fmt.Sprintf("%s-%d", rootCode, max(len(group), 1)+1)
+
+		plan, err = q.CreateOverflowPlan(ctx, store.CreateOverflowPlanParams{
+			OverflowOfPlanID: &source.PlanID,
+			ShipmentGroupID:  &groupID,
+			WorkspaceID:      source.WorkspaceID,
+			PlanCode:         planCode,
+			Status:           &status,
+			ContLabel:        cont.label,
+			LengthMm:         cont.length,
+			WidthMm:          cont.width,
+			HeightMm:         cont.height,
+			MaxWeightKg:      cont.maxWeight,
+			CreatedByType:    createdByType,
+			CreatedByID:      actor.id,
+			WallClearanceMm:  cont.wallClearance,
+			ItemGapMm:        cont.itemGap,
+
+			LashingPointsPerSide:    cont.lashingPoints,
+			LashingPointCapacityDan: cont.lashingCapacity,
+			Compartments:            cont.compartments,
+			FloorLoadKgM2:           cont.floorLoad,
+			LineLoadKgM:             cont.lineLoad,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create overflow plan: %w", err)
+		}
+
+		for _, it := range leftovers {
+			var ordered *int32
+			if it.EachesPerUnit != nil {
+				eaches := it.Quantity * *it.EachesPerUnit
+				ordered = &eaches
+			}
+
+			_, err := q.AddLoadItem(ctx, store.AddLoadItemParams{
+				PlanID:        &plan.PlanID,
+				ItemLabel:     it.ItemLabel,
+				LengthMm:      it.LengthMm,
+				WidthMm:       it.WidthMm,
+				HeightMm:      it.HeightMm,
+				WeightKg:      it.WeightKg,
+				Quantity:      it.Quantity,
+				AllowRotation: it.AllowRotation,
+				ColorHex:      it.ColorHex,
+				PaddingMm:     it.PaddingMm,
+				Priority:      it.Priority,
+				MustShip:      it.MustShip,
+
+				FrictionCoefficient: it.FrictionCoefficient,
+				TemperatureClass:    it.TemperatureClass,
+				Gtin:                it.Gtin,
+				ProductID:           it.ProductID,
+				ProductSku:          it.ProductSku,
+				ProductOverridden:   it.ProductOverridden,
+
+				Fragile:        it.Fragile,
+				ThisSideUp:     it.ThisSideUp,
+				Stackable:      it.Stackable,
+				MaxStackLoadKg: it.MaxStackLoadKg,
+				HazmatClass:    it.HazmatClass,
+				PackagingType:  it.PackagingType,
+
+				Unit:          it.Unit,
+				EachesPerUnit: it.EachesPerUnit,
+				OrderedEaches: ordered,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to copy item: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.OverflowPlanResponse{
+		PlanID:             plan.PlanID.String(),
+		PlanCode:           plan.PlanCode,
+		OverflowOfPlanID:   source.PlanID.String(),
+		ShipmentGroupID:    groupID.String(),
+		ContainerSuggested: suggested,
+		Items:              make([]dto.UnfitItemInfo, 0, len(leftovers)),
+	}
+	for _, it := range leftovers {
+		resp.Items = append(resp.Items, dto.UnfitItemInfo{
+			ItemID:   it.ItemID.String(),
+			Label:    it.ItemLabel,
+			Quantity: int(it.Quantity),
+			MustShip: it.MustShip,
+		})
+		resp.TotalItems += int(it.Quantity)
+	}
+
+	contL, contW, contH := toFloat(plan.LengthMm), toFloat(plan.WidthMm), toFloat(plan.HeightMm)
+	resp.Container = dto.PlanContainerInfo{
+		Name:        plan.ContLabel,
+		LengthMM:    contL,
+		WidthMM:     contW,
+		HeightMM:    contH,
+		MaxWeightKG: toFloat(plan.MaxWeightKg),
+		VolumeM3:    contL * contW * contH / 1_000_000_000.0,
+
+		WallClearanceMM: toFloat(plan.WallClearanceMm),
+		ItemGapMM:       toFloat(plan.ItemGapMm),
+	}
+
+	autoCalc := req.AutoCalculate == nil || *req.AutoCalculate
+	if !autoCalc {
+		resp.Status = status
+		return resp, nil
+	}
+
+	var opts dto.CalculatePlanRequest
+	if req.Options != nil {
+		opts = *req.Options
+	}
+	calcRes, err := s.CalculatePlan(ctx, plan.PlanID.String(), opts)
+	if err != nil {
+		s.markPlanFailed(ctx, plan.PlanID, types.PlanStatusDraft)
+		resp.Status = types.PlanStatusFailed.String()
+		return resp, nil
+	}
+	resp.Status = calcRes.Status
+	resp.Calculation = calcRes
+	return resp, nil
+}
+
+// suggestContainer picks the smallest saved container that every leftover
+// unit fits into and whose weight and volume limits cover the whole load.
+func (s *planService) suggestContainer(ctx context.Context, source store.LoadPlan, leftovers []store.LoadItem) (planContainer, error) {
+	containers, err := s.q.ListContainers(ctx, store.ListContainersParams{WorkspaceID: source.WorkspaceID, Limit: 100})
+	if err != nil {
+		return planContainer{}, fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	var best *store.Container
+	bestVol := 0.0
+	for i := range containers {
+		c := containers[i]
+		candidate := source
+		candidate.LengthMm = c.InnerLengthMm
+		candidate.WidthMm = c.InnerWidthMm
+		candidate.HeightMm = c.InnerHeightMm
+		candidate.MaxWeightKg = c.MaxWeightKg
+		candidate.Compartments = c.Compartments
+
+		contInput, itemInputs := buildPackInputs(candidate, leftovers, dto.CalculatePlanRequest{})
+		report := packer.Preflight(contInput, itemInputs)
+		if len(report.Issues) > 0 || report.Overweight || report.OverVolume {
+			continue
+		}
+		vol := contInput.Length * contInput.Width * contInput.Height
+		if best == nil || vol < bestVol {
+			best, bestVol = &containers[i], vol
+		}
+	}
+	if best == nil {
+		return planContainer{}, ErrNoSuitableContainer
+	}
+
+	cont := containerOf(source)
+	cont.label = &best.Name
+	cont.length = best.InnerLengthMm
+	cont.width = best.InnerWidthMm
+	cont.height = best.InnerHeightMm
+	cont.maxWeight = best.MaxWeightKg
+	cont.lashingPoints = best.LashingPointsPerSide
+	cont.lashingCapacity = best.LashingPointCapacityDan
+	cont.floorLoad = best.FloorLoadKgM2
+	cont.lineLoad = best.LineLoadKgM
+	cont.compartments = best.Compartments
+	return cont, nil
+}
+
+// GetShipmentGroup lists the plans of the shipment a plan belongs to. A plan
+// that was never split is a shipment of its own.
+func (s *planService) GetShipmentGroup(ctx context.Context, planID string) (*dto.ShipmentGroupResponse, error) {
+	pID, err := uuid.Parse(planID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid plan id")
+	}
+
+	scope, err := s.resolvePlanScope(ctx, pID)
+	if err != nil {
+		return nil, err
+	}
+	source := scope.plan
+
+	groupID := source.PlanID
+	plans := []store.LoadPlan{source}
+	if source.ShipmentGroupID != nil {
+		groupID = *source.ShipmentGroupID
+		all, err := s.q.ListShipmentGroupPlans(ctx, &groupID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list shipment plans: %w", err)
+		}
+		plans = plans[:0]
+		for _, p := range all {
+			if sameWorkspace(p.WorkspaceID, source.WorkspaceID) {
+				plans = append(plans, p)
+			}
+		}
+	}
+
+	overflowed := make(map[uuid.UUID]bool, len(plans))
+	for _, p := range plans {
+		if p.OverflowOfPlanID != nil {
+			overflowed[*p.OverflowOfPlanID] = true
+		}
+	}
+
+	resp := &dto.ShipmentGroupResponse{
+		ShipmentGroupID: groupID.String(),
+		Plans:           make([]dto.ShipmentPlan, 0, len(plans)),
+	}
+	for _, p := range plans {
+		snap, err := s.scenarioSnapshot(ctx, p)
+		if err != nil {
+			return nil, err
+		}
+		sp := dto.ShipmentPlan{
+			PlanID:      snap.PlanID,
+			PlanCode:    p.PlanCode,
+			Status:      snap.Status,
+			Calculated:  snap.Calculated,
+			Container:   snap.Container,
+			Stats:       snap.Stats,
+			PackedItems: snap.PackedItems,
+			UnfitItems:  snap.UnfitItems,
+		}
+		if p.OverflowOfPlanID != nil {
+			id := p.OverflowOfPlanID.String()
+			sp.OverflowOfPlanID = &id
+		} else {
+			resp.TotalItems += snap.Stats.TotalItems
+		}
+		resp.Plans = append(resp.Plans, sp)
+		resp.PackedItems += snap.PackedItems
+
+		if overflowed[p.PlanID] {
+			continue
+		}
+		if !snap.Calculated {
+			resp.OutstandingUnits += snap.Stats.TotalItems
+			continue
+		}
+		for _, u := range snap.UnfitItems {
+			resp.OutstandingUnits += u.Quantity
+		}
+	}
+	return resp, nil
+}
+
+func sameWorkspace(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
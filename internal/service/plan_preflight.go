@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ekastn/load-stuffing-calculator/internal/dto"
+	"github.com/ekastn/load-stuffing-calculator/internal/packer"
+	"github.com/google/uuid"
+)
+
+// PreflightPlan checks a plan for items that can never be placed and for
+// totals over the container's limits. It does not pack.
+func (s *planService) PreflightPlan(ctx context.Context, planID string) (*dto.PreflightResponse, error) {
+	pID, err := uuid.Parse(planID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid plan id")
+	}
+
+	scope, err := s.resolvePlanScope(ctx, pID)
+	if err != nil {
+		return nil, fmt.Errorf("plan not found: %w", err)
+	}
+
+	items, err := s.q.ListLoadItems(ctx, &scope.plan.PlanID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list items: %w", err)
+	}
+
+	contInput, itemInputs := buildPackInputs(scope.plan, items, dto.CalculatePlanRequest{})
+	report := packer.Preflight(contInput, itemInputs)
+
+	resp := &dto.PreflightResponse{
+		PlanID:            pID.String(),
+		TotalWeightKG:     report.TotalWeightKG,
+		MaxWeightKG:       contInput.MaxWeight,
+		TotalVolumeM3:     report.TotalVolumeM3,
+		ContainerVolumeM3: contInput.Length * contInput.Width * contInput.Height / 1_000_000_000.0,
+		Overweight:        report.Overweight,
+		OverVolume:        report.OverVolume,
+		Issues:            make([]dto.PreflightIssue, 0, len(report.Issues)),
+	}
+	for _, is := range report.Issues {
+		issue := dto.PreflightIssue{
+			ItemID:   is.ItemID,
+			Quantity: is.Quantity,
+			Code:     is.Code,
+			Message:  is.Message,
+		}
+		if is.Label != "" {
+			label := is.Label
+			issue.Label = &label
+		}
+		resp.Issues = append(resp.Issues, issue)
+	}
+	resp.OK = len(resp.Issues) == 0 && !resp.Overweight && !resp.OverVolume
+	return resp, nil
+}
@@ -0,0 +1,254 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ekastn/load-stuffing-calculator/internal/dto"
+	"github.com/ekastn/load-stuffing-calculator/internal/store"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrItemProductNotFound is returned for a plan item referencing a
+	// product the plan's workspace cannot see.
+	ErrItemProductNotFound = fmt.Errorf("item product not found")
+
+	// ErrItemDimensionsRequired is returned for an item with neither a
+	// catalog product nor its own dimensions and weight.
+	ErrItemDimensionsRequired = fmt.Errorf("item needs dimensions and weight or a catalog product")
+)
+
+// lookupItemProduct finds the catalog product an item references, by ID
+// before SKU, among the products of workspaceID and the global presets. It
+// returns nil for an item without a product. A SKU missing from the catalog
+// is kept on the item for reporting, so an item may carry one while filling
+// its own dimensions.
+func lookupItemProduct(ctx context.Context, q store.Querier, workspaceID *uuid.UUID, item dto.CreatePlanItem) (*store.Product, error) {
+	if item.ProductID != nil {
+		id, err := uuid.Parse(*item.ProductID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid product_id", ErrItemProductNotFound)
+		}
+		product, err := q.GetProduct(ctx, store.GetProductParams{ProductID: id, WorkspaceID: workspaceID})
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrItemProductNotFound, id)
+		}
+		if item.ProductSKU != nil && getString(product.Sku) != *item.ProductSKU {
+			return nil, fmt.Errorf("%w: product %s does not have SKU %q", ErrItemProductNotFound, id, *item.ProductSKU)
+		}
+		return &product, nil
+	}
+	if item.ProductSKU != nil && *item.ProductSKU != "" {
+		product, err := q.GetProductBySku(ctx, store.GetProductBySkuParams{Sku: item.ProductSKU, WorkspaceID: workspaceID})
+		if err == nil {
+			return &product, nil
+		}
+		if !itemHasOwnDimensions(item) {
+			return nil, fmt.Errorf("%w: SKU %q", ErrItemProductNotFound, *item.ProductSKU)
+		}
+	}
+	return nil, nil
+}
+
+func itemHasOwnDimensions(item dto.CreatePlanItem) bool {
+	return item.LengthMM > 0 && item.WidthMM > 0 && item.HeightMM > 0 && item.WeightKG > 0
+}
+
+// planItemParams builds the stored item of a plan line. An item of a
+// product is counted in whole shipping units, and fields the line leaves out
+// are taken from the shipping unit and the product; a given value that
+// differs from those marks the item as overridden. PlanID is left to the
+// caller.
+func planItemParams(item dto.CreatePlanItem, product *store.Product) (store.AddLoadItemParams, error) {
+	gtin, err := normalizeGTIN(item.GTIN)
+	if err != nil {
+		return store.AddLoadItemParams{}, err
+	}
+	allowRot := true
+	if item.AllowRotation != nil {
+		allowRot = *item.AllowRotation
+	}
+
+	length, width, height, weight := item.LengthMM, item.WidthMM, item.HeightMM, item.WeightKG
+	params := store.AddLoadItemParams{
+		ItemLabel:     item.Label,
+		Quantity:      int32(item.Quantity),
+		AllowRotation: &allowRot,
+		ColorHex:      item.ColorHex,
+		PaddingMm:     toOptionalNumeric(item.PaddingMM),
+		Priority:      int32(item.Priority),
+		MustShip:      item.MustShip,
+
+		FrictionCoefficient: toOptionalNumeric(item.FrictionCoefficient),
+		TemperatureClass:    item.TemperatureClass,
+		Gtin:                gtin,
+		ProductSku:          item.ProductSKU,
+
+		Fragile:        boolOr(item.Fragile, false),
+		ThisSideUp:     boolOr(item.ThisSideUp, false),
+		Stackable:      boolOr(item.Stackable, true),
+		MaxStackLoadKg: toOptionalNumeric(item.MaxStackLoadKG),
+		HazmatClass:    item.HazmatClass,
+		PackagingType:  item.PackagingType,
+
+		Unit: item.Unit,
+	}
+
+	if product != nil {
+		ship, units, eaches, err := shippingQuantity(*product, item.Unit, item.Quantity)
+		if err != nil {
+			return store.AddLoadItemParams{}, err
+		}
+		perUnit, ordered := int32(ship.EachQuantity), int32(eaches)
+		params.Quantity = int32(units)
+		params.Unit = &ship.Level
+		params.EachesPerUnit = &perUnit
+		params.OrderedEaches = &ordered
+
+		overridden := false
+		fill := func(v *float64, from float64) {
+			switch {
+			case *v == 0:
+				*v = from
+			case *v != from:
+				overridden = true
+			}
+		}
+		fill(&length, ship.LengthMM)
+		fill(&width, ship.WidthMM)
+		fill(&height, ship.HeightMM)
+		fill(&weight, ship.WeightKG)
+
+		if params.ItemLabel == nil {
+			name := product.Name
+			params.ItemLabel = &name
+		}
+		if params.ColorHex == nil {
+			params.ColorHex = product.ColorHex
+		} else if !strings.EqualFold(*params.ColorHex, getString(product.ColorHex)) {
+			overridden = true
+		}
+		if item.FrictionCoefficient == nil {
+			params.FrictionCoefficient = product.FrictionCoefficient
+		} else if *item.FrictionCoefficient != toFloat(product.FrictionCoefficient) {
+			overridden = true
+		}
+		if params.TemperatureClass == nil {
+			params.TemperatureClass = product.TemperatureClass
+		} else if *params.TemperatureClass != getString(product.TemperatureClass) {
+			overridden = true
+		}
+		if params.Gtin == nil {
+			params.Gtin = product.Gtin
+		} else if *params.Gtin != getString(product.Gtin) {
+			overridden = true
+		}
+
+		fillFlag := func(v *bool, given *bool, from bool) {
+			switch {
+			case given == nil:
+				*v = from
+			case *given != from:
+				overridden = true
+			}
+		}
+		fillFlag(&params.Fragile, item.Fragile, product.Fragile)
+		fillFlag(&params.ThisSideUp, item.ThisSideUp, product.ThisSideUp)
+		fillFlag(&params.Stackable, item.Stackable, product.Stackable)
+		if item.MaxStackLoadKG == nil {
+			params.MaxStackLoadKg = product.MaxStackLoadKg
+		} else if *item.MaxStackLoadKG != toFloat(product.MaxStackLoadKg) {
+			overridden = true
+		}
+		if params.HazmatClass == nil {
+			params.HazmatClass = product.HazmatClass
+		} else if *params.HazmatClass != getString(product.HazmatClass) {
+			overridden = true
+		}
+		if params.PackagingType == nil {
+			params.PackagingType = product.PackagingType
+		} else if *params.PackagingType != getString(product.PackagingType) {
+			overridden = true
+		}
+
+		params.ProductID = &product.ProductID
+		if product.Sku != nil {
+			params.ProductSku = product.Sku
+		}
+		params.ProductOverridden = overridden
+	}
+
+	if length <= 0 || width <= 0 || height <= 0 || weight <= 0 {
+		return store.AddLoadItemParams{}, ErrItemDimensionsRequired
+	}
+	if params.ColorHex == nil {
+		color := "#3498db"
+		params.ColorHex = &color
+	}
+	params.LengthMm = toNumeric(length)
+	params.WidthMm = toNumeric(width)
+	params.HeightMm = toNumeric(height)
+	params.WeightKg = toNumeric(weight)
+	return params, nil
+}
+
+// overridesProduct reports whether an update changes an item filled from a
+// product away from the values it was filled with.
+func overridesProduct(existing store.LoadItem, params store.UpdateLoadItemParams) bool {
+	if existing.ProductID == nil {
+		return false
+	}
+	return toFloat(existing.LengthMm) != toFloat(params.LengthMm) ||
+		toFloat(existing.WidthMm) != toFloat(params.WidthMm) ||
+		toFloat(existing.HeightMm) != toFloat(params.HeightMm) ||
+		toFloat(existing.WeightKg) != toFloat(params.WeightKg) ||
+		!strings.EqualFold(getString(existing.ColorHex), getString(params.ColorHex)) ||
+		toFloat(existing.FrictionCoefficient) != toFloat(params.FrictionCoefficient) ||
+		getString(existing.TemperatureClass) != getString(params.TemperatureClass) ||
+		getString(existing.Gtin) != getString(params.Gtin) ||
+		existing.Fragile != params.Fragile ||
+		existing.ThisSideUp != params.ThisSideUp ||
+		existing.Stackable != params.Stackable ||
+		toFloat(existing.MaxStackLoadKg) != toFloat(params.MaxStackLoadKg) ||
+		getString(existing.HazmatClass) != getString(params.HazmatClass) ||
+		getString(existing.PackagingType) != getString(params.PackagingType)
+}
+
+// skuStats totals the items of each SKU, counting the units placed in
+// placements. Items without a SKU are left out.
+func skuStats(items []store.LoadItem, placements []store.PlanPlacement) []dto.SKUStats {
+	placed := make(map[uuid.UUID]int, len(items))
+	for _, pl := range placements {
+		if pl.StepNumber != 0 && pl.ItemID != nil {
+			placed[*pl.ItemID]++
+		}
+	}
+
+	bySKU := make(map[string]*dto.SKUStats)
+	for _, it := range items {
+		sku := getString(it.ProductSku)
+		if sku == "" {
+			continue
+		}
+		st, ok := bySKU[sku]
+		if !ok {
+			st = &dto.SKUStats{SKU: sku}
+			bySKU[sku] = st
+		}
+		d := *mapLoadItemToDetail(it)
+		st.Quantity += d.Quantity
+		st.PlacedUnits += placed[it.ItemID]
+		st.TotalWeightKG += d.TotalWeightKG
+		st.TotalVolumeM3 += d.TotalVolumeM3
+	}
+
+	out := make([]dto.SKUStats, 0, len(bySKU))
+	for _, st := range bySKU {
+		out = append(out, *st)
+	}
+	sort.Slice(out, func(a, b int) bool { return out[a].SKU < out[b].SKU })
+	return out
+}
@@ -0,0 +1,443 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ekastn/load-stuffing-calculator/internal/dto"
+	"github.com/ekastn/load-stuffing-calculator/internal/store"
+	"github.com/ekastn/load-stuffing-calculator/internal/types"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// maxScenariosPerPlan caps how many what-if variants one plan can have.
+const maxScenariosPerPlan = 10
+
+var ErrScenarioLimitReached = fmt.Errorf("scenario limit reached")
+
+// ErrInvalidScenarioItems is returned when a scenario excludes or overrides
+// items the forked plan does not have.
+var ErrInvalidScenarioItems = fmt.Errorf("invalid scenario items")
+
+// CreateScenario forks a plan into a named scenario. The scenario is a full
+// plan (items, calculation and results work as usual) hidden from plan lists.
+// Forking a scenario creates a sibling under the same base plan.
+func (s *planService) CreateScenario(ctx context.Context, planID string, req dto.CreateScenarioRequest) (*dto.ScenarioResponse, error) {
+	pID, err := uuid.Parse(planID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid plan id")
+	}
+
+	scope, err := s.resolvePlanScope(ctx, pID)
+	if err != nil {
+		return nil, err
+	}
+	source := scope.plan
+
+	baseID := source.PlanID
+	if source.ParentPlanID != nil {
+		baseID = *source.ParentPlanID
+	}
+
+	existing, err := s.q.ListPlanScenarios(ctx, &baseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scenarios: %w", err)
+	}
+	if len(existing) >= maxScenariosPerPlan {
+		return nil, ErrScenarioLimitReached
+	}
+
+	actor, err := actorFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	createdByType := "user"
+	if actor.role == types.RoleTrial.String() {
+		createdByType = "guest"
+	}
+
+	cont, err := s.overrideContainer(ctx, source.WorkspaceID, containerOf(source), req.Container)
+	if err != nil {
+		return nil, err
+	}
+
+	status := types.PlanStatusDraft.String()
+	params := store.CreateScenarioPlanParams{
+		ParentPlanID:  &baseID,
+		ScenarioName:  &req.Name,
+		WorkspaceID:   source.WorkspaceID,
+		PlanCode:      source.PlanCode,
+		Status:        &status,
+		ContLabel:     cont.label,
+		LengthMm:      cont.length,
+		WidthMm:       cont.width,
+		HeightMm:      cont.height,
+		MaxWeightKg:   cont.maxWeight,
+		CreatedByType: createdByType,
+		CreatedByID:   actor.id,
+
+		WallClearanceMm: cont.wallClearance,
+		ItemGapMm:       cont.itemGap,
+
+		LashingPointsPerSide:    cont.lashingPoints,
+		LashingPointCapacityDan: cont.lashingCapacity,
+		Compartments:            cont.compartments,
+		FloorLoadKgM2:           cont.floorLoad,
+		LineLoadKgM:             cont.lineLoad,
+	}
+
+	items, err := s.q.ListLoadItems(ctx, &source.PlanID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list items: %w", err)
+	}
+
+	known := make(map[string]bool, len(items))
+	for _, it := range items {
+		known[it.ItemID.String()] = true
+	}
+	excluded := make(map[string]bool, len(req.ExcludeItemIDs))
+	for _, id := range req.ExcludeItemIDs {
+		if !known[id] {
+			return nil, fmt.Errorf("%w: excluded item %s is not in the plan", ErrInvalidScenarioItems, id)
+		}
+		excluded[id] = true
+	}
+	for id := range req.QuantityOverrides {
+		if !known[id] {
+			return nil, fmt.Errorf("%w: overridden item %s is not in the plan", ErrInvalidScenarioItems, id)
+		}
+	}
+
+	var scenario store.LoadPlan
+	err = inTx(ctx, s.q, func(q store.Querier) error {
+		var err error
+		scenario, err = q.CreateScenarioPlan(ctx, params)
+		if err != nil {
+			return fmt.Errorf("failed to create scenario: %w", err)
+		}
+
+		for _, it := range items {
+			id := it.ItemID.String()
+			if excluded[id] {
+				continue
+			}
+			qty, ordered := it.Quantity, it.OrderedEaches
+			if n, ok := req.QuantityOverrides[id]; ok {
+				qty, ordered = int32(n), nil
+				if it.EachesPerUnit != nil {
+					eaches := qty * *it.EachesPerUnit
+					ordered = &eaches
+				}
+			}
+
+			_, err := q.AddLoadItem(ctx, store.AddLoadItemParams{
+				PlanID:        &scenario.PlanID,
+				ItemLabel:     it.ItemLabel,
+				LengthMm:      it.LengthMm,
+				WidthMm:       it.WidthMm,
+				HeightMm:      it.HeightMm,
+				WeightKg:      it.WeightKg,
+				Quantity:      qty,
+				AllowRotation: it.AllowRotation,
+				ColorHex:      it.ColorHex,
+				PaddingMm:     it.PaddingMm,
+				Priority:      it.Priority,
+				MustShip:      it.MustShip,
+
+				FrictionCoefficient: it.FrictionCoefficient,
+				TemperatureClass:    it.TemperatureClass,
+				Gtin:                it.Gtin,
+				ProductID:           it.ProductID,
+				ProductSku:          it.ProductSku,
+				ProductOverridden:   it.ProductOverridden,
+
+				Fragile:        it.Fragile,
+				ThisSideUp:     it.ThisSideUp,
+				Stackable:      it.Stackable,
+				MaxStackLoadKg: it.MaxStackLoadKg,
+				HazmatClass:    it.HazmatClass,
+				PackagingType:  it.PackagingType,
+
+				Unit:          it.Unit,
+				EachesPerUnit: it.EachesPerUnit,
+				OrderedEaches: ordered,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to copy item: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return mapScenario(scenario), nil
+}
+
+// ListScenarios returns the scenarios of a plan's base plan, oldest first.
+func (s *planService) ListScenarios(ctx context.Context, planID string) ([]dto.ScenarioResponse, error) {
+	pID, err := uuid.Parse(planID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid plan id")
+	}
+
+	scope, err := s.resolvePlanScope(ctx, pID)
+	if err != nil {
+		return nil, err
+	}
+
+	baseID := scope.plan.PlanID
+	if scope.plan.ParentPlanID != nil {
+		baseID = *scope.plan.ParentPlanID
+	}
+
+	scenarios, err := s.q.ListPlanScenarios(ctx, &baseID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dto.ScenarioResponse, 0, len(scenarios))
+	for _, sc := range scenarios {
+		result = append(result, *mapScenario(sc))
+	}
+	return result, nil
+}
+
+// CompareScenarios puts the base plan and each of its scenarios side by side
+// using their latest calculation.
+func (s *planService) CompareScenarios(ctx context.Context, planID string) (*dto.ScenarioComparisonResponse, error) {
+	pID, err := uuid.Parse(planID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid plan id")
+	}
+
+	scope, err := s.resolvePlanScope(ctx, pID)
+	if err != nil {
+		return nil, err
+	}
+
+	base := scope.plan
+	if base.ParentPlanID != nil {
+		base, err = s.q.GetLoadPlanAny(ctx, *base.ParentPlanID)
+		if err != nil {
+			return nil, fmt.Errorf("base plan not found: %w", err)
+		}
+	}
+
+	scenarios, err := s.q.ListPlanScenarios(ctx, &base.PlanID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.ScenarioComparisonResponse{BasePlanID: base.PlanID.String()}
+	for _, p := range append([]store.LoadPlan{base}, scenarios...) {
+		snap, err := s.scenarioSnapshot(ctx, p)
+		if err != nil {
+			return nil, err
+		}
+		resp.Scenarios = append(resp.Scenarios, *snap)
+	}
+	return resp, nil
+}
+
+func (s *planService) scenarioSnapshot(ctx context.Context, plan store.LoadPlan) (*dto.ScenarioSnapshot, error) {
+	items, err := s.q.ListLoadItems(ctx, &plan.PlanID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list items: %w", err)
+	}
+
+	contL := toFloat(plan.LengthMm)
+	contW := toFloat(plan.WidthMm)
+	contH := toFloat(plan.HeightMm)
+	maxWeight := toFloat(plan.MaxWeightKg)
+
+	snap := &dto.ScenarioSnapshot{
+		PlanID: plan.PlanID.String(),
+		Name:   plan.PlanCode,
+		IsBase: plan.ParentPlanID == nil,
+		Status: getString(plan.Status),
+		Container: dto.PlanContainerInfo{
+			Name:        plan.ContLabel,
+			LengthMM:    contL,
+			WidthMM:     contW,
+			HeightMM:    contH,
+			MaxWeightKG: maxWeight,
+			VolumeM3:    contL * contW * contH / 1_000_000_000.0,
+
+			WallClearanceMM: toFloat(plan.WallClearanceMm),
+			ItemGapMM:       toFloat(plan.ItemGapMm),
+		},
+		UnfitItems: []dto.UnfitItemInfo{},
+	}
+	if plan.ScenarioName != nil {
+		snap.Name = *plan.ScenarioName
+	}
+
+	for _, it := range items {
+		q := int(it.Quantity)
+		snap.Stats.TotalItems += q
+		snap.Stats.TotalWeightKG += toFloat(it.WeightKg) * float64(q)
+		snap.Stats.TotalVolumeM3 += toFloat(it.LengthMm) * toFloat(it.WidthMm) * toFloat(it.HeightMm) / 1_000_000_000.0 * float64(q)
+	}
+
+	res, err := s.q.GetPlanResult(ctx, &plan.PlanID)
+	if err != nil {
+		// Not calculated yet.
+		return snap, nil
+	}
+	snap.Calculated = true
+	snap.Stats.VolumeUtilizationPct = toFloat(res.VolumeUtilizationPct)
+	if maxWeight > 0 {
+		snap.Stats.WeightUtilizationPct = toFloat(res.TotalLoadedWeightKg) / maxWeight * 100
+	}
+
+	placements, err := s.q.ListPlanPlacements(ctx, &res.ResultID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list placements: %w", err)
+	}
+	placed := make(map[uuid.UUID]int, len(items))
+	for _, pl := range placements {
+		if pl.ItemID != nil {
+			placed[*pl.ItemID]++
+		}
+	}
+	snap.PackedItems = len(placements)
+
+	for _, it := range items {
+		if missing := int(it.Quantity) - placed[it.ItemID]; missing > 0 {
+			snap.UnfitItems = append(snap.UnfitItems, dto.UnfitItemInfo{
+				ItemID:   it.ItemID.String(),
+				Label:    it.ItemLabel,
+				Quantity: missing,
+			})
+		}
+	}
+	return snap, nil
+}
+
+// planContainer is the container part of a plan row.
+type planContainer struct {
+	label         *string
+	length        pgtype.Numeric
+	width         pgtype.Numeric
+	height        pgtype.Numeric
+	maxWeight     pgtype.Numeric
+	wallClearance pgtype.Numeric
+	itemGap       pgtype.Numeric
+
+	lashingPoints   int32
+	lashingCapacity pgtype.Numeric
+
+	floorLoad pgtype.Numeric
+	lineLoad  pgtype.Numeric
+
+	compartments []byte
+}
+
+func containerOf(p store.LoadPlan) planContainer {
+	return planContainer{
+		label:         p.ContLabel,
+		length:        p.LengthMm,
+		width:         p.WidthMm,
+		height:        p.HeightMm,
+		maxWeight:     p.MaxWeightKg,
+		wallClearance: p.WallClearanceMm,
+		itemGap:       p.ItemGapMm,
+
+		lashingPoints:   p.LashingPointsPerSide,
+		lashingCapacity: p.LashingPointCapacityDan,
+
+		floorLoad: p.FloorLoadKgM2,
+		lineLoad:  p.LineLoadKgM,
+
+		compartments: p.Compartments,
+	}
+}
+
+// overrideContainer applies a container change to base: a saved container
+// replaces the dimensions and limits, otherwise each given field is replaced.
+func (s *planService) overrideContainer(ctx context.Context, workspaceID *uuid.UUID, base planContainer, req *dto.CreatePlanContainer) (planContainer, error) {
+	if req == nil {
+		return base, nil
+	}
+	c := base
+	if req.ContainerID != nil {
+		contUUID, err := uuid.Parse(*req.ContainerID)
+		if err != nil {
+			return c, fmt.Errorf("invalid container_id format")
+		}
+		cont, err := s.q.GetContainer(ctx, store.GetContainerParams{ContainerID: contUUID, WorkspaceID: workspaceID})
+		if err != nil {
+			return c, fmt.Errorf("container not found: %w", err)
+		}
+		c.label = &cont.Name
+		c.length = cont.InnerLengthMm
+		c.width = cont.InnerWidthMm
+		c.height = cont.InnerHeightMm
+		c.maxWeight = cont.MaxWeightKg
+		c.lashingPoints = cont.LashingPointsPerSide
+		c.lashingCapacity = cont.LashingPointCapacityDan
+		c.floorLoad = cont.FloorLoadKgM2
+		c.lineLoad = cont.LineLoadKgM
+		c.compartments = cont.Compartments
+	} else {
+		if req.LengthMM != nil {
+			c.length = toNumeric(*req.LengthMM)
+		}
+		if req.WidthMM != nil {
+			c.width = toNumeric(*req.WidthMM)
+		}
+		if req.HeightMM != nil {
+			c.height = toNumeric(*req.HeightMM)
+		}
+		if req.MaxWeightKG != nil {
+			c.maxWeight = toNumeric(*req.MaxWeightKG)
+		}
+		if req.LashingPointsPerSide != nil {
+			c.lashingPoints = int32(*req.LashingPointsPerSide)
+		}
+		if req.LashingPointCapacityDaN != nil {
+			c.lashingCapacity = toNumeric(*req.LashingPointCapacityDaN)
+		}
+		if req.FloorLoadKgM2 != nil {
+			c.floorLoad = toNumeric(*req.FloorLoadKgM2)
+		}
+		if req.LineLoadKgM != nil {
+			c.lineLoad = toNumeric(*req.LineLoadKgM)
+		}
+	}
+	if req.WallClearanceMM != nil {
+		c.wallClearance = toNumeric(*req.WallClearanceMM)
+	}
+	if req.ItemGapMM != nil {
+		c.itemGap = toNumeric(*req.ItemGapMM)
+	}
+
+	// Compartments must still fit when only the length changed.
+	comps := decodeCompartments(c.compartments)
+	if req.ContainerID == nil && req.Compartments != nil {
+		comps = req.Compartments
+	}
+	raw, err := encodeCompartments(toFloat(c.length), comps)
+	if err != nil {
+		return c, err
+	}
+	c.compartments = raw
+	return c, nil
+}
+
+func mapScenario(p store.LoadPlan) *dto.ScenarioResponse {
+	resp := &dto.ScenarioResponse{
+		PlanID:    p.PlanID.String(),
+		Name:      getString(p.ScenarioName),
+		PlanCode:  p.PlanCode,
+		Status:    getString(p.Status),
+		CreatedAt: p.CreatedAt.Time,
+	}
+	if p.ParentPlanID != nil {
+		resp.ParentPlanID = p.ParentPlanID.String()
+	}
+	return resp
+}
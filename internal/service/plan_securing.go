@@ -0,0 +1,94 @@
+package service
+
+import (
+	"github.com/ekastn/load-stuffing-calculator/internal/dto"
+	"github.com/ekastn/load-stuffing-calculator/internal/packer"
+	"github.com/ekastn/load-stuffing-calculator/internal/store"
+)
+
+// securingStandard names the standard the securing estimate follows.
+const securingStandard = "EN 12195-1:2010"
+
+// planSecuring estimates the lashing for a packed load.
+func planSecuring(container packer.ContainerInput, packed []packer.PackedItem, items []packer.ItemInput) *dto.SecuringPlan {
+	cfg := packer.DefaultSecuringConfig
+	plan := packer.PlanSecuring(container, packed, items, cfg)
+
+	out := &dto.SecuringPlan{
+		Standard:      securingStandard,
+		Forward:       cfg.Forward,
+		Backward:      cfg.Backward,
+		Sideways:      cfg.Sideways,
+		StrapLCDaN:    cfg.StrapLC,
+		StrapSTFDaN:   cfg.StrapSTF,
+		LashingPoints: container.LashingPointsPerSide,
+
+		Blocks:         make([]dto.SecuringBlock, 0, len(plan.Blocks)),
+		TotalStraps:    plan.TotalStraps,
+		TopOverStraps:  plan.TopOverStraps,
+		DirectLashings: plan.DirectLashings,
+		Sufficient:     plan.Sufficient,
+		Warnings:       plan.Warnings,
+	}
+	for _, b := range plan.Blocks {
+		out.Blocks = append(out.Blocks, dto.SecuringBlock{
+			ItemID:         b.ItemID,
+			Label:          b.Label,
+			Units:          b.Units,
+			WeightKG:       b.WeightKG,
+			PositionX:      b.Position.X,
+			PositionY:      b.Position.Y,
+			PositionZ:      b.Position.Z,
+			LengthMM:       b.Length,
+			WidthMM:        b.Width,
+			HeightMM:       b.Height,
+			Friction:       b.Friction,
+			AngleDeg:       b.AngleDeg,
+			RestraintDaN:   b.RestraintDaN,
+			TippingRisk:    b.TippingRisk,
+			TopOverStraps:  b.TopOverStraps,
+			DirectLashings: b.DirectLashings,
+			Method:         b.Method,
+			Straps:         b.Straps,
+			LashingPoints:  b.LashingPoints,
+			Sufficient:     b.Sufficient,
+			Note:           b.Note,
+		})
+	}
+	return out
+}
+
+// packedFromPlacements rebuilds the packed units of a stored result.
+func packedFromPlacements(items []packer.ItemInput, placements []store.PlanPlacement) []packer.PackedItem {
+	byID := make(map[string]packer.ItemInput, len(items))
+	for _, it := range items {
+		byID[it.ID] = it
+	}
+
+	packed := make([]packer.PackedItem, 0, len(placements))
+	for _, pl := range placements {
+		if pl.ItemID == nil {
+			continue
+		}
+		it, ok := byID[pl.ItemID.String()]
+		if !ok {
+			continue
+		}
+		rot := 0
+		if pl.RotationCode != nil {
+			rot = int(*pl.RotationCode)
+		}
+		l, w, h := applyRotation(it.Length, it.Width, it.Height, rot)
+		packed = append(packed, packer.PackedItem{
+			ItemID:        it.ID,
+			InstanceID:    pl.PlacementID.String(),
+			Label:         it.Label,
+			RotatedLength: l,
+			RotatedWidth:  w,
+			RotatedHeight: h,
+			Position:      packer.Position{X: toFloat(pl.PosX), Y: toFloat(pl.PosY), Z: toFloat(pl.PosZ)},
+			RotationType:  rot,
+		})
+	}
+	return packed
+}
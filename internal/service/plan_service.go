@@ -12,6 +12,7 @@ import (
 	"github.com/ekastn/load-stuffing-calculator/internal/store"
 	"github.com/ekastn/load-stuffing-calculator/internal/types"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type PlanService interface {
@@ -25,13 +26,40 @@ type PlanService interface {
 	UpdatePlanItem(ctx context.Context, planID, itemID string, req dto.UpdatePlanItemRequest) error
 	DeletePlanItem(ctx context.Context, planID, itemID string) error
 	CalculatePlan(ctx context.Context, planID string, opts dto.CalculatePlanRequest) (*dto.CalculationResult, error)
+	CalculatePlanWithProgress(ctx context.Context, planID string, opts dto.CalculatePlanRequest, onProgress func(dto.CalculationProgress)) (*dto.CalculationResult, error)
+	ComparePlan(ctx context.Context, planID string, req dto.ComparePlanRequest) (*dto.ComparePlanResponse, error)
+	AdoptAlternative(ctx context.Context, planID, alternativeID string) (*dto.CalculationResult, error)
+	CreateScenario(ctx context.Context, planID string, req dto.CreateScenarioRequest) (*dto.ScenarioResponse, error)
+	ListScenarios(ctx context.Context, planID string) ([]dto.ScenarioResponse, error)
+	CompareScenarios(ctx context.Context, planID string) (*dto.ScenarioComparisonResponse, error)
+	PreflightPlan(ctx context.Context, planID string) (*dto.PreflightResponse, error)
+	CreateOverflowPlan(ctx context.Context, planID string, req dto.CreateOverflowPlanRequest) (*dto.OverflowPlanResponse, error)
+	GetShipmentGroup(ctx context.Context, planID string) (*dto.ShipmentGroupResponse, error)
+	GetPlanStatusHistory(ctx context.Context, planID string) ([]dto.PlanStatusChange, error)
+	ListResultVersions(ctx context.Context, planID string) ([]dto.ResultVersion, error)
+	GetResultVersion(ctx context.Context, planID string, version int) (*dto.ResultVersion, error)
+	RestoreResultVersion(ctx context.Context, planID string, version int) (*dto.ResultVersion, error)
+	DiffResultVersions(ctx context.Context, planID string, from, to int) (*dto.ResultVersionDiff, error)
+	AssignHandlingUnits(ctx context.Context, planID string) ([]dto.HandlingUnit, error)
+	ListHandlingUnits(ctx context.Context, planID string) ([]dto.HandlingUnit, error)
 }
 
 type planService struct {
-	q store.Querier
-	p packer.Packer
+	q        store.Querier
+	p        packer.Packer
+	backends PackerBackends
+
+	alternatives alternativeCache
 }
 
+// PackerBackends maps backend names accepted in calculation options to packers.
+type PackerBackends map[string]packer.Packer
+
+// DefaultBackend names the packer passed to NewPlanService.
+const DefaultBackend = "default"
+
+var ErrUnknownBackend = fmt.Errorf("unknown packing backend")
+
 type planScope struct {
 	plan        store.LoadPlan
 	workspaceID *uuid.UUID
@@ -61,14 +89,39 @@ func actorFromContext(ctx context.Context) (*planActor, error) {
 	return &planActor{role: role, id: id}, nil
 }
 
+// checkTrialLimit returns ErrTrialLimitReached when a trial guest already
+// owns as many plans as a trial allows.
+func (s *planService) checkTrialLimit(ctx context.Context, actor *planActor) error {
+	if actor.role != types.RoleTrial.String() {
+		return nil
+	}
+	count, err := s.q.CountPlansByCreator(ctx, store.CountPlansByCreatorParams{
+		CreatedByType: "guest",
+		CreatedByID:   actor.id,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to check trial limit: %w", err)
+	}
+	if count >= 3 {
+		return ErrTrialLimitReached
+	}
+	return nil
+}
+
 func (s *planService) resolvePlanScope(ctx context.Context, planID uuid.UUID) (*planScope, error) {
+	return resolvePlanScope(ctx, s.q, planID)
+}
+
+// resolvePlanScope loads a plan the caller in ctx is allowed to see. It is shared
+// by services that work on plans without owning them (e.g. calculation jobs).
+func resolvePlanScope(ctx context.Context, q store.Querier, planID uuid.UUID) (*planScope, error) {
 	actor, err := actorFromContext(ctx)
 	if err != nil {
 		return nil, err
 	}
 
 	if actor.role == types.RoleTrial.String() {
-		plan, err := s.q.GetLoadPlanForGuest(ctx, store.GetLoadPlanForGuestParams{PlanID: planID, CreatedByID: actor.id})
+		plan, err := q.GetLoadPlanForGuest(ctx, store.GetLoadPlanForGuestParams{PlanID: planID, CreatedByID: actor.id})
 		if err != nil {
 			return nil, ErrForbidden
 		}
@@ -94,7 +147,7 @@ func (s *planService) resolvePlanScope(ctx context.Context, planID uuid.UUID) (*
 	}
 
 	if isFounder(ctx) && overrideWorkspaceID == nil {
-		plan, err := s.q.GetLoadPlanAny(ctx, planID)
+		plan, err := q.GetLoadPlanAny(ctx, planID)
 		if err != nil {
 			return nil, err
 		}
@@ -109,7 +162,7 @@ func (s *planService) resolvePlanScope(ctx context.Context, planID uuid.UUID) (*
 		workspaceID = overrideWorkspaceID
 	}
 
-	plan, err := s.q.GetLoadPlan(ctx, store.GetLoadPlanParams{PlanID: planID, WorkspaceID: workspaceID})
+	plan, err := q.GetLoadPlan(ctx, store.GetLoadPlanParams{PlanID: planID, WorkspaceID: workspaceID})
 	if err != nil {
 		return nil, err
 	}
@@ -117,7 +170,29 @@ func (s *planService) resolvePlanScope(ctx context.Context, planID uuid.UUID) (*
 }
 
 func NewPlanService(q store.Querier, p packer.Packer) PlanService {
-	return &planService{q: q, p: p}
+	return NewPlanServiceWithBackends(q, p, nil)
+}
+
+// NewPlanServiceWithBackends is like NewPlanService but also accepts named
+// alternative packers that requests can select with the "backend" option.
+func NewPlanServiceWithBackends(q store.Querier, p packer.Packer, backends PackerBackends) PlanService {
+	all := PackerBackends{DefaultBackend: p}
+	for name, b := range backends {
+		all[name] = b
+	}
+	return &planService{q: q, p: p, backends: all}
+}
+
+func (s *planService) packerFor(backend string) (packer.Packer, error) {
+	name := strings.ToLower(strings.TrimSpace(backend))
+	if name == "" {
+		name = DefaultBackend
+	}
+	p, ok := s.backends[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownBackend, backend)
+	}
+	return p, nil
 }
 
 func (s *planService) CreateCompletePlan(ctx context.Context, req dto.CreatePlanRequest) (*dto.CreatePlanResponse, error) {
@@ -133,6 +208,10 @@ func (s *planService) CreateCompletePlan(ctx context.Context, req dto.CreatePlan
 
 	var lengthMM, widthMM, heightMM, maxWeightKG float64
 	var contLabel string = "Custom Container"
+	var lashingPoints int32
+	var lashingCapacity pgtype.Numeric
+	var compartments []byte
+	var floorLoad, lineLoad pgtype.Numeric
 
 	if req.Container.ContainerID != nil {
 		contUUID, err := uuid.Parse(*req.Container.ContainerID)
@@ -157,6 +236,11 @@ func (s *planService) CreateCompletePlan(ctx context.Context, req dto.CreatePlan
 		heightMM = toFloat(cont.InnerHeightMm)
 		maxWeightKG = toFloat(cont.MaxWeightKg)
 		contLabel = cont.Name
+		lashingPoints = cont.LashingPointsPerSide
+		lashingCapacity = cont.LashingPointCapacityDan
+		compartments = cont.Compartments
+		floorLoad = cont.FloorLoadKgM2
+		lineLoad = cont.LineLoadKgM
 	} else {
 		if req.Container.LengthMM == nil || req.Container.WidthMM == nil ||
 			req.Container.HeightMM == nil || req.Container.MaxWeightKG == nil {
@@ -166,6 +250,16 @@ func (s *planService) CreateCompletePlan(ctx context.Context, req dto.CreatePlan
 		widthMM = *req.Container.WidthMM
 		heightMM = *req.Container.HeightMM
 		maxWeightKG = *req.Container.MaxWeightKG
+		if req.Container.LashingPointsPerSide != nil {
+			lashingPoints = int32(*req.Container.LashingPointsPerSide)
+		}
+		lashingCapacity = toOptionalNumeric(req.Container.LashingPointCapacityDaN)
+		floorLoad = toOptionalNumeric(req.Container.FloorLoadKgM2)
+		lineLoad = toOptionalNumeric(req.Container.LineLoadKgM)
+		compartments, err = encodeCompartments(lengthMM, req.Container.Compartments)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	planCode := "PLD-" + time.Now().Format("20060102-150405")
@@ -176,17 +270,8 @@ func (s *planService) CreateCompletePlan(ctx context.Context, req dto.CreatePlan
 		return nil, err
 	}
 
-	if actor.role == types.RoleTrial.String() {
-		count, err := s.q.CountPlansByCreator(ctx, store.CountPlansByCreatorParams{
-			CreatedByType: "guest",
-			CreatedByID:   actor.id,
-		})
-		if err != nil {
-			return nil, fmt.Errorf("failed to check trial limit: %w", err)
-		}
-		if count >= 3 {
-			return nil, ErrTrialLimitReached
-		}
+	if err := s.checkTrialLimit(ctx, actor); err != nil {
+		return nil, err
 	}
 
 	createdByType := "user"
@@ -213,6 +298,17 @@ func (s *planService) CreateCompletePlan(ctx context.Context, req dto.CreatePlan
 		return nil, err
 	}
 
+	itemParams := make([]store.AddLoadItemParams, len(req.Items))
+	for i, item := range req.Items {
+		product, err := lookupItemProduct(ctx, s.q, workspaceID, item)
+		if err != nil {
+			return nil, err
+		}
+		if itemParams[i], err = planItemParams(item, product); err != nil {
+			return nil, err
+		}
+	}
+
 	plan, err := s.q.CreateLoadPlan(ctx, store.CreateLoadPlanParams{
 		WorkspaceID:   workspaceID,
 		PlanCode:      planCode,
@@ -224,6 +320,15 @@ func (s *planService) CreateCompletePlan(ctx context.Context, req dto.CreatePlan
 		MaxWeightKg:   toNumeric(maxWeightKG),
 		CreatedByType: createdByType,
 		CreatedByID:   createdByUUID,
+
+		WallClearanceMm: toOptionalNumeric(req.Container.WallClearanceMM),
+		ItemGapMm:       toOptionalNumeric(req.Container.ItemGapMM),
+
+		LashingPointsPerSide:    lashingPoints,
+		LashingPointCapacityDan: lashingCapacity,
+		Compartments:            compartments,
+		FloorLoadKgM2:           floorLoad,
+		LineLoadKgM:             lineLoad,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create plan: %w", err)
@@ -232,38 +337,16 @@ func (s *planService) CreateCompletePlan(ctx context.Context, req dto.CreatePlan
 	var totalQty int
 	var totalWeight, totalVolume float64
 
-	for _, item := range req.Items {
-		allowRot := true
-		if item.AllowRotation != nil {
-			allowRot = *item.AllowRotation
-		}
-		color := "#3498db"
-		if item.ColorHex != nil {
-			color = *item.ColorHex
-		}
-
-		volumePerItem := item.LengthMM * item.WidthMM * item.HeightMM / 1_000_000_000.0
-		totalVolItem := volumePerItem * float64(item.Quantity)
-		totalWItem := item.WeightKG * float64(item.Quantity)
-
-		_, err := s.q.AddLoadItem(ctx, store.AddLoadItemParams{
-			PlanID:        &plan.PlanID,
-			ItemLabel:     item.Label,
-			LengthMm:      toNumeric(item.LengthMM),
-			WidthMm:       toNumeric(item.WidthMM),
-			HeightMm:      toNumeric(item.HeightMM),
-			WeightKg:      toNumeric(item.WeightKG),
-			Quantity:      int32(item.Quantity),
-			AllowRotation: &allowRot,
-			ColorHex:      &color,
-		})
-		if err != nil {
+	for _, params := range itemParams {
+		params.PlanID = &plan.PlanID
+		if _, err := s.q.AddLoadItem(ctx, params); err != nil {
 			return nil, fmt.Errorf("failed to add item: %w", err)
 		}
 
-		totalQty += item.Quantity
-		totalWeight += totalWItem
-		totalVolume += totalVolItem
+		qty := float64(params.Quantity)
+		totalQty += int(params.Quantity)
+		totalWeight += toFloat(params.WeightKg) * qty
+		totalVolume += toFloat(params.LengthMm) * toFloat(params.WidthMm) * toFloat(params.HeightMm) / 1_000_000_000.0 * qty
 	}
 
 	var jobID *string
@@ -274,21 +357,10 @@ func (s *planService) CreateCompletePlan(ctx context.Context, req dto.CreatePlan
 		calcRes, err := s.CalculatePlan(ctx, plan.PlanID.String(), dto.CalculatePlanRequest{})
 		if err != nil {
 			// If calculation fails, we log it (conceptually) and mark status as FAILED
-			failStatus := types.PlanStatusFailed.String()
-			if isFounder(ctx) {
-				_ = s.q.UpdatePlanStatusAny(ctx, store.UpdatePlanStatusAnyParams{PlanID: plan.PlanID, Status: &failStatus})
-			} else {
-				workspaceID, err := workspaceIDFromContext(ctx)
-				if err == nil {
-					_ = s.q.UpdatePlanStatus(ctx, store.UpdatePlanStatusParams{PlanID: plan.PlanID, WorkspaceID: workspaceID, Status: &failStatus})
-				}
-			}
+			s.markPlanFailed(ctx, plan.PlanID, types.PlanStatusDraft)
 			status = types.PlanStatusFailed.String()
 		} else {
-			status = types.PlanStatusCompleted.String()
-			if !strings.EqualFold(calcRes.Status, types.PlanStatusCompleted.String()) {
-				status = types.PlanStatusPartial.String() // logic inside CalculatePlan handles DB update, here for response
-			}
+			status = calcRes.Status
 			jobID = &calcRes.JobID
 			calcResult = calcRes
 		}
@@ -333,52 +405,30 @@ func (s *planService) GetPlan(ctx context.Context, id string) (*dto.PlanDetailRe
 	var itemDetails []dto.PlanItemDetail
 
 	for _, i := range items {
-		l := toFloat(i.LengthMm)
-		w := toFloat(i.WidthMm)
-		h := toFloat(i.HeightMm)
-		wg := toFloat(i.WeightKg)
-		q := int(i.Quantity)
-
-		vol := l * w * h / 1_000_000_000.0 * float64(q)
-		tw := wg * float64(q)
-
-		totalQty += q
-		totalWeight += tw
-		totalVolume += vol
-
-		itemDetails = append(itemDetails, dto.PlanItemDetail{
-			ItemID:        i.ItemID.String(),
-			Label:         i.ItemLabel,
-			LengthMM:      l,
-			WidthMM:       w,
-			HeightMM:      h,
-			WeightKG:      wg,
-			Quantity:      q,
-			TotalWeightKG: tw,
-			TotalVolumeM3: vol,
-			AllowRotation: *i.AllowRotation,
-			ColorHex:      i.ColorHex,
-			CreatedAt:     "", // DB doesn't have created_at for item
-		})
+		detail := *mapLoadItemToDetail(i)
+		totalQty += detail.Quantity
+		totalWeight += detail.TotalWeightKG
+		totalVolume += detail.TotalVolumeM3
+		itemDetails = append(itemDetails, detail)
 	}
 
-	contL := toFloat(plan.LengthMm)
-	contW := toFloat(plan.WidthMm)
-	contH := toFloat(plan.HeightMm)
-	contVol := contL * contW * contH / 1_000_000_000.0
-
 	var calc *dto.CalculationResult
+	var compStats []dto.CompartmentStats
+	var placed []store.PlanPlacement
 	res, err := s.q.GetPlanResult(ctx, &plan.PlanID)
 	if err == nil {
-		status := types.PlanStatusCompleted.String()
-		if res.IsFeasible != nil && !*res.IsFeasible {
-			status = types.PlanStatusPartial.String()
+		status := resultStatus(res.IsFeasible == nil || *res.IsFeasible).String()
+
+		algorithm := "BestFitDecreasing" // results saved before algorithms were recorded
+		if res.Algorithm != nil {
+			algorithm = *res.Algorithm
 		}
 
 		calc = &dto.CalculationResult{
 			JobID:             res.ResultID.String(),
+			Version:           int(res.Version),
 			Status:            status,
-			Algorithm:         "BestFitDecreasing", // Default for now
+			Algorithm:         algorithm,
 			EfficiencyScore:   toFloat(res.VolumeUtilizationPct),
 			VolumeUtilization: toFloat(res.VolumeUtilizationPct),
 			VisualizationURL:  "/visualizer?plan=" + plan.PlanID.String(),
@@ -387,48 +437,48 @@ func (s *planService) GetPlan(ctx context.Context, id string) (*dto.PlanDetailRe
 		// Fetch placements
 		placements, err := s.q.ListPlanPlacements(ctx, &res.ResultID)
 		if err == nil {
-			var plDetails []dto.PlacementDetail
-			for _, pl := range placements {
-				var iID string
-				if pl.ItemID != nil {
-					iID = pl.ItemID.String()
-				}
-
-				rot := 0
-				if pl.RotationCode != nil {
-					rot = int(*pl.RotationCode)
-				}
-
-				plDetails = append(plDetails, dto.PlacementDetail{
-					PlacementID: pl.PlacementID.String(),
-					ItemID:      iID,
-					PositionX:   toFloat(pl.PosX),
-					PositionY:   toFloat(pl.PosY),
-					PositionZ:   toFloat(pl.PosZ),
-					Rotation:    rot,
-					StepNumber:  int(pl.StepNumber),
-				})
-			}
-			calc.Placements = plDetails
+			calc.Placements = mapPlacementDetails(placements)
+			placed = placements
+
+			contInput, itemInputs := buildPackInputs(plan, items, dto.CalculatePlanRequest{})
+			packed := packedFromPlacements(itemInputs, placements)
+			calc.Securing = planSecuring(contInput, packed, itemInputs)
+			calc.FloorLoad = planFloorLoad(contInput, packed, itemInputs)
+			calc.Handling = planHandling(packed, itemInputs)
+			compStats = compartmentStats(contInput, packed, itemInputs)
 		}
 	}
 
+	var parentPlanID *string
+	if plan.ParentPlanID != nil {
+		id := plan.ParentPlanID.String()
+		parentPlanID = &id
+	}
+	var overflowOfPlanID, shipmentGroupID *string
+	if plan.OverflowOfPlanID != nil {
+		id := plan.OverflowOfPlanID.String()
+		overflowOfPlanID = &id
+	}
+	if plan.ShipmentGroupID != nil {
+		id := plan.ShipmentGroupID.String()
+		shipmentGroupID = &id
+	}
+
 	return &dto.PlanDetailResponse{
-		PlanID:   plan.PlanID.String(),
-		PlanCode: plan.PlanCode,
-		Status:   getString(plan.Status),
-		Container: dto.PlanContainerInfo{
-			Name:        plan.ContLabel,
-			LengthMM:    contL,
-			WidthMM:     contW,
-			HeightMM:    contH,
-			MaxWeightKG: toFloat(plan.MaxWeightKg),
-			VolumeM3:    contVol,
-		},
+		PlanID:           plan.PlanID.String(),
+		ParentPlanID:     parentPlanID,
+		ScenarioName:     plan.ScenarioName,
+		OverflowOfPlanID: overflowOfPlanID,
+		ShipmentGroupID:  shipmentGroupID,
+		PlanCode:         plan.PlanCode,
+		Status:           getString(plan.Status),
+		Container:        mapPlanContainerInfo(plan),
 		Stats: dto.PlanStats{
 			TotalItems:    totalQty,
 			TotalWeightKG: totalWeight,
 			TotalVolumeM3: totalVolume,
+			Compartments:  compStats,
+			SKUs:          skuStats(items, placed),
 		},
 		Items:       itemDetails,
 		Calculation: calc,
@@ -436,6 +486,57 @@ func (s *planService) GetPlan(ctx context.Context, id string) (*dto.PlanDetailRe
 	}, nil
 }
 
+func mapPlanContainerInfo(plan store.LoadPlan) dto.PlanContainerInfo {
+	l := toFloat(plan.LengthMm)
+	w := toFloat(plan.WidthMm)
+	h := toFloat(plan.HeightMm)
+	return dto.PlanContainerInfo{
+		Name:        plan.ContLabel,
+		LengthMM:    l,
+		WidthMM:     w,
+		HeightMM:    h,
+		MaxWeightKG: toFloat(plan.MaxWeightKg),
+		VolumeM3:    l * w * h / 1_000_000_000.0,
+
+		WallClearanceMM: toFloat(plan.WallClearanceMm),
+		ItemGapMM:       toFloat(plan.ItemGapMm),
+
+		LashingPointsPerSide:    int(plan.LashingPointsPerSide),
+		LashingPointCapacityDaN: toOptionalFloat(plan.LashingPointCapacityDan),
+
+		FloorLoadKgM2: toOptionalFloat(plan.FloorLoadKgM2),
+		LineLoadKgM:   toOptionalFloat(plan.LineLoadKgM),
+
+		Compartments: decodeCompartments(plan.Compartments),
+	}
+}
+
+func mapPlacementDetails(placements []store.PlanPlacement) []dto.PlacementDetail {
+	var out []dto.PlacementDetail
+	for _, pl := range placements {
+		var iID string
+		if pl.ItemID != nil {
+			iID = pl.ItemID.String()
+		}
+
+		rot := 0
+		if pl.RotationCode != nil {
+			rot = int(*pl.RotationCode)
+		}
+
+		out = append(out, dto.PlacementDetail{
+			PlacementID: pl.PlacementID.String(),
+			ItemID:      iID,
+			PositionX:   toFloat(pl.PosX),
+			PositionY:   toFloat(pl.PosY),
+			PositionZ:   toFloat(pl.PosZ),
+			Rotation:    rot,
+			StepNumber:  int(pl.StepNumber),
+		})
+	}
+	return out
+}
+
 func (s *planService) ListPlans(ctx context.Context, page, limit int32) ([]dto.PlanListItem, error) {
 	if page < 1 {
 		page = 1
@@ -501,55 +602,61 @@ func (s *planService) UpdatePlan(ctx context.Context, id string, req dto.UpdateP
 		workspaceIDForWrite = plan.WorkspaceID
 	}
 
-	params := store.UpdateLoadPlanParams{
-		PlanID:      planUUID,
-		WorkspaceID: workspaceIDForWrite,
-		PlanCode:    plan.PlanCode,
-		ContLabel:   plan.ContLabel,
-		LengthMm:    plan.LengthMm,
-		WidthMm:     plan.WidthMm,
-		HeightMm:    plan.HeightMm,
-		MaxWeightKg: plan.MaxWeightKg,
-		Status:      plan.Status,
-	}
-
-	if req.Status != nil {
-		params.Status = req.Status
+	cont, err := s.overrideContainer(ctx, workspaceIDForWrite, containerOf(plan), req.Container)
+	if err != nil {
+		return err
 	}
 
-	if req.Container != nil {
-		if req.Container.ContainerID != nil {
-			contUUID, err := uuid.Parse(*req.Container.ContainerID)
-			if err != nil {
-				return fmt.Errorf("invalid container_id format")
+	return inTx(ctx, s.q, func(q store.Querier) error {
+		from, err := lockPlanStatus(ctx, q, planUUID)
+		if err != nil {
+			return err
+		}
+		if req.Container != nil && !from.Calculable() {
+			return fmt.Errorf("%w: the container of a %s plan cannot be changed", ErrInvalidStatusTransition, from)
+		}
+		to := from
+		if req.Status != nil {
+			to = types.PlanStatus(*req.Status)
+			if to != from {
+				if err := checkStatusTransition(ctx, q, plan, from, to); err != nil {
+					return err
+				}
 			}
-			cont, err := s.q.GetContainer(ctx, store.GetContainerParams{ContainerID: contUUID, WorkspaceID: workspaceIDForWrite})
+		}
 
-			if err != nil {
-				return fmt.Errorf("container not found: %w", err)
-			}
-			params.ContLabel = &cont.Name
-			params.LengthMm = cont.InnerLengthMm
-			params.WidthMm = cont.InnerWidthMm
-			params.HeightMm = cont.InnerHeightMm
-			params.MaxWeightKg = cont.MaxWeightKg
-		} else {
-			if req.Container.LengthMM != nil {
-				params.LengthMm = toNumeric(*req.Container.LengthMM)
-			}
-			if req.Container.WidthMM != nil {
-				params.WidthMm = toNumeric(*req.Container.WidthMM)
-			}
-			if req.Container.HeightMM != nil {
-				params.HeightMm = toNumeric(*req.Container.HeightMM)
-			}
-			if req.Container.MaxWeightKG != nil {
-				params.MaxWeightKg = toNumeric(*req.Container.MaxWeightKG)
+		status := to.String()
+		err = q.UpdateLoadPlan(ctx, store.UpdateLoadPlanParams{
+			PlanID:      planUUID,
+			WorkspaceID: workspaceIDForWrite,
+			PlanCode:    plan.PlanCode,
+			ContLabel:   cont.label,
+			LengthMm:    cont.length,
+			WidthMm:     cont.width,
+			HeightMm:    cont.height,
+			MaxWeightKg: cont.maxWeight,
+			Status:      &status,
+
+			WallClearanceMm: cont.wallClearance,
+			ItemGapMm:       cont.itemGap,
+
+			LashingPointsPerSide:    cont.lashingPoints,
+			LashingPointCapacityDan: cont.lashingCapacity,
+			Compartments:            cont.compartments,
+			FloorLoadKgM2:           cont.floorLoad,
+			LineLoadKgM:             cont.lineLoad,
+		})
+		if err != nil {
+			return err
+		}
+		// Leaving IN_PROGRESS ends the loading session still open.
+		if from == types.PlanStatusInProgress && to != from {
+			if err := q.AbandonLoadingSessions(ctx, planUUID); err != nil {
+				return fmt.Errorf("failed to close loading sessions: %w", err)
 			}
 		}
-	}
-
-	return s.q.UpdateLoadPlan(ctx, params)
+		return recordPlanStatus(ctx, q, planUUID, from, to, req.StatusReason)
+	})
 }
 
 func (s *planService) DeletePlan(ctx context.Context, id string) error {
@@ -575,32 +682,34 @@ func (s *planService) AddPlanItem(ctx context.Context, planID string, req dto.Ad
 		return nil, fmt.Errorf("invalid plan id")
 	}
 
-	if _, err := s.resolvePlanScope(ctx, pID); err != nil {
+	scope, err := s.resolvePlanScope(ctx, pID)
+	if err != nil {
 		return nil, err
 	}
 
-	allowRot := true
-	if req.AllowRotation != nil {
-		allowRot = *req.AllowRotation
-	}
-	color := "#3498db"
-	if req.ColorHex != nil {
-		color = *req.ColorHex
-	}
-
-	item, err := s.q.AddLoadItem(ctx, store.AddLoadItemParams{
-		PlanID:        &pID,
-		ItemLabel:     req.Label,
-		LengthMm:      toNumeric(req.LengthMM),
-		WidthMm:       toNumeric(req.WidthMM),
-		HeightMm:      toNumeric(req.HeightMM),
-		WeightKg:      toNumeric(req.WeightKG),
-		Quantity:      int32(req.Quantity),
-		AllowRotation: &allowRot,
-		ColorHex:      &color,
+	product, err := lookupItemProduct(ctx, s.q, scope.plan.WorkspaceID, req.CreatePlanItem)
+	if err != nil {
+		return nil, err
+	}
+	params, err := planItemParams(req.CreatePlanItem, product)
+	if err != nil {
+		return nil, err
+	}
+	params.PlanID = &pID
+
+	var item store.LoadItem
+	err = inTx(ctx, s.q, func(q store.Querier) error {
+		if err := lockEditablePlan(ctx, q, pID); err != nil {
+			return err
+		}
+		item, err = q.AddLoadItem(ctx, params)
+		if err != nil {
+			return fmt.Errorf("failed to add item: %w", err)
+		}
+		return nil
 	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to add item: %w", err)
+		return nil, err
 	}
 
 	return mapLoadItemToDetail(item), nil
@@ -643,54 +752,122 @@ func (s *planService) UpdatePlanItem(ctx context.Context, planID, itemID string,
 		return fmt.Errorf("invalid item id")
 	}
 
-	// Fetch existing to merge
-	existing, err := s.q.GetLoadItem(ctx, store.GetLoadItemParams{PlanID: &pID, ItemID: iID})
-	if err != nil {
-		return err
-	}
+	return inTx(ctx, s.q, func(q store.Querier) error {
+		if err := lockEditablePlan(ctx, q, pID); err != nil {
+			return err
+		}
 
-	params := store.UpdateLoadItemParams{
-		PlanID:        &pID,
-		ItemID:        iID,
-		ItemLabel:     existing.ItemLabel,
-		LengthMm:      existing.LengthMm,
-		WidthMm:       existing.WidthMm,
-		HeightMm:      existing.HeightMm,
-		WeightKg:      existing.WeightKg,
-		Quantity:      existing.Quantity,
-		AllowRotation: existing.AllowRotation,
-		ColorHex:      existing.ColorHex,
-	}
+		// Fetch existing to merge
+		existing, err := q.GetLoadItem(ctx, store.GetLoadItemParams{PlanID: &pID, ItemID: iID})
+		if err != nil {
+			return err
+		}
 
-	if req.Label != nil {
-		params.ItemLabel = req.Label
-	}
-	if req.LengthMM != nil {
-		params.LengthMm = toNumeric(*req.LengthMM)
-	}
-	if req.WidthMM != nil {
-		params.WidthMm = toNumeric(*req.WidthMM)
-	}
-	if req.HeightMM != nil {
-		params.HeightMm = toNumeric(*req.HeightMM)
-	}
-	if req.WeightKG != nil {
-		params.WeightKg = toNumeric(*req.WeightKG)
-	}
-	if req.Quantity != nil {
-		params.Quantity = int32(*req.Quantity)
-	}
-	if req.AllowRotation != nil {
-		params.AllowRotation = req.AllowRotation
-	}
-	if req.ColorHex != nil {
-		params.ColorHex = req.ColorHex
-	}
+		params := store.UpdateLoadItemParams{
+			PlanID:        &pID,
+			ItemID:        iID,
+			ItemLabel:     existing.ItemLabel,
+			LengthMm:      existing.LengthMm,
+			WidthMm:       existing.WidthMm,
+			HeightMm:      existing.HeightMm,
+			WeightKg:      existing.WeightKg,
+			Quantity:      existing.Quantity,
+			AllowRotation: existing.AllowRotation,
+			ColorHex:      existing.ColorHex,
+			PaddingMm:     existing.PaddingMm,
+			Priority:      existing.Priority,
+			MustShip:      existing.MustShip,
+
+			FrictionCoefficient: existing.FrictionCoefficient,
+			TemperatureClass:    existing.TemperatureClass,
+			Gtin:                existing.Gtin,
+			ProductOverridden:   existing.ProductOverridden,
+
+			Fragile:        existing.Fragile,
+			ThisSideUp:     existing.ThisSideUp,
+			Stackable:      existing.Stackable,
+			MaxStackLoadKg: existing.MaxStackLoadKg,
+			HazmatClass:    existing.HazmatClass,
+			PackagingType:  existing.PackagingType,
+			OrderedEaches:  existing.OrderedEaches,
+		}
 
-	if err := s.q.UpdateLoadItem(ctx, params); err != nil {
-		return fmt.Errorf("failed to update item: %w", err)
-	}
-	return nil
+		if req.Label != nil {
+			params.ItemLabel = req.Label
+		}
+		if req.LengthMM != nil {
+			params.LengthMm = toNumeric(*req.LengthMM)
+		}
+		if req.WidthMM != nil {
+			params.WidthMm = toNumeric(*req.WidthMM)
+		}
+		if req.HeightMM != nil {
+			params.HeightMm = toNumeric(*req.HeightMM)
+		}
+		if req.WeightKG != nil {
+			params.WeightKg = toNumeric(*req.WeightKG)
+		}
+		if req.Quantity != nil {
+			params.Quantity = int32(*req.Quantity)
+			if existing.EachesPerUnit != nil {
+				ordered := params.Quantity * *existing.EachesPerUnit
+				params.OrderedEaches = &ordered
+			}
+		}
+		if req.AllowRotation != nil {
+			params.AllowRotation = req.AllowRotation
+		}
+		if req.ColorHex != nil {
+			params.ColorHex = req.ColorHex
+		}
+		if req.PaddingMM != nil {
+			params.PaddingMm = toNumeric(*req.PaddingMM)
+		}
+		if req.Priority != nil {
+			params.Priority = int32(*req.Priority)
+		}
+		if req.MustShip != nil {
+			params.MustShip = *req.MustShip
+		}
+		if req.FrictionCoefficient != nil {
+			params.FrictionCoefficient = toNumeric(*req.FrictionCoefficient)
+		}
+		if req.TemperatureClass != nil {
+			params.TemperatureClass = req.TemperatureClass
+		}
+		if req.GTIN != nil {
+			// An empty GTIN clears it.
+			if params.Gtin, err = normalizeGTIN(req.GTIN); err != nil {
+				return err
+			}
+		}
+		if req.Fragile != nil {
+			params.Fragile = *req.Fragile
+		}
+		if req.ThisSideUp != nil {
+			params.ThisSideUp = *req.ThisSideUp
+		}
+		if req.Stackable != nil {
+			params.Stackable = *req.Stackable
+		}
+		if req.MaxStackLoadKG != nil {
+			params.MaxStackLoadKg = toNumeric(*req.MaxStackLoadKG)
+		}
+		if req.HazmatClass != nil {
+			params.HazmatClass = req.HazmatClass
+		}
+		if req.PackagingType != nil {
+			params.PackagingType = req.PackagingType
+		}
+		if overridesProduct(existing, params) {
+			params.ProductOverridden = true
+		}
+
+		if err := q.UpdateLoadItem(ctx, params); err != nil {
+			return fmt.Errorf("failed to update item: %w", err)
+		}
+		return nil
+	})
 }
 
 func (s *planService) DeletePlanItem(ctx context.Context, planID, itemID string) error {
@@ -707,10 +884,15 @@ func (s *planService) DeletePlanItem(ctx context.Context, planID, itemID string)
 		return fmt.Errorf("invalid item id")
 	}
 
-	if err := s.q.DeleteLoadItem(ctx, store.DeleteLoadItemParams{PlanID: &pID, ItemID: iID}); err != nil {
-		return fmt.Errorf("failed to delete item: %w", err)
-	}
-	return nil
+	return inTx(ctx, s.q, func(q store.Querier) error {
+		if err := lockEditablePlan(ctx, q, pID); err != nil {
+			return err
+		}
+		if err := q.DeleteLoadItem(ctx, store.DeleteLoadItemParams{PlanID: &pID, ItemID: iID}); err != nil {
+			return fmt.Errorf("failed to delete item: %w", err)
+		}
+		return nil
+	})
 }
 
 func (s *planService) CalculatePlan(ctx context.Context, planID string, opts dto.CalculatePlanRequest) (*dto.CalculationResult, error) {
@@ -725,6 +907,10 @@ func (s *planService) CalculatePlan(ctx context.Context, planID string, opts dto
 		return nil, fmt.Errorf("plan not found: %w", err)
 	}
 	plan := scope.plan
+	from := planStatusOf(plan)
+	if !from.Calculable() {
+		return nil, fmt.Errorf("%w: a %s plan cannot be recalculated", ErrInvalidStatusTransition, from)
+	}
 
 	items, err := s.q.ListLoadItems(ctx, &plan.PlanID)
 	if err != nil {
@@ -732,6 +918,147 @@ func (s *planService) CalculatePlan(ctx context.Context, planID string, opts dto
 	}
 
 	// 2. Prepare Inputs
+	contInput, itemInputs := buildPackInputs(plan, items, opts)
+
+	// 3. Run Packing
+	p, err := s.packerFor(opts.Backend)
+	if err != nil {
+		return nil, err
+	}
+	res, err := packer.PackCompartments(ctx, p, contInput, itemInputs)
+	if err != nil {
+		return nil, fmt.Errorf("packing failed: %w", err)
+	}
+	packer.ReplayPlacements(ctx, contInput, res)
+
+	// 4. Save Results as the plan's new active version; older versions are kept.
+	inputs, err := encodeResultInputs(plan, items, opts)
+	if err != nil {
+		return nil, err
+	}
+	var savedRes store.PlanResult
+	err = inTx(ctx, s.q, func(q store.Querier) error {
+		var err error
+		if savedRes, err = saveResultVersion(ctx, q, scope, res, inputs); err != nil {
+			return err
+		}
+		// A caller that gave up meanwhile, e.g. a cancelled calculation job,
+		// must not find the result active afterwards: roll it back.
+		return ctx.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 5. Map DTO
+	return calculationResult(planID, savedRes, contInput, itemInputs, res, opts), nil
+}
+
+// calculationResult describes a saved packing result of a plan.
+func calculationResult(planID string, savedRes store.PlanResult, contInput packer.ContainerInput, itemInputs []packer.ItemInput, res packer.PackingResult, opts dto.CalculatePlanRequest) *dto.CalculationResult {
+	var plDTOs []dto.PlacementDetail
+	for i, pItem := range res.PackedItems {
+		plDTOs = append(plDTOs, dto.PlacementDetail{
+			PlacementID: "", // Not generated yet
+			ItemID:      pItem.ItemID,
+			PositionX:   pItem.Position.X,
+			PositionY:   pItem.Position.Y,
+			PositionZ:   pItem.Position.Z,
+			Rotation:    pItem.RotationType,
+			StepNumber:  i + 1,
+		})
+	}
+
+	return &dto.CalculationResult{
+		JobID:             savedRes.ResultID.String(),
+		Version:           int(savedRes.Version),
+		Status:            resultStatus(res.IsFeasible).String(),
+		Algorithm:         res.Algorithm,
+		EfficiencyScore:   res.VolumeUtilisationPct,
+		VolumeUtilization: res.VolumeUtilisationPct,
+		WeightUtilization: res.WeightUtilisationPct,
+		DurationMs:        res.DurationMs,
+		VisualizationURL:  "/visualizer?plan=" + planID,
+		CacheHit:          res.CacheHit,
+		Placements:        plDTOs,
+		VoidAnalysis:      analyzeVoids(contInput, res, opts),
+		UnfitItems:        mapUnfitItems(contInput, res),
+		Securing:          planSecuring(contInput, res.PackedItems, itemInputs),
+		FloorLoad:         planFloorLoad(contInput, res.PackedItems, itemInputs),
+		Handling:          planHandling(res.PackedItems, itemInputs),
+	}
+}
+
+// saveResultVersion stores res as the new active result version of the plan
+// in scope, with its placements, and moves the plan to PLANNED or PARTIAL.
+// The plan row stays locked until q's transaction ends, so calculations of a
+// plan number their versions one at a time.
+func saveResultVersion(ctx context.Context, q store.Querier, scope *planScope, res packer.PackingResult, inputs []byte) (store.PlanResult, error) {
+	pID := scope.plan.PlanID
+	from, err := lockPlanStatus(ctx, q, pID)
+	if err != nil {
+		return store.PlanResult{}, err
+	}
+	if !from.Calculable() {
+		return store.PlanResult{}, fmt.Errorf("%w: a %s plan cannot be recalculated", ErrInvalidStatusTransition, from)
+	}
+
+	if err := q.DeactivatePlanResults(ctx, &pID); err != nil {
+		return store.PlanResult{}, fmt.Errorf("failed to save result: %w", err)
+	}
+	saved, err := q.CreatePlanResult(ctx, store.CreatePlanResultParams{
+		PlanID:               &pID,
+		TotalLoadedWeightKg:  toNumeric(res.TotalWeightPackedKG),
+		VolumeUtilizationPct: toNumeric(res.VolumeUtilisationPct),
+		IsFeasible:           &res.IsFeasible,
+		Algorithm:            &res.Algorithm,
+		Inputs:               inputs,
+	})
+	if err != nil {
+		return store.PlanResult{}, fmt.Errorf("failed to save result: %w", err)
+	}
+
+	placements := make([]store.CreatePlanPlacementParams, 0, len(res.PackedItems))
+	for i, pItem := range res.PackedItems {
+		itemID, _ := uuid.Parse(pItem.ItemID)
+		rID := saved.ResultID
+		rot := int32(pItem.RotationType)
+		placements = append(placements, store.CreatePlanPlacementParams{
+			ResultID:     &rID,
+			ItemID:       &itemID,
+			PosX:         toNumeric(pItem.Position.X),
+			PosY:         toNumeric(pItem.Position.Y),
+			PosZ:         toNumeric(pItem.Position.Z),
+			RotationCode: &rot,
+			StepNumber:   int32(i + 1),
+		})
+	}
+	if len(placements) > 0 {
+		if _, err := q.CreatePlanPlacement(ctx, placements); err != nil {
+			return store.PlanResult{}, fmt.Errorf("failed to save placements: %w", err)
+		}
+	}
+
+	if err := setPlanStatus(ctx, q, pID, scope.workspaceID, from, resultStatus(res.IsFeasible), nil); err != nil {
+		return store.PlanResult{}, fmt.Errorf("failed to update plan status: %w", err)
+	}
+	return saved, nil
+}
+
+// markPlanFailed moves a plan from status from to FAILED, ignoring errors.
+func (s *planService) markPlanFailed(ctx context.Context, planID uuid.UUID, from types.PlanStatus) {
+	var workspaceID *uuid.UUID
+	if !isFounder(ctx) {
+		var err error
+		if workspaceID, err = workspaceIDFromContext(ctx); err != nil {
+			return
+		}
+	}
+	_ = setPlanStatus(ctx, s.q, planID, workspaceID, from, types.PlanStatusFailed, nil)
+}
+
+// buildPackInputs converts a stored plan and its items into packer inputs.
+func buildPackInputs(plan store.LoadPlan, items []store.LoadItem, opts dto.CalculatePlanRequest) (packer.ContainerInput, []packer.ItemInput) {
 	gravity := false
 	if opts.Gravity != nil {
 		gravity = *opts.Gravity
@@ -743,6 +1070,18 @@ func (s *planService) CalculatePlan(ctx context.Context, planID string, opts dto
 		Width:     toFloat(plan.WidthMm),
 		Height:    toFloat(plan.HeightMm),
 		MaxWeight: toFloat(plan.MaxWeightKg),
+
+		WallClearance: toFloat(plan.WallClearanceMm),
+		ItemGap:       toFloat(plan.ItemGapMm),
+
+		LashingPointsPerSide: int(plan.LashingPointsPerSide),
+		LashingPointCapacity: toFloat(plan.LashingPointCapacityDan),
+
+		FloorLoadLimit: toFloat(plan.FloorLoadKgM2),
+		LineLoadLimit:  toFloat(plan.LineLoadKgM),
+
+		Compartments: packerCompartments(decodeCompartments(plan.Compartments)),
+
 		Options: packer.PackOptions{
 			Strategy: opts.Strategy,
 			Goal:     opts.Goal,
@@ -751,6 +1090,7 @@ func (s *planService) CalculatePlan(ctx context.Context, planID string, opts dto
 	}
 
 	var itemInputs []packer.ItemInput
+	prioritized := false
 	for _, item := range items {
 		allowRot := true
 		if item.AllowRotation != nil {
@@ -764,6 +1104,7 @@ func (s *planService) CalculatePlan(ctx context.Context, planID string, opts dto
 		itemInputs = append(itemInputs, packer.ItemInput{
 			ID:            item.ItemID.String(),
 			Label:         getString(item.ItemLabel),
+			ProductSKU:    getString(item.ProductSku),
 			Length:        toFloat(item.LengthMm),
 			Width:         toFloat(item.WidthMm),
 			Height:        toFloat(item.HeightMm),
@@ -771,90 +1112,85 @@ func (s *planService) CalculatePlan(ctx context.Context, planID string, opts dto
 			Quantity:      int(item.Quantity),
 			AllowRotation: allowRot,
 			Color:         color,
+			Padding:       toFloat(item.PaddingMm),
+			Priority:      int(item.Priority),
+			MustShip:      item.MustShip,
+			Friction:      toFloat(item.FrictionCoefficient),
+
+			TemperatureClass: getString(item.TemperatureClass),
+
+			Fragile:      item.Fragile,
+			ThisSideUp:   item.ThisSideUp,
+			NoStack:      !item.Stackable,
+			MaxStackLoad: toFloat(item.MaxStackLoadKg),
+			HazmatClass:  getString(item.HazmatClass),
 		})
+		if item.Priority != 0 || item.MustShip {
+			prioritized = true
+		}
 	}
-
-	// 3. Run Packing
-	res, err := s.p.Pack(ctx, contInput, itemInputs)
-	if err != nil {
-		return nil, fmt.Errorf("packing failed: %w", err)
+	if opts.Prioritize != nil {
+		prioritized = *opts.Prioritize
 	}
+	contInput.Options.Prioritize = prioritized
+	return contInput, itemInputs
+}
 
-	// 4. Save Results
-	// Delete old results first? Usually only one active result.
-	_ = s.q.DeletePlanResults(ctx, &pID)
-
-	savedRes, err := s.q.CreatePlanResult(ctx, store.CreatePlanResultParams{
-		PlanID:               &pID,
-		TotalLoadedWeightKg:  toNumeric(res.TotalWeightPackedKG),
-		VolumeUtilizationPct: toNumeric(res.VolumeUtilisationPct),
-		IsFeasible:           &res.IsFeasible,
+// CalculatePlanWithProgress runs CalculatePlan and forwards packer progress to onProgress.
+func (s *planService) CalculatePlanWithProgress(ctx context.Context, planID string, opts dto.CalculatePlanRequest, onProgress func(dto.CalculationProgress)) (*dto.CalculationResult, error) {
+	ctx = packer.WithProgress(ctx, func(p packer.Progress) {
+		onProgress(mapPackProgress(p))
 	})
-	if err != nil {
-		return nil, fmt.Errorf("failed to save result: %w", err)
-	}
-
-	// 5. Save Placements (Bulk) AND map DTO
-	var placements []store.CreatePlanPlacementParams
-	var plDTOs []dto.PlacementDetail
-
-	for i, pItem := range res.PackedItems {
-		itemID, _ := uuid.Parse(pItem.ItemID)
-
-		rID := savedRes.ResultID
-		iID := itemID
-		rot := int32(pItem.RotationType)
-
-		placements = append(placements, store.CreatePlanPlacementParams{
-			ResultID:     &rID,
-			ItemID:       &iID,
-			PosX:         toNumeric(pItem.Position.X),
-			PosY:         toNumeric(pItem.Position.Y),
-			PosZ:         toNumeric(pItem.Position.Z),
-			RotationCode: &rot,
-			StepNumber:   int32(i + 1),
-		})
+	return s.CalculatePlan(ctx, planID, opts)
+}
 
-		plDTOs = append(plDTOs, dto.PlacementDetail{
-			PlacementID: "", // Not generated yet
-			ItemID:      pItem.ItemID,
-			PositionX:   pItem.Position.X,
-			PositionY:   pItem.Position.Y,
-			PositionZ:   pItem.Position.Z,
-			Rotation:    pItem.RotationType,
-			StepNumber:  i + 1,
+func mapPackProgress(p packer.Progress) dto.CalculationProgress {
+	out := dto.CalculationProgress{
+		Stage:                 p.Stage,
+		ItemsTotal:            p.ItemsTotal,
+		ItemsPlaced:           p.ItemsPlaced,
+		VolumeUtilization:     p.VolumeUtilisationPct,
+		BestAlgorithm:         p.BestAlgorithm,
+		BestVolumeUtilization: p.BestVolumeUtilisationPct,
+	}
+
+	// NewItems are the tail of the placed items, so their step numbers follow on
+	// from the ones already sent.
+	firstStep := p.ItemsPlaced - len(p.NewItems) + 1
+	for i, it := range p.NewItems {
+		out.Placements = append(out.Placements, dto.PlacementDetail{
+			ItemID:     it.ItemID,
+			PositionX:  it.Position.X,
+			PositionY:  it.Position.Y,
+			PositionZ:  it.Position.Z,
+			Rotation:   it.RotationType,
+			StepNumber: firstStep + i,
 		})
 	}
+	return out
+}
 
-	if len(placements) > 0 {
-		_, err := s.q.CreatePlanPlacement(ctx, placements)
-		if err != nil {
-			return nil, fmt.Errorf("failed to save placements: %w", err)
+// mapUnfitItems lists the units left out of res, the constraint that kept
+// each one out and a diagnosis of why.
+func mapUnfitItems(container packer.ContainerInput, res packer.PackingResult) []dto.UnfitItemInfo {
+	diagnoses := packer.DiagnoseUnfit(container, res)
+	out := make([]dto.UnfitItemInfo, 0, len(res.UnfitItems))
+	for i, u := range res.UnfitItems {
+		info := dto.UnfitItemInfo{
+			ItemID:    u.ID,
+			Quantity:  u.Quantity,
+			MustShip:  u.MustShip,
+			Reason:    packer.UnfitConstraint(container, res, u),
+			Diagnosis: diagnoses[i].Code,
+			Message:   diagnoses[i].Message,
 		}
+		if u.Label != "" {
+			label := u.Label
+			info.Label = &label
+		}
+		out = append(out, info)
 	}
-
-	// 6. Update Status
-	newStatus := types.PlanStatusCompleted.String()
-	if !res.IsFeasible {
-		newStatus = types.PlanStatusPartial.String()
-	}
-	if isFounder(ctx) && scope.workspaceID == nil {
-		_ = s.q.UpdatePlanStatusAny(ctx, store.UpdatePlanStatusAnyParams{PlanID: pID, Status: &newStatus})
-	} else {
-		_ = s.q.UpdatePlanStatus(ctx, store.UpdatePlanStatusParams{PlanID: pID, WorkspaceID: scope.workspaceID, Status: &newStatus})
-	}
-
-	// 7. Return DTO
-	return &dto.CalculationResult{
-		JobID:             savedRes.ResultID.String(),
-		Status:            types.PlanStatusCompleted.String(), // Status from packer result, or from DB
-		Algorithm:         res.Algorithm,
-		EfficiencyScore:   res.VolumeUtilisationPct,
-		VolumeUtilization: res.VolumeUtilisationPct,
-		DurationMs:        res.DurationMs,
-		VisualizationURL:  "/visualizer?plan=" + planID,
-		Placements:        plDTOs,
-	}, nil
+	return out
 }
 
 func mapLoadItemToDetail(i store.LoadItem) *dto.PlanItemDetail {
@@ -884,5 +1220,22 @@ func mapLoadItemToDetail(i store.LoadItem) *dto.PlanItemDetail {
 		TotalVolumeM3: vol,
 		AllowRotation: allowRot,
 		ColorHex:      i.ColorHex,
+		PaddingMM:     toFloat(i.PaddingMm),
+		Priority:      int(i.Priority),
+		MustShip:      i.MustShip,
+
+		FrictionCoefficient: toOptionalFloat(i.FrictionCoefficient),
+		TemperatureClass:    i.TemperatureClass,
+		GTIN:                i.Gtin,
+
+		ProductSKU:        i.ProductSku,
+		ProductID:         uuidString(i.ProductID),
+		ProductOverridden: i.ProductOverridden,
+
+		HandlingAttributes: mapHandling(i.Fragile, i.ThisSideUp, i.Stackable, i.MaxStackLoadKg, i.HazmatClass, i.PackagingType),
+
+		Unit:          i.Unit,
+		EachesPerUnit: toOptionalInt(i.EachesPerUnit),
+		OrderedEaches: toOptionalInt(i.OrderedEaches),
 	}
 }
@@ -2,6 +2,8 @@ package service_test
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
 	"fmt"
 	"testing"
 	"time"
@@ -15,6 +17,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func authedPlannerCtx() context.Context {
@@ -106,6 +109,65 @@ func TestPlanService_CreateCompletePlan(t *testing.T) {
 		assert.Equal(t, types.PlanStatusDraft.String(), resp.Status)
 	})
 
+	t.Run("auto_calculate_reports_partial", func(t *testing.T) {
+		contID := uuid.New()
+		plan := store.LoadPlan{
+			PlanID:      planID,
+			LengthMm:    toNumeric(1000.0),
+			WidthMm:     toNumeric(1000.0),
+			HeightMm:    toNumeric(1000.0),
+			MaxWeightKg: toNumeric(100.0),
+			CreatedAt:   pgtype.Timestamp{Time: time.Now(), Valid: true},
+		}
+		var saved string
+		mockQ := &MockQuerier{
+			GetContainerFunc: func(ctx context.Context, arg store.GetContainerParams) (store.Container, error) {
+				return store.Container{ContainerID: contID, Name: "Box", InnerLengthMm: toNumeric(1000.0), InnerWidthMm: toNumeric(1000.0), InnerHeightMm: toNumeric(1000.0), MaxWeightKg: toNumeric(100.0)}, nil
+			},
+			CreateLoadPlanFunc: func(ctx context.Context, arg store.CreateLoadPlanParams) (store.LoadPlan, error) {
+				return plan, nil
+			},
+			AddLoadItemFunc: func(ctx context.Context, arg store.AddLoadItemParams) (store.LoadItem, error) {
+				return store.LoadItem{ItemID: uuid.New()}, nil
+			},
+			GetLoadPlanFunc: func(ctx context.Context, arg store.GetLoadPlanParams) (store.LoadPlan, error) {
+				return plan, nil
+			},
+			ListLoadItemsFunc: func(ctx context.Context, id *uuid.UUID) ([]store.LoadItem, error) {
+				return nil, nil
+			},
+			LockLoadPlanFunc:            lockPlanAs(nil),
+			CreatePlanStatusHistoryFunc: acceptStatusHistory,
+			DeactivatePlanResultsFunc: func(ctx context.Context, id *uuid.UUID) error {
+				return nil
+			},
+			CreatePlanResultFunc: func(ctx context.Context, arg store.CreatePlanResultParams) (store.PlanResult, error) {
+				return store.PlanResult{ResultID: uuid.New(), IsFeasible: arg.IsFeasible}, nil
+			},
+			UpdatePlanStatusFunc: func(ctx context.Context, arg store.UpdatePlanStatusParams) error {
+				saved = *arg.Status
+				return nil
+			},
+		}
+		mp := &MockPacker{PackFunc: func(ctx context.Context, container packer.ContainerInput, items []packer.ItemInput) (packer.PackingResult, error) {
+			return packer.PackingResult{UnfitItems: []packer.ItemInput{{ID: "A", Quantity: 1}}}, nil
+		}}
+
+		s := service.NewPlanService(mockQ, mp)
+		resp, err := s.CreateCompletePlan(authedPlannerCtx(), dto.CreatePlanRequest{
+			Title:     "Auto",
+			Container: dto.CreatePlanContainer{ContainerID: stringPtr(contID.String())},
+			Items:     []dto.CreatePlanItem{itemReq},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, types.PlanStatusPartial.String(), saved)
+		assert.Equal(t, types.PlanStatusPartial.String(), resp.Status)
+		if assert.NotNil(t, resp.Calculation) {
+			assert.Equal(t, types.PlanStatusPartial.String(), resp.Calculation.Status)
+		}
+	})
+
 	t.Run("founder_with_override_uses_override_workspace", func(t *testing.T) {
 		contID := uuid.New()
 		workspaceID := uuid.New()
@@ -414,6 +476,52 @@ func TestPlanService_GetPlan(t *testing.T) {
 		assert.Equal(t, 2, resp.Stats.TotalItems)
 	})
 
+	t.Run("sku_stats", func(t *testing.T) {
+		tvA, tvB, loose := uuid.New(), uuid.New(), uuid.New()
+		resultID := uuid.New()
+		mockQ := &MockQuerier{
+			GetLoadPlanFunc: func(ctx context.Context, arg store.GetLoadPlanParams) (store.LoadPlan, error) {
+				return store.LoadPlan{
+					PlanID:      planID,
+					LengthMm:    toNumeric(5000),
+					WidthMm:     toNumeric(2000),
+					HeightMm:    toNumeric(2000),
+					MaxWeightKg: toNumeric(10000),
+				}, nil
+			},
+			ListLoadItemsFunc: func(ctx context.Context, id *uuid.UUID) ([]store.LoadItem, error) {
+				box := func(id uuid.UUID, sku *string, qty int32) store.LoadItem {
+					return store.LoadItem{ItemID: id, ProductSku: sku, Quantity: qty, LengthMm: toNumeric(1000), WidthMm: toNumeric(1000), HeightMm: toNumeric(1000), WeightKg: toNumeric(10)}
+				}
+				return []store.LoadItem{
+					box(tvA, stringPtr("TV55"), 2),
+					box(tvB, stringPtr("TV55"), 1),
+					box(loose, nil, 1),
+				}, nil
+			},
+			GetPlanResultFunc: func(ctx context.Context, id *uuid.UUID) (store.PlanResult, error) {
+				return store.PlanResult{ResultID: resultID}, nil
+			},
+			ListPlanPlacementsFunc: func(ctx context.Context, id *uuid.UUID) ([]store.PlanPlacement, error) {
+				return []store.PlanPlacement{
+					{PlacementID: uuid.New(), ItemID: &tvA, PosX: toNumeric(0), PosY: toNumeric(0), PosZ: toNumeric(0), StepNumber: 1},
+					{PlacementID: uuid.New(), ItemID: &tvB, PosX: toNumeric(1000), PosY: toNumeric(0), PosZ: toNumeric(0), StepNumber: 2},
+				}, nil
+			},
+		}
+
+		s := service.NewPlanService(mockQ, packer.NewPacker())
+		resp, err := s.GetPlan(authedPlannerCtx(), planID.String())
+		require.NoError(t, err)
+		require.Len(t, resp.Stats.SKUs, 1)
+		sku := resp.Stats.SKUs[0]
+		assert.Equal(t, "TV55", sku.SKU)
+		assert.Equal(t, 3, sku.Quantity)
+		assert.Equal(t, 2, sku.PlacedUnits)
+		assert.InDelta(t, 30.0, sku.TotalWeightKG, 1e-9)
+		assert.InDelta(t, 3.0, sku.TotalVolumeM3, 1e-9)
+	})
+
 	t.Run("trial_scoped", func(t *testing.T) {
 		guestID := uuid.New()
 		getGuestCalled := false
@@ -546,7 +654,7 @@ func TestPlanService_GetPlan(t *testing.T) {
 		assert.NotNil(t, resp)
 		assert.NotNil(t, resp.Calculation)
 		assert.Equal(t, resultID.String(), resp.Calculation.JobID)
-		assert.Equal(t, types.PlanStatusCompleted.String(), resp.Calculation.Status)
+		assert.Equal(t, types.PlanStatusPlanned.String(), resp.Calculation.Status)
 		assert.Equal(t, 75.5, resp.Calculation.VolumeUtilization)
 		assert.Len(t, resp.Calculation.Placements, 1)
 		assert.Equal(t, itemID.String(), resp.Calculation.Placements[0].ItemID)
@@ -743,9 +851,10 @@ func TestPlanService_ListPlans(t *testing.T) {
 func TestPlanService_UpdatePlan(t *testing.T) {
 	planID := uuid.New()
 	contID := uuid.New()
-	statusNew := types.PlanStatusCompleted.String()
+	statusNew := types.PlanStatusCancelled.String()
 
 	t.Run("success_update_status", func(t *testing.T) {
+		var history *store.CreatePlanStatusHistoryParams
 		mockQ := &MockQuerier{
 			GetLoadPlanFunc: func(ctx context.Context, arg store.GetLoadPlanParams) (store.LoadPlan, error) {
 				return store.LoadPlan{
@@ -759,21 +868,35 @@ func TestPlanService_UpdatePlan(t *testing.T) {
 					MaxWeightKg: toNumeric(1000),
 				}, nil
 			},
+			LockLoadPlanFunc: lockPlanAs(nil),
 			UpdateLoadPlanFunc: func(ctx context.Context, arg store.UpdateLoadPlanParams) error {
 				assert.Equal(t, planID, arg.PlanID)
 				assert.Equal(t, "OLD_CODE", arg.PlanCode)
 				assert.Equal(t, statusNew, *arg.Status)
 				return nil
 			},
+			CreatePlanStatusHistoryFunc: func(ctx context.Context, arg store.CreatePlanStatusHistoryParams) (store.PlanStatusHistory, error) {
+				history = &arg
+				return store.PlanStatusHistory{}, nil
+			},
 		}
 
 		s := service.NewPlanService(mockQ, packer.NewPacker())
 		req := dto.UpdatePlanRequest{
-			Status: stringPtr(statusNew),
+			Status:       stringPtr(statusNew),
+			StatusReason: stringPtr("customer withdrew"),
 		}
 		err := s.UpdatePlan(authedPlannerCtx(), planID.String(), req)
 
 		assert.NoError(t, err)
+		if assert.NotNil(t, history) {
+			assert.Equal(t, planID, history.PlanID)
+			assert.Equal(t, types.PlanStatusDraft.String(), *history.FromStatus)
+			assert.Equal(t, statusNew, history.ToStatus)
+			assert.Equal(t, "customer withdrew", *history.Reason)
+			assert.Equal(t, "user", history.ChangedByType)
+			assert.NotNil(t, history.ChangedByID)
+		}
 	})
 
 	t.Run("success_update_container_preset", func(t *testing.T) {
@@ -800,6 +923,7 @@ func TestPlanService_UpdatePlan(t *testing.T) {
 					MaxWeightKg:   toNumeric(2000),
 				}, nil
 			},
+			LockLoadPlanFunc: lockPlanAs(nil),
 			UpdateLoadPlanFunc: func(ctx context.Context, arg store.UpdateLoadPlanParams) error {
 				assert.Equal(t, "New Cont", *arg.ContLabel)
 				assert.Equal(t, toNumeric(2000), arg.LengthMm)
@@ -891,6 +1015,7 @@ func TestPlanService_UpdatePlan(t *testing.T) {
 					PlanCode: "CODE",
 				}, nil
 			},
+			LockLoadPlanFunc: lockPlanAs(nil),
 			UpdateLoadPlanFunc: func(ctx context.Context, arg store.UpdateLoadPlanParams) error {
 				assert.Equal(t, toNumeric(lengthMM), arg.LengthMm)
 				assert.Equal(t, toNumeric(widthMM), arg.WidthMm)
@@ -918,6 +1043,7 @@ func TestPlanService_UpdatePlan(t *testing.T) {
 			GetLoadPlanFunc: func(ctx context.Context, arg store.GetLoadPlanParams) (store.LoadPlan, error) {
 				return store.LoadPlan{PlanID: planID, PlanCode: "CODE"}, nil
 			},
+			LockLoadPlanFunc: lockPlanAs(nil),
 			UpdateLoadPlanFunc: func(ctx context.Context, arg store.UpdateLoadPlanParams) error {
 				return fmt.Errorf("database update error")
 			},
@@ -928,6 +1054,163 @@ func TestPlanService_UpdatePlan(t *testing.T) {
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "database update error")
 	})
+
+	t.Run("error_container_of_locked_plan", func(t *testing.T) {
+		for _, status := range []types.PlanStatus{types.PlanStatusInProgress, types.PlanStatusCompleted} {
+			mockQ := &MockQuerier{
+				GetLoadPlanFunc: func(ctx context.Context, arg store.GetLoadPlanParams) (store.LoadPlan, error) {
+					return store.LoadPlan{PlanID: planID, PlanCode: "CODE", Status: stringPtr(types.PlanStatusPlanned.String())}, nil
+				},
+				// The status is read again under the lock.
+				LockLoadPlanFunc: lockPlanAs(stringPtr(status.String())),
+			}
+			s := service.NewPlanService(mockQ, packer.NewPacker())
+			lengthMM := 1500.0
+			req := dto.UpdatePlanRequest{Container: &dto.CreatePlanContainer{LengthMM: &lengthMM}}
+			err := s.UpdatePlan(authedPlannerCtx(), planID.String(), req)
+			assert.ErrorIs(t, err, service.ErrInvalidStatusTransition, status)
+		}
+	})
+}
+
+func TestPlanService_StatusTransitions(t *testing.T) {
+	planID := uuid.New()
+	feasible, infeasible := true, false
+
+	newQuerier := func(status types.PlanStatus, isFeasible *bool) *MockQuerier {
+		return &MockQuerier{
+			GetLoadPlanFunc: func(ctx context.Context, arg store.GetLoadPlanParams) (store.LoadPlan, error) {
+				return store.LoadPlan{
+					PlanID:      planID,
+					PlanCode:    "CODE",
+					Status:      stringPtr(status.String()),
+					LengthMm:    toNumeric(1000),
+					WidthMm:     toNumeric(1000),
+					HeightMm:    toNumeric(1000),
+					MaxWeightKg: toNumeric(1000),
+				}, nil
+			},
+			GetPlanResultFunc: func(ctx context.Context, id *uuid.UUID) (store.PlanResult, error) {
+				if isFeasible == nil {
+					return store.PlanResult{}, fmt.Errorf("no rows in result set")
+				}
+				return store.PlanResult{PlanID: id, IsFeasible: isFeasible}, nil
+			},
+			LockLoadPlanFunc: lockPlanAs(stringPtr(status.String())),
+			UpdateLoadPlanFunc: func(ctx context.Context, arg store.UpdateLoadPlanParams) error {
+				return nil
+			},
+			CreatePlanStatusHistoryFunc: func(ctx context.Context, arg store.CreatePlanStatusHistoryParams) (store.PlanStatusHistory, error) {
+				return store.PlanStatusHistory{}, nil
+			},
+			AbandonLoadingSessionsFunc: func(ctx context.Context, id uuid.UUID) error {
+				return nil
+			},
+			ListLoadingSessionsFunc: func(ctx context.Context, id uuid.UUID) ([]store.LoadingSession, error) {
+				return nil, nil
+			},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		from       types.PlanStatus
+		to         types.PlanStatus
+		isFeasible *bool
+		wantErr    string
+	}{
+		{"uncalculated_to_completed", types.PlanStatusDraft, types.PlanStatusCompleted, nil, "DRAFT to COMPLETED"},
+		{"planned_to_completed_skips_loading", types.PlanStatusPlanned, types.PlanStatusCompleted, &feasible, "PLANNED to COMPLETED"},
+		{"draft_to_planned_without_result", types.PlanStatusDraft, types.PlanStatusPlanned, nil, "requires a calculated plan"},
+		{"partial_to_planned", types.PlanStatusPartial, types.PlanStatusPlanned, &infeasible, "requires a feasible calculation result"},
+		{"manual_failed", types.PlanStatusPlanned, types.PlanStatusFailed, &feasible, "set when a calculation fails"},
+		{"completed_is_final", types.PlanStatusCompleted, types.PlanStatusCancelled, &feasible, "COMPLETED to CANCELLED"},
+		{"in_progress_infeasible_to_completed", types.PlanStatusInProgress, types.PlanStatusCompleted, &infeasible, "requires a feasible calculation result"},
+		{"planned_to_in_progress", types.PlanStatusPlanned, types.PlanStatusInProgress, &feasible, ""},
+		{"partial_to_in_progress", types.PlanStatusPartial, types.PlanStatusInProgress, &infeasible, ""},
+		{"in_progress_to_completed_without_loading", types.PlanStatusInProgress, types.PlanStatusCompleted, &feasible, "requires a completed loading session"},
+		{"cancelled_reopened", types.PlanStatusCancelled, types.PlanStatusDraft, nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := service.NewPlanService(newQuerier(tt.from, tt.isFeasible), packer.NewPacker())
+			err := s.UpdatePlan(authedPlannerCtx(), planID.String(), dto.UpdatePlanRequest{Status: stringPtr(tt.to.String())})
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, service.ErrInvalidStatusTransition)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+
+	t.Run("in_progress_to_completed_after_loading", func(t *testing.T) {
+		resultID := uuid.New()
+		mockQ := newQuerier(types.PlanStatusInProgress, &feasible)
+		mockQ.GetPlanResultFunc = func(ctx context.Context, id *uuid.UUID) (store.PlanResult, error) {
+			return store.PlanResult{ResultID: resultID, PlanID: id, IsFeasible: &feasible}, nil
+		}
+		stale := uuid.New()
+		sessions := []store.LoadingSession{{PlanID: planID, ResultID: &stale, Status: "completed"}}
+		mockQ.ListLoadingSessionsFunc = func(ctx context.Context, id uuid.UUID) ([]store.LoadingSession, error) {
+			return sessions, nil
+		}
+		s := service.NewPlanService(mockQ, packer.NewPacker())
+		req := dto.UpdatePlanRequest{Status: stringPtr(types.PlanStatusCompleted.String())}
+
+		// A session of an earlier result does not count.
+		err := s.UpdatePlan(authedPlannerCtx(), planID.String(), req)
+		assert.ErrorIs(t, err, service.ErrInvalidStatusTransition)
+
+		sessions = append(sessions, store.LoadingSession{PlanID: planID, ResultID: &resultID, Status: "completed"})
+		assert.NoError(t, s.UpdatePlan(authedPlannerCtx(), planID.String(), req))
+	})
+
+	t.Run("leaving_in_progress_abandons_loading", func(t *testing.T) {
+		abandoned := false
+		mockQ := newQuerier(types.PlanStatusInProgress, &feasible)
+		mockQ.AbandonLoadingSessionsFunc = func(ctx context.Context, id uuid.UUID) error {
+			assert.Equal(t, planID, id)
+			abandoned = true
+			return nil
+		}
+		s := service.NewPlanService(mockQ, packer.NewPacker())
+		err := s.UpdatePlan(authedPlannerCtx(), planID.String(), dto.UpdatePlanRequest{Status: stringPtr(types.PlanStatusPlanned.String())})
+		require.NoError(t, err)
+		assert.True(t, abandoned)
+	})
+
+	t.Run("in_progress_plan_is_not_recalculated", func(t *testing.T) {
+		mockQ := newQuerier(types.PlanStatusInProgress, &feasible)
+		mockQ.ListLoadItemsFunc = func(ctx context.Context, id *uuid.UUID) ([]store.LoadItem, error) {
+			t.Fatal("items must not be loaded")
+			return nil, nil
+		}
+		s := service.NewPlanService(mockQ, packer.NewPacker())
+		_, err := s.CalculatePlan(authedPlannerCtx(), planID.String(), dto.CalculatePlanRequest{})
+		assert.ErrorIs(t, err, service.ErrInvalidStatusTransition)
+	})
+
+	t.Run("history", func(t *testing.T) {
+		changedBy := uuid.New()
+		mockQ := newQuerier(types.PlanStatusDraft, nil)
+		mockQ.ListPlanStatusHistoryFunc = func(ctx context.Context, id uuid.UUID) ([]store.PlanStatusHistory, error) {
+			assert.Equal(t, planID, id)
+			return []store.PlanStatusHistory{
+				{PlanID: planID, FromStatus: stringPtr("DRAFT"), ToStatus: "PLANNED", ChangedByType: "user", ChangedByID: &changedBy, ChangedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)},
+				{PlanID: planID, FromStatus: stringPtr("PLANNED"), ToStatus: "FAILED", ChangedByType: "system"},
+			}, nil
+		}
+		s := service.NewPlanService(mockQ, packer.NewPacker())
+		got, err := s.GetPlanStatusHistory(authedPlannerCtx(), planID.String())
+		require.NoError(t, err)
+		require.Len(t, got, 2)
+		assert.Equal(t, "PLANNED", got[0].ToStatus)
+		assert.Equal(t, changedBy.String(), *got[0].ChangedByID)
+		assert.Equal(t, "2026-01-02T03:04:05Z", got[0].ChangedAt)
+		assert.Nil(t, got[1].ChangedByID)
+	})
 }
 
 func TestPlanService_DeletePlan(t *testing.T) {
@@ -981,6 +1264,7 @@ func TestPlanService_AddPlanItem(t *testing.T) {
 			GetLoadPlanFunc: func(ctx context.Context, arg store.GetLoadPlanParams) (store.LoadPlan, error) {
 				return store.LoadPlan{PlanID: planID, WorkspaceID: arg.WorkspaceID}, nil
 			},
+			LockLoadPlanFunc: lockPlanAs(nil),
 			AddLoadItemFunc: func(ctx context.Context, arg store.AddLoadItemParams) (store.LoadItem, error) {
 				return store.LoadItem{
 					ItemID:        uuid.New(),
@@ -1050,6 +1334,7 @@ func TestPlanService_AddPlanItem(t *testing.T) {
 			GetLoadPlanFunc: func(ctx context.Context, arg store.GetLoadPlanParams) (store.LoadPlan, error) {
 				return store.LoadPlan{PlanID: planID, WorkspaceID: arg.WorkspaceID}, nil
 			},
+			LockLoadPlanFunc: lockPlanAs(nil),
 			AddLoadItemFunc: func(ctx context.Context, arg store.AddLoadItemParams) (store.LoadItem, error) {
 				return store.LoadItem{}, fmt.Errorf("db error")
 			},
@@ -1094,6 +1379,7 @@ func TestPlanService_UpdatePlanItem(t *testing.T) {
 			GetLoadPlanFunc: func(ctx context.Context, arg store.GetLoadPlanParams) (store.LoadPlan, error) {
 				return store.LoadPlan{PlanID: planID, WorkspaceID: arg.WorkspaceID}, nil
 			},
+			LockLoadPlanFunc: lockPlanAs(nil),
 			GetLoadItemFunc: func(ctx context.Context, arg store.GetLoadItemParams) (store.LoadItem, error) {
 				return initialItem, nil
 			},
@@ -1126,6 +1412,7 @@ func TestPlanService_UpdatePlanItem(t *testing.T) {
 			GetLoadPlanFunc: func(ctx context.Context, arg store.GetLoadPlanParams) (store.LoadPlan, error) {
 				return store.LoadPlan{PlanID: planID, WorkspaceID: arg.WorkspaceID}, nil
 			},
+			LockLoadPlanFunc: lockPlanAs(nil),
 			GetLoadItemFunc: func(ctx context.Context, arg store.GetLoadItemParams) (store.LoadItem, error) {
 				return initialItem, nil
 			},
@@ -1154,6 +1441,7 @@ func TestPlanService_UpdatePlanItem(t *testing.T) {
 			GetLoadPlanFunc: func(ctx context.Context, arg store.GetLoadPlanParams) (store.LoadPlan, error) {
 				return store.LoadPlan{PlanID: planID, WorkspaceID: arg.WorkspaceID}, nil
 			},
+			LockLoadPlanFunc: lockPlanAs(nil),
 			GetLoadItemFunc: func(ctx context.Context, arg store.GetLoadItemParams) (store.LoadItem, error) {
 				return store.LoadItem{}, fmt.Errorf("item not found")
 			},
@@ -1167,6 +1455,226 @@ func TestPlanService_UpdatePlanItem(t *testing.T) {
 	})
 }
 
+func TestPlanService_PlanItemProducts(t *testing.T) {
+	planID := uuid.New()
+	productID := uuid.New()
+	product := store.Product{
+		ProductID:        productID,
+		Name:             "TV LED 55 inch",
+		Sku:              stringPtr("TV55-001"),
+		LengthMm:         toNumeric(1300),
+		WidthMm:          toNumeric(800),
+		HeightMm:         toNumeric(200),
+		WeightKg:         toNumeric(25.5),
+		ColorHex:         stringPtr("#ff5733"),
+		TemperatureClass: stringPtr("ambient"),
+		ThisSideUp:       true,
+		Stackable:        true,
+		MaxStackLoadKg:   toNumeric(60),
+		PackagingType:    stringPtr("carton"),
+	}
+
+	newMock := func(added *store.AddLoadItemParams) *MockQuerier {
+		return &MockQuerier{
+			GetLoadPlanFunc: func(ctx context.Context, arg store.GetLoadPlanParams) (store.LoadPlan, error) {
+				return store.LoadPlan{PlanID: planID, WorkspaceID: arg.WorkspaceID}, nil
+			},
+			LockLoadPlanFunc: lockPlanAs(nil),
+			GetProductFunc: func(ctx context.Context, arg store.GetProductParams) (store.Product, error) {
+				if arg.ProductID != productID {
+					return store.Product{}, fmt.Errorf("no rows")
+				}
+				return product, nil
+			},
+			GetProductBySkuFunc: func(ctx context.Context, arg store.GetProductBySkuParams) (store.Product, error) {
+				if arg.Sku == nil || *arg.Sku != "TV55-001" {
+					return store.Product{}, fmt.Errorf("no rows")
+				}
+				return product, nil
+			},
+			AddLoadItemFunc: func(ctx context.Context, arg store.AddLoadItemParams) (store.LoadItem, error) {
+				*added = arg
+				return store.LoadItem{
+					ItemID:            uuid.New(),
+					ItemLabel:         arg.ItemLabel,
+					LengthMm:          arg.LengthMm,
+					WidthMm:           arg.WidthMm,
+					HeightMm:          arg.HeightMm,
+					WeightKg:          arg.WeightKg,
+					Quantity:          arg.Quantity,
+					ColorHex:          arg.ColorHex,
+					ProductID:         arg.ProductID,
+					ProductSku:        arg.ProductSku,
+					ProductOverridden: arg.ProductOverridden,
+				}, nil
+			},
+		}
+	}
+
+	t.Run("fills_item_from_sku", func(t *testing.T) {
+		var added store.AddLoadItemParams
+		s := service.NewPlanService(newMock(&added), packer.NewPacker())
+		req := dto.AddPlanItemRequest{}
+		req.ProductSKU = stringPtr("TV55-001")
+		req.Quantity = 4
+
+		resp, err := s.AddPlanItem(authedPlannerCtx(), planID.String(), req)
+		require.NoError(t, err)
+		assert.Equal(t, "TV LED 55 inch", *resp.Label)
+		assert.Equal(t, 1300.0, resp.LengthMM)
+		assert.Equal(t, 25.5, resp.WeightKG)
+		assert.Equal(t, "#ff5733", *resp.ColorHex)
+		assert.Equal(t, productID.String(), *resp.ProductID)
+		assert.Equal(t, "TV55-001", *resp.ProductSKU)
+		assert.False(t, resp.ProductOverridden)
+		assert.Equal(t, stringPtr("ambient"), added.TemperatureClass)
+		assert.True(t, added.ThisSideUp)
+		assert.True(t, added.Stackable)
+		assert.Equal(t, toNumeric(60), added.MaxStackLoadKg)
+		assert.Equal(t, stringPtr("carton"), added.PackagingType)
+	})
+
+	t.Run("handling_override", func(t *testing.T) {
+		var added store.AddLoadItemParams
+		s := service.NewPlanService(newMock(&added), packer.NewPacker())
+		req := dto.AddPlanItemRequest{}
+		req.ProductSKU = stringPtr("TV55-001")
+		req.Quantity = 1
+		req.Stackable = boolPtr(false)
+
+		_, err := s.AddPlanItem(authedPlannerCtx(), planID.String(), req)
+		require.NoError(t, err)
+		assert.False(t, added.Stackable)
+		assert.True(t, added.ThisSideUp)
+		assert.True(t, added.ProductOverridden)
+	})
+
+	t.Run("explicit_values_override_product", func(t *testing.T) {
+		var added store.AddLoadItemParams
+		s := service.NewPlanService(newMock(&added), packer.NewPacker())
+		req := dto.AddPlanItemRequest{}
+		req.ProductID = stringPtr(productID.String())
+		req.WeightKG = 30
+		req.Quantity = 1
+
+		resp, err := s.AddPlanItem(authedPlannerCtx(), planID.String(), req)
+		require.NoError(t, err)
+		assert.Equal(t, 30.0, resp.WeightKG)
+		assert.Equal(t, 800.0, resp.WidthMM)
+		assert.True(t, resp.ProductOverridden)
+	})
+
+	t.Run("unknown_sku_with_dimensions_is_free_text", func(t *testing.T) {
+		var added store.AddLoadItemParams
+		s := service.NewPlanService(newMock(&added), packer.NewPacker())
+		req := dto.AddPlanItemRequest{}
+		req.ProductSKU = stringPtr("LOCAL-1")
+		req.LengthMM, req.WidthMM, req.HeightMM, req.WeightKG = 100, 100, 100, 1
+		req.Quantity = 1
+
+		resp, err := s.AddPlanItem(authedPlannerCtx(), planID.String(), req)
+		require.NoError(t, err)
+		assert.Nil(t, resp.ProductID)
+		assert.Equal(t, "LOCAL-1", *resp.ProductSKU)
+		assert.Equal(t, "#3498db", *resp.ColorHex)
+	})
+
+	t.Run("converts_to_shipping_unit", func(t *testing.T) {
+		var added store.AddLoadItemParams
+		mockQ := newMock(&added)
+		cased := product
+		cased.ShippingUnit = "case"
+		cased.Packaging = []byte(`[{"level":"inner","contains":6,"length_mm":200,"width_mm":150,"height_mm":100,"weight_kg":1.5,"each_quantity":6},` +
+			`{"level":"case","contains":4,"length_mm":400,"width_mm":300,"height_mm":200,"weight_kg":6,"each_quantity":24}]`)
+		mockQ.GetProductFunc = func(ctx context.Context, arg store.GetProductParams) (store.Product, error) {
+			return cased, nil
+		}
+		s := service.NewPlanService(mockQ, packer.NewPacker())
+
+		// 9 inners are 54 eaches: two full cases and a partial one.
+		req := dto.AddPlanItemRequest{}
+		req.ProductID = stringPtr(productID.String())
+		req.Quantity = 9
+		req.Unit = stringPtr("inner")
+
+		resp, err := s.AddPlanItem(authedPlannerCtx(), planID.String(), req)
+		require.NoError(t, err)
+		assert.Equal(t, int32(3), added.Quantity)
+		assert.Equal(t, 400.0, resp.LengthMM)
+		assert.Equal(t, 6.0, resp.WeightKG)
+		assert.Equal(t, stringPtr("case"), added.Unit)
+		require.NotNil(t, added.EachesPerUnit)
+		assert.Equal(t, int32(24), *added.EachesPerUnit)
+		require.NotNil(t, added.OrderedEaches)
+		assert.Equal(t, int32(54), *added.OrderedEaches)
+		assert.False(t, added.ProductOverridden)
+
+		req.Unit = stringPtr("pallet")
+		_, err = s.AddPlanItem(authedPlannerCtx(), planID.String(), req)
+		assert.ErrorIs(t, err, service.ErrInvalidPackaging)
+	})
+
+	t.Run("unknown_product", func(t *testing.T) {
+		var added store.AddLoadItemParams
+		s := service.NewPlanService(newMock(&added), packer.NewPacker())
+		req := dto.AddPlanItemRequest{}
+		req.ProductID = stringPtr(uuid.New().String())
+		req.Quantity = 1
+
+		_, err := s.AddPlanItem(authedPlannerCtx(), planID.String(), req)
+		assert.ErrorIs(t, err, service.ErrItemProductNotFound)
+	})
+
+	t.Run("dimensions_required_without_product", func(t *testing.T) {
+		var added store.AddLoadItemParams
+		s := service.NewPlanService(newMock(&added), packer.NewPacker())
+		req := dto.AddPlanItemRequest{}
+		req.Label = stringPtr("Loose box")
+		req.LengthMM = 100
+		req.Quantity = 1
+
+		_, err := s.AddPlanItem(authedPlannerCtx(), planID.String(), req)
+		assert.ErrorIs(t, err, service.ErrItemDimensionsRequired)
+	})
+
+	t.Run("update_marks_override", func(t *testing.T) {
+		itemID := uuid.New()
+		var updated store.UpdateLoadItemParams
+		mockQ := &MockQuerier{
+			GetLoadPlanFunc: func(ctx context.Context, arg store.GetLoadPlanParams) (store.LoadPlan, error) {
+				return store.LoadPlan{PlanID: planID, WorkspaceID: arg.WorkspaceID}, nil
+			},
+			LockLoadPlanFunc: lockPlanAs(nil),
+			GetLoadItemFunc: func(ctx context.Context, arg store.GetLoadItemParams) (store.LoadItem, error) {
+				return store.LoadItem{
+					ItemID:    itemID,
+					PlanID:    &planID,
+					ItemLabel: stringPtr("TV LED 55 inch"),
+					LengthMm:  toNumeric(1300),
+					WidthMm:   toNumeric(800),
+					HeightMm:  toNumeric(200),
+					WeightKg:  toNumeric(25.5),
+					Quantity:  4,
+					ProductID: &productID,
+				}, nil
+			},
+			UpdateLoadItemFunc: func(ctx context.Context, arg store.UpdateLoadItemParams) error {
+				updated = arg
+				return nil
+			},
+		}
+		s := service.NewPlanService(mockQ, packer.NewPacker())
+
+		qty := 8
+		require.NoError(t, s.UpdatePlanItem(authedPlannerCtx(), planID.String(), itemID.String(), dto.UpdatePlanItemRequest{Quantity: &qty}))
+		assert.False(t, updated.ProductOverridden)
+
+		height := 250.0
+		require.NoError(t, s.UpdatePlanItem(authedPlannerCtx(), planID.String(), itemID.String(), dto.UpdatePlanItemRequest{HeightMM: &height}))
+		assert.True(t, updated.ProductOverridden)
+	})
+}
+
 func TestPlanService_DeletePlanItem(t *testing.T) {
 	planID := uuid.New()
 	itemID := uuid.New()
@@ -1176,6 +1684,7 @@ func TestPlanService_DeletePlanItem(t *testing.T) {
 			GetLoadPlanFunc: func(ctx context.Context, arg store.GetLoadPlanParams) (store.LoadPlan, error) {
 				return store.LoadPlan{PlanID: planID, WorkspaceID: arg.WorkspaceID}, nil
 			},
+			LockLoadPlanFunc: lockPlanAs(nil),
 			DeleteLoadItemFunc: func(ctx context.Context, arg store.DeleteLoadItemParams) error {
 				assert.Equal(t, planID, *arg.PlanID)
 				assert.Equal(t, itemID, arg.ItemID)
@@ -1193,6 +1702,7 @@ func TestPlanService_DeletePlanItem(t *testing.T) {
 			GetLoadPlanFunc: func(ctx context.Context, arg store.GetLoadPlanParams) (store.LoadPlan, error) {
 				return store.LoadPlan{PlanID: planID, WorkspaceID: arg.WorkspaceID}, nil
 			},
+			LockLoadPlanFunc: lockPlanAs(nil),
 			DeleteLoadItemFunc: func(ctx context.Context, arg store.DeleteLoadItemParams) error {
 				return fmt.Errorf("db error")
 			},
@@ -1203,6 +1713,27 @@ func TestPlanService_DeletePlanItem(t *testing.T) {
 		assert.Contains(t, err.Error(), "failed to delete item")
 	})
 
+	t.Run("locked_plan", func(t *testing.T) {
+		for _, status := range []types.PlanStatus{types.PlanStatusInProgress, types.PlanStatusCompleted, types.PlanStatusCancelled} {
+			mockQ := &MockQuerier{
+				GetLoadPlanFunc: func(ctx context.Context, arg store.GetLoadPlanParams) (store.LoadPlan, error) {
+					return store.LoadPlan{PlanID: planID, WorkspaceID: arg.WorkspaceID}, nil
+				},
+				LockLoadPlanFunc: lockPlanAs(stringPtr(status.String())),
+			}
+			s := service.NewPlanService(mockQ, packer.NewPacker())
+
+			err := s.DeletePlanItem(authedPlannerCtx(), planID.String(), itemID.String())
+			assert.ErrorIs(t, err, service.ErrInvalidStatusTransition, status)
+			err = s.UpdatePlanItem(authedPlannerCtx(), planID.String(), itemID.String(), dto.UpdatePlanItemRequest{Label: stringPtr("x")})
+			assert.ErrorIs(t, err, service.ErrInvalidStatusTransition, status)
+			req := dto.AddPlanItemRequest{}
+			req.LengthMM, req.WidthMM, req.HeightMM, req.WeightKG, req.Quantity = 100, 100, 100, 1, 1
+			_, err = s.AddPlanItem(authedPlannerCtx(), planID.String(), req)
+			assert.ErrorIs(t, err, service.ErrInvalidStatusTransition, status)
+		}
+	})
+
 	t.Run("invalid_plan_id", func(t *testing.T) {
 		mockQ := &MockQuerier{}
 		s := service.NewPlanService(mockQ, packer.NewPacker())
@@ -1216,6 +1747,7 @@ func TestPlanService_DeletePlanItem(t *testing.T) {
 			GetLoadPlanFunc: func(ctx context.Context, arg store.GetLoadPlanParams) (store.LoadPlan, error) {
 				return store.LoadPlan{PlanID: planID, WorkspaceID: arg.WorkspaceID}, nil
 			},
+			LockLoadPlanFunc: lockPlanAs(nil),
 		}
 		s := service.NewPlanService(mockQ, packer.NewPacker())
 		err := s.DeletePlanItem(authedPlannerCtx(), planID.String(), "invalid-uuid")
@@ -1358,6 +1890,7 @@ func TestPlanService_CalculatePlan(t *testing.T) {
 	planID := uuid.New()
 	workspaceID := uuid.New()
 	itemID1 := uuid.New()
+	itemID2 := uuid.New()
 	resultID := uuid.New()
 
 	tests := []struct {
@@ -1423,7 +1956,9 @@ func TestPlanService_CalculatePlan(t *testing.T) {
 						UnfitItems: []packer.ItemInput{}, // Empty for feasible case
 					}, nil
 				}
-				mq.DeletePlanResultsFunc = func(ctx context.Context, planIDPtr *uuid.UUID) error {
+				mq.LockLoadPlanFunc = lockPlanAs(nil)
+				mq.CreatePlanStatusHistoryFunc = acceptStatusHistory
+				mq.DeactivatePlanResultsFunc = func(ctx context.Context, planIDPtr *uuid.UUID) error {
 					return nil
 				}
 				mq.CreatePlanResultFunc = func(ctx context.Context, arg store.CreatePlanResultParams) (store.PlanResult, error) {
@@ -1439,7 +1974,7 @@ func TestPlanService_CalculatePlan(t *testing.T) {
 					return int64(len(arg)), nil
 				}
 				mq.UpdatePlanStatusFunc = func(ctx context.Context, arg store.UpdatePlanStatusParams) error {
-					assert.Equal(t, types.PlanStatusCompleted.String(), *arg.Status)
+					assert.Equal(t, types.PlanStatusPlanned.String(), *arg.Status)
 					return nil
 				}
 			},
@@ -1448,7 +1983,7 @@ func TestPlanService_CalculatePlan(t *testing.T) {
 				assert.NoError(t, err)
 				assert.NotNil(t, result)
 				assert.Equal(t, resultID.String(), result.JobID)
-				assert.Equal(t, types.PlanStatusCompleted.String(), result.Status)
+				assert.Equal(t, types.PlanStatusPlanned.String(), result.Status)
 				assert.Equal(t, "test-algorithm", result.Algorithm)
 				assert.Equal(t, 15.5, result.VolumeUtilization)
 				assert.Equal(t, 15.5, result.EfficiencyScore)
@@ -1507,7 +2042,9 @@ func TestPlanService_CalculatePlan(t *testing.T) {
 						}, // 3 unfit items for infeasible case
 					}, nil
 				}
-				mq.DeletePlanResultsFunc = func(ctx context.Context, planIDPtr *uuid.UUID) error {
+				mq.LockLoadPlanFunc = lockPlanAs(nil)
+				mq.CreatePlanStatusHistoryFunc = acceptStatusHistory
+				mq.DeactivatePlanResultsFunc = func(ctx context.Context, planIDPtr *uuid.UUID) error {
 					return nil
 				}
 				mq.CreatePlanResultFunc = func(ctx context.Context, arg store.CreatePlanResultParams) (store.PlanResult, error) {
@@ -1531,8 +2068,8 @@ func TestPlanService_CalculatePlan(t *testing.T) {
 			assertFunc: func(t *testing.T, result *dto.CalculationResult, err error) {
 				assert.NoError(t, err)
 				assert.NotNil(t, result)
-				// When packing is infeasible, service returns completed status (not partial) in the DTO
-				assert.Equal(t, types.PlanStatusCompleted.String(), result.Status)
+				// The DTO reports the status the result moved the plan to.
+				assert.Equal(t, types.PlanStatusPartial.String(), result.Status)
 				// Verify only 1 placement was created (from the 1 packed item)
 				assert.Equal(t, 1, len(result.Placements))
 			},
@@ -1649,7 +2186,9 @@ func TestPlanService_CalculatePlan(t *testing.T) {
 						PackedItems: []packer.PackedItem{},
 					}, nil
 				}
-				mq.DeletePlanResultsFunc = func(ctx context.Context, planIDPtr *uuid.UUID) error {
+				mq.LockLoadPlanFunc = lockPlanAs(nil)
+				mq.CreatePlanStatusHistoryFunc = acceptStatusHistory
+				mq.DeactivatePlanResultsFunc = func(ctx context.Context, planIDPtr *uuid.UUID) error {
 					return nil
 				}
 				mq.CreatePlanResultFunc = func(ctx context.Context, arg store.CreatePlanResultParams) (store.PlanResult, error) {
@@ -1694,7 +2233,9 @@ func TestPlanService_CalculatePlan(t *testing.T) {
 						},
 					}, nil
 				}
-				mq.DeletePlanResultsFunc = func(ctx context.Context, planIDPtr *uuid.UUID) error {
+				mq.LockLoadPlanFunc = lockPlanAs(nil)
+				mq.CreatePlanStatusHistoryFunc = acceptStatusHistory
+				mq.DeactivatePlanResultsFunc = func(ctx context.Context, planIDPtr *uuid.UUID) error {
 					return nil
 				}
 				mq.CreatePlanResultFunc = func(ctx context.Context, arg store.CreatePlanResultParams) (store.PlanResult, error) {
@@ -1745,7 +2286,9 @@ func TestPlanService_CalculatePlan(t *testing.T) {
 						PackedItems: []packer.PackedItem{},
 					}, nil
 				}
-				mq.DeletePlanResultsFunc = func(ctx context.Context, planIDPtr *uuid.UUID) error {
+				mq.LockLoadPlanFunc = lockPlanAs(nil)
+				mq.CreatePlanStatusHistoryFunc = acceptStatusHistory
+				mq.DeactivatePlanResultsFunc = func(ctx context.Context, planIDPtr *uuid.UUID) error {
 					return nil
 				}
 				mq.CreatePlanResultFunc = func(ctx context.Context, arg store.CreatePlanResultParams) (store.PlanResult, error) {
@@ -1764,18 +2307,1909 @@ func TestPlanService_CalculatePlan(t *testing.T) {
 				assert.NotNil(t, result)
 			},
 		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			mockQ := &MockQuerier{}
-			mockP := &MockPacker{}
-			tt.mockSetup(mockQ, mockP)
-
-			s := service.NewPlanService(mockQ, mockP)
-			result, err := s.CalculatePlan(tt.ctx, tt.planID, tt.opts)
-
-			tt.assertFunc(t, result, err)
-		})
-	}
+		{
+			name:   "passes_clearances_to_packer",
+			planID: planID.String(),
+			ctx:    authedPlannerCtx(),
+			mockSetup: func(mq *MockQuerier, mp *MockPacker) {
+				mq.GetLoadPlanFunc = func(ctx context.Context, arg store.GetLoadPlanParams) (store.LoadPlan, error) {
+					return store.LoadPlan{
+						PlanID:          planID,
+						WorkspaceID:     &workspaceID,
+						LengthMm:        toNumeric(1000.0),
+						WidthMm:         toNumeric(1000.0),
+						HeightMm:        toNumeric(1000.0),
+						MaxWeightKg:     toNumeric(100.0),
+						WallClearanceMm: toNumeric(20.0),
+						ItemGapMm:       toNumeric(10.0),
+					}, nil
+				}
+				mq.ListLoadItemsFunc = func(ctx context.Context, planIDPtr *uuid.UUID) ([]store.LoadItem, error) {
+					return []store.LoadItem{
+						{
+							ItemID:    itemID1,
+							LengthMm:  toNumeric(100.0),
+							WidthMm:   toNumeric(100.0),
+							HeightMm:  toNumeric(100.0),
+							WeightKg:  toNumeric(10.0),
+							Quantity:  1,
+							PaddingMm: toNumeric(5.0),
+						},
+					}, nil
+				}
+				mp.PackFunc = func(ctx context.Context, container packer.ContainerInput, items []packer.ItemInput) (packer.PackingResult, error) {
+					assert.Equal(t, 20.0, container.WallClearance)
+					assert.Equal(t, 10.0, container.ItemGap)
+					assert.Equal(t, 5.0, items[0].Padding)
+					// Dimensions stay the true ones; the packer applies the clearances.
+					assert.Equal(t, 100.0, items[0].Length)
+					return packer.PackingResult{IsFeasible: true}, nil
+				}
+				mq.LockLoadPlanFunc = lockPlanAs(nil)
+				mq.CreatePlanStatusHistoryFunc = acceptStatusHistory
+				mq.DeactivatePlanResultsFunc = func(ctx context.Context, planIDPtr *uuid.UUID) error {
+					return nil
+				}
+				mq.CreatePlanResultFunc = func(ctx context.Context, arg store.CreatePlanResultParams) (store.PlanResult, error) {
+					return store.PlanResult{ResultID: resultID, PlanID: arg.PlanID}, nil
+				}
+				mq.CreatePlanPlacementFunc = func(ctx context.Context, arg []store.CreatePlanPlacementParams) (int64, error) {
+					return 0, nil
+				}
+				mq.UpdatePlanStatusFunc = func(ctx context.Context, arg store.UpdatePlanStatusParams) error {
+					return nil
+				}
+			},
+			assertFunc: func(t *testing.T, result *dto.CalculationResult, err error) {
+				assert.NoError(t, err)
+				assert.NotNil(t, result)
+			},
+		},
+		{
+			name:   "must_ship_packs_first",
+			planID: planID.String(),
+			ctx:    authedPlannerCtx(),
+			mockSetup: func(mq *MockQuerier, mp *MockPacker) {
+				mq.GetLoadPlanFunc = func(ctx context.Context, arg store.GetLoadPlanParams) (store.LoadPlan, error) {
+					return store.LoadPlan{
+						PlanID:      planID,
+						WorkspaceID: &workspaceID,
+						LengthMm:    toNumeric(1000.0),
+						WidthMm:     toNumeric(1000.0),
+						HeightMm:    toNumeric(1000.0),
+						MaxWeightKg: toNumeric(15.0),
+					}, nil
+				}
+				mq.ListLoadItemsFunc = func(ctx context.Context, planIDPtr *uuid.UUID) ([]store.LoadItem, error) {
+					return []store.LoadItem{
+						{ItemID: itemID1, LengthMm: toNumeric(100.0), WidthMm: toNumeric(100.0), HeightMm: toNumeric(100.0), WeightKg: toNumeric(10.0), Quantity: 1},
+						{ItemID: itemID2, LengthMm: toNumeric(100.0), WidthMm: toNumeric(100.0), HeightMm: toNumeric(100.0), WeightKg: toNumeric(10.0), Quantity: 1, MustShip: true},
+					}, nil
+				}
+				// Stand-in for a weight-limited packer: one unit fits.
+				mp.PackFunc = func(ctx context.Context, container packer.ContainerInput, items []packer.ItemInput) (packer.PackingResult, error) {
+					assert.False(t, container.Options.Prioritize)
+					res := packer.PackingResult{}
+					for _, it := range items {
+						for n := 0; n < it.Quantity; n++ {
+							if len(res.PackedItems) == 1 {
+								res.UnfitItems = append(res.UnfitItems, it)
+								continue
+							}
+							res.PackedItems = append(res.PackedItems, packer.PackedItem{ItemID: it.ID})
+							res.TotalWeightPackedKG += it.Weight
+						}
+					}
+					res.IsFeasible = len(res.UnfitItems) == 0
+					return res, nil
+				}
+				mq.LockLoadPlanFunc = lockPlanAs(nil)
+				mq.CreatePlanStatusHistoryFunc = acceptStatusHistory
+				mq.DeactivatePlanResultsFunc = func(ctx context.Context, planIDPtr *uuid.UUID) error {
+					return nil
+				}
+				mq.CreatePlanResultFunc = func(ctx context.Context, arg store.CreatePlanResultParams) (store.PlanResult, error) {
+					return store.PlanResult{ResultID: resultID, PlanID: arg.PlanID}, nil
+				}
+				mq.CreatePlanPlacementFunc = func(ctx context.Context, arg []store.CreatePlanPlacementParams) (int64, error) {
+					return 0, nil
+				}
+				mq.UpdatePlanStatusFunc = func(ctx context.Context, arg store.UpdatePlanStatusParams) error {
+					return nil
+				}
+			},
+			assertFunc: func(t *testing.T, result *dto.CalculationResult, err error) {
+				require.NoError(t, err)
+				require.Len(t, result.Placements, 1)
+				assert.Equal(t, itemID2.String(), result.Placements[0].ItemID)
+
+				require.Len(t, result.UnfitItems, 1)
+				assert.Equal(t, itemID1.String(), result.UnfitItems[0].ItemID)
+				assert.Equal(t, packer.ConstraintWeight, result.UnfitItems[0].Reason)
+				assert.Equal(t, packer.UnfitOverweight, result.UnfitItems[0].Diagnosis)
+				assert.False(t, result.UnfitItems[0].MustShip)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockQ := &MockQuerier{}
+			mockP := &MockPacker{}
+			tt.mockSetup(mockQ, mockP)
+
+			s := service.NewPlanService(mockQ, mockP)
+			result, err := s.CalculatePlan(tt.ctx, tt.planID, tt.opts)
+
+			tt.assertFunc(t, result, err)
+		})
+	}
+}
+
+func TestPlanService_CalculatePlan_VoidAnalysis(t *testing.T) {
+	planID := uuid.New()
+	workspaceID := uuid.New()
+	itemID := uuid.New()
+
+	mockQ := &MockQuerier{
+		GetLoadPlanFunc: func(ctx context.Context, arg store.GetLoadPlanParams) (store.LoadPlan, error) {
+			return store.LoadPlan{
+				PlanID:      planID,
+				WorkspaceID: &workspaceID,
+				LengthMm:    toNumeric(2000.0),
+				WidthMm:     toNumeric(1000.0),
+				HeightMm:    toNumeric(1000.0),
+				MaxWeightKg: toNumeric(1000.0),
+			}, nil
+		},
+		ListLoadItemsFunc: func(ctx context.Context, planIDPtr *uuid.UUID) ([]store.LoadItem, error) {
+			return []store.LoadItem{{ItemID: itemID, Quantity: 1}}, nil
+		},
+		LockLoadPlanFunc:            lockPlanAs(nil),
+		CreatePlanStatusHistoryFunc: acceptStatusHistory,
+		DeactivatePlanResultsFunc: func(ctx context.Context, planIDPtr *uuid.UUID) error {
+			return nil
+		},
+		CreatePlanResultFunc: func(ctx context.Context, arg store.CreatePlanResultParams) (store.PlanResult, error) {
+			return store.PlanResult{ResultID: uuid.New(), PlanID: arg.PlanID}, nil
+		},
+		CreatePlanPlacementFunc: func(ctx context.Context, arg []store.CreatePlanPlacementParams) (int64, error) {
+			return int64(len(arg)), nil
+		},
+		UpdatePlanStatusFunc: func(ctx context.Context, arg store.UpdatePlanStatusParams) error {
+			return nil
+		},
+	}
+	// One block leaves a 200 mm gap at the door.
+	mockP := &MockPacker{
+		PackFunc: func(ctx context.Context, container packer.ContainerInput, items []packer.ItemInput) (packer.PackingResult, error) {
+			return packer.PackingResult{
+				IsFeasible:          true,
+				TotalVolumePackedM3: 1.8,
+				PackedItems: []packer.PackedItem{{
+					ItemID:        itemID.String(),
+					RotatedLength: 1800,
+					RotatedWidth:  1000,
+					RotatedHeight: 1000,
+				}},
+			}, nil
+		},
+	}
+
+	s := service.NewPlanService(mockQ, mockP)
+
+	t.Run("flags_gap_with_airbags", func(t *testing.T) {
+		res, err := s.CalculatePlan(authedPlannerCtx(), planID.String(), dto.CalculatePlanRequest{})
+		require.NoError(t, err)
+		require.NotNil(t, res.VoidAnalysis)
+
+		va := res.VoidAnalysis
+		assert.Equal(t, 50.0, va.ThresholdMM)
+		assert.InDelta(t, 0.2, va.EmptyVolumeM3, 1e-9)
+		require.Len(t, va.Voids, 1)
+		assert.True(t, va.Voids[0].Flagged)
+		assert.Equal(t, 1800.0, va.Voids[0].PositionX)
+		assert.Equal(t, 200.0, va.Voids[0].GapMM)
+		assert.Equal(t, "length", va.Voids[0].ShiftAxis)
+		require.NotNil(t, va.Voids[0].Dunnage)
+		assert.Equal(t, "airbag", va.Voids[0].Dunnage.Type)
+		assert.Equal(t, []dto.DunnageRecommendation{{Type: "airbag", Quantity: 1, EstimatedCost: 4.5}}, va.Dunnage)
+		assert.Equal(t, 4.5, va.TotalDunnageCost)
+	})
+
+	t.Run("threshold_option", func(t *testing.T) {
+		threshold := 250.0
+		res, err := s.CalculatePlan(authedPlannerCtx(), planID.String(), dto.CalculatePlanRequest{VoidThresholdMM: &threshold})
+		require.NoError(t, err)
+
+		assert.Equal(t, 250.0, res.VoidAnalysis.ThresholdMM)
+		assert.Empty(t, res.VoidAnalysis.Voids)
+		assert.Empty(t, res.VoidAnalysis.Dunnage)
+	})
+}
+
+func TestPlanService_Securing(t *testing.T) {
+	planID := uuid.New()
+	workspaceID := uuid.New()
+	itemID := uuid.New()
+	resultID := uuid.New()
+	label := "Crate"
+
+	plan := store.LoadPlan{
+		PlanID:      planID,
+		WorkspaceID: &workspaceID,
+		LengthMm:    toNumeric(6000.0),
+		WidthMm:     toNumeric(2350.0),
+		HeightMm:    toNumeric(2390.0),
+		MaxWeightKg: toNumeric(20000.0),
+		CreatedAt:   pgtype.Timestamp{Time: time.Now(), Valid: true},
+
+		LashingPointsPerSide: 8, // every 750 mm
+	}
+	items := []store.LoadItem{{
+		ItemID:              itemID,
+		ItemLabel:           &label,
+		Quantity:            1,
+		LengthMm:            toNumeric(1200.0),
+		WidthMm:             toNumeric(1000.0),
+		HeightMm:            toNumeric(1000.0),
+		WeightKg:            toNumeric(500.0),
+		AllowRotation:       boolPtr(true),
+		FrictionCoefficient: toNumeric(0.3),
+	}}
+
+	assertSecuring := func(t *testing.T, sec *dto.SecuringPlan) {
+		require.NotNil(t, sec)
+		assert.Equal(t, "EN 12195-1:2010", sec.Standard)
+		assert.Equal(t, 8, sec.LashingPoints)
+		require.Len(t, sec.Blocks, 1)
+		b := sec.Blocks[0]
+		assert.Equal(t, itemID.String(), b.ItemID)
+		assert.Equal(t, "Crate", b.Label)
+		assert.InDelta(t, 0.3, b.Friction, 1e-9)
+		assert.Equal(t, packer.SecuringTopOver, b.Method)
+		assert.Equal(t, 2, b.Straps)
+		assert.Equal(t, 2, b.LashingPoints)
+		assert.True(t, sec.Sufficient)
+		assert.Equal(t, 2, sec.TotalStraps)
+		assert.Empty(t, sec.Warnings)
+	}
+
+	t.Run("fresh_calculation", func(t *testing.T) {
+		mockQ := &MockQuerier{
+			GetLoadPlanFunc: func(ctx context.Context, arg store.GetLoadPlanParams) (store.LoadPlan, error) {
+				return plan, nil
+			},
+			ListLoadItemsFunc: func(ctx context.Context, id *uuid.UUID) ([]store.LoadItem, error) {
+				return items, nil
+			},
+			LockLoadPlanFunc:            lockPlanAs(nil),
+			CreatePlanStatusHistoryFunc: acceptStatusHistory,
+			DeactivatePlanResultsFunc: func(ctx context.Context, id *uuid.UUID) error {
+				return nil
+			},
+			CreatePlanResultFunc: func(ctx context.Context, arg store.CreatePlanResultParams) (store.PlanResult, error) {
+				return store.PlanResult{ResultID: resultID, PlanID: arg.PlanID}, nil
+			},
+			CreatePlanPlacementFunc: func(ctx context.Context, arg []store.CreatePlanPlacementParams) (int64, error) {
+				return int64(len(arg)), nil
+			},
+			UpdatePlanStatusFunc: func(ctx context.Context, arg store.UpdatePlanStatusParams) error {
+				return nil
+			},
+		}
+		mockP := &MockPacker{
+			PackFunc: func(ctx context.Context, container packer.ContainerInput, in []packer.ItemInput) (packer.PackingResult, error) {
+				assert.Equal(t, 8, container.LashingPointsPerSide)
+				assert.InDelta(t, 0.3, in[0].Friction, 1e-9)
+				return packer.PackingResult{
+					IsFeasible: true,
+					PackedItems: []packer.PackedItem{{
+						ItemID:        itemID.String(),
+						RotatedLength: 1200,
+						RotatedWidth:  1000,
+						RotatedHeight: 1000,
+					}},
+				}, nil
+			},
+		}
+
+		s := service.NewPlanService(mockQ, mockP)
+		res, err := s.CalculatePlan(authedPlannerCtx(), planID.String(), dto.CalculatePlanRequest{})
+		require.NoError(t, err)
+		assertSecuring(t, res.Securing)
+	})
+
+	t.Run("stored_result", func(t *testing.T) {
+		rot := int32(0)
+		mockQ := &MockQuerier{
+			GetLoadPlanFunc: func(ctx context.Context, arg store.GetLoadPlanParams) (store.LoadPlan, error) {
+				return plan, nil
+			},
+			ListLoadItemsFunc: func(ctx context.Context, id *uuid.UUID) ([]store.LoadItem, error) {
+				return items, nil
+			},
+			GetPlanResultFunc: func(ctx context.Context, id *uuid.UUID) (store.PlanResult, error) {
+				return store.PlanResult{ResultID: resultID}, nil
+			},
+			ListPlanPlacementsFunc: func(ctx context.Context, id *uuid.UUID) ([]store.PlanPlacement, error) {
+				return []store.PlanPlacement{{
+					PlacementID:  uuid.New(),
+					ItemID:       &itemID,
+					PosX:         toNumeric(0),
+					PosY:         toNumeric(0),
+					PosZ:         toNumeric(0),
+					RotationCode: &rot,
+					StepNumber:   1,
+				}}, nil
+			},
+		}
+
+		s := service.NewPlanService(mockQ, nil)
+		res, err := s.GetPlan(authedPlannerCtx(), planID.String())
+		require.NoError(t, err)
+		require.NotNil(t, res.Calculation)
+		assertSecuring(t, res.Calculation.Securing)
+		assert.Equal(t, 8, res.Container.LashingPointsPerSide)
+		require.NotNil(t, res.Items[0].FrictionCoefficient)
+		assert.InDelta(t, 0.3, *res.Items[0].FrictionCoefficient, 1e-9)
+	})
+}
+
+func TestPlanService_Compartments(t *testing.T) {
+	planID := uuid.New()
+	workspaceID := uuid.New()
+	dryID, coldID, frozenID := uuid.New(), uuid.New(), uuid.New()
+	resultID := uuid.New()
+	chilled, frozen := "chilled", "frozen"
+
+	plan := store.LoadPlan{
+		PlanID:       planID,
+		WorkspaceID:  &workspaceID,
+		LengthMm:     toNumeric(2000.0),
+		WidthMm:      toNumeric(1000.0),
+		HeightMm:     toNumeric(1000.0),
+		MaxWeightKg:  toNumeric(1000.0),
+		CreatedAt:    pgtype.Timestamp{Time: time.Now(), Valid: true},
+		Compartments: []byte(`[{"name":"Dry","temperature_class":"ambient","start_mm":0,"length_mm":1000},{"name":"Cold","temperature_class":"chilled","start_mm":1000,"length_mm":1000,"max_weight_kg":100}]`),
+	}
+	item := func(id uuid.UUID, class *string) store.LoadItem {
+		return store.LoadItem{
+			ItemID:           id,
+			Quantity:         1,
+			LengthMm:         toNumeric(500.0),
+			WidthMm:          toNumeric(500.0),
+			HeightMm:         toNumeric(500.0),
+			WeightKg:         toNumeric(25.0),
+			AllowRotation:    boolPtr(true),
+			TemperatureClass: class,
+		}
+	}
+	items := []store.LoadItem{item(dryID, nil), item(coldID, &chilled), item(frozenID, &frozen)}
+
+	t.Run("calculate_keeps_classes_apart", func(t *testing.T) {
+		var packedLengths []float64
+		mockQ := &MockQuerier{
+			GetLoadPlanFunc: func(ctx context.Context, arg store.GetLoadPlanParams) (store.LoadPlan, error) {
+				return plan, nil
+			},
+			ListLoadItemsFunc: func(ctx context.Context, id *uuid.UUID) ([]store.LoadItem, error) {
+				return items, nil
+			},
+			LockLoadPlanFunc:            lockPlanAs(nil),
+			CreatePlanStatusHistoryFunc: acceptStatusHistory,
+			DeactivatePlanResultsFunc: func(ctx context.Context, id *uuid.UUID) error {
+				return nil
+			},
+			CreatePlanResultFunc: func(ctx context.Context, arg store.CreatePlanResultParams) (store.PlanResult, error) {
+				return store.PlanResult{ResultID: resultID, PlanID: arg.PlanID}, nil
+			},
+			CreatePlanPlacementFunc: func(ctx context.Context, arg []store.CreatePlanPlacementParams) (int64, error) {
+				return int64(len(arg)), nil
+			},
+			UpdatePlanStatusFunc: func(ctx context.Context, arg store.UpdatePlanStatusParams) error {
+				return nil
+			},
+		}
+		mockP := &MockPacker{
+			PackFunc: func(ctx context.Context, container packer.ContainerInput, in []packer.ItemInput) (packer.PackingResult, error) {
+				packedLengths = append(packedLengths, container.Length)
+				require.Len(t, in, 1)
+				return packer.PackingResult{
+					IsFeasible:          true,
+					TotalWeightPackedKG: in[0].Weight,
+					PackedItems: []packer.PackedItem{{
+						ItemID:        in[0].ID,
+						RotatedLength: 500,
+						RotatedWidth:  500,
+						RotatedHeight: 500,
+					}},
+				}, nil
+			},
+		}
+
+		s := service.NewPlanService(mockQ, mockP)
+		res, err := s.CalculatePlan(authedPlannerCtx(), planID.String(), dto.CalculatePlanRequest{})
+		require.NoError(t, err)
+
+		assert.Equal(t, []float64{1000, 1000}, packedLengths)
+		require.Len(t, res.Placements, 2)
+		assert.Equal(t, dryID.String(), res.Placements[0].ItemID)
+		assert.Equal(t, 0.0, res.Placements[0].PositionX)
+		assert.Equal(t, coldID.String(), res.Placements[1].ItemID)
+		assert.Equal(t, 1000.0, res.Placements[1].PositionX)
+
+		require.Len(t, res.UnfitItems, 1)
+		assert.Equal(t, frozenID.String(), res.UnfitItems[0].ItemID)
+		assert.Equal(t, packer.ConstraintTemperature, res.UnfitItems[0].Reason)
+		assert.Equal(t, packer.UnfitTemperature, res.UnfitItems[0].Diagnosis)
+	})
+
+	t.Run("plan_stats_per_compartment", func(t *testing.T) {
+		rot := int32(0)
+		mockQ := &MockQuerier{
+			GetLoadPlanFunc: func(ctx context.Context, arg store.GetLoadPlanParams) (store.LoadPlan, error) {
+				return plan, nil
+			},
+			ListLoadItemsFunc: func(ctx context.Context, id *uuid.UUID) ([]store.LoadItem, error) {
+				return items, nil
+			},
+			GetPlanResultFunc: func(ctx context.Context, id *uuid.UUID) (store.PlanResult, error) {
+				return store.PlanResult{ResultID: resultID}, nil
+			},
+			ListPlanPlacementsFunc: func(ctx context.Context, id *uuid.UUID) ([]store.PlanPlacement, error) {
+				return []store.PlanPlacement{
+					{PlacementID: uuid.New(), ItemID: &dryID, PosX: toNumeric(0), PosY: toNumeric(0), PosZ: toNumeric(0), RotationCode: &rot, StepNumber: 1},
+					{PlacementID: uuid.New(), ItemID: &coldID, PosX: toNumeric(1000), PosY: toNumeric(0), PosZ: toNumeric(0), RotationCode: &rot, StepNumber: 2},
+				}, nil
+			},
+		}
+
+		s := service.NewPlanService(mockQ, nil)
+		res, err := s.GetPlan(authedPlannerCtx(), planID.String())
+		require.NoError(t, err)
+
+		require.Len(t, res.Container.Compartments, 2)
+		assert.Equal(t, "chilled", res.Container.Compartments[1].TemperatureClass)
+		require.NotNil(t, res.Items[1].TemperatureClass)
+		assert.Equal(t, "chilled", *res.Items[1].TemperatureClass)
+
+		require.Len(t, res.Stats.Compartments, 2)
+		dry, cold := res.Stats.Compartments[0], res.Stats.Compartments[1]
+		assert.Equal(t, "Dry", dry.Name)
+		assert.Equal(t, 1, dry.TotalItems)
+		assert.InDelta(t, 12.5, dry.VolumeUtilizationPct, 1e-6)
+		assert.Zero(t, dry.WeightUtilizationPct)
+		assert.Equal(t, "Cold", cold.Name)
+		assert.Equal(t, 1, cold.TotalItems)
+		assert.InDelta(t, 25.0, cold.TotalWeightKG, 1e-6)
+		assert.InDelta(t, 25.0, cold.WeightUtilizationPct, 1e-6)
+	})
+
+	t.Run("update_rejects_compartments_past_new_length", func(t *testing.T) {
+		mockQ := &MockQuerier{
+			GetLoadPlanFunc: func(ctx context.Context, arg store.GetLoadPlanParams) (store.LoadPlan, error) {
+				return plan, nil
+			},
+			UpdateLoadPlanFunc: func(ctx context.Context, arg store.UpdateLoadPlanParams) error {
+				t.Fatalf("unexpected update")
+				return nil
+			},
+		}
+
+		s := service.NewPlanService(mockQ, nil)
+		shorter := 1500.0
+		err := s.UpdatePlan(authedPlannerCtx(), planID.String(), dto.UpdatePlanRequest{
+			Container: &dto.CreatePlanContainer{LengthMM: &shorter},
+		})
+		assert.ErrorIs(t, err, service.ErrInvalidCompartments)
+	})
+}
+
+func TestPlanService_CalculatePlan_FloorLoad(t *testing.T) {
+	planID := uuid.New()
+	workspaceID := uuid.New()
+	itemID := uuid.New()
+
+	plan := store.LoadPlan{
+		PlanID:        planID,
+		WorkspaceID:   &workspaceID,
+		LengthMm:      toNumeric(6000.0),
+		WidthMm:       toNumeric(2350.0),
+		HeightMm:      toNumeric(2390.0),
+		MaxWeightKg:   toNumeric(20000.0),
+		FloorLoadKgM2: toNumeric(2000.0),
+		LineLoadKgM:   toNumeric(3000.0),
+	}
+	items := []store.LoadItem{{
+		ItemID:        itemID,
+		Quantity:      1,
+		LengthMm:      toNumeric(1000.0),
+		WidthMm:       toNumeric(1000.0),
+		HeightMm:      toNumeric(1000.0),
+		WeightKg:      toNumeric(2500.0),
+		AllowRotation: boolPtr(false),
+	}}
+
+	mockQ := &MockQuerier{
+		GetLoadPlanFunc: func(ctx context.Context, arg store.GetLoadPlanParams) (store.LoadPlan, error) {
+			return plan, nil
+		},
+		ListLoadItemsFunc: func(ctx context.Context, id *uuid.UUID) ([]store.LoadItem, error) {
+			return items, nil
+		},
+		LockLoadPlanFunc:            lockPlanAs(nil),
+		CreatePlanStatusHistoryFunc: acceptStatusHistory,
+		DeactivatePlanResultsFunc: func(ctx context.Context, id *uuid.UUID) error {
+			return nil
+		},
+		CreatePlanResultFunc: func(ctx context.Context, arg store.CreatePlanResultParams) (store.PlanResult, error) {
+			return store.PlanResult{ResultID: uuid.New(), PlanID: arg.PlanID}, nil
+		},
+		CreatePlanPlacementFunc: func(ctx context.Context, arg []store.CreatePlanPlacementParams) (int64, error) {
+			return int64(len(arg)), nil
+		},
+		UpdatePlanStatusFunc: func(ctx context.Context, arg store.UpdatePlanStatusParams) error {
+			return nil
+		},
+	}
+	mockP := &MockPacker{
+		PackFunc: func(ctx context.Context, container packer.ContainerInput, in []packer.ItemInput) (packer.PackingResult, error) {
+			assert.Equal(t, 2000.0, container.FloorLoadLimit)
+			assert.Equal(t, 3000.0, container.LineLoadLimit)
+			return packer.PackingResult{
+				IsFeasible:           true,
+				WeightUtilisationPct: 12.5,
+				PackedItems: []packer.PackedItem{{
+					ItemID:        itemID.String(),
+					RotatedLength: 1000,
+					RotatedWidth:  1000,
+					RotatedHeight: 1000,
+				}},
+			}, nil
+		},
+	}
+
+	s := service.NewPlanService(mockQ, mockP)
+	res, err := s.CalculatePlan(authedPlannerCtx(), planID.String(), dto.CalculatePlanRequest{})
+	require.NoError(t, err)
+
+	assert.Equal(t, 12.5, res.WeightUtilization)
+	require.NotNil(t, res.FloorLoad)
+	fl := res.FloorLoad
+	assert.False(t, fl.OK)
+	assert.InDelta(t, 2500.0, fl.MaxPressureKgM2, 1e-6)
+	require.NotNil(t, fl.LimitKgM2)
+	assert.Equal(t, 2000.0, *fl.LimitKgM2)
+	require.Len(t, fl.Violations, 1)
+	assert.Equal(t, itemID.String(), fl.Violations[0].ItemID)
+	assert.InDelta(t, 1.25, fl.Violations[0].RequiredAreaM2, 1e-9)
+	assert.Empty(t, fl.LineViolations)
+}
+
+func TestPlanService_CalculatePlanWithProgress(t *testing.T) {
+	planID := uuid.New()
+	workspaceID := uuid.New()
+	itemID := uuid.New()
+
+	mockQ := &MockQuerier{
+		GetLoadPlanFunc: func(ctx context.Context, arg store.GetLoadPlanParams) (store.LoadPlan, error) {
+			return store.LoadPlan{
+				PlanID:      planID,
+				WorkspaceID: &workspaceID,
+				LengthMm:    toNumeric(1000.0),
+				WidthMm:     toNumeric(1000.0),
+				HeightMm:    toNumeric(1000.0),
+				MaxWeightKg: toNumeric(100.0),
+			}, nil
+		},
+		ListLoadItemsFunc: func(ctx context.Context, planIDPtr *uuid.UUID) ([]store.LoadItem, error) {
+			return []store.LoadItem{{
+				ItemID:    itemID,
+				LengthMm:  toNumeric(500.0),
+				WidthMm:   toNumeric(500.0),
+				HeightMm:  toNumeric(500.0),
+				WeightKg:  toNumeric(10.0),
+				Quantity:  3,
+				Stackable: true,
+			}}, nil
+		},
+		LockLoadPlanFunc:            lockPlanAs(nil),
+		CreatePlanStatusHistoryFunc: acceptStatusHistory,
+		DeactivatePlanResultsFunc: func(ctx context.Context, planIDPtr *uuid.UUID) error {
+			return nil
+		},
+		CreatePlanResultFunc: func(ctx context.Context, arg store.CreatePlanResultParams) (store.PlanResult, error) {
+			return store.PlanResult{ResultID: uuid.New(), PlanID: arg.PlanID}, nil
+		},
+		CreatePlanPlacementFunc: func(ctx context.Context, arg []store.CreatePlanPlacementParams) (int64, error) {
+			return int64(len(arg)), nil
+		},
+		UpdatePlanStatusFunc: func(ctx context.Context, arg store.UpdatePlanStatusParams) error {
+			return nil
+		},
+	}
+
+	var events []dto.CalculationProgress
+	s := service.NewPlanService(mockQ, packer.NewPacker())
+	res, err := s.CalculatePlanWithProgress(authedPlannerCtx(), planID.String(), dto.CalculatePlanRequest{}, func(p dto.CalculationProgress) {
+		events = append(events, p)
+	})
+
+	assert.NoError(t, err)
+	assert.NotNil(t, res)
+	assert.NotEmpty(t, events)
+	assert.Equal(t, "started", events[0].Stage)
+	assert.Equal(t, 3, events[0].ItemsTotal)
+
+	var steps []int
+	for _, e := range events {
+		for _, pl := range e.Placements {
+			assert.Equal(t, itemID.String(), pl.ItemID)
+			steps = append(steps, pl.StepNumber)
+		}
+	}
+	assert.Equal(t, []int{1, 2, 3}, steps)
+}
+
+func TestPlanService_CalculatePlan_Transaction(t *testing.T) {
+	planID := uuid.New()
+	workspaceID := uuid.New()
+	itemID := uuid.New()
+
+	newQuerier := func() (*MockQuerier, *[]string) {
+		var calls []string
+		mockQ := &MockQuerier{
+			GetLoadPlanFunc: func(ctx context.Context, arg store.GetLoadPlanParams) (store.LoadPlan, error) {
+				return store.LoadPlan{
+					PlanID:      planID,
+					WorkspaceID: &workspaceID,
+					LengthMm:    toNumeric(1000.0),
+					WidthMm:     toNumeric(1000.0),
+					HeightMm:    toNumeric(1000.0),
+					MaxWeightKg: toNumeric(100.0),
+				}, nil
+			},
+			ListLoadItemsFunc: func(ctx context.Context, planIDPtr *uuid.UUID) ([]store.LoadItem, error) {
+				return []store.LoadItem{{
+					ItemID:   itemID,
+					LengthMm: toNumeric(500.0),
+					WidthMm:  toNumeric(500.0),
+					HeightMm: toNumeric(500.0),
+					WeightKg: toNumeric(10.0),
+					Quantity: 1,
+				}}, nil
+			},
+			LockLoadPlanFunc: func(ctx context.Context, id uuid.UUID) (*string, error) {
+				calls = append(calls, "lock")
+				return nil, nil
+			},
+			DeactivatePlanResultsFunc: func(ctx context.Context, planIDPtr *uuid.UUID) error {
+				calls = append(calls, "deactivate")
+				return nil
+			},
+			CreatePlanResultFunc: func(ctx context.Context, arg store.CreatePlanResultParams) (store.PlanResult, error) {
+				calls = append(calls, "result")
+				return store.PlanResult{ResultID: uuid.New(), PlanID: arg.PlanID}, nil
+			},
+			CreatePlanPlacementFunc: func(ctx context.Context, arg []store.CreatePlanPlacementParams) (int64, error) {
+				calls = append(calls, "placements")
+				return int64(len(arg)), nil
+			},
+			UpdatePlanStatusFunc: func(ctx context.Context, arg store.UpdatePlanStatusParams) error {
+				calls = append(calls, "status")
+				return nil
+			},
+			CreatePlanStatusHistoryFunc: acceptStatusHistory,
+		}
+		mockQ.ExecTxFunc = func(ctx context.Context, fn func(store.Querier) error) error {
+			calls = append(calls, "begin")
+			if err := fn(mockQ); err != nil {
+				calls = append(calls, "rollback")
+				return err
+			}
+			calls = append(calls, "commit")
+			return nil
+		}
+		return mockQ, &calls
+	}
+
+	t.Run("saves_in_one_transaction", func(t *testing.T) {
+		mockQ, calls := newQuerier()
+		s := service.NewPlanService(mockQ, packer.NewPacker())
+		_, err := s.CalculatePlan(authedPlannerCtx(), planID.String(), dto.CalculatePlanRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"begin", "lock", "deactivate", "result", "placements", "status", "commit"}, *calls)
+	})
+
+	t.Run("failure_rolls_back", func(t *testing.T) {
+		mockQ, calls := newQuerier()
+		mockQ.CreatePlanStatusHistoryFunc = func(ctx context.Context, arg store.CreatePlanStatusHistoryParams) (store.PlanStatusHistory, error) {
+			return store.PlanStatusHistory{}, fmt.Errorf("db down")
+		}
+		s := service.NewPlanService(mockQ, packer.NewPacker())
+		_, err := s.CalculatePlan(authedPlannerCtx(), planID.String(), dto.CalculatePlanRequest{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "db down")
+		assert.Equal(t, "rollback", (*calls)[len(*calls)-1])
+	})
+
+	t.Run("cancel_during_save_rolls_back", func(t *testing.T) {
+		mockQ, calls := newQuerier()
+		ctx, cancel := context.WithCancel(authedPlannerCtx())
+		defer cancel()
+		mockQ.UpdatePlanStatusFunc = func(ctx context.Context, arg store.UpdatePlanStatusParams) error {
+			*calls = append(*calls, "status")
+			cancel()
+			return nil
+		}
+		s := service.NewPlanService(mockQ, packer.NewPacker())
+		_, err := s.CalculatePlan(ctx, planID.String(), dto.CalculatePlanRequest{})
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, []string{"begin", "lock", "deactivate", "result", "placements", "status", "rollback"}, *calls)
+	})
+
+	t.Run("locked_status_is_checked", func(t *testing.T) {
+		mockQ, calls := newQuerier()
+		mockQ.LockLoadPlanFunc = lockPlanAs(stringPtr(types.PlanStatusInProgress.String()))
+		s := service.NewPlanService(mockQ, packer.NewPacker())
+		_, err := s.CalculatePlan(authedPlannerCtx(), planID.String(), dto.CalculatePlanRequest{})
+		assert.ErrorIs(t, err, service.ErrInvalidStatusTransition)
+		assert.Equal(t, []string{"begin", "rollback"}, *calls)
+	})
+}
+
+func TestPlanService_ComparePlan(t *testing.T) {
+	planID := uuid.New()
+	workspaceID := uuid.New()
+	itemID := uuid.New()
+
+	mockQ := &MockQuerier{
+		GetLoadPlanFunc: func(ctx context.Context, arg store.GetLoadPlanParams) (store.LoadPlan, error) {
+			return store.LoadPlan{
+				PlanID:      planID,
+				WorkspaceID: &workspaceID,
+				LengthMm:    toNumeric(1000.0),
+				WidthMm:     toNumeric(1000.0),
+				HeightMm:    toNumeric(1000.0),
+				MaxWeightKg: toNumeric(100.0),
+			}, nil
+		},
+		ListLoadItemsFunc: func(ctx context.Context, planIDPtr *uuid.UUID) ([]store.LoadItem, error) {
+			return []store.LoadItem{{
+				ItemID:    itemID,
+				LengthMm:  toNumeric(500.0),
+				WidthMm:   toNumeric(500.0),
+				HeightMm:  toNumeric(500.0),
+				WeightKg:  toNumeric(10.0),
+				Quantity:  3,
+				Stackable: true,
+			}}, nil
+		},
+	}
+
+	partial := &MockPacker{
+		PackFunc: func(ctx context.Context, container packer.ContainerInput, items []packer.ItemInput) (packer.PackingResult, error) {
+			unfit := items[0]
+			unfit.Quantity = 1
+			return packer.PackingResult{
+				Algorithm: "partial",
+				PackedItems: []packer.PackedItem{
+					{ItemID: itemID.String(), RotatedLength: 500, RotatedWidth: 500, RotatedHeight: 500},
+					{ItemID: itemID.String(), Position: packer.Position{X: 500}, RotatedLength: 500, RotatedWidth: 500, RotatedHeight: 500},
+				},
+				UnfitItems:       []packer.ItemInput{unfit},
+				TotalPackedItems: 2,
+			}, nil
+		},
+	}
+	broken := &MockPacker{
+		PackFunc: func(ctx context.Context, container packer.ContainerInput, items []packer.ItemInput) (packer.PackingResult, error) {
+			return packer.PackingResult{}, fmt.Errorf("backend down")
+		},
+	}
+
+	s := service.NewPlanServiceWithBackends(mockQ, partial, service.PackerBackends{
+		"native": packer.NewPacker(),
+		"broken": broken,
+	})
+
+	t.Run("ranks_alternatives", func(t *testing.T) {
+		res, err := s.ComparePlan(authedPlannerCtx(), planID.String(), dto.ComparePlanRequest{
+			Candidates: []dto.CalculatePlanRequest{
+				{Backend: "broken"},
+				{},
+				{Backend: "Native", Strategy: "bestfitdecreasing"},
+			},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, planID.String(), res.PlanID)
+		if assert.Len(t, res.Alternatives, 3) {
+			best := res.Alternatives[0]
+			assert.Equal(t, 1, best.Rank)
+			assert.Equal(t, "Native", best.Options.Backend)
+			assert.Equal(t, 0, best.UnfitCount)
+			assert.Len(t, best.Placements, 3)
+			assert.Equal(t, 3, best.Placements[2].StepNumber)
+
+			assert.Equal(t, "partial", res.Alternatives[1].Algorithm)
+			assert.Equal(t, 1, res.Alternatives[1].UnfitCount)
+			assert.Equal(t, 100.0, res.Alternatives[1].StabilityPct)
+
+			assert.Equal(t, 3, res.Alternatives[2].Rank)
+			assert.Equal(t, "backend down", res.Alternatives[2].Error)
+		}
+	})
+
+	t.Run("unknown_backend", func(t *testing.T) {
+		_, err := s.ComparePlan(authedPlannerCtx(), planID.String(), dto.ComparePlanRequest{
+			Candidates: []dto.CalculatePlanRequest{{Backend: "nope"}},
+		})
+		assert.ErrorIs(t, err, service.ErrUnknownBackend)
+	})
+
+	t.Run("invalid_plan_id", func(t *testing.T) {
+		_, err := s.ComparePlan(authedPlannerCtx(), "bad", dto.ComparePlanRequest{})
+		assert.Error(t, err)
+	})
+}
+
+func TestPlanService_AdoptAlternative(t *testing.T) {
+	planID := uuid.New()
+	workspaceID := uuid.New()
+	itemID := uuid.New()
+	quantity := int32(2)
+
+	var saved []store.CreatePlanPlacementParams
+	var savedStatus string
+	mockQ := &MockQuerier{
+		GetLoadPlanFunc: func(ctx context.Context, arg store.GetLoadPlanParams) (store.LoadPlan, error) {
+			return store.LoadPlan{
+				PlanID:      planID,
+				WorkspaceID: &workspaceID,
+				Status:      stringPtr(types.PlanStatusDraft.String()),
+				LengthMm:    toNumeric(1000.0),
+				WidthMm:     toNumeric(1000.0),
+				HeightMm:    toNumeric(1000.0),
+				MaxWeightKg: toNumeric(100.0),
+			}, nil
+		},
+		ListLoadItemsFunc: func(ctx context.Context, planIDPtr *uuid.UUID) ([]store.LoadItem, error) {
+			return []store.LoadItem{{
+				ItemID:    itemID,
+				LengthMm:  toNumeric(500.0),
+				WidthMm:   toNumeric(500.0),
+				HeightMm:  toNumeric(500.0),
+				WeightKg:  toNumeric(10.0),
+				Quantity:  quantity,
+				Stackable: true,
+			}}, nil
+		},
+		LockLoadPlanFunc:            lockPlanAs(stringPtr(types.PlanStatusDraft.String())),
+		CreatePlanStatusHistoryFunc: acceptStatusHistory,
+		DeactivatePlanResultsFunc: func(ctx context.Context, planIDPtr *uuid.UUID) error {
+			return nil
+		},
+		CreatePlanResultFunc: func(ctx context.Context, arg store.CreatePlanResultParams) (store.PlanResult, error) {
+			return store.PlanResult{ResultID: uuid.New(), PlanID: arg.PlanID, Version: 1, IsFeasible: arg.IsFeasible}, nil
+		},
+		CreatePlanPlacementFunc: func(ctx context.Context, arg []store.CreatePlanPlacementParams) (int64, error) {
+			saved = arg
+			return int64(len(arg)), nil
+		},
+		UpdatePlanStatusFunc: func(ctx context.Context, arg store.UpdatePlanStatusParams) error {
+			savedStatus = *arg.Status
+			return nil
+		},
+	}
+
+	// Every run places the first unit further along, so a repack would not
+	// reproduce the compared layout.
+	calls := 0
+	shifting := &MockPacker{
+		PackFunc: func(ctx context.Context, container packer.ContainerInput, items []packer.ItemInput) (packer.PackingResult, error) {
+			calls++
+			x := float64(calls * 10)
+			return packer.PackingResult{
+				Algorithm:  "shifting",
+				IsFeasible: true,
+				PackedItems: []packer.PackedItem{
+					{ItemID: itemID.String(), Position: packer.Position{X: x}, RotatedLength: 500, RotatedWidth: 500, RotatedHeight: 500},
+					{ItemID: itemID.String(), Position: packer.Position{Y: 500}, RotatedLength: 500, RotatedWidth: 500, RotatedHeight: 500},
+				},
+				TotalPackedItems: 2,
+			}, nil
+		},
+	}
+	s := service.NewPlanService(mockQ, shifting)
+	ctx := authedPlannerCtx()
+
+	compare := func(t *testing.T) dto.PlanAlternative {
+		t.Helper()
+		res, err := s.ComparePlan(ctx, planID.String(), dto.ComparePlanRequest{
+			Candidates: []dto.CalculatePlanRequest{{Strategy: "bestfitdecreasing"}},
+		})
+		require.NoError(t, err)
+		require.Len(t, res.Alternatives, 1)
+		require.NotEmpty(t, res.Alternatives[0].AlternativeID)
+		return res.Alternatives[0]
+	}
+
+	t.Run("saves_compared_placements", func(t *testing.T) {
+		alt := compare(t)
+		packs := calls
+
+		res, err := s.AdoptAlternative(ctx, planID.String(), alt.AlternativeID)
+		require.NoError(t, err)
+		assert.Equal(t, packs, calls)
+		assert.Equal(t, types.PlanStatusPlanned.String(), res.Status)
+		assert.Equal(t, types.PlanStatusPlanned.String(), savedStatus)
+		require.Len(t, saved, 2)
+		assert.Equal(t, toNumeric(alt.Placements[0].PositionX), saved[0].PosX)
+		assert.Equal(t, alt.Placements, res.Placements)
+
+		_, err = s.AdoptAlternative(ctx, planID.String(), alt.AlternativeID)
+		assert.ErrorIs(t, err, service.ErrAlternativeNotFound)
+	})
+
+	t.Run("items_changed", func(t *testing.T) {
+		alt := compare(t)
+		quantity = 3
+		defer func() { quantity = 2 }()
+
+		_, err := s.AdoptAlternative(ctx, planID.String(), alt.AlternativeID)
+		assert.ErrorIs(t, err, service.ErrStaleAlternative)
+	})
+
+	t.Run("unknown_alternative", func(t *testing.T) {
+		alt := compare(t)
+		other := service.NewPlanService(mockQ, shifting)
+
+		_, err := other.AdoptAlternative(ctx, planID.String(), alt.AlternativeID)
+		assert.ErrorIs(t, err, service.ErrAlternativeNotFound)
+		_, err = s.AdoptAlternative(ctx, planID.String(), uuid.New().String())
+		assert.ErrorIs(t, err, service.ErrAlternativeNotFound)
+	})
+}
+
+func TestPlanService_CreateScenario(t *testing.T) {
+	planID := uuid.New()
+	workspaceID := uuid.New()
+	keepID := uuid.New()
+	dropID := uuid.New()
+
+	basePlan := store.LoadPlan{
+		PlanID:      planID,
+		PlanCode:    "PLD-1",
+		WorkspaceID: &workspaceID,
+		LengthMm:    toNumeric(12000.0),
+		WidthMm:     toNumeric(2350.0),
+		HeightMm:    toNumeric(2690.0),
+		MaxWeightKg: toNumeric(28000.0),
+	}
+	baseItems := []store.LoadItem{
+		{ItemID: keepID, Quantity: 10, LengthMm: toNumeric(100.0), WidthMm: toNumeric(100.0), HeightMm: toNumeric(100.0), WeightKg: toNumeric(1.0), EachesPerUnit: int32Ptr(12), OrderedEaches: int32Ptr(120)},
+		{ItemID: dropID, Quantity: 5, LengthMm: toNumeric(100.0), WidthMm: toNumeric(100.0), HeightMm: toNumeric(100.0), WeightKg: toNumeric(1.0)},
+	}
+
+	t.Run("copies_items_with_overrides", func(t *testing.T) {
+		scenarioID := uuid.New()
+		var created store.CreateScenarioPlanParams
+		var copied []store.AddLoadItemParams
+
+		mockQ := &MockQuerier{
+			GetLoadPlanFunc: func(ctx context.Context, arg store.GetLoadPlanParams) (store.LoadPlan, error) {
+				return basePlan, nil
+			},
+			ListPlanScenariosFunc: func(ctx context.Context, parentPlanID *uuid.UUID) ([]store.LoadPlan, error) {
+				assert.Equal(t, planID, *parentPlanID)
+				return nil, nil
+			},
+			ListLoadItemsFunc: func(ctx context.Context, id *uuid.UUID) ([]store.LoadItem, error) {
+				return baseItems, nil
+			},
+			CreateScenarioPlanFunc: func(ctx context.Context, arg store.CreateScenarioPlanParams) (store.LoadPlan, error) {
+				created = arg
+				return store.LoadPlan{PlanID: scenarioID, ParentPlanID: arg.ParentPlanID, ScenarioName: arg.ScenarioName, PlanCode: arg.PlanCode, Status: arg.Status}, nil
+			},
+			AddLoadItemFunc: func(ctx context.Context, arg store.AddLoadItemParams) (store.LoadItem, error) {
+				copied = append(copied, arg)
+				return store.LoadItem{}, nil
+			},
+		}
+
+		s := service.NewPlanService(mockQ, nil)
+		length := 5900.0
+		res, err := s.CreateScenario(authedPlannerCtx(), planID.String(), dto.CreateScenarioRequest{
+			Name:              "20GP",
+			Container:         &dto.CreatePlanContainer{LengthMM: &length},
+			ExcludeItemIDs:    []string{dropID.String()},
+			QuantityOverrides: map[string]int{keepID.String(): 4},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, scenarioID.String(), res.PlanID)
+		assert.Equal(t, planID.String(), res.ParentPlanID)
+		assert.Equal(t, "20GP", res.Name)
+		assert.Equal(t, &workspaceID, created.WorkspaceID)
+		assert.Equal(t, toNumeric(5900.0), created.LengthMm)
+		assert.Equal(t, basePlan.WidthMm, created.WidthMm)
+		if assert.Len(t, copied, 1) {
+			assert.Equal(t, scenarioID, *copied[0].PlanID)
+			assert.Equal(t, int32(4), copied[0].Quantity)
+			assert.Equal(t, int32(48), *copied[0].OrderedEaches)
+		}
+	})
+
+	t.Run("unknown_items", func(t *testing.T) {
+		mockQ := &MockQuerier{
+			GetLoadPlanFunc: func(ctx context.Context, arg store.GetLoadPlanParams) (store.LoadPlan, error) {
+				return basePlan, nil
+			},
+			ListPlanScenariosFunc: func(ctx context.Context, parentPlanID *uuid.UUID) ([]store.LoadPlan, error) {
+				return nil, nil
+			},
+			ListLoadItemsFunc: func(ctx context.Context, id *uuid.UUID) ([]store.LoadItem, error) {
+				return baseItems, nil
+			},
+			CreateScenarioPlanFunc: func(ctx context.Context, arg store.CreateScenarioPlanParams) (store.LoadPlan, error) {
+				t.Fatal("scenario must not be created")
+				return store.LoadPlan{}, nil
+			},
+		}
+		s := service.NewPlanService(mockQ, nil)
+
+		unknown := uuid.NewString()
+		_, err := s.CreateScenario(authedPlannerCtx(), planID.String(), dto.CreateScenarioRequest{Name: "typo", ExcludeItemIDs: []string{unknown}})
+		assert.ErrorIs(t, err, service.ErrInvalidScenarioItems)
+		assert.Contains(t, err.Error(), unknown)
+
+		_, err = s.CreateScenario(authedPlannerCtx(), planID.String(), dto.CreateScenarioRequest{Name: "typo", QuantityOverrides: map[string]int{unknown: 3}})
+		assert.ErrorIs(t, err, service.ErrInvalidScenarioItems)
+	})
+
+	t.Run("failed_copy_rolls_back", func(t *testing.T) {
+		var rolledBack bool
+		mockQ := &MockQuerier{
+			GetLoadPlanFunc: func(ctx context.Context, arg store.GetLoadPlanParams) (store.LoadPlan, error) {
+				return basePlan, nil
+			},
+			ListPlanScenariosFunc: func(ctx context.Context, parentPlanID *uuid.UUID) ([]store.LoadPlan, error) {
+				return nil, nil
+			},
+			ListLoadItemsFunc: func(ctx context.Context, id *uuid.UUID) ([]store.LoadItem, error) {
+				return baseItems, nil
+			},
+			CreateScenarioPlanFunc: func(ctx context.Context, arg store.CreateScenarioPlanParams) (store.LoadPlan, error) {
+				return store.LoadPlan{PlanID: uuid.New()}, nil
+			},
+			AddLoadItemFunc: func(ctx context.Context, arg store.AddLoadItemParams) (store.LoadItem, error) {
+				return store.LoadItem{}, fmt.Errorf("db down")
+			},
+		}
+		mockQ.ExecTxFunc = func(ctx context.Context, fn func(store.Querier) error) error {
+			err := fn(mockQ)
+			rolledBack = err != nil
+			return err
+		}
+
+		s := service.NewPlanService(mockQ, nil)
+		_, err := s.CreateScenario(authedPlannerCtx(), planID.String(), dto.CreateScenarioRequest{Name: "broken"})
+		assert.Error(t, err)
+		assert.True(t, rolledBack)
+	})
+
+	t.Run("fork_of_scenario_attaches_to_base", func(t *testing.T) {
+		scenario := basePlan
+		scenario.PlanID = uuid.New()
+		scenario.ParentPlanID = &planID
+
+		mockQ := &MockQuerier{
+			GetLoadPlanFunc: func(ctx context.Context, arg store.GetLoadPlanParams) (store.LoadPlan, error) {
+				return scenario, nil
+			},
+			ListPlanScenariosFunc: func(ctx context.Context, parentPlanID *uuid.UUID) ([]store.LoadPlan, error) {
+				return []store.LoadPlan{scenario}, nil
+			},
+			ListLoadItemsFunc: func(ctx context.Context, id *uuid.UUID) ([]store.LoadItem, error) {
+				assert.Equal(t, scenario.PlanID, *id)
+				return nil, nil
+			},
+			CreateScenarioPlanFunc: func(ctx context.Context, arg store.CreateScenarioPlanParams) (store.LoadPlan, error) {
+				assert.Equal(t, planID, *arg.ParentPlanID)
+				return store.LoadPlan{PlanID: uuid.New(), ParentPlanID: arg.ParentPlanID}, nil
+			},
+		}
+
+		s := service.NewPlanService(mockQ, nil)
+		_, err := s.CreateScenario(authedPlannerCtx(), scenario.PlanID.String(), dto.CreateScenarioRequest{Name: "copy"})
+		assert.NoError(t, err)
+	})
+
+	t.Run("limit_reached", func(t *testing.T) {
+		mockQ := &MockQuerier{
+			GetLoadPlanFunc: func(ctx context.Context, arg store.GetLoadPlanParams) (store.LoadPlan, error) {
+				return basePlan, nil
+			},
+			ListPlanScenariosFunc: func(ctx context.Context, parentPlanID *uuid.UUID) ([]store.LoadPlan, error) {
+				return make([]store.LoadPlan, 10), nil
+			},
+		}
+
+		s := service.NewPlanService(mockQ, nil)
+		_, err := s.CreateScenario(authedPlannerCtx(), planID.String(), dto.CreateScenarioRequest{Name: "one too many"})
+		assert.ErrorIs(t, err, service.ErrScenarioLimitReached)
+	})
+}
+
+func TestPlanService_CompareScenarios(t *testing.T) {
+	baseID := uuid.New()
+	scenarioID := uuid.New()
+	workspaceID := uuid.New()
+	itemID := uuid.New()
+	resultID := uuid.New()
+	name := "20GP"
+	label := "Carton"
+
+	base := store.LoadPlan{
+		PlanID:      baseID,
+		PlanCode:    "PLD-1",
+		WorkspaceID: &workspaceID,
+		LengthMm:    toNumeric(12000.0),
+		WidthMm:     toNumeric(2000.0),
+		HeightMm:    toNumeric(2000.0),
+		MaxWeightKg: toNumeric(1000.0),
+	}
+	scenario := base
+	scenario.PlanID = scenarioID
+	scenario.ParentPlanID = &baseID
+	scenario.ScenarioName = &name
+
+	mockQ := &MockQuerier{
+		GetLoadPlanFunc: func(ctx context.Context, arg store.GetLoadPlanParams) (store.LoadPlan, error) {
+			return scenario, nil
+		},
+		GetLoadPlanAnyFunc: func(ctx context.Context, id uuid.UUID) (store.LoadPlan, error) {
+			assert.Equal(t, baseID, id)
+			return base, nil
+		},
+		ListPlanScenariosFunc: func(ctx context.Context, parentPlanID *uuid.UUID) ([]store.LoadPlan, error) {
+			return []store.LoadPlan{scenario}, nil
+		},
+		ListLoadItemsFunc: func(ctx context.Context, id *uuid.UUID) ([]store.LoadItem, error) {
+			return []store.LoadItem{{
+				ItemID:    itemID,
+				ItemLabel: &label,
+				Quantity:  3,
+				LengthMm:  toNumeric(1000.0),
+				WidthMm:   toNumeric(1000.0),
+				HeightMm:  toNumeric(1000.0),
+				WeightKg:  toNumeric(100.0),
+			}}, nil
+		},
+		GetPlanResultFunc: func(ctx context.Context, id *uuid.UUID) (store.PlanResult, error) {
+			if *id == baseID {
+				return store.PlanResult{}, sql.ErrNoRows
+			}
+			return store.PlanResult{ResultID: resultID, VolumeUtilizationPct: toNumeric(4.17), TotalLoadedWeightKg: toNumeric(200.0)}, nil
+		},
+		ListPlanPlacementsFunc: func(ctx context.Context, id *uuid.UUID) ([]store.PlanPlacement, error) {
+			return []store.PlanPlacement{{ItemID: &itemID}, {ItemID: &itemID}}, nil
+		},
+	}
+
+	s := service.NewPlanService(mockQ, nil)
+	res, err := s.CompareScenarios(authedPlannerCtx(), scenarioID.String())
+
+	assert.NoError(t, err)
+	assert.Equal(t, baseID.String(), res.BasePlanID)
+	if assert.Len(t, res.Scenarios, 2) {
+		b := res.Scenarios[0]
+		assert.True(t, b.IsBase)
+		assert.False(t, b.Calculated)
+		assert.Equal(t, "PLD-1", b.Name)
+		assert.Equal(t, 3, b.Stats.TotalItems)
+		assert.Empty(t, b.UnfitItems)
+
+		sc := res.Scenarios[1]
+		assert.Equal(t, "20GP", sc.Name)
+		assert.True(t, sc.Calculated)
+		assert.Equal(t, 2, sc.PackedItems)
+		assert.InDelta(t, 20.0, sc.Stats.WeightUtilizationPct, 1e-9)
+		if assert.Len(t, sc.UnfitItems, 1) {
+			assert.Equal(t, itemID.String(), sc.UnfitItems[0].ItemID)
+			assert.Equal(t, 1, sc.UnfitItems[0].Quantity)
+		}
+	}
+}
+
+func TestPlanService_PreflightPlan(t *testing.T) {
+	planID := uuid.New()
+	workspaceID := uuid.New()
+	bigID, okID := uuid.New(), uuid.New()
+
+	mq := &MockQuerier{
+		GetLoadPlanFunc: func(ctx context.Context, arg store.GetLoadPlanParams) (store.LoadPlan, error) {
+			return store.LoadPlan{
+				PlanID:      planID,
+				WorkspaceID: &workspaceID,
+				LengthMm:    toNumeric(1000.0),
+				WidthMm:     toNumeric(1000.0),
+				HeightMm:    toNumeric(1000.0),
+				MaxWeightKg: toNumeric(50.0),
+			}, nil
+		},
+		ListLoadItemsFunc: func(ctx context.Context, planIDPtr *uuid.UUID) ([]store.LoadItem, error) {
+			label := "Pipe"
+			return []store.LoadItem{
+				{ItemID: bigID, ItemLabel: &label, LengthMm: toNumeric(1200.0), WidthMm: toNumeric(100.0), HeightMm: toNumeric(100.0), WeightKg: toNumeric(5.0), Quantity: 2},
+				{ItemID: okID, LengthMm: toNumeric(100.0), WidthMm: toNumeric(100.0), HeightMm: toNumeric(100.0), WeightKg: toNumeric(10.0), Quantity: 5},
+			}, nil
+		},
+	}
+
+	s := service.NewPlanService(mq, &MockPacker{})
+	resp, err := s.PreflightPlan(authedPlannerCtx(), planID.String())
+	require.NoError(t, err)
+
+	assert.False(t, resp.OK)
+	assert.True(t, resp.Overweight) // 2x5 + 5x10 = 60 kg
+	assert.False(t, resp.OverVolume)
+	assert.Equal(t, 60.0, resp.TotalWeightKG)
+	require.Len(t, resp.Issues, 1)
+	assert.Equal(t, bigID.String(), resp.Issues[0].ItemID)
+	assert.Equal(t, packer.UnfitTooLarge, resp.Issues[0].Code)
+	assert.Equal(t, "Pipe", *resp.Issues[0].Label)
+	assert.Equal(t, 2, resp.Issues[0].Quantity)
+}
+
+func TestPlanService_CreateOverflowPlan(t *testing.T) {
+	sourceID := uuid.New()
+	workspaceID := uuid.New()
+	resultID := uuid.New()
+	packedID, leftID := uuid.New(), uuid.New()
+	label := "Crate"
+
+	source := store.LoadPlan{
+		PlanID:      sourceID,
+		PlanCode:    "PLD-1",
+		WorkspaceID: &workspaceID,
+		LengthMm:    toNumeric(5900.0),
+		WidthMm:     toNumeric(2350.0),
+		HeightMm:    toNumeric(2390.0),
+		MaxWeightKg: toNumeric(20000.0),
+	}
+	items := []store.LoadItem{
+		{ItemID: packedID, Quantity: 2, LengthMm: toNumeric(1000.0), WidthMm: toNumeric(1000.0), HeightMm: toNumeric(1000.0), WeightKg: toNumeric(10.0)},
+		{ItemID: leftID, ItemLabel: &label, Quantity: 5, Priority: 3, MustShip: true, LengthMm: toNumeric(1000.0), WidthMm: toNumeric(800.0), HeightMm: toNumeric(600.0), WeightKg: toNumeric(50.0)},
+	}
+
+	newQuerier := func() *MockQuerier {
+		return &MockQuerier{
+			GetLoadPlanFunc: func(ctx context.Context, arg store.GetLoadPlanParams) (store.LoadPlan, error) {
+				return source, nil
+			},
+			ListLoadItemsFunc: func(ctx context.Context, id *uuid.UUID) ([]store.LoadItem, error) {
+				return items, nil
+			},
+			GetPlanResultFunc: func(ctx context.Context, id *uuid.UUID) (store.PlanResult, error) {
+				return store.PlanResult{ResultID: resultID}, nil
+			},
+			ListPlanPlacementsFunc: func(ctx context.Context, id *uuid.UUID) ([]store.PlanPlacement, error) {
+				return []store.PlanPlacement{{ItemID: &packedID}, {ItemID: &packedID}, {ItemID: &leftID}, {ItemID: &leftID}}, nil
+			},
+			LockLoadPlanFunc: lockPlanAs(nil),
+			SetPlanShipmentGroupFunc: func(ctx context.Context, arg store.SetPlanShipmentGroupParams) error {
+				return nil
+			},
+			ListShipmentGroupPlansFunc: func(ctx context.Context, id *uuid.UUID) ([]store.LoadPlan, error) {
+				grouped := source
+				grouped.ShipmentGroupID = &sourceID
+				return []store.LoadPlan{grouped}, nil
+			},
+		}
+	}
+
+	t.Run("copies_leftovers", func(t *testing.T) {
+		mq := newQuerier()
+		var grouped store.SetPlanShipmentGroupParams
+		mq.SetPlanShipmentGroupFunc = func(ctx context.Context, arg store.SetPlanShipmentGroupParams) error {
+			grouped = arg
+			return nil
+		}
+		var created store.CreateOverflowPlanParams
+		mq.CreateOverflowPlanFunc = func(ctx context.Context, arg store.CreateOverflowPlanParams) (store.LoadPlan, error) {
+			created = arg
+			return store.LoadPlan{
+				PlanID:           uuid.New(),
+				PlanCode:         arg.PlanCode,
+				OverflowOfPlanID: arg.OverflowOfPlanID,
+				ShipmentGroupID:  arg.ShipmentGroupID,
+				LengthMm:         arg.LengthMm,
+				WidthMm:          arg.WidthMm,
+				HeightMm:         arg.HeightMm,
+				MaxWeightKg:      arg.MaxWeightKg,
+			}, nil
+		}
+		var added []store.AddLoadItemParams
+		mq.AddLoadItemFunc = func(ctx context.Context, arg store.AddLoadItemParams) (store.LoadItem, error) {
+			added = append(added, arg)
+			return store.LoadItem{}, nil
+		}
+
+		s := service.NewPlanService(mq, nil)
+		autoCalc := false
+		res, err := s.CreateOverflowPlan(authedPlannerCtx(), sourceID.String(), dto.CreateOverflowPlanRequest{AutoCalculate: &autoCalc})
+
+		assert.NoError(t, err)
+		assert.Equal(t, sourceID, grouped.PlanID)
+		assert.Equal(t, &sourceID, grouped.ShipmentGroupID)
+		assert.Equal(t, &sourceID, created.OverflowOfPlanID)
+		assert.Equal(t, &sourceID, created.ShipmentGroupID)
+		assert.Equal(t, "PLD-1-2", created.PlanCode)
+		assert.Equal(t, source.LengthMm, created.LengthMm)
+
+		if assert.Len(t, added, 1) {
+			assert.Equal(t, int32(3), added[0].Quantity)
+			assert.Equal(t, int32(3), added[0].Priority)
+			assert.True(t, added[0].MustShip)
+			assert.Equal(t, &label, added[0].ItemLabel)
+		}
+		assert.Equal(t, sourceID.String(), res.OverflowOfPlanID)
+		assert.Equal(t, sourceID.String(), res.ShipmentGroupID)
+		assert.Equal(t, 3, res.TotalItems)
+		assert.Equal(t, types.PlanStatusDraft.String(), res.Status)
+		assert.Nil(t, res.Calculation)
+	})
+
+	t.Run("creates_in_one_transaction", func(t *testing.T) {
+		mq := newQuerier()
+		var calls []string
+		mq.ExecTxFunc = func(ctx context.Context, fn func(store.Querier) error) error {
+			calls = append(calls, "begin")
+			err := fn(mq)
+			calls = append(calls, "end")
+			return err
+		}
+		mq.LockLoadPlanFunc = func(ctx context.Context, id uuid.UUID) (*string, error) {
+			assert.Equal(t, sourceID, id)
+			calls = append(calls, "lock")
+			return nil, nil
+		}
+		mq.SetPlanShipmentGroupFunc = func(ctx context.Context, arg store.SetPlanShipmentGroupParams) error {
+			calls = append(calls, "group")
+			return nil
+		}
+		mq.CreateOverflowPlanFunc = func(ctx context.Context, arg store.CreateOverflowPlanParams) (store.LoadPlan, error) {
+			calls = append(calls, "plan")
+			return store.LoadPlan{PlanID: uuid.New()}, nil
+		}
+		mq.AddLoadItemFunc = func(ctx context.Context, arg store.AddLoadItemParams) (store.LoadItem, error) {
+			calls = append(calls, "item")
+			return store.LoadItem{}, fmt.Errorf("insert failed")
+		}
+
+		s := service.NewPlanService(mq, nil)
+		autoCalc := false
+		_, err := s.CreateOverflowPlan(authedPlannerCtx(), sourceID.String(), dto.CreateOverflowPlanRequest{AutoCalculate: &autoCalc})
+
+		assert.ErrorContains(t, err, "insert failed")
+		assert.Equal(t, []string{"begin", "lock", "group", "plan", "item", "end"}, calls)
+	})
+
+	t.Run("rejects_second_overflow", func(t *testing.T) {
+		mq := newQuerier()
+		mq.ListShipmentGroupPlansFunc = func(ctx context.Context, id *uuid.UUID) ([]store.LoadPlan, error) {
+			grouped := source
+			grouped.ShipmentGroupID = &sourceID
+			overflow := grouped
+			overflow.PlanID = uuid.New()
+			overflow.PlanCode = "PLD-1-2"
+			overflow.OverflowOfPlanID = &sourceID
+			return []store.LoadPlan{grouped, overflow}, nil
+		}
+
+		s := service.NewPlanService(mq, nil)
+		_, err := s.CreateOverflowPlan(authedPlannerCtx(), sourceID.String(), dto.CreateOverflowPlanRequest{})
+		assert.ErrorIs(t, err, service.ErrOverflowExists)
+	})
+
+	t.Run("trial_limit", func(t *testing.T) {
+		guestID := uuid.New()
+		mq := newQuerier()
+		mq.GetLoadPlanForGuestFunc = func(ctx context.Context, arg store.GetLoadPlanForGuestParams) (store.LoadPlan, error) {
+			assert.Equal(t, guestID, arg.CreatedByID)
+			return source, nil
+		}
+		mq.CountPlansByCreatorFunc = func(ctx context.Context, arg store.CountPlansByCreatorParams) (int64, error) {
+			assert.Equal(t, "guest", arg.CreatedByType)
+			return 3, nil
+		}
+
+		s := service.NewPlanService(mq, nil)
+		_, err := s.CreateOverflowPlan(authedTrialCtxWithID(guestID), sourceID.String(), dto.CreateOverflowPlanRequest{})
+		assert.ErrorIs(t, err, service.ErrTrialLimitReached)
+	})
+
+	t.Run("recomputes_ordered_eaches", func(t *testing.T) {
+		mq := newQuerier()
+		eachesPerUnit, ordered := int32(12), int32(60)
+		withPacks := append([]store.LoadItem(nil), items...)
+		withPacks[1].EachesPerUnit = &eachesPerUnit
+		withPacks[1].OrderedEaches = &ordered
+		mq.ListLoadItemsFunc = func(ctx context.Context, id *uuid.UUID) ([]store.LoadItem, error) {
+			return withPacks, nil
+		}
+		mq.CreateOverflowPlanFunc = func(ctx context.Context, arg store.CreateOverflowPlanParams) (store.LoadPlan, error) {
+			return store.LoadPlan{PlanID: uuid.New()}, nil
+		}
+		var added []store.AddLoadItemParams
+		mq.AddLoadItemFunc = func(ctx context.Context, arg store.AddLoadItemParams) (store.LoadItem, error) {
+			added = append(added, arg)
+			return store.LoadItem{}, nil
+		}
+
+		s := service.NewPlanService(mq, nil)
+		autoCalc := false
+		_, err := s.CreateOverflowPlan(authedPlannerCtx(), sourceID.String(), dto.CreateOverflowPlanRequest{AutoCalculate: &autoCalc})
+
+		require.NoError(t, err)
+		require.Len(t, added, 1)
+		assert.Equal(t, int32(3), added[0].Quantity)
+		if assert.NotNil(t, added[0].OrderedEaches) {
+			assert.Equal(t, int32(36), *added[0].OrderedEaches)
+		}
+	})
+
+	t.Run("suggests_smallest_container", func(t *testing.T) {
+		mq := newQuerier()
+		mq.ListContainersFunc = func(ctx context.Context, arg store.ListContainersParams) ([]store.Container, error) {
+			return []store.Container{
+				{Name: "40HC", InnerLengthMm: toNumeric(12000.0), InnerWidthMm: toNumeric(2350.0), InnerHeightMm: toNumeric(2690.0), MaxWeightKg: toNumeric(26000.0)},
+				{Name: "Van", InnerLengthMm: toNumeric(3000.0), InnerWidthMm: toNumeric(1700.0), InnerHeightMm: toNumeric(1500.0), MaxWeightKg: toNumeric(1000.0)},
+				{Name: "Tiny", InnerLengthMm: toNumeric(900.0), InnerWidthMm: toNumeric(900.0), InnerHeightMm: toNumeric(900.0), MaxWeightKg: toNumeric(1000.0)},
+			}, nil
+		}
+		var created store.CreateOverflowPlanParams
+		mq.CreateOverflowPlanFunc = func(ctx context.Context, arg store.CreateOverflowPlanParams) (store.LoadPlan, error) {
+			created = arg
+			return store.LoadPlan{PlanID: uuid.New(), ContLabel: arg.ContLabel}, nil
+		}
+		mq.AddLoadItemFunc = func(ctx context.Context, arg store.AddLoadItemParams) (store.LoadItem, error) {
+			return store.LoadItem{}, nil
+		}
+
+		s := service.NewPlanService(mq, nil)
+		autoCalc := false
+		res, err := s.CreateOverflowPlan(authedPlannerCtx(), sourceID.String(), dto.CreateOverflowPlanRequest{SuggestContainer: true, AutoCalculate: &autoCalc})
+
+		assert.NoError(t, err)
+		assert.True(t, res.ContainerSuggested)
+		if assert.NotNil(t, created.ContLabel) {
+			assert.Equal(t, "Van", *created.ContLabel)
+		}
+	})
+
+	t.Run("nothing_to_overflow", func(t *testing.T) {
+		mq := newQuerier()
+		mq.ListPlanPlacementsFunc = func(ctx context.Context, id *uuid.UUID) ([]store.PlanPlacement, error) {
+			pl := make([]store.PlanPlacement, 0, 7)
+			for i := 0; i < 2; i++ {
+				pl = append(pl, store.PlanPlacement{ItemID: &packedID})
+			}
+			for i := 0; i < 5; i++ {
+				pl = append(pl, store.PlanPlacement{ItemID: &leftID})
+			}
+			return pl, nil
+		}
+
+		s := service.NewPlanService(mq, nil)
+		_, err := s.CreateOverflowPlan(authedPlannerCtx(), sourceID.String(), dto.CreateOverflowPlanRequest{})
+		assert.ErrorIs(t, err, service.ErrNothingToOverflow)
+	})
+
+	t.Run("not_calculated", func(t *testing.T) {
+		mq := newQuerier()
+		mq.GetPlanResultFunc = func(ctx context.Context, id *uuid.UUID) (store.PlanResult, error) {
+			return store.PlanResult{}, sql.ErrNoRows
+		}
+
+		s := service.NewPlanService(mq, nil)
+		_, err := s.CreateOverflowPlan(authedPlannerCtx(), sourceID.String(), dto.CreateOverflowPlanRequest{})
+		assert.ErrorIs(t, err, service.ErrNothingToOverflow)
+	})
+}
+
+func TestPlanService_GetShipmentGroup(t *testing.T) {
+	rootID, overflowID := uuid.New(), uuid.New()
+	workspaceID := uuid.New()
+	itemID, overflowItemID := uuid.New(), uuid.New()
+	resultID := uuid.New()
+
+	root := store.LoadPlan{
+		PlanID:          rootID,
+		PlanCode:        "PLD-1",
+		WorkspaceID:     &workspaceID,
+		ShipmentGroupID: &rootID,
+		LengthMm:        toNumeric(5900.0),
+		WidthMm:         toNumeric(2350.0),
+		HeightMm:        toNumeric(2390.0),
+	}
+	overflow := root
+	overflow.PlanID = overflowID
+	overflow.PlanCode = "PLD-1-2"
+	overflow.OverflowOfPlanID = &rootID
+
+	mq := &MockQuerier{
+		GetLoadPlanFunc: func(ctx context.Context, arg store.GetLoadPlanParams) (store.LoadPlan, error) {
+			return overflow, nil
+		},
+		ListShipmentGroupPlansFunc: func(ctx context.Context, id *uuid.UUID) ([]store.LoadPlan, error) {
+			assert.Equal(t, rootID, *id)
+			return []store.LoadPlan{root, overflow}, nil
+		},
+		ListLoadItemsFunc: func(ctx context.Context, id *uuid.UUID) ([]store.LoadItem, error) {
+			if *id == rootID {
+				return []store.LoadItem{{ItemID: itemID, Quantity: 10}}, nil
+			}
+			return []store.LoadItem{{ItemID: overflowItemID, Quantity: 4}}, nil
+		},
+	}
+	placed := map[uuid.UUID]int{rootID: 6, overflowID: 3}
+	var current uuid.UUID
+	mq.GetPlanResultFunc = func(ctx context.Context, id *uuid.UUID) (store.PlanResult, error) {
+		current = *id
+		return store.PlanResult{ResultID: resultID}, nil
+	}
+	mq.ListPlanPlacementsFunc = func(ctx context.Context, id *uuid.UUID) ([]store.PlanPlacement, error) {
+		item := itemID
+		if current == overflowID {
+			item = overflowItemID
+		}
+		pl := make([]store.PlanPlacement, placed[current])
+		for i := range pl {
+			pl[i].ItemID = &item
+		}
+		return pl, nil
+	}
+
+	s := service.NewPlanService(mq, nil)
+	res, err := s.GetShipmentGroup(authedPlannerCtx(), overflowID.String())
+
+	assert.NoError(t, err)
+	assert.Equal(t, rootID.String(), res.ShipmentGroupID)
+	if assert.Len(t, res.Plans, 2) {
+		assert.Nil(t, res.Plans[0].OverflowOfPlanID)
+		if assert.NotNil(t, res.Plans[1].OverflowOfPlanID) {
+			assert.Equal(t, rootID.String(), *res.Plans[1].OverflowOfPlanID)
+		}
+		assert.Equal(t, "PLD-1-2", res.Plans[1].PlanCode)
+	}
+	assert.Equal(t, 10, res.TotalItems)
+	assert.Equal(t, 9, res.PackedItems)
+	// The root's 4 unfit units moved on; 1 of them is still unplaced.
+	assert.Equal(t, 1, res.OutstandingUnits)
+}
+
+func TestPlanService_ResultVersions(t *testing.T) {
+	planID := uuid.New()
+	workspaceID := uuid.New()
+	itemID := uuid.New()
+
+	plan := store.LoadPlan{
+		PlanID:      planID,
+		WorkspaceID: &workspaceID,
+		Status:      stringPtr(types.PlanStatusPlanned.String()),
+		LengthMm:    toNumeric(6000.0),
+		WidthMm:     toNumeric(2350.0),
+		HeightMm:    toNumeric(2390.0),
+		MaxWeightKg: toNumeric(20000.0),
+	}
+	items := []store.LoadItem{{
+		ItemID:        itemID,
+		ItemLabel:     stringPtr("crate"),
+		Quantity:      2,
+		LengthMm:      toNumeric(1000.0),
+		WidthMm:       toNumeric(1000.0),
+		HeightMm:      toNumeric(1000.0),
+		WeightKg:      toNumeric(100.0),
+		AllowRotation: boolPtr(true),
+	}}
+	placement := func(x float64) store.PlanPlacement {
+		return store.PlanPlacement{PlacementID: uuid.New(), ItemID: &itemID, PosX: toNumeric(x), PosY: toNumeric(0), PosZ: toNumeric(0)}
+	}
+
+	t.Run("calculation_saves_a_new_active_version", func(t *testing.T) {
+		var deactivated bool
+		var saved store.CreatePlanResultParams
+		mockQ := &MockQuerier{
+			GetLoadPlanFunc: func(ctx context.Context, arg store.GetLoadPlanParams) (store.LoadPlan, error) {
+				return plan, nil
+			},
+			ListLoadItemsFunc: func(ctx context.Context, id *uuid.UUID) ([]store.LoadItem, error) {
+				return items, nil
+			},
+			LockLoadPlanFunc:            lockPlanAs(nil),
+			CreatePlanStatusHistoryFunc: acceptStatusHistory,
+			DeactivatePlanResultsFunc: func(ctx context.Context, id *uuid.UUID) error {
+				deactivated = true
+				return nil
+			},
+			CreatePlanResultFunc: func(ctx context.Context, arg store.CreatePlanResultParams) (store.PlanResult, error) {
+				assert.True(t, deactivated, "older versions are deactivated first")
+				saved = arg
+				return store.PlanResult{ResultID: uuid.New(), PlanID: arg.PlanID, Version: 3, IsActive: true}, nil
+			},
+			CreatePlanPlacementFunc: func(ctx context.Context, arg []store.CreatePlanPlacementParams) (int64, error) {
+				return int64(len(arg)), nil
+			},
+			UpdatePlanStatusFunc: func(ctx context.Context, arg store.UpdatePlanStatusParams) error {
+				return nil
+			},
+		}
+		mockP := &MockPacker{
+			PackFunc: func(ctx context.Context, container packer.ContainerInput, in []packer.ItemInput) (packer.PackingResult, error) {
+				return packer.PackingResult{IsFeasible: true, Algorithm: "test-algorithm"}, nil
+			},
+		}
+
+		s := service.NewPlanService(mockQ, mockP)
+		res, err := s.CalculatePlan(authedPlannerCtx(), planID.String(), dto.CalculatePlanRequest{Strategy: "bestfitdecreasing"})
+		require.NoError(t, err)
+
+		assert.Equal(t, 3, res.Version)
+		require.NotNil(t, saved.Algorithm)
+		assert.Equal(t, "test-algorithm", *saved.Algorithm)
+
+		var inputs dto.ResultInputs
+		require.NoError(t, json.Unmarshal(saved.Inputs, &inputs))
+		assert.Equal(t, "default", inputs.Backend)
+		assert.Equal(t, "bestfitdecreasing", inputs.Options.Strategy)
+		assert.Equal(t, 6000.0, inputs.Container.LengthMM)
+		require.Len(t, inputs.Items, 1)
+		assert.Equal(t, itemID.String(), inputs.Items[0].ItemID)
+		assert.Equal(t, 2, inputs.Items[0].Quantity)
+	})
+
+	snapshot := func(qty int32) []byte {
+		it := items[0]
+		it.Quantity = qty
+		// Capture the snapshot a calculation of these items saves.
+		var raw []byte
+		mockQ := &MockQuerier{
+			GetLoadPlanFunc: func(ctx context.Context, arg store.GetLoadPlanParams) (store.LoadPlan, error) {
+				return plan, nil
+			},
+			ListLoadItemsFunc: func(ctx context.Context, id *uuid.UUID) ([]store.LoadItem, error) {
+				return []store.LoadItem{it}, nil
+			},
+			LockLoadPlanFunc:            lockPlanAs(nil),
+			CreatePlanStatusHistoryFunc: acceptStatusHistory,
+			DeactivatePlanResultsFunc:   func(ctx context.Context, id *uuid.UUID) error { return nil },
+			CreatePlanResultFunc: func(ctx context.Context, arg store.CreatePlanResultParams) (store.PlanResult, error) {
+				raw = arg.Inputs
+				return store.PlanResult{ResultID: uuid.New()}, nil
+			},
+			UpdatePlanStatusFunc: func(ctx context.Context, arg store.UpdatePlanStatusParams) error { return nil },
+		}
+		mockP := &MockPacker{
+			PackFunc: func(ctx context.Context, container packer.ContainerInput, in []packer.ItemInput) (packer.PackingResult, error) {
+				return packer.PackingResult{IsFeasible: true}, nil
+			},
+		}
+		_, err := service.NewPlanService(mockQ, mockP).CalculatePlan(authedPlannerCtx(), planID.String(), dto.CalculatePlanRequest{})
+		require.NoError(t, err)
+		return raw
+	}
+
+	v1 := store.PlanResult{ResultID: uuid.New(), PlanID: &planID, Version: 1, IsFeasible: boolPtr(true), Algorithm: stringPtr("a"), Inputs: snapshot(2), VolumeUtilizationPct: toNumeric(10.0)}
+	v2 := store.PlanResult{ResultID: uuid.New(), PlanID: &planID, Version: 2, IsFeasible: boolPtr(false), Algorithm: stringPtr("b"), Inputs: snapshot(3), VolumeUtilizationPct: toNumeric(15.0), IsActive: true}
+	placements := map[uuid.UUID][]store.PlanPlacement{
+		v1.ResultID: {placement(0), placement(1000)},
+		v2.ResultID: {placement(0), placement(2000), placement(3000)},
+	}
+
+	versionQuerier := func() *MockQuerier {
+		return &MockQuerier{
+			GetLoadPlanFunc: func(ctx context.Context, arg store.GetLoadPlanParams) (store.LoadPlan, error) {
+				return plan, nil
+			},
+			ListLoadItemsFunc: func(ctx context.Context, id *uuid.UUID) ([]store.LoadItem, error) {
+				return items, nil
+			},
+			ListPlanResultsFunc: func(ctx context.Context, id *uuid.UUID) ([]store.PlanResult, error) {
+				return []store.PlanResult{v2, v1}, nil
+			},
+			GetPlanResultVersionFunc: func(ctx context.Context, arg store.GetPlanResultVersionParams) (store.PlanResult, error) {
+				switch arg.Version {
+				case 1:
+					return v1, nil
+				case 2:
+					return v2, nil
+				}
+				return store.PlanResult{}, fmt.Errorf("no rows in result set")
+			},
+			ListPlanPlacementsFunc: func(ctx context.Context, id *uuid.UUID) ([]store.PlanPlacement, error) {
+				return placements[*id], nil
+			},
+		}
+	}
+
+	t.Run("list_and_get", func(t *testing.T) {
+		s := service.NewPlanService(versionQuerier(), packer.NewPacker())
+
+		list, err := s.ListResultVersions(authedPlannerCtx(), planID.String())
+		require.NoError(t, err)
+		require.Len(t, list, 2)
+		assert.Equal(t, 2, list[0].Version)
+		assert.True(t, list[0].IsActive)
+		assert.Nil(t, list[0].Inputs)
+
+		v, err := s.GetResultVersion(authedPlannerCtx(), planID.String(), 1)
+		require.NoError(t, err)
+		assert.Equal(t, "a", v.Algorithm)
+		require.NotNil(t, v.Inputs)
+		assert.Len(t, v.Placements, 2)
+
+		_, err = s.GetResultVersion(authedPlannerCtx(), planID.String(), 9)
+		assert.ErrorIs(t, err, service.ErrResultVersionNotFound)
+	})
+
+	t.Run("restore", func(t *testing.T) {
+		var activated uuid.UUID
+		var history store.CreatePlanStatusHistoryParams
+		mockQ := versionQuerier()
+		mockQ.GetLoadPlanFunc = func(ctx context.Context, arg store.GetLoadPlanParams) (store.LoadPlan, error) {
+			p := plan
+			p.Status = stringPtr(types.PlanStatusPartial.String())
+			return p, nil
+		}
+		mockQ.LockLoadPlanFunc = lockPlanAs(stringPtr(types.PlanStatusPartial.String()))
+		mockQ.DeactivatePlanResultsFunc = func(ctx context.Context, id *uuid.UUID) error { return nil }
+		mockQ.ActivatePlanResultFunc = func(ctx context.Context, id uuid.UUID) error {
+			activated = id
+			return nil
+		}
+		mockQ.UpdatePlanStatusFunc = func(ctx context.Context, arg store.UpdatePlanStatusParams) error { return nil }
+		mockQ.CreatePlanStatusHistoryFunc = func(ctx context.Context, arg store.CreatePlanStatusHistoryParams) (store.PlanStatusHistory, error) {
+			history = arg
+			return store.PlanStatusHistory{}, nil
+		}
+
+		s := service.NewPlanService(mockQ, packer.NewPacker())
+		v, err := s.RestoreResultVersion(authedPlannerCtx(), planID.String(), 1)
+		require.NoError(t, err)
+		assert.True(t, v.IsActive)
+		assert.Equal(t, v1.ResultID, activated)
+		assert.Equal(t, types.PlanStatusPlanned.String(), history.ToStatus)
+		assert.Equal(t, types.PlanStatusPartial.String(), *history.FromStatus)
+		require.NotNil(t, history.Reason)
+		assert.Equal(t, "restored result version 1", *history.Reason)
+
+		// Version 2 was calculated for three crates; the plan now has two.
+		_, err = s.RestoreResultVersion(authedPlannerCtx(), planID.String(), 2)
+		assert.ErrorIs(t, err, service.ErrStaleResultVersion)
+	})
+
+	t.Run("restore_locked_plan", func(t *testing.T) {
+		mockQ := versionQuerier()
+		mockQ.GetLoadPlanFunc = func(ctx context.Context, arg store.GetLoadPlanParams) (store.LoadPlan, error) {
+			p := plan
+			p.Status = stringPtr(types.PlanStatusInProgress.String())
+			return p, nil
+		}
+		s := service.NewPlanService(mockQ, packer.NewPacker())
+		_, err := s.RestoreResultVersion(authedPlannerCtx(), planID.String(), 1)
+		assert.ErrorIs(t, err, service.ErrInvalidStatusTransition)
+	})
+
+	t.Run("diff", func(t *testing.T) {
+		s := service.NewPlanService(versionQuerier(), packer.NewPacker())
+		d, err := s.DiffResultVersions(authedPlannerCtx(), planID.String(), 1, 2)
+		require.NoError(t, err)
+
+		assert.Equal(t, "a", d.FromAlgorithm)
+		assert.Equal(t, "b", d.ToAlgorithm)
+		assert.True(t, d.FromFeasible)
+		assert.False(t, d.ToFeasible)
+		assert.InDelta(t, 5.0, d.VolumeUtilizationDelta, 1e-9)
+		assert.False(t, d.ContainerChanged)
+		assert.False(t, d.OptionsChanged)
+		require.Len(t, d.Items, 1)
+		assert.Equal(t, dto.ResultItemDiff{
+			ItemID: itemID.String(), Label: "crate", Change: "changed",
+			FromQuantity: 2, ToQuantity: 3, FromPacked: 2, ToPacked: 3,
+		}, d.Items[0])
+		assert.Equal(t, 1, d.UnitsUnchanged)
+		assert.Equal(t, 2, d.UnitsMoved)
+	})
+}
+
+func TestPlanService_HandlingUnits(t *testing.T) {
+	ctx := authedPlannerCtx()
+	planID, resultID, wsID := uuid.New(), uuid.New(), uuid.New()
+	items := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+
+	newQuerier := func(gs1 *store.WorkspaceGs1, units *[]store.HandlingUnit) *MockQuerier {
+		return &MockQuerier{
+			GetLoadPlanFunc: func(ctx context.Context, arg store.GetLoadPlanParams) (store.LoadPlan, error) {
+				return store.LoadPlan{PlanID: planID, PlanCode: "PLN-1", WorkspaceID: &wsID}, nil
+			},
+			GetPlanResultFunc: func(ctx context.Context, id *uuid.UUID) (store.PlanResult, error) {
+				return store.PlanResult{ResultID: resultID, PlanID: id}, nil
+			},
+			ListPlanPlacementsFunc: func(ctx context.Context, id *uuid.UUID) ([]store.PlanPlacement, error) {
+				out := make([]store.PlanPlacement, 0, len(items))
+				for i := range items {
+					out = append(out, store.PlanPlacement{PlacementID: items[i], ResultID: id, ItemID: &items[i], StepNumber: int32(i + 1)})
+				}
+				return out, nil
+			},
+			ListLoadItemsFunc: func(ctx context.Context, id *uuid.UUID) ([]store.LoadItem, error) {
+				gtin := "04006381333931"
+				return []store.LoadItem{
+					{ItemID: items[0], ItemLabel: stringPtr("Carton"), Gtin: &gtin},
+					{ItemID: items[1], ItemLabel: stringPtr("Crate")},
+					{ItemID: items[2], ItemLabel: stringPtr("Drum")},
+				}, nil
+			},
+			GetWorkspaceGS1Func: func(ctx context.Context, id uuid.UUID) (store.WorkspaceGs1, error) {
+				if gs1 == nil {
+					return store.WorkspaceGs1{}, fmt.Errorf("no rows in result set")
+				}
+				return *gs1, nil
+			},
+			ReserveSSCCSerialsFunc: func(ctx context.Context, arg store.ReserveSSCCSerialsParams) (store.WorkspaceGs1, error) {
+				gs1.LastSerial += arg.Count
+				return *gs1, nil
+			},
+			ListHandlingUnitsFunc: func(ctx context.Context, id uuid.UUID) ([]store.HandlingUnit, error) {
+				return *units, nil
+			},
+			CreateHandlingUnitFunc: func(ctx context.Context, arg store.CreateHandlingUnitParams) (store.HandlingUnit, error) {
+				u := store.HandlingUnit{Sscc: arg.Sscc, WorkspaceID: arg.WorkspaceID, PlanID: arg.PlanID, ResultID: arg.ResultID, PlacementID: arg.PlacementID, StepNumber: arg.StepNumber}
+				*units = append(*units, u)
+				return u, nil
+			},
+		}
+	}
+
+	t.Run("assigns_missing_units_once", func(t *testing.T) {
+		gs1 := &store.WorkspaceGs1{WorkspaceID: wsID, CompanyPrefix: "0614141", LastSerial: 0}
+		units := []store.HandlingUnit{{Sscc: "106141410000000019", PlacementID: items[0], StepNumber: 1}}
+		s := service.NewPlanService(newQuerier(gs1, &units), packer.NewPacker())
+
+		out, err := s.AssignHandlingUnits(ctx, planID.String())
+		require.NoError(t, err)
+		require.Len(t, out, 3)
+		assert.Equal(t, int64(2), gs1.LastSerial)
+		assert.Equal(t, "106141410000000019", out[0].SSCC)
+		assert.Equal(t, "04006381333931", out[0].GTIN)
+		assert.Equal(t, "Carton", out[0].ItemLabel)
+		assert.Equal(t, "006141410000000012", out[1].SSCC)
+		assert.Equal(t, "(00)006141410000000012", out[1].ElementString)
+		assert.Equal(t, 2, out[1].StepNumber)
+		assert.Equal(t, "006141410000000029", out[2].SSCC)
+
+		_, err = s.AssignHandlingUnits(ctx, planID.String())
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), gs1.LastSerial)
+	})
+
+	t.Run("not_configured", func(t *testing.T) {
+		var units []store.HandlingUnit
+		s := service.NewPlanService(newQuerier(nil, &units), packer.NewPacker())
+		_, err := s.AssignHandlingUnits(ctx, planID.String())
+		assert.ErrorIs(t, err, service.ErrGS1NotConfigured)
+	})
+
+	t.Run("serials_exhausted", func(t *testing.T) {
+		gs1 := &store.WorkspaceGs1{WorkspaceID: wsID, CompanyPrefix: "061414112345", LastSerial: 9998}
+		var units []store.HandlingUnit
+		s := service.NewPlanService(newQuerier(gs1, &units), packer.NewPacker())
+		_, err := s.AssignHandlingUnits(ctx, planID.String())
+		assert.ErrorIs(t, err, service.ErrSSCCExhausted)
+		assert.Empty(t, units)
+	})
 }
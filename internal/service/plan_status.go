@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ekastn/load-stuffing-calculator/internal/dto"
+	"github.com/ekastn/load-stuffing-calculator/internal/store"
+	"github.com/ekastn/load-stuffing-calculator/internal/types"
+	"github.com/google/uuid"
+)
+
+// ErrInvalidStatusTransition is returned when a plan may not move to the
+// requested status, either because the state machine does not allow it or
+// because the plan does not meet the target status's conditions.
+var ErrInvalidStatusTransition = fmt.Errorf("invalid status transition")
+
+// planStatusOf returns the stored status of plan; an unknown status is
+// treated as a draft.
+func planStatusOf(plan store.LoadPlan) types.PlanStatus {
+	st, ok := types.ParsePlanStatus(getString(plan.Status))
+	if !ok {
+		return types.PlanStatusDraft
+	}
+	return st
+}
+
+// lockPlanStatus locks the plan row until q's transaction ends and returns
+// its status, so status changes made meanwhile are not lost.
+func lockPlanStatus(ctx context.Context, q store.Querier, planID uuid.UUID) (types.PlanStatus, error) {
+	status, err := q.LockLoadPlan(ctx, planID)
+	if err != nil {
+		return "", fmt.Errorf("failed to lock plan: %w", err)
+	}
+	return planStatusOf(store.LoadPlan{Status: status}), nil
+}
+
+// lockEditablePlan locks the plan row until q's transaction ends and
+// rejects changes to its items once it is being loaded, loaded or cancelled.
+func lockEditablePlan(ctx context.Context, q store.Querier, planID uuid.UUID) error {
+	from, err := lockPlanStatus(ctx, q, planID)
+	if err != nil {
+		return err
+	}
+	if !from.Calculable() {
+		return fmt.Errorf("%w: the items of a %s plan cannot be changed", ErrInvalidStatusTransition, from)
+	}
+	return nil
+}
+
+// resultStatus is the status a calculation result puts its plan in.
+func resultStatus(feasible bool) types.PlanStatus {
+	if !feasible {
+		return types.PlanStatusPartial
+	}
+	return types.PlanStatusPlanned
+}
+
+// checkStatusTransition validates a manual status change of plan. It and
+// the other status helpers are shared by the services that move plans.
+func checkStatusTransition(ctx context.Context, q store.Querier, plan store.LoadPlan, from, to types.PlanStatus) error {
+	if !from.CanTransition(to) {
+		return fmt.Errorf("%w: %s to %s", ErrInvalidStatusTransition, from, to)
+	}
+
+	switch to {
+	case types.PlanStatusFailed:
+		return fmt.Errorf("%w: %s is set when a calculation fails", ErrInvalidStatusTransition, to)
+	case types.PlanStatusPlanned, types.PlanStatusPartial, types.PlanStatusInProgress, types.PlanStatusCompleted:
+		res, err := q.GetPlanResult(ctx, &plan.PlanID)
+		if err != nil {
+			return fmt.Errorf("%w: %s requires a calculated plan", ErrInvalidStatusTransition, to)
+		}
+		feasible := res.IsFeasible == nil || *res.IsFeasible
+		switch {
+		case to == types.PlanStatusPartial && feasible:
+			return fmt.Errorf("%w: the calculation result is feasible", ErrInvalidStatusTransition)
+		case (to == types.PlanStatusPlanned || to == types.PlanStatusCompleted) && !feasible:
+			return fmt.Errorf("%w: %s requires a feasible calculation result", ErrInvalidStatusTransition, to)
+		}
+		if to == types.PlanStatusCompleted {
+			return checkLoadingCompleted(ctx, q, plan.PlanID, res.ResultID)
+		}
+	}
+	return nil
+}
+
+// checkLoadingCompleted requires a completed loading session of the plan's
+// active result, so a plan is only COMPLETED once it has been loaded.
+func checkLoadingCompleted(ctx context.Context, q store.Querier, planID, resultID uuid.UUID) error {
+	sessions, err := q.ListLoadingSessions(ctx, planID)
+	if err != nil {
+		return fmt.Errorf("failed to list loading sessions: %w", err)
+	}
+	for _, s := range sessions {
+		if s.Status == types.LoadingSessionCompleted.String() && s.ResultID != nil && *s.ResultID == resultID {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s requires a completed loading session", ErrInvalidStatusTransition, types.PlanStatusCompleted)
+}
+
+// setPlanStatus moves a plan to status and records the change. A nil
+// workspaceID lets founders write any plan.
+func setPlanStatus(ctx context.Context, q store.Querier, planID uuid.UUID, workspaceID *uuid.UUID, from, to types.PlanStatus, reason *string) error {
+	status := to.String()
+	var err error
+	if isFounder(ctx) && workspaceID == nil {
+		err = q.UpdatePlanStatusAny(ctx, store.UpdatePlanStatusAnyParams{PlanID: planID, Status: &status})
+	} else {
+		err = q.UpdatePlanStatus(ctx, store.UpdatePlanStatusParams{PlanID: planID, WorkspaceID: workspaceID, Status: &status})
+	}
+	if err != nil {
+		return err
+	}
+	return recordPlanStatus(ctx, q, planID, from, to, reason)
+}
+
+// recordPlanStatus adds a status change made by the caller in ctx to the
+// plan's history. Changes without a caller are recorded as made by the system.
+func recordPlanStatus(ctx context.Context, q store.Querier, planID uuid.UUID, from, to types.PlanStatus, reason *string) error {
+	if from == to {
+		return nil
+	}
+	fromStatus := from.String()
+	params := store.CreatePlanStatusHistoryParams{
+		PlanID:        planID,
+		FromStatus:    &fromStatus,
+		ToStatus:      to.String(),
+		Reason:        reason,
+		ChangedByType: "system",
+	}
+	if actor, err := actorFromContext(ctx); err == nil {
+		params.ChangedByType = "user"
+		if actor.role == types.RoleTrial.String() {
+			params.ChangedByType = "guest"
+		}
+		params.ChangedByID = &actor.id
+	}
+	if _, err := q.CreatePlanStatusHistory(ctx, params); err != nil {
+		return fmt.Errorf("failed to record status change: %w", err)
+	}
+	return nil
+}
+
+func (s *planService) GetPlanStatusHistory(ctx context.Context, planID string) ([]dto.PlanStatusChange, error) {
+	pID, err := uuid.Parse(planID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid plan id")
+	}
+	if _, err := s.resolvePlanScope(ctx, pID); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.q.ListPlanStatusHistory(ctx, pID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list status history: %w", err)
+	}
+
+	out := make([]dto.PlanStatusChange, 0, len(rows))
+	for _, r := range rows {
+		change := dto.PlanStatusChange{
+			FromStatus:    r.FromStatus,
+			ToStatus:      r.ToStatus,
+			Reason:        r.Reason,
+			ChangedByType: r.ChangedByType,
+			ChangedAt:     r.ChangedAt.Format(time.RFC3339),
+		}
+		if r.ChangedByID != nil {
+			id := r.ChangedByID.String()
+			change.ChangedByID = &id
+		}
+		out = append(out, change)
+	}
+	return out, nil
+}
@@ -0,0 +1,334 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ekastn/load-stuffing-calculator/internal/dto"
+	"github.com/ekastn/load-stuffing-calculator/internal/store"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrResultVersionNotFound is returned for a result version the plan
+	// does not have.
+	ErrResultVersionNotFound = fmt.Errorf("result version not found")
+
+	// ErrStaleResultVersion is returned when restoring a version whose
+	// container or items no longer match the plan.
+	ErrStaleResultVersion = fmt.Errorf("result version does not match the plan")
+)
+
+// resultInputsOf snapshots what a calculation of plan runs on. Items are
+// sorted by ID so snapshots of the same plan compare equal.
+func resultInputsOf(plan store.LoadPlan, items []store.LoadItem, opts dto.CalculatePlanRequest) dto.ResultInputs {
+	details := make([]dto.PlanItemDetail, 0, len(items))
+	for _, it := range items {
+		details = append(details, *mapLoadItemToDetail(it))
+	}
+	sort.Slice(details, func(a, b int) bool { return details[a].ItemID < details[b].ItemID })
+
+	backend := strings.ToLower(strings.TrimSpace(opts.Backend))
+	if backend == "" {
+		backend = DefaultBackend
+	}
+	return dto.ResultInputs{
+		Container: mapPlanContainerInfo(plan),
+		Items:     details,
+		Options:   opts,
+		Backend:   backend,
+	}
+}
+
+func encodeResultInputs(plan store.LoadPlan, items []store.LoadItem, opts dto.CalculatePlanRequest) ([]byte, error) {
+	raw, err := json.Marshal(resultInputsOf(plan, items, opts))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode result inputs: %w", err)
+	}
+	return raw, nil
+}
+
+// decodeResultInputs reads a stored snapshot; results saved before snapshots
+// were kept have none.
+func decodeResultInputs(raw []byte) *dto.ResultInputs {
+	if len(raw) == 0 {
+		return nil
+	}
+	var in dto.ResultInputs
+	if err := json.Unmarshal(raw, &in); err != nil {
+		return nil
+	}
+	return &in
+}
+
+func mapResultVersion(r store.PlanResult) dto.ResultVersion {
+	v := dto.ResultVersion{
+		ResultID:          r.ResultID.String(),
+		Version:           int(r.Version),
+		IsActive:          r.IsActive,
+		IsFeasible:        r.IsFeasible == nil || *r.IsFeasible,
+		VolumeUtilization: toFloat(r.VolumeUtilizationPct),
+		LoadedWeightKG:    toFloat(r.TotalLoadedWeightKg),
+	}
+	if r.Algorithm != nil {
+		v.Algorithm = *r.Algorithm
+	}
+	if r.CreatedAt.Valid {
+		v.CreatedAt = r.CreatedAt.Time.Format(time.RFC3339)
+	}
+	return v
+}
+
+func (s *planService) ListResultVersions(ctx context.Context, planID string) ([]dto.ResultVersion, error) {
+	pID, err := uuid.Parse(planID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid plan id")
+	}
+	if _, err := s.resolvePlanScope(ctx, pID); err != nil {
+		return nil, err
+	}
+
+	results, err := s.q.ListPlanResults(ctx, &pID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list result versions: %w", err)
+	}
+	out := make([]dto.ResultVersion, 0, len(results))
+	for _, r := range results {
+		out = append(out, mapResultVersion(r))
+	}
+	return out, nil
+}
+
+func (s *planService) GetResultVersion(ctx context.Context, planID string, version int) (*dto.ResultVersion, error) {
+	pID, err := uuid.Parse(planID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid plan id")
+	}
+	if _, err := s.resolvePlanScope(ctx, pID); err != nil {
+		return nil, err
+	}
+	return s.resultVersion(ctx, pID, version)
+}
+
+// resultVersion loads a version of a plan's result with its inputs and
+// placements.
+func (s *planService) resultVersion(ctx context.Context, planID uuid.UUID, version int) (*dto.ResultVersion, error) {
+	r, err := s.q.GetPlanResultVersion(ctx, store.GetPlanResultVersionParams{PlanID: &planID, Version: int32(version)})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %d", ErrResultVersionNotFound, version)
+	}
+	placements, err := s.q.ListPlanPlacements(ctx, &r.ResultID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list placements: %w", err)
+	}
+
+	v := mapResultVersion(r)
+	v.Inputs = decodeResultInputs(r.Inputs)
+	v.Placements = mapPlacementDetails(placements)
+	return &v, nil
+}
+
+// RestoreResultVersion makes a saved version the plan's active result. The
+// plan must still have the container and items the version was calculated
+// from, and must be in a status that may be recalculated.
+func (s *planService) RestoreResultVersion(ctx context.Context, planID string, version int) (*dto.ResultVersion, error) {
+	pID, err := uuid.Parse(planID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid plan id")
+	}
+	scope, err := s.resolvePlanScope(ctx, pID)
+	if err != nil {
+		return nil, err
+	}
+	plan := scope.plan
+	from := planStatusOf(plan)
+	if !from.Calculable() {
+		return nil, fmt.Errorf("%w: a %s plan cannot change its result", ErrInvalidStatusTransition, from)
+	}
+
+	v, err := s.resultVersion(ctx, pID, version)
+	if err != nil {
+		return nil, err
+	}
+	if v.Inputs == nil {
+		return nil, fmt.Errorf("%w: version %d has no input snapshot", ErrStaleResultVersion, version)
+	}
+
+	items, err := s.q.ListLoadItems(ctx, &pID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list items: %w", err)
+	}
+	current := resultInputsOf(plan, items, v.Inputs.Options)
+	if !sameJSON(current.Container, v.Inputs.Container) || !sameJSON(current.Items, v.Inputs.Items) {
+		return nil, fmt.Errorf("%w: the container or items changed after version %d", ErrStaleResultVersion, version)
+	}
+
+	resultID, _ := uuid.Parse(v.ResultID)
+	err = inTx(ctx, s.q, func(q store.Querier) error {
+		from, err := lockPlanStatus(ctx, q, pID)
+		if err != nil {
+			return err
+		}
+		if !from.Calculable() {
+			return fmt.Errorf("%w: a %s plan cannot change its result", ErrInvalidStatusTransition, from)
+		}
+		if err := q.DeactivatePlanResults(ctx, &pID); err != nil {
+			return fmt.Errorf("failed to restore version: %w", err)
+		}
+		if err := q.ActivatePlanResult(ctx, resultID); err != nil {
+			return fmt.Errorf("failed to restore version: %w", err)
+		}
+
+		reason := fmt.Sprintf("restored result version %d", version)
+		if err := setPlanStatus(ctx, q, pID, scope.workspaceID, from, resultStatus(v.IsFeasible), &reason); err != nil {
+			return fmt.Errorf("failed to update plan status: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	v.IsActive = true
+	return v, nil
+}
+
+// DiffResultVersions compares version to against version from.
+func (s *planService) DiffResultVersions(ctx context.Context, planID string, from, to int) (*dto.ResultVersionDiff, error) {
+	pID, err := uuid.Parse(planID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid plan id")
+	}
+	if _, err := s.resolvePlanScope(ctx, pID); err != nil {
+		return nil, err
+	}
+
+	a, err := s.resultVersion(ctx, pID, from)
+	if err != nil {
+		return nil, err
+	}
+	b, err := s.resultVersion(ctx, pID, to)
+	if err != nil {
+		return nil, err
+	}
+	return diffResultVersions(a, b), nil
+}
+
+func diffResultVersions(a, b *dto.ResultVersion) *dto.ResultVersionDiff {
+	d := &dto.ResultVersionDiff{
+		FromVersion:            a.Version,
+		ToVersion:              b.Version,
+		FromAlgorithm:          a.Algorithm,
+		ToAlgorithm:            b.Algorithm,
+		FromFeasible:           a.IsFeasible,
+		ToFeasible:             b.IsFeasible,
+		VolumeUtilizationDelta: b.VolumeUtilization - a.VolumeUtilization,
+		LoadedWeightDeltaKG:    b.LoadedWeightKG - a.LoadedWeightKG,
+		Items:                  []dto.ResultItemDiff{},
+	}
+
+	var aItems, bItems []dto.PlanItemDetail
+	if a.Inputs != nil && b.Inputs != nil {
+		d.ContainerChanged = !sameJSON(a.Inputs.Container, b.Inputs.Container)
+		d.OptionsChanged = !sameJSON(a.Inputs.Options, b.Inputs.Options) || a.Inputs.Backend != b.Inputs.Backend
+		aItems, bItems = a.Inputs.Items, b.Inputs.Items
+	}
+
+	// Items are matched by ID; without snapshots only packed counts are known.
+	aDef := make(map[string]dto.PlanItemDetail, len(aItems))
+	bDef := make(map[string]dto.PlanItemDetail, len(bItems))
+	var ids []string
+	seen := make(map[string]bool)
+	addID := func(id string) {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	for _, it := range aItems {
+		aDef[it.ItemID] = it
+		addID(it.ItemID)
+	}
+	for _, it := range bItems {
+		bDef[it.ItemID] = it
+		addID(it.ItemID)
+	}
+	aPacked := packedCounts(a.Placements)
+	bPacked := packedCounts(b.Placements)
+	for _, pl := range a.Placements {
+		addID(pl.ItemID)
+	}
+	for _, pl := range b.Placements {
+		addID(pl.ItemID)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		ai, inA := aDef[id]
+		bi, inB := bDef[id]
+		diff := dto.ResultItemDiff{
+			ItemID:       id,
+			FromQuantity: ai.Quantity,
+			ToQuantity:   bi.Quantity,
+			FromPacked:   aPacked[id],
+			ToPacked:     bPacked[id],
+		}
+		switch {
+		case inA && !inB && len(bItems) > 0:
+			diff.Change = "removed"
+			diff.Label = getString(ai.Label)
+		case inB && !inA && len(aItems) > 0:
+			diff.Change = "added"
+			diff.Label = getString(bi.Label)
+		case !sameJSON(ai, bi) || diff.FromPacked != diff.ToPacked:
+			diff.Change = "changed"
+			diff.Label = getString(bi.Label)
+			if diff.Label == "" {
+				diff.Label = getString(ai.Label)
+			}
+		default:
+			continue
+		}
+		d.Items = append(d.Items, diff)
+	}
+
+	// A unit is unchanged when the same item sits at the same spot with the
+	// same rotation in both versions.
+	spots := make(map[string]int, len(a.Placements))
+	for _, pl := range a.Placements {
+		spots[placementKey(pl)]++
+	}
+	for _, pl := range b.Placements {
+		k := placementKey(pl)
+		if spots[k] > 0 {
+			spots[k]--
+			d.UnitsUnchanged++
+		} else {
+			d.UnitsMoved++
+		}
+	}
+	return d
+}
+
+func packedCounts(placements []dto.PlacementDetail) map[string]int {
+	out := make(map[string]int)
+	for _, pl := range placements {
+		out[pl.ItemID]++
+	}
+	return out
+}
+
+func placementKey(pl dto.PlacementDetail) string {
+	return fmt.Sprintf("%s|%.0f|%.0f|%.0f|%d", pl.ItemID, pl.PositionX, pl.PositionY, pl.PositionZ, pl.Rotation)
+}
+
+// sameJSON reports whether a and b encode to the same JSON.
+func sameJSON(a, b any) bool {
+	ra, errA := json.Marshal(a)
+	rb, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(ra, rb)
+}
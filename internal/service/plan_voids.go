@@ -0,0 +1,150 @@
+package service
+
+import (
+	"math"
+	"sort"
+
+	"github.com/ekastn/load-stuffing-calculator/internal/dto"
+	"github.com/ekastn/load-stuffing-calculator/internal/packer"
+)
+
+// maxReportedVoids caps the voids listed in a calculation result. Flagged
+// voids come first, so they are never dropped for unflagged ones.
+const maxReportedVoids = 50
+
+// analyzeVoids runs the void and dunnage analysis for a packing result. The
+// space the wall clearance keeps free is not a void, and every compartment is
+// analysed on its own so bulkheads bound the gaps like walls do.
+func analyzeVoids(container packer.ContainerInput, res packer.PackingResult, opts dto.CalculatePlanRequest) *dto.VoidAnalysis {
+	cfg := packer.DefaultDunnageConfig
+	if opts.VoidThresholdMM != nil {
+		cfg.MinGap = *opts.VoidThresholdMM
+	}
+
+	report := packer.VoidReport{Currency: cfg.Currency}
+	usableM3 := 0.0
+	for _, r := range voidRegions(container) {
+		usableM3 += r.box.Length * r.box.Width * r.box.Height / 1_000_000_000.0
+
+		var items []packer.PackedItem
+		for _, pi := range res.PackedItems {
+			if centre := pi.Position.X + pi.RotatedLength/2; centre < r.start || centre >= r.end {
+				continue
+			}
+			pi.Position = packer.Position{X: pi.Position.X - r.origin.X, Y: pi.Position.Y - r.origin.Y, Z: pi.Position.Z}
+			items = append(items, pi)
+		}
+
+		sub := packer.AnalyzeVoids(r.box, items, cfg)
+		for _, v := range sub.Voids {
+			report.Voids = append(report.Voids, r.toContainer(v))
+		}
+		for _, g := range sub.Gaps {
+			g.Void = r.toContainer(g.Void)
+			report.Gaps = append(report.Gaps, g)
+		}
+		report.TotalCost += sub.TotalCost
+	}
+	sort.SliceStable(report.Voids, func(i, j int) bool {
+		return report.Voids[i].VolumeM3() > report.Voids[j].VolumeM3()
+	})
+	sort.SliceStable(report.Gaps, func(i, j int) bool {
+		return report.Gaps[i].VolumeM3() > report.Gaps[j].VolumeM3()
+	})
+
+	out := &dto.VoidAnalysis{
+		ThresholdMM:      cfg.MinGap,
+		EmptyVolumeM3:    math.Max(usableM3-res.TotalVolumePackedM3, 0),
+		Voids:            []dto.VoidDetail{},
+		Dunnage:          []dto.DunnageRecommendation{},
+		TotalDunnageCost: report.TotalCost,
+		Currency:         report.Currency,
+	}
+
+	flagged := make(map[packer.Void]bool, len(report.Gaps))
+	totalIdx := make(map[string]int)
+	for _, g := range report.Gaps {
+		flagged[g.Void] = true
+
+		d := mapVoid(g.Void)
+		d.Flagged = true
+		d.ShiftAxis = g.Axis
+		d.GapMM = g.Gap
+		d.Dunnage = &dto.DunnageRecommendation{Type: g.Type, Quantity: g.Quantity, EstimatedCost: g.Cost}
+		out.Voids = append(out.Voids, d)
+
+		i, ok := totalIdx[g.Type]
+		if !ok {
+			i = len(out.Dunnage)
+			totalIdx[g.Type] = i
+			out.Dunnage = append(out.Dunnage, dto.DunnageRecommendation{Type: g.Type})
+		}
+		out.Dunnage[i].Quantity += g.Quantity
+		out.Dunnage[i].EstimatedCost += g.Cost
+	}
+
+	for _, v := range report.Voids {
+		if len(out.Voids) >= maxReportedVoids {
+			break
+		}
+		if !flagged[v] {
+			out.Voids = append(out.Voids, mapVoid(v))
+		}
+	}
+	return out
+}
+
+func mapVoid(v packer.Void) dto.VoidDetail {
+	return dto.VoidDetail{
+		PositionX: v.Position.X,
+		PositionY: v.Position.Y,
+		PositionZ: v.Position.Z,
+		LengthMM:  v.Length,
+		WidthMM:   v.Width,
+		HeightMM:  v.Height,
+		VolumeM3:  v.VolumeM3(),
+	}
+}
+
+// voidRegion is a part of the container analysed as a container of its own.
+type voidRegion struct {
+	box        packer.ContainerInput // usable space, at the origin
+	origin     packer.Position       // where box lies in the container
+	start, end float64               // units with their centre in [start, end) belong here
+}
+
+// voidRegions splits the container into its compartments, or keeps it whole
+// without any, and shrinks each by the wall clearance along the walls, both
+// ends and the ceiling, as the packer does.
+func voidRegions(container packer.ContainerInput) []voidRegion {
+	wall := math.Max(container.WallClearance, 0)
+	region := func(start, length float64) voidRegion {
+		return voidRegion{
+			box: packer.ContainerInput{
+				ID:     container.ID,
+				Length: math.Max(length-2*wall, 0),
+				Width:  math.Max(container.Width-2*wall, 0),
+				Height: math.Max(container.Height-wall, 0),
+			},
+			origin: packer.Position{X: start + wall, Y: wall},
+			start:  start,
+			end:    start + length,
+		}
+	}
+
+	if len(container.Compartments) == 0 {
+		return []voidRegion{region(0, container.Length)}
+	}
+	out := make([]voidRegion, len(container.Compartments))
+	for i, c := range container.Compartments {
+		out[i] = region(c.Start, c.Length)
+	}
+	return out
+}
+
+// toContainer moves v from region to container coordinates.
+func (r voidRegion) toContainer(v packer.Void) packer.Void {
+	v.Position.X += r.origin.X
+	v.Position.Y += r.origin.Y
+	return v
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/ekastn/load-stuffing-calculator/internal/dto"
+	"github.com/ekastn/load-stuffing-calculator/internal/packer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeVoids_Regions(t *testing.T) {
+	t.Run("wall_clearance_is_not_a_void", func(t *testing.T) {
+		container := packer.ContainerInput{Length: 2000, Width: 1000, Height: 1000, WallClearance: 100}
+		res := packer.PackingResult{
+			TotalVolumePackedM3: 1.296,
+			PackedItems: []packer.PackedItem{{
+				RotatedLength: 1800, RotatedWidth: 800, RotatedHeight: 900,
+				Position: packer.Position{X: 100, Y: 100},
+			}},
+		}
+
+		va := analyzeVoids(container, res, dto.CalculatePlanRequest{})
+
+		assert.Empty(t, va.Voids)
+		assert.Empty(t, va.Dunnage)
+		assert.InDelta(t, 0, va.EmptyVolumeM3, 1e-9)
+	})
+
+	t.Run("compartments_are_analysed_separately", func(t *testing.T) {
+		container := packer.ContainerInput{
+			Length: 2000, Width: 1000, Height: 1000,
+			Compartments: []packer.Compartment{
+				{Name: "Dry", Start: 0, Length: 1000},
+				{Name: "Cold", TemperatureClass: packer.TemperatureChilled, Start: 1000, Length: 1000},
+			},
+		}
+		res := packer.PackingResult{
+			TotalVolumePackedM3: 0.8,
+			PackedItems: []packer.PackedItem{{
+				RotatedLength: 800, RotatedWidth: 1000, RotatedHeight: 1000,
+			}},
+		}
+
+		va := analyzeVoids(container, res, dto.CalculatePlanRequest{})
+
+		require.Len(t, va.Voids, 2)
+		gap := va.Voids[0]
+		assert.True(t, gap.Flagged)
+		assert.Equal(t, 800.0, gap.PositionX)
+		assert.Equal(t, 200.0, gap.LengthMM)
+		assert.Equal(t, 200.0, gap.GapMM)
+
+		empty := va.Voids[1]
+		assert.False(t, empty.Flagged)
+		assert.Equal(t, 1000.0, empty.PositionX)
+		assert.Equal(t, 1000.0, empty.LengthMM)
+		assert.InDelta(t, 1.2, va.EmptyVolumeM3, 1e-9)
+	})
+}
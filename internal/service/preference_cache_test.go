@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ekastn/load-stuffing-calculator/internal/mocks"
+	"github.com/ekastn/load-stuffing-calculator/internal/store"
+	"github.com/ekastn/load-stuffing-calculator/internal/units"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPreferenceService_UnitsCache(t *testing.T) {
+	t.Run("entries_expire", func(t *testing.T) {
+		calls := 0
+		q := &mocks.MockQuerier{
+			GetUserPreferenceFunc: func(ctx context.Context, id uuid.UUID) (store.UserPreference, error) {
+				calls++
+				return store.UserPreference{UserID: id, Units: "in,lb"}, nil
+			},
+		}
+		now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		s := NewPreferenceService(q).(*preferenceService)
+		s.now = func() time.Time { return now }
+		userID := uuid.New().String()
+
+		s.UnitsForUser(context.Background(), userID)
+		s.UnitsForUser(context.Background(), userID)
+		assert.Equal(t, 1, calls)
+
+		now = now.Add(unitsCacheTTL)
+		sys, ok := s.UnitsForUser(context.Background(), userID)
+		assert.True(t, ok)
+		assert.Equal(t, units.Pound, sys.Weight)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("size_is_bounded", func(t *testing.T) {
+		s := NewPreferenceService(&mocks.MockQuerier{}).(*preferenceService)
+		sys := units.Canonical
+		for i := 0; i < unitsCacheSize+10; i++ {
+			s.cacheUnits(fmt.Sprintf("user-%d", i), &sys)
+		}
+		assert.Len(t, s.units, unitsCacheSize)
+	})
+}
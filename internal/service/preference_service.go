@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/ekastn/load-stuffing-calculator/internal/auth"
+	"github.com/ekastn/load-stuffing-calculator/internal/dto"
+	"github.com/ekastn/load-stuffing-calculator/internal/store"
+	"github.com/ekastn/load-stuffing-calculator/internal/types"
+	"github.com/ekastn/load-stuffing-calculator/internal/units"
+	"github.com/google/uuid"
+)
+
+var ErrInvalidUnits = fmt.Errorf("invalid units")
+
+type PreferenceService interface {
+	GetPreferences(ctx context.Context) (*dto.PreferencesResponse, error)
+	UpdatePreferences(ctx context.Context, req dto.UpdatePreferencesRequest) (*dto.PreferencesResponse, error)
+	// UnitsForUser returns the stored unit preference; ok is false when the
+	// user has none. Results are cached in memory for a few minutes.
+	UnitsForUser(ctx context.Context, userID string) (sys units.System, ok bool)
+}
+
+// Cached preferences expire so changes made by another instance are picked
+// up, and the cache holds at most unitsCacheSize users.
+const (
+	unitsCacheTTL  = 10 * time.Minute
+	unitsCacheSize = 10000
+)
+
+type cachedUnits struct {
+	sys     *units.System // nil: no preference stored
+	expires time.Time
+}
+
+type preferenceService struct {
+	q   store.Querier
+	now func() time.Time
+
+	mu    sync.RWMutex
+	units map[string]cachedUnits
+}
+
+func NewPreferenceService(q store.Querier) PreferenceService {
+	return &preferenceService{q: q, now: time.Now, units: make(map[string]cachedUnits)}
+}
+
+func (s *preferenceService) GetPreferences(ctx context.Context) (*dto.PreferencesResponse, error) {
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sys, _ := s.UnitsForUser(ctx, userID.String())
+	return mapPreferences(sys), nil
+}
+
+func (s *preferenceService) UpdatePreferences(ctx context.Context, req dto.UpdatePreferencesRequest) (*dto.PreferencesResponse, error) {
+	if role, _ := auth.RoleFromContext(ctx); role == types.RoleTrial.String() {
+		return nil, ErrForbidden
+	}
+
+	userID, err := userIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sys, err := units.Parse(req.Units)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidUnits, err)
+	}
+
+	if _, err := s.q.UpsertUserPreference(ctx, store.UpsertUserPreferenceParams{
+		UserID: userID,
+		Units:  sys.String(),
+	}); err != nil {
+		return nil, fmt.Errorf("failed to save preferences: %w", err)
+	}
+
+	s.cacheUnits(userID.String(), &sys)
+
+	return mapPreferences(sys), nil
+}
+
+func (s *preferenceService) UnitsForUser(ctx context.Context, userID string) (units.System, bool) {
+	s.mu.RLock()
+	cached, hit := s.units[userID]
+	s.mu.RUnlock()
+	if hit && s.now().Before(cached.expires) {
+		if cached.sys == nil {
+			return units.Canonical, false
+		}
+		return *cached.sys, true
+	}
+
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return units.Canonical, false
+	}
+
+	var sys *units.System
+	pref, err := s.q.GetUserPreference(ctx, id)
+	switch {
+	case err == nil:
+		if parsed, perr := units.Parse(pref.Units); perr == nil {
+			sys = &parsed
+		}
+	case errors.Is(err, sql.ErrNoRows):
+	default:
+		// Don't cache lookup failures.
+		log.Printf("preferences: lookup failed for %s: %v", userID, err)
+		return units.Canonical, false
+	}
+
+	s.cacheUnits(userID, sys)
+
+	if sys == nil {
+		return units.Canonical, false
+	}
+	return *sys, true
+}
+
+// cacheUnits stores a user's preference. When the cache is full, expired
+// entries are dropped first and then arbitrary ones.
+func (s *preferenceService) cacheUnits(userID string, sys *units.System) {
+	now := s.now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.units[userID]; !ok && len(s.units) >= unitsCacheSize {
+		for id, c := range s.units {
+			if !now.Before(c.expires) {
+				delete(s.units, id)
+			}
+		}
+		for id := range s.units {
+			if len(s.units) < unitsCacheSize {
+				break
+			}
+			delete(s.units, id)
+		}
+	}
+	s.units[userID] = cachedUnits{sys: sys, expires: now.Add(unitsCacheTTL)}
+}
+
+func mapPreferences(sys units.System) *dto.PreferencesResponse {
+	return &dto.PreferencesResponse{
+		Units:      sys.String(),
+		LengthUnit: string(sys.Length),
+		WeightUnit: string(sys.Weight),
+		VolumeUnit: string(sys.Volume()),
+	}
+}
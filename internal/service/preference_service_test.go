@@ -0,0 +1,91 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/ekastn/load-stuffing-calculator/internal/auth"
+	"github.com/ekastn/load-stuffing-calculator/internal/dto"
+	"github.com/ekastn/load-stuffing-calculator/internal/service"
+	"github.com/ekastn/load-stuffing-calculator/internal/store"
+	"github.com/ekastn/load-stuffing-calculator/internal/types"
+	"github.com/ekastn/load-stuffing-calculator/internal/units"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPreferenceService_UnitsForUser(t *testing.T) {
+	userID := uuid.New()
+
+	t.Run("cached_after_first_lookup", func(t *testing.T) {
+		calls := 0
+		mockQ := &MockQuerier{
+			GetUserPreferenceFunc: func(ctx context.Context, id uuid.UUID) (store.UserPreference, error) {
+				calls++
+				return store.UserPreference{UserID: id, Units: "in,lb"}, nil
+			},
+		}
+		s := service.NewPreferenceService(mockQ)
+
+		for i := 0; i < 2; i++ {
+			sys, ok := s.UnitsForUser(context.Background(), userID.String())
+			assert.True(t, ok)
+			assert.Equal(t, units.System{Length: units.Inch, Weight: units.Pound}, sys)
+		}
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("no_preference", func(t *testing.T) {
+		mockQ := &MockQuerier{
+			GetUserPreferenceFunc: func(ctx context.Context, id uuid.UUID) (store.UserPreference, error) {
+				return store.UserPreference{}, sql.ErrNoRows
+			},
+		}
+		s := service.NewPreferenceService(mockQ)
+
+		sys, ok := s.UnitsForUser(context.Background(), userID.String())
+		assert.False(t, ok)
+		assert.Equal(t, units.Canonical, sys)
+	})
+}
+
+func TestPreferenceService_UpdatePreferences(t *testing.T) {
+	userID := uuid.New()
+	ctx := auth.WithRole(auth.WithUserID(context.Background(), userID.String()), types.RolePlanner.String())
+
+	t.Run("success_updates_cache", func(t *testing.T) {
+		var saved store.UpsertUserPreferenceParams
+		mockQ := &MockQuerier{
+			UpsertUserPreferenceFunc: func(ctx context.Context, arg store.UpsertUserPreferenceParams) (store.UserPreference, error) {
+				saved = arg
+				return store.UserPreference{UserID: arg.UserID, Units: arg.Units}, nil
+			},
+		}
+		s := service.NewPreferenceService(mockQ)
+
+		resp, err := s.UpdatePreferences(ctx, dto.UpdatePreferencesRequest{Units: "Imperial"})
+		assert.NoError(t, err)
+		assert.Equal(t, "in,lb", saved.Units)
+		assert.Equal(t, userID, saved.UserID)
+		assert.Equal(t, "ft3", resp.VolumeUnit)
+
+		// Served from the cache; GetUserPreferenceFunc is not set.
+		sys, ok := s.UnitsForUser(ctx, userID.String())
+		assert.True(t, ok)
+		assert.Equal(t, units.Pound, sys.Weight)
+	})
+
+	t.Run("invalid_units", func(t *testing.T) {
+		s := service.NewPreferenceService(&MockQuerier{})
+		_, err := s.UpdatePreferences(ctx, dto.UpdatePreferencesRequest{Units: "stone"})
+		assert.ErrorIs(t, err, service.ErrInvalidUnits)
+	})
+
+	t.Run("trial_forbidden", func(t *testing.T) {
+		trialCtx := auth.WithRole(auth.WithUserID(context.Background(), userID.String()), types.RoleTrial.String())
+		s := service.NewPreferenceService(&MockQuerier{})
+		_, err := s.UpdatePreferences(trialCtx, dto.UpdatePreferencesRequest{Units: "metric"})
+		assert.ErrorIs(t, err, service.ErrForbidden)
+	})
+}
@@ -5,13 +5,19 @@ import (
 	"fmt"
 
 	"github.com/ekastn/load-stuffing-calculator/internal/dto"
+	"github.com/ekastn/load-stuffing-calculator/internal/gs1"
 	"github.com/ekastn/load-stuffing-calculator/internal/store"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
+// ErrInvalidGTIN is returned for a GTIN with a wrong length or check digit.
+var ErrInvalidGTIN = gs1.ErrInvalidGTIN
+
 type ProductService interface {
 	CreateProduct(ctx context.Context, req dto.CreateProductRequest) (*dto.ProductResponse, error)
 	GetProduct(ctx context.Context, id string) (*dto.ProductResponse, error)
+	GetProductBySKU(ctx context.Context, sku string) (*dto.ProductResponse, error)
 	ListProducts(ctx context.Context, page, limit int32) ([]dto.ProductResponse, error)
 	UpdateProduct(ctx context.Context, id string, req dto.UpdateProductRequest) error
 	DeleteProduct(ctx context.Context, id string) error
@@ -47,6 +53,15 @@ func (s *productService) CreateProduct(ctx context.Context, req dto.CreateProduc
 		return nil, fmt.Errorf("workspace id is required")
 	}
 
+	gtin, err := normalizeGTIN(req.GTIN)
+	if err != nil {
+		return nil, err
+	}
+	packaging, shippingUnit, err := encodePackaging(req.Packaging, req.ShippingUnit)
+	if err != nil {
+		return nil, err
+	}
+
 	product, err := s.q.CreateProduct(ctx, store.CreateProductParams{
 		WorkspaceID: workspaceID,
 		Name:        req.Name,
@@ -56,6 +71,20 @@ func (s *productService) CreateProduct(ctx context.Context, req dto.CreateProduc
 		HeightMm:    toNumeric(req.HeightMM),
 		WeightKg:    toNumeric(req.WeightKG),
 		ColorHex:    req.ColorHex,
+
+		FrictionCoefficient: toOptionalNumeric(req.FrictionCoefficient),
+		TemperatureClass:    req.TemperatureClass,
+		Gtin:                gtin,
+
+		Fragile:        boolOr(req.Fragile, false),
+		ThisSideUp:     boolOr(req.ThisSideUp, false),
+		Stackable:      boolOr(req.Stackable, true),
+		MaxStackLoadKg: toOptionalNumeric(req.MaxStackLoadKG),
+		HazmatClass:    req.HazmatClass,
+		PackagingType:  req.PackagingType,
+
+		Packaging:    packaging,
+		ShippingUnit: shippingUnit,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create product: %w", err)
@@ -99,6 +128,38 @@ func (s *productService) GetProduct(ctx context.Context, id string) (*dto.Produc
 	return mapProductToResponse(product), nil
 }
 
+// GetProductBySKU looks a product up by SKU. A workspace product wins over a
+// global preset with the same SKU.
+func (s *productService) GetProductBySKU(ctx context.Context, sku string) (*dto.ProductResponse, error) {
+	overrideWorkspaceID, err := workspaceOverrideIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if isFounder(ctx) && overrideWorkspaceID == nil {
+		product, err := s.q.GetProductBySkuAny(ctx, &sku)
+		if err != nil {
+			return nil, err
+		}
+		return mapProductToResponse(product), nil
+	}
+
+	workspaceID, err := workspaceIDFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if overrideWorkspaceID != nil {
+		workspaceID = overrideWorkspaceID
+	}
+
+	product, err := s.q.GetProductBySku(ctx, store.GetProductBySkuParams{Sku: &sku, WorkspaceID: workspaceID})
+	if err != nil {
+		return nil, err
+	}
+
+	return mapProductToResponse(product), nil
+}
+
 func (s *productService) ListProducts(ctx context.Context, page, limit int32) ([]dto.ProductResponse, error) {
 	if page < 1 {
 		page = 1
@@ -146,6 +207,10 @@ func (s *productService) ListProducts(ctx context.Context, page, limit int32) ([
 	return result, nil
 }
 
+// UpdateProduct replaces the product's name, SKU, dimensions, weight and
+// colour. The catalog attributes (friction, temperature class, GTIN,
+// handling and packaging) keep their stored values when the request leaves
+// them out, and an empty string clears a text attribute.
 func (s *productService) UpdateProduct(ctx context.Context, id string, req dto.UpdateProductRequest) error {
 	productID, err := uuid.Parse(id)
 	if err != nil {
@@ -157,51 +222,136 @@ func (s *productService) UpdateProduct(ctx context.Context, id string, req dto.U
 		return err
 	}
 
-	if isFounder(ctx) && overrideWorkspaceID == nil {
-		err = s.q.UpdateProductAny(ctx, store.UpdateProductAnyParams{
-			ProductID: productID,
-			Name:      req.Name,
-			Sku:       req.SKU,
-			LengthMm:  toNumeric(req.LengthMM),
-			WidthMm:   toNumeric(req.WidthMM),
-			HeightMm:  toNumeric(req.HeightMM),
-			WeightKg:  toNumeric(req.WeightKG),
-			ColorHex:  req.ColorHex,
-		})
+	anyWorkspace := isFounder(ctx) && overrideWorkspaceID == nil
+	var workspaceID *uuid.UUID
+	var current store.Product
+	if anyWorkspace {
+		current, err = s.q.GetProductAny(ctx, productID)
+	} else {
+		workspaceID, err = workspaceIDFromContext(ctx)
 		if err != nil {
-			return fmt.Errorf("failed to update product: %w", err)
+			return err
 		}
-		return nil
+		if overrideWorkspaceID != nil {
+			workspaceID = overrideWorkspaceID
+		}
+		if workspaceID == nil {
+			return fmt.Errorf("workspace id is required")
+		}
+		current, err = s.q.GetProduct(ctx, store.GetProductParams{ProductID: productID, WorkspaceID: workspaceID})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load product: %w", err)
 	}
 
-	workspaceID, err := workspaceIDFromContext(ctx)
+	arg, err := productUpdate(current, req)
 	if err != nil {
 		return err
 	}
-	if overrideWorkspaceID != nil {
-		workspaceID = overrideWorkspaceID
-	}
-	if workspaceID == nil {
-		return fmt.Errorf("workspace id is required")
-	}
 
-	err = s.q.UpdateProduct(ctx, store.UpdateProductParams{
-		ProductID:   productID,
-		WorkspaceID: workspaceID,
-		Name:        req.Name,
-		Sku:         req.SKU,
-		LengthMm:    toNumeric(req.LengthMM),
-		WidthMm:     toNumeric(req.WidthMM),
-		HeightMm:    toNumeric(req.HeightMM),
-		WeightKg:    toNumeric(req.WeightKG),
-		ColorHex:    req.ColorHex,
-	})
+	if anyWorkspace {
+		err = s.q.UpdateProductAny(ctx, store.UpdateProductAnyParams{
+			ProductID: productID,
+			Name:      arg.Name,
+			Sku:       arg.Sku,
+			LengthMm:  arg.LengthMm,
+			WidthMm:   arg.WidthMm,
+			HeightMm:  arg.HeightMm,
+			WeightKg:  arg.WeightKg,
+			ColorHex:  arg.ColorHex,
+
+			FrictionCoefficient: arg.FrictionCoefficient,
+			TemperatureClass:    arg.TemperatureClass,
+			Gtin:                arg.Gtin,
+
+			Fragile:        arg.Fragile,
+			ThisSideUp:     arg.ThisSideUp,
+			Stackable:      arg.Stackable,
+			MaxStackLoadKg: arg.MaxStackLoadKg,
+			HazmatClass:    arg.HazmatClass,
+			PackagingType:  arg.PackagingType,
+
+			Packaging:    arg.Packaging,
+			ShippingUnit: arg.ShippingUnit,
+		})
+	} else {
+		arg.ProductID = productID
+		arg.WorkspaceID = workspaceID
+		err = s.q.UpdateProduct(ctx, arg)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to update product: %w", err)
 	}
 	return nil
 }
 
+// productUpdate merges an update request into the stored product.
+func productUpdate(current store.Product, req dto.UpdateProductRequest) (store.UpdateProductParams, error) {
+	gtin := current.Gtin
+	if req.GTIN != nil {
+		var err error
+		if gtin, err = normalizeGTIN(req.GTIN); err != nil {
+			return store.UpdateProductParams{}, err
+		}
+	}
+	// The stored hierarchy and shipping unit stay unless replaced; an empty
+	// packaging list removes the hierarchy.
+	levels, unit := req.Packaging, req.ShippingUnit
+	if levels == nil {
+		levels = decodePackaging(current.Packaging)
+	}
+	if unit == nil && current.ShippingUnit != "" {
+		unit = &current.ShippingUnit
+	}
+	packaging, shippingUnit, err := encodePackaging(levels, unit)
+	if err != nil {
+		return store.UpdateProductParams{}, err
+	}
+
+	return store.UpdateProductParams{
+		Name:     req.Name,
+		Sku:      req.SKU,
+		LengthMm: toNumeric(req.LengthMM),
+		WidthMm:  toNumeric(req.WidthMM),
+		HeightMm: toNumeric(req.HeightMM),
+		WeightKg: toNumeric(req.WeightKG),
+		ColorHex: req.ColorHex,
+
+		FrictionCoefficient: updatedNumeric(req.FrictionCoefficient, current.FrictionCoefficient),
+		TemperatureClass:    updatedString(req.TemperatureClass, current.TemperatureClass),
+		Gtin:                gtin,
+
+		Fragile:        boolOr(req.Fragile, current.Fragile),
+		ThisSideUp:     boolOr(req.ThisSideUp, current.ThisSideUp),
+		Stackable:      boolOr(req.Stackable, current.Stackable),
+		MaxStackLoadKg: updatedNumeric(req.MaxStackLoadKG, current.MaxStackLoadKg),
+		HazmatClass:    updatedString(req.HazmatClass, current.HazmatClass),
+		PackagingType:  updatedString(req.PackagingType, current.PackagingType),
+
+		Packaging:    packaging,
+		ShippingUnit: shippingUnit,
+	}, nil
+}
+
+// updatedString returns cur when v is nil and clears the value for "".
+func updatedString(v, cur *string) *string {
+	if v == nil {
+		return cur
+	}
+	if *v == "" {
+		return nil
+	}
+	return v
+}
+
+// updatedNumeric returns cur when v is nil.
+func updatedNumeric(v *float64, cur pgtype.Numeric) pgtype.Numeric {
+	if v == nil {
+		return cur
+	}
+	return toNumeric(*v)
+}
+
 func (s *productService) DeleteProduct(ctx context.Context, id string) error {
 	productID, err := uuid.Parse(id)
 	if err != nil {
@@ -240,6 +390,10 @@ func (s *productService) DeleteProduct(ctx context.Context, id string) error {
 }
 
 func mapProductToResponse(p store.Product) *dto.ProductResponse {
+	shippingUnit := p.ShippingUnit
+	if shippingUnit == "" {
+		shippingUnit = unitEach
+	}
 	return &dto.ProductResponse{
 		ID:       p.ProductID.String(),
 		Name:     p.Name,
@@ -249,5 +403,38 @@ func mapProductToResponse(p store.Product) *dto.ProductResponse {
 		HeightMM: toFloat(p.HeightMm),
 		WeightKG: toFloat(p.WeightKg),
 		ColorHex: p.ColorHex,
+
+		FrictionCoefficient: toOptionalFloat(p.FrictionCoefficient),
+		TemperatureClass:    p.TemperatureClass,
+		GTIN:                p.Gtin,
+
+		HandlingAttributes: mapHandling(p.Fragile, p.ThisSideUp, p.Stackable, p.MaxStackLoadKg, p.HazmatClass, p.PackagingType),
+
+		Packaging:    decodePackaging(p.Packaging),
+		ShippingUnit: shippingUnit,
+	}
+}
+
+// mapHandling returns stored handling attributes in their API form.
+func mapHandling(fragile, thisSideUp, stackable bool, maxStackLoad pgtype.Numeric, hazmatClass, packagingType *string) dto.HandlingAttributes {
+	return dto.HandlingAttributes{
+		Fragile:        fragile,
+		ThisSideUp:     thisSideUp,
+		Stackable:      stackable,
+		MaxStackLoadKG: toOptionalFloat(maxStackLoad),
+		HazmatClass:    hazmatClass,
+		PackagingType:  packagingType,
+	}
+}
+
+// normalizeGTIN validates an optional GTIN and returns its 14-digit form.
+func normalizeGTIN(gtin *string) (*string, error) {
+	if gtin == nil || *gtin == "" {
+		return nil, nil
+	}
+	n, err := gs1.NormalizeGTIN(*gtin)
+	if err != nil {
+		return nil, err
 	}
+	return &n, nil
 }
@@ -2,6 +2,7 @@ package service_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"testing"
 
@@ -184,6 +185,202 @@ func TestProductService_CreateProduct(t *testing.T) {
 	}
 }
 
+func TestProductService_CreateProduct_GTIN(t *testing.T) {
+	var saw *string
+	mockQ := &MockQuerier{
+		CreateProductFunc: func(ctx context.Context, arg store.CreateProductParams) (store.Product, error) {
+			saw = arg.Gtin
+			return store.Product{ProductID: uuid.New(), Name: arg.Name, Gtin: arg.Gtin}, nil
+		},
+	}
+	s := service.NewProductService(mockQ)
+	ctx := ctxWithWorkspaceID(uuid.New())
+
+	resp, err := s.CreateProduct(ctx, dto.CreateProductRequest{Name: "Item", GTIN: stringPtr("4006381333931")})
+	if err != nil {
+		t.Fatalf("CreateProduct() error = %v", err)
+	}
+	if saw == nil || *saw != "04006381333931" {
+		t.Errorf("stored GTIN = %v, want the 14-digit form", saw)
+	}
+	if resp.GTIN == nil || *resp.GTIN != "04006381333931" {
+		t.Errorf("GTIN = %v, want the 14-digit form", resp.GTIN)
+	}
+
+	saw = nil
+	_, err = s.CreateProduct(ctx, dto.CreateProductRequest{Name: "Item", GTIN: stringPtr("4006381333932")})
+	if !errors.Is(err, service.ErrInvalidGTIN) {
+		t.Errorf("CreateProduct() error = %v, want ErrInvalidGTIN", err)
+	}
+	if saw != nil {
+		t.Fatalf("unexpected db call")
+	}
+}
+
+func TestProductService_CreateProduct_Handling(t *testing.T) {
+	var saw store.CreateProductParams
+	mockQ := &MockQuerier{
+		CreateProductFunc: func(ctx context.Context, arg store.CreateProductParams) (store.Product, error) {
+			saw = arg
+			return store.Product{
+				ProductID:      uuid.New(),
+				Name:           arg.Name,
+				Fragile:        arg.Fragile,
+				ThisSideUp:     arg.ThisSideUp,
+				Stackable:      arg.Stackable,
+				MaxStackLoadKg: arg.MaxStackLoadKg,
+				HazmatClass:    arg.HazmatClass,
+				PackagingType:  arg.PackagingType,
+			}, nil
+		},
+	}
+	s := service.NewProductService(mockQ)
+	ctx := ctxWithWorkspaceID(uuid.New())
+
+	// Left out, a product is stackable and neither fragile nor this-side-up.
+	resp, err := s.CreateProduct(ctx, dto.CreateProductRequest{Name: "Carton"})
+	if err != nil {
+		t.Fatalf("CreateProduct() error = %v", err)
+	}
+	if saw.Fragile || saw.ThisSideUp || !saw.Stackable || saw.MaxStackLoadKg.Valid {
+		t.Errorf("default handling = %+v, want stackable only", saw)
+	}
+	if !resp.Stackable || resp.MaxStackLoadKG != nil {
+		t.Errorf("response handling = %+v, want stackable without limit", resp.HandlingAttributes)
+	}
+
+	yes, no, load := true, false, 80.0
+	req := dto.CreateProductRequest{Name: "Paint"}
+	req.Fragile = &yes
+	req.ThisSideUp = &yes
+	req.Stackable = &no
+	req.MaxStackLoadKG = &load
+	req.HazmatClass = stringPtr("3")
+	req.PackagingType = stringPtr("drum")
+	resp, err = s.CreateProduct(ctx, req)
+	if err != nil {
+		t.Fatalf("CreateProduct() error = %v", err)
+	}
+	if !resp.Fragile || !resp.ThisSideUp || resp.Stackable {
+		t.Errorf("flags = %+v, want fragile, this side up, not stackable", resp.HandlingAttributes)
+	}
+	if resp.MaxStackLoadKG == nil || *resp.MaxStackLoadKG != 80 {
+		t.Errorf("MaxStackLoadKG = %v, want 80", resp.MaxStackLoadKG)
+	}
+	if resp.HazmatClass == nil || *resp.HazmatClass != "3" || resp.PackagingType == nil || *resp.PackagingType != "drum" {
+		t.Errorf("hazmat %v packaging %v, want 3 and drum", resp.HazmatClass, resp.PackagingType)
+	}
+}
+
+func TestProductService_CreateProduct_Packaging(t *testing.T) {
+	var saw store.CreateProductParams
+	mockQ := &MockQuerier{
+		CreateProductFunc: func(ctx context.Context, arg store.CreateProductParams) (store.Product, error) {
+			saw = arg
+			return store.Product{ProductID: uuid.New(), Name: arg.Name, Packaging: arg.Packaging, ShippingUnit: arg.ShippingUnit}, nil
+		},
+	}
+	s := service.NewProductService(mockQ)
+	ctx := ctxWithWorkspaceID(uuid.New())
+
+	caseUnit := "case"
+	resp, err := s.CreateProduct(ctx, dto.CreateProductRequest{
+		Name: "Soap",
+		Packaging: []dto.PackagingLevel{
+			{Level: "case", Contains: 4, LengthMM: 400, WidthMM: 300, HeightMM: 200, WeightKG: 6},
+			{Level: "inner", Contains: 6, LengthMM: 200, WidthMM: 150, HeightMM: 100, WeightKG: 1.5},
+		},
+		ShippingUnit: &caseUnit,
+	})
+	if err != nil {
+		t.Fatalf("CreateProduct() error = %v", err)
+	}
+	if saw.ShippingUnit != "case" {
+		t.Errorf("stored shipping unit = %q, want case", saw.ShippingUnit)
+	}
+	if len(resp.Packaging) != 2 {
+		t.Fatalf("Packaging = %+v, want 2 levels", resp.Packaging)
+	}
+	if resp.Packaging[0].Level != "inner" || resp.Packaging[0].EachQuantity != 6 {
+		t.Errorf("first level = %+v, want inner of 6", resp.Packaging[0])
+	}
+	if resp.Packaging[1].Level != "case" || resp.Packaging[1].EachQuantity != 24 {
+		t.Errorf("second level = %+v, want case of 24", resp.Packaging[1])
+	}
+
+	// Without a hierarchy a product ships in eaches.
+	resp, err = s.CreateProduct(ctx, dto.CreateProductRequest{Name: "Loose"})
+	if err != nil {
+		t.Fatalf("CreateProduct() error = %v", err)
+	}
+	if saw.Packaging != nil || resp.ShippingUnit != "each" {
+		t.Errorf("packaging %s shipping unit %q, want none and each", saw.Packaging, resp.ShippingUnit)
+	}
+
+	pallet := "pallet"
+	saw = store.CreateProductParams{}
+	_, err = s.CreateProduct(ctx, dto.CreateProductRequest{Name: "Soap", ShippingUnit: &pallet})
+	if !errors.Is(err, service.ErrInvalidPackaging) {
+		t.Errorf("CreateProduct() error = %v, want ErrInvalidPackaging", err)
+	}
+	_, err = s.CreateProduct(ctx, dto.CreateProductRequest{
+		Name: "Soap",
+		Packaging: []dto.PackagingLevel{
+			{Level: "case", Contains: 4, LengthMM: 1, WidthMM: 1, HeightMM: 1, WeightKG: 1},
+			{Level: "case", Contains: 2, LengthMM: 1, WidthMM: 1, HeightMM: 1, WeightKG: 1},
+		},
+	})
+	if !errors.Is(err, service.ErrInvalidPackaging) {
+		t.Errorf("CreateProduct() error = %v, want ErrInvalidPackaging", err)
+	}
+	if saw.Name != "" {
+		t.Fatalf("unexpected db call")
+	}
+}
+
+func TestProductService_UpdateProduct_KeepsPackaging(t *testing.T) {
+	stored := store.Product{
+		ProductID:    uuid.New(),
+		Packaging:    []byte(`[{"level":"case","contains":12,"length_mm":400,"width_mm":300,"height_mm":200,"weight_kg":6,"each_quantity":12}]`),
+		ShippingUnit: "case",
+	}
+	var saw store.UpdateProductParams
+	mockQ := &MockQuerier{
+		GetProductFunc: func(ctx context.Context, arg store.GetProductParams) (store.Product, error) {
+			return stored, nil
+		},
+		UpdateProductFunc: func(ctx context.Context, arg store.UpdateProductParams) error {
+			saw = arg
+			return nil
+		},
+	}
+	s := service.NewProductService(mockQ)
+	ctx := ctxWithWorkspaceID(uuid.New())
+	req := dto.UpdateProductRequest{Name: "Soap", LengthMM: 100, WidthMM: 100, HeightMM: 100, WeightKG: 1}
+
+	if err := s.UpdateProduct(ctx, stored.ProductID.String(), req); err != nil {
+		t.Fatalf("UpdateProduct() error = %v", err)
+	}
+	if saw.ShippingUnit != "case" || saw.Packaging == nil {
+		t.Errorf("packaging %s shipping unit %q, want the stored case hierarchy", saw.Packaging, saw.ShippingUnit)
+	}
+
+	// An empty list removes the hierarchy; the shipping unit must follow.
+	req.Packaging = []dto.PackagingLevel{}
+	err := s.UpdateProduct(ctx, stored.ProductID.String(), req)
+	if !errors.Is(err, service.ErrInvalidPackaging) {
+		t.Errorf("UpdateProduct() error = %v, want ErrInvalidPackaging", err)
+	}
+	each := "each"
+	req.ShippingUnit = &each
+	if err := s.UpdateProduct(ctx, stored.ProductID.String(), req); err != nil {
+		t.Fatalf("UpdateProduct() error = %v", err)
+	}
+	if saw.Packaging != nil || saw.ShippingUnit != "each" {
+		t.Errorf("packaging %s shipping unit %q, want none and each", saw.Packaging, saw.ShippingUnit)
+	}
+}
+
 func TestProductService_GetProduct(t *testing.T) {
 	id := uuid.New()
 	name := "Item 2"
@@ -335,6 +532,68 @@ func TestProductService_GetProduct(t *testing.T) {
 	})
 }
 
+func TestProductService_GetProductBySKU(t *testing.T) {
+	id := uuid.New()
+	sku := "TV55-001"
+
+	t.Run("scoped_to_workspace", func(t *testing.T) {
+		workspaceID := uuid.New()
+		mockQ := &MockQuerier{
+			GetProductBySkuFunc: func(ctx context.Context, arg store.GetProductBySkuParams) (store.Product, error) {
+				if arg.Sku == nil || *arg.Sku != sku {
+					return store.Product{}, fmt.Errorf("sku mismatch")
+				}
+				if arg.WorkspaceID == nil || *arg.WorkspaceID != workspaceID {
+					return store.Product{}, fmt.Errorf("workspace mismatch")
+				}
+				return store.Product{ProductID: id, Sku: &sku}, nil
+			},
+		}
+
+		s := service.NewProductService(mockQ)
+		resp, err := s.GetProductBySKU(ctxWithWorkspaceID(workspaceID), sku)
+		if err != nil {
+			t.Fatalf("GetProductBySKU() error = %v", err)
+		}
+		if resp.ID != id.String() {
+			t.Errorf("ID = %v, want %v", resp.ID, id)
+		}
+	})
+
+	t.Run("not_found", func(t *testing.T) {
+		mockQ := &MockQuerier{
+			GetProductBySkuFunc: func(ctx context.Context, arg store.GetProductBySkuParams) (store.Product, error) {
+				return store.Product{}, fmt.Errorf("no rows")
+			},
+		}
+
+		s := service.NewProductService(mockQ)
+		if _, err := s.GetProductBySKU(ctxWithWorkspaceID(uuid.New()), sku); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+
+	t.Run("founder_no_override_uses_any", func(t *testing.T) {
+		mockQ := &MockQuerier{
+			GetProductBySkuAnyFunc: func(ctx context.Context, s *string) (store.Product, error) {
+				return store.Product{ProductID: id, Sku: s}, nil
+			},
+			GetProductBySkuFunc: func(ctx context.Context, arg store.GetProductBySkuParams) (store.Product, error) {
+				return store.Product{}, fmt.Errorf("unexpected scoped call")
+			},
+		}
+
+		s := service.NewProductService(mockQ)
+		resp, err := s.GetProductBySKU(ctxWithRole("founder"), sku)
+		if err != nil {
+			t.Fatalf("GetProductBySKU() error = %v", err)
+		}
+		if resp.ID != id.String() {
+			t.Errorf("ID = %v, want %v", resp.ID, id)
+		}
+	})
+}
+
 func TestProductService_ListProducts(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -558,6 +817,9 @@ func TestProductService_UpdateProduct(t *testing.T) {
 			}
 
 			mockQ := &MockQuerier{
+				GetProductFunc: func(ctx context.Context, arg store.GetProductParams) (store.Product, error) {
+					return store.Product{ProductID: id, WorkspaceID: arg.WorkspaceID}, nil
+				},
 				UpdateProductFunc: func(ctx context.Context, arg store.UpdateProductParams) error {
 					// Should not be called for error cases
 					if tt.name == "trial_no_workspace_forbidden" || tt.name == "invalid_uuid" || tt.name == "workspace_override_id_error" || tt.name == "workspace_id_from_context_error" {
@@ -582,6 +844,9 @@ func TestProductService_UpdateProduct(t *testing.T) {
 		called := false
 
 		mockQ := &MockQuerier{
+			GetProductAnyFunc: func(ctx context.Context, productID uuid.UUID) (store.Product, error) {
+				return store.Product{ProductID: productID}, nil
+			},
 			UpdateProductAnyFunc: func(ctx context.Context, arg store.UpdateProductAnyParams) error {
 				called = true
 				if arg.ProductID != id {
@@ -615,6 +880,12 @@ func TestProductService_UpdateProduct(t *testing.T) {
 		req := dto.UpdateProductRequest{Name: "founder_update_scoped"}
 
 		mockQ := &MockQuerier{
+			GetProductFunc: func(ctx context.Context, arg store.GetProductParams) (store.Product, error) {
+				if arg.WorkspaceID == nil || *arg.WorkspaceID != overrideWorkspaceID {
+					return store.Product{}, fmt.Errorf("workspace mismatch")
+				}
+				return store.Product{ProductID: arg.ProductID}, nil
+			},
 			UpdateProductFunc: func(ctx context.Context, arg store.UpdateProductParams) error {
 				if arg.ProductID != id {
 					return fmt.Errorf("id mismatch")
@@ -638,6 +909,60 @@ func TestProductService_UpdateProduct(t *testing.T) {
 			t.Fatalf("UpdateProduct() error = %v", err)
 		}
 	})
+
+	t.Run("omitted_attributes_keep_stored_values", func(t *testing.T) {
+		ctx := ctxWithWorkspaceID(uuid.New())
+		hazmat, packagingType, temp := "3", "drum", "chilled"
+		stored := store.Product{
+			ProductID:           id,
+			FrictionCoefficient: toNumeric(0.4),
+			TemperatureClass:    &temp,
+			Gtin:                stringPtr("04006381333931"),
+			Fragile:             true,
+			ThisSideUp:          true,
+			Stackable:           false,
+			MaxStackLoadKg:      toNumeric(150.0),
+			HazmatClass:         &hazmat,
+			PackagingType:       &packagingType,
+		}
+
+		var got store.UpdateProductParams
+		mockQ := &MockQuerier{
+			GetProductFunc: func(ctx context.Context, arg store.GetProductParams) (store.Product, error) {
+				return stored, nil
+			},
+			UpdateProductFunc: func(ctx context.Context, arg store.UpdateProductParams) error {
+				got = arg
+				return nil
+			},
+		}
+
+		s := service.NewProductService(mockQ)
+		req := dto.UpdateProductRequest{Name: name, LengthMM: 100, WidthMM: 100, HeightMM: 100, WeightKG: 1}
+		if err := s.UpdateProduct(ctx, id.String(), req); err != nil {
+			t.Fatalf("UpdateProduct() error = %v", err)
+		}
+		if got.FrictionCoefficient != stored.FrictionCoefficient || got.MaxStackLoadKg != stored.MaxStackLoadKg {
+			t.Errorf("numeric attributes were not kept")
+		}
+		if got.TemperatureClass != stored.TemperatureClass || got.Gtin != stored.Gtin ||
+			got.HazmatClass != stored.HazmatClass || got.PackagingType != stored.PackagingType {
+			t.Errorf("text attributes were not kept")
+		}
+		if !got.Fragile || !got.ThisSideUp || got.Stackable {
+			t.Errorf("handling flags = %v/%v/%v, want true/true/false", got.Fragile, got.ThisSideUp, got.Stackable)
+		}
+
+		// Given values replace the stored ones and "" clears text attributes.
+		fragile, empty := false, ""
+		req.ProductHandling = dto.ProductHandling{Fragile: &fragile, HazmatClass: &empty}
+		if err := s.UpdateProduct(ctx, id.String(), req); err != nil {
+			t.Fatalf("UpdateProduct() error = %v", err)
+		}
+		if got.Fragile || got.HazmatClass != nil || !got.ThisSideUp {
+			t.Errorf("fragile = %v, hazmat = %v, this side up = %v", got.Fragile, got.HazmatClass, got.ThisSideUp)
+		}
+	})
 }
 
 func TestProductService_DeleteProduct(t *testing.T) {
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/ekastn/load-stuffing-calculator/internal/dto"
+	"github.com/ekastn/load-stuffing-calculator/internal/gs1"
 	"github.com/ekastn/load-stuffing-calculator/internal/store"
 	"github.com/ekastn/load-stuffing-calculator/internal/types"
 	"github.com/google/uuid"
@@ -16,6 +17,10 @@ type WorkspaceService interface {
 	CreateWorkspace(ctx context.Context, req dto.CreateWorkspaceRequest) (*dto.WorkspaceResponse, error)
 	UpdateWorkspace(ctx context.Context, id string, req dto.UpdateWorkspaceRequest) (*dto.WorkspaceResponse, error)
 	DeleteWorkspace(ctx context.Context, id string) error
+	GetGS1Settings(ctx context.Context, id string) (*dto.GS1SettingsResponse, error)
+	// UpdateGS1Settings sets the company prefix and extension digit SSCCs
+	// are issued with. The serial counter carries over.
+	UpdateGS1Settings(ctx context.Context, id string, req dto.UpdateGS1SettingsRequest) (*dto.GS1SettingsResponse, error)
 }
 
 type workspaceService struct {
@@ -202,6 +207,74 @@ func (s *workspaceService) DeleteWorkspace(ctx context.Context, id string) error
 	return nil
 }
 
+func (s *workspaceService) GetGS1Settings(ctx context.Context, id string) (*dto.GS1SettingsResponse, error) {
+	workspaceID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid workspace id")
+	}
+	if _, err := ensureWorkspaceAdminOrOwnerOrFounder(ctx, s.q, workspaceID); err != nil {
+		return nil, err
+	}
+
+	settings, err := s.q.GetWorkspaceGS1(ctx, workspaceID)
+	if err != nil {
+		return nil, ErrGS1NotConfigured
+	}
+	resp := mapGS1Settings(settings)
+	return &resp, nil
+}
+
+func (s *workspaceService) UpdateGS1Settings(ctx context.Context, id string, req dto.UpdateGS1SettingsRequest) (*dto.GS1SettingsResponse, error) {
+	workspaceID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid workspace id")
+	}
+	ws, err := ensureWorkspaceExists(ctx, s.q, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureWorkspaceOwnerOrFounder(ctx, s.q, ws); err != nil {
+		return nil, err
+	}
+
+	if !gs1.ValidCompanyPrefix(req.CompanyPrefix) {
+		return nil, gs1.ErrInvalidCompanyPrefix
+	}
+	extension := 0
+	if req.ExtensionDigit != nil {
+		extension = *req.ExtensionDigit
+	}
+	if extension < 0 || extension > 9 {
+		return nil, fmt.Errorf("extension digit must be 0 to 9")
+	}
+
+	settings, err := s.q.UpsertWorkspaceGS1(ctx, store.UpsertWorkspaceGS1Params{
+		WorkspaceID:    ws.WorkspaceID,
+		CompanyPrefix:  req.CompanyPrefix,
+		ExtensionDigit: int16(extension),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update GS1 settings: %w", err)
+	}
+	resp := mapGS1Settings(settings)
+	return &resp, nil
+}
+
+func mapGS1Settings(g store.WorkspaceGs1) dto.GS1SettingsResponse {
+	left := gs1.MaxSerial(g.CompanyPrefix) - g.LastSerial
+	if left < 0 {
+		left = 0
+	}
+	return dto.GS1SettingsResponse{
+		WorkspaceID:    g.WorkspaceID.String(),
+		CompanyPrefix:  g.CompanyPrefix,
+		ExtensionDigit: int(g.ExtensionDigit),
+		LastSerial:     g.LastSerial,
+		SerialsLeft:    left,
+		UpdatedAt:      g.UpdatedAt,
+	}
+}
+
 func mapWorkspace(ws store.Workspace) dto.WorkspaceResponse {
 	return dto.WorkspaceResponse{
 		WorkspaceID: ws.WorkspaceID.String(),
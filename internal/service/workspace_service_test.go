@@ -848,3 +848,47 @@ func TestWorkspaceService_ListWorkspaces(t *testing.T) {
 		})
 	}
 }
+
+func TestWorkspaceService_UpdateGS1Settings(t *testing.T) {
+	mockQ := &mocks.MockQuerier{}
+	svc := service.NewWorkspaceService(mockQ)
+
+	wsID := uuid.New()
+	ownerID := uuid.New()
+	ctx := ctxWithUserAndRole(types.RoleOwner, ownerID)
+
+	mockQ.GetWorkspaceFunc = func(ctx context.Context, workspaceID uuid.UUID) (store.Workspace, error) {
+		return store.Workspace{WorkspaceID: wsID, Type: "organization", Name: "Org", OwnerUserID: ownerID}, nil
+	}
+	var saved *store.UpsertWorkspaceGS1Params
+	mockQ.UpsertWorkspaceGS1Func = func(ctx context.Context, arg store.UpsertWorkspaceGS1Params) (store.WorkspaceGs1, error) {
+		saved = &arg
+		return store.WorkspaceGs1{WorkspaceID: arg.WorkspaceID, CompanyPrefix: arg.CompanyPrefix, ExtensionDigit: arg.ExtensionDigit, LastSerial: 10}, nil
+	}
+
+	ext := 3
+	resp, err := svc.UpdateGS1Settings(ctx, wsID.String(), dto.UpdateGS1SettingsRequest{CompanyPrefix: "0614141", ExtensionDigit: &ext})
+	if err != nil {
+		t.Fatalf("expected nil error, got %v", err)
+	}
+	if saved == nil || saved.CompanyPrefix != "0614141" || saved.ExtensionDigit != 3 {
+		t.Fatalf("unexpected upsert %+v", saved)
+	}
+	// A 7-digit prefix leaves 9 serial digits.
+	if resp.SerialsLeft != 999999999-10 {
+		t.Fatalf("expected %d serials left, got %d", 999999999-10, resp.SerialsLeft)
+	}
+
+	saved = nil
+	if _, err := svc.UpdateGS1Settings(ctx, wsID.String(), dto.UpdateGS1SettingsRequest{CompanyPrefix: "06141"}); err == nil {
+		t.Fatalf("expected error for a short prefix")
+	}
+	if saved != nil {
+		t.Fatalf("unexpected upsert")
+	}
+
+	other := ctxWithUserAndRole(types.RoleOwner, uuid.New())
+	if _, err := svc.UpdateGS1Settings(other, wsID.String(), dto.UpdateGS1SettingsRequest{CompanyPrefix: "0614141"}); err == nil {
+		t.Fatalf("expected error for a user who does not own the workspace")
+	}
+}
@@ -20,21 +20,31 @@ INSERT INTO containers (
     inner_width_mm,
     inner_height_mm,
     max_weight_kg,
-    description
+    description,
+    lashing_points_per_side,
+    lashing_point_capacity_dan,
+    compartments,
+    floor_load_kg_m2,
+    line_load_kg_m
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
 )
-RETURNING container_id, name, inner_length_mm, inner_width_mm, inner_height_mm, max_weight_kg, description, created_at, updated_at, workspace_id
+RETURNING container_id, name, inner_length_mm, inner_width_mm, inner_height_mm, max_weight_kg, description, created_at, updated_at, workspace_id, lashing_points_per_side, lashing_point_capacity_dan, compartments, floor_load_kg_m2, line_load_kg_m
 `
 
 type CreateContainerParams struct {
-	WorkspaceID   *uuid.UUID     `json:"workspace_id"`
-	Name          string         `json:"name"`
-	InnerLengthMm pgtype.Numeric `json:"inner_length_mm"`
-	InnerWidthMm  pgtype.Numeric `json:"inner_width_mm"`
-	InnerHeightMm pgtype.Numeric `json:"inner_height_mm"`
-	MaxWeightKg   pgtype.Numeric `json:"max_weight_kg"`
-	Description   *string        `json:"description"`
+	WorkspaceID             *uuid.UUID     `json:"workspace_id"`
+	Name                    string         `json:"name"`
+	InnerLengthMm           pgtype.Numeric `json:"inner_length_mm"`
+	InnerWidthMm            pgtype.Numeric `json:"inner_width_mm"`
+	InnerHeightMm           pgtype.Numeric `json:"inner_height_mm"`
+	MaxWeightKg             pgtype.Numeric `json:"max_weight_kg"`
+	Description             *string        `json:"description"`
+	LashingPointsPerSide    int32          `json:"lashing_points_per_side"`
+	LashingPointCapacityDan pgtype.Numeric `json:"lashing_point_capacity_dan"`
+	Compartments            []byte         `json:"compartments"`
+	FloorLoadKgM2           pgtype.Numeric `json:"floor_load_kg_m2"`
+	LineLoadKgM             pgtype.Numeric `json:"line_load_kg_m"`
 }
 
 func (q *Queries) CreateContainer(ctx context.Context, arg CreateContainerParams) (Container, error) {
@@ -46,6 +56,11 @@ func (q *Queries) CreateContainer(ctx context.Context, arg CreateContainerParams
 		arg.InnerHeightMm,
 		arg.MaxWeightKg,
 		arg.Description,
+		arg.LashingPointsPerSide,
+		arg.LashingPointCapacityDan,
+		arg.Compartments,
+		arg.FloorLoadKgM2,
+		arg.LineLoadKgM,
 	)
 	var i Container
 	err := row.Scan(
@@ -59,6 +74,11 @@ func (q *Queries) CreateContainer(ctx context.Context, arg CreateContainerParams
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.WorkspaceID,
+		&i.LashingPointsPerSide,
+		&i.LashingPointCapacityDan,
+		&i.Compartments,
+		&i.FloorLoadKgM2,
+		&i.LineLoadKgM,
 	)
 	return i, err
 }
@@ -90,7 +110,7 @@ func (q *Queries) DeleteContainerAny(ctx context.Context, containerID uuid.UUID)
 }
 
 const getContainer = `-- name: GetContainer :one
-SELECT container_id, name, inner_length_mm, inner_width_mm, inner_height_mm, max_weight_kg, description, created_at, updated_at, workspace_id
+SELECT container_id, name, inner_length_mm, inner_width_mm, inner_height_mm, max_weight_kg, description, created_at, updated_at, workspace_id, lashing_points_per_side, lashing_point_capacity_dan, compartments, floor_load_kg_m2, line_load_kg_m
 FROM containers
 WHERE container_id = $1
   AND (workspace_id = $2 OR workspace_id IS NULL)
@@ -115,12 +135,17 @@ func (q *Queries) GetContainer(ctx context.Context, arg GetContainerParams) (Con
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.WorkspaceID,
+		&i.LashingPointsPerSide,
+		&i.LashingPointCapacityDan,
+		&i.Compartments,
+		&i.FloorLoadKgM2,
+		&i.LineLoadKgM,
 	)
 	return i, err
 }
 
 const getContainerAny = `-- name: GetContainerAny :one
-SELECT container_id, name, inner_length_mm, inner_width_mm, inner_height_mm, max_weight_kg, description, created_at, updated_at, workspace_id
+SELECT container_id, name, inner_length_mm, inner_width_mm, inner_height_mm, max_weight_kg, description, created_at, updated_at, workspace_id, lashing_points_per_side, lashing_point_capacity_dan, compartments, floor_load_kg_m2, line_load_kg_m
 FROM containers
 WHERE container_id = $1
 `
@@ -139,12 +164,17 @@ func (q *Queries) GetContainerAny(ctx context.Context, containerID uuid.UUID) (C
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.WorkspaceID,
+		&i.LashingPointsPerSide,
+		&i.LashingPointCapacityDan,
+		&i.Compartments,
+		&i.FloorLoadKgM2,
+		&i.LineLoadKgM,
 	)
 	return i, err
 }
 
 const listContainers = `-- name: ListContainers :many
-SELECT container_id, name, inner_length_mm, inner_width_mm, inner_height_mm, max_weight_kg, description, created_at, updated_at, workspace_id
+SELECT container_id, name, inner_length_mm, inner_width_mm, inner_height_mm, max_weight_kg, description, created_at, updated_at, workspace_id, lashing_points_per_side, lashing_point_capacity_dan, compartments, floor_load_kg_m2, line_load_kg_m
 FROM containers
 WHERE workspace_id = $1 OR workspace_id IS NULL
 ORDER BY (workspace_id IS NULL) DESC, name
@@ -177,6 +207,11 @@ func (q *Queries) ListContainers(ctx context.Context, arg ListContainersParams)
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.WorkspaceID,
+			&i.LashingPointsPerSide,
+			&i.LashingPointCapacityDan,
+			&i.Compartments,
+			&i.FloorLoadKgM2,
+			&i.LineLoadKgM,
 		); err != nil {
 			return nil, err
 		}
@@ -189,7 +224,7 @@ func (q *Queries) ListContainers(ctx context.Context, arg ListContainersParams)
 }
 
 const listContainersAll = `-- name: ListContainersAll :many
-SELECT container_id, name, inner_length_mm, inner_width_mm, inner_height_mm, max_weight_kg, description, created_at, updated_at, workspace_id
+SELECT container_id, name, inner_length_mm, inner_width_mm, inner_height_mm, max_weight_kg, description, created_at, updated_at, workspace_id, lashing_points_per_side, lashing_point_capacity_dan, compartments, floor_load_kg_m2, line_load_kg_m
 FROM containers
 ORDER BY (workspace_id IS NULL) DESC, name
 LIMIT $1 OFFSET $2
@@ -220,6 +255,11 @@ func (q *Queries) ListContainersAll(ctx context.Context, arg ListContainersAllPa
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.WorkspaceID,
+			&i.LashingPointsPerSide,
+			&i.LashingPointCapacityDan,
+			&i.Compartments,
+			&i.FloorLoadKgM2,
+			&i.LineLoadKgM,
 		); err != nil {
 			return nil, err
 		}
@@ -240,20 +280,30 @@ SET
     inner_height_mm = $6,
     max_weight_kg = $7,
     description = $8,
-    updated_at = NOW()
+    updated_at = NOW(),
+    lashing_points_per_side = $9,
+    lashing_point_capacity_dan = $10,
+    compartments = $11,
+    floor_load_kg_m2 = $12,
+    line_load_kg_m = $13
 WHERE container_id = $1
   AND workspace_id = $2
 `
 
 type UpdateContainerParams struct {
-	ContainerID   uuid.UUID      `json:"container_id"`
-	WorkspaceID   *uuid.UUID     `json:"workspace_id"`
-	Name          string         `json:"name"`
-	InnerLengthMm pgtype.Numeric `json:"inner_length_mm"`
-	InnerWidthMm  pgtype.Numeric `json:"inner_width_mm"`
-	InnerHeightMm pgtype.Numeric `json:"inner_height_mm"`
-	MaxWeightKg   pgtype.Numeric `json:"max_weight_kg"`
-	Description   *string        `json:"description"`
+	ContainerID             uuid.UUID      `json:"container_id"`
+	WorkspaceID             *uuid.UUID     `json:"workspace_id"`
+	Name                    string         `json:"name"`
+	InnerLengthMm           pgtype.Numeric `json:"inner_length_mm"`
+	InnerWidthMm            pgtype.Numeric `json:"inner_width_mm"`
+	InnerHeightMm           pgtype.Numeric `json:"inner_height_mm"`
+	MaxWeightKg             pgtype.Numeric `json:"max_weight_kg"`
+	Description             *string        `json:"description"`
+	LashingPointsPerSide    int32          `json:"lashing_points_per_side"`
+	LashingPointCapacityDan pgtype.Numeric `json:"lashing_point_capacity_dan"`
+	Compartments            []byte         `json:"compartments"`
+	FloorLoadKgM2           pgtype.Numeric `json:"floor_load_kg_m2"`
+	LineLoadKgM             pgtype.Numeric `json:"line_load_kg_m"`
 }
 
 func (q *Queries) UpdateContainer(ctx context.Context, arg UpdateContainerParams) error {
@@ -266,6 +316,11 @@ func (q *Queries) UpdateContainer(ctx context.Context, arg UpdateContainerParams
 		arg.InnerHeightMm,
 		arg.MaxWeightKg,
 		arg.Description,
+		arg.LashingPointsPerSide,
+		arg.LashingPointCapacityDan,
+		arg.Compartments,
+		arg.FloorLoadKgM2,
+		arg.LineLoadKgM,
 	)
 	return err
 }
@@ -279,18 +334,28 @@ SET
     inner_height_mm = $5,
     max_weight_kg = $6,
     description = $7,
-    updated_at = NOW()
+    updated_at = NOW(),
+    lashing_points_per_side = $8,
+    lashing_point_capacity_dan = $9,
+    compartments = $10,
+    floor_load_kg_m2 = $11,
+    line_load_kg_m = $12
 WHERE container_id = $1
 `
 
 type UpdateContainerAnyParams struct {
-	ContainerID   uuid.UUID      `json:"container_id"`
-	Name          string         `json:"name"`
-	InnerLengthMm pgtype.Numeric `json:"inner_length_mm"`
-	InnerWidthMm  pgtype.Numeric `json:"inner_width_mm"`
-	InnerHeightMm pgtype.Numeric `json:"inner_height_mm"`
-	MaxWeightKg   pgtype.Numeric `json:"max_weight_kg"`
-	Description   *string        `json:"description"`
+	ContainerID             uuid.UUID      `json:"container_id"`
+	Name                    string         `json:"name"`
+	InnerLengthMm           pgtype.Numeric `json:"inner_length_mm"`
+	InnerWidthMm            pgtype.Numeric `json:"inner_width_mm"`
+	InnerHeightMm           pgtype.Numeric `json:"inner_height_mm"`
+	MaxWeightKg             pgtype.Numeric `json:"max_weight_kg"`
+	Description             *string        `json:"description"`
+	LashingPointsPerSide    int32          `json:"lashing_points_per_side"`
+	LashingPointCapacityDan pgtype.Numeric `json:"lashing_point_capacity_dan"`
+	Compartments            []byte         `json:"compartments"`
+	FloorLoadKgM2           pgtype.Numeric `json:"floor_load_kg_m2"`
+	LineLoadKgM             pgtype.Numeric `json:"line_load_kg_m"`
 }
 
 func (q *Queries) UpdateContainerAny(ctx context.Context, arg UpdateContainerAnyParams) error {
@@ -302,6 +367,11 @@ func (q *Queries) UpdateContainerAny(ctx context.Context, arg UpdateContainerAny
 		arg.InnerHeightMm,
 		arg.MaxWeightKg,
 		arg.Description,
+		arg.LashingPointsPerSide,
+		arg.LashingPointCapacityDan,
+		arg.Compartments,
+		arg.FloorLoadKgM2,
+		arg.LineLoadKgM,
 	)
 	return err
 }
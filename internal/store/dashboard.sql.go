@@ -12,7 +12,7 @@ import (
 )
 
 const countGlobalActivePlans = `-- name: CountGlobalActivePlans :one
-SELECT COUNT(*) FROM load_plans WHERE status NOT IN ('COMPLETED', 'FAILED')
+SELECT COUNT(*) FROM load_plans WHERE status NOT IN ('COMPLETED', 'FAILED') AND parent_plan_id IS NULL
 `
 
 func (q *Queries) CountGlobalActivePlans(ctx context.Context) (int64, error) {
@@ -23,7 +23,7 @@ func (q *Queries) CountGlobalActivePlans(ctx context.Context) (int64, error) {
 }
 
 const countGlobalCompletedPlans = `-- name: CountGlobalCompletedPlans :one
-SELECT COUNT(*) FROM load_plans WHERE status = 'COMPLETED'
+SELECT COUNT(*) FROM load_plans WHERE status = 'COMPLETED' AND parent_plan_id IS NULL
 `
 
 func (q *Queries) CountGlobalCompletedPlans(ctx context.Context) (int64, error) {
@@ -37,6 +37,7 @@ const countGlobalCompletedPlansToday = `-- name: CountGlobalCompletedPlansToday
 SELECT COUNT(*) FROM load_plans 
 WHERE status = 'COMPLETED' 
 AND created_at >= CURRENT_DATE
+AND parent_plan_id IS NULL
 `
 
 func (q *Queries) CountGlobalCompletedPlansToday(ctx context.Context) (int64, error) {
@@ -58,7 +59,10 @@ func (q *Queries) CountGlobalContainers(ctx context.Context) (int64, error) {
 }
 
 const countGlobalTotalItems = `-- name: CountGlobalTotalItems :one
-SELECT COALESCE(SUM(quantity), 0)::BIGINT FROM load_items
+SELECT COALESCE(SUM(li.quantity), 0)::BIGINT
+FROM load_items li
+JOIN load_plans lp ON li.plan_id = lp.plan_id
+WHERE lp.parent_plan_id IS NULL
 `
 
 func (q *Queries) CountGlobalTotalItems(ctx context.Context) (int64, error) {
@@ -86,6 +90,7 @@ SELECT COUNT(*)
 FROM load_plans 
 WHERE workspace_id = $1 
 AND status NOT IN ('COMPLETED', 'FAILED')
+AND parent_plan_id IS NULL
 `
 
 func (q *Queries) CountWorkspaceActivePlans(ctx context.Context, workspaceID *uuid.UUID) (int64, error) {
@@ -100,6 +105,7 @@ SELECT COUNT(*)
 FROM load_plans 
 WHERE workspace_id = $1 
 AND status = 'COMPLETED'
+AND parent_plan_id IS NULL
 `
 
 func (q *Queries) CountWorkspaceCompletedPlans(ctx context.Context, workspaceID *uuid.UUID) (int64, error) {
@@ -115,6 +121,7 @@ FROM load_plans
 WHERE workspace_id = $1 
 AND status = 'COMPLETED' 
 AND created_at >= CURRENT_DATE
+AND parent_plan_id IS NULL
 `
 
 func (q *Queries) CountWorkspaceCompletedPlansToday(ctx context.Context, workspaceID *uuid.UUID) (int64, error) {
@@ -142,6 +149,7 @@ SELECT COALESCE(SUM(li.quantity), 0)::BIGINT
 FROM load_items li
 JOIN load_plans lp ON li.plan_id = lp.plan_id
 WHERE lp.workspace_id = $1
+AND lp.parent_plan_id IS NULL
 `
 
 func (q *Queries) CountWorkspaceItems(ctx context.Context, workspaceID *uuid.UUID) (int64, error) {
@@ -167,8 +175,11 @@ func (q *Queries) CountWorkspaceMembers(ctx context.Context, workspaceID uuid.UU
 }
 
 const getGlobalAvgVolumeUtilization = `-- name: GetGlobalAvgVolumeUtilization :one
-SELECT COALESCE(AVG(volume_utilization_pct), 0)::FLOAT
-FROM plan_results
+SELECT COALESCE(AVG(pr.volume_utilization_pct), 0)::FLOAT
+FROM plan_results pr
+JOIN load_plans lp ON pr.plan_id = lp.plan_id
+WHERE pr.is_active
+AND lp.parent_plan_id IS NULL
 `
 
 func (q *Queries) GetGlobalAvgVolumeUtilization(ctx context.Context) (float64, error) {
@@ -181,6 +192,7 @@ func (q *Queries) GetGlobalAvgVolumeUtilization(ctx context.Context) (float64, e
 const getGlobalPlanStatusDistribution = `-- name: GetGlobalPlanStatusDistribution :many
 SELECT status, COUNT(*) as count 
 FROM load_plans 
+WHERE parent_plan_id IS NULL
 GROUP BY status
 `
 
@@ -213,7 +225,9 @@ const getWorkspaceAvgVolumeUtilization = `-- name: GetWorkspaceAvgVolumeUtilizat
 SELECT COALESCE(AVG(pr.volume_utilization_pct), 0)::FLOAT
 FROM plan_results pr
 JOIN load_plans lp ON pr.plan_id = lp.plan_id
-WHERE lp.workspace_id = $1
+WHERE pr.is_active
+AND lp.workspace_id = $1
+AND lp.parent_plan_id IS NULL
 `
 
 func (q *Queries) GetWorkspaceAvgVolumeUtilization(ctx context.Context, workspaceID *uuid.UUID) (float64, error) {
@@ -227,6 +241,7 @@ const getWorkspacePlanStatusDistribution = `-- name: GetWorkspacePlanStatusDistr
 SELECT status, COUNT(*) as count 
 FROM load_plans 
 WHERE workspace_id = $1
+AND parent_plan_id IS NULL
 GROUP BY status
 `
 
@@ -11,17 +11,37 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type CalculationJob struct {
+	JobID           uuid.UUID  `json:"job_id"`
+	PlanID          uuid.UUID  `json:"plan_id"`
+	Status          string     `json:"status"`
+	Options         []byte     `json:"options"`
+	ResultID        *uuid.UUID `json:"result_id"`
+	ErrorMessage    *string    `json:"error_message"`
+	RequestedByID   uuid.UUID  `json:"requested_by_id"`
+	RequestedByRole string     `json:"requested_by_role"`
+	WorkspaceID     *uuid.UUID `json:"workspace_id"`
+	CreatedAt       time.Time  `json:"created_at"`
+	StartedAt       *time.Time `json:"started_at"`
+	FinishedAt      *time.Time `json:"finished_at"`
+}
+
 type Container struct {
-	ContainerID   uuid.UUID        `json:"container_id"`
-	Name          string           `json:"name"`
-	InnerLengthMm pgtype.Numeric   `json:"inner_length_mm"`
-	InnerWidthMm  pgtype.Numeric   `json:"inner_width_mm"`
-	InnerHeightMm pgtype.Numeric   `json:"inner_height_mm"`
-	MaxWeightKg   pgtype.Numeric   `json:"max_weight_kg"`
-	Description   *string          `json:"description"`
-	CreatedAt     pgtype.Timestamp `json:"created_at"`
-	UpdatedAt     pgtype.Timestamp `json:"updated_at"`
-	WorkspaceID   *uuid.UUID       `json:"workspace_id"`
+	ContainerID             uuid.UUID        `json:"container_id"`
+	Name                    string           `json:"name"`
+	InnerLengthMm           pgtype.Numeric   `json:"inner_length_mm"`
+	InnerWidthMm            pgtype.Numeric   `json:"inner_width_mm"`
+	InnerHeightMm           pgtype.Numeric   `json:"inner_height_mm"`
+	MaxWeightKg             pgtype.Numeric   `json:"max_weight_kg"`
+	Description             *string          `json:"description"`
+	CreatedAt               pgtype.Timestamp `json:"created_at"`
+	UpdatedAt               pgtype.Timestamp `json:"updated_at"`
+	WorkspaceID             *uuid.UUID       `json:"workspace_id"`
+	LashingPointsPerSide    int32            `json:"lashing_points_per_side"`
+	LashingPointCapacityDan pgtype.Numeric   `json:"lashing_point_capacity_dan"`
+	Compartments            []byte           `json:"compartments"`
+	FloorLoadKgM2           pgtype.Numeric   `json:"floor_load_kg_m2"`
+	LineLoadKgM             pgtype.Numeric   `json:"line_load_kg_m"`
 }
 
 type Invite struct {
@@ -37,32 +57,96 @@ type Invite struct {
 	CreatedAt       *time.Time `json:"created_at"`
 }
 
+type HandlingUnit struct {
+	Sscc        string    `json:"sscc"`
+	WorkspaceID uuid.UUID `json:"workspace_id"`
+	PlanID      uuid.UUID `json:"plan_id"`
+	ResultID    uuid.UUID `json:"result_id"`
+	PlacementID uuid.UUID `json:"placement_id"`
+	StepNumber  int32     `json:"step_number"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type LoadingScan struct {
+	ScanID        uuid.UUID  `json:"scan_id"`
+	SessionID     uuid.UUID  `json:"session_id"`
+	Barcode       string     `json:"barcode"`
+	Status        string     `json:"status"`
+	StepNumber    *int32     `json:"step_number"`
+	ExpectedStep  *int32     `json:"expected_step"`
+	OperatorID    uuid.UUID  `json:"operator_id"`
+	ScannedAt     time.Time  `json:"scanned_at"`
+	ClientEventID *uuid.UUID `json:"client_event_id"`
+}
+
+type LoadingSession struct {
+	SessionID     uuid.UUID  `json:"session_id"`
+	PlanID        uuid.UUID  `json:"plan_id"`
+	ResultID      *uuid.UUID `json:"result_id"`
+	Status        string     `json:"status"`
+	TotalSteps    int32      `json:"total_steps"`
+	StartedByID   uuid.UUID  `json:"started_by_id"`
+	StartedAt     time.Time  `json:"started_at"`
+	PausedAt      *time.Time `json:"paused_at"`
+	CompletedByID *uuid.UUID `json:"completed_by_id"`
+	CompletedAt   *time.Time `json:"completed_at"`
+}
+
 type LoadItem struct {
-	ItemID        uuid.UUID      `json:"item_id"`
-	PlanID        *uuid.UUID     `json:"plan_id"`
-	ItemLabel     *string        `json:"item_label"`
-	LengthMm      pgtype.Numeric `json:"length_mm"`
-	WidthMm       pgtype.Numeric `json:"width_mm"`
-	HeightMm      pgtype.Numeric `json:"height_mm"`
-	WeightKg      pgtype.Numeric `json:"weight_kg"`
-	Quantity      int32          `json:"quantity"`
-	AllowRotation *bool          `json:"allow_rotation"`
-	ColorHex      *string        `json:"color_hex"`
+	ItemID              uuid.UUID      `json:"item_id"`
+	PlanID              *uuid.UUID     `json:"plan_id"`
+	ItemLabel           *string        `json:"item_label"`
+	LengthMm            pgtype.Numeric `json:"length_mm"`
+	WidthMm             pgtype.Numeric `json:"width_mm"`
+	HeightMm            pgtype.Numeric `json:"height_mm"`
+	WeightKg            pgtype.Numeric `json:"weight_kg"`
+	Quantity            int32          `json:"quantity"`
+	AllowRotation       *bool          `json:"allow_rotation"`
+	ColorHex            *string        `json:"color_hex"`
+	PaddingMm           pgtype.Numeric `json:"padding_mm"`
+	Priority            int32          `json:"priority"`
+	MustShip            bool           `json:"must_ship"`
+	FrictionCoefficient pgtype.Numeric `json:"friction_coefficient"`
+	TemperatureClass    *string        `json:"temperature_class"`
+	Gtin                *string        `json:"gtin"`
+	ProductID           *uuid.UUID     `json:"product_id"`
+	ProductSku          *string        `json:"product_sku"`
+	ProductOverridden   bool           `json:"product_overridden"`
+	Fragile             bool           `json:"fragile"`
+	ThisSideUp          bool           `json:"this_side_up"`
+	Stackable           bool           `json:"stackable"`
+	MaxStackLoadKg      pgtype.Numeric `json:"max_stack_load_kg"`
+	HazmatClass         *string        `json:"hazmat_class"`
+	PackagingType       *string        `json:"packaging_type"`
+	Unit                *string        `json:"unit"`
+	EachesPerUnit       *int32         `json:"eaches_per_unit"`
+	OrderedEaches       *int32         `json:"ordered_eaches"`
 }
 
 type LoadPlan struct {
-	PlanID        uuid.UUID        `json:"plan_id"`
-	PlanCode      string           `json:"plan_code"`
-	Status        *string          `json:"status"`
-	ContLabel     *string          `json:"cont_label"`
-	LengthMm      pgtype.Numeric   `json:"length_mm"`
-	WidthMm       pgtype.Numeric   `json:"width_mm"`
-	HeightMm      pgtype.Numeric   `json:"height_mm"`
-	MaxWeightKg   pgtype.Numeric   `json:"max_weight_kg"`
-	CreatedAt     pgtype.Timestamp `json:"created_at"`
-	CreatedByType string           `json:"created_by_type"`
-	CreatedByID   uuid.UUID        `json:"created_by_id"`
-	WorkspaceID   *uuid.UUID       `json:"workspace_id"`
+	PlanID                  uuid.UUID        `json:"plan_id"`
+	PlanCode                string           `json:"plan_code"`
+	Status                  *string          `json:"status"`
+	ContLabel               *string          `json:"cont_label"`
+	LengthMm                pgtype.Numeric   `json:"length_mm"`
+	WidthMm                 pgtype.Numeric   `json:"width_mm"`
+	HeightMm                pgtype.Numeric   `json:"height_mm"`
+	MaxWeightKg             pgtype.Numeric   `json:"max_weight_kg"`
+	CreatedAt               pgtype.Timestamp `json:"created_at"`
+	CreatedByType           string           `json:"created_by_type"`
+	CreatedByID             uuid.UUID        `json:"created_by_id"`
+	WorkspaceID             *uuid.UUID       `json:"workspace_id"`
+	ParentPlanID            *uuid.UUID       `json:"parent_plan_id"`
+	ScenarioName            *string          `json:"scenario_name"`
+	WallClearanceMm         pgtype.Numeric   `json:"wall_clearance_mm"`
+	ItemGapMm               pgtype.Numeric   `json:"item_gap_mm"`
+	OverflowOfPlanID        *uuid.UUID       `json:"overflow_of_plan_id"`
+	ShipmentGroupID         *uuid.UUID       `json:"shipment_group_id"`
+	LashingPointsPerSide    int32            `json:"lashing_points_per_side"`
+	LashingPointCapacityDan pgtype.Numeric   `json:"lashing_point_capacity_dan"`
+	Compartments            []byte           `json:"compartments"`
+	FloorLoadKgM2           pgtype.Numeric   `json:"floor_load_kg_m2"`
+	LineLoadKgM             pgtype.Numeric   `json:"line_load_kg_m"`
 }
 
 type Member struct {
@@ -74,6 +158,15 @@ type Member struct {
 	UpdatedAt   *time.Time `json:"updated_at"`
 }
 
+type PackingResultCache struct {
+	CacheKey  string     `json:"cache_key"`
+	Backend   string     `json:"backend"`
+	Result    []byte     `json:"result"`
+	HitCount  int32      `json:"hit_count"`
+	CreatedAt time.Time  `json:"created_at"`
+	LastHitAt *time.Time `json:"last_hit_at"`
+}
+
 type Permission struct {
 	PermissionID uuid.UUID  `json:"permission_id"`
 	Name         string     `json:"name"`
@@ -99,6 +192,21 @@ type PlanResult struct {
 	VolumeUtilizationPct pgtype.Numeric   `json:"volume_utilization_pct"`
 	IsFeasible           *bool            `json:"is_feasible"`
 	CreatedAt            pgtype.Timestamp `json:"created_at"`
+	Version              int32            `json:"version"`
+	IsActive             bool             `json:"is_active"`
+	Algorithm            *string          `json:"algorithm"`
+	Inputs               []byte           `json:"inputs"`
+}
+
+type PlanStatusHistory struct {
+	HistoryID     uuid.UUID  `json:"history_id"`
+	PlanID        uuid.UUID  `json:"plan_id"`
+	FromStatus    *string    `json:"from_status"`
+	ToStatus      string     `json:"to_status"`
+	Reason        *string    `json:"reason"`
+	ChangedByType string     `json:"changed_by_type"`
+	ChangedByID   *uuid.UUID `json:"changed_by_id"`
+	ChangedAt     time.Time  `json:"changed_at"`
 }
 
 type PlatformMember struct {
@@ -108,17 +216,28 @@ type PlatformMember struct {
 }
 
 type Product struct {
-	ProductID   uuid.UUID        `json:"product_id"`
-	Name        string           `json:"name"`
-	LengthMm    pgtype.Numeric   `json:"length_mm"`
-	WidthMm     pgtype.Numeric   `json:"width_mm"`
-	HeightMm    pgtype.Numeric   `json:"height_mm"`
-	WeightKg    pgtype.Numeric   `json:"weight_kg"`
-	ColorHex    *string          `json:"color_hex"`
-	CreatedAt   pgtype.Timestamp `json:"created_at"`
-	UpdatedAt   pgtype.Timestamp `json:"updated_at"`
-	WorkspaceID *uuid.UUID       `json:"workspace_id"`
-	Sku         *string          `json:"sku"`
+	ProductID           uuid.UUID        `json:"product_id"`
+	Name                string           `json:"name"`
+	LengthMm            pgtype.Numeric   `json:"length_mm"`
+	WidthMm             pgtype.Numeric   `json:"width_mm"`
+	HeightMm            pgtype.Numeric   `json:"height_mm"`
+	WeightKg            pgtype.Numeric   `json:"weight_kg"`
+	ColorHex            *string          `json:"color_hex"`
+	CreatedAt           pgtype.Timestamp `json:"created_at"`
+	UpdatedAt           pgtype.Timestamp `json:"updated_at"`
+	WorkspaceID         *uuid.UUID       `json:"workspace_id"`
+	Sku                 *string          `json:"sku"`
+	FrictionCoefficient pgtype.Numeric   `json:"friction_coefficient"`
+	TemperatureClass    *string          `json:"temperature_class"`
+	Gtin                *string          `json:"gtin"`
+	Fragile             bool             `json:"fragile"`
+	ThisSideUp          bool             `json:"this_side_up"`
+	Stackable           bool             `json:"stackable"`
+	MaxStackLoadKg      pgtype.Numeric   `json:"max_stack_load_kg"`
+	HazmatClass         *string          `json:"hazmat_class"`
+	PackagingType       *string          `json:"packaging_type"`
+	Packaging           []byte           `json:"packaging"`
+	ShippingUnit        string           `json:"shipping_unit"`
 }
 
 type RefreshToken struct {
@@ -153,6 +272,12 @@ type User struct {
 	UpdatedAt    *time.Time `json:"updated_at"`
 }
 
+type UserPreference struct {
+	UserID    uuid.UUID `json:"user_id"`
+	Units     string    `json:"units"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
 type UserProfile struct {
 	ProfileID   uuid.UUID   `json:"profile_id"`
 	UserID      uuid.UUID   `json:"user_id"`
@@ -173,3 +298,11 @@ type Workspace struct {
 	CreatedAt   *time.Time `json:"created_at"`
 	UpdatedAt   *time.Time `json:"updated_at"`
 }
+
+type WorkspaceGs1 struct {
+	WorkspaceID    uuid.UUID `json:"workspace_id"`
+	CompanyPrefix  string    `json:"company_prefix"`
+	ExtensionDigit int16     `json:"extension_digit"`
+	LastSerial     int64     `json:"last_serial"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
@@ -12,6 +12,15 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const activatePlanResult = `-- name: ActivatePlanResult :exec
+UPDATE plan_results SET is_active = TRUE WHERE result_id = $1
+`
+
+func (q *Queries) ActivatePlanResult(ctx context.Context, resultID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, activatePlanResult, resultID)
+	return err
+}
+
 const addLoadItem = `-- name: AddLoadItem :one
 INSERT INTO load_items (
     plan_id,
@@ -20,23 +29,59 @@ INSERT INTO load_items (
     weight_kg,
     quantity,
     allow_rotation,
-    color_hex
+    color_hex,
+    padding_mm,
+    priority,
+    must_ship,
+    friction_coefficient,
+    temperature_class,
+    gtin,
+    product_id,
+    product_sku,
+    product_overridden,
+    fragile,
+    this_side_up,
+    stackable,
+    max_stack_load_kg,
+    hazmat_class,
+    packaging_type,
+    unit,
+    eaches_per_unit,
+    ordered_eaches
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27
 )
-RETURNING item_id, plan_id, item_label, length_mm, width_mm, height_mm, weight_kg, quantity, allow_rotation, color_hex
+RETURNING item_id, plan_id, item_label, length_mm, width_mm, height_mm, weight_kg, quantity, allow_rotation, color_hex, padding_mm, priority, must_ship, friction_coefficient, temperature_class, gtin, product_id, product_sku, product_overridden, fragile, this_side_up, stackable, max_stack_load_kg, hazmat_class, packaging_type, unit, eaches_per_unit, ordered_eaches
 `
 
 type AddLoadItemParams struct {
-	PlanID        *uuid.UUID     `json:"plan_id"`
-	ItemLabel     *string        `json:"item_label"`
-	LengthMm      pgtype.Numeric `json:"length_mm"`
-	WidthMm       pgtype.Numeric `json:"width_mm"`
-	HeightMm      pgtype.Numeric `json:"height_mm"`
-	WeightKg      pgtype.Numeric `json:"weight_kg"`
-	Quantity      int32          `json:"quantity"`
-	AllowRotation *bool          `json:"allow_rotation"`
-	ColorHex      *string        `json:"color_hex"`
+	PlanID              *uuid.UUID     `json:"plan_id"`
+	ItemLabel           *string        `json:"item_label"`
+	LengthMm            pgtype.Numeric `json:"length_mm"`
+	WidthMm             pgtype.Numeric `json:"width_mm"`
+	HeightMm            pgtype.Numeric `json:"height_mm"`
+	WeightKg            pgtype.Numeric `json:"weight_kg"`
+	Quantity            int32          `json:"quantity"`
+	AllowRotation       *bool          `json:"allow_rotation"`
+	ColorHex            *string        `json:"color_hex"`
+	PaddingMm           pgtype.Numeric `json:"padding_mm"`
+	Priority            int32          `json:"priority"`
+	MustShip            bool           `json:"must_ship"`
+	FrictionCoefficient pgtype.Numeric `json:"friction_coefficient"`
+	TemperatureClass    *string        `json:"temperature_class"`
+	Gtin                *string        `json:"gtin"`
+	ProductID           *uuid.UUID     `json:"product_id"`
+	ProductSku          *string        `json:"product_sku"`
+	ProductOverridden   bool           `json:"product_overridden"`
+	Fragile             bool           `json:"fragile"`
+	ThisSideUp          bool           `json:"this_side_up"`
+	Stackable           bool           `json:"stackable"`
+	MaxStackLoadKg      pgtype.Numeric `json:"max_stack_load_kg"`
+	HazmatClass         *string        `json:"hazmat_class"`
+	PackagingType       *string        `json:"packaging_type"`
+	Unit                *string        `json:"unit"`
+	EachesPerUnit       *int32         `json:"eaches_per_unit"`
+	OrderedEaches       *int32         `json:"ordered_eaches"`
 }
 
 func (q *Queries) AddLoadItem(ctx context.Context, arg AddLoadItemParams) (LoadItem, error) {
@@ -50,6 +95,24 @@ func (q *Queries) AddLoadItem(ctx context.Context, arg AddLoadItemParams) (LoadI
 		arg.Quantity,
 		arg.AllowRotation,
 		arg.ColorHex,
+		arg.PaddingMm,
+		arg.Priority,
+		arg.MustShip,
+		arg.FrictionCoefficient,
+		arg.TemperatureClass,
+		arg.Gtin,
+		arg.ProductID,
+		arg.ProductSku,
+		arg.ProductOverridden,
+		arg.Fragile,
+		arg.ThisSideUp,
+		arg.Stackable,
+		arg.MaxStackLoadKg,
+		arg.HazmatClass,
+		arg.PackagingType,
+		arg.Unit,
+		arg.EachesPerUnit,
+		arg.OrderedEaches,
 	)
 	var i LoadItem
 	err := row.Scan(
@@ -63,6 +126,24 @@ func (q *Queries) AddLoadItem(ctx context.Context, arg AddLoadItemParams) (LoadI
 		&i.Quantity,
 		&i.AllowRotation,
 		&i.ColorHex,
+		&i.PaddingMm,
+		&i.Priority,
+		&i.MustShip,
+		&i.FrictionCoefficient,
+		&i.TemperatureClass,
+		&i.Gtin,
+		&i.ProductID,
+		&i.ProductSku,
+		&i.ProductOverridden,
+		&i.Fragile,
+		&i.ThisSideUp,
+		&i.Stackable,
+		&i.MaxStackLoadKg,
+		&i.HazmatClass,
+		&i.PackagingType,
+		&i.Unit,
+		&i.EachesPerUnit,
+		&i.OrderedEaches,
 	)
 	return i, err
 }
@@ -78,24 +159,38 @@ INSERT INTO load_plans (
     height_mm,
     max_weight_kg,
     created_by_type,
-    created_by_id
+    created_by_id,
+    wall_clearance_mm,
+    item_gap_mm,
+    lashing_points_per_side,
+    lashing_point_capacity_dan,
+    compartments,
+    floor_load_kg_m2,
+    line_load_kg_m
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17
 )
-RETURNING plan_id, plan_code, status, cont_label, length_mm, width_mm, height_mm, max_weight_kg, created_at, created_by_type, created_by_id, workspace_id
+RETURNING plan_id, plan_code, status, cont_label, length_mm, width_mm, height_mm, max_weight_kg, created_at, created_by_type, created_by_id, workspace_id, parent_plan_id, scenario_name, wall_clearance_mm, item_gap_mm, overflow_of_plan_id, shipment_group_id, lashing_points_per_side, lashing_point_capacity_dan, compartments, floor_load_kg_m2, line_load_kg_m
 `
 
 type CreateLoadPlanParams struct {
-	WorkspaceID   *uuid.UUID     `json:"workspace_id"`
-	PlanCode      string         `json:"plan_code"`
-	Status        *string        `json:"status"`
-	ContLabel     *string        `json:"cont_label"`
-	LengthMm      pgtype.Numeric `json:"length_mm"`
-	WidthMm       pgtype.Numeric `json:"width_mm"`
-	HeightMm      pgtype.Numeric `json:"height_mm"`
-	MaxWeightKg   pgtype.Numeric `json:"max_weight_kg"`
-	CreatedByType string         `json:"created_by_type"`
-	CreatedByID   uuid.UUID      `json:"created_by_id"`
+	WorkspaceID             *uuid.UUID     `json:"workspace_id"`
+	PlanCode                string         `json:"plan_code"`
+	Status                  *string        `json:"status"`
+	ContLabel               *string        `json:"cont_label"`
+	LengthMm                pgtype.Numeric `json:"length_mm"`
+	WidthMm                 pgtype.Numeric `json:"width_mm"`
+	HeightMm                pgtype.Numeric `json:"height_mm"`
+	MaxWeightKg             pgtype.Numeric `json:"max_weight_kg"`
+	CreatedByType           string         `json:"created_by_type"`
+	CreatedByID             uuid.UUID      `json:"created_by_id"`
+	WallClearanceMm         pgtype.Numeric `json:"wall_clearance_mm"`
+	ItemGapMm               pgtype.Numeric `json:"item_gap_mm"`
+	LashingPointsPerSide    int32          `json:"lashing_points_per_side"`
+	LashingPointCapacityDan pgtype.Numeric `json:"lashing_point_capacity_dan"`
+	Compartments            []byte         `json:"compartments"`
+	FloorLoadKgM2           pgtype.Numeric `json:"floor_load_kg_m2"`
+	LineLoadKgM             pgtype.Numeric `json:"line_load_kg_m"`
 }
 
 func (q *Queries) CreateLoadPlan(ctx context.Context, arg CreateLoadPlanParams) (LoadPlan, error) {
@@ -110,6 +205,13 @@ func (q *Queries) CreateLoadPlan(ctx context.Context, arg CreateLoadPlanParams)
 		arg.MaxWeightKg,
 		arg.CreatedByType,
 		arg.CreatedByID,
+		arg.WallClearanceMm,
+		arg.ItemGapMm,
+		arg.LashingPointsPerSide,
+		arg.LashingPointCapacityDan,
+		arg.Compartments,
+		arg.FloorLoadKgM2,
+		arg.LineLoadKgM,
 	)
 	var i LoadPlan
 	err := row.Scan(
@@ -125,6 +227,17 @@ func (q *Queries) CreateLoadPlan(ctx context.Context, arg CreateLoadPlanParams)
 		&i.CreatedByType,
 		&i.CreatedByID,
 		&i.WorkspaceID,
+		&i.ParentPlanID,
+		&i.ScenarioName,
+		&i.WallClearanceMm,
+		&i.ItemGapMm,
+		&i.OverflowOfPlanID,
+		&i.ShipmentGroupID,
+		&i.LashingPointsPerSide,
+		&i.LashingPointCapacityDan,
+		&i.Compartments,
+		&i.FloorLoadKgM2,
+		&i.LineLoadKgM,
 	)
 	return i, err
 }
@@ -139,16 +252,121 @@ type CreatePlanPlacementParams struct {
 	StepNumber   int32          `json:"step_number"`
 }
 
+const createOverflowPlan = `-- name: CreateOverflowPlan :one
+INSERT INTO load_plans (
+    overflow_of_plan_id,
+    shipment_group_id,
+    workspace_id,
+    plan_code,
+    status,
+    cont_label,
+    length_mm,
+    width_mm,
+    height_mm,
+    max_weight_kg,
+    created_by_type,
+    created_by_id,
+    wall_clearance_mm,
+    item_gap_mm,
+    lashing_points_per_side,
+    lashing_point_capacity_dan,
+    compartments,
+    floor_load_kg_m2,
+    line_load_kg_m
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19
+)
+RETURNING plan_id, plan_code, status, cont_label, length_mm, width_mm, height_mm, max_weight_kg, created_at, created_by_type, created_by_id, workspace_id, parent_plan_id, scenario_name, wall_clearance_mm, item_gap_mm, overflow_of_plan_id, shipment_group_id, lashing_points_per_side, lashing_point_capacity_dan, compartments, floor_load_kg_m2, line_load_kg_m
+`
+
+type CreateOverflowPlanParams struct {
+	OverflowOfPlanID        *uuid.UUID     `json:"overflow_of_plan_id"`
+	ShipmentGroupID         *uuid.UUID     `json:"shipment_group_id"`
+	WorkspaceID             *uuid.UUID     `json:"workspace_id"`
+	PlanCode                string         `json:"plan_code"`
+	Status                  *string        `json:"status"`
+	ContLabel               *string        `json:"cont_label"`
+	LengthMm                pgtype.Numeric `json:"length_mm"`
+	WidthMm                 pgtype.Numeric `json:"width_mm"`
+	HeightMm                pgtype.Numeric `json:"height_mm"`
+	MaxWeightKg             pgtype.Numeric `json:"max_weight_kg"`
+	CreatedByType           string         `json:"created_by_type"`
+	CreatedByID             uuid.UUID      `json:"created_by_id"`
+	WallClearanceMm         pgtype.Numeric `json:"wall_clearance_mm"`
+	ItemGapMm               pgtype.Numeric `json:"item_gap_mm"`
+	LashingPointsPerSide    int32          `json:"lashing_points_per_side"`
+	LashingPointCapacityDan pgtype.Numeric `json:"lashing_point_capacity_dan"`
+	Compartments            []byte         `json:"compartments"`
+	FloorLoadKgM2           pgtype.Numeric `json:"floor_load_kg_m2"`
+	LineLoadKgM             pgtype.Numeric `json:"line_load_kg_m"`
+}
+
+func (q *Queries) CreateOverflowPlan(ctx context.Context, arg CreateOverflowPlanParams) (LoadPlan, error) {
+	row := q.db.QueryRow(ctx, createOverflowPlan,
+		arg.OverflowOfPlanID,
+		arg.ShipmentGroupID,
+		arg.WorkspaceID,
+		arg.PlanCode,
+		arg.Status,
+		arg.ContLabel,
+		arg.LengthMm,
+		arg.WidthMm,
+		arg.HeightMm,
+		arg.MaxWeightKg,
+		arg.CreatedByType,
+		arg.CreatedByID,
+		arg.WallClearanceMm,
+		arg.ItemGapMm,
+		arg.LashingPointsPerSide,
+		arg.LashingPointCapacityDan,
+		arg.Compartments,
+		arg.FloorLoadKgM2,
+		arg.LineLoadKgM,
+	)
+	var i LoadPlan
+	err := row.Scan(
+		&i.PlanID,
+		&i.PlanCode,
+		&i.Status,
+		&i.ContLabel,
+		&i.LengthMm,
+		&i.WidthMm,
+		&i.HeightMm,
+		&i.MaxWeightKg,
+		&i.CreatedAt,
+		&i.CreatedByType,
+		&i.CreatedByID,
+		&i.WorkspaceID,
+		&i.ParentPlanID,
+		&i.ScenarioName,
+		&i.WallClearanceMm,
+		&i.ItemGapMm,
+		&i.OverflowOfPlanID,
+		&i.ShipmentGroupID,
+		&i.LashingPointsPerSide,
+		&i.LashingPointCapacityDan,
+		&i.Compartments,
+		&i.FloorLoadKgM2,
+		&i.LineLoadKgM,
+	)
+	return i, err
+}
+
 const createPlanResult = `-- name: CreatePlanResult :one
 INSERT INTO plan_results (
     plan_id,
     total_loaded_weight_kg,
     volume_utilization_pct,
-    is_feasible
+    is_feasible,
+    version,
+    algorithm,
+    inputs
 ) VALUES (
-    $1, $2, $3, $4
+    $1, $2, $3, $4,
+    (SELECT COALESCE(MAX(version), 0) + 1 FROM plan_results WHERE plan_id = $1),
+    $5, $6
 )
-RETURNING result_id, plan_id, total_loaded_weight_kg, volume_utilization_pct, is_feasible, created_at
+RETURNING result_id, plan_id, total_loaded_weight_kg, volume_utilization_pct, is_feasible, created_at, version, is_active, algorithm, inputs
 `
 
 type CreatePlanResultParams struct {
@@ -156,6 +374,8 @@ type CreatePlanResultParams struct {
 	TotalLoadedWeightKg  pgtype.Numeric `json:"total_loaded_weight_kg"`
 	VolumeUtilizationPct pgtype.Numeric `json:"volume_utilization_pct"`
 	IsFeasible           *bool          `json:"is_feasible"`
+	Algorithm            *string        `json:"algorithm"`
+	Inputs               []byte         `json:"inputs"`
 }
 
 func (q *Queries) CreatePlanResult(ctx context.Context, arg CreatePlanResultParams) (PlanResult, error) {
@@ -164,6 +384,8 @@ func (q *Queries) CreatePlanResult(ctx context.Context, arg CreatePlanResultPara
 		arg.TotalLoadedWeightKg,
 		arg.VolumeUtilizationPct,
 		arg.IsFeasible,
+		arg.Algorithm,
+		arg.Inputs,
 	)
 	var i PlanResult
 	err := row.Scan(
@@ -173,10 +395,123 @@ func (q *Queries) CreatePlanResult(ctx context.Context, arg CreatePlanResultPara
 		&i.VolumeUtilizationPct,
 		&i.IsFeasible,
 		&i.CreatedAt,
+		&i.Version,
+		&i.IsActive,
+		&i.Algorithm,
+		&i.Inputs,
 	)
 	return i, err
 }
 
+const createScenarioPlan = `-- name: CreateScenarioPlan :one
+INSERT INTO load_plans (
+    parent_plan_id,
+    scenario_name,
+    workspace_id,
+    plan_code,
+    status,
+    cont_label,
+    length_mm,
+    width_mm,
+    height_mm,
+    max_weight_kg,
+    created_by_type,
+    created_by_id,
+    wall_clearance_mm,
+    item_gap_mm,
+    lashing_points_per_side,
+    lashing_point_capacity_dan,
+    compartments,
+    floor_load_kg_m2,
+    line_load_kg_m
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19
+)
+RETURNING plan_id, plan_code, status, cont_label, length_mm, width_mm, height_mm, max_weight_kg, created_at, created_by_type, created_by_id, workspace_id, parent_plan_id, scenario_name, wall_clearance_mm, item_gap_mm, overflow_of_plan_id, shipment_group_id, lashing_points_per_side, lashing_point_capacity_dan, compartments, floor_load_kg_m2, line_load_kg_m
+`
+
+type CreateScenarioPlanParams struct {
+	ParentPlanID            *uuid.UUID     `json:"parent_plan_id"`
+	ScenarioName            *string        `json:"scenario_name"`
+	WorkspaceID             *uuid.UUID     `json:"workspace_id"`
+	PlanCode                string         `json:"plan_code"`
+	Status                  *string        `json:"status"`
+	ContLabel               *string        `json:"cont_label"`
+	LengthMm                pgtype.Numeric `json:"length_mm"`
+	WidthMm                 pgtype.Numeric `json:"width_mm"`
+	HeightMm                pgtype.Numeric `json:"height_mm"`
+	MaxWeightKg             pgtype.Numeric `json:"max_weight_kg"`
+	CreatedByType           string         `json:"created_by_type"`
+	CreatedByID             uuid.UUID      `json:"created_by_id"`
+	WallClearanceMm         pgtype.Numeric `json:"wall_clearance_mm"`
+	ItemGapMm               pgtype.Numeric `json:"item_gap_mm"`
+	LashingPointsPerSide    int32          `json:"lashing_points_per_side"`
+	LashingPointCapacityDan pgtype.Numeric `json:"lashing_point_capacity_dan"`
+	Compartments            []byte         `json:"compartments"`
+	FloorLoadKgM2           pgtype.Numeric `json:"floor_load_kg_m2"`
+	LineLoadKgM             pgtype.Numeric `json:"line_load_kg_m"`
+}
+
+func (q *Queries) CreateScenarioPlan(ctx context.Context, arg CreateScenarioPlanParams) (LoadPlan, error) {
+	row := q.db.QueryRow(ctx, createScenarioPlan,
+		arg.ParentPlanID,
+		arg.ScenarioName,
+		arg.WorkspaceID,
+		arg.PlanCode,
+		arg.Status,
+		arg.ContLabel,
+		arg.LengthMm,
+		arg.WidthMm,
+		arg.HeightMm,
+		arg.MaxWeightKg,
+		arg.CreatedByType,
+		arg.CreatedByID,
+		arg.WallClearanceMm,
+		arg.ItemGapMm,
+		arg.LashingPointsPerSide,
+		arg.LashingPointCapacityDan,
+		arg.Compartments,
+		arg.FloorLoadKgM2,
+		arg.LineLoadKgM,
+	)
+	var i LoadPlan
+	err := row.Scan(
+		&i.PlanID,
+		&i.PlanCode,
+		&i.Status,
+		&i.ContLabel,
+		&i.LengthMm,
+		&i.WidthMm,
+		&i.HeightMm,
+		&i.MaxWeightKg,
+		&i.CreatedAt,
+		&i.CreatedByType,
+		&i.CreatedByID,
+		&i.WorkspaceID,
+		&i.ParentPlanID,
+		&i.ScenarioName,
+		&i.WallClearanceMm,
+		&i.ItemGapMm,
+		&i.OverflowOfPlanID,
+		&i.ShipmentGroupID,
+		&i.LashingPointsPerSide,
+		&i.LashingPointCapacityDan,
+		&i.Compartments,
+		&i.FloorLoadKgM2,
+		&i.LineLoadKgM,
+	)
+	return i, err
+}
+
+const deactivatePlanResults = `-- name: DeactivatePlanResults :exec
+UPDATE plan_results SET is_active = FALSE WHERE plan_id = $1 AND is_active
+`
+
+func (q *Queries) DeactivatePlanResults(ctx context.Context, planID *uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deactivatePlanResults, planID)
+	return err
+}
+
 const deleteLoadItem = `-- name: DeleteLoadItem :exec
 DELETE FROM load_items
 WHERE plan_id = $1 AND item_id = $2
@@ -208,17 +543,8 @@ func (q *Queries) DeleteLoadPlan(ctx context.Context, arg DeleteLoadPlanParams)
 	return err
 }
 
-const deletePlanResults = `-- name: DeletePlanResults :exec
-DELETE FROM plan_results WHERE plan_id = $1
-`
-
-func (q *Queries) DeletePlanResults(ctx context.Context, planID *uuid.UUID) error {
-	_, err := q.db.Exec(ctx, deletePlanResults, planID)
-	return err
-}
-
 const getLoadItem = `-- name: GetLoadItem :one
-SELECT item_id, plan_id, item_label, length_mm, width_mm, height_mm, weight_kg, quantity, allow_rotation, color_hex FROM load_items
+SELECT item_id, plan_id, item_label, length_mm, width_mm, height_mm, weight_kg, quantity, allow_rotation, color_hex, padding_mm, priority, must_ship, friction_coefficient, temperature_class, gtin, product_id, product_sku, product_overridden, fragile, this_side_up, stackable, max_stack_load_kg, hazmat_class, packaging_type, unit, eaches_per_unit, ordered_eaches FROM load_items
 WHERE plan_id = $1 AND item_id = $2
 `
 
@@ -241,12 +567,30 @@ func (q *Queries) GetLoadItem(ctx context.Context, arg GetLoadItemParams) (LoadI
 		&i.Quantity,
 		&i.AllowRotation,
 		&i.ColorHex,
+		&i.PaddingMm,
+		&i.Priority,
+		&i.MustShip,
+		&i.FrictionCoefficient,
+		&i.TemperatureClass,
+		&i.Gtin,
+		&i.ProductID,
+		&i.ProductSku,
+		&i.ProductOverridden,
+		&i.Fragile,
+		&i.ThisSideUp,
+		&i.Stackable,
+		&i.MaxStackLoadKg,
+		&i.HazmatClass,
+		&i.PackagingType,
+		&i.Unit,
+		&i.EachesPerUnit,
+		&i.OrderedEaches,
 	)
 	return i, err
 }
 
 const getLoadPlan = `-- name: GetLoadPlan :one
-SELECT plan_id, plan_code, status, cont_label, length_mm, width_mm, height_mm, max_weight_kg, created_at, created_by_type, created_by_id, workspace_id
+SELECT plan_id, plan_code, status, cont_label, length_mm, width_mm, height_mm, max_weight_kg, created_at, created_by_type, created_by_id, workspace_id, parent_plan_id, scenario_name, wall_clearance_mm, item_gap_mm, overflow_of_plan_id, shipment_group_id, lashing_points_per_side, lashing_point_capacity_dan, compartments, floor_load_kg_m2, line_load_kg_m
 FROM load_plans
 WHERE plan_id = $1
   AND workspace_id IS NOT DISTINCT FROM $2
@@ -273,12 +617,23 @@ func (q *Queries) GetLoadPlan(ctx context.Context, arg GetLoadPlanParams) (LoadP
 		&i.CreatedByType,
 		&i.CreatedByID,
 		&i.WorkspaceID,
+		&i.ParentPlanID,
+		&i.ScenarioName,
+		&i.WallClearanceMm,
+		&i.ItemGapMm,
+		&i.OverflowOfPlanID,
+		&i.ShipmentGroupID,
+		&i.LashingPointsPerSide,
+		&i.LashingPointCapacityDan,
+		&i.Compartments,
+		&i.FloorLoadKgM2,
+		&i.LineLoadKgM,
 	)
 	return i, err
 }
 
 const getLoadPlanAny = `-- name: GetLoadPlanAny :one
-SELECT plan_id, plan_code, status, cont_label, length_mm, width_mm, height_mm, max_weight_kg, created_at, created_by_type, created_by_id, workspace_id
+SELECT plan_id, plan_code, status, cont_label, length_mm, width_mm, height_mm, max_weight_kg, created_at, created_by_type, created_by_id, workspace_id, parent_plan_id, scenario_name, wall_clearance_mm, item_gap_mm, overflow_of_plan_id, shipment_group_id, lashing_points_per_side, lashing_point_capacity_dan, compartments, floor_load_kg_m2, line_load_kg_m
 FROM load_plans
 WHERE plan_id = $1
 `
@@ -299,12 +654,23 @@ func (q *Queries) GetLoadPlanAny(ctx context.Context, planID uuid.UUID) (LoadPla
 		&i.CreatedByType,
 		&i.CreatedByID,
 		&i.WorkspaceID,
+		&i.ParentPlanID,
+		&i.ScenarioName,
+		&i.WallClearanceMm,
+		&i.ItemGapMm,
+		&i.OverflowOfPlanID,
+		&i.ShipmentGroupID,
+		&i.LashingPointsPerSide,
+		&i.LashingPointCapacityDan,
+		&i.Compartments,
+		&i.FloorLoadKgM2,
+		&i.LineLoadKgM,
 	)
 	return i, err
 }
 
 const getLoadPlanForGuest = `-- name: GetLoadPlanForGuest :one
-SELECT plan_id, plan_code, status, cont_label, length_mm, width_mm, height_mm, max_weight_kg, created_at, created_by_type, created_by_id, workspace_id
+SELECT plan_id, plan_code, status, cont_label, length_mm, width_mm, height_mm, max_weight_kg, created_at, created_by_type, created_by_id, workspace_id, parent_plan_id, scenario_name, wall_clearance_mm, item_gap_mm, overflow_of_plan_id, shipment_group_id, lashing_points_per_side, lashing_point_capacity_dan, compartments, floor_load_kg_m2, line_load_kg_m
 FROM load_plans
 WHERE plan_id = $1
   AND created_by_type = 'guest'
@@ -332,12 +698,23 @@ func (q *Queries) GetLoadPlanForGuest(ctx context.Context, arg GetLoadPlanForGue
 		&i.CreatedByType,
 		&i.CreatedByID,
 		&i.WorkspaceID,
+		&i.ParentPlanID,
+		&i.ScenarioName,
+		&i.WallClearanceMm,
+		&i.ItemGapMm,
+		&i.OverflowOfPlanID,
+		&i.ShipmentGroupID,
+		&i.LashingPointsPerSide,
+		&i.LashingPointCapacityDan,
+		&i.Compartments,
+		&i.FloorLoadKgM2,
+		&i.LineLoadKgM,
 	)
 	return i, err
 }
 
 const getPlanResult = `-- name: GetPlanResult :one
-SELECT result_id, plan_id, total_loaded_weight_kg, volume_utilization_pct, is_feasible, created_at FROM plan_results WHERE plan_id = $1
+SELECT result_id, plan_id, total_loaded_weight_kg, volume_utilization_pct, is_feasible, created_at, version, is_active, algorithm, inputs FROM plan_results WHERE plan_id = $1 AND is_active
 `
 
 func (q *Queries) GetPlanResult(ctx context.Context, planID *uuid.UUID) (PlanResult, error) {
@@ -350,12 +727,43 @@ func (q *Queries) GetPlanResult(ctx context.Context, planID *uuid.UUID) (PlanRes
 		&i.VolumeUtilizationPct,
 		&i.IsFeasible,
 		&i.CreatedAt,
+		&i.Version,
+		&i.IsActive,
+		&i.Algorithm,
+		&i.Inputs,
+	)
+	return i, err
+}
+
+const getPlanResultVersion = `-- name: GetPlanResultVersion :one
+SELECT result_id, plan_id, total_loaded_weight_kg, volume_utilization_pct, is_feasible, created_at, version, is_active, algorithm, inputs FROM plan_results WHERE plan_id = $1 AND version = $2
+`
+
+type GetPlanResultVersionParams struct {
+	PlanID  *uuid.UUID `json:"plan_id"`
+	Version int32      `json:"version"`
+}
+
+func (q *Queries) GetPlanResultVersion(ctx context.Context, arg GetPlanResultVersionParams) (PlanResult, error) {
+	row := q.db.QueryRow(ctx, getPlanResultVersion, arg.PlanID, arg.Version)
+	var i PlanResult
+	err := row.Scan(
+		&i.ResultID,
+		&i.PlanID,
+		&i.TotalLoadedWeightKg,
+		&i.VolumeUtilizationPct,
+		&i.IsFeasible,
+		&i.CreatedAt,
+		&i.Version,
+		&i.IsActive,
+		&i.Algorithm,
+		&i.Inputs,
 	)
 	return i, err
 }
 
 const listLoadItems = `-- name: ListLoadItems :many
-SELECT item_id, plan_id, item_label, length_mm, width_mm, height_mm, weight_kg, quantity, allow_rotation, color_hex FROM load_items
+SELECT item_id, plan_id, item_label, length_mm, width_mm, height_mm, weight_kg, quantity, allow_rotation, color_hex, padding_mm, priority, must_ship, friction_coefficient, temperature_class, gtin, product_id, product_sku, product_overridden, fragile, this_side_up, stackable, max_stack_load_kg, hazmat_class, packaging_type, unit, eaches_per_unit, ordered_eaches FROM load_items
 WHERE plan_id = $1
 `
 
@@ -379,6 +787,24 @@ func (q *Queries) ListLoadItems(ctx context.Context, planID *uuid.UUID) ([]LoadI
 			&i.Quantity,
 			&i.AllowRotation,
 			&i.ColorHex,
+			&i.PaddingMm,
+			&i.Priority,
+			&i.MustShip,
+			&i.FrictionCoefficient,
+			&i.TemperatureClass,
+			&i.Gtin,
+			&i.ProductID,
+			&i.ProductSku,
+			&i.ProductOverridden,
+			&i.Fragile,
+			&i.ThisSideUp,
+			&i.Stackable,
+			&i.MaxStackLoadKg,
+			&i.HazmatClass,
+			&i.PackagingType,
+			&i.Unit,
+			&i.EachesPerUnit,
+			&i.OrderedEaches,
 		); err != nil {
 			return nil, err
 		}
@@ -391,9 +817,10 @@ func (q *Queries) ListLoadItems(ctx context.Context, planID *uuid.UUID) ([]LoadI
 }
 
 const listLoadPlans = `-- name: ListLoadPlans :many
-SELECT plan_id, plan_code, status, cont_label, length_mm, width_mm, height_mm, max_weight_kg, created_at, created_by_type, created_by_id, workspace_id
+SELECT plan_id, plan_code, status, cont_label, length_mm, width_mm, height_mm, max_weight_kg, created_at, created_by_type, created_by_id, workspace_id, parent_plan_id, scenario_name, wall_clearance_mm, item_gap_mm, overflow_of_plan_id, shipment_group_id, lashing_points_per_side, lashing_point_capacity_dan, compartments, floor_load_kg_m2, line_load_kg_m
 FROM load_plans
 WHERE workspace_id IS NOT DISTINCT FROM $1
+  AND parent_plan_id IS NULL
 ORDER BY created_at DESC
 LIMIT $2 OFFSET $3
 `
@@ -426,6 +853,17 @@ func (q *Queries) ListLoadPlans(ctx context.Context, arg ListLoadPlansParams) ([
 			&i.CreatedByType,
 			&i.CreatedByID,
 			&i.WorkspaceID,
+			&i.ParentPlanID,
+			&i.ScenarioName,
+			&i.WallClearanceMm,
+			&i.ItemGapMm,
+			&i.OverflowOfPlanID,
+			&i.ShipmentGroupID,
+			&i.LashingPointsPerSide,
+			&i.LashingPointCapacityDan,
+			&i.Compartments,
+			&i.FloorLoadKgM2,
+			&i.LineLoadKgM,
 		); err != nil {
 			return nil, err
 		}
@@ -438,8 +876,9 @@ func (q *Queries) ListLoadPlans(ctx context.Context, arg ListLoadPlansParams) ([
 }
 
 const listLoadPlansAll = `-- name: ListLoadPlansAll :many
-SELECT plan_id, plan_code, status, cont_label, length_mm, width_mm, height_mm, max_weight_kg, created_at, created_by_type, created_by_id, workspace_id
+SELECT plan_id, plan_code, status, cont_label, length_mm, width_mm, height_mm, max_weight_kg, created_at, created_by_type, created_by_id, workspace_id, parent_plan_id, scenario_name, wall_clearance_mm, item_gap_mm, overflow_of_plan_id, shipment_group_id, lashing_points_per_side, lashing_point_capacity_dan, compartments, floor_load_kg_m2, line_load_kg_m
 FROM load_plans
+WHERE parent_plan_id IS NULL
 ORDER BY created_at DESC
 LIMIT $1 OFFSET $2
 `
@@ -471,6 +910,17 @@ func (q *Queries) ListLoadPlansAll(ctx context.Context, arg ListLoadPlansAllPara
 			&i.CreatedByType,
 			&i.CreatedByID,
 			&i.WorkspaceID,
+			&i.ParentPlanID,
+			&i.ScenarioName,
+			&i.WallClearanceMm,
+			&i.ItemGapMm,
+			&i.OverflowOfPlanID,
+			&i.ShipmentGroupID,
+			&i.LashingPointsPerSide,
+			&i.LashingPointCapacityDan,
+			&i.Compartments,
+			&i.FloorLoadKgM2,
+			&i.LineLoadKgM,
 		); err != nil {
 			return nil, err
 		}
@@ -483,10 +933,11 @@ func (q *Queries) ListLoadPlansAll(ctx context.Context, arg ListLoadPlansAllPara
 }
 
 const listLoadPlansForGuest = `-- name: ListLoadPlansForGuest :many
-SELECT plan_id, plan_code, status, cont_label, length_mm, width_mm, height_mm, max_weight_kg, created_at, created_by_type, created_by_id, workspace_id
+SELECT plan_id, plan_code, status, cont_label, length_mm, width_mm, height_mm, max_weight_kg, created_at, created_by_type, created_by_id, workspace_id, parent_plan_id, scenario_name, wall_clearance_mm, item_gap_mm, overflow_of_plan_id, shipment_group_id, lashing_points_per_side, lashing_point_capacity_dan, compartments, floor_load_kg_m2, line_load_kg_m
 FROM load_plans
 WHERE created_by_type = 'guest'
   AND created_by_id = $1
+  AND parent_plan_id IS NULL
 ORDER BY created_at DESC
 LIMIT $2 OFFSET $3
 `
@@ -519,6 +970,17 @@ func (q *Queries) ListLoadPlansForGuest(ctx context.Context, arg ListLoadPlansFo
 			&i.CreatedByType,
 			&i.CreatedByID,
 			&i.WorkspaceID,
+			&i.ParentPlanID,
+			&i.ScenarioName,
+			&i.WallClearanceMm,
+			&i.ItemGapMm,
+			&i.OverflowOfPlanID,
+			&i.ShipmentGroupID,
+			&i.LashingPointsPerSide,
+			&i.LashingPointCapacityDan,
+			&i.Compartments,
+			&i.FloorLoadKgM2,
+			&i.LineLoadKgM,
 		); err != nil {
 			return nil, err
 		}
@@ -563,6 +1025,173 @@ func (q *Queries) ListPlanPlacements(ctx context.Context, resultID *uuid.UUID) (
 	return items, nil
 }
 
+const listPlanResults = `-- name: ListPlanResults :many
+SELECT result_id, plan_id, total_loaded_weight_kg, volume_utilization_pct, is_feasible, created_at, version, is_active, algorithm, inputs FROM plan_results WHERE plan_id = $1 ORDER BY version DESC
+`
+
+func (q *Queries) ListPlanResults(ctx context.Context, planID *uuid.UUID) ([]PlanResult, error) {
+	rows, err := q.db.Query(ctx, listPlanResults, planID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PlanResult
+	for rows.Next() {
+		var i PlanResult
+		if err := rows.Scan(
+			&i.ResultID,
+			&i.PlanID,
+			&i.TotalLoadedWeightKg,
+			&i.VolumeUtilizationPct,
+			&i.IsFeasible,
+			&i.CreatedAt,
+			&i.Version,
+			&i.IsActive,
+			&i.Algorithm,
+			&i.Inputs,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPlanScenarios = `-- name: ListPlanScenarios :many
+SELECT plan_id, plan_code, status, cont_label, length_mm, width_mm, height_mm, max_weight_kg, created_at, created_by_type, created_by_id, workspace_id, parent_plan_id, scenario_name, wall_clearance_mm, item_gap_mm, overflow_of_plan_id, shipment_group_id, lashing_points_per_side, lashing_point_capacity_dan, compartments, floor_load_kg_m2, line_load_kg_m
+FROM load_plans
+WHERE parent_plan_id = $1
+ORDER BY created_at ASC
+`
+
+func (q *Queries) ListPlanScenarios(ctx context.Context, parentPlanID *uuid.UUID) ([]LoadPlan, error) {
+	rows, err := q.db.Query(ctx, listPlanScenarios, parentPlanID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LoadPlan
+	for rows.Next() {
+		var i LoadPlan
+		if err := rows.Scan(
+			&i.PlanID,
+			&i.PlanCode,
+			&i.Status,
+			&i.ContLabel,
+			&i.LengthMm,
+			&i.WidthMm,
+			&i.HeightMm,
+			&i.MaxWeightKg,
+			&i.CreatedAt,
+			&i.CreatedByType,
+			&i.CreatedByID,
+			&i.WorkspaceID,
+			&i.ParentPlanID,
+			&i.ScenarioName,
+			&i.WallClearanceMm,
+			&i.ItemGapMm,
+			&i.OverflowOfPlanID,
+			&i.ShipmentGroupID,
+			&i.LashingPointsPerSide,
+			&i.LashingPointCapacityDan,
+			&i.Compartments,
+			&i.FloorLoadKgM2,
+			&i.LineLoadKgM,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listShipmentGroupPlans = `-- name: ListShipmentGroupPlans :many
+SELECT plan_id, plan_code, status, cont_label, length_mm, width_mm, height_mm, max_weight_kg, created_at, created_by_type, created_by_id, workspace_id, parent_plan_id, scenario_name, wall_clearance_mm, item_gap_mm, overflow_of_plan_id, shipment_group_id, lashing_points_per_side, lashing_point_capacity_dan, compartments, floor_load_kg_m2, line_load_kg_m
+FROM load_plans
+WHERE shipment_group_id = $1
+ORDER BY created_at ASC
+`
+
+func (q *Queries) ListShipmentGroupPlans(ctx context.Context, shipmentGroupID *uuid.UUID) ([]LoadPlan, error) {
+	rows, err := q.db.Query(ctx, listShipmentGroupPlans, shipmentGroupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LoadPlan
+	for rows.Next() {
+		var i LoadPlan
+		if err := rows.Scan(
+			&i.PlanID,
+			&i.PlanCode,
+			&i.Status,
+			&i.ContLabel,
+			&i.LengthMm,
+			&i.WidthMm,
+			&i.HeightMm,
+			&i.MaxWeightKg,
+			&i.CreatedAt,
+			&i.CreatedByType,
+			&i.CreatedByID,
+			&i.WorkspaceID,
+			&i.ParentPlanID,
+			&i.ScenarioName,
+			&i.WallClearanceMm,
+			&i.ItemGapMm,
+			&i.OverflowOfPlanID,
+			&i.ShipmentGroupID,
+			&i.LashingPointsPerSide,
+			&i.LashingPointCapacityDan,
+			&i.Compartments,
+			&i.FloorLoadKgM2,
+			&i.LineLoadKgM,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockLoadPlan = `-- name: LockLoadPlan :one
+SELECT status FROM load_plans
+WHERE plan_id = $1
+FOR UPDATE
+`
+
+func (q *Queries) LockLoadPlan(ctx context.Context, planID uuid.UUID) (*string, error) {
+	row := q.db.QueryRow(ctx, lockLoadPlan, planID)
+	var status *string
+	err := row.Scan(&status)
+	return status, err
+}
+
+const setPlanShipmentGroup = `-- name: SetPlanShipmentGroup :exec
+UPDATE load_plans
+SET shipment_group_id = $2
+WHERE plan_id = $1
+  AND shipment_group_id IS NULL
+`
+
+type SetPlanShipmentGroupParams struct {
+	PlanID          uuid.UUID  `json:"plan_id"`
+	ShipmentGroupID *uuid.UUID `json:"shipment_group_id"`
+}
+
+func (q *Queries) SetPlanShipmentGroup(ctx context.Context, arg SetPlanShipmentGroupParams) error {
+	_, err := q.db.Exec(ctx, setPlanShipmentGroup, arg.PlanID, arg.ShipmentGroupID)
+	return err
+}
+
 const updateLoadItem = `-- name: UpdateLoadItem :exec
 UPDATE load_items
 SET
@@ -573,21 +1202,49 @@ SET
     weight_kg = $7,
     quantity = $8,
     allow_rotation = $9,
-    color_hex = $10
+    color_hex = $10,
+    padding_mm = $11,
+    priority = $12,
+    must_ship = $13,
+    friction_coefficient = $14,
+    temperature_class = $15,
+    gtin = $16,
+    product_overridden = $17,
+    fragile = $18,
+    this_side_up = $19,
+    stackable = $20,
+    max_stack_load_kg = $21,
+    hazmat_class = $22,
+    packaging_type = $23,
+    ordered_eaches = $24
 WHERE plan_id = $1 AND item_id = $2
 `
 
 type UpdateLoadItemParams struct {
-	PlanID        *uuid.UUID     `json:"plan_id"`
-	ItemID        uuid.UUID      `json:"item_id"`
-	ItemLabel     *string        `json:"item_label"`
-	LengthMm      pgtype.Numeric `json:"length_mm"`
-	WidthMm       pgtype.Numeric `json:"width_mm"`
-	HeightMm      pgtype.Numeric `json:"height_mm"`
-	WeightKg      pgtype.Numeric `json:"weight_kg"`
-	Quantity      int32          `json:"quantity"`
-	AllowRotation *bool          `json:"allow_rotation"`
-	ColorHex      *string        `json:"color_hex"`
+	PlanID              *uuid.UUID     `json:"plan_id"`
+	ItemID              uuid.UUID      `json:"item_id"`
+	ItemLabel           *string        `json:"item_label"`
+	LengthMm            pgtype.Numeric `json:"length_mm"`
+	WidthMm             pgtype.Numeric `json:"width_mm"`
+	HeightMm            pgtype.Numeric `json:"height_mm"`
+	WeightKg            pgtype.Numeric `json:"weight_kg"`
+	Quantity            int32          `json:"quantity"`
+	AllowRotation       *bool          `json:"allow_rotation"`
+	ColorHex            *string        `json:"color_hex"`
+	PaddingMm           pgtype.Numeric `json:"padding_mm"`
+	Priority            int32          `json:"priority"`
+	MustShip            bool           `json:"must_ship"`
+	FrictionCoefficient pgtype.Numeric `json:"friction_coefficient"`
+	TemperatureClass    *string        `json:"temperature_class"`
+	Gtin                *string        `json:"gtin"`
+	ProductOverridden   bool           `json:"product_overridden"`
+	Fragile             bool           `json:"fragile"`
+	ThisSideUp          bool           `json:"this_side_up"`
+	Stackable           bool           `json:"stackable"`
+	MaxStackLoadKg      pgtype.Numeric `json:"max_stack_load_kg"`
+	HazmatClass         *string        `json:"hazmat_class"`
+	PackagingType       *string        `json:"packaging_type"`
+	OrderedEaches       *int32         `json:"ordered_eaches"`
 }
 
 func (q *Queries) UpdateLoadItem(ctx context.Context, arg UpdateLoadItemParams) error {
@@ -602,6 +1259,20 @@ func (q *Queries) UpdateLoadItem(ctx context.Context, arg UpdateLoadItemParams)
 		arg.Quantity,
 		arg.AllowRotation,
 		arg.ColorHex,
+		arg.PaddingMm,
+		arg.Priority,
+		arg.MustShip,
+		arg.FrictionCoefficient,
+		arg.TemperatureClass,
+		arg.Gtin,
+		arg.ProductOverridden,
+		arg.Fragile,
+		arg.ThisSideUp,
+		arg.Stackable,
+		arg.MaxStackLoadKg,
+		arg.HazmatClass,
+		arg.PackagingType,
+		arg.OrderedEaches,
 	)
 	return err
 }
@@ -615,21 +1286,35 @@ SET
     width_mm = $6,
     height_mm = $7,
     max_weight_kg = $8,
-    status = $9
+    status = $9,
+    wall_clearance_mm = $10,
+    item_gap_mm = $11,
+    lashing_points_per_side = $12,
+    lashing_point_capacity_dan = $13,
+    compartments = $14,
+    floor_load_kg_m2 = $15,
+    line_load_kg_m = $16
 WHERE plan_id = $1
   AND workspace_id IS NOT DISTINCT FROM $2
 `
 
 type UpdateLoadPlanParams struct {
-	PlanID      uuid.UUID      `json:"plan_id"`
-	WorkspaceID *uuid.UUID     `json:"workspace_id"`
-	PlanCode    string         `json:"plan_code"`
-	ContLabel   *string        `json:"cont_label"`
-	LengthMm    pgtype.Numeric `json:"length_mm"`
-	WidthMm     pgtype.Numeric `json:"width_mm"`
-	HeightMm    pgtype.Numeric `json:"height_mm"`
-	MaxWeightKg pgtype.Numeric `json:"max_weight_kg"`
-	Status      *string        `json:"status"`
+	PlanID                  uuid.UUID      `json:"plan_id"`
+	WorkspaceID             *uuid.UUID     `json:"workspace_id"`
+	PlanCode                string         `json:"plan_code"`
+	ContLabel               *string        `json:"cont_label"`
+	LengthMm                pgtype.Numeric `json:"length_mm"`
+	WidthMm                 pgtype.Numeric `json:"width_mm"`
+	HeightMm                pgtype.Numeric `json:"height_mm"`
+	MaxWeightKg             pgtype.Numeric `json:"max_weight_kg"`
+	Status                  *string        `json:"status"`
+	WallClearanceMm         pgtype.Numeric `json:"wall_clearance_mm"`
+	ItemGapMm               pgtype.Numeric `json:"item_gap_mm"`
+	LashingPointsPerSide    int32          `json:"lashing_points_per_side"`
+	LashingPointCapacityDan pgtype.Numeric `json:"lashing_point_capacity_dan"`
+	Compartments            []byte         `json:"compartments"`
+	FloorLoadKgM2           pgtype.Numeric `json:"floor_load_kg_m2"`
+	LineLoadKgM             pgtype.Numeric `json:"line_load_kg_m"`
 }
 
 func (q *Queries) UpdateLoadPlan(ctx context.Context, arg UpdateLoadPlanParams) error {
@@ -643,6 +1328,13 @@ func (q *Queries) UpdateLoadPlan(ctx context.Context, arg UpdateLoadPlanParams)
 		arg.HeightMm,
 		arg.MaxWeightKg,
 		arg.Status,
+		arg.WallClearanceMm,
+		arg.ItemGapMm,
+		arg.LashingPointsPerSide,
+		arg.LashingPointCapacityDan,
+		arg.Compartments,
+		arg.FloorLoadKgM2,
+		arg.LineLoadKgM,
 	)
 	return err
 }
@@ -36,6 +36,7 @@ SELECT COUNT(*)
 FROM load_plans
 WHERE created_by_type = $1
   AND created_by_id = $2
+  AND parent_plan_id IS NULL
 `
 
 type CountPlansByCreatorParams struct {
@@ -21,22 +21,44 @@ INSERT INTO products (
     width_mm,
     height_mm,
     weight_kg,
-    color_hex
+    color_hex,
+    friction_coefficient,
+    temperature_class,
+    gtin,
+    fragile,
+    this_side_up,
+    stackable,
+    max_stack_load_kg,
+    hazmat_class,
+    packaging_type,
+    packaging,
+    shipping_unit
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19
 )
-RETURNING product_id, name, length_mm, width_mm, height_mm, weight_kg, color_hex, created_at, updated_at, workspace_id, sku
+RETURNING product_id, name, length_mm, width_mm, height_mm, weight_kg, color_hex, created_at, updated_at, workspace_id, sku, friction_coefficient, temperature_class, gtin, fragile, this_side_up, stackable, max_stack_load_kg, hazmat_class, packaging_type, packaging, shipping_unit
 `
 
 type CreateProductParams struct {
-	WorkspaceID *uuid.UUID     `json:"workspace_id"`
-	Name        string         `json:"name"`
-	Sku         *string        `json:"sku"`
-	LengthMm    pgtype.Numeric `json:"length_mm"`
-	WidthMm     pgtype.Numeric `json:"width_mm"`
-	HeightMm    pgtype.Numeric `json:"height_mm"`
-	WeightKg    pgtype.Numeric `json:"weight_kg"`
-	ColorHex    *string        `json:"color_hex"`
+	WorkspaceID         *uuid.UUID     `json:"workspace_id"`
+	Name                string         `json:"name"`
+	Sku                 *string        `json:"sku"`
+	LengthMm            pgtype.Numeric `json:"length_mm"`
+	WidthMm             pgtype.Numeric `json:"width_mm"`
+	HeightMm            pgtype.Numeric `json:"height_mm"`
+	WeightKg            pgtype.Numeric `json:"weight_kg"`
+	ColorHex            *string        `json:"color_hex"`
+	FrictionCoefficient pgtype.Numeric `json:"friction_coefficient"`
+	TemperatureClass    *string        `json:"temperature_class"`
+	Gtin                *string        `json:"gtin"`
+	Fragile             bool           `json:"fragile"`
+	ThisSideUp          bool           `json:"this_side_up"`
+	Stackable           bool           `json:"stackable"`
+	MaxStackLoadKg      pgtype.Numeric `json:"max_stack_load_kg"`
+	HazmatClass         *string        `json:"hazmat_class"`
+	PackagingType       *string        `json:"packaging_type"`
+	Packaging           []byte         `json:"packaging"`
+	ShippingUnit        string         `json:"shipping_unit"`
 }
 
 func (q *Queries) CreateProduct(ctx context.Context, arg CreateProductParams) (Product, error) {
@@ -49,6 +71,17 @@ func (q *Queries) CreateProduct(ctx context.Context, arg CreateProductParams) (P
 		arg.HeightMm,
 		arg.WeightKg,
 		arg.ColorHex,
+		arg.FrictionCoefficient,
+		arg.TemperatureClass,
+		arg.Gtin,
+		arg.Fragile,
+		arg.ThisSideUp,
+		arg.Stackable,
+		arg.MaxStackLoadKg,
+		arg.HazmatClass,
+		arg.PackagingType,
+		arg.Packaging,
+		arg.ShippingUnit,
 	)
 	var i Product
 	err := row.Scan(
@@ -63,6 +96,17 @@ func (q *Queries) CreateProduct(ctx context.Context, arg CreateProductParams) (P
 		&i.UpdatedAt,
 		&i.WorkspaceID,
 		&i.Sku,
+		&i.FrictionCoefficient,
+		&i.TemperatureClass,
+		&i.Gtin,
+		&i.Fragile,
+		&i.ThisSideUp,
+		&i.Stackable,
+		&i.MaxStackLoadKg,
+		&i.HazmatClass,
+		&i.PackagingType,
+		&i.Packaging,
+		&i.ShippingUnit,
 	)
 	return i, err
 }
@@ -94,7 +138,7 @@ func (q *Queries) DeleteProductAny(ctx context.Context, productID uuid.UUID) err
 }
 
 const getProduct = `-- name: GetProduct :one
-SELECT product_id, name, length_mm, width_mm, height_mm, weight_kg, color_hex, created_at, updated_at, workspace_id, sku
+SELECT product_id, name, length_mm, width_mm, height_mm, weight_kg, color_hex, created_at, updated_at, workspace_id, sku, friction_coefficient, temperature_class, gtin, fragile, this_side_up, stackable, max_stack_load_kg, hazmat_class, packaging_type, packaging, shipping_unit
 FROM products
 WHERE product_id = $1
   AND (workspace_id = $2 OR workspace_id IS NULL)
@@ -120,12 +164,23 @@ func (q *Queries) GetProduct(ctx context.Context, arg GetProductParams) (Product
 		&i.UpdatedAt,
 		&i.WorkspaceID,
 		&i.Sku,
+		&i.FrictionCoefficient,
+		&i.TemperatureClass,
+		&i.Gtin,
+		&i.Fragile,
+		&i.ThisSideUp,
+		&i.Stackable,
+		&i.MaxStackLoadKg,
+		&i.HazmatClass,
+		&i.PackagingType,
+		&i.Packaging,
+		&i.ShippingUnit,
 	)
 	return i, err
 }
 
 const getProductAny = `-- name: GetProductAny :one
-SELECT product_id, name, length_mm, width_mm, height_mm, weight_kg, color_hex, created_at, updated_at, workspace_id, sku
+SELECT product_id, name, length_mm, width_mm, height_mm, weight_kg, color_hex, created_at, updated_at, workspace_id, sku, friction_coefficient, temperature_class, gtin, fragile, this_side_up, stackable, max_stack_load_kg, hazmat_class, packaging_type, packaging, shipping_unit
 FROM products
 WHERE product_id = $1
 `
@@ -145,12 +200,105 @@ func (q *Queries) GetProductAny(ctx context.Context, productID uuid.UUID) (Produ
 		&i.UpdatedAt,
 		&i.WorkspaceID,
 		&i.Sku,
+		&i.FrictionCoefficient,
+		&i.TemperatureClass,
+		&i.Gtin,
+		&i.Fragile,
+		&i.ThisSideUp,
+		&i.Stackable,
+		&i.MaxStackLoadKg,
+		&i.HazmatClass,
+		&i.PackagingType,
+		&i.Packaging,
+		&i.ShippingUnit,
+	)
+	return i, err
+}
+
+const getProductBySku = `-- name: GetProductBySku :one
+SELECT product_id, name, length_mm, width_mm, height_mm, weight_kg, color_hex, created_at, updated_at, workspace_id, sku, friction_coefficient, temperature_class, gtin, fragile, this_side_up, stackable, max_stack_load_kg, hazmat_class, packaging_type, packaging, shipping_unit
+FROM products
+WHERE sku = $1
+  AND (workspace_id = $2 OR workspace_id IS NULL)
+ORDER BY (workspace_id IS NULL) ASC
+LIMIT 1
+`
+
+type GetProductBySkuParams struct {
+	Sku         *string    `json:"sku"`
+	WorkspaceID *uuid.UUID `json:"workspace_id"`
+}
+
+func (q *Queries) GetProductBySku(ctx context.Context, arg GetProductBySkuParams) (Product, error) {
+	row := q.db.QueryRow(ctx, getProductBySku, arg.Sku, arg.WorkspaceID)
+	var i Product
+	err := row.Scan(
+		&i.ProductID,
+		&i.Name,
+		&i.LengthMm,
+		&i.WidthMm,
+		&i.HeightMm,
+		&i.WeightKg,
+		&i.ColorHex,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.WorkspaceID,
+		&i.Sku,
+		&i.FrictionCoefficient,
+		&i.TemperatureClass,
+		&i.Gtin,
+		&i.Fragile,
+		&i.ThisSideUp,
+		&i.Stackable,
+		&i.MaxStackLoadKg,
+		&i.HazmatClass,
+		&i.PackagingType,
+		&i.Packaging,
+		&i.ShippingUnit,
+	)
+	return i, err
+}
+
+const getProductBySkuAny = `-- name: GetProductBySkuAny :one
+SELECT product_id, name, length_mm, width_mm, height_mm, weight_kg, color_hex, created_at, updated_at, workspace_id, sku, friction_coefficient, temperature_class, gtin, fragile, this_side_up, stackable, max_stack_load_kg, hazmat_class, packaging_type, packaging, shipping_unit
+FROM products
+WHERE sku = $1
+ORDER BY (workspace_id IS NULL) DESC, created_at
+LIMIT 1
+`
+
+func (q *Queries) GetProductBySkuAny(ctx context.Context, sku *string) (Product, error) {
+	row := q.db.QueryRow(ctx, getProductBySkuAny, sku)
+	var i Product
+	err := row.Scan(
+		&i.ProductID,
+		&i.Name,
+		&i.LengthMm,
+		&i.WidthMm,
+		&i.HeightMm,
+		&i.WeightKg,
+		&i.ColorHex,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.WorkspaceID,
+		&i.Sku,
+		&i.FrictionCoefficient,
+		&i.TemperatureClass,
+		&i.Gtin,
+		&i.Fragile,
+		&i.ThisSideUp,
+		&i.Stackable,
+		&i.MaxStackLoadKg,
+		&i.HazmatClass,
+		&i.PackagingType,
+		&i.Packaging,
+		&i.ShippingUnit,
 	)
 	return i, err
 }
 
 const listProducts = `-- name: ListProducts :many
-SELECT product_id, name, length_mm, width_mm, height_mm, weight_kg, color_hex, created_at, updated_at, workspace_id, sku
+SELECT product_id, name, length_mm, width_mm, height_mm, weight_kg, color_hex, created_at, updated_at, workspace_id, sku, friction_coefficient, temperature_class, gtin, fragile, this_side_up, stackable, max_stack_load_kg, hazmat_class, packaging_type, packaging, shipping_unit
 FROM products
 WHERE workspace_id = $1 OR workspace_id IS NULL
 ORDER BY (workspace_id IS NULL) DESC, name
@@ -184,6 +332,17 @@ func (q *Queries) ListProducts(ctx context.Context, arg ListProductsParams) ([]P
 			&i.UpdatedAt,
 			&i.WorkspaceID,
 			&i.Sku,
+			&i.FrictionCoefficient,
+			&i.TemperatureClass,
+			&i.Gtin,
+			&i.Fragile,
+			&i.ThisSideUp,
+			&i.Stackable,
+			&i.MaxStackLoadKg,
+			&i.HazmatClass,
+			&i.PackagingType,
+			&i.Packaging,
+			&i.ShippingUnit,
 		); err != nil {
 			return nil, err
 		}
@@ -196,7 +355,7 @@ func (q *Queries) ListProducts(ctx context.Context, arg ListProductsParams) ([]P
 }
 
 const listProductsAll = `-- name: ListProductsAll :many
-SELECT product_id, name, length_mm, width_mm, height_mm, weight_kg, color_hex, created_at, updated_at, workspace_id, sku
+SELECT product_id, name, length_mm, width_mm, height_mm, weight_kg, color_hex, created_at, updated_at, workspace_id, sku, friction_coefficient, temperature_class, gtin, fragile, this_side_up, stackable, max_stack_load_kg, hazmat_class, packaging_type, packaging, shipping_unit
 FROM products
 ORDER BY (workspace_id IS NULL) DESC, name
 LIMIT $1 OFFSET $2
@@ -228,6 +387,17 @@ func (q *Queries) ListProductsAll(ctx context.Context, arg ListProductsAllParams
 			&i.UpdatedAt,
 			&i.WorkspaceID,
 			&i.Sku,
+			&i.FrictionCoefficient,
+			&i.TemperatureClass,
+			&i.Gtin,
+			&i.Fragile,
+			&i.ThisSideUp,
+			&i.Stackable,
+			&i.MaxStackLoadKg,
+			&i.HazmatClass,
+			&i.PackagingType,
+			&i.Packaging,
+			&i.ShippingUnit,
 		); err != nil {
 			return nil, err
 		}
@@ -249,21 +419,43 @@ SET
     height_mm = $7,
     weight_kg = $8,
     color_hex = $9,
-    updated_at = NOW()
+    updated_at = NOW(),
+    friction_coefficient = $10,
+    temperature_class = $11,
+    gtin = $12,
+    fragile = $13,
+    this_side_up = $14,
+    stackable = $15,
+    max_stack_load_kg = $16,
+    hazmat_class = $17,
+    packaging_type = $18,
+    packaging = $19,
+    shipping_unit = $20
 WHERE product_id = $1
   AND workspace_id = $2
 `
 
 type UpdateProductParams struct {
-	ProductID   uuid.UUID      `json:"product_id"`
-	WorkspaceID *uuid.UUID     `json:"workspace_id"`
-	Name        string         `json:"name"`
-	Sku         *string        `json:"sku"`
-	LengthMm    pgtype.Numeric `json:"length_mm"`
-	WidthMm     pgtype.Numeric `json:"width_mm"`
-	HeightMm    pgtype.Numeric `json:"height_mm"`
-	WeightKg    pgtype.Numeric `json:"weight_kg"`
-	ColorHex    *string        `json:"color_hex"`
+	ProductID           uuid.UUID      `json:"product_id"`
+	WorkspaceID         *uuid.UUID     `json:"workspace_id"`
+	Name                string         `json:"name"`
+	Sku                 *string        `json:"sku"`
+	LengthMm            pgtype.Numeric `json:"length_mm"`
+	WidthMm             pgtype.Numeric `json:"width_mm"`
+	HeightMm            pgtype.Numeric `json:"height_mm"`
+	WeightKg            pgtype.Numeric `json:"weight_kg"`
+	ColorHex            *string        `json:"color_hex"`
+	FrictionCoefficient pgtype.Numeric `json:"friction_coefficient"`
+	TemperatureClass    *string        `json:"temperature_class"`
+	Gtin                *string        `json:"gtin"`
+	Fragile             bool           `json:"fragile"`
+	ThisSideUp          bool           `json:"this_side_up"`
+	Stackable           bool           `json:"stackable"`
+	MaxStackLoadKg      pgtype.Numeric `json:"max_stack_load_kg"`
+	HazmatClass         *string        `json:"hazmat_class"`
+	PackagingType       *string        `json:"packaging_type"`
+	Packaging           []byte         `json:"packaging"`
+	ShippingUnit        string         `json:"shipping_unit"`
 }
 
 func (q *Queries) UpdateProduct(ctx context.Context, arg UpdateProductParams) error {
@@ -277,6 +469,17 @@ func (q *Queries) UpdateProduct(ctx context.Context, arg UpdateProductParams) er
 		arg.HeightMm,
 		arg.WeightKg,
 		arg.ColorHex,
+		arg.FrictionCoefficient,
+		arg.TemperatureClass,
+		arg.Gtin,
+		arg.Fragile,
+		arg.ThisSideUp,
+		arg.Stackable,
+		arg.MaxStackLoadKg,
+		arg.HazmatClass,
+		arg.PackagingType,
+		arg.Packaging,
+		arg.ShippingUnit,
 	)
 	return err
 }
@@ -291,19 +494,41 @@ SET
     height_mm = $6,
     weight_kg = $7,
     color_hex = $8,
-    updated_at = NOW()
+    updated_at = NOW(),
+    friction_coefficient = $9,
+    temperature_class = $10,
+    gtin = $11,
+    fragile = $12,
+    this_side_up = $13,
+    stackable = $14,
+    max_stack_load_kg = $15,
+    hazmat_class = $16,
+    packaging_type = $17,
+    packaging = $18,
+    shipping_unit = $19
 WHERE product_id = $1
 `
 
 type UpdateProductAnyParams struct {
-	ProductID uuid.UUID      `json:"product_id"`
-	Name      string         `json:"name"`
-	Sku       *string        `json:"sku"`
-	LengthMm  pgtype.Numeric `json:"length_mm"`
-	WidthMm   pgtype.Numeric `json:"width_mm"`
-	HeightMm  pgtype.Numeric `json:"height_mm"`
-	WeightKg  pgtype.Numeric `json:"weight_kg"`
-	ColorHex  *string        `json:"color_hex"`
+	ProductID           uuid.UUID      `json:"product_id"`
+	Name                string         `json:"name"`
+	Sku                 *string        `json:"sku"`
+	LengthMm            pgtype.Numeric `json:"length_mm"`
+	WidthMm             pgtype.Numeric `json:"width_mm"`
+	HeightMm            pgtype.Numeric `json:"height_mm"`
+	WeightKg            pgtype.Numeric `json:"weight_kg"`
+	ColorHex            *string        `json:"color_hex"`
+	FrictionCoefficient pgtype.Numeric `json:"friction_coefficient"`
+	TemperatureClass    *string        `json:"temperature_class"`
+	Gtin                *string        `json:"gtin"`
+	Fragile             bool           `json:"fragile"`
+	ThisSideUp          bool           `json:"this_side_up"`
+	Stackable           bool           `json:"stackable"`
+	MaxStackLoadKg      pgtype.Numeric `json:"max_stack_load_kg"`
+	HazmatClass         *string        `json:"hazmat_class"`
+	PackagingType       *string        `json:"packaging_type"`
+	Packaging           []byte         `json:"packaging"`
+	ShippingUnit        string         `json:"shipping_unit"`
 }
 
 func (q *Queries) UpdateProductAny(ctx context.Context, arg UpdateProductAnyParams) error {
@@ -316,6 +541,17 @@ func (q *Queries) UpdateProductAny(ctx context.Context, arg UpdateProductAnyPara
 		arg.HeightMm,
 		arg.WeightKg,
 		arg.ColorHex,
+		arg.FrictionCoefficient,
+		arg.TemperatureClass,
+		arg.Gtin,
+		arg.Fragile,
+		arg.ThisSideUp,
+		arg.Stackable,
+		arg.MaxStackLoadKg,
+		arg.HazmatClass,
+		arg.PackagingType,
+		arg.Packaging,
+		arg.ShippingUnit,
 	)
 	return err
 }
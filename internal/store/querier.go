@@ -11,10 +11,15 @@ import (
 )
 
 type Querier interface {
+	AbandonLoadingSessions(ctx context.Context, planID uuid.UUID) error
 	AcceptInvite(ctx context.Context, arg AcceptInviteParams) error
+	ActivatePlanResult(ctx context.Context, resultID uuid.UUID) error
 	AddLoadItem(ctx context.Context, arg AddLoadItemParams) (LoadItem, error)
 	AddRolePermission(ctx context.Context, arg AddRolePermissionParams) error
+	CancelCalculationJob(ctx context.Context, jobID uuid.UUID) (int64, error)
 	ClaimPlansFromGuest(ctx context.Context, arg ClaimPlansFromGuestParams) error
+	CompleteCalculationJob(ctx context.Context, arg CompleteCalculationJobParams) error
+	CompleteLoadingSession(ctx context.Context, arg CompleteLoadingSessionParams) (int64, error)
 	CountGlobalActivePlans(ctx context.Context) (int64, error)
 	CountGlobalCompletedPlans(ctx context.Context) (int64, error)
 	CountGlobalCompletedPlansToday(ctx context.Context) (int64, error)
@@ -30,31 +35,41 @@ type Querier interface {
 	CountWorkspaceItems(ctx context.Context, workspaceID *uuid.UUID) (int64, error)
 	// WORKSPACE SCOPED QUERIES
 	CountWorkspaceMembers(ctx context.Context, workspaceID uuid.UUID) (int64, error)
+	CreateCalculationJob(ctx context.Context, arg CreateCalculationJobParams) (CalculationJob, error)
 	CreateContainer(ctx context.Context, arg CreateContainerParams) (Container, error)
+	CreateHandlingUnit(ctx context.Context, arg CreateHandlingUnitParams) (HandlingUnit, error)
 	CreateInvite(ctx context.Context, arg CreateInviteParams) (Invite, error)
 	CreateLoadPlan(ctx context.Context, arg CreateLoadPlanParams) (LoadPlan, error)
+	CreateLoadingScan(ctx context.Context, arg CreateLoadingScanParams) (LoadingScan, error)
+	CreateLoadingSession(ctx context.Context, arg CreateLoadingSessionParams) (LoadingSession, error)
 	CreateMember(ctx context.Context, arg CreateMemberParams) (Member, error)
+	CreateOverflowPlan(ctx context.Context, arg CreateOverflowPlanParams) (LoadPlan, error)
 	CreatePermission(ctx context.Context, arg CreatePermissionParams) (Permission, error)
 	CreatePlanPlacement(ctx context.Context, arg []CreatePlanPlacementParams) (int64, error)
 	CreatePlanResult(ctx context.Context, arg CreatePlanResultParams) (PlanResult, error)
+	CreatePlanStatusHistory(ctx context.Context, arg CreatePlanStatusHistoryParams) (PlanStatusHistory, error)
 	CreateProduct(ctx context.Context, arg CreateProductParams) (Product, error)
 	CreateRefreshToken(ctx context.Context, arg CreateRefreshTokenParams) error
 	CreateRole(ctx context.Context, arg CreateRoleParams) (Role, error)
+	CreateScenarioPlan(ctx context.Context, arg CreateScenarioPlanParams) (LoadPlan, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
 	CreateWorkspace(ctx context.Context, arg CreateWorkspaceParams) (Workspace, error)
+	DeactivatePlanResults(ctx context.Context, planID *uuid.UUID) error
 	DeleteContainer(ctx context.Context, arg DeleteContainerParams) error
 	DeleteContainerAny(ctx context.Context, containerID uuid.UUID) error
 	DeleteLoadItem(ctx context.Context, arg DeleteLoadItemParams) error
 	DeleteLoadPlan(ctx context.Context, arg DeleteLoadPlanParams) error
 	DeleteMember(ctx context.Context, arg DeleteMemberParams) error
 	DeletePermission(ctx context.Context, permissionID uuid.UUID) error
-	DeletePlanResults(ctx context.Context, planID *uuid.UUID) error
 	DeleteProduct(ctx context.Context, arg DeleteProductParams) error
 	DeleteProductAny(ctx context.Context, productID uuid.UUID) error
 	DeleteRole(ctx context.Context, roleID uuid.UUID) error
 	DeleteRolePermissions(ctx context.Context, roleID uuid.UUID) error
 	DeleteUser(ctx context.Context, userID uuid.UUID) error
 	DeleteWorkspace(ctx context.Context, workspaceID uuid.UUID) error
+	FailCalculationJob(ctx context.Context, arg FailCalculationJobParams) error
+	GetCalculationJob(ctx context.Context, arg GetCalculationJobParams) (CalculationJob, error)
+	GetCalculationJobAny(ctx context.Context, jobID uuid.UUID) (CalculationJob, error)
 	GetContainer(ctx context.Context, arg GetContainerParams) (Container, error)
 	GetContainerAny(ctx context.Context, containerID uuid.UUID) (Container, error)
 	GetGlobalAvgVolumeUtilization(ctx context.Context) (float64, error)
@@ -64,16 +79,21 @@ type Querier interface {
 	GetLoadPlan(ctx context.Context, arg GetLoadPlanParams) (LoadPlan, error)
 	GetLoadPlanAny(ctx context.Context, planID uuid.UUID) (LoadPlan, error)
 	GetLoadPlanForGuest(ctx context.Context, arg GetLoadPlanForGuestParams) (LoadPlan, error)
+	GetLoadingSession(ctx context.Context, arg GetLoadingSessionParams) (LoadingSession, error)
 	GetMember(ctx context.Context, memberID uuid.UUID) (Member, error)
 	GetMemberByWorkspaceAndUser(ctx context.Context, arg GetMemberByWorkspaceAndUserParams) (Member, error)
 	GetMemberRoleNameByWorkspaceAndUser(ctx context.Context, arg GetMemberRoleNameByWorkspaceAndUserParams) (string, error)
+	GetOpenLoadingSession(ctx context.Context, planID uuid.UUID) (LoadingSession, error)
 	GetPermission(ctx context.Context, permissionID uuid.UUID) (Permission, error)
 	GetPermissionsByRole(ctx context.Context, name string) ([]string, error)
 	GetPersonalWorkspaceByOwner(ctx context.Context, ownerUserID uuid.UUID) (Workspace, error)
 	GetPlanResult(ctx context.Context, planID *uuid.UUID) (PlanResult, error)
+	GetPlanResultVersion(ctx context.Context, arg GetPlanResultVersionParams) (PlanResult, error)
 	GetPlatformRoleByUserID(ctx context.Context, userID uuid.UUID) (string, error)
 	GetProduct(ctx context.Context, arg GetProductParams) (Product, error)
 	GetProductAny(ctx context.Context, productID uuid.UUID) (Product, error)
+	GetProductBySku(ctx context.Context, arg GetProductBySkuParams) (Product, error)
+	GetProductBySkuAny(ctx context.Context, sku *string) (Product, error)
 	GetRefreshToken(ctx context.Context, token string) (GetRefreshTokenRow, error)
 	GetRole(ctx context.Context, roleID uuid.UUID) (Role, error)
 	GetRoleByName(ctx context.Context, name string) (GetRoleByNameRow, error)
@@ -81,28 +101,50 @@ type Querier interface {
 	GetUserByEmail(ctx context.Context, email string) (GetUserByEmailRow, error)
 	GetUserByID(ctx context.Context, userID uuid.UUID) (GetUserByIDRow, error)
 	GetUserByUsername(ctx context.Context, username string) (GetUserByUsernameRow, error)
+	GetUserPreference(ctx context.Context, userID uuid.UUID) (UserPreference, error)
 	GetWorkspace(ctx context.Context, workspaceID uuid.UUID) (Workspace, error)
 	GetWorkspaceAvgVolumeUtilization(ctx context.Context, workspaceID *uuid.UUID) (float64, error)
+	GetWorkspaceGS1(ctx context.Context, workspaceID uuid.UUID) (WorkspaceGs1, error)
 	GetWorkspacePlanStatusDistribution(ctx context.Context, workspaceID *uuid.UUID) ([]GetWorkspacePlanStatusDistributionRow, error)
+	HitPackingResultCache(ctx context.Context, cacheKey string) ([]byte, error)
 	ListContainers(ctx context.Context, arg ListContainersParams) ([]Container, error)
 	ListContainersAll(ctx context.Context, arg ListContainersAllParams) ([]Container, error)
+	ListHandlingUnits(ctx context.Context, resultID uuid.UUID) ([]HandlingUnit, error)
 	ListInvitesByWorkspace(ctx context.Context, arg ListInvitesByWorkspaceParams) ([]ListInvitesByWorkspaceRow, error)
 	ListLoadItems(ctx context.Context, planID *uuid.UUID) ([]LoadItem, error)
 	ListLoadPlans(ctx context.Context, arg ListLoadPlansParams) ([]LoadPlan, error)
 	ListLoadPlansAll(ctx context.Context, arg ListLoadPlansAllParams) ([]LoadPlan, error)
 	ListLoadPlansForGuest(ctx context.Context, arg ListLoadPlansForGuestParams) ([]LoadPlan, error)
+	ListLoadingScans(ctx context.Context, sessionID uuid.UUID) ([]LoadingScan, error)
+	ListLoadingSessions(ctx context.Context, planID uuid.UUID) ([]LoadingSession, error)
+	ListMatchedLoadingSteps(ctx context.Context, sessionID uuid.UUID) ([]*int32, error)
 	ListMembersByWorkspace(ctx context.Context, arg ListMembersByWorkspaceParams) ([]ListMembersByWorkspaceRow, error)
+	ListPendingCalculationJobs(ctx context.Context, limit int32) ([]CalculationJob, error)
 	ListPermissions(ctx context.Context, arg ListPermissionsParams) ([]Permission, error)
 	ListPlanPlacements(ctx context.Context, resultID *uuid.UUID) ([]PlanPlacement, error)
+	ListPlanResults(ctx context.Context, planID *uuid.UUID) ([]PlanResult, error)
+	ListPlanScenarios(ctx context.Context, parentPlanID *uuid.UUID) ([]LoadPlan, error)
+	ListPlanStatusHistory(ctx context.Context, planID uuid.UUID) ([]PlanStatusHistory, error)
 	ListProducts(ctx context.Context, arg ListProductsParams) ([]Product, error)
 	ListProductsAll(ctx context.Context, arg ListProductsAllParams) ([]Product, error)
 	ListRoles(ctx context.Context, arg ListRolesParams) ([]Role, error)
+	ListShipmentGroupPlans(ctx context.Context, shipmentGroupID *uuid.UUID) ([]LoadPlan, error)
 	ListUsers(ctx context.Context, arg ListUsersParams) ([]ListUsersRow, error)
 	ListWorkspacesAll(ctx context.Context, arg ListWorkspacesAllParams) ([]ListWorkspacesAllRow, error)
 	ListWorkspacesByOwner(ctx context.Context, arg ListWorkspacesByOwnerParams) ([]Workspace, error)
 	ListWorkspacesForUser(ctx context.Context, arg ListWorkspacesForUserParams) ([]Workspace, error)
+	LockLoadPlan(ctx context.Context, planID uuid.UUID) (*string, error)
+	MarkCalculationJobRunning(ctx context.Context, jobID uuid.UUID) (int64, error)
+	PauseLoadingSession(ctx context.Context, sessionID uuid.UUID) (int64, error)
+	// Drops results not used since before and all but the keep most recently
+	// used ones.
+	PrunePackingResultCache(ctx context.Context, arg PrunePackingResultCacheParams) (int64, error)
+	RequeueRunningCalculationJobs(ctx context.Context) (int64, error)
+	ReserveSSCCSerials(ctx context.Context, arg ReserveSSCCSerialsParams) (WorkspaceGs1, error)
+	ResumeLoadingSession(ctx context.Context, sessionID uuid.UUID) (int64, error)
 	RevokeInvite(ctx context.Context, arg RevokeInviteParams) error
 	RevokeRefreshToken(ctx context.Context, token string) error
+	SetPlanShipmentGroup(ctx context.Context, arg SetPlanShipmentGroupParams) error
 	TransferWorkspaceOwnership(ctx context.Context, arg TransferWorkspaceOwnershipParams) error
 	UpdateContainer(ctx context.Context, arg UpdateContainerParams) error
 	UpdateContainerAny(ctx context.Context, arg UpdateContainerAnyParams) error
@@ -119,7 +161,10 @@ type Querier interface {
 	UpdateUser(ctx context.Context, arg UpdateUserParams) error
 	UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) error
 	UpdateWorkspace(ctx context.Context, arg UpdateWorkspaceParams) error
+	UpsertPackingResultCache(ctx context.Context, arg UpsertPackingResultCacheParams) error
 	UpsertPlatformMember(ctx context.Context, arg UpsertPlatformMemberParams) error
+	UpsertUserPreference(ctx context.Context, arg UpsertUserPreferenceParams) (UserPreference, error)
+	UpsertWorkspaceGS1(ctx context.Context, arg UpsertWorkspaceGS1Params) (WorkspaceGs1, error)
 }
 
 var _ Querier = (*Queries)(nil)
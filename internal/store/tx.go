@@ -0,0 +1,45 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// TxQuerier is a Querier that can run a group of queries in one
+// transaction.
+type TxQuerier interface {
+	Querier
+	// ExecTx runs fn with queries bound to a transaction, committing it when
+	// fn returns nil and rolling it back otherwise.
+	ExecTx(ctx context.Context, fn func(Querier) error) error
+}
+
+var _ TxQuerier = (*Queries)(nil)
+
+type txBeginner interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// ExecTx runs fn in a transaction of the pool or, inside a transaction, in a
+// savepoint. Queries over a connection that cannot begin one run fn on q.
+func (q *Queries) ExecTx(ctx context.Context, fn func(Querier) error) error {
+	b, ok := q.db.(txBeginner)
+	if !ok {
+		return fn(q)
+	}
+	tx, err := b.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(q.WithTx(tx)); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
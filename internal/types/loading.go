@@ -0,0 +1,40 @@
+package types
+
+type LoadingSessionStatus string
+
+const (
+	LoadingSessionActive    LoadingSessionStatus = "active"
+	LoadingSessionPaused    LoadingSessionStatus = "paused"
+	LoadingSessionCompleted LoadingSessionStatus = "completed"
+	LoadingSessionAbandoned LoadingSessionStatus = "abandoned"
+)
+
+func (s LoadingSessionStatus) String() string {
+	return string(s)
+}
+
+// ScanStatus is the outcome of a barcode scanned during a loading session.
+// Only MATCHED scans count as loaded units.
+type ScanStatus string
+
+const (
+	ScanMatched       ScanStatus = "MATCHED"
+	ScanOutOfSequence ScanStatus = "OUT_OF_SEQUENCE"
+	ScanDuplicate     ScanStatus = "DUPLICATE"
+	ScanUnknownStep   ScanStatus = "UNKNOWN_STEP"
+	// ScanUnknownProduct is a scanned GTIN no item of the plan carries.
+	ScanUnknownProduct ScanStatus = "UNKNOWN_PRODUCT"
+	ScanWrongPlan      ScanStatus = "WRONG_PLAN"
+	ScanInvalidFormat  ScanStatus = "INVALID_FORMAT"
+	// ScanInvalidSignature is a signed label whose signature does not match,
+	// i.e. a forged or damaged one.
+	ScanInvalidSignature ScanStatus = "INVALID_SIGNATURE"
+	// ScanConflict is an offline scan uploaded after the plan was
+	// recalculated or the session closed. It is kept for the record but
+	// never counts as loaded.
+	ScanConflict ScanStatus = "CONFLICT"
+)
+
+func (s ScanStatus) String() string {
+	return string(s)
+}
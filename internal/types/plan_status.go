@@ -4,6 +4,7 @@ type PlanStatus string
 
 const (
 	PlanStatusDraft      PlanStatus = "DRAFT"
+	PlanStatusPlanned    PlanStatus = "PLANNED"
 	PlanStatusInProgress PlanStatus = "IN_PROGRESS"
 	PlanStatusCompleted  PlanStatus = "COMPLETED"
 	PlanStatusFailed     PlanStatus = "FAILED"
@@ -14,3 +15,43 @@ const (
 func (s PlanStatus) String() string {
 	return string(s)
 }
+
+// planTransitions lists the statuses each status may move to. A calculation
+// moves a plan to PLANNED, PARTIAL or FAILED; loading takes it through
+// IN_PROGRESS to COMPLETED. COMPLETED is final.
+var planTransitions = map[PlanStatus][]PlanStatus{
+	PlanStatusDraft:      {PlanStatusPlanned, PlanStatusPartial, PlanStatusFailed, PlanStatusCancelled},
+	PlanStatusPlanned:    {PlanStatusDraft, PlanStatusPlanned, PlanStatusPartial, PlanStatusFailed, PlanStatusInProgress, PlanStatusCancelled},
+	PlanStatusPartial:    {PlanStatusDraft, PlanStatusPlanned, PlanStatusPartial, PlanStatusFailed, PlanStatusInProgress, PlanStatusCancelled},
+	PlanStatusFailed:     {PlanStatusDraft, PlanStatusPlanned, PlanStatusPartial, PlanStatusFailed, PlanStatusCancelled},
+	PlanStatusInProgress: {PlanStatusPlanned, PlanStatusPartial, PlanStatusCompleted, PlanStatusCancelled},
+	PlanStatusCompleted:  {},
+	PlanStatusCancelled:  {PlanStatusDraft},
+}
+
+// ParsePlanStatus returns the status named by s. A plan without a status is
+// a draft.
+func ParsePlanStatus(s string) (PlanStatus, bool) {
+	if s == "" {
+		return PlanStatusDraft, true
+	}
+	st := PlanStatus(s)
+	_, ok := planTransitions[st]
+	return st, ok
+}
+
+// CanTransition reports whether a plan may move from one status to another.
+func (s PlanStatus) CanTransition(to PlanStatus) bool {
+	for _, next := range planTransitions[s] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Calculable reports whether a plan in this status may be (re)calculated.
+// Plans being loaded, loaded or cancelled keep their result.
+func (s PlanStatus) Calculable() bool {
+	return s.CanTransition(PlanStatusPlanned) && s != PlanStatusInProgress
+}
@@ -72,6 +72,11 @@ func TestPlanStatus_String(t *testing.T) {
 			status:   PlanStatusDraft,
 			expected: "DRAFT",
 		},
+		{
+			name:     "planned_status",
+			status:   PlanStatusPlanned,
+			expected: "PLANNED",
+		},
 		{
 			name:     "in_progress_status",
 			status:   PlanStatusInProgress,
@@ -106,6 +111,94 @@ func TestPlanStatus_String(t *testing.T) {
 	}
 }
 
+func TestPlanStatus_CanTransition(t *testing.T) {
+	tests := []struct {
+		from, to PlanStatus
+		ok       bool
+	}{
+		{PlanStatusDraft, PlanStatusPlanned, true},
+		{PlanStatusDraft, PlanStatusCompleted, false},
+		{PlanStatusDraft, PlanStatusInProgress, false},
+		{PlanStatusPlanned, PlanStatusInProgress, true},
+		{PlanStatusPartial, PlanStatusInProgress, true},
+		{PlanStatusFailed, PlanStatusInProgress, false},
+		{PlanStatusInProgress, PlanStatusCompleted, true},
+		{PlanStatusInProgress, PlanStatusPlanned, true},
+		{PlanStatusInProgress, PlanStatusDraft, false},
+		{PlanStatusCompleted, PlanStatusDraft, false},
+		{PlanStatusCancelled, PlanStatusDraft, true},
+		{PlanStatusCancelled, PlanStatusPlanned, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.from.String()+"_to_"+tt.to.String(), func(t *testing.T) {
+			assert.Equal(t, tt.ok, tt.from.CanTransition(tt.to))
+		})
+	}
+}
+
+func TestPlanStatus_Calculable(t *testing.T) {
+	for _, s := range []PlanStatus{PlanStatusDraft, PlanStatusPlanned, PlanStatusPartial, PlanStatusFailed} {
+		assert.True(t, s.Calculable(), s)
+	}
+	for _, s := range []PlanStatus{PlanStatusInProgress, PlanStatusCompleted, PlanStatusCancelled} {
+		assert.False(t, s.Calculable(), s)
+	}
+}
+
+func TestParsePlanStatus(t *testing.T) {
+	s, ok := ParsePlanStatus("")
+	assert.True(t, ok)
+	assert.Equal(t, PlanStatusDraft, s)
+
+	s, ok = ParsePlanStatus("PLANNED")
+	assert.True(t, ok)
+	assert.Equal(t, PlanStatusPlanned, s)
+
+	_, ok = ParsePlanStatus("CALCULATED")
+	assert.False(t, ok)
+}
+
+func TestJobStatus_String(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   JobStatus
+		expected string
+	}{
+		{
+			name:     "queued_status",
+			status:   JobStatusQueued,
+			expected: "queued",
+		},
+		{
+			name:     "running_status",
+			status:   JobStatusRunning,
+			expected: "running",
+		},
+		{
+			name:     "completed_status",
+			status:   JobStatusCompleted,
+			expected: "completed",
+		},
+		{
+			name:     "failed_status",
+			status:   JobStatusFailed,
+			expected: "failed",
+		},
+		{
+			name:     "cancelled_status",
+			status:   JobStatusCancelled,
+			expected: "cancelled",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.status.String())
+		})
+	}
+}
+
 func TestNormalizeRole(t *testing.T) {
 	tests := []struct {
 		name     string
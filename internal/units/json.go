@@ -0,0 +1,189 @@
+package units
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Field names follow the canonical unit: "length_mm", "max_weight_kg",
+// "total_volume_m3", and for loads "floor_load_kg_m2" and "line_load_kg_m".
+// Placement coordinates ("pos_x") and the "position" and
+// "dimensions" objects of barcode responses are millimetres without a suffix.
+var (
+	bareLengthKeys   = map[string]bool{"pos_x": true, "pos_y": true, "pos_z": true}
+	lengthObjectKeys = map[string]bool{"position": true, "dimensions": true}
+)
+
+// RewriteRequest converts a JSON request body written in s to canonical
+// units. Numeric fields suffixed with s's units ("length_in", "weight_lb")
+// are converted and renamed to their canonical form ("length_mm",
+// "weight_kg"). Fields already in canonical units are left alone.
+func RewriteRequest(body []byte, s System) ([]byte, error) {
+	if s.IsCanonical() {
+		return body, nil
+	}
+	lenSuffix := "_" + string(s.Length)
+	weightSuffix := "_" + string(s.Weight)
+	pressureSuffix := weightSuffix + "_" + string(s.Area())
+	lineSuffix := weightSuffix + "_" + string(s.Run())
+
+	return rewrite(body, func(key string, n float64, _ bool) (string, float64, bool) {
+		// Loads first: a line load in kg/m would otherwise read as a length
+		// in metres.
+		switch {
+		case strings.HasSuffix(key, "_kg_m2"), strings.HasSuffix(key, "_kg_m"):
+			return key, n, false
+		case strings.HasSuffix(key, pressureSuffix):
+			return strings.TrimSuffix(key, pressureSuffix) + "_kg_m2", s.ToKilogramsPerSquareMetre(n), true
+		case strings.HasSuffix(key, lineSuffix):
+			return strings.TrimSuffix(key, lineSuffix) + "_kg_m", s.ToKilogramsPerMetre(n), true
+		}
+		if s.Length != Millimetre && strings.HasSuffix(key, lenSuffix) {
+			return strings.TrimSuffix(key, lenSuffix) + "_mm", s.ToMillimetres(n), true
+		}
+		if s.Weight != Kilogram && strings.HasSuffix(key, weightSuffix) {
+			return strings.TrimSuffix(key, weightSuffix) + "_kg", s.ToKilograms(n), true
+		}
+		return key, n, false
+	})
+}
+
+// RewriteResponse converts a canonical JSON response body to s, renaming
+// suffixed fields ("length_mm" becomes "length_in", "floor_load_kg_m2"
+// becomes "floor_load_lb_ft2").
+func RewriteResponse(body []byte, s System) ([]byte, error) {
+	if s.IsCanonical() {
+		return body, nil
+	}
+	weight := "_" + string(s.Weight)
+	return rewrite(body, func(key string, n float64, inLengthObject bool) (string, float64, bool) {
+		switch {
+		case strings.HasSuffix(key, "_kg_m2"):
+			return strings.TrimSuffix(key, "_kg_m2") + weight + "_" + string(s.Area()), s.FromKilogramsPerSquareMetre(n), true
+		case strings.HasSuffix(key, "_kg_m"):
+			return strings.TrimSuffix(key, "_kg_m") + weight + "_" + string(s.Run()), s.FromKilogramsPerMetre(n), true
+		case strings.HasSuffix(key, "_m2"):
+			return strings.TrimSuffix(key, "_m2") + "_" + string(s.Area()), s.FromSquareMetres(n), true
+		case strings.HasSuffix(key, "_mm"):
+			return strings.TrimSuffix(key, "_mm") + "_" + string(s.Length), s.FromMillimetres(n), true
+		case strings.HasSuffix(key, "_kg"):
+			return strings.TrimSuffix(key, "_kg") + "_" + string(s.Weight), s.FromKilograms(n), true
+		case strings.HasSuffix(key, "_m3"):
+			return strings.TrimSuffix(key, "_m3") + "_" + string(s.Volume()), s.FromCubicMetres(n), true
+		case bareLengthKeys[key] || inLengthObject:
+			return key, s.FromMillimetres(n), true
+		}
+		return key, n, false
+	})
+}
+
+// fieldFunc maps a numeric object field. ok reports whether it was changed.
+type fieldFunc func(key string, n float64, inLengthObject bool) (newKey string, v float64, ok bool)
+
+func rewrite(body []byte, fn fieldFunc) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+
+	var out bytes.Buffer
+	out.Grow(len(body))
+
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	if err := copyValue(dec, &out, tok, false, fn); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("unexpected data after JSON value")
+	}
+	return out.Bytes(), nil
+}
+
+// copyValue writes the value starting at tok, rewriting numeric fields of
+// any objects it contains.
+func copyValue(dec *json.Decoder, out *bytes.Buffer, tok json.Token, inLengthObject bool, fn fieldFunc) error {
+	switch t := tok.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			return copyObject(dec, out, inLengthObject, fn)
+		case '[':
+			out.WriteByte('[')
+			for i := 0; dec.More(); i++ {
+				if i > 0 {
+					out.WriteByte(',')
+				}
+				next, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				if err := copyValue(dec, out, next, inLengthObject, fn); err != nil {
+					return err
+				}
+			}
+			if _, err := dec.Token(); err != nil { // ']'
+				return err
+			}
+			out.WriteByte(']')
+			return nil
+		}
+		return fmt.Errorf("unexpected delimiter %q", t)
+	default:
+		raw, err := json.Marshal(t)
+		if err != nil {
+			return err
+		}
+		out.Write(raw)
+		return nil
+	}
+}
+
+func copyObject(dec *json.Decoder, out *bytes.Buffer, inLengthObject bool, fn fieldFunc) error {
+	out.WriteByte('{')
+	for i := 0; dec.More(); i++ {
+		if i > 0 {
+			out.WriteByte(',')
+		}
+		keyTok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, _ := keyTok.(string)
+
+		val, err := dec.Token()
+		if err != nil {
+			return err
+		}
+
+		if num, ok := val.(json.Number); ok {
+			if f, err := num.Float64(); err == nil {
+				if newKey, v, changed := fn(key, f, inLengthObject); changed {
+					writeKey(out, newKey)
+					out.WriteString(strconv.FormatFloat(v, 'f', -1, 64))
+					continue
+				}
+			}
+		}
+
+		writeKey(out, key)
+		if err := copyValue(dec, out, val, lengthObjectKeys[key], fn); err != nil {
+			return err
+		}
+	}
+	if _, err := dec.Token(); err != nil { // '}'
+		return err
+	}
+	out.WriteByte('}')
+	return nil
+}
+
+func writeKey(out *bytes.Buffer, key string) {
+	raw, _ := json.Marshal(key)
+	out.Write(raw)
+	out.WriteByte(':')
+}
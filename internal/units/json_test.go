@@ -0,0 +1,78 @@
+package units
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRewriteRequest(t *testing.T) {
+	us := System{Length: Inch, Weight: Pound}
+
+	t.Run("converts_and_renames_suffixed_fields", func(t *testing.T) {
+		in := `{"title":"US order","container":{"length_in":100,"max_weight_lb":1000},"items":[{"length_in":10.5,"weight_kg":2,"quantity":3}]}`
+
+		out, err := RewriteRequest([]byte(in), us)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"title":"US order","container":{"length_mm":2540,"max_weight_kg":453.59},"items":[{"length_mm":266.7,"weight_kg":2,"quantity":3}]}`, string(out))
+	})
+
+	t.Run("canonical_is_untouched", func(t *testing.T) {
+		in := []byte(`{"length_in":1}`)
+		out, err := RewriteRequest(in, Canonical)
+		require.NoError(t, err)
+		assert.Equal(t, in, out)
+	})
+
+	t.Run("non_numeric_values_are_kept", func(t *testing.T) {
+		out, err := RewriteRequest([]byte(`{"plan_in":"x","length_in":null}`), us)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"plan_in":"x","length_in":null}`, string(out))
+	})
+
+	t.Run("invalid_json", func(t *testing.T) {
+		_, err := RewriteRequest([]byte(`{"length_in":`), us)
+		assert.Error(t, err)
+	})
+}
+
+func TestRewriteResponse(t *testing.T) {
+	us := System{Length: Inch, Weight: Pound}
+
+	in := `{"success":true,"data":{"length_mm":254,"total_weight_kg":10,"volume_m3":1,"volume_utilization_pct":50.5,` +
+		`"placements":[{"pos_x":25.4,"step_number":1}],"position":{"x":50.8},"step_number":2}}`
+
+	out, err := RewriteResponse([]byte(in), us)
+	require.NoError(t, err)
+	assert.Equal(t, `{"success":true,"data":{"length_in":10,"total_weight_lb":22.05,"volume_ft3":35.31,"volume_utilization_pct":50.5,`+
+		`"placements":[{"pos_x":1,"step_number":1}],"position":{"x":2},"step_number":2}}`, string(out))
+}
+
+func TestRewriteLoads(t *testing.T) {
+	us := System{Length: Inch, Weight: Pound}
+
+	t.Run("response", func(t *testing.T) {
+		in := `{"floor_load_kg_m2":2500,"line_load_kg_m":4500,"max_pressure_kg_m2":100,"required_bearing_area_m2":1}`
+		out, err := RewriteResponse([]byte(in), us)
+		require.NoError(t, err)
+		assert.Equal(t, `{"floor_load_lb_ft2":512.04,"line_load_lb_ft":3023.86,"max_pressure_lb_ft2":20.48,"required_bearing_area_ft2":10.76}`, string(out))
+	})
+
+	t.Run("request", func(t *testing.T) {
+		out, err := RewriteRequest([]byte(`{"floor_load_lb_ft2":100,"line_load_lb_ft":100}`), us)
+		require.NoError(t, err)
+		assert.Equal(t, `{"floor_load_kg_m2":488.24,"line_load_kg_m":148.82}`, string(out))
+	})
+
+	t.Run("metre_lengths_keep_canonical_loads", func(t *testing.T) {
+		sys := System{Length: Metre, Weight: Kilogram}
+		out, err := RewriteRequest([]byte(`{"length_m":12,"line_load_kg_m":4500}`), sys)
+		require.NoError(t, err)
+		assert.Equal(t, `{"length_mm":12000,"line_load_kg_m":4500}`, string(out))
+
+		out, err = RewriteResponse([]byte(`{"length_mm":12000,"line_load_kg_m":4500,"floor_load_kg_m2":2500}`), sys)
+		require.NoError(t, err)
+		assert.Equal(t, `{"length_m":12,"line_load_kg_m":4500,"floor_load_kg_m2":2500}`, string(out))
+	})
+}
@@ -0,0 +1,232 @@
+// Package units converts between the canonical storage units (millimetres,
+// kilograms, cubic metres) and the units a client asked for.
+//
+// Conversion factors are exact (1 in = 25.4 mm, 1 lb = 0.45359237 kg).
+// Incoming values are rounded half away from zero to 0.01 mm / 0.01 kg, the
+// precision of the database columns. Outgoing values are rounded per unit:
+// mm 1, cm 2, m 4, in 2, kg 2, lb 2, m3 3 and ft3 2 decimals, m2 3 and ft2
+// 2 decimals, and floor and line loads 2 decimals.
+package units
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+type Length string
+
+const (
+	Millimetre Length = "mm"
+	Centimetre Length = "cm"
+	Metre      Length = "m"
+	Inch       Length = "in"
+)
+
+type Weight string
+
+const (
+	Kilogram Weight = "kg"
+	Pound    Weight = "lb"
+)
+
+type Volume string
+
+const (
+	CubicMetre Volume = "m3"
+	CubicFoot  Volume = "ft3"
+)
+
+// Area is the unit of surface areas and of floor loads, which are weights
+// per area.
+type Area string
+
+const (
+	SquareMetre Area = "m2"
+	SquareFoot  Area = "ft2"
+)
+
+// Run is the unit of length line loads are given per.
+type Run string
+
+const (
+	RunMetre Run = "m"
+	RunFoot  Run = "ft"
+)
+
+// System is the pair of length and weight units used for one request.
+type System struct {
+	Length Length
+	Weight Weight
+}
+
+// Canonical is how values are stored and how the API speaks by default.
+var Canonical = System{Length: Millimetre, Weight: Kilogram}
+
+var (
+	mmPer = map[Length]float64{Millimetre: 1, Centimetre: 10, Metre: 1000, Inch: 25.4}
+	kgPer = map[Weight]float64{Kilogram: 1, Pound: 0.45359237}
+
+	// m3 per unit; 1 ft = 0.3048 m exactly.
+	m3Per = map[Volume]float64{CubicMetre: 1, CubicFoot: 0.3048 * 0.3048 * 0.3048}
+
+	// m2 and m per unit.
+	m2Per  = map[Area]float64{SquareMetre: 1, SquareFoot: 0.3048 * 0.3048}
+	runPer = map[Run]float64{RunMetre: 1, RunFoot: 0.3048}
+
+	lengthDecimals = map[Length]int{Millimetre: 1, Centimetre: 2, Metre: 4, Inch: 2}
+	weightDecimals = map[Weight]int{Kilogram: 2, Pound: 2}
+	volumeDecimals = map[Volume]int{CubicMetre: 3, CubicFoot: 2}
+	areaDecimals   = map[Area]int{SquareMetre: 3, SquareFoot: 2}
+)
+
+// loadDecimals is the output precision of floor and line loads.
+const loadDecimals = 2
+
+// storageDecimals matches NUMERIC(10,2).
+const storageDecimals = 2
+
+// Parse reads a unit selection. It accepts "metric" (mm, kg), "imperial" or
+// "us" (in, lb), a single unit ("cm", "lb") or a "<length>,<weight>" pair
+// ("in,lb", "cm,kg"). Missing halves default to the canonical unit.
+func Parse(s string) (System, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	switch s {
+	case "", "metric":
+		return Canonical, nil
+	case "imperial", "us":
+		return System{Length: Inch, Weight: Pound}, nil
+	}
+
+	sys := Canonical
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if _, ok := mmPer[Length(part)]; ok {
+			sys.Length = Length(part)
+			continue
+		}
+		if _, ok := kgPer[Weight(part)]; ok {
+			sys.Weight = Weight(part)
+			continue
+		}
+		return Canonical, fmt.Errorf("unsupported unit %q", part)
+	}
+	return sys, nil
+}
+
+func (s System) String() string {
+	return string(s.Length) + "," + string(s.Weight)
+}
+
+func (s System) IsCanonical() bool {
+	return s == Canonical
+}
+
+// Volume is cubic feet for inch-based systems and cubic metres otherwise.
+func (s System) Volume() Volume {
+	if s.Length == Inch {
+		return CubicFoot
+	}
+	return CubicMetre
+}
+
+// Area is square feet for inch-based systems and square metres otherwise.
+func (s System) Area() Area {
+	if s.Length == Inch {
+		return SquareFoot
+	}
+	return SquareMetre
+}
+
+// Run is feet for inch-based systems and metres otherwise.
+func (s System) Run() Run {
+	if s.Length == Inch {
+		return RunFoot
+	}
+	return RunMetre
+}
+
+// ToMillimetres converts a length in s to millimetres at storage precision.
+func (s System) ToMillimetres(v float64) float64 {
+	return Round(v*mmPer[s.Length], storageDecimals)
+}
+
+// FromMillimetres converts a stored length to s, rounded for output.
+func (s System) FromMillimetres(mm float64) float64 {
+	return Round(mm/mmPer[s.Length], lengthDecimals[s.Length])
+}
+
+// ToKilograms converts a weight in s to kilograms at storage precision.
+func (s System) ToKilograms(v float64) float64 {
+	return Round(v*kgPer[s.Weight], storageDecimals)
+}
+
+// FromKilograms converts a stored weight to s, rounded for output.
+func (s System) FromKilograms(kg float64) float64 {
+	return Round(kg/kgPer[s.Weight], weightDecimals[s.Weight])
+}
+
+// FromCubicMetres converts a volume to s.Volume(), rounded for output.
+func (s System) FromCubicMetres(m3 float64) float64 {
+	v := s.Volume()
+	return Round(m3/m3Per[v], volumeDecimals[v])
+}
+
+// FromSquareMetres converts an area to s.Area(), rounded for output.
+func (s System) FromSquareMetres(m2 float64) float64 {
+	a := s.Area()
+	return Round(m2/m2Per[a], areaDecimals[a])
+}
+
+// ToKilogramsPerSquareMetre converts a floor load in s.Weight per s.Area()
+// to kg/m2 at storage precision.
+func (s System) ToKilogramsPerSquareMetre(v float64) float64 {
+	return Round(v*kgPer[s.Weight]/m2Per[s.Area()], storageDecimals)
+}
+
+// FromKilogramsPerSquareMetre converts a stored floor load to s.Weight per
+// s.Area(), rounded for output.
+func (s System) FromKilogramsPerSquareMetre(v float64) float64 {
+	return Round(v/kgPer[s.Weight]*m2Per[s.Area()], loadDecimals)
+}
+
+// ToKilogramsPerMetre converts a line load in s.Weight per s.Run() to kg/m
+// at storage precision.
+func (s System) ToKilogramsPerMetre(v float64) float64 {
+	return Round(v*kgPer[s.Weight]/runPer[s.Run()], storageDecimals)
+}
+
+// FromKilogramsPerMetre converts a stored line load to s.Weight per s.Run(),
+// rounded for output.
+func (s System) FromKilogramsPerMetre(v float64) float64 {
+	return Round(v/kgPer[s.Weight]*runPer[s.Run()], loadDecimals)
+}
+
+// Round rounds half away from zero to the given number of decimals. It works
+// on the shortest decimal representation of v, so 1.005 rounds to 1.01 even
+// though its binary value is slightly below.
+func Round(v float64, decimals int) float64 {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return v
+	}
+	r, ok := new(big.Rat).SetString(strconv.FormatFloat(v, 'g', -1, 64))
+	if !ok {
+		return v
+	}
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	r.Mul(r, new(big.Rat).SetInt(scale))
+
+	half := big.NewRat(1, 2)
+	if r.Sign() < 0 {
+		r.Sub(r, half)
+	} else {
+		r.Add(r, half)
+	}
+	// Quo truncates toward zero.
+	n := new(big.Int).Quo(r.Num(), r.Denom())
+
+	out, _ := new(big.Rat).SetFrac(n, scale).Float64()
+	return out
+}
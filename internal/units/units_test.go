@@ -0,0 +1,65 @@
+package units
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    System
+		wantErr bool
+	}{
+		{in: "", want: Canonical},
+		{in: "metric", want: Canonical},
+		{in: " Imperial ", want: System{Length: Inch, Weight: Pound}},
+		{in: "us", want: System{Length: Inch, Weight: Pound}},
+		{in: "cm", want: System{Length: Centimetre, Weight: Kilogram}},
+		{in: "lb", want: System{Length: Millimetre, Weight: Pound}},
+		{in: "in,kg", want: System{Length: Inch, Weight: Kilogram}},
+		{in: "m, lb", want: System{Length: Metre, Weight: Pound}},
+		{in: "furlong", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := Parse(tt.in)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestSystem_Conversions(t *testing.T) {
+	us := System{Length: Inch, Weight: Pound}
+
+	assert.Equal(t, 254.0, us.ToMillimetres(10))
+	assert.Equal(t, 10.0, us.FromMillimetres(254))
+	assert.Equal(t, 0.45, us.ToKilograms(1))
+	assert.Equal(t, 2.2, us.FromKilograms(0.9979))
+	assert.Equal(t, 35.31, us.FromCubicMetres(1))
+	assert.Equal(t, CubicFoot, us.Volume())
+
+	m := System{Length: Metre, Weight: Kilogram}
+	assert.Equal(t, 12.0325, m.FromMillimetres(12032.5))
+	assert.Equal(t, 1.5, m.FromCubicMetres(1.5))
+	assert.Equal(t, CubicMetre, m.Volume())
+
+	assert.Equal(t, "in,lb", us.String())
+	assert.False(t, us.IsCanonical())
+	assert.True(t, Canonical.IsCanonical())
+}
+
+func TestRound(t *testing.T) {
+	assert.Equal(t, 1.01, Round(1.005, 2))
+	assert.Equal(t, -1.01, Round(-1.005, 2))
+	assert.Equal(t, 2.5, Round(2.45, 1))
+	assert.Equal(t, 3.0, Round(2.5, 0))
+	assert.Equal(t, 0.0, Round(0.0049, 2))
+}
@@ -60,7 +60,9 @@ export function ItemInputForm({ shipmentId, onSuccess, maxLength_mm, maxWidth_mm
       height_mm: product.height_mm,
       weight_kg: product.weight_kg,
       allow_rotation: true,
-      color_hex: product.color_hex || "#3498db"
+      color_hex: product.color_hex || "#3498db",
+      friction_coefficient: product.friction_coefficient,
+      temperature_class: product.temperature_class,
     })
 
     if (success) {
@@ -131,7 +131,9 @@ export function ShipmentWizard() {
       height_mm: product.height_mm,
       weight_kg: product.weight_kg,
       allow_rotation: true,
-      color_hex: product.color_hex || "#3498db"
+      color_hex: product.color_hex || "#3498db",
+      friction_coefficient: product.friction_coefficient,
+      temperature_class: product.temperature_class,
     }
 
     setItems([...items, newItem])
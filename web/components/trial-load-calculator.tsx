@@ -187,6 +187,8 @@ export function TrialLoadCalculator() {
       weight_kg: product.weight_kg,
       allow_rotation: true,
       color_hex: product.color_hex || "#3498db",
+      friction_coefficient: product.friction_coefficient,
+      temperature_class: product.temperature_class,
     }
 
     setItems((prev) => [...prev, newItem])
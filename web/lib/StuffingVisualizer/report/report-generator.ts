@@ -47,6 +47,11 @@ export class ReportGenerator {
 
         this.createSummaryPage(doc, data, config, pageWidth, pageHeight, margin);
 
+        if (data.calculation.securing && data.calculation.securing.blocks.length > 0) {
+            doc.addPage();
+            this.createSecuringPage(doc, data, pageWidth, margin);
+        }
+
         this.createStepPages(doc, data, config, pageWidth, pageHeight, margin);
 
         return doc.output("blob");
@@ -229,6 +234,91 @@ export class ReportGenerator {
         });
     }
 
+    private createSecuringPage(
+        doc: any,
+        data: StuffingPlanData,
+        pageWidth: number,
+        margin: number
+    ) {
+        const securing = data.calculation.securing!;
+        let y = margin;
+
+        doc.setTextColor(...this.BRAND_BLUE);
+        doc.setFontSize(20);
+        doc.setFont(undefined, "bold");
+        doc.text(`LOAD SECURING (${securing.standard})`, margin, y);
+        y += 10;
+
+        doc.setTextColor(0, 0, 0);
+        doc.setFontSize(10);
+        doc.setFont(undefined, "normal");
+        doc.text(
+            `Straps: LC ${securing.strap_lc_dan} daN, STF ${securing.strap_stf_dan} daN. ` +
+                `Total straps: ${securing.total_straps}.`,
+            margin,
+            y
+        );
+        y += 10;
+
+        const right = pageWidth - margin;
+        const width = right - margin;
+        const colLabel = margin;
+        const colUnits = margin + width * 0.3;
+        const colWeight = margin + width * 0.38;
+        const colFriction = margin + width * 0.48;
+        const colMethod = margin + width * 0.56;
+        const colStraps = margin + width * 0.66;
+        const colPoints = margin + width * 0.73;
+        const colNote = margin + width * 0.8;
+
+        doc.setDrawColor(200, 200, 200);
+        doc.setLineWidth(0.3);
+        doc.line(margin, y, right, y);
+        y += 6;
+
+        doc.setFontSize(9);
+        doc.setFont(undefined, "bold");
+        doc.text("Block", colLabel, y);
+        doc.text("Units", colUnits, y);
+        doc.text("Weight", colWeight, y);
+        doc.text("Friction", colFriction, y);
+        doc.text("Method", colMethod, y);
+        doc.text("Straps", colStraps, y);
+        doc.text("Points", colPoints, y);
+        doc.text("Note", colNote, y);
+        y += 3;
+        doc.line(margin, y, right, y);
+        y += 5;
+
+        doc.setFontSize(8);
+        doc.setFont(undefined, "normal");
+        const methods: Record<string, string> = { none: "None", top_over: "Top-over", direct: "Direct" };
+        securing.blocks.forEach((block, i) => {
+            if (!block.sufficient) doc.setTextColor(200, 0, 0);
+            doc.text(`${i + 1}. ${(block.label || "Item").substring(0, 40)}`, colLabel, y);
+            doc.text(block.units.toString(), colUnits, y);
+            doc.text(`${block.weight_kg.toFixed(1)} kg`, colWeight, y);
+            doc.text(block.friction_coefficient.toFixed(2), colFriction, y);
+            doc.text(methods[block.method] || block.method, colMethod, y);
+            doc.text(block.straps.toString(), colStraps, y);
+            doc.text(block.lashing_points.toString(), colPoints, y);
+            doc.text((block.note || "").substring(0, 45), colNote, y);
+            doc.setTextColor(0, 0, 0);
+            y += 5;
+        });
+
+        if (securing.warnings && securing.warnings.length > 0) {
+            y += 5;
+            doc.setFont(undefined, "bold");
+            doc.text("Warnings", margin, y);
+            doc.setFont(undefined, "normal");
+            securing.warnings.forEach((w) => {
+                y += 5;
+                doc.text(`- ${w}`, margin, y);
+            });
+        }
+    }
+
     private createStepPages(
         doc: any,
         data: StuffingPlanData,
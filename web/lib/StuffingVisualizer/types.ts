@@ -34,6 +34,29 @@ export interface PlacementData {
     step_number: number;
 }
 
+export interface SecuringBlockData {
+    item_id: string;
+    label?: string;
+    units: number;
+    weight_kg: number;
+    friction_coefficient: number;
+    method: string;
+    straps: number;
+    lashing_points: number;
+    sufficient: boolean;
+    note?: string;
+}
+
+export interface SecuringData {
+    standard: string;
+    strap_lc_dan: number;
+    strap_stf_dan: number;
+    blocks: SecuringBlockData[];
+    total_straps: number;
+    sufficient: boolean;
+    warnings?: string[];
+}
+
 export interface StuffingPlanData {
     plan_id: string;
     plan_code: string;
@@ -54,6 +77,7 @@ export interface StuffingPlanData {
         volume_utilization_pct: number;
         efficiency_score: number;
         visualization_url: string;
+        securing?: SecuringData;
     };
 }
 
@@ -5,6 +5,9 @@ import {
   PlanDetailResponse,
   PlanListItem,
   UpdatePlanRequest,
+  PlanStatusChange,
+  ResultVersion,
+  ResultVersionDiff,
   AddPlanItemRequest,
   UpdatePlanItemRequest,
   PlanItemDetail,
@@ -12,7 +15,14 @@ import {
   CalculatePlanRequest,
   BarcodeInfo,
   ValidateBarcodeRequest,
-  ValidationResult
+  ValidationResult,
+  LoadingSession,
+  LoadingScan,
+  LoadingScanRequest,
+  LoadingScanResponse,
+  OfflineBundle,
+  OfflineSyncRequest,
+  OfflineSyncReport
 } from "../types"
 
 function withWorkspaceId(url: string, workspaceId?: string | null) {
@@ -59,6 +69,53 @@ export const PlanService = {
     }
   },
 
+  getStatusHistory: async (id: string): Promise<PlanStatusChange[]> => {
+    try {
+      const response = await apiGet<PlanStatusChange[]>(`/plans/${id}/status-history`)
+      return response || []
+    } catch (error: any) {
+      console.error(`PlanService.getStatusHistory(${id}) failed:`, error)
+      throw new Error(error.message || "Failed to fetch status history")
+    }
+  },
+
+  listResultVersions: async (id: string): Promise<ResultVersion[]> => {
+    try {
+      const response = await apiGet<ResultVersion[]>(`/plans/${id}/results`)
+      return response || []
+    } catch (error: any) {
+      console.error(`PlanService.listResultVersions(${id}) failed:`, error)
+      throw new Error(error.message || "Failed to list result versions")
+    }
+  },
+
+  getResultVersion: async (id: string, version: number): Promise<ResultVersion> => {
+    try {
+      return await apiGet<ResultVersion>(`/plans/${id}/results/${version}`)
+    } catch (error: any) {
+      console.error(`PlanService.getResultVersion(${id}, ${version}) failed:`, error)
+      throw new Error(error.message || "Failed to fetch result version")
+    }
+  },
+
+  restoreResultVersion: async (id: string, version: number): Promise<ResultVersion> => {
+    try {
+      return await apiPost<ResultVersion>(`/plans/${id}/results/${version}/restore`, {})
+    } catch (error: any) {
+      console.error(`PlanService.restoreResultVersion(${id}, ${version}) failed:`, error)
+      throw new Error(error.message || "Failed to restore result version")
+    }
+  },
+
+  diffResultVersions: async (id: string, from: number, to: number): Promise<ResultVersionDiff> => {
+    try {
+      return await apiGet<ResultVersionDiff>(`/plans/${id}/results/diff?from=${from}&to=${to}`)
+    } catch (error: any) {
+      console.error(`PlanService.diffResultVersions(${id}) failed:`, error)
+      throw new Error(error.message || "Failed to diff result versions")
+    }
+  },
+
   deletePlan: async (id: string): Promise<void> => {
     try {
       return await apiDelete<void>(`/plans/${id}`)
@@ -120,5 +177,88 @@ export const PlanService = {
       console.error(`PlanService.validatePlanBarcode(${planId}) failed:`, error)
       throw new Error(error.message || "Failed to validate barcode")
     }
+  },
+
+  startLoadingSession: async (planId: string): Promise<LoadingSession> => {
+    try {
+      return await apiPost<LoadingSession>(`/plans/${planId}/loading-sessions`, {})
+    } catch (error: any) {
+      console.error(`PlanService.startLoadingSession(${planId}) failed:`, error)
+      throw new Error(error.message || "Failed to start loading session")
+    }
+  },
+
+  listLoadingSessions: async (planId: string): Promise<LoadingSession[]> => {
+    try {
+      const response = await apiGet<LoadingSession[]>(`/plans/${planId}/loading-sessions`)
+      return response || []
+    } catch (error: any) {
+      console.error(`PlanService.listLoadingSessions(${planId}) failed:`, error)
+      throw new Error(error.message || "Failed to fetch loading sessions")
+    }
+  },
+
+  getLoadingSession: async (planId: string, sessionId: string): Promise<LoadingSession> => {
+    try {
+      return await apiGet<LoadingSession>(`/plans/${planId}/loading-sessions/${sessionId}`)
+    } catch (error: any) {
+      console.error(`PlanService.getLoadingSession(${planId}, ${sessionId}) failed:`, error)
+      throw new Error(error.message || "Failed to fetch loading session")
+    }
+  },
+
+  pauseLoadingSession: async (planId: string, sessionId: string): Promise<LoadingSession> => {
+    try {
+      return await apiPost<LoadingSession>(`/plans/${planId}/loading-sessions/${sessionId}/pause`, {})
+    } catch (error: any) {
+      console.error(`PlanService.pauseLoadingSession(${planId}, ${sessionId}) failed:`, error)
+      throw new Error(error.message || "Failed to pause loading session")
+    }
+  },
+
+  completeLoadingSession: async (planId: string, sessionId: string): Promise<LoadingSession> => {
+    try {
+      return await apiPost<LoadingSession>(`/plans/${planId}/loading-sessions/${sessionId}/complete`, {})
+    } catch (error: any) {
+      console.error(`PlanService.completeLoadingSession(${planId}, ${sessionId}) failed:`, error)
+      throw new Error(error.message || "Failed to complete loading session")
+    }
+  },
+
+  recordLoadingScan: async (planId: string, sessionId: string, data: LoadingScanRequest): Promise<LoadingScanResponse> => {
+    try {
+      return await apiPost<LoadingScanResponse>(`/plans/${planId}/loading-sessions/${sessionId}/scans`, data)
+    } catch (error: any) {
+      console.error(`PlanService.recordLoadingScan(${planId}, ${sessionId}) failed:`, error)
+      throw new Error(error.message || "Failed to record scan")
+    }
+  },
+
+  listLoadingScans: async (planId: string, sessionId: string): Promise<LoadingScan[]> => {
+    try {
+      const response = await apiGet<LoadingScan[]>(`/plans/${planId}/loading-sessions/${sessionId}/scans`)
+      return response || []
+    } catch (error: any) {
+      console.error(`PlanService.listLoadingScans(${planId}, ${sessionId}) failed:`, error)
+      throw new Error(error.message || "Failed to fetch scans")
+    }
+  },
+
+  getOfflineBundle: async (planId: string): Promise<OfflineBundle> => {
+    try {
+      return await apiGet<OfflineBundle>(`/plans/${planId}/offline-bundle`)
+    } catch (error: any) {
+      console.error(`PlanService.getOfflineBundle(${planId}) failed:`, error)
+      throw new Error(error.message || "Failed to download offline bundle")
+    }
+  },
+
+  syncOfflineScans: async (planId: string, data: OfflineSyncRequest): Promise<OfflineSyncReport> => {
+    try {
+      return await apiPost<OfflineSyncReport>(`/plans/${planId}/offline-sync`, data)
+    } catch (error: any) {
+      console.error(`PlanService.syncOfflineScans(${planId}) failed:`, error)
+      throw new Error(error.message || "Failed to sync offline scans")
+    }
   }
 }
@@ -44,6 +44,7 @@ export function toStuffingPlanData(plan: PlanDetailResponse): StuffingPlanData {
       volume_utilization_pct: plan.calculation?.volume_utilization_pct || 0,
       efficiency_score: plan.calculation?.efficiency_score || 0,
       visualization_url: plan.calculation?.visualization_url || "",
+      securing: plan.calculation?.securing,
     },
   }
 }
@@ -0,0 +1,51 @@
+import type { CreatePlanContainer, PlanContainerInfo, PlanStats } from "./plan"
+
+export interface MaxQuantityMixEntry {
+  product_id?: string
+  product_sku?: string
+  ratio: number
+}
+
+export interface MaxQuantityRequest {
+  product_id?: string
+  product_sku?: string
+  mix?: MaxQuantityMixEntry[]
+  container: CreatePlanContainer
+  allow_rotation?: boolean
+  strategy?: string
+  goal?: string
+  gravity?: boolean
+}
+
+export interface MaxQuantityProduct {
+  product_id: string
+  sku?: string
+  name: string
+  ratio: number
+  quantity: number
+}
+
+export interface CapacityPlacement {
+  product_id: string
+  pos_x: number
+  pos_y: number
+  pos_z: number
+  length_mm: number
+  width_mm: number
+  height_mm: number
+  rotation: number
+  step_number: number
+}
+
+export interface MaxQuantityResponse {
+  max_quantity: number
+  sets: number
+  limiting_factor: string // weight | volume | space | search_cap
+  search_complete: boolean
+  iterations: number
+  algorithm: string
+  products: MaxQuantityProduct[]
+  container: PlanContainerInfo
+  stats: PlanStats
+  placements: CapacityPlacement[]
+}
@@ -1,9 +1,24 @@
+// Compartment is a section of a container between two bulkheads, running
+// start_mm to start_mm + length_mm along the container.
+export interface Compartment {
+  name: string
+  temperature_class: string // ambient | chilled | frozen
+  start_mm: number
+  length_mm: number
+  max_weight_kg?: number
+}
+
 export interface CreateContainerRequest {
   name: string
   inner_length_mm: number
   inner_width_mm: number
   inner_height_mm: number
   max_weight_kg: number
+  lashing_points_per_side?: number
+  lashing_point_capacity_dan?: number
+  floor_load_kg_m2?: number
+  line_load_kg_m?: number
+  compartments?: Compartment[]
   description?: string
 }
 
@@ -13,6 +28,11 @@ export interface UpdateContainerRequest {
   inner_width_mm: number
   inner_height_mm: number
   max_weight_kg: number
+  lashing_points_per_side?: number
+  lashing_point_capacity_dan?: number
+  floor_load_kg_m2?: number
+  line_load_kg_m?: number
+  compartments?: Compartment[]
   description?: string
 }
 
@@ -23,5 +43,10 @@ export interface ContainerResponse {
   inner_width_mm: number
   inner_height_mm: number
   max_weight_kg: number
+  lashing_points_per_side?: number
+  lashing_point_capacity_dan?: number
+  floor_load_kg_m2?: number
+  line_load_kg_m?: number
+  compartments?: Compartment[]
   description?: string
 }
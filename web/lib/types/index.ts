@@ -9,3 +9,4 @@ export * from "./user"
 export * from "./workspace"
 export * from "./member"
 export * from "./invite"
+export * from "./capacity"
@@ -1,4 +1,6 @@
 import { UserSummary } from "./auth"
+import { Compartment } from "./container"
+import { ProductHandling } from "./product"
 
 export interface CreatePlanContainer {
   container_id?: string
@@ -6,10 +8,21 @@ export interface CreatePlanContainer {
   width_mm?: number
   height_mm?: number
   max_weight_kg?: number
+  wall_clearance_mm?: number
+  item_gap_mm?: number
+  lashing_points_per_side?: number
+  lashing_point_capacity_dan?: number
+  floor_load_kg_m2?: number
+  line_load_kg_m?: number
+  compartments?: Compartment[]
 }
 
-export interface CreatePlanItem {
+// Items with a product_id or catalog product_sku take the product's
+// dimensions, weight and attributes for any field sent as zero or left out.
+export interface CreatePlanItem extends ProductHandling {
+  product_id?: string
   product_sku?: string
+  gtin?: string
   label?: string
   length_mm: number
   width_mm: number
@@ -18,6 +31,12 @@ export interface CreatePlanItem {
   quantity: number
   allow_rotation?: boolean
   color_hex?: string
+  padding_mm?: number
+  priority?: number
+  must_ship?: boolean
+  friction_coefficient?: number
+  temperature_class?: string // ambient | chilled | frozen
+  unit?: string // each | inner | case | pallet; the product's shipping unit when left out
 }
 
 export interface CreatePlanRequest {
@@ -40,14 +59,169 @@ export interface PlacementDetail {
 
 export interface CalculationResult {
   job_id: string
+  version?: number
   status: string
   algorithm: string
   calculated_at?: string
   duration_ms: number
   efficiency_score: number
   volume_utilization_pct: number
+  weight_utilization_pct?: number
   visualization_url: string
+  cache_hit?: boolean
   placements?: PlacementDetail[]
+  void_analysis?: VoidAnalysis
+  unfit_items?: UnfitItemInfo[]
+  securing?: SecuringPlan
+  floor_load?: FloorLoadReport
+  handling?: HandlingReport
+}
+
+export interface HandlingViolation {
+  item_id: string
+  label?: string
+  pos_x: number
+  pos_y: number
+  pos_z: number
+  rule: string // this_side_up | no_stack | fragile | max_stack_load
+  load_kg?: number
+  limit_kg?: number
+}
+
+export interface HandlingReport {
+  violations: HandlingViolation[]
+  hazmat_classes?: string[]
+  ok: boolean
+}
+
+export interface FloorLoadViolation {
+  item_id: string
+  label?: string
+  pos_x: number
+  pos_y: number
+  length_mm: number
+  width_mm: number
+  load_kg: number
+  pressure_kg_m2: number
+  required_bearing_area_m2: number
+  recommended_length_mm: number
+  recommended_width_mm: number
+}
+
+export interface LineLoadViolation {
+  start_mm: number
+  end_mm: number
+  load_kg: number
+  required_length_m: number
+}
+
+export interface FloorLoadReport {
+  max_pressure_kg_m2: number
+  max_line_load_kg_m: number
+  floor_load_limit_kg_m2?: number
+  line_load_limit_kg_m?: number
+  violations: FloorLoadViolation[]
+  line_violations: LineLoadViolation[]
+  ok: boolean
+}
+
+export interface UnfitItemInfo {
+  item_id: string
+  label?: string
+  quantity: number
+  must_ship?: boolean
+  reason?: string // volume | weight | geometry
+  diagnosis?: string // too_large | overweight | no_space | orientation | stacking | unplaced
+  message?: string
+}
+
+export interface PreflightIssue {
+  item_id: string
+  label?: string
+  quantity: number
+  code: string // too_large | orientation | overweight
+  message: string
+}
+
+export interface PreflightResponse {
+  plan_id: string
+  ok: boolean
+  total_weight_kg: number
+  max_weight_kg: number
+  total_volume_m3: number
+  container_volume_m3: number
+  overweight: boolean
+  over_volume: boolean
+  issues: PreflightIssue[]
+}
+
+export interface DunnageRecommendation {
+  type: string // airbag | filler
+  quantity: number
+  estimated_cost: number
+}
+
+export interface VoidDetail {
+  pos_x: number
+  pos_y: number
+  pos_z: number
+  length_mm: number
+  width_mm: number
+  height_mm: number
+  volume_m3: number
+  flagged: boolean
+  shift_axis?: string
+  gap_mm?: number
+  dunnage?: DunnageRecommendation
+}
+
+export interface VoidAnalysis {
+  threshold_mm: number
+  empty_volume_m3: number
+  voids: VoidDetail[]
+  dunnage: DunnageRecommendation[]
+  total_dunnage_cost: number
+  currency: string
+}
+
+export interface SecuringBlock {
+  item_id: string
+  label?: string
+  units: number
+  weight_kg: number
+  pos_x: number
+  pos_y: number
+  pos_z: number
+  length_mm: number
+  width_mm: number
+  height_mm: number
+  friction_coefficient: number
+  angle_deg: number
+  restraint_dan: number
+  tipping_risk: boolean
+  top_over_straps: number
+  direct_lashings: number
+  method: string // none | top_over | direct
+  straps: number
+  lashing_points: number
+  sufficient: boolean
+  note?: string
+}
+
+export interface SecuringPlan {
+  standard: string
+  forward_coefficient: number
+  backward_coefficient: number
+  sideways_coefficient: number
+  strap_lc_dan: number
+  strap_stf_dan: number
+  lashing_points_per_side: number
+  blocks: SecuringBlock[]
+  total_straps: number
+  top_over_straps: number
+  direct_lashings: number
+  sufficient: boolean
+  warnings?: string[]
 }
 
 export interface CreatePlanResponse {
@@ -71,6 +245,23 @@ export interface PlanContainerInfo {
   height_mm: number
   max_weight_kg: number
   volume_m3: number
+  wall_clearance_mm: number
+  item_gap_mm: number
+  lashing_points_per_side: number
+  lashing_point_capacity_dan?: number
+  floor_load_kg_m2?: number
+  line_load_kg_m?: number
+  compartments?: Compartment[]
+}
+
+export interface CompartmentStats {
+  name: string
+  temperature_class: string
+  total_items: number
+  total_weight_kg: number
+  total_volume_m3: number
+  volume_utilization_pct: number
+  weight_utilization_pct: number
 }
 
 export interface PlanStats {
@@ -79,11 +270,22 @@ export interface PlanStats {
   total_volume_m3: number
   volume_utilization_pct: number
   weight_utilization_pct: number
+  compartments?: CompartmentStats[]
+  skus?: SKUStats[]
+}
+
+export interface SKUStats {
+  sku: string
+  quantity: number
+  placed_units: number
+  total_weight_kg: number
+  total_volume_m3: number
 }
 
 export interface PlanItemDetail {
   item_id: string
   product_sku?: string
+  gtin?: string
   label?: string
   length_mm: number
   width_mm: number
@@ -95,11 +297,31 @@ export interface PlanItemDetail {
   allow_rotation: boolean
   stacking_limit: number
   color_hex?: string
+  padding_mm: number
+  priority: number
+  must_ship: boolean
+  friction_coefficient?: number
+  temperature_class?: string
+  product_id?: string
+  product_overridden: boolean
+  fragile: boolean
+  this_side_up: boolean
+  stackable: boolean
+  max_stack_load_kg?: number
+  hazmat_class?: string
+  packaging_type?: string
+  unit?: string
+  eaches_per_unit?: number
+  ordered_eaches?: number
   created_at: string
 }
 
 export interface PlanDetailResponse {
   plan_id: string
+  parent_plan_id?: string
+  scenario_name?: string
+  overflow_of_plan_id?: string
+  shipment_group_id?: string
   plan_code: string
   title: string
   notes?: string
@@ -129,12 +351,22 @@ export interface PlanListItem {
 export interface UpdatePlanRequest {
   title?: string
   status?: string
+  status_reason?: string
   container?: CreatePlanContainer
 }
 
+export interface PlanStatusChange {
+  from_status?: string
+  to_status: string
+  reason?: string
+  changed_by_type: string // user, guest, system
+  changed_by_id?: string
+  changed_at: string
+}
+
 export interface AddPlanItemRequest extends CreatePlanItem {}
 
-export interface UpdatePlanItemRequest {
+export interface UpdatePlanItemRequest extends ProductHandling {
   label?: string
   length_mm?: number
   width_mm?: number
@@ -143,18 +375,27 @@ export interface UpdatePlanItemRequest {
   quantity?: number
   allow_rotation?: boolean
   color_hex?: string
+  padding_mm?: number
+  priority?: number
+  must_ship?: boolean
+  friction_coefficient?: number
+  temperature_class?: string // ambient | chilled | frozen
 }
 
 export interface CalculatePlanRequest {
   strategy?: string
   goal?: string
   gravity?: boolean
+  void_threshold_mm?: number
+  prioritize?: boolean
 }
 
 export interface BarcodeInfo {
   step_number: number
   item_id: string
   item_label: string
+  sku?: string
+  gtin?: string
   barcode: string
   position: {
     x: number
@@ -175,10 +416,184 @@ export interface ValidateBarcodeRequest {
 
 export interface ValidationResult {
   valid: boolean
-  status: string // MATCHED, OUT_OF_SEQUENCE, WRONG_PLAN, INVALID_FORMAT
+  status: string // MATCHED, OUT_OF_SEQUENCE, WRONG_PLAN, INVALID_FORMAT, INVALID_SIGNATURE
   plan_id?: string
   step_number?: number
   item_id?: string
   barcode?: string
   error?: string
 }
+
+export interface LoadingSession {
+  session_id: string
+  plan_id: string
+  result_id?: string
+  status: string // active, paused, completed, abandoned
+  plan_status?: string
+  total_steps: number
+  loaded_steps: number
+  progress_pct: number
+  next_expected_step?: number
+  started_by_id: string
+  started_at: string
+  paused_at?: string
+  completed_by_id?: string
+  completed_at?: string
+}
+
+export interface LoadingScanRequest {
+  barcode: string
+}
+
+export interface LoadingScan {
+  scan_id: string
+  barcode: string
+  status: string // MATCHED, OUT_OF_SEQUENCE, DUPLICATE, UNKNOWN_STEP, UNKNOWN_PRODUCT, WRONG_PLAN, INVALID_FORMAT, INVALID_SIGNATURE
+  step_number?: number
+  expected_step?: number
+  operator_id: string
+  scanned_at: string
+}
+
+export interface LoadingScanResponse {
+  scan: LoadingScan
+  session: LoadingSession
+}
+
+export interface OfflineBundleHeader {
+  format_version: number
+  plan_id: string
+  result_id: string
+  result_version: number
+  session_id?: string
+  issued_at: string
+  content_digest: string
+  signature: string
+}
+
+export interface OfflineBundle {
+  header: OfflineBundleHeader
+  content: {
+    plan_code: string
+    plan_status: string
+    container: PlanContainerInfo
+    total_steps: number
+    loaded_steps: number[]
+    steps: BarcodeInfo[]
+  }
+}
+
+export interface OfflineScanEvent {
+  event_id: string
+  barcode: string
+  scanned_at: string
+}
+
+export interface OfflineSyncRequest {
+  bundle: OfflineBundleHeader
+  events: OfflineScanEvent[]
+}
+
+export interface OfflineEventResult {
+  event_id: string
+  outcome: "applied" | "already_synced" | "conflict"
+  scan_status?: string
+  step_number?: number
+  expected_step?: number
+  reason?: string
+}
+
+export interface OfflineSyncReport {
+  applied: number
+  matched: number
+  already_synced: number
+  conflicts: number
+  events: OfflineEventResult[]
+  session: LoadingSession
+}
+
+export interface CreateOverflowPlanRequest {
+  container?: CreatePlanContainer
+  suggest_container?: boolean
+  auto_calculate?: boolean // default true
+  options?: CalculatePlanRequest
+}
+
+export interface OverflowPlanResponse {
+  plan_id: string
+  plan_code: string
+  status: string
+  overflow_of_plan_id: string
+  shipment_group_id: string
+  container: PlanContainerInfo
+  container_suggested: boolean
+  items: UnfitItemInfo[]
+  total_items: number
+  calculation?: CalculationResult
+}
+
+export interface ShipmentPlan {
+  plan_id: string
+  plan_code: string
+  overflow_of_plan_id?: string
+  status: string
+  calculated: boolean
+  container: PlanContainerInfo
+  stats: PlanStats
+  packed_items: number
+  unfit_items: UnfitItemInfo[]
+}
+
+export interface ShipmentGroupResponse {
+  shipment_group_id: string
+  plans: ShipmentPlan[]
+  total_items: number
+  packed_items: number
+  outstanding_units: number
+}
+
+export interface ResultInputs {
+  container: PlanContainerInfo
+  items: PlanItemDetail[]
+  options: CalculatePlanRequest
+  backend: string
+}
+
+export interface ResultVersion {
+  result_id: string
+  version: number
+  is_active: boolean
+  algorithm: string
+  is_feasible: boolean
+  volume_utilization_pct: number
+  loaded_weight_kg: number
+  created_at: string
+  inputs?: ResultInputs
+  placements?: PlacementDetail[]
+}
+
+export interface ResultItemDiff {
+  item_id: string
+  label?: string
+  change: string // added, removed, changed
+  from_quantity: number
+  to_quantity: number
+  from_packed: number
+  to_packed: number
+}
+
+export interface ResultVersionDiff {
+  from_version: number
+  to_version: number
+  from_algorithm: string
+  to_algorithm: string
+  from_feasible: boolean
+  to_feasible: boolean
+  volume_utilization_delta_pct: number
+  loaded_weight_delta_kg: number
+  container_changed: boolean
+  options_changed: boolean
+  items: ResultItemDiff[]
+  units_unchanged: number
+  units_moved: number
+}
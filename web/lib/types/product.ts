@@ -1,31 +1,74 @@
-export interface CreateProductRequest {
+// Handling attributes of a product, copied onto plan items filled from it.
+export interface ProductHandling {
+  fragile?: boolean
+  this_side_up?: boolean
+  stackable?: boolean // true when left out
+  max_stack_load_kg?: number
+  hazmat_class?: string // UN class or division, e.g. "3" or "2.1"
+  packaging_type?: string // box | carton | case | crate | drum | bag | sack | pallet | roll | bundle | other
+}
+
+// A packaging level above the each; contains counts units of the next lower
+// level, e.g. inners in a case.
+export interface PackagingLevel {
+  level: string // inner | case | pallet
+  contains: number
+  length_mm: number
+  width_mm: number
+  height_mm: number
+  weight_kg: number
+  each_quantity?: number // set by the server
+}
+
+export interface CreateProductRequest extends ProductHandling {
   name: string
   sku?: string
+  gtin?: string
   length_mm: number
   width_mm: number
   height_mm: number
   weight_kg: number
   color_hex?: string
+  friction_coefficient?: number
+  temperature_class?: string // ambient | chilled | frozen
+  packaging?: PackagingLevel[]
+  shipping_unit?: string // each | inner | case | pallet
 }
 
-export interface UpdateProductRequest {
+export interface UpdateProductRequest extends ProductHandling {
   name: string
   sku?: string
+  gtin?: string
   length_mm: number
   width_mm: number
   height_mm: number
   weight_kg: number
   color_hex?: string
+  friction_coefficient?: number
+  temperature_class?: string // ambient | chilled | frozen
+  packaging?: PackagingLevel[]
+  shipping_unit?: string // each | inner | case | pallet
 }
 
 export interface ProductResponse {
   id: string
   name: string
   sku?: string
+  gtin?: string
   length_mm: number
   width_mm: number
   height_mm: number
   weight_kg: number
   color_hex?: string
+  friction_coefficient?: number
+  temperature_class?: string // ambient | chilled | frozen
+  fragile: boolean
+  this_side_up: boolean
+  stackable: boolean
+  max_stack_load_kg?: number
+  hazmat_class?: string
+  packaging_type?: string
+  packaging?: PackagingLevel[]
+  shipping_unit: string
   images?: string[]
 }